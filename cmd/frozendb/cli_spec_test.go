@@ -1751,37 +1751,32 @@ func Test_S_037_FR_015_OffsetParameterFollowsSameIndexing(t *testing.T) {
 	t.Logf("✓ Offset parameter follows same zero-based indexing as index column")
 }
 
-// Test_S_037_FR_016_OffsetBeyondFileSizeSucceedsWithZeroRows verifies behavior when offset exceeds total rows
+// Test_S_037_FR_016_OffsetBeyondFileSizeFails verifies behavior when offset exceeds total rows
 //
-// Functional Requirement FR-016: If offset is greater than number of rows, system MUST succeed (exit code 0) and display zero rows
-// Success Criteria SC-006: Empty database displays checksum row with zero data rows
-func Test_S_037_FR_016_OffsetBeyondFileSizeSucceedsWithZeroRows(t *testing.T) {
+// Functional Requirement FR-016 (revised): If offset is not below the number of rows in the
+// database, system MUST report the row count on stderr and exit non-zero without printing rows
+func Test_S_037_FR_016_OffsetBeyondFileSizeFails(t *testing.T) {
 	binaryPath := buildCLIBinary(t)
 	dbPath := createTestDatabase(t, binaryPath)
 
 	// Execute with very large offset (beyond file size)
-	cmd := exec.Command(binaryPath, "--path", dbPath, "inspect", "--offset", "999999")
-	output, err := cmd.CombinedOutput()
-
-	// Should succeed with exit code 0
-	if err != nil {
-		t.Fatalf("Expected success for large offset, got error: %v\nOutput: %s", err, output)
+	stdout, stderr, exitCode := runCLI(t, binaryPath, "--path", dbPath, "inspect", "--offset", "999999")
+	if exitCode != 1 {
+		t.Fatalf("Expected exit code 1 for offset beyond file size, got %d\nStdout: %s", exitCode, stdout)
 	}
-
-	outputStr := string(output)
-	lines := strings.Split(strings.TrimSpace(outputStr), "\n")
-
-	// Should have only the header row, no data rows
-	if len(lines) != 1 {
-		t.Errorf("Expected only header row for offset beyond file size, got %d lines: %s", len(lines), outputStr)
+	if stdout != "" {
+		t.Errorf("Expected no output on stdout, got: %s", stdout)
+	}
+	if !strings.Contains(stderr, "offset 999999 exceeds") || !strings.Contains(stderr, "total rows") {
+		t.Errorf("Expected stderr to report the row count, got: %s", stderr)
 	}
 
-	// Verify header is present
-	if !strings.Contains(lines[0], "index\ttype\tkey") {
-		t.Errorf("Expected header row, got: %s", lines[0])
+	// Offsets within the file still succeed
+	if _, stderr, exitCode := runCLI(t, binaryPath, "--path", dbPath, "inspect", "--offset", "0"); exitCode != 0 {
+		t.Errorf("Expected offset 0 to succeed, got exit code %d: %s", exitCode, stderr)
 	}
 
-	t.Logf("✓ Offset beyond file size succeeds and displays zero rows")
+	t.Logf("✓ Offset beyond file size fails with the row count")
 }
 
 // ============================================================================
@@ -1,10 +1,26 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"os"
+
+	pkg_frozendb "github.com/susu-dot-dev/frozenDB/pkg/frozendb"
+)
+
+// Exit codes used with --exit-codes. They are part of the CLI's interface and
+// must not change. Without --exit-codes every error exits with exitError.
+const (
+	exitError        = 1 // Any error not listed below
+	exitKeyNotFound  = 2 // KeyNotFoundError
+	exitWriteError   = 3 // WriteError, including another writer holding the lock
+	exitCorrupt      = 4 // CorruptDatabaseError or TruncatedFileError
+	exitInvalidInput = 5 // InvalidInputError
 )
 
+// exitCodes is set by the --exit-codes global flag.
+var exitCodes bool
+
 // formatError formats a FrozenDBError for CLI output.
 // Format: "Error: message"
 // Per FR-007, all errors must follow this format where message is err.Error().
@@ -12,10 +28,32 @@ func formatError(err error) string {
 	return fmt.Sprintf("Error: %s", err.Error())
 }
 
-// printError prints an error to stderr in structured format and exits with code 1.
+// printError prints an error to stderr in structured format and exits with code 1,
+// or with the code errorExitCode assigns to err under --exit-codes.
 // Per FR-007, all errors must go to stderr with exit code 1.
 // Per FR-005, success exits with code 0 (handled by caller, not this function).
 func printError(err error) {
 	fmt.Fprintln(os.Stderr, formatError(err))
-	os.Exit(1)
+	os.Exit(errorExitCode(err))
+}
+
+// errorExitCode returns the exit code for err: exitError unless --exit-codes is
+// set, in which case the outermost error in err's chain with its own code decides.
+func errorExitCode(err error) int {
+	if !exitCodes {
+		return exitError
+	}
+	for ; err != nil; err = errors.Unwrap(err) {
+		switch err.(type) {
+		case *pkg_frozendb.KeyNotFoundError:
+			return exitKeyNotFound
+		case *pkg_frozendb.WriteError:
+			return exitWriteError
+		case *pkg_frozendb.CorruptDatabaseError, *pkg_frozendb.TruncatedFileError:
+			return exitCorrupt
+		case *pkg_frozendb.InvalidInputError:
+			return exitInvalidInput
+		}
+	}
+	return exitError
 }
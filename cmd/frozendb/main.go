@@ -1,11 +1,28 @@
 package main
 
 import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/csv"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"iter"
+	"math"
+	"math/rand/v2"
 	"os"
+	"os/signal"
+	"slices"
 	"strconv"
 	"strings"
+	"syscall"
+	"time"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/google/uuid"
 	internal_frozendb "github.com/susu-dot-dev/frozenDB/internal/frozendb"
@@ -42,31 +59,29 @@ func parseGlobalFlags(osArgs []string) (*globalFlags, error) {
 	for i < len(osArgs) {
 		arg := osArgs[i]
 
-		// Check for --path flag
-		if arg == "--path" {
+		// Check for --path flag (--path value or --path=value)
+		if value, consumed, err := flagValue(osArgs, i, "--path"); err != nil {
+			return nil, err
+		} else if consumed > 0 {
 			if seenPath {
 				return nil, pkg_frozendb.NewInvalidInputError("duplicate flag: --path", nil)
 			}
-			if i+1 >= len(osArgs) {
-				return nil, pkg_frozendb.NewInvalidInputError("--path requires a value", nil)
-			}
-			flags.path = osArgs[i+1]
+			flags.path = value
 			seenPath = true
-			i += 2
+			i += consumed
 			continue
 		}
 
-		// Check for --finder flag
-		if arg == "--finder" {
+		// Check for --finder flag (--finder value or --finder=value)
+		if value, consumed, err := flagValue(osArgs, i, "--finder"); err != nil {
+			return nil, err
+		} else if consumed > 0 {
 			if seenFinder {
 				return nil, pkg_frozendb.NewInvalidInputError("duplicate flag: --finder", nil)
 			}
-			if i+1 >= len(osArgs) {
-				return nil, pkg_frozendb.NewInvalidInputError("--finder requires a value", nil)
-			}
-			flags.finder = osArgs[i+1]
+			flags.finder = value
 			seenFinder = true
-			i += 2
+			i += consumed
 			continue
 		}
 
@@ -90,10 +105,27 @@ func parseGlobalFlags(osArgs []string) (*globalFlags, error) {
 	return flags, nil
 }
 
+// flagValue matches a value flag at args[i] in either the "--name value" or the
+// "--name=value" form. consumed is the number of arguments used (0 if args[i] is
+// not the flag). A missing value is an InvalidInputError.
+func flagValue(args []string, i int, name string) (value string, consumed int, err error) {
+	arg := args[i]
+	if arg == name {
+		if i+1 >= len(args) {
+			return "", 0, pkg_frozendb.NewInvalidInputError(fmt.Sprintf("%s requires a value", name), nil)
+		}
+		return args[i+1], 2, nil
+	}
+	if value, ok := strings.CutPrefix(arg, name+"="); ok {
+		return value, 1, nil
+	}
+	return "", 0, nil
+}
+
 // parseFinderStrategy maps case-insensitive finder values to FinderStrategy constants
 // Per FR-005: Default to BinarySearchFinder if empty/missing
 // Per A-003: Case-insensitive normalization
-// Per VR-004: Validate finder value is one of: simple, inmemory, binary
+// Per VR-004: Validate finder value is one of: simple, inmemory, binary, auto
 func parseFinderStrategy(value string) (pkg_frozendb.FinderStrategy, error) {
 	// Normalize to lowercase for case-insensitive matching
 	normalized := strings.ToLower(value)
@@ -105,18 +137,28 @@ func parseFinderStrategy(value string) (pkg_frozendb.FinderStrategy, error) {
 		return pkg_frozendb.FinderStrategySimple, nil
 	case "inmemory":
 		return pkg_frozendb.FinderStrategyInMemory, nil
+	case "auto":
+		return pkg_frozendb.FinderStrategyAuto, nil
 	default:
 		return "", pkg_frozendb.NewInvalidInputError(
-			fmt.Sprintf("invalid finder strategy: %s (valid: simple, inmemory, binary)", value),
+			fmt.Sprintf("invalid finder strategy: %s (valid: simple, inmemory, binary, auto)", value),
 			nil,
 		)
 	}
 }
 
 // main is the CLI entry point. Routes to subcommand handlers.
-// Follows Unix conventions: silent success, errors to stderr, exit codes 0/1.
+// Follows Unix conventions: silent success, errors to stderr, exit codes 0/1,
+// or the per-error exit codes in errors.go with --exit-codes.
 func main() {
-	// Handle version command/flag before anything else
+	// --exit-codes applies to every command, including create, which parses its
+	// own arguments, so it is taken out before any command sees them
+	if slices.Contains(os.Args[1:], "--exit-codes") {
+		exitCodes = true
+		os.Args = slices.DeleteFunc(os.Args, func(arg string) bool { return arg == "--exit-codes" })
+	}
+
+	// Handle version command/flag before the other commands
 	if len(os.Args) >= 2 && (os.Args[1] == "version" || os.Args[1] == "--version") {
 		handleVersion()
 	}
@@ -126,16 +168,35 @@ func main() {
 		fmt.Fprintln(os.Stderr, "Usage: frozendb <command> [arguments]")
 		fmt.Fprintln(os.Stderr, "")
 		fmt.Fprintln(os.Stderr, "Commands:")
-		fmt.Fprintln(os.Stderr, "  create <path>                                             - Initialize new database")
+		fmt.Fprintln(os.Stderr, "  create [--checksum-interval N] [--value-compression none|gzip] [--meta key=value]... [--record-created-at] [--no-immutable] [--force] <path> - Initialize new database (--force replaces an existing file)")
+		fmt.Fprintln(os.Stderr, "  create --estimate <rows> [--checksum-interval N]           - Print the projected file size in bytes")
 		fmt.Fprintln(os.Stderr, "  [--path <file>] [--finder <strategy>] begin              - Start transaction")
 		fmt.Fprintln(os.Stderr, "  [--path <file>] [--finder <strategy>] commit             - Commit transaction")
 		fmt.Fprintln(os.Stderr, "  [--path <file>] [--finder <strategy>] savepoint          - Create savepoint")
 		fmt.Fprintln(os.Stderr, "  [--path <file>] [--finder <strategy>] rollback [id]      - Rollback transaction")
+		fmt.Fprintln(os.Stderr, "  [--path <file>] [--finder <strategy>] mark               - Commit an empty transaction (NullRow)")
 		fmt.Fprintln(os.Stderr, "  [--path <file>] [--finder <strategy>] add <key|NOW> <val> - Insert key-value pair")
-		fmt.Fprintln(os.Stderr, "  [--path <file>] [--finder <strategy>] get <key>          - Retrieve value by key")
-		fmt.Fprintln(os.Stderr, "  [--path <file>] inspect [--offset N] [--limit N] [--print-header BOOL] - Display database contents")
-		fmt.Fprintln(os.Stderr, "  [--path <file>] verify                                   - Verify database integrity")
+		fmt.Fprintln(os.Stderr, "  [--path <file>] [--finder <strategy>] get <key> [--base64] - Retrieve value by key")
+		fmt.Fprintln(os.Stderr, "  [--path <file>] inspect [--offset N] [--limit N] [--print-header BOOL] [--show-time] [--follow] [--raw] [--type TYPE] [--committed-only] - Display database contents")
+		fmt.Fprintln(os.Stderr, "  [--path <file>] verify [--parallel N] [--check-ordering] [--repair --yes]   - Verify database integrity")
+		fmt.Fprintln(os.Stderr, "  [--path <file>] decode --index N                         - Explain the bytes of one row")
+		fmt.Fprintln(os.Stderr, "  diff --a <file> --b <file> [--verbose]                   - Compare two databases by key")
+		fmt.Fprintln(os.Stderr, "  [--path <file>] export [--after KEY] [--before KEY] [--count-only] [--format jsonl|csv] [--flatten] - Write committed rows as JSON lines or CSV")
+		fmt.Fprintln(os.Stderr, "  [--path <file>] head [N]                                 - Print the first N committed rows (default 10)")
+		fmt.Fprintln(os.Stderr, "  [--path <file>] tail [N]                                 - Print the last N committed rows (default 10)")
+		fmt.Fprintln(os.Stderr, "  [--path <file>] backup [--since BYTES] --out <delta>     - Copy the bytes after offset BYTES and print the new offset")
+		fmt.Fprintln(os.Stderr, "  [--path <file>] restore --apply <delta>                  - Append a backup delta and print the new offset")
+		fmt.Fprintln(os.Stderr, "  [--path <file>] seed --rows N [--no-immutable] [--force] - Create a database holding N deterministic sample rows")
+		fmt.Fprintln(os.Stderr, "  [--path <file>] reframe --row-size N --out <file> [--no-immutable] [--force] - Rewrite committed rows into a new database with another row size")
+		fmt.Fprintln(os.Stderr, "  [--path <file>] set-skew --skew-ms N --out <file> [--no-immutable] [--force] - Copy every row into a new database with another skew_ms")
+		fmt.Fprintln(os.Stderr, "  [--path <file>] [--finder <strategy>] merge-import <file>... - Append the rows of sorted, non-overlapping JSON-Lines files in order")
 		fmt.Fprintln(os.Stderr, "  version                                                  - Display version information")
+		fmt.Fprintln(os.Stderr, "")
+		fmt.Fprintln(os.Stderr, "Flags taking a value accept both --flag value and --flag=value.")
+		fmt.Fprintln(os.Stderr, "--finder accepts simple, inmemory, binary (default), or auto (chosen from file size).")
+		fmt.Fprintln(os.Stderr, "Errors exit with code 1. With --exit-codes, any command exits with 2 for key not found,")
+		fmt.Fprintln(os.Stderr, "3 for write errors (including a held lock), 4 for a corrupt database, 5 for invalid input,")
+		fmt.Fprintln(os.Stderr, "and 1 for other errors.")
 		os.Exit(1)
 	}
 
@@ -152,7 +213,8 @@ func main() {
 	}
 
 	// VR-001: Validate --path is present for commands requiring it
-	if flags.path == "" {
+	// 'diff' names its two databases with its own --a/--b flags instead
+	if flags.path == "" && flags.subcommand != "diff" {
 		printError(pkg_frozendb.NewInvalidInputError("missing required flag: --path", nil))
 	}
 
@@ -172,12 +234,38 @@ func main() {
 		handleSavepoint(flags.path, finderStrategy)
 	case "rollback":
 		handleRollback(flags.path, finderStrategy, flags.args)
+	case "mark":
+		handleMark(flags.path, finderStrategy)
 	case "add":
 		handleAdd(flags.path, finderStrategy, flags.args)
 	case "get":
 		handleGet(flags.path, finderStrategy, flags.args)
 	case "inspect":
 		handleInspect(flags.path, finderStrategy, flags.args)
+	case "verify":
+		handleVerify(flags.path, flags.args)
+	case "diff":
+		handleDiff(finderStrategy, flags.args)
+	case "export":
+		handleExport(flags.path, finderStrategy, flags.args)
+	case "decode":
+		handleDecode(flags.path, flags.args)
+	case "head":
+		handleHead(flags.path, finderStrategy, flags.args)
+	case "tail":
+		handleTail(flags.path, finderStrategy, flags.args)
+	case "backup":
+		handleBackup(flags.path, flags.args)
+	case "restore":
+		handleRestore(flags.path, flags.args)
+	case "seed":
+		handleSeed(flags.path, finderStrategy, flags.args)
+	case "reframe":
+		handleReframe(flags.path, finderStrategy, flags.args)
+	case "set-skew":
+		handleSetSkew(flags.path, flags.args)
+	case "merge-import":
+		handleMergeImport(flags.path, finderStrategy, flags.args)
 	default:
 		printError(pkg_frozendb.NewInvalidInputError(fmt.Sprintf("unknown command: %s", flags.subcommand), nil))
 	}
@@ -192,35 +280,215 @@ func handleVersion() {
 }
 
 // handleCreate implements the 'create' command.
-// Creates a new database file with default row_size and skew_ms.
-// Requires sudo elevation for setting file attributes.
+// Creates a new database file with default row_size and skew_ms and, with
+// --checksum-interval, a non-default number of rows between checksum rows.
+// Requires sudo elevation for setting file attributes, unless --no-immutable is
+// given, which skips the append-only attribute and prints a warning. An existing
+// file is only replaced with --force.
 func handleCreate() {
-	// Expect exactly one positional argument: the path
-	if len(os.Args) < 3 {
-		printError(pkg_frozendb.NewInvalidInputError("missing required argument: path", nil))
-	}
-	if len(os.Args) > 3 {
-		printError(pkg_frozendb.NewInvalidInputError("too many arguments for create command", nil))
+	opts, err := parseCreateArgs(os.Args[2:])
+	if err != nil {
+		printError(err)
 	}
 
-	path := os.Args[2]
+	if opts.estimate {
+		fmt.Println(pkg_frozendb.EstimatedSize(defaultRowSize, opts.estimateRows, opts.checksumInterval))
+		os.Exit(0)
+	}
 
 	// Create config with default values
-	config := internal_frozendb.NewCreateConfig(path, defaultRowSize, defaultSkewMs)
+	config := internal_frozendb.NewCreateConfig(opts.path, defaultRowSize, defaultSkewMs)
+	config.SetChecksumInterval(opts.checksumInterval)
+	config.SetNoImmutable(opts.noImmutable)
+	config.SetForce(opts.force)
+	config.SetValueCompression(opts.valueCompression)
+	config.SetMetadata(opts.metadata)
+	if opts.recordCreatedAt {
+		config.SetCreatedAt(time.Now())
+	}
 
 	// Call internal Create function
 	if err := internal_frozendb.Create(config); err != nil {
 		printError(err)
 	}
 
-	// Success: exit silently with code 0 (per FR-005)
+	if opts.noImmutable {
+		fmt.Fprintln(os.Stderr, "warning: append-only attribute not set; the operating system does not prevent modifying the file")
+	}
+
+	// Success: exit with code 0 (per FR-005)
 	os.Exit(0)
 }
 
+// createOptions holds the parsed arguments of the create command.
+type createOptions struct {
+	path             string
+	checksumInterval int                                // 0 when absent, meaning the default
+	noImmutable      bool                               // Skip the append-only attribute (and the sudo requirement)
+	force            bool                               // Replace an existing file at path
+	valueCompression internal_frozendb.ValueCompression // "" when absent, meaning none
+	metadata         map[string]string                  // From --meta key=value, nil when absent
+	recordCreatedAt  bool                               // Record the current time as the creation time
+	estimate         bool                               // Print the projected size of estimateRows rows instead of creating
+	estimateRows     int64
+}
+
+// parseCreateArgs parses the create command arguments: exactly one positional path,
+// an optional --checksum-interval, an optional --value-compression, any number of
+// --meta key=value, an optional --record-created-at, an optional --no-immutable,
+// and an optional --force. With
+// --estimate <rows> the path may be omitted, since nothing is created.
+func parseCreateArgs(args []string) (createOptions, error) {
+	var opts createOptions
+	seenPath := false
+	for i := 0; i < len(args); {
+		if value, consumed, err := flagValue(args, i, "--checksum-interval"); err != nil {
+			return createOptions{}, err
+		} else if consumed > 0 {
+			opts.checksumInterval, err = strconv.Atoi(value)
+			if err != nil {
+				return createOptions{}, pkg_frozendb.NewInvalidInputError("--checksum-interval must be a number", err)
+			}
+			i += consumed
+			continue
+		}
+		if value, consumed, err := flagValue(args, i, "--value-compression"); err != nil {
+			return createOptions{}, err
+		} else if consumed > 0 {
+			opts.valueCompression = internal_frozendb.ValueCompression(value)
+			i += consumed
+			continue
+		}
+		if value, consumed, err := flagValue(args, i, "--meta"); err != nil {
+			return createOptions{}, err
+		} else if consumed > 0 {
+			key, metaValue, ok := strings.Cut(value, "=")
+			if !ok || key == "" {
+				return createOptions{}, pkg_frozendb.NewInvalidInputError(fmt.Sprintf("--meta must be key=value, got %q", value), nil)
+			}
+			if _, seen := opts.metadata[key]; seen {
+				return createOptions{}, pkg_frozendb.NewInvalidInputError(fmt.Sprintf("--meta key %q given more than once", key), nil)
+			}
+			if opts.metadata == nil {
+				opts.metadata = make(map[string]string)
+			}
+			opts.metadata[key] = metaValue
+			i += consumed
+			continue
+		}
+		if value, consumed, err := flagValue(args, i, "--estimate"); err != nil {
+			return createOptions{}, err
+		} else if consumed > 0 {
+			opts.estimateRows, err = strconv.ParseInt(value, 10, 64)
+			if err != nil || opts.estimateRows < 0 {
+				return createOptions{}, pkg_frozendb.NewInvalidInputError("--estimate must be a non-negative number of rows", err)
+			}
+			opts.estimate = true
+			i += consumed
+			continue
+		}
+		if args[i] == "--record-created-at" {
+			opts.recordCreatedAt = true
+			i++
+			continue
+		}
+		if args[i] == "--no-immutable" {
+			opts.noImmutable = true
+			i++
+			continue
+		}
+		if args[i] == "--force" {
+			opts.force = true
+			i++
+			continue
+		}
+		if strings.HasPrefix(args[i], "--") {
+			return createOptions{}, pkg_frozendb.NewInvalidInputError(fmt.Sprintf("unknown flag: %s", args[i]), nil)
+		}
+		if seenPath {
+			return createOptions{}, pkg_frozendb.NewInvalidInputError("too many arguments for create command", nil)
+		}
+		opts.path = args[i]
+		seenPath = true
+		i++
+	}
+	// Create validates the interval itself; an estimate never reaches it.
+	if opts.estimate && opts.checksumInterval != 0 &&
+		(opts.checksumInterval < internal_frozendb.MIN_CHECKSUM_INTERVAL || opts.checksumInterval > internal_frozendb.MAX_CHECKSUM_INTERVAL) {
+		return createOptions{}, pkg_frozendb.NewInvalidInputError(fmt.Sprintf("--checksum-interval must be between %d and %d", internal_frozendb.MIN_CHECKSUM_INTERVAL, internal_frozendb.MAX_CHECKSUM_INTERVAL), nil)
+	}
+	if !seenPath && !opts.estimate {
+		return createOptions{}, pkg_frozendb.NewInvalidInputError("missing required argument: path", nil)
+	}
+	return opts, nil
+}
+
+// writeGuard holds back SIGINT and SIGTERM while a write command runs. Each
+// command performs several appends (finalizing the previous row, a checksum row,
+// the next partial row), and being killed between them leaves the tail in a state
+// no command produces. With the guard, the operation always completes; finish
+// then rolls back the active transaction if a signal arrived, so an interrupted
+// command leaves the file at a transaction boundary.
+type writeGuard struct {
+	signals chan os.Signal
+}
+
+// newWriteGuard starts holding back SIGINT and SIGTERM
+func newWriteGuard() *writeGuard {
+	g := &writeGuard{signals: make(chan os.Signal, 1)}
+	signal.Notify(g.signals, os.Interrupt, syscall.SIGTERM)
+	return g
+}
+
+// finish returns if no signal arrived. Otherwise it rolls back the active
+// transaction, if any, and exits with 128 plus the signal number when work was
+// abandoned: a transaction was rolled back, or complete is false because the
+// command stopped before doing all it was asked to. A signal that arrives once
+// the work is done, such as during a commit that then succeeds, abandons
+// nothing, so finish returns and the command exits 0.
+func (g *writeGuard) finish(db *pkg_frozendb.FrozenDB, complete bool) {
+	sig, rolledBack, err := g.rollbackIfInterrupted(db)
+	if err != nil {
+		printError(err)
+	}
+	if sig == nil || (complete && !rolledBack) {
+		signal.Stop(g.signals)
+		return
+	}
+	_ = db.Close()
+	if rolledBack {
+		fmt.Fprintf(os.Stderr, "interrupted (%s): active transaction rolled back\n", sig)
+	} else {
+		fmt.Fprintf(os.Stderr, "interrupted (%s)\n", sig)
+	}
+	os.Exit(128 + int(sig.(syscall.Signal)))
+}
+
+// rollbackIfInterrupted rolls back the active transaction of db if a signal has
+// arrived, returning the signal (nil if none) and whether a transaction was
+// rolled back.
+func (g *writeGuard) rollbackIfInterrupted(db *pkg_frozendb.FrozenDB) (os.Signal, bool, error) {
+	var sig os.Signal
+	select {
+	case sig = <-g.signals:
+	default:
+		return nil, false, nil
+	}
+	tx := db.GetActiveTx()
+	if tx == nil {
+		return sig, false, nil
+	}
+	if err := tx.Rollback(0); err != nil {
+		return sig, false, err
+	}
+	return sig, true, nil
+}
+
 // handleBegin implements the 'begin' command.
 // Starts a new transaction on the specified database.
 func handleBegin(path string, finderStrategy pkg_frozendb.FinderStrategy) {
 	// Open database in write mode
+	guard := newWriteGuard()
 	db, err := pkg_frozendb.NewFrozenDB(path, pkg_frozendb.MODE_WRITE, finderStrategy)
 	if err != nil {
 		printError(err)
@@ -238,6 +506,8 @@ func handleBegin(path string, finderStrategy pkg_frozendb.FinderStrategy) {
 		printError(err)
 	}
 
+	guard.finish(db, true)
+
 	// Success: exit silently with code 0 (per FR-005)
 	os.Exit(0)
 }
@@ -246,6 +516,7 @@ func handleBegin(path string, finderStrategy pkg_frozendb.FinderStrategy) {
 // Commits the active transaction.
 func handleCommit(path string, finderStrategy pkg_frozendb.FinderStrategy) {
 	// Open database in write mode
+	guard := newWriteGuard()
 	db, err := pkg_frozendb.NewFrozenDB(path, pkg_frozendb.MODE_WRITE, finderStrategy)
 	if err != nil {
 		printError(err)
@@ -263,6 +534,40 @@ func handleCommit(path string, finderStrategy pkg_frozendb.FinderStrategy) {
 		printError(err)
 	}
 
+	guard.finish(db, true)
+
+	// Success: exit silently with code 0 (per FR-005)
+	os.Exit(0)
+}
+
+// handleMark implements the 'mark' command.
+// Begins and immediately commits an empty transaction, which appends a NullRow
+// that marks a point in time without storing data.
+func handleMark(path string, finderStrategy pkg_frozendb.FinderStrategy) {
+	// Open database in write mode
+	guard := newWriteGuard()
+	db, err := pkg_frozendb.NewFrozenDB(path, pkg_frozendb.MODE_WRITE, finderStrategy)
+	if err != nil {
+		printError(err)
+	}
+	defer func() { _ = db.Close() }() // Error ignored - exit on errors
+
+	// Check if transaction already active
+	if db.GetActiveTx() != nil {
+		printError(pkg_frozendb.NewInvalidActionError("transaction already active", nil))
+	}
+
+	// Begin and commit with no rows: Commit writes a NullRow
+	tx, err := db.BeginTx()
+	if err != nil {
+		printError(err)
+	}
+	if err := tx.Commit(); err != nil {
+		printError(err)
+	}
+
+	guard.finish(db, true)
+
 	// Success: exit silently with code 0 (per FR-005)
 	os.Exit(0)
 }
@@ -271,6 +576,7 @@ func handleCommit(path string, finderStrategy pkg_frozendb.FinderStrategy) {
 // Creates a savepoint at the current position in the active transaction.
 func handleSavepoint(path string, finderStrategy pkg_frozendb.FinderStrategy) {
 	// Open database in write mode
+	guard := newWriteGuard()
 	db, err := pkg_frozendb.NewFrozenDB(path, pkg_frozendb.MODE_WRITE, finderStrategy)
 	if err != nil {
 		printError(err)
@@ -288,6 +594,8 @@ func handleSavepoint(path string, finderStrategy pkg_frozendb.FinderStrategy) {
 		printError(err)
 	}
 
+	guard.finish(db, true)
+
 	// Success: exit silently with code 0 (per FR-005)
 	os.Exit(0)
 }
@@ -309,6 +617,7 @@ func handleRollback(path string, finderStrategy pkg_frozendb.FinderStrategy, arg
 	}
 
 	// Open database in write mode
+	guard := newWriteGuard()
 	db, err := pkg_frozendb.NewFrozenDB(path, pkg_frozendb.MODE_WRITE, finderStrategy)
 	if err != nil {
 		printError(err)
@@ -326,6 +635,8 @@ func handleRollback(path string, finderStrategy pkg_frozendb.FinderStrategy, arg
 		printError(err)
 	}
 
+	guard.finish(db, true)
+
 	// Success: exit silently with code 0 (per FR-005)
 	os.Exit(0)
 }
@@ -368,6 +679,7 @@ func handleAdd(path string, finderStrategy pkg_frozendb.FinderStrategy, args []s
 	}
 
 	// Open database in write mode
+	guard := newWriteGuard()
 	db, err := pkg_frozendb.NewFrozenDB(path, pkg_frozendb.MODE_WRITE, finderStrategy)
 	if err != nil {
 		printError(err)
@@ -385,6 +697,8 @@ func handleAdd(path string, finderStrategy pkg_frozendb.FinderStrategy, args []s
 		printError(err)
 	}
 
+	guard.finish(db, true)
+
 	// Success: output the key to stdout (FR-004, VR-009 through VR-012)
 	fmt.Println(key.String())
 	os.Exit(0)
@@ -392,14 +706,14 @@ func handleAdd(path string, finderStrategy pkg_frozendb.FinderStrategy, args []s
 
 // handleGet implements the 'get' command.
 // Retrieves a value by UUIDv7 key and prints it as pretty-formatted JSON.
+// With --base64, the stored value bytes are printed base64-encoded instead, so
+// values that are unsafe to write to a terminal can be retrieved exactly.
 func handleGet(path string, finderStrategy pkg_frozendb.FinderStrategy, args []string) {
-	// Parse positional argument: key
-	if len(args) < 1 {
-		printError(pkg_frozendb.NewInvalidInputError("missing required argument: key", nil))
+	keyStr, base64Output, err := parseGetArgs(args)
+	if err != nil {
+		printError(err)
 	}
 
-	keyStr := args[0]
-
 	// Validate UUIDv7 format (FR-003)
 	key, err := validateUUIDv7(keyStr)
 	if err != nil {
@@ -413,6 +727,15 @@ func handleGet(path string, finderStrategy pkg_frozendb.FinderStrategy, args []s
 	}
 	defer func() { _ = db.Close() }() // Error ignored - exit on errors
 
+	if base64Output {
+		var raw json.RawMessage
+		if err := db.Get(key, &raw); err != nil {
+			printError(err)
+		}
+		fmt.Println(base64.StdEncoding.EncodeToString(raw))
+		os.Exit(0)
+	}
+
 	// Get value by key
 	var result interface{}
 	if err := db.Get(key, &result); err != nil {
@@ -428,6 +751,27 @@ func handleGet(path string, finderStrategy pkg_frozendb.FinderStrategy, args []s
 	os.Exit(0)
 }
 
+// parseGetArgs parses the 'get' arguments: the key and an optional --base64 flag,
+// which may appear before or after it.
+func parseGetArgs(args []string) (keyStr string, base64Output bool, err error) {
+	for _, arg := range args {
+		switch {
+		case arg == "--base64":
+			base64Output = true
+		case strings.HasPrefix(arg, "--"):
+			return "", false, pkg_frozendb.NewInvalidInputError(fmt.Sprintf("unknown flag: %s", arg), nil)
+		case keyStr == "":
+			keyStr = arg
+		default:
+			return "", false, pkg_frozendb.NewInvalidInputError(fmt.Sprintf("unexpected argument: %s", arg), nil)
+		}
+	}
+	if keyStr == "" {
+		return "", false, pkg_frozendb.NewInvalidInputError("missing required argument: key", nil)
+	}
+	return keyStr, base64Output, nil
+}
+
 // validateUUIDv7 validates that a string is a valid UUIDv7.
 // Returns the parsed UUID or an InvalidInputError.
 // Per FR-003: "Keys must be valid UUIDv7 strings".
@@ -464,13 +808,18 @@ func prettyPrintJSON(value interface{}) error {
 }
 
 // handleInspect implements the 'inspect' command.
-// Displays database contents in tab-separated format.
+// Displays database contents in tab-separated format, streaming one row at a time.
+// With --follow, keeps printing rows appended to the file until the limit is
+// reached or the process is interrupted. --type and --committed-only filter the
+// rows in the --offset/--limit window; rows they hide are still read, and a row
+// that fails to parse still makes the command exit 1.
 func handleInspect(path string, finderStrategy pkg_frozendb.FinderStrategy, args []string) {
 	// Parse inspect-specific flags
-	offset, limit, printHeader, err := parseInspectFlags(args)
+	opts, err := parseInspectFlags(args)
 	if err != nil {
 		printError(err)
 	}
+	offset, limit := opts.offset, opts.limit
 
 	// Open database file in read mode
 	file, err := internal_frozendb.NewDBFile(path, internal_frozendb.MODE_READ)
@@ -490,48 +839,63 @@ func handleInspect(path string, finderStrategy pkg_frozendb.FinderStrategy, args
 		printError(err)
 	}
 
-	// Print optional header table
-	if printHeader {
-		printHeaderTable(header)
-	}
-
-	// Print row data table header
-	printRowTableHeader()
-
-	// Calculate total rows: (fileSize - 64) / rowSize
-	fileSize := file.Size()
+	opts.compression = header.GetValueCompression()
 	rowSize := int64(header.GetRowSize())
-	totalRows := (fileSize - 64) / rowSize
 
-	// Validate offset
+	// A partially written final row is shown as type "partial". With --follow it is
+	// instead printed once complete, so it is never shown twice.
+	rowCount := completeRowCount(file.Size(), rowSize)
+	if !opts.follow && internal_frozendb.HEADER_SIZE+rowCount*rowSize < file.Size() {
+		rowCount++
+	}
+
+	// Validate offset before printing anything. Past the end is only meaningful
+	// with --follow, which waits for the rows to be appended.
 	if offset < 0 {
 		printError(pkg_frozendb.NewInvalidInputError("offset cannot be negative", nil))
 	}
+	if !opts.follow && offset >= rowCount {
+		printError(pkg_frozendb.NewInvalidInputError(fmt.Sprintf("offset %d exceeds %d total rows", offset, rowCount), nil))
+	}
 
-	// Determine end index based on limit
-	var endIndex int64
-	if limit < 0 {
-		endIndex = totalRows // Display all remaining rows
-	} else {
-		endIndex = offset + limit
-		if endIndex > totalRows {
-			endIndex = totalRows
+	// Print optional header table, reading the header again with the initial
+	// checksum row, which holds the metadata of a version 2 file
+	if opts.printHeader {
+		fullHeader, err := pkg_frozendb.ReadHeader(path)
+		if err != nil {
+			printError(err)
 		}
+		printHeaderTable(fullHeader)
 	}
 
-	// Track errors for exit code
-	hasErrors := false
+	// Print row data table header
+	printRowTableHeader(opts)
 
-	// Iterate through rows
-	for index := offset; index < endIndex; index++ {
-		row, err := readAndParseRow(file, index, int(rowSize))
-		if err != nil {
-			// Mark as error but continue processing
-			hasErrors = true
-			row.Type = "error"
-			row.Index = index
+	// Determine end index based on limit (exclusive; rows past the current end of
+	// file are only reached in follow mode)
+	endIndex := int64(math.MaxInt64)
+	if limit >= 0 && limit <= math.MaxInt64-offset {
+		endIndex = offset + limit
+	}
+
+	// Rows are read and printed one at a time, so memory use does not grow with the file
+	next, hasErrors := printInspectRows(file, offset, min(endIndex, rowCount), rowSize, opts)
+
+	if opts.follow {
+		// Keep printing appended rows until the limit is reached or the user interrupts
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		ticker := time.NewTicker(inspectFollowInterval)
+		for next < endIndex && ctx.Err() == nil {
+			select {
+			case <-ctx.Done():
+			case <-ticker.C:
+				var rowErrors bool
+				next, rowErrors = printInspectRows(file, next, min(endIndex, completeRowCount(file.Size(), rowSize)), rowSize, opts)
+				hasErrors = hasErrors || rowErrors
+			}
 		}
-		printInspectRow(row)
+		ticker.Stop()
+		stop()
 	}
 
 	// Exit with appropriate code
@@ -541,124 +905,1684 @@ func handleInspect(path string, finderStrategy pkg_frozendb.FinderStrategy, args
 	os.Exit(0)
 }
 
-// parseInspectFlags parses inspect-specific command flags
-func parseInspectFlags(args []string) (offset int64, limit int64, printHeader bool, err error) {
-	// Set defaults
-	offset = 0
-	limit = -1
-	printHeader = false
-
-	// Parse flags
-	i := 0
-	for i < len(args) {
-		if i >= len(args) {
-			break
-		}
+// inspectFollowInterval is how often inspect --follow checks the file for new rows.
+const inspectFollowInterval = 250 * time.Millisecond
 
-		arg := args[i]
+// completeRowCount returns the number of complete rows in a file of fileSize bytes.
+// A partially written row at the end of the file is not counted.
+func completeRowCount(fileSize, rowSize int64) int64 {
+	if fileSize <= internal_frozendb.HEADER_SIZE {
+		return 0
+	}
+	return (fileSize - internal_frozendb.HEADER_SIZE) / rowSize
+}
 
-		if arg == "--offset" {
-			if i+1 >= len(args) {
-				return 0, 0, false, pkg_frozendb.NewInvalidInputError("--offset requires a value", nil)
-			}
-			val, parseErr := strconv.ParseInt(args[i+1], 10, 64)
-			if parseErr != nil {
-				return 0, 0, false, pkg_frozendb.NewInvalidInputError("--offset must be a number", parseErr)
-			}
-			offset = val
-			i += 2
-			continue
+// printInspectRows prints rows [from, to) and returns the index after the last row
+// printed. hasErrors reports whether any row failed to parse; such rows are printed
+// as error rows and processing continues.
+func printInspectRows(file internal_frozendb.DBFile, from, to, rowSize int64, opts inspectOptions) (next int64, hasErrors bool) {
+	for index := from; index < to; index++ {
+		row, err := readAndParseRow(file, index, int(rowSize))
+		if err != nil {
+			// Mark as error but continue processing
+			hasErrors = true
+			row.Type = "error"
+			row.Index = index
 		}
-
-		if arg == "--limit" {
-			if i+1 >= len(args) {
-				return 0, 0, false, pkg_frozendb.NewInvalidInputError("--limit requires a value", nil)
-			}
-			val, parseErr := strconv.ParseInt(args[i+1], 10, 64)
-			if parseErr != nil {
-				return 0, 0, false, pkg_frozendb.NewInvalidInputError("--limit must be a number", parseErr)
-			}
-			limit = val
-			i += 2
+		if opts.rowType != "" && !strings.EqualFold(row.Type, opts.rowType) {
 			continue
 		}
-
-		if arg == "--print-header" {
-			if i+1 >= len(args) {
-				return 0, 0, false, pkg_frozendb.NewInvalidInputError("--print-header requires a value", nil)
-			}
-			val := strings.ToLower(args[i+1])
-			switch val {
-			case "true", "t", "1":
-				printHeader = true
-			case "false", "f", "0":
-				printHeader = false
-			default:
-				return 0, 0, false, pkg_frozendb.NewInvalidInputError("--print-header must be true or false", nil)
-			}
-			i += 2
+		if opts.committedOnly && !opts.visibility.visible(file, row, rowSize) {
 			continue
 		}
+		if opts.compression != internal_frozendb.ValueCompressionNone {
+			decompressInspectValue(&row, opts.compression)
+		}
+		if !opts.raw {
+			row.Value = escapeInspectValue(row.Value)
+		}
+		printInspectRow(row, opts)
+	}
+	return max(from, to), hasErrors
+}
 
-		// Unknown flag
-		return 0, 0, false, pkg_frozendb.NewInvalidInputError(fmt.Sprintf("unknown flag: %s", arg), nil)
+// handleVerify implements the 'verify' command.
+// Validates checksums and row structure of the whole file, exiting silently on success.
+// On failure every problem found is printed, one per line, first problem first.
+// --parallel N validates up to N checksum windows at once; the output is the same.
+// --check-ordering also checks the committed keys against each other under the
+// skew rule and reports the first out-of-order pair with both timestamps.
+// With --repair, a database that fails verification is truncated back to the end of
+// its last fully validated checksum row; --yes is required to confirm the truncation.
+// Data covered by that checksum is never modified.
+func handleVerify(path string, args []string) {
+	opts, err := parseVerifyFlags(args)
+	if err != nil {
+		printError(err)
+	}
+
+	report, verifyErr := internal_frozendb.VerifyWithOptions(path, internal_frozendb.VerifyOptions{
+		Workers:       opts.parallel,
+		CheckOrdering: opts.checkOrdering,
+	})
+	if verifyErr == nil {
+		// Success: exit silently with code 0 (per FR-005)
+		os.Exit(0)
+	}
+	if !opts.repair {
+		printVerifyProblems(report, verifyErr)
+	}
+
+	verifiedSize, err := internal_frozendb.VerifiedPrefixSize(path)
+	if err != nil {
+		printError(pkg_frozendb.NewCorruptDatabaseError("database cannot be repaired", err))
+	}
+
+	fileInfo, err := os.Stat(path)
+	if err != nil {
+		printError(pkg_frozendb.NewPathError("failed to stat database file", err))
+	}
+	rowSize, err := readRowSize(path)
+	if err != nil {
+		printError(err)
+	}
+	discardedBytes := fileInfo.Size() - verifiedSize
+	if discardedBytes <= 0 {
+		// Corruption lies within checksummed data; truncation cannot fix it
+		printError(verifyErr)
+	}
+	discardedRows := (discardedBytes + rowSize - 1) / rowSize
+
+	if !opts.yes {
+		printError(pkg_frozendb.NewInvalidActionError(
+			fmt.Sprintf("repair would discard %d rows (%d bytes) after offset %d (cause: %v); re-run with --yes to confirm",
+				discardedRows, discardedBytes, verifiedSize, verifyErr),
+			nil,
+		))
+	}
+
+	if err := os.Truncate(path, verifiedSize); err != nil {
+		printError(pkg_frozendb.NewWriteError("failed to truncate database (is the append-only attribute still set?)", err))
 	}
 
-	return offset, limit, printHeader, nil
+	fmt.Printf("discarded %d rows (%d bytes)\n", discardedRows, discardedBytes)
+	os.Exit(0)
 }
 
-// printHeaderTable prints the database header information table
-func printHeaderTable(header *internal_frozendb.Header) {
-	fmt.Printf("Row Size\tClock Skew\tFile Version\n")
-	fmt.Printf("%d\t%d\t%d\n", header.GetRowSize(), header.GetSkewMs(), header.GetVersion())
-	fmt.Println() // Blank line separator
+// printVerifyProblems prints verifyErr followed by the other problems in report
+// to stderr and exits with code 1, or verifyErr's code under --exit-codes. report
+// is nil when the header could not be read, in which case only verifyErr is
+// printed.
+func printVerifyProblems(report *internal_frozendb.VerifyReport, verifyErr error) {
+	fmt.Fprintln(os.Stderr, formatError(verifyErr))
+	if report != nil && len(report.Problems) > 1 {
+		for _, problem := range report.Problems[1:] {
+			fmt.Fprintln(os.Stderr, formatError(problem.Err))
+		}
+		if omitted := report.ProblemCount - len(report.Problems); omitted > 0 {
+			fmt.Fprintf(os.Stderr, "Error: %d more problems not shown\n", omitted)
+		}
+	}
+	os.Exit(errorExitCode(verifyErr))
 }
 
-// printRowTableHeader prints the row data table column headers
-func printRowTableHeader() {
-	fmt.Printf("index\ttype\tkey\tvalue\tsavepoint\ttx start\ttx end\trollback\tparity\n")
+// verifyOptions holds the flags of the verify command
+type verifyOptions struct {
+	repair        bool // Truncate the file to its verified prefix
+	yes           bool // Confirm the repair
+	parallel      int  // Checksum windows validated at once
+	checkOrdering bool // Run the committed key ordering pass
 }
 
-// InspectRow represents a single row for display
-type InspectRow struct {
-	Index     int64
-	Type      string
-	Key       string
-	Value     string
-	Savepoint string
-	TxStart   string
-	TxEnd     string
-	Rollback  string
-	Parity    string
+// parseVerifyFlags parses verify-specific command flags
+func parseVerifyFlags(args []string) (verifyOptions, error) {
+	opts := verifyOptions{parallel: 1}
+	for i := 0; i < len(args); {
+		switch args[i] {
+		case "--repair":
+			opts.repair = true
+			i++
+			continue
+		case "--yes":
+			opts.yes = true
+			i++
+			continue
+		case "--check-ordering":
+			opts.checkOrdering = true
+			i++
+			continue
+		}
+		value, consumed, err := flagValue(args, i, "--parallel")
+		if err != nil {
+			return verifyOptions{}, err
+		}
+		if consumed == 0 {
+			return verifyOptions{}, pkg_frozendb.NewInvalidInputError(fmt.Sprintf("unknown flag: %s", args[i]), nil)
+		}
+		opts.parallel, err = strconv.Atoi(value)
+		if err != nil || opts.parallel < 1 {
+			return verifyOptions{}, pkg_frozendb.NewInvalidInputError("--parallel must be a positive number", err)
+		}
+		i += consumed
+	}
+	if opts.yes && !opts.repair {
+		return verifyOptions{}, pkg_frozendb.NewInvalidInputError("--yes requires --repair", nil)
+	}
+	return opts, nil
 }
 
-// printInspectRow prints a single row in TSV format
-func printInspectRow(row InspectRow) {
-	fmt.Printf("%d\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
-		row.Index, row.Type, row.Key, row.Value,
-		row.Savepoint, row.TxStart, row.TxEnd, row.Rollback, row.Parity)
+// readRowSize reads the row_size from the header of the database file at path
+func readRowSize(path string) (int64, error) {
+	header, err := pkg_frozendb.ReadHeader(path)
+	if err != nil {
+		return 0, err
+	}
+	return int64(header.GetRowSize()), nil
 }
 
-// readAndParseRow reads and parses a single row from the database
-func readAndParseRow(file internal_frozendb.DBFile, index int64, rowSize int) (InspectRow, error) {
-	// Calculate offset: 64 (header) + index * rowSize
-	offset := int64(64) + index*int64(rowSize)
+// handleDiff implements the 'diff' command.
+// Walks the committed rows of two databases in key order and reports keys present
+// only in A, only in B, and present in both with differing values. Rolled back
+// and uncommitted rows are invisible on both sides, so they never show up as
+// differences. Prints a summary; with --verbose, each differing key is listed first.
+func handleDiff(finderStrategy pkg_frozendb.FinderStrategy, args []string) {
+	pathA, pathB, verbose, err := parseDiffFlags(args)
+	if err != nil {
+		printError(err)
+	}
 
-	// Read row bytes
-	rowBytes, err := file.Read(offset, int32(rowSize))
+	dbA, err := pkg_frozendb.NewFrozenDB(pathA, pkg_frozendb.MODE_READ, finderStrategy)
 	if err != nil {
-		// Check if this is a partial row at end of file
-		if strings.Contains(err.Error(), "exceeds file size") {
-			// Try to read remaining bytes
-			remaining := file.Size() - offset
-			if remaining > 0 && remaining < int64(rowSize) {
-				partialBytes, readErr := file.Read(offset, int32(remaining))
-				if readErr == nil {
-					return parsePartialRow(index, partialBytes, rowSize)
-				}
-			}
-		}
-		// Return error row
+		printError(err)
+	}
+	defer func() { _ = dbA.Close() }()
+
+	dbB, err := pkg_frozendb.NewFrozenDB(pathB, pkg_frozendb.MODE_READ, finderStrategy)
+	if err != nil {
+		printError(err)
+	}
+	defer func() { _ = dbB.Close() }()
+
+	summary, err := diffDatabases(dbA, dbB, func(kind string, key uuid.UUID) {
+		if verbose {
+			fmt.Printf("%s\t%s\n", kind, key)
+		}
+	})
+	if err != nil {
+		printError(err)
+	}
+
+	fmt.Printf("only-in-a: %d\n", summary.onlyInA)
+	fmt.Printf("only-in-b: %d\n", summary.onlyInB)
+	fmt.Printf("differs: %d\n", summary.differs)
+	os.Exit(0)
+}
+
+// exportOptions holds the parsed export-specific flags
+type exportOptions struct {
+	after     uuid.UUID // Exclusive lower key bound (uuid.Nil for none)
+	before    uuid.UUID // Exclusive upper key bound (uuid.Nil for none)
+	countOnly bool      // Print only the number of matching rows
+	format    string    // exportFormatJSONL or exportFormatCSV
+	flatten   bool      // With CSV, one column per top-level field of flat object values
+}
+
+// Export output formats selected with --format
+const (
+	exportFormatJSONL = "jsonl"
+	exportFormatCSV   = "csv"
+)
+
+// exportLine is one line of export output
+type exportLine struct {
+	Key   uuid.UUID       `json:"key"`
+	Value json.RawMessage `json:"value"`
+}
+
+// handleExport implements the 'export' command.
+// Writes the committed rows whose keys lie strictly between --after and --before,
+// in ascending key order, as one {"key":...,"value":...} JSON object per line.
+// With --format csv it writes a key,value CSV table instead (see writeExportCSV).
+// With --count-only it prints just the number of matching rows, without reading
+// values into memory.
+func handleExport(path string, finderStrategy pkg_frozendb.FinderStrategy, args []string) {
+	opts, err := parseExportFlags(args)
+	if err != nil {
+		printError(err)
+	}
+
+	db, err := pkg_frozendb.NewFrozenDB(path, pkg_frozendb.MODE_READ, finderStrategy)
+	if err != nil {
+		printError(err)
+	}
+	defer func() { _ = db.Close() }()
+
+	if opts.countOnly {
+		count, err := db.CountRange(opts.after, opts.before)
+		if err != nil {
+			printError(err)
+		}
+		fmt.Println(count)
+		os.Exit(0)
+	}
+
+	if opts.format == exportFormatCSV {
+		err = writeExportCSV(db, opts)
+	} else {
+		out := json.NewEncoder(os.Stdout)
+		err = scanExportRange(db, opts, func(key uuid.UUID, value json.RawMessage) error {
+			if err := out.Encode(exportLine{Key: key, Value: value}); err != nil {
+				return pkg_frozendb.NewWriteError("failed to write export output", err)
+			}
+			return nil
+		})
+	}
+	if err != nil {
+		printError(err)
+	}
+	os.Exit(0)
+}
+
+// scanExportRange calls fn for every committed row whose key lies strictly
+// between opts.after and opts.before, in ascending key order. An error returned
+// by fn stops the scan and is returned.
+func scanExportRange(db *pkg_frozendb.FrozenDB, opts exportOptions, fn func(key uuid.UUID, value json.RawMessage) error) error {
+	var fnErr error
+	err := db.Scan(func(key uuid.UUID, value json.RawMessage) bool {
+		if opts.after != uuid.Nil && bytes.Compare(key[:], opts.after[:]) <= 0 {
+			return true
+		}
+		if opts.before != uuid.Nil && bytes.Compare(key[:], opts.before[:]) >= 0 {
+			return false // Keys arrive in ascending order
+		}
+		fnErr = fn(key, value)
+		return fnErr == nil
+	})
+	if err != nil {
+		return err
+	}
+	return fnErr
+}
+
+// writeExportCSV writes the export range as an RFC 4180 CSV table with a header
+// row. By default the columns are key and value, the value holding the row's JSON
+// text in a single quoted field.
+//
+// With --flatten, a first pass checks whether every value is a JSON object whose
+// fields are all scalars; if so, each top-level field becomes a column, in order
+// of first appearance, holding the field's string contents or literal (empty for
+// null or an absent field). Any other shape, or a field named "key", falls back to
+// the key,value columns.
+func writeExportCSV(db *pkg_frozendb.FrozenDB, opts exportOptions) error {
+	var columns []string
+	if opts.flatten {
+		var err error
+		if columns, err = flatExportColumns(db, opts); err != nil {
+			return err
+		}
+	}
+
+	out := csv.NewWriter(os.Stdout)
+	header := []string{"key", "value"}
+	if columns != nil {
+		header = append([]string{"key"}, columns...)
+	}
+	if err := out.Write(header); err != nil {
+		return pkg_frozendb.NewWriteError("failed to write export output", err)
+	}
+
+	err := scanExportRange(db, opts, func(key uuid.UUID, value json.RawMessage) error {
+		record := []string{key.String(), string(value)}
+		if columns != nil {
+			fields, ok := flatFields(value)
+			if !ok {
+				// Only a row committed between the two passes can get here
+				return pkg_frozendb.NewInvalidDataError(fmt.Sprintf("value of key %s is not a flat JSON object; export without --flatten", key), nil)
+			}
+			record = append(record[:1], make([]string, len(columns))...)
+			for _, field := range fields {
+				i := slices.Index(columns, field.name)
+				if i < 0 {
+					return pkg_frozendb.NewInvalidDataError(fmt.Sprintf("value of key %s has a new field %q; export without --flatten", key, field.name), nil)
+				}
+				record[1+i] = field.cell
+			}
+		}
+		if err := out.Write(record); err != nil {
+			return pkg_frozendb.NewWriteError("failed to write export output", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	out.Flush()
+	if err := out.Error(); err != nil {
+		return pkg_frozendb.NewWriteError("failed to write export output", err)
+	}
+	return nil
+}
+
+// flatExportColumns returns the union of top-level field names over the export
+// range, in order of first appearance, or nil if any value is not flat (see
+// flatFields) or no value has a field.
+func flatExportColumns(db *pkg_frozendb.FrozenDB, opts exportOptions) ([]string, error) {
+	var columns []string
+	flat := true
+	err := scanExportRange(db, opts, func(_ uuid.UUID, value json.RawMessage) error {
+		fields, ok := flatFields(value)
+		if !ok {
+			flat = false
+			return errStopScan
+		}
+		for _, field := range fields {
+			if !slices.Contains(columns, field.name) {
+				columns = append(columns, field.name)
+			}
+		}
+		return nil
+	})
+	if err != nil && err != errStopScan {
+		return nil, err
+	}
+	if !flat || len(columns) == 0 {
+		return nil, nil
+	}
+	return columns, nil
+}
+
+// errStopScan ends a scanExportRange early without reporting an error
+var errStopScan = errors.New("stop scan")
+
+// flatField is one top-level field of a flat JSON object, rendered as a CSV cell
+type flatField struct {
+	name string
+	cell string
+}
+
+// flatFields returns the fields of value in document order if value is a JSON
+// object whose fields are all strings, numbers, booleans or null, and none is
+// named "key". Strings are unquoted, null becomes the empty string, and numbers
+// and booleans keep their JSON literal.
+func flatFields(value json.RawMessage) ([]flatField, bool) {
+	dec := json.NewDecoder(bytes.NewReader(value))
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return nil, false
+	}
+	var fields []flatField
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, false
+		}
+		name, _ := tok.(string)
+		if name == "key" {
+			return nil, false
+		}
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return nil, false
+		}
+		field := flatField{name: name}
+		switch raw[0] {
+		case '{', '[':
+			return nil, false
+		case '"':
+			if err := json.Unmarshal(raw, &field.cell); err != nil {
+				return nil, false
+			}
+		case 'n':
+			// null stays empty
+		default:
+			field.cell = string(raw)
+		}
+		fields = append(fields, field)
+	}
+	return fields, true
+}
+
+// handleBackup implements the 'backup' command.
+// Writes the bytes the database gained after offset --since (0, the default,
+// meaning every row after the initial checksum row) to a new file --out, and
+// prints the offset the delta ends at, to pass as --since to the next backup.
+// The output file must not exist, so an earlier delta is never overwritten.
+func handleBackup(path string, args []string) {
+	since, out, err := parseBackupFlags(args)
+	if err != nil {
+		printError(err)
+	}
+
+	file, err := os.OpenFile(out, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		printError(pkg_frozendb.NewPathError(fmt.Sprintf("failed to create %s", out), err))
+	}
+	until, err := pkg_frozendb.WriteBackupDelta(path, since, file)
+	if closeErr := file.Close(); err == nil && closeErr != nil {
+		err = pkg_frozendb.NewWriteError(fmt.Sprintf("failed to write %s", out), closeErr)
+	}
+	if err != nil {
+		_ = os.Remove(out)
+		printError(err)
+	}
+
+	fmt.Println(until)
+	os.Exit(0)
+}
+
+// handleRestore implements the 'restore' command.
+// Appends the backup delta --apply to the database, which must end where the
+// delta begins, and prints the new size of the file. Rows are validated as they
+// are appended.
+func handleRestore(path string, args []string) {
+	deltaPath, err := parseRestoreFlags(args)
+	if err != nil {
+		printError(err)
+	}
+
+	file, err := os.Open(deltaPath)
+	if err != nil {
+		printError(pkg_frozendb.NewPathError(fmt.Sprintf("failed to open %s", deltaPath), err))
+	}
+	defer func() { _ = file.Close() }()
+
+	size, err := pkg_frozendb.ApplyBackupDelta(path, file)
+	if err != nil {
+		printError(err)
+	}
+
+	fmt.Println(size)
+	os.Exit(0)
+}
+
+// parseBackupFlags parses backup-specific command flags
+func parseBackupFlags(args []string) (since int64, out string, err error) {
+	for i := 0; i < len(args); {
+		if value, consumed, err := flagValue(args, i, "--since"); err != nil {
+			return 0, "", err
+		} else if consumed > 0 {
+			since, err = strconv.ParseInt(value, 10, 64)
+			if err != nil || since < 0 {
+				return 0, "", pkg_frozendb.NewInvalidInputError("--since must be a non-negative byte offset", err)
+			}
+			i += consumed
+			continue
+		}
+		if value, consumed, err := flagValue(args, i, "--out"); err != nil {
+			return 0, "", err
+		} else if consumed > 0 {
+			out = value
+			i += consumed
+			continue
+		}
+		return 0, "", pkg_frozendb.NewInvalidInputError(fmt.Sprintf("unknown flag: %s", args[i]), nil)
+	}
+	if out == "" {
+		return 0, "", pkg_frozendb.NewInvalidInputError("missing required flag: --out", nil)
+	}
+	return since, out, nil
+}
+
+// parseRestoreFlags parses restore-specific command flags
+func parseRestoreFlags(args []string) (string, error) {
+	var deltaPath string
+	for i := 0; i < len(args); {
+		if value, consumed, err := flagValue(args, i, "--apply"); err != nil {
+			return "", err
+		} else if consumed > 0 {
+			deltaPath = value
+			i += consumed
+			continue
+		}
+		return "", pkg_frozendb.NewInvalidInputError(fmt.Sprintf("unknown flag: %s", args[i]), nil)
+	}
+	if deltaPath == "" {
+		return "", pkg_frozendb.NewInvalidInputError("missing required flag: --apply", nil)
+	}
+	return deltaPath, nil
+}
+
+// seedEpoch is the clock seed starts from: the key of sample row i carries the
+// timestamp seedEpoch + i milliseconds.
+var seedEpoch = time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// seedOptions holds the parsed arguments of the seed command.
+type seedOptions struct {
+	rows        int64
+	noImmutable bool // Skip the append-only attribute (and the sudo requirement)
+	force       bool // Replace an existing file at path
+}
+
+// handleSeed implements the 'seed' command.
+// Creates a new database at path, as create does with default settings, and
+// commits --rows sample rows to it in transactions of MAX_BATCH_ENTRIES rows.
+// Keys come from a fixed clock and a fixed random source and values depend only
+// on the row number, so the same arguments always produce a byte-identical file.
+func handleSeed(path string, finderStrategy pkg_frozendb.FinderStrategy, args []string) {
+	opts, err := parseSeedFlags(args)
+	if err != nil {
+		printError(err)
+	}
+
+	config := internal_frozendb.NewCreateConfig(path, defaultRowSize, defaultSkewMs)
+	config.SetNoImmutable(opts.noImmutable)
+	config.SetForce(opts.force)
+	if err := internal_frozendb.Create(config); err != nil {
+		printError(err)
+	}
+	if opts.noImmutable {
+		fmt.Fprintln(os.Stderr, "warning: append-only attribute not set; the operating system does not prevent modifying the file")
+	}
+
+	guard := newWriteGuard()
+	db, err := pkg_frozendb.NewFrozenDB(path, pkg_frozendb.MODE_WRITE, finderStrategy)
+	if err != nil {
+		printError(err)
+	}
+	defer func() { _ = db.Close() }() // Error ignored - exit on errors
+
+	entries := make([]pkg_frozendb.Entry, 0, pkg_frozendb.MAX_BATCH_ENTRIES)
+	random := rand.New(rand.NewPCG(1, 2))
+	// Stop between transactions once a signal arrives; finish then exits
+	var i int64
+	for i < opts.rows && len(guard.signals) == 0 {
+		entries = entries[:0]
+		for ; i < opts.rows && len(entries) < pkg_frozendb.MAX_BATCH_ENTRIES; i++ {
+			value := fmt.Sprintf(`{"id":%d,"name":"sample-%d","even":%t}`, i, i, i%2 == 0)
+			entries = append(entries, pkg_frozendb.Entry{Key: seedKey(seedEpoch.UnixMilli()+i, random), Value: json.RawMessage(value)})
+		}
+		batch, err := db.PrepareBatch(entries...)
+		if err != nil {
+			printError(err)
+		}
+		if err := batch.Commit(); err != nil {
+			printError(err)
+		}
+	}
+
+	guard.finish(db, i == opts.rows)
+
+	// Success: exit silently with code 0 (per FR-005)
+	os.Exit(0)
+}
+
+// seedKey returns a UUIDv7 with timestamp ms and the remaining bits read from
+// random, so a seeded random source yields the same keys on every run.
+func seedKey(ms int64, random *rand.Rand) uuid.UUID {
+	var key uuid.UUID
+	binary.BigEndian.PutUint64(key[8:], random.Uint64())
+	binary.BigEndian.PutUint64(key[:8], uint64(ms)<<16|random.Uint64()&0x0fff)
+	key[6] = key[6]&0x0f | 0x70 // Version 7
+	key[8] = key[8]&0x3f | 0x80 // RFC 4122 variant
+	return key
+}
+
+// parseSeedFlags parses seed-specific command flags
+func parseSeedFlags(args []string) (seedOptions, error) {
+	var opts seedOptions
+	seenRows := false
+	for i := 0; i < len(args); {
+		if value, consumed, err := flagValue(args, i, "--rows"); err != nil {
+			return seedOptions{}, err
+		} else if consumed > 0 {
+			opts.rows, err = strconv.ParseInt(value, 10, 64)
+			if err != nil || opts.rows < 0 {
+				return seedOptions{}, pkg_frozendb.NewInvalidInputError("--rows must be a non-negative number", err)
+			}
+			seenRows = true
+			i += consumed
+			continue
+		}
+		switch args[i] {
+		case "--no-immutable":
+			opts.noImmutable = true
+		case "--force":
+			opts.force = true
+		default:
+			return seedOptions{}, pkg_frozendb.NewInvalidInputError(fmt.Sprintf("unknown flag: %s", args[i]), nil)
+		}
+		i++
+	}
+	if !seenRows {
+		return seedOptions{}, pkg_frozendb.NewInvalidInputError("missing required flag: --rows", nil)
+	}
+	return opts, nil
+}
+
+// reframeOptions holds the parsed flags of the reframe command
+type reframeOptions struct {
+	rowSize     int
+	out         string
+	noImmutable bool // Skip the append-only attribute (and the sudo requirement)
+	force       bool // Replace an existing file at out
+}
+
+// handleReframe implements the 'reframe' command.
+// Rewrites the committed rows of the database at path into a new database at
+// --out with row size --row-size. The new database keeps the source's skew_ms,
+// checksum interval, value compression, metadata, and creation time; checksum rows and max_timestamp are
+// regenerated as the rows are written. Rows are written in ascending key order in
+// transactions of up to MAX_BATCH_ENTRIES rows, starting a new transaction before
+// a repeated key. Rolled back, uncommitted, and deleted rows are not copied.
+// Every value is checked against the new row size before the output is created,
+// so a value that does not fit fails with ValueTooLargeError and writes nothing.
+func handleReframe(path string, finderStrategy pkg_frozendb.FinderStrategy, args []string) {
+	opts, err := parseReframeFlags(args)
+	if err != nil {
+		printError(err)
+	}
+	if srcInfo, err := os.Stat(path); err == nil {
+		if outInfo, err := os.Stat(opts.out); err == nil && os.SameFile(srcInfo, outInfo) {
+			printError(pkg_frozendb.NewInvalidInputError("--out must differ from --path", nil))
+		}
+	}
+
+	header, err := pkg_frozendb.ReadHeader(path)
+	if err != nil {
+		printError(err)
+	}
+	src, err := pkg_frozendb.NewFrozenDB(path, pkg_frozendb.MODE_READ, finderStrategy)
+	if err != nil {
+		printError(err)
+	}
+	defer func() { _ = src.Close() }() // Error ignored - exit on errors
+
+	// Check every value against the new row size before creating the output
+	compression := header.GetValueCompression()
+	maxSize := pkg_frozendb.MaxValueSize(opts.rowSize)
+	var checkErr error
+	err = src.Scan(func(key uuid.UUID, value json.RawMessage) bool {
+		stored, err := internal_frozendb.CompressValue(compression, value)
+		if err != nil {
+			checkErr = err
+			return false
+		}
+		if len(stored) > maxSize {
+			msg := fmt.Sprintf("value of key %s (%d bytes) exceeds the maximum of %d bytes for row size %d", key, len(stored), maxSize, opts.rowSize)
+			checkErr = pkg_frozendb.NewValueTooLargeError(msg, len(stored), maxSize, pkg_frozendb.NewInvalidInputError(msg, nil))
+			return false
+		}
+		return true
+	})
+	if err == nil {
+		err = checkErr
+	}
+	if err != nil {
+		printError(err)
+	}
+
+	config := internal_frozendb.NewCreateConfig(opts.out, opts.rowSize, header.GetSkewMs())
+	config.SetChecksumInterval(header.GetChecksumInterval())
+	config.SetValueCompression(compression)
+	config.SetMetadata(header.GetMetadata())
+	config.SetCreatedAt(header.GetCreatedAt())
+	config.SetNoImmutable(opts.noImmutable)
+	config.SetForce(opts.force)
+	if err := internal_frozendb.Create(config); err != nil {
+		printError(err)
+	}
+	if opts.noImmutable {
+		fmt.Fprintln(os.Stderr, "warning: append-only attribute not set; the operating system does not prevent modifying the file")
+	}
+
+	guard := newWriteGuard()
+	dst, err := pkg_frozendb.NewFrozenDB(opts.out, pkg_frozendb.MODE_WRITE, finderStrategy)
+	if err != nil {
+		printError(err)
+	}
+	defer func() { _ = dst.Close() }() // Error ignored - exit on errors
+
+	entries := make([]pkg_frozendb.Entry, 0, pkg_frozendb.MAX_BATCH_ENTRIES)
+	inBatch := make(map[uuid.UUID]bool, pkg_frozendb.MAX_BATCH_ENTRIES)
+	flush := func() error {
+		if len(entries) == 0 {
+			return nil
+		}
+		batch, err := dst.PrepareBatch(entries...)
+		if err != nil {
+			return err
+		}
+		entries = entries[:0]
+		clear(inBatch)
+		return batch.Commit()
+	}
+	var writeErr error
+	interrupted := false
+	// Stop between transactions once a signal arrives; finish then exits
+	err = src.Scan(func(key uuid.UUID, value json.RawMessage) bool {
+		if len(entries) == pkg_frozendb.MAX_BATCH_ENTRIES || inBatch[key] {
+			if writeErr = flush(); writeErr != nil {
+				return false
+			}
+			if len(guard.signals) > 0 {
+				interrupted = true
+				return false
+			}
+		}
+		entries = append(entries, pkg_frozendb.Entry{Key: key, Value: slices.Clone(value)})
+		inBatch[key] = true
+		return true
+	})
+	if err == nil && writeErr == nil && !interrupted {
+		writeErr = flush()
+	}
+	if err == nil {
+		err = writeErr
+	}
+	if err != nil {
+		printError(err)
+	}
+
+	guard.finish(dst, !interrupted)
+
+	// Success: exit silently with code 0 (per FR-005)
+	os.Exit(0)
+}
+
+// parseReframeFlags parses reframe-specific command flags
+func parseReframeFlags(args []string) (reframeOptions, error) {
+	var opts reframeOptions
+	for i := 0; i < len(args); {
+		if value, consumed, err := flagValue(args, i, "--row-size"); err != nil {
+			return reframeOptions{}, err
+		} else if consumed > 0 {
+			opts.rowSize, err = strconv.Atoi(value)
+			if err != nil || opts.rowSize <= 0 {
+				return reframeOptions{}, pkg_frozendb.NewInvalidInputError("--row-size must be a positive number", err)
+			}
+			i += consumed
+			continue
+		}
+		if value, consumed, err := flagValue(args, i, "--out"); err != nil {
+			return reframeOptions{}, err
+		} else if consumed > 0 {
+			opts.out = value
+			i += consumed
+			continue
+		}
+		switch args[i] {
+		case "--no-immutable":
+			opts.noImmutable = true
+		case "--force":
+			opts.force = true
+		default:
+			return reframeOptions{}, pkg_frozendb.NewInvalidInputError(fmt.Sprintf("unknown flag: %s", args[i]), nil)
+		}
+		i++
+	}
+	if opts.rowSize == 0 {
+		return reframeOptions{}, pkg_frozendb.NewInvalidInputError("missing required flag: --row-size", nil)
+	}
+	if opts.out == "" {
+		return reframeOptions{}, pkg_frozendb.NewInvalidInputError("missing required flag: --out", nil)
+	}
+	return opts, nil
+}
+
+// setSkewOptions holds the parsed flags of the set-skew command
+type setSkewOptions struct {
+	skewMs      int
+	out         string
+	noImmutable bool // Skip the append-only attribute (and the sudo requirement)
+	force       bool // Replace an existing file at out
+}
+
+// handleSetSkew implements the 'set-skew' command.
+// Copies the database at path to a new database at --out whose header records
+// skew_ms --skew-ms; row_size, checksum interval, value compression, metadata, and
+// creation time are kept.
+// Every row after the initial checksum row is copied byte for byte, including
+// rolled back and uncommitted rows, and checksum rows are regenerated for the
+// new header. The source is not modified. A skew_ms smaller than the source's is
+// only accepted if every data row satisfies the ordering rule under it, which is
+// checked before the output is created.
+func handleSetSkew(path string, args []string) {
+	opts, err := parseSetSkewFlags(args)
+	if err != nil {
+		printError(err)
+	}
+	if srcInfo, err := os.Stat(path); err == nil {
+		if outInfo, err := os.Stat(opts.out); err == nil && os.SameFile(srcInfo, outInfo) {
+			printError(pkg_frozendb.NewInvalidInputError("--out must differ from --path", nil))
+		}
+	}
+
+	header, err := pkg_frozendb.ReadHeader(path)
+	if err != nil {
+		printError(err)
+	}
+	if opts.skewMs < header.GetSkewMs() {
+		if err := internal_frozendb.CheckRowOrder(path, opts.skewMs); err != nil {
+			printError(err)
+		}
+	}
+
+	config := internal_frozendb.NewCreateConfig(opts.out, header.GetRowSize(), opts.skewMs)
+	config.SetChecksumInterval(header.GetChecksumInterval())
+	config.SetValueCompression(header.GetValueCompression())
+	config.SetMetadata(header.GetMetadata())
+	config.SetCreatedAt(header.GetCreatedAt())
+	config.SetNoImmutable(opts.noImmutable)
+	config.SetForce(opts.force)
+	if err := internal_frozendb.Create(config); err != nil {
+		printError(err)
+	}
+	if opts.noImmutable {
+		fmt.Fprintln(os.Stderr, "warning: append-only attribute not set; the operating system does not prevent modifying the file")
+	}
+
+	if _, err := internal_frozendb.CopyRows(path, opts.out); err != nil {
+		printError(err)
+	}
+
+	// Success: exit silently with code 0 (per FR-005)
+	os.Exit(0)
+}
+
+// parseSetSkewFlags parses set-skew-specific command flags
+func parseSetSkewFlags(args []string) (setSkewOptions, error) {
+	opts := setSkewOptions{skewMs: -1}
+	for i := 0; i < len(args); {
+		if value, consumed, err := flagValue(args, i, "--skew-ms"); err != nil {
+			return setSkewOptions{}, err
+		} else if consumed > 0 {
+			opts.skewMs, err = strconv.Atoi(value)
+			if err != nil || opts.skewMs < 0 || opts.skewMs > internal_frozendb.MAX_SKEW_MS {
+				return setSkewOptions{}, pkg_frozendb.NewInvalidInputError(
+					fmt.Sprintf("--skew-ms must be a number between 0 and %d", internal_frozendb.MAX_SKEW_MS), err)
+			}
+			i += consumed
+			continue
+		}
+		if value, consumed, err := flagValue(args, i, "--out"); err != nil {
+			return setSkewOptions{}, err
+		} else if consumed > 0 {
+			opts.out = value
+			i += consumed
+			continue
+		}
+		switch args[i] {
+		case "--no-immutable":
+			opts.noImmutable = true
+		case "--force":
+			opts.force = true
+		default:
+			return setSkewOptions{}, pkg_frozendb.NewInvalidInputError(fmt.Sprintf("unknown flag: %s", args[i]), nil)
+		}
+		i++
+	}
+	if opts.skewMs < 0 {
+		return setSkewOptions{}, pkg_frozendb.NewInvalidInputError("missing required flag: --skew-ms", nil)
+	}
+	if opts.out == "" {
+		return setSkewOptions{}, pkg_frozendb.NewInvalidInputError("missing required flag: --out", nil)
+	}
+	return opts, nil
+}
+
+// importLine is one line of a merge-import input file.
+type importLine struct {
+	file  string
+	line  int
+	key   uuid.UUID
+	value json.RawMessage
+}
+
+// handleMergeImport implements the 'merge-import' command.
+// Appends the rows of one or more JSON-Lines files, each line an export-style
+// {"key":...,"value":...} object, to the database in the order given. Every
+// file is validated before anything is written: keys must be strictly ascending
+// within each file and across files, so the files' key ranges are ascending and
+// non-overlapping, and every key must satisfy the ordering rule against the
+// max_timestamp carried forward from the database and the keys before it. The
+// rows are then written in back-to-back transactions of MAX_BATCH_ENTRIES rows,
+// reading the files again one line at a time.
+func handleMergeImport(path string, finderStrategy pkg_frozendb.FinderStrategy, args []string) {
+	if len(args) == 0 {
+		printError(pkg_frozendb.NewInvalidInputError("merge-import requires at least one input file", nil))
+	}
+	for _, arg := range args {
+		if strings.HasPrefix(arg, "--") {
+			printError(pkg_frozendb.NewInvalidInputError(fmt.Sprintf("unknown flag: %s", arg), nil))
+		}
+	}
+
+	header, err := pkg_frozendb.ReadHeader(path)
+	if err != nil {
+		printError(err)
+	}
+	guard := newWriteGuard()
+	db, err := pkg_frozendb.NewFrozenDB(path, pkg_frozendb.MODE_WRITE, finderStrategy)
+	if err != nil {
+		printError(err)
+	}
+	defer func() { _ = db.Close() }() // Error ignored - exit on errors
+
+	if err := validateMergeImport(args, db.MaxTimestamp(), int64(header.GetSkewMs())); err != nil {
+		printError(err)
+	}
+
+	entries := make([]pkg_frozendb.Entry, 0, pkg_frozendb.MAX_BATCH_ENTRIES)
+	commit := func() error {
+		batch, err := db.PrepareBatch(entries...)
+		if err != nil {
+			return err
+		}
+		entries = entries[:0]
+		return batch.Commit()
+	}
+	errInterrupted := errors.New("interrupted")
+	err = readImportLines(args, func(l importLine) error {
+		entries = append(entries, pkg_frozendb.Entry{Key: l.key, Value: l.value})
+		if len(entries) < pkg_frozendb.MAX_BATCH_ENTRIES {
+			return nil
+		}
+		if err := commit(); err != nil {
+			return err
+		}
+		// Stop between transactions once a signal arrives; finish then exits
+		if len(guard.signals) > 0 {
+			return errInterrupted
+		}
+		return nil
+	})
+	if err == nil && len(entries) > 0 {
+		err = commit()
+	}
+	if err != nil && !errors.Is(err, errInterrupted) {
+		printError(err)
+	}
+
+	guard.finish(db, err == nil)
+
+	// Success: exit silently with code 0 (per FR-005)
+	os.Exit(0)
+}
+
+// validateMergeImport reads every line of files and checks that the keys are
+// strictly ascending across all of them, and that each key's timestamp plus
+// skewMs exceeds the largest timestamp before it, starting from maxTimestamp.
+// A violation is a KeyOrderingError naming the file and line of the offending
+// key and of the key it conflicts with.
+func validateMergeImport(files []string, maxTimestamp, skewMs int64) error {
+	var prev importLine
+	return readImportLines(files, func(l importLine) error {
+		if prev.file != "" && bytes.Compare(l.key[:], prev.key[:]) <= 0 {
+			return pkg_frozendb.NewKeyOrderingError(fmt.Sprintf("%s line %d: key %s is not after key %s at %s line %d",
+				l.file, l.line, l.key, prev.key, prev.file, prev.line), nil)
+		}
+		ts := internal_frozendb.ExtractUUIDv7Timestamp(l.key)
+		if ts+skewMs <= maxTimestamp {
+			return pkg_frozendb.NewKeyOrderingError(fmt.Sprintf("%s line %d: key timestamp %d plus skew_ms %d does not exceed max_timestamp %d",
+				l.file, l.line, ts, skewMs, maxTimestamp), nil)
+		}
+		maxTimestamp = max(maxTimestamp, ts)
+		prev = l
+		return nil
+	})
+}
+
+// readImportLines calls fn for every non-empty line of files, in order, parsed
+// as an export-style {"key":...,"value":...} object. A line that does not parse
+// is an InvalidInputError naming its file and line. An error returned by fn stops
+// the read and is returned.
+func readImportLines(files []string, fn func(l importLine) error) error {
+	for _, name := range files {
+		file, err := os.Open(name)
+		if err != nil {
+			return pkg_frozendb.NewPathError(fmt.Sprintf("failed to open input file %s", name), err)
+		}
+		err = readImportFile(name, file, fn)
+		_ = file.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readImportFile calls fn for every non-empty line read from r, as readImportLines.
+func readImportFile(name string, r io.Reader, fn func(l importLine) error) error {
+	reader := bufio.NewReader(r)
+	for line := 1; ; line++ {
+		text, readErr := reader.ReadBytes('\n')
+		if readErr != nil && readErr != io.EOF {
+			return pkg_frozendb.NewReadError(fmt.Sprintf("failed to read %s line %d", name, line), readErr)
+		}
+		if text = bytes.TrimSpace(text); len(text) > 0 {
+			var raw struct {
+				Key   string          `json:"key"`
+				Value json.RawMessage `json:"value"`
+			}
+			if err := json.Unmarshal(text, &raw); err != nil {
+				return pkg_frozendb.NewInvalidInputError(fmt.Sprintf("%s line %d: invalid JSON line", name, line), err)
+			}
+			key, err := validateUUIDv7(raw.Key)
+			if err != nil {
+				return pkg_frozendb.NewInvalidInputError(fmt.Sprintf("%s line %d: invalid key %q", name, line, raw.Key), err)
+			}
+			if len(raw.Value) == 0 {
+				return pkg_frozendb.NewInvalidInputError(fmt.Sprintf("%s line %d: missing value", name, line), nil)
+			}
+			if err := fn(importLine{file: name, line: line, key: key, value: raw.Value}); err != nil {
+				return err
+			}
+		}
+		if readErr == io.EOF {
+			return nil
+		}
+	}
+}
+
+// defaultHeadTailRows is the number of rows head and tail print without N.
+const defaultHeadTailRows = 10
+
+// handleHead implements the 'head' command.
+// Prints the first N committed rows in ascending key order, one export-style
+// {"key":...,"value":...} JSON object per line. The scan stops after N rows.
+func handleHead(path string, finderStrategy pkg_frozendb.FinderStrategy, args []string) {
+	n, err := parseHeadTailArgs(args)
+	if err != nil {
+		printError(err)
+	}
+
+	db, err := pkg_frozendb.NewFrozenDB(path, pkg_frozendb.MODE_READ, finderStrategy)
+	if err != nil {
+		printError(err)
+	}
+	defer func() { _ = db.Close() }()
+
+	var lines []exportLine
+	err = db.Scan(func(key uuid.UUID, value json.RawMessage) bool {
+		lines = append(lines, exportLine{Key: key, Value: value})
+		return len(lines) < n
+	})
+	if err != nil {
+		printError(err)
+	}
+	writeExportLines(lines)
+}
+
+// handleTail implements the 'tail' command.
+// Prints the last N committed rows, oldest first like head. The file is read
+// backward from its end, so only the rows near the tail are read.
+func handleTail(path string, finderStrategy pkg_frozendb.FinderStrategy, args []string) {
+	n, err := parseHeadTailArgs(args)
+	if err != nil {
+		printError(err)
+	}
+
+	db, err := pkg_frozendb.NewFrozenDB(path, pkg_frozendb.MODE_READ, finderStrategy)
+	if err != nil {
+		printError(err)
+	}
+	defer func() { _ = db.Close() }()
+
+	var lines []exportLine
+	err = db.ScanReverse(func(key uuid.UUID, value json.RawMessage) bool {
+		lines = append(lines, exportLine{Key: key, Value: value})
+		return len(lines) < n
+	})
+	if err != nil {
+		printError(err)
+	}
+	slices.Reverse(lines)
+	writeExportLines(lines)
+}
+
+// writeExportLines prints lines as JSON lines and exits with code 0.
+func writeExportLines(lines []exportLine) {
+	out := json.NewEncoder(os.Stdout)
+	for _, line := range lines {
+		if err := out.Encode(line); err != nil {
+			printError(pkg_frozendb.NewWriteError("failed to write output", err))
+		}
+	}
+	os.Exit(0)
+}
+
+// parseHeadTailArgs parses the optional row count of head and tail.
+func parseHeadTailArgs(args []string) (int, error) {
+	if len(args) == 0 {
+		return defaultHeadTailRows, nil
+	}
+	if len(args) > 1 {
+		return 0, pkg_frozendb.NewInvalidInputError(fmt.Sprintf("unexpected argument: %s", args[1]), nil)
+	}
+	n, err := strconv.Atoi(args[0])
+	if err != nil || n <= 0 {
+		return 0, pkg_frozendb.NewInvalidInputError(fmt.Sprintf("row count must be a positive integer, got %q", args[0]), err)
+	}
+	return n, nil
+}
+
+// parseExportFlags parses export-specific command flags
+func parseExportFlags(args []string) (exportOptions, error) {
+	var opts exportOptions
+	i := 0
+	for i < len(args) {
+		arg := args[i]
+
+		if value, consumed, err := flagValue(args, i, "--after"); err != nil {
+			return exportOptions{}, err
+		} else if consumed > 0 {
+			key, err := validateUUIDv7(value)
+			if err != nil {
+				return exportOptions{}, pkg_frozendb.NewInvalidInputError("--after must be a UUIDv7 key", err)
+			}
+			opts.after = key
+			i += consumed
+			continue
+		}
+
+		if value, consumed, err := flagValue(args, i, "--before"); err != nil {
+			return exportOptions{}, err
+		} else if consumed > 0 {
+			key, err := validateUUIDv7(value)
+			if err != nil {
+				return exportOptions{}, pkg_frozendb.NewInvalidInputError("--before must be a UUIDv7 key", err)
+			}
+			opts.before = key
+			i += consumed
+			continue
+		}
+
+		if value, consumed, err := flagValue(args, i, "--format"); err != nil {
+			return exportOptions{}, err
+		} else if consumed > 0 {
+			if value != exportFormatJSONL && value != exportFormatCSV {
+				return exportOptions{}, pkg_frozendb.NewInvalidInputError(fmt.Sprintf("--format must be %s or %s, got %q", exportFormatJSONL, exportFormatCSV, value), nil)
+			}
+			opts.format = value
+			i += consumed
+			continue
+		}
+
+		if arg == "--count-only" {
+			opts.countOnly = true
+			i++
+			continue
+		}
+
+		if arg == "--flatten" {
+			opts.flatten = true
+			i++
+			continue
+		}
+
+		return exportOptions{}, pkg_frozendb.NewInvalidInputError(fmt.Sprintf("unknown flag: %s", arg), nil)
+	}
+	if opts.format == "" {
+		opts.format = exportFormatJSONL
+	}
+	if opts.flatten && opts.format != exportFormatCSV {
+		return exportOptions{}, pkg_frozendb.NewInvalidInputError("--flatten requires --format csv", nil)
+	}
+	return opts, nil
+}
+
+// parseDiffFlags parses diff-specific command flags
+func parseDiffFlags(args []string) (pathA string, pathB string, verbose bool, err error) {
+	i := 0
+	for i < len(args) {
+		arg := args[i]
+
+		if arg == "--a" || arg == "--b" {
+			if i+1 >= len(args) {
+				return "", "", false, pkg_frozendb.NewInvalidInputError(fmt.Sprintf("%s requires a value", arg), nil)
+			}
+			target := &pathA
+			if arg == "--b" {
+				target = &pathB
+			}
+			if *target != "" {
+				return "", "", false, pkg_frozendb.NewInvalidInputError(fmt.Sprintf("duplicate flag: %s", arg), nil)
+			}
+			*target = args[i+1]
+			i += 2
+			continue
+		}
+
+		if arg == "--verbose" {
+			verbose = true
+			i++
+			continue
+		}
+
+		return "", "", false, pkg_frozendb.NewInvalidInputError(fmt.Sprintf("unknown flag: %s", arg), nil)
+	}
+
+	if pathA == "" {
+		return "", "", false, pkg_frozendb.NewInvalidInputError("missing required flag: --a", nil)
+	}
+	if pathB == "" {
+		return "", "", false, pkg_frozendb.NewInvalidInputError("missing required flag: --b", nil)
+	}
+	return pathA, pathB, verbose, nil
+}
+
+// diffSummary counts the differences found by diffDatabases
+type diffSummary struct {
+	onlyInA int
+	onlyInB int
+	differs int
+}
+
+// diffDatabases merges the key-ordered committed rows of a and b, calling report
+// with "only-in-a", "only-in-b" or "differs" for every key that does not match.
+// Values are compared byte for byte as stored. Memory use is independent of
+// database size since both sides are consumed as streams.
+func diffDatabases(a, b *pkg_frozendb.FrozenDB, report func(kind string, key uuid.UUID)) (diffSummary, error) {
+	var summary diffSummary
+	var errA, errB error
+
+	nextA, stopA := iter.Pull2(committedRows(a, &errA))
+	defer stopA()
+	nextB, stopB := iter.Pull2(committedRows(b, &errB))
+	defer stopB()
+
+	keyA, valueA, okA := nextA()
+	keyB, valueB, okB := nextB()
+	for okA || okB {
+		cmp := 0
+		switch {
+		case !okB:
+			cmp = -1
+		case !okA:
+			cmp = 1
+		default:
+			cmp = bytes.Compare(keyA[:], keyB[:])
+		}
+
+		switch {
+		case cmp < 0:
+			summary.onlyInA++
+			report("only-in-a", keyA)
+			keyA, valueA, okA = nextA()
+		case cmp > 0:
+			summary.onlyInB++
+			report("only-in-b", keyB)
+			keyB, valueB, okB = nextB()
+		default:
+			if !bytes.Equal(valueA, valueB) {
+				summary.differs++
+				report("differs", keyA)
+			}
+			keyA, valueA, okA = nextA()
+			keyB, valueB, okB = nextB()
+		}
+	}
+
+	if errA != nil {
+		return summary, errA
+	}
+	return summary, errB
+}
+
+// committedRows adapts db.Scan to an iterator. Any scan error is stored in
+// *scanErr once the iterator is exhausted.
+func committedRows(db *pkg_frozendb.FrozenDB, scanErr *error) iter.Seq2[uuid.UUID, json.RawMessage] {
+	return func(yield func(uuid.UUID, json.RawMessage) bool) {
+		*scanErr = db.Scan(yield)
+	}
+}
+
+// inspectOptions holds the parsed flags of the 'inspect' command
+type inspectOptions struct {
+	offset      int64 // First row index to display
+	limit       int64 // Maximum rows to display (-1 for all remaining rows)
+	printHeader bool  // Print the header table before the rows
+	showTime    bool  // Append a key_time column decoded from UUIDv7 keys
+	follow      bool  // Keep printing rows appended after reaching the end of the file
+	raw         bool  // Print values exactly as stored instead of escaping non-printable bytes
+
+	rowType       string        // Print only rows of this type (one of inspectRowTypes; "" for all)
+	committedOnly bool          // Hide data and partial rows not left visible by their transaction
+	visibility    *txVisibility // Transaction outcome cache for committedOnly
+
+	compression internal_frozendb.ValueCompression // From the header: decompress values and print their sizes
+}
+
+// parseInspectFlags parses inspect-specific command flags
+func parseInspectFlags(args []string) (inspectOptions, error) {
+	// Set defaults
+	opts := inspectOptions{
+		offset: 0,
+		limit:  -1,
+	}
+
+	// Parse flags
+	i := 0
+	for i < len(args) {
+		arg := args[i]
+
+		if value, consumed, err := flagValue(args, i, "--offset"); err != nil {
+			return inspectOptions{}, err
+		} else if consumed > 0 {
+			val, parseErr := strconv.ParseInt(value, 10, 64)
+			if parseErr != nil {
+				return inspectOptions{}, pkg_frozendb.NewInvalidInputError("--offset must be a number", parseErr)
+			}
+			opts.offset = val
+			i += consumed
+			continue
+		}
+
+		if value, consumed, err := flagValue(args, i, "--limit"); err != nil {
+			return inspectOptions{}, err
+		} else if consumed > 0 {
+			val, parseErr := strconv.ParseInt(value, 10, 64)
+			if parseErr != nil {
+				return inspectOptions{}, pkg_frozendb.NewInvalidInputError("--limit must be a number", parseErr)
+			}
+			if val == 0 {
+				return inspectOptions{}, pkg_frozendb.NewInvalidInputError("--limit 0 would print no rows; use -1 for all rows", nil)
+			}
+			opts.limit = val
+			i += consumed
+			continue
+		}
+
+		if value, consumed, err := flagValue(args, i, "--print-header"); err != nil {
+			return inspectOptions{}, err
+		} else if consumed > 0 {
+			switch strings.ToLower(value) {
+			case "true", "t", "1":
+				opts.printHeader = true
+			case "false", "f", "0":
+				opts.printHeader = false
+			default:
+				return inspectOptions{}, pkg_frozendb.NewInvalidInputError("--print-header must be true or false", nil)
+			}
+			i += consumed
+			continue
+		}
+
+		if arg == "--show-time" {
+			opts.showTime = true
+			i++
+			continue
+		}
+
+		if arg == "--follow" {
+			opts.follow = true
+			i++
+			continue
+		}
+
+		if arg == "--raw" {
+			opts.raw = true
+			i++
+			continue
+		}
+
+		if value, consumed, err := flagValue(args, i, "--type"); err != nil {
+			return inspectOptions{}, err
+		} else if consumed > 0 {
+			if !slices.ContainsFunc(inspectRowTypes, func(t string) bool { return strings.EqualFold(t, value) }) {
+				return inspectOptions{}, pkg_frozendb.NewInvalidInputError(fmt.Sprintf("--type must be one of %s", strings.Join(inspectRowTypes, ", ")), nil)
+			}
+			opts.rowType = value
+			i += consumed
+			continue
+		}
+
+		if arg == "--committed-only" {
+			opts.committedOnly = true
+			opts.visibility = &txVisibility{}
+			i++
+			continue
+		}
+
+		// Unknown flag
+		return inspectOptions{}, pkg_frozendb.NewInvalidInputError(fmt.Sprintf("unknown flag: %s", arg), nil)
+	}
+
+	return opts, nil
+}
+
+// inspectRowTypes are the values of InspectRow.Type, accepted by inspect --type
+// in any case.
+var inspectRowTypes = []string{"Data", "NullRow", "Checksum", "partial", "error"}
+
+// txVisibility decides for inspect --committed-only whether a data row is left
+// visible by its transaction, remembering the last transaction resolved so its
+// rows are read again only once.
+type txVisibility struct {
+	start, end  int64 // Rows of the cached transaction (end is 0 when nothing is cached)
+	lastVisible int64 // Last row the transaction leaves visible (start-1 if none)
+}
+
+// visible reports whether row is committed. Rows other than data and partial rows
+// are always shown. A data row is visible if its transaction committed it; rows
+// of a transaction that is still open, or whose boundaries cannot be read because
+// of a corrupt row, are not.
+func (v *txVisibility) visible(file internal_frozendb.DBFile, row InspectRow, rowSize int64) bool {
+	switch row.Type {
+	case "partial":
+		return false
+	case "Data":
+	default:
+		return true
+	}
+	if v.end == 0 || row.Index < v.start || row.Index > v.end {
+		if !v.resolve(file, row.Index, rowSize) {
+			return false
+		}
+	}
+	return row.Index <= v.lastVisible
+}
+
+// resolve finds the transaction holding the data row at index and caches how it
+// ended, returning false if the transaction is open or cannot be read.
+func (v *txVisibility) resolve(file internal_frozendb.DBFile, index, rowSize int64) bool {
+	// Walk back to the row that started the transaction
+	start := index
+	for ; ; start-- {
+		startControl, _, ok := readInspectControls(file, start, rowSize)
+		if !ok || start == 0 {
+			return false
+		}
+		if startControl == internal_frozendb.START_TRANSACTION {
+			break
+		}
+	}
+
+	// Walk forward to the row that ended it, counting savepoints
+	var savepoints []int64
+	rowCount := completeRowCount(file.Size(), rowSize)
+	for end := index; end < rowCount; end++ {
+		startControl, endControl, ok := readInspectControls(file, end, rowSize)
+		if !ok {
+			return false
+		}
+		if startControl == internal_frozendb.CHECKSUM_ROW {
+			continue
+		}
+		if end > index && startControl == internal_frozendb.START_TRANSACTION {
+			return false // Next transaction began without this one ending
+		}
+		if endControl[0] == 'S' {
+			savepoints = append(savepoints, end)
+		}
+		if endControl[1] == 'E' {
+			continue
+		}
+
+		v.start, v.end = start, end
+		switch n := endControl[1]; {
+		case n == 'C':
+			v.lastVisible = end
+		case n >= '1' && n <= '9' && int(n-'0') <= len(savepoints):
+			v.lastVisible = savepoints[n-'1']
+		default:
+			v.lastVisible = start - 1
+		}
+		return true
+	}
+	return false // Still open
+}
+
+// readInspectControls returns the control bytes of the complete row at index.
+// Checksum rows report CHECKSUM_ROW; ok is false for a NullRow, which cannot be
+// part of a data transaction, and for a row that does not parse.
+func readInspectControls(file internal_frozendb.DBFile, index, rowSize int64) (internal_frozendb.StartControl, internal_frozendb.EndControl, bool) {
+	rowBytes, err := file.Read(internal_frozendb.HEADER_SIZE+index*rowSize, int32(rowSize))
+	if err != nil {
+		return 0, internal_frozendb.EndControl{}, false
+	}
+	ru := &internal_frozendb.RowUnion{}
+	if err := ru.UnmarshalText(rowBytes); err != nil {
+		return 0, internal_frozendb.EndControl{}, false
+	}
+	switch {
+	case ru.ChecksumRow != nil:
+		return internal_frozendb.CHECKSUM_ROW, internal_frozendb.EndControl{}, true
+	case ru.DataRow != nil:
+		return ru.DataRow.StartControl, ru.DataRow.EndControl, true
+	}
+	return 0, internal_frozendb.EndControl{}, false
+}
+
+// printHeaderTable prints the database header information table. A Value
+// Compression column is added only for databases with compressed values, a
+// Created At column only for databases that recorded their creation time, and a
+// Metadata column, holding the metadata as a JSON object, only for databases
+// created with metadata.
+func printHeaderTable(header *internal_frozendb.Header) {
+	names := []string{"Row Size", "Clock Skew", "File Version"}
+	values := []string{strconv.Itoa(header.GetRowSize()), strconv.Itoa(header.GetSkewMs()), strconv.Itoa(header.GetVersion())}
+	if compression := header.GetValueCompression(); compression != internal_frozendb.ValueCompressionNone {
+		names = append(names, "Value Compression")
+		values = append(values, string(compression))
+	}
+	if createdAt := header.GetCreatedAt(); !createdAt.IsZero() {
+		names = append(names, "Created At")
+		values = append(values, createdAt.Format(time.RFC3339Nano))
+	}
+	if metadata := header.GetMetadata(); metadata != nil {
+		encoded, _ := json.Marshal(metadata) // Error ignored - a map of strings always encodes
+		names = append(names, "Metadata")
+		values = append(values, string(encoded))
+	}
+	fmt.Println(strings.Join(names, "\t"))
+	fmt.Println(strings.Join(values, "\t"))
+	fmt.Println() // Blank line separator
+}
+
+// printRowTableHeader prints the row data table column headers
+func printRowTableHeader(opts inspectOptions) {
+	fmt.Printf("index\ttype\tkey\tvalue\tsavepoint\ttx start\ttx end\trollback\tparity")
+	if opts.showTime {
+		fmt.Printf("\tkey_time")
+	}
+	if opts.compression != internal_frozendb.ValueCompressionNone {
+		fmt.Printf("\tstored_size\tvalue_size")
+	}
+	fmt.Println()
+}
+
+// InspectRow represents a single row for display
+type InspectRow struct {
+	Index     int64
+	Type      string
+	Key       string
+	Value     string
+	Savepoint string
+	TxStart   string
+	TxEnd     string
+	Rollback  string
+	Parity    string
+	KeyTime   string // RFC3339Nano time decoded from the UUIDv7 key (blank when there is no key)
+
+	StoredSize string // Bytes of the value as stored, for databases with compressed values
+	ValueSize  string // Bytes of the decompressed value, for databases with compressed values
+}
+
+// printInspectRow prints a single row in TSV format
+func printInspectRow(row InspectRow, opts inspectOptions) {
+	fmt.Printf("%d\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s",
+		row.Index, row.Type, row.Key, row.Value,
+		row.Savepoint, row.TxStart, row.TxEnd, row.Rollback, row.Parity)
+	if opts.showTime {
+		fmt.Printf("\t%s", row.KeyTime)
+	}
+	if opts.compression != internal_frozendb.ValueCompressionNone {
+		fmt.Printf("\t%s\t%s", row.StoredSize, row.ValueSize)
+	}
+	fmt.Println()
+}
+
+// decompressInspectValue replaces the stored value of a data row, or of a partial
+// row that has its payload, with the decompressed value and records both sizes.
+// A value that does not decompress is left as stored with a blank value_size.
+func decompressInspectValue(row *InspectRow, compression internal_frozendb.ValueCompression) {
+	if row.Value == "" || (row.Type != "Data" && row.Type != "partial") {
+		return
+	}
+	row.StoredSize = strconv.Itoa(len(row.Value))
+	value, err := internal_frozendb.DecompressValue(compression, json.RawMessage(row.Value))
+	if err != nil {
+		return
+	}
+	row.Value = string(value)
+	row.ValueSize = strconv.Itoa(len(value))
+}
+
+// escapeInspectValue makes a value safe for the TSV value column. Control
+// characters (including the tab and newline JSON allows as whitespace) and bytes
+// that are not valid UTF-8 are written as \xNN, or \uNNNN for C1 controls.
+// Backslashes already in the value, such as those of JSON string escapes, are
+// left as they are, so the column is for reading rather than for decoding back
+// to the stored bytes; get --base64 retrieves a value exactly.
+func escapeInspectValue(value string) string {
+	var b strings.Builder
+	for i := 0; i < len(value); {
+		r, size := utf8.DecodeRuneInString(value[i:])
+		switch {
+		case r == utf8.RuneError && size == 1:
+			fmt.Fprintf(&b, "\\x%02x", value[i])
+		case unicode.IsControl(r) && r < utf8.RuneSelf:
+			fmt.Fprintf(&b, "\\x%02x", r)
+		case unicode.IsControl(r):
+			fmt.Fprintf(&b, "\\u%04x", r)
+		default:
+			b.WriteString(value[i : i+size])
+		}
+		i += size
+	}
+	return b.String()
+}
+
+// formatKeyTime formats the timestamp encoded in a UUIDv7 key for the key_time column.
+// uuid.Nil yields a blank value.
+func formatKeyTime(key uuid.UUID) string {
+	if key == uuid.Nil {
+		return ""
+	}
+	return pkg_frozendb.TimestampOf(key).Format(time.RFC3339Nano)
+}
+
+// readAndParseRow reads and parses a single row from the database
+func readAndParseRow(file internal_frozendb.DBFile, index int64, rowSize int) (InspectRow, error) {
+	// Calculate offset: 64 (header) + index * rowSize
+	offset := int64(64) + index*int64(rowSize)
+
+	// Read row bytes
+	rowBytes, err := file.Read(offset, int32(rowSize))
+	if err != nil {
+		// Check if this is a partial row at end of file
+		var pastEnd *pkg_frozendb.ReadPastEndError
+		if errors.As(err, &pastEnd) {
+			// Try to read remaining bytes
+			remaining := pastEnd.FileSize - offset
+			if remaining > 0 && remaining < int64(rowSize) {
+				partialBytes, readErr := file.Read(offset, int32(remaining))
+				if readErr == nil {
+					return parsePartialRow(index, partialBytes, rowSize)
+				}
+			}
+		}
+		// Return error row
 		return InspectRow{
 			Index: index,
 			Type:  "error",
@@ -702,6 +2626,7 @@ func readAndParseRow(file internal_frozendb.DBFile, index int64, rowSize int) (I
 			TxEnd:     "true",
 			Rollback:  "false",
 			Parity:    parity,
+			KeyTime:   formatKeyTime(ru.NullRow.RowPayload.Key),
 		}, nil
 	}
 
@@ -722,6 +2647,7 @@ func readAndParseRow(file internal_frozendb.DBFile, index int64, rowSize int) (I
 			TxEnd:     txEnd,
 			Rollback:  rollback,
 			Parity:    parity,
+			KeyTime:   formatKeyTime(payload.Key),
 		}, nil
 	}
 
@@ -736,6 +2662,7 @@ func readAndParseRow(file internal_frozendb.DBFile, index int64, rowSize int) (I
 			TxEnd:     "true",
 			Rollback:  "false",
 			Parity:    parity,
+			KeyTime:   formatKeyTime(ru.NullRow.RowPayload.Key),
 		}, nil
 	}
 
@@ -756,6 +2683,7 @@ func readAndParseRow(file internal_frozendb.DBFile, index int64, rowSize int) (I
 			TxEnd:     txEnd,
 			Rollback:  rollback,
 			Parity:    parity,
+			KeyTime:   formatKeyTime(payload.Key),
 		}, nil
 	}
 
@@ -783,19 +2711,22 @@ func parsePartialRow(index int64, rowBytes []byte, fullRowSize int) (InspectRow,
 		Type:  "partial",
 	}
 
-	state := partial.GetState()
-
-	// For PartialDataRow, we need to access the internal DataRow fields
-	// Since these are not exported, we can only determine state-based information
-
-	// We can't directly access the internal fields of PartialDataRow
-	// So we'll just show the state-based information we have
-
 	// State 1: Only start_control available
 	// State 2: start_control + payload available
 	// State 3: start_control + payload + savepoint marker available
-
-	if state == 3 { // PartialDataRowWithSavepoint
+	if partial.GetStartControl() == internal_frozendb.START_TRANSACTION {
+		row.TxStart = "true"
+	} else {
+		row.TxStart = "false"
+	}
+	if key, ok := partial.GetKey(); ok {
+		row.Key = key.String()
+		row.KeyTime = formatKeyTime(key)
+	}
+	if value, ok := partial.GetValue(); ok {
+		row.Value = string(value)
+	}
+	if partial.GetState() == internal_frozendb.PartialDataRowWithSavepoint {
 		row.Savepoint = "true"
 	}
 
@@ -844,3 +2775,243 @@ func extractTransactionFields(startControl internal_frozendb.StartControl, endCo
 
 	return
 }
+
+// handleDecode implements the 'decode' command.
+// Reads the row at --index and prints each of its fields with a plain-English
+// explanation of the control bytes, followed by parity and framing checks.
+// Exits with code 1 if the row is not valid.
+func handleDecode(path string, args []string) {
+	index, err := parseDecodeFlags(args)
+	if err != nil {
+		printError(err)
+	}
+
+	file, err := internal_frozendb.NewDBFile(path, internal_frozendb.MODE_READ)
+	if err != nil {
+		printError(err)
+	}
+	defer func() { _ = file.Close() }()
+
+	headerBytes, err := file.Read(0, internal_frozendb.HEADER_SIZE)
+	if err != nil {
+		printError(err)
+	}
+	header := &internal_frozendb.Header{}
+	if err := header.UnmarshalText(headerBytes); err != nil {
+		printError(err)
+	}
+
+	rowSize := int64(header.GetRowSize())
+	offset := internal_frozendb.HEADER_SIZE + index*rowSize
+	if offset >= file.Size() {
+		rowCount := (file.Size() - internal_frozendb.HEADER_SIZE + rowSize - 1) / rowSize
+		printError(pkg_frozendb.NewInvalidInputError(fmt.Sprintf("row index %d out of range [0, %d)", index, rowCount), nil))
+	}
+	rowBytes, err := file.Read(offset, int32(min(rowSize, file.Size()-offset)))
+	if err != nil {
+		printError(err)
+	}
+
+	lines, valid := decodeRow(rowBytes, int(rowSize))
+	fmt.Printf("index: %d\noffset: %d\n", index, offset)
+	for _, line := range lines {
+		fmt.Println(line)
+	}
+	if !valid {
+		os.Exit(1)
+	}
+	os.Exit(0)
+}
+
+// parseDecodeFlags parses decode-specific command flags; --index is required
+func parseDecodeFlags(args []string) (int64, error) {
+	index := int64(-1)
+	for i := 0; i < len(args); {
+		value, consumed, err := flagValue(args, i, "--index")
+		if err != nil {
+			return 0, err
+		}
+		if consumed == 0 {
+			return 0, pkg_frozendb.NewInvalidInputError(fmt.Sprintf("unknown flag: %s", args[i]), nil)
+		}
+		index, err = strconv.ParseInt(value, 10, 64)
+		if err != nil || index < 0 {
+			return 0, pkg_frozendb.NewInvalidInputError("--index must be a non-negative number", err)
+		}
+		i += consumed
+	}
+	if index < 0 {
+		return 0, pkg_frozendb.NewInvalidInputError("missing required flag: --index", nil)
+	}
+	return index, nil
+}
+
+// decodeRow explains the raw bytes of one row as "name: value" lines. Parity and
+// framing are checked on the raw bytes, so they are reported even when the row
+// does not parse. rowBytes shorter than rowSize is decoded as a partial row.
+// valid reports whether the row parsed and passed every check.
+func decodeRow(rowBytes []byte, rowSize int) (lines []string, valid bool) {
+	if len(rowBytes) < rowSize {
+		return decodePartialRow(rowBytes, rowSize)
+	}
+
+	var row InspectRow
+	var parseErr error
+	var nullRow bool
+	ru := &internal_frozendb.RowUnion{}
+	if parseErr = ru.UnmarshalText(rowBytes); parseErr == nil {
+		switch {
+		case ru.ChecksumRow != nil:
+			value, _ := ru.ChecksumRow.RowPayload.MarshalText()
+			row = InspectRow{Type: "ChecksumRow", Value: string(value)}
+		case ru.NullRow != nil:
+			key := ru.NullRow.RowPayload.Key
+			row = InspectRow{Type: "NullRow", Key: key.String(), KeyTime: formatKeyTime(key)}
+			nullRow = true
+		case ru.DataRow != nil:
+			key := ru.DataRow.RowPayload.Key
+			row = InspectRow{Type: "DataRow", Key: key.String(), Value: string(ru.DataRow.RowPayload.Value), KeyTime: formatKeyTime(key)}
+		}
+	}
+
+	startControl := internal_frozendb.StartControl(rowBytes[1])
+	endControl := internal_frozendb.EndControl{rowBytes[rowSize-5], rowBytes[rowSize-4]}
+	row.Savepoint, row.TxStart, row.TxEnd, row.Rollback = extractTransactionFields(startControl, endControl)
+	if nullRow {
+		// A NullRow is a whole transaction, as inspect shows it
+		row.TxEnd = "true"
+	}
+
+	if row.Type == "" {
+		row.Type = "unknown (row does not parse)"
+	}
+	lines = append(lines,
+		"type: "+row.Type,
+		fmt.Sprintf("start_control: %s", describeStartControl(startControl)),
+		fmt.Sprintf("end_control: %s", describeEndControl(endControl)),
+	)
+	if startControl != internal_frozendb.CHECKSUM_ROW {
+		lines = append(lines, fmt.Sprintf("transaction: savepoint=%s tx_start=%s tx_end=%s rollback=%s", row.Savepoint, row.TxStart, row.TxEnd, row.Rollback))
+	}
+	if row.Key != "" {
+		lines = append(lines, "key: "+row.Key, "key_time: "+row.KeyTime)
+	}
+	if row.Value != "" {
+		name := "value"
+		if ru.ChecksumRow != nil {
+			name = "crc32"
+		}
+		lines = append(lines, name+": "+escapeInspectValue(row.Value))
+	}
+
+	// Parity is the XOR of bytes [0, N-4], written as two uppercase hex digits
+	var xor byte
+	for _, b := range rowBytes[:rowSize-3] {
+		xor ^= b
+	}
+	stored := string(rowBytes[rowSize-3 : rowSize-1])
+	parityOK := stored == fmt.Sprintf("%02X", xor)
+	if parityOK {
+		lines = append(lines, fmt.Sprintf("parity: ok (%s)", stored))
+	} else {
+		lines = append(lines, fmt.Sprintf("parity: bad (stored %s, computed %02X)", escapeInspectValue(stored), xor))
+	}
+
+	framing := decodeFraming(rowBytes[0], rowBytes[rowSize-1], true)
+	lines = append(lines, "framing: "+framing)
+
+	if parseErr != nil {
+		lines = append(lines, "error: "+parseErr.Error())
+	}
+	return lines, parseErr == nil && parityOK && framing == "ok"
+}
+
+// decodePartialRow explains a row that has not been completely written.
+func decodePartialRow(rowBytes []byte, rowSize int) (lines []string, valid bool) {
+	lines = append(lines, fmt.Sprintf("type: partial (%d of %d bytes written)", len(rowBytes), rowSize))
+	if len(rowBytes) > 1 {
+		lines = append(lines, "start_control: "+describeStartControl(internal_frozendb.StartControl(rowBytes[1])))
+	}
+
+	partial := &internal_frozendb.PartialDataRow{}
+	if err := partial.UnmarshalText(rowBytes); err != nil {
+		if len(rowBytes) > 0 {
+			lines = append(lines, "framing: "+decodeFraming(rowBytes[0], 0, false))
+		}
+		return append(lines, "error: "+err.Error()), false
+	}
+	if key, ok := partial.GetKey(); ok {
+		lines = append(lines, "key: "+key.String(), "key_time: "+formatKeyTime(key))
+	}
+	if value, ok := partial.GetValue(); ok {
+		lines = append(lines, "value: "+escapeInspectValue(string(value)))
+	}
+	if partial.GetState() == internal_frozendb.PartialDataRowWithSavepoint {
+		lines = append(lines, "end_control: S (savepoint; the second byte is not written yet)")
+	} else {
+		lines = append(lines, "end_control: not written yet (transaction in progress)")
+	}
+	return append(lines, "framing: ok (ROW_START present; ROW_END not written yet)"), true
+}
+
+// decodeFraming checks the ROW_START byte and, when hasEnd is set, the ROW_END byte.
+func decodeFraming(start, end byte, hasEnd bool) string {
+	var problems []string
+	if start != internal_frozendb.ROW_START {
+		problems = append(problems, fmt.Sprintf("ROW_START is 0x%02X, want 0x%02X", start, internal_frozendb.ROW_START))
+	}
+	if hasEnd && end != internal_frozendb.ROW_END {
+		problems = append(problems, fmt.Sprintf("ROW_END is 0x%02X, want 0x%02X", end, internal_frozendb.ROW_END))
+	}
+	if len(problems) == 0 {
+		return "ok"
+	}
+	return "bad (" + strings.Join(problems, "; ") + ")"
+}
+
+// describeStartControl explains a start_control byte.
+func describeStartControl(startControl internal_frozendb.StartControl) string {
+	switch startControl {
+	case internal_frozendb.START_TRANSACTION:
+		return "T (transaction start)"
+	case internal_frozendb.ROW_CONTINUE:
+		return "R (row continue: another row of the open transaction)"
+	case internal_frozendb.CHECKSUM_ROW:
+		return "C (checksum row)"
+	}
+	return fmt.Sprintf("%s (unknown)", escapeInspectValue(string(startControl)))
+}
+
+// describeEndControl explains an end_control pair: RE, SE, TC, SC, NR, CS,
+// R0-R9, or S0-S9.
+func describeEndControl(endControl internal_frozendb.EndControl) string {
+	code := escapeInspectValue(string(endControl[:]))
+	first, second := endControl[0], endControl[1]
+	savepoint := ""
+	if first == 'S' {
+		savepoint = "savepoint, then "
+	}
+	switch endControl {
+	case internal_frozendb.NULL_ROW_CONTROL:
+		return code + " (null row: an empty transaction, starting and ending here)"
+	case internal_frozendb.CHECKSUM_ROW_CONTROL:
+		return code + " (checksum row end)"
+	case internal_frozendb.TRANSACTION_COMMIT:
+		return code + " (commit: the transaction ends and all its rows are visible)"
+	case internal_frozendb.SAVEPOINT_COMMIT:
+		return code + " (savepoint, then commit: the transaction ends and all its rows are visible)"
+	}
+	if first != 'R' && first != 'S' {
+		return code + " (unknown)"
+	}
+	switch {
+	case second == 'E':
+		return code + " (" + savepoint + "row end: the transaction continues)"
+	case second == '0':
+		return code + " (" + savepoint + "full rollback: the transaction ends and none of its rows are visible)"
+	case second >= '1' && second <= '9':
+		return fmt.Sprintf("%s (%srollback to savepoint %c: the transaction ends and only rows up to savepoint %c are visible)",
+			code, savepoint, second, second)
+	}
+	return code + " (unknown)"
+}
@@ -0,0 +1,1737 @@
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	internal_frozendb "github.com/susu-dot-dev/frozenDB/internal/frozendb"
+	pkg_frozendb "github.com/susu-dot-dev/frozenDB/pkg/frozendb"
+)
+
+// runCLI runs the CLI binary and returns stdout, stderr and the exit code
+func runCLI(t *testing.T, binaryPath string, args ...string) (string, string, int) {
+	t.Helper()
+	cmd := exec.Command(binaryPath, args...)
+	var stdout, stderr strings.Builder
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+	exitCode := 0
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		exitCode = exitErr.ExitCode()
+	} else if err != nil {
+		t.Fatalf("Failed to run CLI: %v", err)
+	}
+	return stdout.String(), stderr.String(), exitCode
+}
+
+func TestParseDiffFlags(t *testing.T) {
+	tests := []struct {
+		name        string
+		args        []string
+		wantA       string
+		wantB       string
+		wantVerbose bool
+		wantErr     bool
+	}{
+		{name: "both paths", args: []string{"--a", "a.fdb", "--b", "b.fdb"}, wantA: "a.fdb", wantB: "b.fdb"},
+		{name: "verbose first", args: []string{"--verbose", "--b", "b.fdb", "--a", "a.fdb"}, wantA: "a.fdb", wantB: "b.fdb", wantVerbose: true},
+		{name: "missing a", args: []string{"--b", "b.fdb"}, wantErr: true},
+		{name: "missing b", args: []string{"--a", "a.fdb"}, wantErr: true},
+		{name: "a without value", args: []string{"--b", "b.fdb", "--a"}, wantErr: true},
+		{name: "duplicate a", args: []string{"--a", "a.fdb", "--a", "c.fdb", "--b", "b.fdb"}, wantErr: true},
+		{name: "unknown flag", args: []string{"--a", "a.fdb", "--b", "b.fdb", "--color"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, b, verbose, err := parseDiffFlags(tt.args)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got a=%q b=%q", a, b)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if a != tt.wantA || b != tt.wantB || verbose != tt.wantVerbose {
+				t.Errorf("got (%q, %q, %v), want (%q, %q, %v)", a, b, verbose, tt.wantA, tt.wantB, tt.wantVerbose)
+			}
+		})
+	}
+}
+
+func TestDiff_IdenticalDatabases(t *testing.T) {
+	binaryPath := buildCLIBinary(t)
+	dbA := createTestDatabase(t, binaryPath)
+	dbB := createTestDatabase(t, binaryPath)
+
+	stdout, stderr, exitCode := runCLI(t, binaryPath, "diff", "--a", dbA, "--b", dbB, "--verbose")
+	if exitCode != 0 {
+		t.Fatalf("Expected exit code 0, got %d. Stderr: %s", exitCode, stderr)
+	}
+	want := "only-in-a: 0\nonly-in-b: 0\ndiffers: 0\n"
+	if stdout != want {
+		t.Errorf("Expected output %q, got %q", want, stdout)
+	}
+}
+
+func TestDiff_ReportsDifferences(t *testing.T) {
+	binaryPath := buildCLIBinary(t)
+	dbA := createTestDatabase(t, binaryPath)
+	dbB := createTestDatabase(t, binaryPath)
+
+	shared := uuid.Must(uuid.NewV7())
+	onlyA := uuid.Must(uuid.NewV7())
+	changed := uuid.Must(uuid.NewV7())
+	onlyB := uuid.Must(uuid.NewV7())
+
+	addRowToDatabase(t, binaryPath, dbA, shared.String(), `{"v":1}`)
+	addRowToDatabase(t, binaryPath, dbA, onlyA.String(), `{"v":2}`)
+	addRowToDatabase(t, binaryPath, dbA, changed.String(), `{"v":3}`)
+
+	addRowToDatabase(t, binaryPath, dbB, shared.String(), `{"v":1}`)
+	addRowToDatabase(t, binaryPath, dbB, changed.String(), `{"v":30}`)
+	addRowToDatabase(t, binaryPath, dbB, onlyB.String(), `{"v":4}`)
+
+	stdout, stderr, exitCode := runCLI(t, binaryPath, "diff", "--a", dbA, "--b", dbB)
+	if exitCode != 0 {
+		t.Fatalf("Expected exit code 0, got %d. Stderr: %s", exitCode, stderr)
+	}
+	want := "only-in-a: 1\nonly-in-b: 1\ndiffers: 1\n"
+	if stdout != want {
+		t.Errorf("Expected output %q, got %q", want, stdout)
+	}
+
+	stdout, stderr, exitCode = runCLI(t, binaryPath, "diff", "--a", dbA, "--b", dbB, "--verbose")
+	if exitCode != 0 {
+		t.Fatalf("Expected exit code 0, got %d. Stderr: %s", exitCode, stderr)
+	}
+	want = "only-in-a\t" + onlyA.String() + "\n" +
+		"differs\t" + changed.String() + "\n" +
+		"only-in-b\t" + onlyB.String() + "\n" +
+		"only-in-a: 1\nonly-in-b: 1\ndiffers: 1\n"
+	if stdout != want {
+		t.Errorf("Expected output %q, got %q", want, stdout)
+	}
+}
+
+func TestDiff_IgnoresRolledBackRows(t *testing.T) {
+	binaryPath := buildCLIBinary(t)
+	dbA := createTestDatabase(t, binaryPath)
+	dbB := createTestDatabase(t, binaryPath)
+
+	key := uuid.Must(uuid.NewV7())
+	for _, args := range [][]string{
+		{"--path", dbA, "begin"},
+		{"--path", dbA, "add", key.String(), `{"v":1}`},
+		{"--path", dbA, "rollback"},
+	} {
+		if _, stderr, exitCode := runCLI(t, binaryPath, args...); exitCode != 0 {
+			t.Fatalf("%v failed: %s", args, stderr)
+		}
+	}
+
+	stdout, stderr, exitCode := runCLI(t, binaryPath, "diff", "--a", dbA, "--b", dbB)
+	if exitCode != 0 {
+		t.Fatalf("Expected exit code 0, got %d. Stderr: %s", exitCode, stderr)
+	}
+	want := "only-in-a: 0\nonly-in-b: 0\ndiffers: 0\n"
+	if stdout != want {
+		t.Errorf("Expected output %q, got %q", want, stdout)
+	}
+}
+
+func TestDiff_MissingFlagError(t *testing.T) {
+	binaryPath := buildCLIBinary(t)
+	dbA := createTestDatabase(t, binaryPath)
+
+	_, stderr, exitCode := runCLI(t, binaryPath, "diff", "--a", dbA)
+	if exitCode != 1 {
+		t.Fatalf("Expected exit code 1, got %d", exitCode)
+	}
+	if !strings.HasPrefix(stderr, "Error: ") || !strings.Contains(stderr, "--b") {
+		t.Errorf("Expected error mentioning --b, got %q", stderr)
+	}
+}
+
+func TestVerify_ValidDatabaseIsSilent(t *testing.T) {
+	binaryPath := buildCLIBinary(t)
+	dbPath := createTestDatabase(t, binaryPath)
+
+	stdout, stderr, exitCode := runCLI(t, binaryPath, "--path", dbPath, "verify")
+	if exitCode != 0 || stdout != "" || stderr != "" {
+		t.Fatalf("Expected silent success, got exit %d, stdout %q, stderr %q", exitCode, stdout, stderr)
+	}
+
+	stdout, stderr, exitCode = runCLI(t, binaryPath, "--path", dbPath, "verify", "--repair", "--yes")
+	if exitCode != 0 || stdout != "" || stderr != "" {
+		t.Fatalf("Expected repair of valid database to be a silent no-op, got exit %d, stdout %q, stderr %q", exitCode, stdout, stderr)
+	}
+
+	stdout, stderr, exitCode = runCLI(t, binaryPath, "--path", dbPath, "verify", "--check-ordering")
+	if exitCode != 0 || stdout != "" || stderr != "" {
+		t.Fatalf("Expected ordered database to pass --check-ordering, got exit %d, stdout %q, stderr %q", exitCode, stdout, stderr)
+	}
+}
+
+func TestVerify_RepairTruncatesCorruptTail(t *testing.T) {
+	binaryPath := buildCLIBinary(t)
+	dbPath := createTestDatabase(t, binaryPath)
+
+	original, err := os.ReadFile(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to read database: %v", err)
+	}
+	// The sample database has row_size 256: header + initial checksum row is 320 bytes
+	const verifiedSize = 64 + 256
+	corrupted := append(append([]byte{}, original...), []byte("garbage after crash")...)
+	if err := os.WriteFile(dbPath, corrupted, 0644); err != nil {
+		t.Fatalf("Failed to corrupt database: %v", err)
+	}
+
+	_, stderr, exitCode := runCLI(t, binaryPath, "--path", dbPath, "verify")
+	if exitCode != 1 || !strings.HasPrefix(stderr, "Error: corrupt_database") {
+		t.Fatalf("Expected corrupt_database error, got exit %d, stderr %q", exitCode, stderr)
+	}
+
+	// Without --yes nothing is changed
+	_, stderr, exitCode = runCLI(t, binaryPath, "--path", dbPath, "verify", "--repair")
+	if exitCode != 1 || !strings.Contains(stderr, "--yes") {
+		t.Fatalf("Expected confirmation error, got exit %d, stderr %q", exitCode, stderr)
+	}
+	if info, _ := os.Stat(dbPath); info.Size() != int64(len(corrupted)) {
+		t.Fatalf("Database was modified without --yes")
+	}
+
+	stdout, stderr, exitCode := runCLI(t, binaryPath, "--path", dbPath, "verify", "--repair", "--yes")
+	if exitCode != 0 {
+		t.Fatalf("Expected repair to succeed, got exit %d, stderr %q", exitCode, stderr)
+	}
+	discarded := len(corrupted) - verifiedSize
+	want := "discarded 4 rows (" + strconv.Itoa(discarded) + " bytes)\n"
+	if stdout != want {
+		t.Errorf("Expected output %q, got %q", want, stdout)
+	}
+
+	repaired, err := os.ReadFile(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to read repaired database: %v", err)
+	}
+	if !bytes.Equal(repaired, original[:verifiedSize]) {
+		t.Errorf("Repaired database must be the untouched prefix up to the last checksum row")
+	}
+	if _, stderr, exitCode := runCLI(t, binaryPath, "--path", dbPath, "verify"); exitCode != 0 {
+		t.Errorf("Expected repaired database to verify, got stderr %q", stderr)
+	}
+}
+
+func TestVerify_ReportsEveryProblem(t *testing.T) {
+	binaryPath := buildCLIBinary(t)
+	dbPath := createTestDatabase(t, binaryPath)
+
+	data, err := os.ReadFile(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to read database: %v", err)
+	}
+	// Break the parity of the first and third data rows (row_size 256)
+	for _, index := range []int{1, 3} {
+		data[64+index*256+256-3] ^= 0x01
+	}
+	if err := os.WriteFile(dbPath, data, 0644); err != nil {
+		t.Fatalf("Failed to corrupt database: %v", err)
+	}
+
+	_, stderr, exitCode := runCLI(t, binaryPath, "--path", dbPath, "verify")
+	lines := strings.Split(strings.TrimSuffix(stderr, "\n"), "\n")
+	if exitCode != 1 || len(lines) != 2 {
+		t.Fatalf("Expected exit 1 with two problems, got exit %d, stderr %q", exitCode, stderr)
+	}
+	for i, offset := range []int{64 + 256, 64 + 3*256} {
+		if want := fmt.Sprintf("offset %d", offset); !strings.Contains(lines[i], want) {
+			t.Errorf("Expected line %d to mention %q, got %q", i, want, lines[i])
+		}
+	}
+
+	_, parallelStderr, exitCode := runCLI(t, binaryPath, "--path", dbPath, "verify", "--parallel", "4")
+	if exitCode != 1 || parallelStderr != stderr {
+		t.Errorf("Expected --parallel to report the same problems, got exit %d, stderr %q", exitCode, parallelStderr)
+	}
+}
+
+func TestParseVerifyFlags(t *testing.T) {
+	for _, args := range [][]string{{"--yes"}, {"--force"}, {"--parallel", "0"}, {"--parallel", "x"}, {"--parallel"}} {
+		if _, err := parseVerifyFlags(args); err == nil {
+			t.Errorf("Expected %v to fail", args)
+		}
+	}
+	opts, err := parseVerifyFlags([]string{"--yes", "--repair"})
+	if err != nil || !opts.repair || !opts.yes || opts.parallel != 1 {
+		t.Errorf("Expected repair and yes with parallel 1, got (%+v, %v)", opts, err)
+	}
+	opts, err = parseVerifyFlags([]string{"--parallel=4"})
+	if err != nil || opts.parallel != 4 || opts.repair {
+		t.Errorf("Expected parallel 4, got (%+v, %v)", opts, err)
+	}
+}
+
+func TestParseInspectFlags_ShowTime(t *testing.T) {
+	opts, err := parseInspectFlags([]string{"--show-time", "--limit", "2"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !opts.showTime || opts.limit != 2 || opts.offset != 0 || opts.printHeader {
+		t.Errorf("unexpected options: %+v", opts)
+	}
+
+	opts, err = parseInspectFlags(nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if opts.showTime || opts.limit != -1 {
+		t.Errorf("unexpected defaults: %+v", opts)
+	}
+}
+
+func TestInspect_ShowTime(t *testing.T) {
+	binaryPath := buildCLIBinary(t)
+	dbPath := createTestDatabase(t, binaryPath)
+
+	stdout, stderr, exitCode := runCLI(t, binaryPath, "--path", dbPath, "inspect", "--show-time", "--limit", "2")
+	if exitCode != 0 {
+		t.Fatalf("Expected exit code 0, got %d. Stderr: %s", exitCode, stderr)
+	}
+	lines := strings.Split(strings.TrimSuffix(stdout, "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("Expected header and 2 rows, got %q", stdout)
+	}
+	header := strings.Split(lines[0], "\t")
+	if header[len(header)-1] != "key_time" {
+		t.Errorf("Expected last column key_time, got %q", lines[0])
+	}
+
+	checksum := strings.Split(lines[1], "\t")
+	if len(checksum) != len(header) || checksum[len(checksum)-1] != "" {
+		t.Errorf("Expected blank key_time for checksum row, got %q", lines[1])
+	}
+
+	data := strings.Split(lines[2], "\t")
+	if len(data) != len(header) {
+		t.Fatalf("Expected %d columns, got %q", len(header), lines[2])
+	}
+	key := uuid.MustParse(data[2])
+	sec, nsec := key.Time().UnixTime()
+	want := time.Unix(sec, nsec).UTC().Format(time.RFC3339Nano)
+	if data[len(data)-1] != want {
+		t.Errorf("Expected key_time %q, got %q", want, data[len(data)-1])
+	}
+
+	// Without the flag the column is absent
+	stdout, _, _ = runCLI(t, binaryPath, "--path", dbPath, "inspect", "--limit", "1")
+	if strings.Contains(stdout, "key_time") {
+		t.Errorf("Expected no key_time column without --show-time, got %q", stdout)
+	}
+}
+
+func TestParseGlobalFlags_EqualsSyntax(t *testing.T) {
+	flags, err := parseGlobalFlags([]string{"frozendb", "--path=db.fdb", "--finder=simple", "inspect", "--limit=2"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if flags.path != "db.fdb" || flags.finder != "simple" || flags.subcommand != "inspect" {
+		t.Errorf("unexpected flags: %+v", flags)
+	}
+	if len(flags.args) != 1 || flags.args[0] != "--limit=2" {
+		t.Errorf("expected --limit=2 to pass through to the subcommand, got %v", flags.args)
+	}
+
+	// A value may itself contain '='
+	flags, err = parseGlobalFlags([]string{"frozendb", "--path=a=b.fdb", "inspect"})
+	if err != nil || flags.path != "a=b.fdb" {
+		t.Errorf("expected path a=b.fdb, got %+v (%v)", flags, err)
+	}
+
+	duplicates := [][]string{
+		{"frozendb", "--path=a.fdb", "--path", "b.fdb", "inspect"},
+		{"frozendb", "--path", "a.fdb", "--path=b.fdb", "inspect"},
+		{"frozendb", "--finder=simple", "--finder=binary", "--path", "a.fdb", "inspect"},
+	}
+	for _, args := range duplicates {
+		if _, err := parseGlobalFlags(args); err == nil || !strings.Contains(err.Error(), "duplicate flag") {
+			t.Errorf("%v: expected duplicate flag error, got %v", args, err)
+		}
+	}
+}
+
+func TestParseInspectFlags_EqualsSyntax(t *testing.T) {
+	opts, err := parseInspectFlags([]string{"--offset=3", "--limit=4", "--print-header=true"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if opts.offset != 3 || opts.limit != 4 || !opts.printHeader {
+		t.Errorf("unexpected options: %+v", opts)
+	}
+
+	for _, args := range [][]string{{"--offset="}, {"--limit=x"}, {"--print-header=maybe"}, {"--limit"}, {"--limit", "0"}} {
+		if _, err := parseInspectFlags(args); err == nil {
+			t.Errorf("%v: expected error", args)
+		}
+	}
+}
+
+func TestParseFinderStrategy_Auto(t *testing.T) {
+	for _, value := range []string{"auto", "AUTO"} {
+		strategy, err := parseFinderStrategy(value)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", value, err)
+		}
+		if strategy != pkg_frozendb.FinderStrategyAuto {
+			t.Errorf("%s: got %s, want %s", value, strategy, pkg_frozendb.FinderStrategyAuto)
+		}
+	}
+	if _, err := parseFinderStrategy("automatic"); err == nil || !strings.Contains(err.Error(), "auto") {
+		t.Errorf("expected invalid strategy error listing auto, got %v", err)
+	}
+}
+
+func TestInspect_Follow(t *testing.T) {
+	binaryPath := buildCLIBinary(t)
+	dbPath := createTestDatabase(t, binaryPath)
+
+	data, err := os.ReadFile(dbPath)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	rowSize, err := readRowSize(dbPath)
+	if err != nil {
+		t.Fatalf("readRowSize: %v", err)
+	}
+	existing := completeRowCount(int64(len(data)), rowSize)
+	if existing < 2 {
+		t.Fatalf("Expected sample database with at least 2 rows, got %d", existing)
+	}
+	dataRow := data[64+rowSize : 64+2*rowSize]
+
+	// Follow until one row past the current end of file
+	cmd := exec.Command(binaryPath, "--path", dbPath, "inspect", "--follow", "--offset", strconv.FormatInt(existing, 10), "--limit", "1")
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Start(); err != nil {
+		t.Fatalf("Failed to start CLI: %v", err)
+	}
+	done := make(chan error, 1)
+	go func() { done <- cmd.Wait() }()
+
+	// Append the row in two writes; the half-written row must not be printed
+	f, err := os.OpenFile(dbPath, os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatalf("OpenFile: %v", err)
+	}
+	defer f.Close()
+	time.Sleep(300 * time.Millisecond)
+	if _, err := f.Write(dataRow[:rowSize/2]); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	time.Sleep(600 * time.Millisecond)
+	if _, err := f.Write(dataRow[rowSize/2:]); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("inspect --follow failed: %v. Stderr: %s", err, stderr.String())
+		}
+	case <-time.After(10 * time.Second):
+		_ = cmd.Process.Kill()
+		t.Fatalf("inspect --follow did not exit after reaching the limit. Stdout: %s", stdout.String())
+	}
+
+	lines := strings.Split(strings.TrimSuffix(stdout.String(), "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected header and 1 row, got %q", stdout.String())
+	}
+	fields := strings.Split(lines[1], "\t")
+	if fields[0] != strconv.FormatInt(existing, 10) || fields[1] != "Data" {
+		t.Errorf("Expected appended Data row at index %d, got %q", existing, lines[1])
+	}
+}
+
+func TestInspect_PartialRowFields(t *testing.T) {
+	binaryPath := buildCLIBinary(t)
+	dbPath := createTestDatabase(t, binaryPath)
+
+	data, err := os.ReadFile(dbPath)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	rowSize, err := readRowSize(dbPath)
+	if err != nil {
+		t.Fatalf("readRowSize: %v", err)
+	}
+	rows := completeRowCount(int64(len(data)), rowSize)
+
+	// Simulate a crash after the payload of a data row was written
+	dataRow := data[64+rowSize : 64+2*rowSize]
+	padding := bytes.IndexByte(dataRow[2:], 0) + 2
+	f, err := os.OpenFile(dbPath, os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatalf("OpenFile: %v", err)
+	}
+	if _, err := f.Write(dataRow[:padding+1]); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	f.Close()
+
+	stdout, stderr, exitCode := runCLI(t, binaryPath, "--path", dbPath, "inspect")
+	if exitCode != 0 {
+		t.Fatalf("Expected exit code 0, got %d. Stderr: %s", exitCode, stderr)
+	}
+	lines := strings.Split(strings.TrimSuffix(stdout, "\n"), "\n")
+	if int64(len(lines)) != rows+2 {
+		t.Fatalf("Expected header, %d rows and the partial row, got %q", rows, stdout)
+	}
+	complete := strings.Split(lines[2], "\t")
+	partial := strings.Split(lines[len(lines)-1], "\t")
+	if partial[1] != "partial" || partial[2] != complete[2] || partial[3] != complete[3] {
+		t.Errorf("Expected partial row with key %s and value %s, got %q", complete[2], complete[3], lines[len(lines)-1])
+	}
+}
+
+func TestParseCreateArgs(t *testing.T) {
+	tests := []struct {
+		name         string
+		args         []string
+		wantPath     string
+		wantInterval int
+		wantNoImmut  bool
+		wantForce    bool
+		wantEstimate bool
+		wantRows     int64
+		wantErr      string
+	}{
+		{name: "path only", args: []string{"db.fdb"}, wantPath: "db.fdb"},
+		{name: "interval before path", args: []string{"--checksum-interval", "500", "db.fdb"}, wantPath: "db.fdb", wantInterval: 500},
+		{name: "interval after path", args: []string{"db.fdb", "--checksum-interval=500"}, wantPath: "db.fdb", wantInterval: 500},
+		{name: "no immutable", args: []string{"--no-immutable", "db.fdb"}, wantPath: "db.fdb", wantNoImmut: true},
+		{name: "force", args: []string{"db.fdb", "--force"}, wantPath: "db.fdb", wantForce: true},
+		{name: "estimate without path", args: []string{"--estimate", "1000000"}, wantEstimate: true, wantRows: 1000000},
+		{name: "estimate with interval", args: []string{"--estimate=0", "--checksum-interval", "500"}, wantInterval: 500, wantEstimate: true},
+		{name: "negative estimate", args: []string{"--estimate", "-1"}, wantErr: "non-negative"},
+		{name: "estimate with bad interval", args: []string{"--estimate", "10", "--checksum-interval", "5"}, wantErr: "must be between"},
+		{name: "missing path", args: []string{"--checksum-interval=500"}, wantErr: "missing required argument"},
+		{name: "two paths", args: []string{"a.fdb", "b.fdb"}, wantErr: "too many arguments"},
+		{name: "bad interval", args: []string{"--checksum-interval=many", "db.fdb"}, wantErr: "must be a number"},
+		{name: "unknown flag", args: []string{"--row-size", "db.fdb"}, wantErr: "unknown flag"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts, err := parseCreateArgs(tt.args)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if opts.path != tt.wantPath || opts.checksumInterval != tt.wantInterval || opts.noImmutable != tt.wantNoImmut {
+				t.Errorf("got (%q, %d, %v), want (%q, %d, %v)", opts.path, opts.checksumInterval, opts.noImmutable, tt.wantPath, tt.wantInterval, tt.wantNoImmut)
+			}
+			if opts.force != tt.wantForce {
+				t.Errorf("force = %v, want %v", opts.force, tt.wantForce)
+			}
+			if opts.estimate != tt.wantEstimate || opts.estimateRows != tt.wantRows {
+				t.Errorf("estimate = (%v, %d), want (%v, %d)", opts.estimate, opts.estimateRows, tt.wantEstimate, tt.wantRows)
+			}
+		})
+	}
+}
+
+func TestParseExportFlags(t *testing.T) {
+	key := uuid.Must(uuid.NewV7())
+
+	opts, err := parseExportFlags([]string{"--after", key.String(), "--before=" + key.String(), "--count-only"})
+	if err != nil {
+		t.Fatalf("parseExportFlags: %v", err)
+	}
+	if opts.after != key || opts.before != key || !opts.countOnly {
+		t.Errorf("unexpected options: %+v", opts)
+	}
+
+	if opts.format != exportFormatJSONL || opts.flatten {
+		t.Errorf("default format = %q (flatten %v), want %q", opts.format, opts.flatten, exportFormatJSONL)
+	}
+
+	opts, err = parseExportFlags([]string{"--format", "csv", "--flatten"})
+	if err != nil {
+		t.Fatalf("parseExportFlags: %v", err)
+	}
+	if opts.format != exportFormatCSV || !opts.flatten {
+		t.Errorf("unexpected options: %+v", opts)
+	}
+
+	for _, args := range [][]string{
+		{"--after"},
+		{"--after", "not-a-uuid"},
+		{"--before", uuid.New().String()}, // UUIDv4
+		{"--unknown"},
+		{"--format", "xml"},
+		{"--flatten"},
+	} {
+		if _, err := parseExportFlags(args); err == nil {
+			t.Errorf("parseExportFlags(%v): expected error", args)
+		}
+	}
+}
+
+func TestExport_RangeAndCountOnly(t *testing.T) {
+	binaryPath := buildCLIBinary(t)
+	dbPath := createTestDatabase(t, binaryPath)
+
+	keys := make([]uuid.UUID, 3)
+	for i := range keys {
+		keys[i] = uuid.Must(uuid.NewV7())
+		addRowToDatabase(t, binaryPath, dbPath, keys[i].String(), fmt.Sprintf(`{"v":%d}`, i))
+	}
+
+	stdout, stderr, exitCode := runCLI(t, binaryPath, "--path", dbPath, "export", "--after", keys[0].String(), "--before", keys[2].String())
+	if exitCode != 0 {
+		t.Fatalf("Expected exit code 0, got %d. Stderr: %s", exitCode, stderr)
+	}
+	want := `{"key":"` + keys[1].String() + `","value":{"v":1}}` + "\n"
+	if stdout != want {
+		t.Errorf("Expected output %q, got %q", want, stdout)
+	}
+
+	// The sample database holds three committed rows older than the added keys
+	stdout, stderr, exitCode = runCLI(t, binaryPath, "--path", dbPath, "export", "--count-only")
+	if exitCode != 0 {
+		t.Fatalf("Expected exit code 0, got %d. Stderr: %s", exitCode, stderr)
+	}
+	if stdout != "6\n" {
+		t.Errorf("Expected count 6, got %q", stdout)
+	}
+
+	stdout, _, _ = runCLI(t, binaryPath, "--path", dbPath, "export", "--after", keys[0].String(), "--count-only")
+	if stdout != "2\n" {
+		t.Errorf("Expected count 2, got %q", stdout)
+	}
+}
+
+func TestFlatFields(t *testing.T) {
+	fields, ok := flatFields(json.RawMessage(`{"name":"a,\"b\"","n":1.5,"ok":true,"none":null}`))
+	if !ok {
+		t.Fatal("expected a flat object")
+	}
+	want := []flatField{{"name", `a,"b"`}, {"n", "1.5"}, {"ok", "true"}, {"none", ""}}
+	if !slices.Equal(fields, want) {
+		t.Errorf("flatFields = %v, want %v", fields, want)
+	}
+
+	for _, value := range []string{`[1]`, `"s"`, `{"a":{"b":1}}`, `{"a":[1]}`, `{"key":1}`} {
+		if _, ok := flatFields(json.RawMessage(value)); ok {
+			t.Errorf("flatFields(%s): expected not flat", value)
+		}
+	}
+}
+
+func TestExport_CSV(t *testing.T) {
+	binaryPath := buildCLIBinary(t)
+	dbPath := createTestDatabase(t, binaryPath)
+
+	key := uuid.Must(uuid.NewV7())
+	addRowToDatabase(t, binaryPath, dbPath, key.String(), `{"message":"a, \"quoted\" value","count":4}`)
+
+	readCSV := func(args ...string) [][]string {
+		t.Helper()
+		stdout, stderr, exitCode := runCLI(t, binaryPath, append([]string{"--path", dbPath, "export", "--format", "csv"}, args...)...)
+		if exitCode != 0 {
+			t.Fatalf("Expected exit code 0, got %d. Stderr: %s", exitCode, stderr)
+		}
+		records, err := csv.NewReader(strings.NewReader(stdout)).ReadAll()
+		if err != nil {
+			t.Fatalf("output is not valid CSV: %v\n%s", err, stdout)
+		}
+		return records
+	}
+
+	// The sample database holds three committed rows older than the added key
+	records := readCSV()
+	if len(records) != 5 || !slices.Equal(records[0], []string{"key", "value"}) {
+		t.Fatalf("unexpected CSV: %q", records)
+	}
+	if want := []string{key.String(), `{"message":"a, \"quoted\" value","count":4}`}; !slices.Equal(records[4], want) {
+		t.Errorf("last record = %q, want %q", records[4], want)
+	}
+
+	records = readCSV("--flatten")
+	if len(records) != 5 || !slices.Equal(records[0], []string{"key", "message", "count"}) {
+		t.Fatalf("unexpected flattened CSV: %q", records)
+	}
+	if want := []string{key.String(), `a, "quoted" value`, "4"}; !slices.Equal(records[4], want) {
+		t.Errorf("last flattened record = %q, want %q", records[4], want)
+	}
+
+	// A nested value falls back to the key,value columns
+	addRowToDatabase(t, binaryPath, dbPath, uuid.Must(uuid.NewV7()).String(), `{"nested":{"a":1}}`)
+	records = readCSV("--flatten")
+	if len(records) != 6 || !slices.Equal(records[0], []string{"key", "value"}) {
+		t.Errorf("expected fallback to key,value columns, got %q", records)
+	}
+}
+
+func TestParseHeadTailArgs(t *testing.T) {
+	if n, err := parseHeadTailArgs(nil); err != nil || n != defaultHeadTailRows {
+		t.Errorf("no argument: got (%d, %v), want (%d, nil)", n, err, defaultHeadTailRows)
+	}
+	if n, err := parseHeadTailArgs([]string{"3"}); err != nil || n != 3 {
+		t.Errorf("3: got (%d, %v), want (3, nil)", n, err)
+	}
+	for _, args := range [][]string{{"0"}, {"-1"}, {"x"}, {"1", "2"}} {
+		if _, err := parseHeadTailArgs(args); err == nil {
+			t.Errorf("parseHeadTailArgs(%v): expected error", args)
+		}
+	}
+}
+
+func TestHeadAndTail(t *testing.T) {
+	binaryPath := buildCLIBinary(t)
+	dbPath := createTestDatabase(t, binaryPath)
+
+	keys := make([]uuid.UUID, 3)
+	for i := range keys {
+		keys[i] = uuid.Must(uuid.NewV7())
+		addRowToDatabase(t, binaryPath, dbPath, keys[i].String(), fmt.Sprintf(`{"v":%d}`, i))
+	}
+	// A rolled back row is not shown
+	rolledBack := uuid.Must(uuid.NewV7())
+	runCLI(t, binaryPath, "--path", dbPath, "begin")
+	runCLI(t, binaryPath, "--path", dbPath, "add", rolledBack.String(), `{"v":"gone"}`)
+	runCLI(t, binaryPath, "--path", dbPath, "rollback")
+
+	line := func(i int) string {
+		return `{"key":"` + keys[i].String() + `","value":{"v":` + fmt.Sprint(i) + `}}` + "\n"
+	}
+
+	stdout, stderr, exitCode := runCLI(t, binaryPath, "--path", dbPath, "tail", "2")
+	if exitCode != 0 {
+		t.Fatalf("Expected exit code 0, got %d. Stderr: %s", exitCode, stderr)
+	}
+	if want := line(1) + line(2); stdout != want {
+		t.Errorf("tail 2: expected %q, got %q", want, stdout)
+	}
+
+	// The sample database holds three committed rows older than the added keys
+	stdout, stderr, exitCode = runCLI(t, binaryPath, "--path", dbPath, "head", "4")
+	if exitCode != 0 {
+		t.Fatalf("Expected exit code 0, got %d. Stderr: %s", exitCode, stderr)
+	}
+	if lines := strings.Split(strings.TrimSuffix(stdout, "\n"), "\n"); len(lines) != 4 || lines[3]+"\n" != line(0) {
+		t.Errorf("head 4: unexpected output %q", stdout)
+	}
+
+	stdout, _, _ = runCLI(t, binaryPath, "--path", dbPath, "head")
+	if lines := strings.Split(strings.TrimSuffix(stdout, "\n"), "\n"); len(lines) != 6 || strings.Contains(stdout, rolledBack.String()) {
+		t.Errorf("head: expected the 6 committed rows, got %q", stdout)
+	}
+
+	_, stderr, exitCode = runCLI(t, binaryPath, "--path", dbPath, "tail", "0")
+	if exitCode != 1 || !strings.Contains(stderr, "positive integer") {
+		t.Errorf("tail 0: expected exit 1 with a row count error, got %d: %s", exitCode, stderr)
+	}
+}
+
+func TestEscapeInspectValue(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{`{"a":1}`, `{"a":1}`},
+		{"{\"a\":\t1,\n\"b\":2}", `{"a":\x091,\x0a"b":2}`},
+		{"\"\x1b[2J\"", `"\x1b[2J"`},
+		{"\"caf\xc3\xa9\"", "\"caf\xc3\xa9\""},
+		{"\"\xff\xfe\"", `"\xff\xfe"`},
+		{"\"\u0085\"", `"\u0085"`},
+	}
+	for _, tt := range tests {
+		if got := escapeInspectValue(tt.value); got != tt.want {
+			t.Errorf("escapeInspectValue(%q) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestParseGetArgs(t *testing.T) {
+	key, base64Output, err := parseGetArgs([]string{"--base64", "k"})
+	if err != nil || key != "k" || !base64Output {
+		t.Errorf("got (%q, %v, %v), want (k, true, nil)", key, base64Output, err)
+	}
+	key, base64Output, err = parseGetArgs([]string{"k"})
+	if err != nil || key != "k" || base64Output {
+		t.Errorf("got (%q, %v, %v), want (k, false, nil)", key, base64Output, err)
+	}
+	for _, args := range [][]string{nil, {"--base64"}, {"k", "--raw"}, {"k", "j"}} {
+		if _, _, err := parseGetArgs(args); err == nil {
+			t.Errorf("%v: expected error", args)
+		}
+	}
+}
+
+func TestInspectAndGet_NonPrintableValue(t *testing.T) {
+	binaryPath := buildCLIBinary(t)
+	dbPath := createTestDatabase(t, binaryPath)
+
+	key := uuid.Must(uuid.NewV7())
+	value := "{\"a\":\t1,\n\"b\":\"\\u001b\"}"
+	addRowToDatabase(t, binaryPath, dbPath, key.String(), value)
+
+	findRow := func(stdout string) string {
+		t.Helper()
+		for _, line := range strings.Split(stdout, "\n") {
+			if strings.Contains(line, key.String()) {
+				return line
+			}
+		}
+		t.Fatalf("row for %s not found in %q", key, stdout)
+		return ""
+	}
+
+	stdout, stderr, exitCode := runCLI(t, binaryPath, "--path", dbPath, "inspect")
+	if exitCode != 0 {
+		t.Fatalf("Expected exit code 0, got %d. Stderr: %s", exitCode, stderr)
+	}
+	columns := strings.Split(findRow(stdout), "\t")
+	if len(columns) != 9 {
+		t.Fatalf("Expected 9 columns, got %q", columns)
+	}
+	if want := `{"a":\x091,\x0a"b":"\u001b"}`; columns[3] != want {
+		t.Errorf("Expected escaped value %q, got %q", want, columns[3])
+	}
+
+	// --raw prints the stored bytes unchanged
+	stdout, _, _ = runCLI(t, binaryPath, "--path", dbPath, "inspect", "--raw")
+	if !strings.Contains(stdout, value) {
+		t.Errorf("Expected raw value %q in %q", value, stdout)
+	}
+
+	stdout, stderr, exitCode = runCLI(t, binaryPath, "--path", dbPath, "get", key.String(), "--base64")
+	if exitCode != 0 {
+		t.Fatalf("Expected exit code 0, got %d. Stderr: %s", exitCode, stderr)
+	}
+	decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(stdout))
+	if err != nil {
+		t.Fatalf("Expected base64 output, got %q: %v", stdout, err)
+	}
+	if string(decoded) != value {
+		t.Errorf("Expected decoded value %q, got %q", value, decoded)
+	}
+}
+
+func TestDescribeEndControl(t *testing.T) {
+	tests := map[string]string{
+		"RE": "row end: the transaction continues",
+		"SE": "savepoint, then row end",
+		"TC": "commit: the transaction ends and all its rows are visible",
+		"SC": "savepoint, then commit",
+		"NR": "null row",
+		"CS": "checksum row end",
+		"R0": "full rollback",
+		"S0": "savepoint, then full rollback",
+		"R3": "rollback to savepoint 3",
+		"S9": "savepoint, then rollback to savepoint 9",
+		"TE": "unknown",
+		"RC": "unknown",
+		"XX": "unknown",
+	}
+	for code, want := range tests {
+		got := describeEndControl(internal_frozendb.EndControl{code[0], code[1]})
+		if !strings.HasPrefix(got, code+" (") || !strings.Contains(got, want) {
+			t.Errorf("describeEndControl(%s) = %q, want it to contain %q", code, got, want)
+		}
+	}
+}
+
+func TestParseDecodeFlags(t *testing.T) {
+	if index, err := parseDecodeFlags([]string{"--index=3"}); err != nil || index != 3 {
+		t.Errorf("got (%d, %v), want (3, nil)", index, err)
+	}
+	for _, args := range [][]string{nil, {"--index", "-1"}, {"--index", "x"}, {"--offset", "1"}} {
+		if _, err := parseDecodeFlags(args); err == nil {
+			t.Errorf("%v: expected error", args)
+		}
+	}
+}
+
+func TestDecode(t *testing.T) {
+	binaryPath := buildCLIBinary(t)
+	dbPath := createTestDatabase(t, binaryPath)
+
+	stdout, stderr, exitCode := runCLI(t, binaryPath, "--path", dbPath, "decode", "--index", "1")
+	if exitCode != 0 {
+		t.Fatalf("Expected exit code 0, got %d. Stderr: %s", exitCode, stderr)
+	}
+	for _, want := range []string{"type: DataRow", "start_control: T (transaction start)", "end_control: RE (row end", "parity: ok", "framing: ok", "value: {"} {
+		if !strings.Contains(stdout, want) {
+			t.Errorf("Expected %q in output:\n%s", want, stdout)
+		}
+	}
+
+	stdout, _, exitCode = runCLI(t, binaryPath, "--path", dbPath, "decode", "--index", "0")
+	if exitCode != 0 || !strings.Contains(stdout, "type: ChecksumRow") || strings.Contains(stdout, "transaction:") {
+		t.Errorf("Expected checksum row without transaction fields, got exit %d:\n%s", exitCode, stdout)
+	}
+
+	// A bad parity byte is reported, with the bytes still decoded
+	data, err := os.ReadFile(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to read database: %v", err)
+	}
+	data[64+3*256+256-3] ^= 0x01
+	data = append(data, 0x1F, 'T') // Partial row: only start_control written
+	if err := os.WriteFile(dbPath, data, 0644); err != nil {
+		t.Fatalf("Failed to write database: %v", err)
+	}
+	stdout, _, exitCode = runCLI(t, binaryPath, "--path", dbPath, "decode", "--index", "3")
+	if exitCode != 1 || !strings.Contains(stdout, "parity: bad") || !strings.Contains(stdout, "end_control: TC (commit") {
+		t.Errorf("Expected bad parity with decoded controls, got exit %d:\n%s", exitCode, stdout)
+	}
+
+	stdout, _, exitCode = runCLI(t, binaryPath, "--path", dbPath, "decode", "--index", "4")
+	if exitCode != 0 || !strings.Contains(stdout, "type: partial (2 of 256 bytes written)") {
+		t.Errorf("Expected partial row, got exit %d:\n%s", exitCode, stdout)
+	}
+
+	_, stderr, exitCode = runCLI(t, binaryPath, "--path", dbPath, "decode", "--index", "5")
+	if exitCode != 1 || !strings.Contains(stderr, "out of range") {
+		t.Errorf("Expected out of range error, got exit %d, stderr %q", exitCode, stderr)
+	}
+}
+
+func TestMark(t *testing.T) {
+	binaryPath := buildCLIBinary(t)
+	dbPath := createTestDatabase(t, binaryPath)
+	before, err := os.Stat(dbPath)
+	if err != nil {
+		t.Fatalf("Stat: %v", err)
+	}
+
+	stdout, stderr, exitCode := runCLI(t, binaryPath, "--path", dbPath, "mark")
+	if exitCode != 0 {
+		t.Fatalf("Expected exit code 0, got %d. Stderr: %s", exitCode, stderr)
+	}
+	if stdout != "" || stderr != "" {
+		t.Errorf("Expected silent success, got stdout %q stderr %q", stdout, stderr)
+	}
+
+	db, err := pkg_frozendb.NewFrozenDB(dbPath, pkg_frozendb.MODE_READ, pkg_frozendb.FinderStrategySimple)
+	if err != nil {
+		t.Fatalf("NewFrozenDB: %v", err)
+	}
+	row, err := db.RowAt(db.RowCount() - 1)
+	_ = db.Close()
+	if err != nil {
+		t.Fatalf("RowAt: %v", err)
+	}
+	if row.Kind != pkg_frozendb.RowKindNull {
+		t.Errorf("Expected the last row to be a NullRow, got %s", row.Kind)
+	}
+	if after, _ := os.Stat(dbPath); after.Size()-before.Size() < 256 {
+		t.Errorf("Expected mark to append a row, file grew by %d bytes", after.Size()-before.Size())
+	}
+
+	// Like the other write commands, mark refuses to run inside an open transaction
+	if _, stderr, exitCode := runCLI(t, binaryPath, "--path", dbPath, "begin"); exitCode != 0 {
+		t.Fatalf("begin failed: %s", stderr)
+	}
+	if _, stderr, exitCode := runCLI(t, binaryPath, "--path", dbPath, "mark"); exitCode != 1 || !strings.Contains(stderr, "transaction already active") {
+		t.Errorf("Expected exit code 1 with an active transaction, got %d. Stderr: %s", exitCode, stderr)
+	}
+}
+
+func TestCreate_NoImmutable(t *testing.T) {
+	binaryPath := buildCLIBinary(t)
+	dbPath := filepath.Join(t.TempDir(), "plain.fdb")
+
+	stdout, stderr, exitCode := runCLI(t, binaryPath, "create", "--no-immutable", dbPath)
+	if exitCode != 0 {
+		t.Fatalf("Expected exit code 0, got %d. Stderr: %s", exitCode, stderr)
+	}
+	if stdout != "" || !strings.Contains(stderr, "warning: append-only attribute not set") {
+		t.Errorf("Expected only the append-only warning, got stdout %q stderr %q", stdout, stderr)
+	}
+	if _, stderr, exitCode := runCLI(t, binaryPath, "--path", dbPath, "verify"); exitCode != 0 {
+		t.Errorf("verify failed on the created database: %s", stderr)
+	}
+}
+
+func TestCreate_ExistingFile(t *testing.T) {
+	binaryPath := buildCLIBinary(t)
+	dbPath := createTestDatabase(t, binaryPath)
+	before, err := os.ReadFile(dbPath)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+
+	_, stderr, exitCode := runCLI(t, binaryPath, "create", "--no-immutable", dbPath)
+	if exitCode != 1 || !strings.Contains(stderr, "file already exists") {
+		t.Fatalf("Expected exit code 1 with 'file already exists', got %d. Stderr: %s", exitCode, stderr)
+	}
+	if after, _ := os.ReadFile(dbPath); !bytes.Equal(after, before) {
+		t.Fatal("create modified the existing database without --force")
+	}
+
+	if _, stderr, exitCode := runCLI(t, binaryPath, "create", "--no-immutable", "--force", dbPath); exitCode != 0 {
+		t.Fatalf("create --force failed with exit code %d. Stderr: %s", exitCode, stderr)
+	}
+	info, err := os.Stat(dbPath)
+	if err != nil {
+		t.Fatalf("Stat: %v", err)
+	}
+	if want := pkg_frozendb.EstimatedSize(defaultRowSize, 0, 0); info.Size() != want {
+		t.Errorf("replaced database is %d bytes, want an empty database of %d", info.Size(), want)
+	}
+}
+
+func TestCreate_Estimate(t *testing.T) {
+	binaryPath := buildCLIBinary(t)
+	dbPath := filepath.Join(t.TempDir(), "planned.fdb")
+
+	stdout, stderr, exitCode := runCLI(t, binaryPath, "create", "--estimate", "1000000", dbPath)
+	if exitCode != 0 {
+		t.Fatalf("Expected exit code 0, got %d. Stderr: %s", exitCode, stderr)
+	}
+	want := pkg_frozendb.EstimatedSize(defaultRowSize, 1000000, 0)
+	if strings.TrimSpace(stdout) != strconv.FormatInt(want, 10) {
+		t.Errorf("stdout = %q, want %d", stdout, want)
+	}
+	if _, err := os.Stat(dbPath); !os.IsNotExist(err) {
+		t.Errorf("create --estimate created %s (stat error %v)", dbPath, err)
+	}
+}
+
+func TestCreate_ValueCompression(t *testing.T) {
+	binaryPath := buildCLIBinary(t)
+	dbPath := filepath.Join(t.TempDir(), "gzip.fdb")
+
+	if _, stderr, exitCode := runCLI(t, binaryPath, "create", "--no-immutable", "--value-compression", "gzip", dbPath); exitCode != 0 {
+		t.Fatalf("create failed: %s", stderr)
+	}
+	key := uuid.Must(uuid.NewV7()).String()
+	value := fmt.Sprintf(`{"text":%q}`, strings.Repeat("frozen ", 1000))
+	addRowToDatabase(t, binaryPath, dbPath, key, value)
+
+	stdout, stderr, exitCode := runCLI(t, binaryPath, "--path", dbPath, "get", key)
+	if exitCode != 0 {
+		t.Fatalf("get failed: %s", stderr)
+	}
+	if !strings.Contains(stdout, "frozen frozen") {
+		t.Errorf("get did not return the decompressed value: %.60q", stdout)
+	}
+
+	stdout, stderr, exitCode = runCLI(t, binaryPath, "--path", dbPath, "inspect", "--print-header", "true")
+	if exitCode != 0 {
+		t.Fatalf("inspect failed: %s", stderr)
+	}
+	lines := strings.Split(strings.TrimSpace(stdout), "\n")
+	if lines[0] != "Row Size\tClock Skew\tFile Version\tValue Compression" || !strings.HasSuffix(lines[1], "\tgzip") {
+		t.Errorf("unexpected header table: %q", lines[:2])
+	}
+	if !strings.HasSuffix(lines[3], "\tstored_size\tvalue_size") {
+		t.Errorf("unexpected column header: %q", lines[3])
+	}
+	fields := strings.Split(lines[5], "\t")
+	if fields[1] != "Data" || fields[3] != value {
+		t.Fatalf("data row does not show the decompressed value: %.80q", lines[5])
+	}
+	stored, _ := strconv.Atoi(fields[len(fields)-2])
+	if fields[len(fields)-1] != strconv.Itoa(len(value)) || stored == 0 || stored >= len(value) {
+		t.Errorf("sizes = %q, %q; want a stored size below %d", fields[len(fields)-2], fields[len(fields)-1], len(value))
+	}
+
+	if _, stderr, exitCode := runCLI(t, binaryPath, "create", "--no-immutable", "--value-compression", "zstd", filepath.Join(t.TempDir(), "zstd.fdb")); exitCode == 0 || !strings.Contains(stderr, "unsupported value compression") {
+		t.Errorf("create with zstd: exit %d, stderr %q", exitCode, stderr)
+	}
+}
+
+func TestCreate_Metadata(t *testing.T) {
+	binaryPath := buildCLIBinary(t)
+	dir := t.TempDir()
+	dbPath := filepath.Join(dir, "meta.fdb")
+
+	if _, stderr, exitCode := runCLI(t, binaryPath, "create", "--no-immutable", "--meta", "schema=3", "--meta=source=billing=eu", dbPath); exitCode != 0 {
+		t.Fatalf("create failed: %s", stderr)
+	}
+	wantHeader := func(path string) {
+		t.Helper()
+		stdout, stderr, exitCode := runCLI(t, binaryPath, "--path", path, "inspect", "--print-header", "true")
+		if exitCode != 0 {
+			t.Fatalf("inspect failed: %s", stderr)
+		}
+		lines := strings.Split(strings.TrimSpace(stdout), "\n")
+		if lines[0] != "Row Size\tClock Skew\tFile Version\tMetadata" || !strings.HasSuffix(lines[1], "\t2\t"+`{"schema":"3","source":"billing=eu"}`) {
+			t.Errorf("unexpected header table: %q", lines[:2])
+		}
+	}
+	wantHeader(dbPath)
+
+	// set-skew keeps the metadata
+	skewed := filepath.Join(dir, "skewed.fdb")
+	if _, stderr, exitCode := runCLI(t, binaryPath, "--path", dbPath, "set-skew", "--skew-ms", "10", "--out", skewed, "--no-immutable"); exitCode != 0 {
+		t.Fatalf("set-skew failed: %s", stderr)
+	}
+	wantHeader(skewed)
+
+	for _, args := range [][]string{
+		{"--meta", "schema", "x.fdb"},
+		{"--meta", "=3", "x.fdb"},
+		{"--meta", "schema=3", "--meta", "schema=4", "x.fdb"},
+	} {
+		if _, err := parseCreateArgs(args); err == nil {
+			t.Errorf("parseCreateArgs(%q) should fail", args)
+		}
+	}
+}
+
+func TestCreate_RecordCreatedAt(t *testing.T) {
+	binaryPath := buildCLIBinary(t)
+	dbPath := filepath.Join(t.TempDir(), "created.fdb")
+
+	before := time.Now().Truncate(time.Millisecond)
+	if _, stderr, exitCode := runCLI(t, binaryPath, "create", "--no-immutable", "--record-created-at", dbPath); exitCode != 0 {
+		t.Fatalf("create failed: %s", stderr)
+	}
+	after := time.Now()
+
+	stdout, stderr, exitCode := runCLI(t, binaryPath, "--path", dbPath, "inspect", "--print-header", "true")
+	if exitCode != 0 {
+		t.Fatalf("inspect failed: %s", stderr)
+	}
+	lines := strings.Split(strings.TrimSpace(stdout), "\n")
+	if lines[0] != "Row Size\tClock Skew\tFile Version\tCreated At" {
+		t.Fatalf("unexpected header table: %q", lines[:2])
+	}
+	fields := strings.Split(lines[1], "\t")
+	createdAt, err := time.Parse(time.RFC3339Nano, fields[3])
+	if err != nil || fields[2] != "2" || createdAt.Before(before) || createdAt.After(after) {
+		t.Errorf("header row %q: want version 2 and a creation time between %v and %v", lines[1], before, after)
+	}
+}
+
+func TestWriteGuard_RollsBackOnSignal(t *testing.T) {
+	binaryPath := buildCLIBinary(t)
+	dbPath := createTestDatabase(t, binaryPath)
+
+	guard := newWriteGuard()
+	defer signal.Stop(guard.signals)
+	db, err := pkg_frozendb.NewFrozenDB(dbPath, pkg_frozendb.MODE_WRITE, pkg_frozendb.FinderStrategySimple)
+	if err != nil {
+		t.Fatalf("NewFrozenDB: %v", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	// No signal yet: nothing happens
+	if sig, rolledBack, err := guard.rollbackIfInterrupted(db); sig != nil || rolledBack || err != nil {
+		t.Fatalf("rollbackIfInterrupted without a signal = (%v, %v, %v)", sig, rolledBack, err)
+	}
+
+	tx, err := db.BeginTx()
+	if err != nil {
+		t.Fatalf("BeginTx: %v", err)
+	}
+	key := uuid.Must(uuid.NewV7())
+	if err := tx.AddRow(key, json.RawMessage(`{"v":1}`)); err != nil {
+		t.Fatalf("AddRow: %v", err)
+	}
+
+	// The guard holds the signal back instead of letting it end the test process
+	if err := syscall.Kill(os.Getpid(), syscall.SIGINT); err != nil {
+		t.Fatalf("Kill: %v", err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	var sig os.Signal
+	var rolledBack bool
+	for sig == nil && time.Now().Before(deadline) {
+		sig, rolledBack, err = guard.rollbackIfInterrupted(db)
+		if err != nil {
+			t.Fatalf("rollbackIfInterrupted: %v", err)
+		}
+		if sig == nil {
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+	if sig != os.Interrupt || !rolledBack {
+		t.Fatalf("rollbackIfInterrupted = (%v, %v), want (interrupt, true)", sig, rolledBack)
+	}
+	if db.GetActiveTx() != nil {
+		t.Error("transaction still active after the interrupt")
+	}
+	var value map[string]any
+	var notFound *pkg_frozendb.KeyNotFoundError
+	if err := db.Get(key, &value); !errors.As(err, &notFound) {
+		t.Errorf("Get after rollback: expected KeyNotFoundError, got %v", err)
+	}
+}
+
+func TestWriteGuard_SignalAfterCommitSucceeds(t *testing.T) {
+	binaryPath := buildCLIBinary(t)
+	dbPath := createTestDatabase(t, binaryPath)
+
+	guard := newWriteGuard()
+	defer signal.Stop(guard.signals)
+	db, err := pkg_frozendb.NewFrozenDB(dbPath, pkg_frozendb.MODE_WRITE, pkg_frozendb.FinderStrategySimple)
+	if err != nil {
+		t.Fatalf("NewFrozenDB: %v", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	key := uuid.Must(uuid.NewV7())
+	batch, err := db.PrepareBatch(pkg_frozendb.Entry{Key: key, Value: json.RawMessage(`{"v":1}`)})
+	if err != nil {
+		t.Fatalf("PrepareBatch: %v", err)
+	}
+	if err := batch.Commit(); err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+
+	if err := syscall.Kill(os.Getpid(), syscall.SIGINT); err != nil {
+		t.Fatalf("Kill: %v", err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for len(guard.signals) == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if len(guard.signals) == 0 {
+		t.Fatal("signal never reached the guard")
+	}
+
+	// The write already completed, so finish returns instead of exiting 130
+	guard.finish(db, true)
+	var value map[string]any
+	if err := db.Get(key, &value); err != nil {
+		t.Errorf("Get after finish: %v", err)
+	}
+}
+
+func TestBackupAndRestore(t *testing.T) {
+	binaryPath := buildCLIBinary(t)
+	dbPath := createTestDatabase(t, binaryPath)
+	dir := t.TempDir()
+
+	// The restore target starts as the database did when created: the header and
+	// the initial checksum row of the sample database, whose row_size is 256
+	data, err := os.ReadFile(dbPath)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	restoredPath := filepath.Join(dir, "restored.fdb")
+	if err := os.WriteFile(restoredPath, data[:64+256], 0644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	backup := func(name string, args ...string) string {
+		t.Helper()
+		out := filepath.Join(dir, name)
+		stdout, stderr, exitCode := runCLI(t, binaryPath, append([]string{"--path", dbPath, "backup", "--out", out}, args...)...)
+		if exitCode != 0 {
+			t.Fatalf("backup failed with exit code %d. Stderr: %s", exitCode, stderr)
+		}
+		return strings.TrimSpace(stdout)
+	}
+	restore := func(name string) string {
+		t.Helper()
+		stdout, stderr, exitCode := runCLI(t, binaryPath, "--path", restoredPath, "restore", "--apply", filepath.Join(dir, name))
+		if exitCode != 0 {
+			t.Fatalf("restore failed with exit code %d. Stderr: %s", exitCode, stderr)
+		}
+		return strings.TrimSpace(stdout)
+	}
+	requireRestored := func() {
+		t.Helper()
+		want, _ := os.ReadFile(dbPath)
+		got, _ := os.ReadFile(restoredPath)
+		if !bytes.Equal(got, want) {
+			t.Fatalf("restored database (%d bytes) differs from the original (%d bytes)", len(got), len(want))
+		}
+	}
+
+	offset := backup("full.delta")
+	if offset != strconv.Itoa(len(data)) {
+		t.Errorf("backup printed offset %s, want %d", offset, len(data))
+	}
+	if got := restore("full.delta"); got != offset {
+		t.Errorf("restore printed size %s, want %s", got, offset)
+	}
+	requireRestored()
+
+	addRowToDatabase(t, binaryPath, dbPath, uuid.Must(uuid.NewV7()).String(), `{"v":1}`)
+	next := backup("incremental.delta", "--since", offset)
+	if restore("incremental.delta") != next {
+		t.Errorf("restore did not report the new offset %s", next)
+	}
+	requireRestored()
+
+	// Deltas are never overwritten, and a delta that does not start at the end of the file is rejected
+	if _, stderr, exitCode := runCLI(t, binaryPath, "--path", dbPath, "backup", "--out", filepath.Join(dir, "full.delta")); exitCode != 1 || !strings.Contains(stderr, "file exists") {
+		t.Errorf("backup over an existing file: exit code %d, stderr %s", exitCode, stderr)
+	}
+	if _, stderr, exitCode := runCLI(t, binaryPath, "--path", restoredPath, "restore", "--apply", filepath.Join(dir, "full.delta")); exitCode != 1 || !strings.Contains(stderr, "backup delta begins at byte") {
+		t.Errorf("restore of a stale delta: exit code %d, stderr %s", exitCode, stderr)
+	}
+	if _, stderr, exitCode := runCLI(t, binaryPath, "--path", dbPath, "backup", "--since", "100", "--out", filepath.Join(dir, "bad.delta")); exitCode != 1 || !strings.Contains(stderr, "since") {
+		t.Errorf("backup from a misaligned offset: exit code %d, stderr %s", exitCode, stderr)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "bad.delta")); !os.IsNotExist(err) {
+		t.Errorf("failed backup left its output file behind (stat error %v)", err)
+	}
+}
+
+func TestSeed(t *testing.T) {
+	binaryPath := buildCLIBinary(t)
+	dir := t.TempDir()
+
+	seed := func(name string, args ...string) string {
+		t.Helper()
+		dbPath := filepath.Join(dir, name)
+		_, stderr, exitCode := runCLI(t, binaryPath, append([]string{"--path", dbPath, "seed", "--no-immutable"}, args...)...)
+		if exitCode != 0 {
+			t.Fatalf("seed failed with exit code %d. Stderr: %s", exitCode, stderr)
+		}
+		return dbPath
+	}
+
+	// More rows than one transaction holds, so seed commits several
+	first := seed("first.fdb", "--rows", "250")
+	second := seed("second.fdb", "--rows=250")
+	a, _ := os.ReadFile(first)
+	b, _ := os.ReadFile(second)
+	if !bytes.Equal(a, b) {
+		t.Fatal("seeding twice with the same arguments produced different files")
+	}
+
+	if _, stderr, exitCode := runCLI(t, binaryPath, "--path", first, "verify"); exitCode != 0 {
+		t.Errorf("verify failed on the seeded database: %s", stderr)
+	}
+	stdout, stderr, exitCode := runCLI(t, binaryPath, "--path", first, "export", "--count-only")
+	if exitCode != 0 || strings.TrimSpace(stdout) != "250" {
+		t.Errorf("export --count-only = %q (exit code %d, stderr %s), want 250", stdout, exitCode, stderr)
+	}
+	stdout, _, _ = runCLI(t, binaryPath, "--path", first, "head", "1")
+	if !strings.Contains(stdout, `{"id":0,"name":"sample-0","even":true}`) {
+		t.Errorf("head 1 = %q, want the first sample row", stdout)
+	}
+
+	// An existing file is only replaced with --force
+	if _, stderr, exitCode := runCLI(t, binaryPath, "--path", first, "seed", "--no-immutable", "--rows", "1"); exitCode != 1 || !strings.Contains(stderr, "file already exists") {
+		t.Errorf("seed over an existing file: exit code %d, stderr %s", exitCode, stderr)
+	}
+	seed("first.fdb", "--rows", "0", "--force")
+	if _, _, exitCode := runCLI(t, binaryPath, "--path", first, "seed", "--no-immutable"); exitCode != 1 {
+		t.Errorf("seed without --rows: exit code %d, want 1", exitCode)
+	}
+}
+
+func TestReframe(t *testing.T) {
+	binaryPath := buildCLIBinary(t)
+	dir := t.TempDir()
+	src := filepath.Join(dir, "src.fdb")
+	if _, stderr, exitCode := runCLI(t, binaryPath, "--path", src, "seed", "--no-immutable", "--rows", "250"); exitCode != 0 {
+		t.Fatalf("seed failed: %s", stderr)
+	}
+
+	// A key committed twice lands in two transactions of the output, and one
+	// value is too large for 128-byte rows
+	key := uuid.Must(uuid.NewV7()).String()
+	large := uuid.Must(uuid.NewV7()).String()
+	for _, row := range [][2]string{{key, `{"v":1}`}, {key, `{"v":2}`}, {large, `{"text":"` + strings.Repeat("x", 200) + `"}`}} {
+		for _, args := range [][]string{{"begin"}, {"add", row[0], row[1]}, {"commit"}} {
+			if _, stderr, exitCode := runCLI(t, binaryPath, append([]string{"--path", src}, args...)...); exitCode != 0 {
+				t.Fatalf("%v failed: %s", args, stderr)
+			}
+		}
+	}
+
+	dst := filepath.Join(dir, "dst.fdb")
+	if _, stderr, exitCode := runCLI(t, binaryPath, "--path", src, "reframe", "--row-size", "512", "--out", dst, "--no-immutable"); exitCode != 0 {
+		t.Fatalf("reframe failed with exit code %d. Stderr: %s", exitCode, stderr)
+	}
+	if _, stderr, exitCode := runCLI(t, binaryPath, "--path", dst, "verify"); exitCode != 0 {
+		t.Errorf("verify failed on the reframed database: %s", stderr)
+	}
+	if rowSize, err := readRowSize(dst); err != nil || rowSize != 512 {
+		t.Errorf("reframed row size = %d, %v; want 512", rowSize, err)
+	}
+	want, _, _ := runCLI(t, binaryPath, "--path", src, "export")
+	got, stderr, exitCode := runCLI(t, binaryPath, "--path", dst, "export")
+	if exitCode != 0 || got != want {
+		t.Errorf("reframed export differs from the source (exit code %d, stderr %s)", exitCode, stderr)
+	}
+
+	// A value too large for the new row size fails before the output is created
+	if _, stderr, exitCode := runCLI(t, binaryPath, "--path", src, "reframe", "--row-size", "128", "--out", filepath.Join(dir, "small.fdb"), "--no-immutable"); exitCode != 1 || !strings.Contains(stderr, "exceeds the maximum") {
+		t.Errorf("reframe to a too small row size: exit code %d, stderr %s", exitCode, stderr)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "small.fdb")); !os.IsNotExist(err) {
+		t.Errorf("output created despite the failed check: %v", err)
+	}
+
+	// Missing flags and reframing onto the source are rejected
+	for _, args := range [][]string{
+		{"reframe", "--out", filepath.Join(dir, "x.fdb")},
+		{"reframe", "--row-size", "512"},
+		{"reframe", "--row-size", "512", "--out", src, "--force"},
+	} {
+		if _, _, exitCode := runCLI(t, binaryPath, append([]string{"--path", src}, args...)...); exitCode != 1 {
+			t.Errorf("%v: exit code %d, want 1", args, exitCode)
+		}
+	}
+}
+
+func TestReframe_DuplicateKeyOrder(t *testing.T) {
+	binaryPath := buildCLIBinary(t)
+	dir := t.TempDir()
+	src := filepath.Join(dir, "src.fdb")
+	if _, stderr, exitCode := runCLI(t, binaryPath, "--path", src, "seed", "--no-immutable", "--rows", "0"); exitCode != 0 {
+		t.Fatalf("seed failed: %s", stderr)
+	}
+
+	// One key committed four times keeps its occurrences in file order
+	key := uuid.Must(uuid.NewV7()).String()
+	for v := 1; v <= 4; v++ {
+		for _, args := range [][]string{{"begin"}, {"add", key, fmt.Sprintf(`{"v":%d}`, v)}, {"commit"}} {
+			if _, stderr, exitCode := runCLI(t, binaryPath, append([]string{"--path", src}, args...)...); exitCode != 0 {
+				t.Fatalf("%v failed: %s", args, stderr)
+			}
+		}
+	}
+
+	dst := filepath.Join(dir, "dst.fdb")
+	if _, stderr, exitCode := runCLI(t, binaryPath, "--path", src, "reframe", "--row-size", "512", "--out", dst, "--no-immutable"); exitCode != 0 {
+		t.Fatalf("reframe failed with exit code %d. Stderr: %s", exitCode, stderr)
+	}
+	for _, path := range []string{src, dst} {
+		got, stderr, exitCode := runCLI(t, binaryPath, "--path", path, "export")
+		if exitCode != 0 {
+			t.Fatalf("export failed: %s", stderr)
+		}
+		last := -1
+		for v := 1; v <= 4; v++ {
+			at := strings.Index(got, fmt.Sprintf(`{"v":%d}`, v))
+			if at <= last {
+				t.Fatalf("export of %s does not list the occurrences in file order:\n%s", filepath.Base(path), got)
+			}
+			last = at
+		}
+	}
+
+	db, err := pkg_frozendb.NewFrozenDB(dst, pkg_frozendb.MODE_READ, pkg_frozendb.FinderStrategySimple)
+	if err != nil {
+		t.Fatalf("NewFrozenDB: %v", err)
+	}
+	defer db.Close()
+	for _, read := range []struct {
+		name string
+		get  func(key uuid.UUID, value any) error
+		want int
+	}{{"Get", db.Get, 1}, {"GetLatest", db.GetLatest, 4}} {
+		var value map[string]int
+		if err := read.get(uuid.MustParse(key), &value); err != nil || value["v"] != read.want {
+			t.Errorf("%s on the reframed database = %v, %v; want v=%d", read.name, value, err, read.want)
+		}
+	}
+}
+
+func TestSetSkew(t *testing.T) {
+	binaryPath := buildCLIBinary(t)
+	dir := t.TempDir()
+	src := filepath.Join(dir, "src.fdb")
+	if _, stderr, exitCode := runCLI(t, binaryPath, "--path", src, "seed", "--no-immutable", "--rows", "50"); exitCode != 0 {
+		t.Fatalf("seed failed: %s", stderr)
+	}
+	// A rolled back row and an open transaction are copied as they are
+	for _, args := range [][]string{
+		{"begin"}, {"add", "NOW", `{"v":1}`}, {"rollback"},
+		{"begin"}, {"add", "NOW", `{"v":2}`},
+	} {
+		if _, stderr, exitCode := runCLI(t, binaryPath, append([]string{"--path", src}, args...)...); exitCode != 0 {
+			t.Fatalf("%v failed: %s", args, stderr)
+		}
+	}
+	header, err := pkg_frozendb.ReadHeader(src)
+	if err != nil {
+		t.Fatalf("ReadHeader: %v", err)
+	}
+
+	dst := filepath.Join(dir, "dst.fdb")
+	newSkew := header.GetSkewMs() + 60000
+	if _, stderr, exitCode := runCLI(t, binaryPath, "--path", src, "set-skew", "--skew-ms", strconv.Itoa(newSkew), "--out", dst, "--no-immutable"); exitCode != 0 {
+		t.Fatalf("set-skew failed with exit code %d. Stderr: %s", exitCode, stderr)
+	}
+	if _, stderr, exitCode := runCLI(t, binaryPath, "--path", dst, "verify"); exitCode != 0 {
+		t.Errorf("verify failed on the copy: %s", stderr)
+	}
+	if got, err := pkg_frozendb.ReadHeader(dst); err != nil || got.GetSkewMs() != newSkew {
+		t.Errorf("copy header: %v", err)
+	}
+	srcBytes, _ := os.ReadFile(src)
+	dstBytes, _ := os.ReadFile(dst)
+	rowsStart := 64 + header.GetRowSize()
+	if !bytes.Equal(srcBytes[rowsStart:], dstBytes[rowsStart:]) {
+		t.Errorf("rows after the initial checksum row differ between source and copy")
+	}
+
+	// Out of range and missing values are rejected before anything is written
+	for _, args := range [][]string{
+		{"set-skew", "--skew-ms", "86400001", "--out", filepath.Join(dir, "x.fdb")},
+		{"set-skew", "--out", filepath.Join(dir, "x.fdb")},
+		{"set-skew", "--skew-ms", "10"},
+		{"set-skew", "--skew-ms", "10", "--out", src, "--force"},
+	} {
+		if _, _, exitCode := runCLI(t, binaryPath, append([]string{"--path", src}, args...)...); exitCode != 1 {
+			t.Errorf("%v: exit code %d, want 1", args, exitCode)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "x.fdb")); !os.IsNotExist(err) {
+		t.Errorf("output created despite the rejected flags: %v", err)
+	}
+}
+
+func TestInspect_Filters(t *testing.T) {
+	binaryPath := buildCLIBinary(t)
+	dbPath := filepath.Join(t.TempDir(), "filters.fdb")
+	if _, stderr, exitCode := runCLI(t, binaryPath, "--path", dbPath, "seed", "--no-immutable", "--rows", "0"); exitCode != 0 {
+		t.Fatalf("seed failed: %s", stderr)
+	}
+	keys := make([]string, 5)
+	for i := range keys {
+		keys[i] = uuid.Must(uuid.NewV7()).String()
+	}
+	// keys[1] is rolled back to the savepoint after keys[0], keys[3] is fully
+	// rolled back, and keys[4] is left in an open transaction
+	for _, args := range [][]string{
+		{"begin"}, {"add", keys[0], `{"v":0}`}, {"savepoint"}, {"add", keys[1], `{"v":1}`}, {"rollback", "1"},
+		{"begin"}, {"add", keys[2], `{"v":2}`}, {"commit"},
+		{"begin"}, {"add", keys[3], `{"v":3}`}, {"rollback"},
+		{"mark"},
+		{"begin"}, {"add", keys[4], `{"v":4}`},
+	} {
+		if _, stderr, exitCode := runCLI(t, binaryPath, append([]string{"--path", dbPath}, args...)...); exitCode != 0 {
+			t.Fatalf("%v failed: %s", args, stderr)
+		}
+	}
+
+	inspectKeys := func(args ...string) (types, shown []string) {
+		t.Helper()
+		stdout, stderr, exitCode := runCLI(t, binaryPath, append([]string{"--path", dbPath, "inspect"}, args...)...)
+		if exitCode != 0 {
+			t.Fatalf("inspect %v failed with exit code %d. Stderr: %s", args, exitCode, stderr)
+		}
+		for _, line := range strings.Split(strings.TrimSuffix(stdout, "\n"), "\n")[1:] {
+			fields := strings.Split(line, "\t")
+			types = append(types, fields[1])
+			shown = append(shown, fields[2])
+		}
+		return types, shown
+	}
+
+	if types, _ := inspectKeys(); len(types) != 7 {
+		t.Errorf("unfiltered inspect printed %v, want all 7 rows", types)
+	}
+	if _, shown := inspectKeys("--type", "data"); !slices.Equal(shown, keys[:4]) {
+		t.Errorf("--type data keys = %v, want %v", shown, keys[:4])
+	}
+	if types, _ := inspectKeys("--type=NullRow"); !slices.Equal(types, []string{"NullRow"}) {
+		t.Errorf("--type NullRow printed %v", types)
+	}
+	if _, shown := inspectKeys("--type", "Data", "--committed-only"); !slices.Equal(shown, []string{keys[0], keys[2]}) {
+		t.Errorf("--committed-only keys = %v, want %v", shown, []string{keys[0], keys[2]})
+	}
+	if types, _ := inspectKeys("--committed-only"); !slices.Equal(types, []string{"Checksum", "Data", "Data", "NullRow"}) {
+		t.Errorf("--committed-only printed %v", types)
+	}
+	if _, _, exitCode := runCLI(t, binaryPath, "--path", dbPath, "inspect", "--type", "bogus"); exitCode != 1 {
+		t.Errorf("--type bogus: exit code %d, want 1", exitCode)
+	}
+}
+
+func TestMergeImport(t *testing.T) {
+	binaryPath := buildCLIBinary(t)
+	dir := t.TempDir()
+	dbPath := filepath.Join(dir, "merge.fdb")
+	if _, stderr, exitCode := runCLI(t, binaryPath, "--path", dbPath, "seed", "--no-immutable", "--rows", "0"); exitCode != 0 {
+		t.Fatalf("seed failed: %s", stderr)
+	}
+
+	random := rand.New(rand.NewPCG(3, 4))
+	const baseMs = 1_700_000_000_000
+	writeInput := func(name string, fromMs, rows int) (string, []uuid.UUID) {
+		t.Helper()
+		var buf bytes.Buffer
+		var keys []uuid.UUID
+		for i := 0; i < rows; i++ {
+			key := seedKey(int64(baseMs+fromMs+i), random)
+			keys = append(keys, key)
+			fmt.Fprintf(&buf, `{"key":%q,"value":{"i":%d}}`+"\n", key, fromMs+i)
+		}
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+			t.Fatalf("WriteFile: %v", err)
+		}
+		return path, keys
+	}
+	countRows := func() string {
+		t.Helper()
+		stdout, stderr, exitCode := runCLI(t, binaryPath, "--path", dbPath, "export", "--count-only")
+		if exitCode != 0 {
+			t.Fatalf("export --count-only failed: %s", stderr)
+		}
+		return strings.TrimSpace(stdout)
+	}
+
+	// 250 rows over two files span three transactions
+	first, firstKeys := writeInput("first.jsonl", 0, 150)
+	second, secondKeys := writeInput("second.jsonl", 150, 100)
+	if _, stderr, exitCode := runCLI(t, binaryPath, "--path", dbPath, "merge-import", first, second); exitCode != 0 {
+		t.Fatalf("merge-import failed with exit code %d. Stderr: %s", exitCode, stderr)
+	}
+	if _, stderr, exitCode := runCLI(t, binaryPath, "--path", dbPath, "verify"); exitCode != 0 {
+		t.Errorf("verify failed after merge-import: %s", stderr)
+	}
+	if got := countRows(); got != "250" {
+		t.Errorf("export --count-only = %s, want 250", got)
+	}
+	stdout, _, _ := runCLI(t, binaryPath, "--path", dbPath, "export")
+	var exported []uuid.UUID
+	for _, line := range strings.Split(strings.TrimSpace(stdout), "\n") {
+		var l exportLine
+		if err := json.Unmarshal([]byte(line), &l); err != nil {
+			t.Fatalf("export line %q: %v", line, err)
+		}
+		exported = append(exported, l.Key)
+	}
+	if want := append(firstKeys, secondKeys...); !slices.Equal(exported, want) {
+		t.Errorf("exported keys differ from the imported keys")
+	}
+
+	// Overlapping files, keys out of order within a file, and keys behind the
+	// database's max_timestamp are rejected before anything is written
+	later, _ := writeInput("later.jsonl", 400, 10)
+	overlap, _ := writeInput("overlap.jsonl", 395, 10)
+	laterBytes, err := os.ReadFile(later)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(laterBytes)), "\n")
+	lines[2], lines[3] = lines[3], lines[2]
+	unordered := filepath.Join(dir, "unordered.jsonl")
+	if err := os.WriteFile(unordered, []byte(strings.Join(lines, "\n")), 0644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	behind, _ := writeInput("behind.jsonl", -10_000, 1)
+	for _, tc := range []struct {
+		files []string
+		want  string
+	}{
+		{[]string{later, overlap}, "overlap.jsonl line 1: key"},
+		{[]string{unordered}, "unordered.jsonl line 4: key"},
+		{[]string{behind}, "behind.jsonl line 1: key timestamp"},
+	} {
+		_, stderr, exitCode := runCLI(t, binaryPath, append([]string{"--path", dbPath, "merge-import"}, tc.files...)...)
+		if exitCode != 1 || !strings.Contains(stderr, tc.want) {
+			t.Errorf("merge-import %v: exit code %d, stderr %q; want %q", tc.files, exitCode, stderr, tc.want)
+		}
+	}
+	if got := countRows(); got != "250" {
+		t.Errorf("export --count-only = %s after rejected imports, want 250", got)
+	}
+
+	if _, stderr, exitCode := runCLI(t, binaryPath, "--path", dbPath, "merge-import"); exitCode != 1 || !strings.Contains(stderr, "at least one input file") {
+		t.Errorf("merge-import without files: exit code %d, stderr %s", exitCode, stderr)
+	}
+}
+
+func TestErrorExitCode(t *testing.T) {
+	exitCodes = true
+	defer func() { exitCodes = false }()
+	for _, tc := range []struct {
+		err  error
+		want int
+	}{
+		{pkg_frozendb.NewKeyNotFoundError("missing", nil), exitKeyNotFound},
+		{pkg_frozendb.NewWriteError("lock held", nil), exitWriteError},
+		{pkg_frozendb.NewCorruptDatabaseError("bad row", nil), exitCorrupt},
+		{pkg_frozendb.NewInvalidInputError("bad flag", nil), exitInvalidInput},
+		{pkg_frozendb.NewPathError("no such file", nil), exitError},
+		{errors.New("plain"), exitError},
+		// The outermost error with its own code decides
+		{pkg_frozendb.NewInvalidInputError("batch failed", pkg_frozendb.NewCorruptDatabaseError("bad row", nil)), exitInvalidInput},
+		{pkg_frozendb.NewPathError("open", pkg_frozendb.NewWriteError("lock held", nil)), exitWriteError},
+		{fmt.Errorf("wrapped: %w", pkg_frozendb.NewKeyNotFoundError("missing", nil)), exitKeyNotFound},
+	} {
+		if got := errorExitCode(tc.err); got != tc.want {
+			t.Errorf("errorExitCode(%v) = %d, want %d", tc.err, got, tc.want)
+		}
+	}
+	exitCodes = false
+	if got := errorExitCode(pkg_frozendb.NewKeyNotFoundError("missing", nil)); got != exitError {
+		t.Errorf("errorExitCode without --exit-codes = %d, want %d", got, exitError)
+	}
+}
+
+func TestExitCodes(t *testing.T) {
+	binaryPath := buildCLIBinary(t)
+	dbPath := createTestDatabase(t, binaryPath)
+	missing := uuid.Must(uuid.NewV7()).String()
+
+	for _, tc := range []struct {
+		args []string
+		want int
+	}{
+		{[]string{"get", missing}, exitKeyNotFound},
+		{[]string{"get", "not-a-uuid"}, exitInvalidInput},
+		{[]string{"frobnicate"}, exitInvalidInput},
+	} {
+		args := append([]string{"--path", dbPath}, tc.args...)
+		if _, _, exitCode := runCLI(t, binaryPath, args...); exitCode != 1 {
+			t.Errorf("%v: exit code %d, want 1 without --exit-codes", tc.args, exitCode)
+		}
+		if _, _, exitCode := runCLI(t, binaryPath, append([]string{"--exit-codes"}, args...)...); exitCode != tc.want {
+			t.Errorf("--exit-codes %v: exit code %d, want %d", tc.args, exitCode, tc.want)
+		}
+	}
+
+	// The flag is accepted in any position, including by create
+	if _, _, exitCode := runCLI(t, binaryPath, "create", "--exit-codes", "--checksum-interval", "x", filepath.Join(t.TempDir(), "x.fdb")); exitCode != exitInvalidInput {
+		t.Errorf("create --exit-codes with an invalid flag: exit code %d, want %d", exitCode, exitInvalidInput)
+	}
+
+	data, err := os.ReadFile(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to read database: %v", err)
+	}
+	data[64+256+256-3] ^= 0x01 // Break the parity of the first data row (row_size 256)
+	if err := os.WriteFile(dbPath, data, 0644); err != nil {
+		t.Fatalf("Failed to corrupt database: %v", err)
+	}
+	if _, stderr, exitCode := runCLI(t, binaryPath, "--path", dbPath, "verify", "--exit-codes"); exitCode != exitCorrupt {
+		t.Errorf("verify --exit-codes on a corrupt database: exit code %d, want %d. Stderr: %s", exitCode, exitCorrupt, stderr)
+	}
+}
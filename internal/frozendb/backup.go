@@ -0,0 +1,170 @@
+package frozendb
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// BACKUP_DELTA_SIGNATURE identifies the header line of a backup delta.
+const BACKUP_DELTA_SIGNATURE = "fDBdelta"
+
+// backupDeltaHeader is the JSON line that starts a backup delta. The file bytes
+// [Since, Until) of the database follow it.
+type backupDeltaHeader struct {
+	Sig   string `json:"sig"`
+	Ver   int    `json:"ver"`
+	Since int64  `json:"since"`
+	Until int64  `json:"until"`
+}
+
+// WriteBackupDelta writes an incremental backup of the database file at path to
+// w: a one-line JSON header recording the byte range, followed by the file bytes
+// from offset since to the current end of the file. Because the file is
+// append-only, those bytes are exactly what was added after a previous backup
+// that ended at since, and applying the deltas in order with ApplyBackupDelta
+// reproduces the file.
+//
+// since must lie where a writer can stop: at a row boundary after the initial
+// checksum row, or at a point where a writer pauses within a data row (after its
+// start_control, its payload, or a savepoint marker), which is where a backup of
+// a database with a transaction in progress ends. A since of 0 selects
+// HEADER_SIZE + row_size, backing up every row a writer appended; the header and
+// initial checksum row before it are fixed by Create, so the first delta restores
+// onto a copy of the file as created.
+//
+// Parameters:
+//   - path: Filesystem path to the database file (opened in MODE_READ)
+//   - since: Byte offset to copy from, usually the value a previous call returned,
+//     or 0 for every appended row
+//   - w: Destination of the delta
+//   - opts: Optional OpenOption values, such as WithLogger
+//
+// Returns:
+//   - int64: File size the delta ends at; pass it as since to the next backup
+//   - error: InvalidInputError (since is beyond the file or not where a writer
+//     stops), WriteError (w fails), ReadError, PathError, or CorruptDatabaseError
+func WriteBackupDelta(path string, since int64, w io.Writer, opts ...OpenOption) (int64, error) {
+	dbFile, err := newDBFile(path, MODE_READ, newOpenOptions(opts))
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = dbFile.Close() }()
+
+	header, err := validateDatabaseFile(dbFile)
+	if err != nil {
+		return 0, err
+	}
+	until := dbFile.Size()
+	if since == 0 {
+		since = HEADER_SIZE + int64(header.GetRowSize())
+	}
+	if err := checkDeltaStart(dbFile, header, since, until); err != nil {
+		return 0, err
+	}
+
+	line, err := json.Marshal(backupDeltaHeader{Sig: BACKUP_DELTA_SIGNATURE, Ver: 1, Since: since, Until: until})
+	if err != nil {
+		return 0, NewWriteError("failed to encode backup delta header", err)
+	}
+	if _, err := w.Write(append(line, '\n')); err != nil {
+		return 0, NewWriteError("failed to write backup delta header", err)
+	}
+	for start := since; start < until; {
+		chunk := min(until-start, int64(replicationReadSize))
+		data, err := dbFile.Read(start, int32(chunk))
+		if err != nil {
+			return 0, NewReadError(fmt.Sprintf("failed to read %d bytes at offset %d", chunk, start), err)
+		}
+		if _, err := w.Write(data); err != nil {
+			return 0, NewWriteError("failed to write backup delta", err)
+		}
+		start += chunk
+	}
+	return until, nil
+}
+
+// checkDeltaStart reports an InvalidInputError unless offset since of a file of
+// size bytes is a point a writer stops at.
+func checkDeltaStart(dbFile DBFile, header *Header, since, size int64) error {
+	rowSize := int64(header.GetRowSize())
+	if since < HEADER_SIZE+rowSize || since > size {
+		return NewInvalidInputError(fmt.Sprintf("since must be between %d and the file size %d, got %d", HEADER_SIZE+rowSize, size, since), nil)
+	}
+	within := (since - HEADER_SIZE) % rowSize
+	if within == 0 {
+		return nil
+	}
+	rowStart := since - within
+	fragment, err := dbFile.Read(rowStart, int32(within))
+	if err != nil {
+		return NewReadError(fmt.Sprintf("failed to read the row at offset %d", rowStart), err)
+	}
+	var partialRow PartialDataRow
+	if !isPartialRowLength(int(within), int(rowSize)) || partialRow.UnmarshalText(fragment) != nil {
+		return NewInvalidInputError(fmt.Sprintf("since %d falls %d bytes into the row at offset %d, where no writer stops", since, within, rowStart), nil)
+	}
+	return nil
+}
+
+// ApplyBackupDelta appends a delta written by WriteBackupDelta to the database
+// file at path, which must end exactly where the delta begins: a restored copy
+// that the previous deltas were applied to, in order. Every row is validated as
+// it is appended, like ApplyReplicationStream does: framing, parity, checksum rows
+// at their positions and matching the bytes they cover, and a trailing partial
+// row in a state a writer produces.
+//
+// Parameters:
+//   - path: Filesystem path to the database file (opened in MODE_WRITE)
+//   - r: The delta
+//   - opts: Optional OpenOption values, such as WithoutLock or WithLogger
+//
+// Returns:
+//   - int64: File size after the delta was applied, equal to the delta's end offset
+//   - error: InvalidInputError (not a backup delta, or the file does not end where
+//     the delta begins), CorruptDatabaseError (a row fails validation, or the delta
+//     is shorter or longer than its header states), ReadError, PathError, or WriteError
+func ApplyBackupDelta(path string, r io.Reader, opts ...OpenOption) (int64, error) {
+	br := bufio.NewReader(r)
+	line, err := br.ReadBytes('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return 0, NewReadError("failed to read backup delta header", err)
+	}
+	var delta backupDeltaHeader
+	if err := json.Unmarshal(line, &delta); err != nil || delta.Sig != BACKUP_DELTA_SIGNATURE {
+		return 0, NewInvalidInputError("input is not a frozenDB backup delta", err)
+	}
+	if delta.Ver != 1 {
+		return 0, NewInvalidInputError(fmt.Sprintf("unsupported backup delta version %d", delta.Ver), nil)
+	}
+	if delta.Until < delta.Since {
+		return 0, NewInvalidInputError(fmt.Sprintf("backup delta ends at %d before it begins at %d", delta.Until, delta.Since), nil)
+	}
+
+	dbFile, err := newDBFile(path, MODE_WRITE, newOpenOptions(opts))
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = dbFile.Close() }()
+
+	header, err := validateDatabaseFile(dbFile)
+	if err != nil {
+		return 0, err
+	}
+	if size := dbFile.Size(); size != delta.Since {
+		return 0, NewInvalidInputError(fmt.Sprintf("backup delta begins at byte %d but the database has %d bytes", delta.Since, size), nil)
+	}
+
+	if err := applyStream(dbFile, header, io.LimitReader(br, delta.Until-delta.Since)); err != nil {
+		return 0, err
+	}
+	if size := dbFile.Size(); size != delta.Until {
+		return 0, NewCorruptDatabaseError(fmt.Sprintf("backup delta is truncated: applied up to byte %d of %d", size, delta.Until), nil)
+	}
+	if _, err := br.ReadByte(); !errors.Is(err, io.EOF) {
+		return 0, NewCorruptDatabaseError(fmt.Sprintf("backup delta holds data past byte %d", delta.Until), err)
+	}
+	return delta.Until, nil
+}
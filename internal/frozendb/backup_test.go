@@ -0,0 +1,111 @@
+package frozendb
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+)
+
+// backupDelta returns the delta of path from since and the offset it ends at
+func backupDelta(t *testing.T, path string, since int64) ([]byte, int64) {
+	t.Helper()
+	var delta bytes.Buffer
+	until, err := WriteBackupDelta(path, since, &delta)
+	if err != nil {
+		t.Fatalf("WriteBackupDelta(%d): %v", since, err)
+	}
+	return delta.Bytes(), until
+}
+
+func requireSameBytes(t *testing.T, a, b string) {
+	t.Helper()
+	aBytes, _ := os.ReadFile(a)
+	bBytes, _ := os.ReadFile(b)
+	if !bytes.Equal(aBytes, bBytes) {
+		t.Fatalf("%s (%d bytes) differs from %s (%d bytes)", b, len(bBytes), a, len(aBytes))
+	}
+}
+
+func TestBackupDelta_RestoresIncrementally(t *testing.T) {
+	primary, restored := setupReplicaPair(t)
+
+	db := replicateRows(t, primary, &bytes.Buffer{}, [][]int{{1000, 1001}, {2000}})
+	tx := db.GetActiveTx()
+
+	// First delta: everything after the initial checksum row, ending within the
+	// partial row of the open transaction
+	delta, until := backupDelta(t, primary, 0)
+	if (until-HEADER_SIZE)%confRowSize == 0 {
+		t.Fatalf("expected the first delta to end within a row, got offset %d", until)
+	}
+	got, err := ApplyBackupDelta(restored, bytes.NewReader(delta))
+	if err != nil || got != until {
+		t.Fatalf("ApplyBackupDelta = (%d, %v), want (%d, nil)", got, err, until)
+	}
+	requireSameBytes(t, primary, restored)
+
+	// Second delta resumes within that row and crosses a checksum row
+	for i := 0; i < 90; i++ {
+		mustAdd(t, tx, uuidFromTS(3000+i), `{"v":2}`)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+	tx, err = db.BeginTx()
+	if err != nil {
+		t.Fatalf("BeginTx: %v", err)
+	}
+	for i := 0; i < 20; i++ {
+		mustAdd(t, tx, uuidFromTS(4000+i), `{"v":3}`)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+	if err := db.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	delta2, until2 := backupDelta(t, primary, until)
+	if got, err := ApplyBackupDelta(restored, bytes.NewReader(delta2)); err != nil || got != until2 {
+		t.Fatalf("ApplyBackupDelta = (%d, %v), want (%d, nil)", got, err, until2)
+	}
+	requireSameBytes(t, primary, restored)
+
+	// A delta already applied no longer lines up with the end of the file
+	var invalidInput *InvalidInputError
+	if _, err := ApplyBackupDelta(restored, bytes.NewReader(delta)); !errors.As(err, &invalidInput) {
+		t.Errorf("re-applying a delta: expected InvalidInputError, got %v", err)
+	}
+
+	// An empty delta at the end of the file is a no-op
+	delta3, until3 := backupDelta(t, primary, until2)
+	if got, err := ApplyBackupDelta(restored, bytes.NewReader(delta3)); err != nil || got != until2 || until3 != until2 {
+		t.Errorf("empty delta: got (%d, %v), want (%d, nil)", got, err, until2)
+	}
+}
+
+func TestBackupDelta_Validation(t *testing.T) {
+	primary, restored := setupReplicaPair(t)
+	db := replicateRows(t, primary, &bytes.Buffer{}, [][]int{{1000, 1001, 1002}, {2000}})
+	_ = db.Close()
+	size := int64(HEADER_SIZE + 5*confRowSize)
+
+	var invalidInput *InvalidInputError
+	for _, since := range []int64{-1, HEADER_SIZE, HEADER_SIZE + confRowSize + 7, size + 1} {
+		if _, err := WriteBackupDelta(primary, since, &bytes.Buffer{}); !errors.As(err, &invalidInput) {
+			t.Errorf("WriteBackupDelta(since %d): expected InvalidInputError, got %v", since, err)
+		}
+	}
+
+	if _, err := ApplyBackupDelta(restored, strings.NewReader("not a delta\n")); !errors.As(err, &invalidInput) {
+		t.Errorf("ApplyBackupDelta(garbage): expected InvalidInputError, got %v", err)
+	}
+
+	// A delta cut short is reported after the rows it does hold are applied
+	delta, _ := backupDelta(t, primary, HEADER_SIZE+confRowSize)
+	var corrupt *CorruptDatabaseError
+	if _, err := ApplyBackupDelta(restored, bytes.NewReader(delta[:len(delta)-confRowSize])); !errors.As(err, &corrupt) {
+		t.Errorf("truncated delta: expected CorruptDatabaseError, got %v", err)
+	}
+}
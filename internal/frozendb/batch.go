@@ -0,0 +1,167 @@
+package frozendb
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// MAX_BATCH_ENTRIES is the most entries a PreparedBatch can hold: the number of
+// rows a single transaction can contain.
+const MAX_BATCH_ENTRIES = MAX_TRANSACTION_ROWS
+
+// Entry is a key-value pair to add to the database.
+type Entry struct {
+	Key   uuid.UUID       // UUIDv7 key
+	Value json.RawMessage // JSON value
+}
+
+// PreparedBatch is a batch of entries validated in memory by PrepareBatch, ready to
+// be written as one transaction by Commit.
+type PreparedBatch struct {
+	db        *FrozenDB
+	entries   []Entry
+	mu        sync.Mutex
+	committed bool
+}
+
+// PrepareBatch validates entries as a single transaction's worth of rows without
+// touching the file, so that input mistakes are reported before anything is written
+// and cannot leave a tombstoned transaction behind.
+//
+// Every entry is checked, and all problems are returned together: each key must be
+// a UUIDv7 that appears once in the batch, keys must be in non-decreasing timestamp
+// order, each value must be non-empty valid JSON that fits in a row and satisfies
+// the value schema and row validator (if set), and the batch must hold between 1 and
+// MAX_BATCH_ENTRIES entries. Entries are copied, so the caller may reuse them.
+//
+// Parameters:
+//   - entries: Rows to add, in the order they will be written
+//
+// Returns:
+//   - *PreparedBatch: The validated batch, written by its Commit method
+//   - error: InvalidInputError listing every problem found; errors.As also finds
+//     the InvalidDataError or DuplicateKeyError of individual entries
+//
+// Thread Safety: Safe for concurrent calls on the same FrozenDB instance
+func (db *FrozenDB) PrepareBatch(entries ...Entry) (*PreparedBatch, error) {
+	db.txMu.RLock()
+	schema := db.valueSchema
+	validator := db.rowValidator
+	db.txMu.RUnlock()
+
+	var problems []error
+	if len(entries) == 0 {
+		problems = append(problems, NewInvalidInputError("batch must contain at least one entry", nil))
+	}
+	if len(entries) > MAX_BATCH_ENTRIES {
+		problems = append(problems, NewInvalidInputError(
+			fmt.Sprintf("batch has %d entries; a transaction holds at most %d rows", len(entries), MAX_BATCH_ENTRIES), nil))
+	}
+
+	rowSize := db.header.GetRowSize()
+	seen := make(map[uuid.UUID]int, len(entries))
+	prevTimestamp := int64(-1)
+	for i, e := range entries {
+		if err := ValidateUUIDv7(e.Key); err != nil {
+			problems = append(problems, NewInvalidInputError(fmt.Sprintf("entry %d: invalid UUIDv7 key", i), err))
+		} else {
+			if first, ok := seen[e.Key]; ok {
+				problems = append(problems, NewDuplicateKeyError(fmt.Sprintf("entry %d: key %s was already used by entry %d", i, e.Key, first), nil))
+			} else {
+				seen[e.Key] = i
+			}
+			ts := ExtractUUIDv7Timestamp(e.Key)
+			if ts < prevTimestamp {
+				problems = append(problems, NewInvalidInputError(
+					fmt.Sprintf("entry %d: key timestamp %d is before the previous entry's %d; keys must be in non-decreasing order", i, ts, prevTimestamp), nil))
+			} else {
+				prevTimestamp = ts
+			}
+		}
+
+		switch {
+		case len(e.Value) == 0:
+			problems = append(problems, NewInvalidInputError(fmt.Sprintf("entry %d: value cannot be empty", i), nil))
+		case !json.Valid(e.Value):
+			problems = append(problems, NewInvalidDataError(fmt.Sprintf("entry %d: value is not valid JSON", i), nil))
+		case isTombstone(e.Value):
+			problems = append(problems, NewInvalidInputError(fmt.Sprintf("entry %d: value is reserved for Delete", i), nil))
+		default:
+			stored, err := CompressValue(db.header.GetValueCompression(), e.Value)
+			if err == nil {
+				err = validatePayloadSize(&DataRowPayload{Key: e.Key, Value: stored}, rowSize)
+			}
+			if err != nil {
+				problems = append(problems, NewInvalidInputError(fmt.Sprintf("entry %d: value does not fit in a row", i), err))
+			} else if schema != nil {
+				if err := schema.validate(e.Value); err != nil {
+					problems = append(problems, NewInvalidDataError(fmt.Sprintf("entry %d: value violates the value schema", i), err))
+				}
+			}
+			if err := validateRow(validator, e.Key, e.Value); err != nil {
+				problems = append(problems, NewInvalidDataError(fmt.Sprintf("entry %d: row rejected by the row validator", i), err))
+			}
+		}
+	}
+
+	if len(problems) > 0 {
+		return nil, NewInvalidInputError(fmt.Sprintf("batch failed validation with %d problem(s)", len(problems)), errors.Join(problems...))
+	}
+
+	copied := make([]Entry, len(entries))
+	for i, e := range entries {
+		copied[i] = Entry{Key: e.Key, Value: append(json.RawMessage(nil), e.Value...)}
+	}
+	return &PreparedBatch{db: db, entries: copied}, nil
+}
+
+// Len returns the number of entries in the batch.
+func (b *PreparedBatch) Len() int {
+	return len(b.entries)
+}
+
+// Commit writes the batch as one transaction, through Submit, so it is serialized
+// with other Submit calls on the handle. Before beginning the transaction it checks
+// the first key against the rows already in the database, which PrepareBatch could
+// not do, so a batch whose keys are now too old fails without writing anything.
+//
+// A failure adding a row after the transaction has begun (for example, another
+// writer committed newer keys in between) rolls the transaction back. An I/O failure
+// tombstones the transaction as with any write. A batch can be committed only once.
+//
+// Returns:
+//   - error: KeyOrderingError (keys too old for the database), InvalidActionError
+//     (already committed, read-mode handle, or another transaction active), or any
+//     error from AddRow or Commit
+//
+// Thread Safety: Safe for concurrent use
+func (b *PreparedBatch) Commit() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.committed {
+		return NewInvalidActionError("batch was already committed", nil)
+	}
+
+	first := ExtractUUIDv7Timestamp(b.entries[0].Key)
+	if first+int64(b.db.header.GetSkewMs()) <= b.db.finder.MaxTimestamp() {
+		return NewKeyOrderingError("UUID timestamp violates ordering constraint: new_timestamp + skew_ms must be > max_timestamp", nil)
+	}
+
+	err := b.db.Submit(func(tx *Transaction) error {
+		for _, e := range b.entries {
+			if err := tx.AddRow(e.Key, e.Value); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	b.committed = true
+	return nil
+}
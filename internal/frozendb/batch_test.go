@@ -0,0 +1,132 @@
+package frozendb
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestPrepareBatch_CommitsEntries(t *testing.T) {
+	path := setupCreate(t, t.TempDir(), confSkewMs)
+	db, err := NewFrozenDB(path, MODE_WRITE, FinderStrategySimple)
+	if err != nil {
+		t.Fatalf("NewFrozenDB: %v", err)
+	}
+	defer db.Close()
+
+	entries := make([]Entry, MAX_BATCH_ENTRIES)
+	for i := range entries {
+		entries[i] = Entry{Key: uuidFromTS(1000 + i), Value: json.RawMessage(`{"n":1}`)}
+	}
+	batch, err := db.PrepareBatch(entries...)
+	if err != nil {
+		t.Fatalf("PrepareBatch: %v", err)
+	}
+	if batch.Len() != MAX_BATCH_ENTRIES {
+		t.Errorf("Len() = %d, want %d", batch.Len(), MAX_BATCH_ENTRIES)
+	}
+	if err := batch.Commit(); err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+	var value map[string]any
+	for i := range entries {
+		if err := db.Get(entries[i].Key, &value); err != nil {
+			t.Errorf("Get entry %d: %v", i, err)
+		}
+	}
+
+	var invalidAction *InvalidActionError
+	if err := batch.Commit(); !errors.As(err, &invalidAction) {
+		t.Errorf("second Commit: expected InvalidActionError, got %v", err)
+	}
+}
+
+func TestPrepareBatch_ReportsEveryProblem(t *testing.T) {
+	path := setupCreate(t, t.TempDir(), confSkewMs)
+	db, err := NewFrozenDB(path, MODE_WRITE, FinderStrategySimple)
+	if err != nil {
+		t.Fatalf("NewFrozenDB: %v", err)
+	}
+	defer db.Close()
+	if err := db.SetValueSchema([]byte(`{"type":"object","required":["type"]}`)); err != nil {
+		t.Fatalf("SetValueSchema: %v", err)
+	}
+	info, _ := os.Stat(path)
+
+	_, err = db.PrepareBatch(
+		Entry{Key: uuidFromTS(2000), Value: json.RawMessage(`{"type":"a"}`)},
+		Entry{Key: uuid.New(), Value: json.RawMessage(`{"type":"a"}`)},                                     // not UUIDv7
+		Entry{Key: uuidFromTS(2000), Value: json.RawMessage(`{"type":"a"}`)},                               // duplicate
+		Entry{Key: uuidFromTS(1000), Value: json.RawMessage(`{"type":"a"}`)},                               // out of order
+		Entry{Key: uuidFromTS(3000), Value: json.RawMessage(`{"type":`)},                                   // invalid JSON
+		Entry{Key: uuidFromTS(3001), Value: nil},                                                           // empty
+		Entry{Key: uuidFromTS(3002), Value: json.RawMessage(`{"other":1}`)},                                // schema
+		Entry{Key: uuidFromTS(3003), Value: json.RawMessage(`"` + strings.Repeat("x", confRowSize) + `"`)}, // too large
+	)
+	var invalidInput *InvalidInputError
+	if !errors.As(err, &invalidInput) {
+		t.Fatalf("expected InvalidInputError, got %v", err)
+	}
+	for _, want := range []string{"entry 1:", "entry 2:", "entry 3:", "entry 4:", "entry 5:", "entry 6:", "entry 7:", "7 problem(s)"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error does not mention %q: %v", want, err)
+		}
+	}
+	var duplicate *DuplicateKeyError
+	var invalidData *InvalidDataError
+	if !errors.As(err, &duplicate) || !errors.As(err, &invalidData) {
+		t.Errorf("expected DuplicateKeyError and InvalidDataError in the chain, got %v", err)
+	}
+
+	if _, err := db.PrepareBatch(); !errors.As(err, &invalidInput) {
+		t.Errorf("empty batch: expected InvalidInputError, got %v", err)
+	}
+	tooMany := make([]Entry, MAX_BATCH_ENTRIES+1)
+	for i := range tooMany {
+		tooMany[i] = Entry{Key: uuidFromTS(4000 + i), Value: json.RawMessage(`{"type":"a"}`)}
+	}
+	if _, err := db.PrepareBatch(tooMany...); !errors.As(err, &invalidInput) {
+		t.Errorf("oversized batch: expected InvalidInputError, got %v", err)
+	}
+
+	if after, _ := os.Stat(path); after.Size() != info.Size() {
+		t.Errorf("file grew from %d to %d bytes during validation", info.Size(), after.Size())
+	}
+	if db.GetActiveTx() != nil {
+		t.Errorf("validation must not begin a transaction")
+	}
+}
+
+func TestPreparedBatch_CommitRejectsStaleKeysWithoutWriting(t *testing.T) {
+	path := setupCreate(t, t.TempDir(), confSkewMs)
+	db, err := NewFrozenDB(path, MODE_WRITE, FinderStrategySimple)
+	if err != nil {
+		t.Fatalf("NewFrozenDB: %v", err)
+	}
+	defer db.Close()
+
+	batch, err := db.PrepareBatch(Entry{Key: uuidFromTS(1000), Value: json.RawMessage(`{}`)})
+	if err != nil {
+		t.Fatalf("PrepareBatch: %v", err)
+	}
+	newer, err := db.PrepareBatch(Entry{Key: uuidFromTS(1000 + 2*confSkewMs), Value: json.RawMessage(`{}`)})
+	if err != nil {
+		t.Fatalf("PrepareBatch: %v", err)
+	}
+	if err := newer.Commit(); err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+	info, _ := os.Stat(path)
+
+	var ordering *KeyOrderingError
+	if err := batch.Commit(); !errors.As(err, &ordering) {
+		t.Fatalf("expected KeyOrderingError, got %v", err)
+	}
+	if after, _ := os.Stat(path); after.Size() != info.Size() {
+		t.Errorf("rejected batch wrote %d bytes", after.Size()-info.Size())
+	}
+}
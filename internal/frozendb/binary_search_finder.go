@@ -22,13 +22,15 @@ import (
 // Memory Usage: O(row_size) - constant regardless of database size
 // Performance: O(log n) for GetIndex, O(k) for transaction boundary methods where k <= 101
 type BinarySearchFinder struct {
-	dbFile        DBFile     // Database file interface for reading rows
-	rowSize       int32      // Size of each row in bytes from header
-	size          int64      // Confirmed file size (updated via OnRowAdded)
-	maxTimestamp  int64      // Maximum timestamp among all complete data and null rows
-	skewMs        int64      // Time skew window in milliseconds from database header
-	tombstonedErr error      // Error that caused this Finder to be tombstoned (nil if not tombstoned)
-	mu            sync.Mutex // Protects size, maxTimestamp, skewMs, and tombstonedErr fields for concurrent access
+	dbFile        DBFile        // Database file interface for reading rows
+	rowSize       int32         // Size of each row in bytes from header
+	size          int64         // Confirmed file size (updated via OnRowAdded)
+	maxTimestamp  int64         // Maximum timestamp among all complete data and null rows
+	skewMs        int64         // Time skew window in milliseconds from database header
+	interval      int64         // Data and null rows between checksum rows, from database header
+	tombstonedErr error         // Error that caused this Finder to be tombstoned (nil if not tombstoned)
+	readAhead     *rowReadAhead // Serves readRow when WithFinderReadAhead is set (nil otherwise)
+	mu            sync.Mutex    // Protects size, maxTimestamp, skewMs, and tombstonedErr fields for concurrent access
 }
 
 // NewBinarySearchFinder creates a new BinarySearchFinder instance.
@@ -48,14 +50,14 @@ func NewBinarySearchFinder(dbFile DBFile, rowSize int32, rowEmitter *RowEmitter)
 	if dbFile == nil {
 		return nil, NewInvalidInputError("dbFile cannot be nil", nil)
 	}
-	if rowSize < 128 || rowSize > 65536 {
-		return nil, NewInvalidInputError(fmt.Sprintf("rowSize must be between 128 and 65536, got %d", rowSize), nil)
+	if rowSize < MIN_ROW_SIZE || rowSize > MAX_ROW_SIZE {
+		return nil, NewInvalidInputError(fmt.Sprintf("rowSize must be between %d and %d, got %d", MIN_ROW_SIZE, MAX_ROW_SIZE, rowSize), nil)
 	}
 	if rowEmitter == nil {
 		return nil, NewInvalidInputError("rowEmitter cannot be nil", nil)
 	}
 
-	// Read header to get skewMs
+	// Read header to get skewMs and the checksum interval
 	headerBytes, err := dbFile.Read(0, HEADER_SIZE)
 	if err != nil {
 		return nil, NewCorruptDatabaseError("failed to read header", err)
@@ -78,9 +80,10 @@ func NewBinarySearchFinder(dbFile DBFile, rowSize int32, rowEmitter *RowEmitter)
 		size:         dbFile.Size(),
 		maxTimestamp: 0,
 		skewMs:       int64(header.GetSkewMs()),
+		interval:     int64(header.GetChecksumInterval()),
 	}
 
-	// Initialize maxTimestamp by scanning existing rows
+	// Initialize maxTimestamp from the rows at the end of the file
 	if err := bsf.initializeMaxTimestamp(); err != nil {
 		return nil, err
 	}
@@ -94,53 +97,18 @@ func NewBinarySearchFinder(dbFile DBFile, rowSize int32, rowEmitter *RowEmitter)
 	return bsf, nil
 }
 
-// initializeMaxTimestamp scans all existing rows to find the maximum timestamp.
-// This is called once during initialization to establish the baseline maxTimestamp.
+// initializeMaxTimestamp derives the maximum timestamp from the tail of the file
+// (see tailMaxTimestamp), so opening a large database does not scan it.
 func (bsf *BinarySearchFinder) initializeMaxTimestamp() error {
 	bsf.mu.Lock()
 	defer bsf.mu.Unlock()
 
-	confirmedSize := bsf.size
-	totalRows := (confirmedSize - HEADER_SIZE) / int64(bsf.rowSize)
-
-	bsf.maxTimestamp = 0
-
-	// Scan all rows to find maximum timestamp
-	for i := int64(0); i < totalRows; i++ {
-		rowBytes, err := bsf.readRow(i)
-		if err != nil {
-			// Read error during initialization - fail immediately
-			return err
-		}
-
-		var rowUnion RowUnion
-		if err := rowUnion.UnmarshalText(rowBytes); err != nil {
-			// Skip corrupted rows
-			continue
-		}
-
-		// Only consider complete DataRow and NullRow entries
-		if rowUnion.DataRow != nil {
-			key := rowUnion.DataRow.GetKey()
-			if key != uuid.Nil {
-				if err := ValidateUUIDv7(key); err == nil {
-					timestamp := ExtractUUIDv7Timestamp(key)
-					if timestamp > bsf.maxTimestamp {
-						bsf.maxTimestamp = timestamp
-					}
-				}
-			}
-		} else if rowUnion.NullRow != nil {
-			// Extract timestamp from NullRow key and compare, same as DataRow
-			key := rowUnion.NullRow.GetKey()
-			timestamp := ExtractUUIDv7Timestamp(key)
-			if timestamp > bsf.maxTimestamp {
-				bsf.maxTimestamp = timestamp
-			}
-		}
-		// Skip ChecksumRow and PartialDataRow
+	totalRows := (bsf.size - HEADER_SIZE) / int64(bsf.rowSize)
+	maxTimestamp, err := tailMaxTimestamp(totalRows, bsf.skewMs, bsf.readRow)
+	if err != nil {
+		return err
 	}
-
+	bsf.maxTimestamp = maxTimestamp
 	return nil
 }
 
@@ -218,6 +186,12 @@ func (bsf *BinarySearchFinder) GetIndex(key uuid.UUID) (int64, error) {
 	// Map logical index back to physical row index
 	physicalIndex := bsf.logicalToPhysicalIndex(logicalIndex)
 
+	// A key written more than once resolves to its first occurrence
+	physicalIndex, err = firstOccurrence(key, bsf.skewMs, physicalIndex, bsf.readRowKey)
+	if err != nil {
+		return -1, err
+	}
+
 	// Verify the found row actually contains the key
 	rowBytes, err := bsf.readRow(physicalIndex)
 	if err != nil {
@@ -241,12 +215,28 @@ func (bsf *BinarySearchFinder) GetIndex(key uuid.UUID) (int64, error) {
 	return physicalIndex, nil
 }
 
+// GetIndexes returns the index of every row containing key, in file order,
+// reading forward from the first occurrence GetIndex finds until the skew window
+// of key has passed.
+//
+// Time Complexity: O(log n), plus the rows written within skew_ms of key
+func (bsf *BinarySearchFinder) GetIndexes(key uuid.UUID, skewMs int64) ([]int64, error) {
+	first, err := bsf.GetIndex(key)
+	if err != nil {
+		return nil, err
+	}
+	bsf.mu.Lock()
+	totalRows := (bsf.size - HEADER_SIZE) / int64(bsf.rowSize)
+	bsf.mu.Unlock()
+	return laterOccurrences([]int64{first}, key, skewMs, first, totalRows, bsf.readRowKey)
+}
+
 // countLogicalRows calculates the number of logical rows (DataRows and NullRows)
 // given the total number of physical rows, excluding checksum rows.
 //
-// Checksum rows occur at physical indices: 0, 10001, 20002, 30003, ...
-// Pattern: checksum at index = k * 10001 for k >= 0
-// Number of checksum rows up to (and including) index N = floor(N / 10001) + 1 (if N >= 0)
+// Checksum rows occur at physical indices: 0, interval+1, 2*(interval+1), ...
+// (0, 10001, 20002, ... with the default interval of 10,000)
+// Number of checksum rows up to (and including) index N = floor(N / (interval+1)) + 1 (if N >= 0)
 //
 // Parameters:
 //   - totalRows: Total number of physical rows (including checksum rows)
@@ -257,18 +247,16 @@ func (bsf *BinarySearchFinder) countLogicalRows(totalRows int64) int64 {
 	if totalRows == 0 {
 		return 0
 	}
-	// Count checksum rows: checksum at indices 0, 10001, 20002, 30003, ...
-	// Pattern: checksum at index = k * 10001 for k >= 0
-	// Number of checksum rows up to index N = floor(N / 10001) + 1
-	numChecksumRows := (totalRows-1)/10001 + 1
+	numChecksumRows := (totalRows-1)/(bsf.interval+1) + 1
 	return totalRows - numChecksumRows
 }
 
 // logicalToPhysicalIndex converts a logical index (used by FuzzyBinarySearch) to a
 // physical row index in the database file.
 //
-// Formula: physicalIndex = logicalIndex + floor(logicalIndex / 10000) + 1
-// This accounts for checksum rows at indices: 0, 10001, 20002, 30003, ...
+// Formula: physicalIndex = logicalIndex + floor(logicalIndex / interval) + 1
+// This accounts for checksum rows at indices: 0, interval+1, 2*(interval+1), ...
+// With the default interval of 10,000 these are 0, 10001, 20002, ...
 //
 // Parameters:
 //   - logicalIndex: Index in the logical contiguous array (includes DataRows and NullRows)
@@ -276,7 +264,7 @@ func (bsf *BinarySearchFinder) countLogicalRows(totalRows int64) int64 {
 // Returns:
 //   - int64: Physical row index accounting for checksum rows
 func (bsf *BinarySearchFinder) logicalToPhysicalIndex(logicalIndex int64) int64 {
-	return logicalIndex + (logicalIndex / 10000) + 1
+	return logicalIndex + (logicalIndex / bsf.interval) + 1
 }
 
 // getLogicalKey is an adapter function for FuzzyBinarySearch that returns the UUID key
@@ -453,6 +441,11 @@ func (bsf *BinarySearchFinder) GetTransactionEnd(index int64) (int64, error) {
 			continue
 		}
 
+		// A transaction cannot start before the current one ends
+		if bsf.rowStartsTransaction(row) {
+			return -1, newInterleavedTransactionError(index, i)
+		}
+
 		// Check if this row ends a transaction
 		if bsf.rowEndsTransaction(row) {
 			return i, nil
@@ -533,13 +526,27 @@ func (bsf *BinarySearchFinder) MaxTimestamp() int64 {
 	return bsf.maxTimestamp
 }
 
+// setReadAhead makes readRow read window rows at a time through a rowReadAhead.
+// It must be called before the finder is used.
+func (bsf *BinarySearchFinder) setReadAhead(window int) {
+	bsf.readAhead = newRowReadAhead(bsf.dbFile, bsf.rowSize, window)
+}
+
 // readRow reads a single row from disk at the specified index.
 // Helper method for internal use.
 func (bsf *BinarySearchFinder) readRow(index int64) ([]byte, error) {
+	if bsf.readAhead != nil {
+		return bsf.readAhead.readRow(index)
+	}
 	offset := HEADER_SIZE + index*int64(bsf.rowSize)
 	return bsf.dbFile.Read(offset, bsf.rowSize)
 }
 
+// readRowKey reads the key prefix of the row at index, implementing rowKeyReader.
+func (bsf *BinarySearchFinder) readRowKey(index int64) ([]byte, error) {
+	return readRowKeyPrefix(bsf.dbFile, bsf.readAhead, bsf.rowSize, index)
+}
+
 // readRowUnion reads and parses a row as RowUnion.
 // Helper method for internal use.
 func (bsf *BinarySearchFinder) readRowUnion(index int64) (*RowUnion, error) {
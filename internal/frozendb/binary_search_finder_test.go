@@ -1,12 +1,15 @@
 package frozendb
 
 import (
+	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
 )
 
 // TestBinarySearchFinder_LogicalToPhysicalIndex tests the logical to physical index mapping.
 func TestBinarySearchFinder_LogicalToPhysicalIndex(t *testing.T) {
-	bsf := &BinarySearchFinder{}
+	bsf := &BinarySearchFinder{interval: CHECKSUM_INTERVAL}
 
 	tests := []struct {
 		name         string
@@ -67,7 +70,7 @@ func TestBinarySearchFinder_LogicalToPhysicalIndex(t *testing.T) {
 
 // TestBinarySearchFinder_CountLogicalRows tests the logical row counting function.
 func TestBinarySearchFinder_CountLogicalRows(t *testing.T) {
-	bsf := &BinarySearchFinder{}
+	bsf := &BinarySearchFinder{interval: CHECKSUM_INTERVAL}
 
 	tests := []struct {
 		name        string
@@ -134,7 +137,7 @@ func TestBinarySearchFinder_CountLogicalRows(t *testing.T) {
 // TestBinarySearchFinder_IndexMappingAlignment validates that numLogicalRows,
 // logicalIndex, and physicalIndex all align properly.
 func TestBinarySearchFinder_IndexMappingAlignment(t *testing.T) {
-	bsf := &BinarySearchFinder{}
+	bsf := &BinarySearchFinder{interval: CHECKSUM_INTERVAL}
 
 	tests := []struct {
 		name        string
@@ -223,7 +226,7 @@ func TestBinarySearchFinder_IndexMappingAlignment(t *testing.T) {
 // TestBinarySearchFinder_IndexMappingRoundTrip validates that the mapping
 // is consistent for edge cases around checksum boundaries.
 func TestBinarySearchFinder_IndexMappingRoundTrip(t *testing.T) {
-	bsf := &BinarySearchFinder{}
+	bsf := &BinarySearchFinder{interval: CHECKSUM_INTERVAL}
 
 	// Test around checksum boundaries
 	boundaryTests := []struct {
@@ -275,3 +278,111 @@ func TestBinarySearchFinder_IndexMappingRoundTrip(t *testing.T) {
 		})
 	}
 }
+
+// readCountingFile counts the rows read through a DBFile.
+type readCountingFile struct {
+	DBFile
+	reads int
+}
+
+func (f *readCountingFile) Read(start int64, size int32) ([]byte, error) {
+	f.reads++
+	return f.DBFile.Read(start, size)
+}
+
+// TestBinarySearchFinder_MaxTimestampFromTail opens a database spanning several
+// checksum rows in write mode and requires max_timestamp to come from the last
+// committed key, read without scanning the file. Keys are further apart than
+// skew_ms, so the last two rows settle it.
+func TestBinarySearchFinder_MaxTimestampFromTail(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tail.fdb")
+	createWithInterval(t, path, MIN_CHECKSUM_INTERVAL)
+
+	db, err := NewFrozenDB(path, MODE_WRITE, FinderStrategySimple)
+	if err != nil {
+		t.Fatalf("NewFrozenDB: %v", err)
+	}
+	ts := 1000
+	step := confSkewMs + 1000
+	for txNum := 0; txNum < 7; txNum++ {
+		tx, err := db.BeginTx()
+		if err != nil {
+			t.Fatalf("BeginTx: %v", err)
+		}
+		for i := 0; i < 50; i++ {
+			ts += step
+			mustAdd(t, tx, uuidFromTS(ts), fmt.Sprintf(`{"n":%d}`, ts))
+		}
+		if err := tx.Commit(); err != nil {
+			t.Fatalf("Commit: %v", err)
+		}
+	}
+	db.Close()
+
+	db, err = NewFrozenDB(path, MODE_WRITE, FinderStrategyBinarySearch)
+	if err != nil {
+		t.Fatalf("NewFrozenDB(binary_search): %v", err)
+	}
+	if got := db.finder.MaxTimestamp(); got != int64(ts) {
+		t.Errorf("MaxTimestamp() = %d, want last committed key timestamp %d", got, ts)
+	}
+	db.Close()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	if rows := (len(data) - HEADER_SIZE) / confRowSize; rows < 3*(MIN_CHECKSUM_INTERVAL+1) {
+		t.Fatalf("expected at least 3 checksum intervals, got %d rows", rows)
+	}
+	file := &readCountingFile{DBFile: NewMemFileManager(data, MODE_READ)}
+	emitter, err := NewRowEmitter(file, confRowSize)
+	if err != nil {
+		t.Fatalf("NewRowEmitter: %v", err)
+	}
+	reads := file.reads
+	finder, err := NewBinarySearchFinder(file, confRowSize, emitter)
+	if err != nil {
+		t.Fatalf("NewBinarySearchFinder: %v", err)
+	}
+	// The header, then the last two rows
+	if got := file.reads - reads; got != 3 {
+		t.Errorf("NewBinarySearchFinder read %d times, want 3", got)
+	}
+	if got := finder.MaxTimestamp(); got != int64(ts) {
+		t.Errorf("MaxTimestamp() = %d, want %d", got, ts)
+	}
+}
+
+// TestBinarySearchFinder_MaxTimestampWithinSkew requires the tail walk to find a
+// max_timestamp that is not the last key, when later keys fall back within skew_ms.
+func TestBinarySearchFinder_MaxTimestampWithinSkew(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "skew.fdb")
+	createWithInterval(t, path, MIN_CHECKSUM_INTERVAL)
+
+	db, err := NewFrozenDB(path, MODE_WRITE, FinderStrategySimple)
+	if err != nil {
+		t.Fatalf("NewFrozenDB: %v", err)
+	}
+	tx, err := db.BeginTx()
+	if err != nil {
+		t.Fatalf("BeginTx: %v", err)
+	}
+	for _, ts := range []int{10000, 20000, 19000, 17000, 16000} {
+		mustAdd(t, tx, uuidFromTS(ts), `{}`)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+	want := db.finder.MaxTimestamp()
+	db.Close()
+
+	db, err = NewFrozenDB(path, MODE_WRITE, FinderStrategyBinarySearch)
+	if err != nil {
+		t.Fatalf("NewFrozenDB(binary_search): %v", err)
+	}
+	defer db.Close()
+	if got := db.finder.MaxTimestamp(); got != want || want != 20000 {
+		t.Errorf("MaxTimestamp() = %d, want %d (simple finder: %d)", got, 20000, want)
+	}
+}
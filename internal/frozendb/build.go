@@ -0,0 +1,181 @@
+package frozendb
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"io"
+
+	"github.com/google/uuid"
+)
+
+// BuildDatabase writes a complete database file to w: the header and initial
+// checksum row that Create writes, followed by entries committed in transactions of
+// MAX_TRANSACTION_ROWS rows, with checksum rows interleaved where a writer places
+// them. The output is byte-identical to creating the database with cfg and adding
+// the same entries through BeginTx, AddRow, and Commit, so it can generate test
+// fixtures and golden files without a FileManager, a lock, or sudo.
+//
+// Only the row_size, skew_ms, and checksum interval of cfg are used; its path is
+// ignored. Entries must satisfy the rules AddRow enforces: UUIDv7 keys within the
+// skew_ms ordering window, no key repeated within one transaction, and non-empty
+// values that fit in a row. Values are written as given, as with AddRow. Nothing is
+// written to w unless every entry is valid.
+//
+// Parameters:
+//   - w: Destination of the file bytes
+//   - cfg: Configuration the database would be created with
+//   - entries: Rows to commit, in order
+//
+// Returns:
+//   - error: InvalidInputError (invalid configuration or entry), KeyOrderingError,
+//     DuplicateKeyError, or WriteError if w fails
+func BuildDatabase(w io.Writer, cfg CreateConfig, entries []Entry) error {
+	header := cfg.header()
+	if err := header.Validate(); err != nil {
+		return err
+	}
+	values, err := validateBuildEntries(header, entries)
+	if err != nil {
+		return err
+	}
+
+	headerBytes, err := header.MarshalText()
+	if err != nil {
+		return NewWriteError("failed to generate header", err)
+	}
+	checksumRow, err := newInitialChecksumRow(header, headerBytes)
+	if err != nil {
+		return NewWriteError("failed to create checksum row", err)
+	}
+	checksumBytes, err := checksumRow.MarshalText()
+	if err != nil {
+		return NewWriteError("failed to marshal checksum row", err)
+	}
+	if _, err := w.Write(headerBytes); err != nil {
+		return NewWriteError("failed to write header", err)
+	}
+
+	b := &databaseBuilder{w: w, header: header, crc: crc32.NewIEEE()}
+	if err := b.writeRow(checksumBytes); err != nil {
+		return err
+	}
+
+	for start := 0; start < len(entries); start += MAX_TRANSACTION_ROWS {
+		end := min(start+MAX_TRANSACTION_ROWS, len(entries))
+		for i := start; i < end; i++ {
+			row := &DataRow{
+				baseRow[*DataRowPayload]{
+					RowSize:      header.GetRowSize(),
+					StartControl: ROW_CONTINUE,
+					EndControl:   ROW_END_CONTROL,
+					RowPayload:   &DataRowPayload{Key: entries[i].Key, Value: values[i]},
+				},
+			}
+			if i == start {
+				row.StartControl = START_TRANSACTION
+			}
+			if i == end-1 {
+				row.EndControl = TRANSACTION_COMMIT
+			}
+			rowBytes, err := row.MarshalText()
+			if err != nil {
+				return NewInvalidInputError(fmt.Sprintf("failed to marshal entry %d", i), err)
+			}
+			if err := b.writeDataRow(rowBytes); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// validateBuildEntries applies AddRow's checks to entries as BuildDatabase will
+// group them into transactions, returning the value to store for each entry
+// (compressed if the header asks for it).
+func validateBuildEntries(header *Header, entries []Entry) ([]json.RawMessage, error) {
+	skewMs := int64(header.GetSkewMs())
+	maxTimestamp := int64(0)
+	values := make([]json.RawMessage, len(entries))
+	var txKeys map[uuid.UUID]bool
+	for i, e := range entries {
+		if i%MAX_TRANSACTION_ROWS == 0 {
+			txKeys = make(map[uuid.UUID]bool, MAX_TRANSACTION_ROWS)
+		}
+		if err := ValidateUUIDv7(e.Key); err != nil {
+			return nil, NewInvalidInputError(fmt.Sprintf("entry %d: invalid UUIDv7 key", i), err)
+		}
+		if len(e.Value) == 0 {
+			return nil, NewInvalidInputError(fmt.Sprintf("entry %d: value cannot be empty", i), nil)
+		}
+		if isTombstone(e.Value) {
+			return nil, NewInvalidInputError(fmt.Sprintf("entry %d: value is reserved for Delete", i), nil)
+		}
+		value, err := CompressValue(header.GetValueCompression(), e.Value)
+		if err != nil {
+			return nil, NewInvalidInputError(fmt.Sprintf("entry %d: failed to compress value", i), err)
+		}
+		if err := validatePayloadSize(&DataRowPayload{Key: e.Key, Value: value}, header.GetRowSize()); err != nil {
+			return nil, NewInvalidInputError(fmt.Sprintf("entry %d: value does not fit in a row", i), err)
+		}
+		values[i] = value
+		if txKeys[e.Key] {
+			return nil, NewDuplicateKeyError(fmt.Sprintf("entry %d: key %s was already added in this transaction", i, e.Key), nil)
+		}
+		txKeys[e.Key] = true
+
+		ts := ExtractUUIDv7Timestamp(e.Key)
+		if err := checkKeyOrder(i, ts, maxTimestamp, skewMs); err != nil {
+			return nil, err
+		}
+		maxTimestamp = max(maxTimestamp, ts)
+	}
+	return values, nil
+}
+
+// databaseBuilder writes rows to a stream, inserting checksum rows at the
+// positions the checksum interval requires.
+type databaseBuilder struct {
+	w      io.Writer
+	header *Header
+	index  int64       // Index of the next row
+	crc    hash.Hash32 // CRC32 of the rows since the most recent checksum row
+}
+
+// writeRow writes a complete row and adds it to the running checksum.
+func (b *databaseBuilder) writeRow(row []byte) error {
+	if _, err := b.w.Write(row); err != nil {
+		return NewWriteError(fmt.Sprintf("failed to write row %d", b.index), err)
+	}
+	b.crc.Write(row)
+	b.index++
+	return nil
+}
+
+// writeDataRow writes a data row, followed by a checksum row if it completes a
+// checksum interval.
+func (b *databaseBuilder) writeDataRow(row []byte) error {
+	if err := b.writeRow(row); err != nil {
+		return err
+	}
+	if b.index%int64(b.header.GetChecksumInterval()+1) != 0 {
+		return nil
+	}
+
+	checksum := Checksum(b.crc.Sum32())
+	checksumRow := &ChecksumRow{
+		baseRow: baseRow[*Checksum]{
+			RowSize:      b.header.GetRowSize(),
+			StartControl: CHECKSUM_ROW,
+			EndControl:   CHECKSUM_ROW_CONTROL,
+			RowPayload:   &checksum,
+		},
+	}
+	checksumBytes, err := checksumRow.MarshalText()
+	if err != nil {
+		return NewWriteError("failed to marshal checksum row", err)
+	}
+	b.crc.Reset()
+	return b.writeRow(checksumBytes)
+}
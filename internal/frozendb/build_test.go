@@ -0,0 +1,101 @@
+package frozendb
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// createWithInterval creates a database through Create with the given checksum interval.
+func createWithInterval(t *testing.T, path string, interval int) CreateConfig {
+	t.Helper()
+	setupMockSyscalls(false, false)
+	t.Cleanup(restoreRealSyscalls)
+	t.Setenv("SUDO_USER", MOCK_USER)
+	t.Setenv("SUDO_UID", MOCK_UID)
+	t.Setenv("SUDO_GID", MOCK_GID)
+	config := NewCreateConfig(path, confRowSize, confSkewMs)
+	config.SetChecksumInterval(interval)
+	if err := Create(config); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	return config
+}
+
+func TestBuildDatabase_MatchesWritePath(t *testing.T) {
+	for _, n := range []int{0, 1, MAX_TRANSACTION_ROWS, 250} {
+		t.Run(fmt.Sprintf("%d_entries", n), func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "written.fdb")
+			config := createWithInterval(t, path, MIN_CHECKSUM_INTERVAL)
+
+			entries := make([]Entry, n)
+			for i := range entries {
+				entries[i] = Entry{Key: uuidFromTS(1000 + i), Value: json.RawMessage(fmt.Sprintf(`{"i":%d}`, i))}
+			}
+
+			db, err := NewFrozenDB(path, MODE_WRITE, FinderStrategySimple)
+			if err != nil {
+				t.Fatalf("NewFrozenDB: %v", err)
+			}
+			for start := 0; start < n; start += MAX_TRANSACTION_ROWS {
+				tx, err := db.BeginTx()
+				if err != nil {
+					t.Fatalf("BeginTx: %v", err)
+				}
+				for _, e := range entries[start:min(start+MAX_TRANSACTION_ROWS, n)] {
+					if err := tx.AddRow(e.Key, e.Value); err != nil {
+						t.Fatalf("AddRow: %v", err)
+					}
+				}
+				if err := tx.Commit(); err != nil {
+					t.Fatalf("Commit: %v", err)
+				}
+			}
+			_ = db.Close()
+
+			var built bytes.Buffer
+			if err := BuildDatabase(&built, config, entries); err != nil {
+				t.Fatalf("BuildDatabase: %v", err)
+			}
+			written, _ := os.ReadFile(path)
+			if !bytes.Equal(built.Bytes(), written) {
+				t.Fatalf("built database (%d bytes) differs from written database (%d bytes)", built.Len(), len(written))
+			}
+		})
+	}
+}
+
+func TestBuildDatabase_RejectsInvalidEntries(t *testing.T) {
+	config := NewCreateConfig("unused.fdb", confRowSize, confSkewMs)
+	value := json.RawMessage(`{}`)
+	tests := []struct {
+		name    string
+		entries []Entry
+		target  any
+	}{
+		{"empty value", []Entry{{Key: uuidFromTS(1000)}}, new(*InvalidInputError)},
+		{"key ordering", []Entry{{Key: uuidFromTS(1000 + 2*confSkewMs), Value: value}, {Key: uuidFromTS(1000), Value: value}}, new(*KeyOrderingError)},
+		{"duplicate in transaction", []Entry{{Key: uuidFromTS(1000), Value: value}, {Key: uuidFromTS(1000), Value: value}}, new(*DuplicateKeyError)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var built bytes.Buffer
+			err := BuildDatabase(&built, config, tt.entries)
+			if !errors.As(err, tt.target) {
+				t.Fatalf("expected %T, got %v", tt.target, err)
+			}
+			if built.Len() != 0 {
+				t.Errorf("wrote %d bytes for invalid entries", built.Len())
+			}
+		})
+	}
+
+	var invalidInput *InvalidInputError
+	if err := BuildDatabase(&bytes.Buffer{}, NewCreateConfig("unused.fdb", 1, confSkewMs), nil); !errors.As(err, &invalidInput) {
+		t.Errorf("invalid row size: expected InvalidInputError, got %v", err)
+	}
+}
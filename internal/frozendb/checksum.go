@@ -2,6 +2,7 @@ package frozendb
 
 import (
 	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"hash/crc32"
 )
@@ -31,7 +32,7 @@ func (c *Checksum) UnmarshalText(text []byte) error {
 	if len(text) != 8 {
 		return NewInvalidInputError(fmt.Sprintf("Checksum Base64 must be exactly 8 bytes, got %d", len(text)), nil)
 	}
-	decoded, err := base64.StdEncoding.DecodeString(string(text))
+	decoded, err := base64.StdEncoding.Strict().DecodeString(string(text))
 	if err != nil {
 		return NewInvalidInputError("invalid Base64 encoding for checksum", err)
 	}
@@ -52,6 +53,57 @@ func (c Checksum) Validate() error {
 // ChecksumRow represents a checksum integrity row in frozenDB
 type ChecksumRow struct {
 	baseRow[*Checksum] // Embedded with typed *Checksum payload
+
+	// Extension is the JSON object written after the checksum in the initial
+	// checksum row of a version 2 file (v1_file_format.md section 6.1), or nil
+	Extension []byte
+}
+
+// checksumExtensionOverhead is the number of bytes of a checksum row that an
+// extension cannot use: ROW_START, start_control, the 8-character checksum, at
+// least one NULL_BYTE of padding before end_control, end_control, parity, and ROW_END.
+const checksumExtensionOverhead = 17
+
+// extendedChecksum is the payload of a checksum row with an extension: the
+// 8-character checksum immediately followed by the extension JSON object.
+type extendedChecksum struct {
+	checksum  Checksum
+	extension []byte
+}
+
+// MarshalText converts the checksum and extension to the payload bytes
+func (p *extendedChecksum) MarshalText() ([]byte, error) {
+	checksumBytes, err := p.checksum.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+	return append(checksumBytes, p.extension...), nil
+}
+
+// UnmarshalText parses an 8-character checksum followed by an extension
+func (p *extendedChecksum) UnmarshalText(text []byte) error {
+	if len(text) <= 8 {
+		return NewInvalidInputError(fmt.Sprintf("extended checksum payload must be longer than 8 bytes, got %d", len(text)), nil)
+	}
+	if err := p.checksum.UnmarshalText(text[:8]); err != nil {
+		return err
+	}
+	p.extension = append([]byte(nil), text[8:]...)
+	return p.Validate()
+}
+
+// Validate checks that the extension is a JSON object
+func (p *extendedChecksum) Validate() error {
+	return validateChecksumExtension(p.extension)
+}
+
+// validateChecksumExtension checks that extension is a single JSON object
+// with nothing before or after it.
+func validateChecksumExtension(extension []byte) error {
+	if len(extension) < 2 || extension[0] != '{' || extension[len(extension)-1] != '}' || !json.Valid(extension) {
+		return NewInvalidInputError("checksum row extension must be a JSON object", nil)
+	}
+	return nil
 }
 
 // NewChecksumRow creates a new checksum row from header and data bytes
@@ -68,7 +120,7 @@ func NewChecksumRow(rowSize int, dataBytes []byte) (*ChecksumRow, error) {
 
 	// Create checksum row
 	cr := &ChecksumRow{
-		baseRow[*Checksum]{
+		baseRow: baseRow[*Checksum]{
 			RowSize:      rowSize,
 			StartControl: CHECKSUM_ROW,
 			EndControl:   CHECKSUM_ROW_CONTROL,
@@ -97,11 +149,41 @@ func (cr *ChecksumRow) GetChecksum() Checksum {
 
 // MarshalText serializes ChecksumRow to exact byte format per v1_file_format.md
 func (cr *ChecksumRow) MarshalText() ([]byte, error) {
-	return cr.baseRow.MarshalText()
+	if cr.Extension == nil {
+		return cr.baseRow.MarshalText()
+	}
+	if err := cr.Validate(); err != nil {
+		return nil, err
+	}
+	extended := baseRow[*extendedChecksum]{
+		RowSize:      cr.RowSize,
+		StartControl: cr.StartControl,
+		EndControl:   cr.EndControl,
+		RowPayload:   &extendedChecksum{checksum: *cr.RowPayload, extension: cr.Extension},
+	}
+	return extended.MarshalText()
 }
 
 // UnmarshalText deserializes ChecksumRow from byte array with validation
 func (cr *ChecksumRow) UnmarshalText(text []byte) error {
+	cr.Extension = nil
+
+	// A payload byte after the 8-character checksum starts an extension
+	if len(text) > 10 && text[10] != NULL_BYTE {
+		var extended baseRow[*extendedChecksum]
+		if err := extended.UnmarshalText(text); err != nil {
+			return err
+		}
+		cr.baseRow = baseRow[*Checksum]{
+			RowSize:      extended.RowSize,
+			StartControl: extended.StartControl,
+			EndControl:   extended.EndControl,
+			RowPayload:   &extended.RowPayload.checksum,
+		}
+		cr.Extension = extended.RowPayload.extension
+		return cr.Validate()
+	}
+
 	// This will parse StartControl and EndControl from the text
 	// baseRow.UnmarshalText() will call baseRow.Validate() internally
 	if err := cr.baseRow.UnmarshalText(text); err != nil {
@@ -128,6 +210,16 @@ func (cr *ChecksumRow) Validate() error {
 
 	// Checksum is universally valid (uint32 is always valid), no validation needed
 
+	if cr.Extension != nil {
+		if err := validateChecksumExtension(cr.Extension); err != nil {
+			return err
+		}
+		if len(cr.Extension) > cr.RowSize-checksumExtensionOverhead {
+			return NewInvalidInputError(fmt.Sprintf("checksum row extension of %d bytes does not fit in row_size %d (maximum %d)",
+				len(cr.Extension), cr.RowSize, cr.RowSize-checksumExtensionOverhead), nil)
+		}
+	}
+
 	return nil
 }
 
@@ -293,7 +293,7 @@ func TestChecksumRow_validate(t *testing.T) {
 			name: "nil payload",
 			setup: func() *ChecksumRow {
 				return &ChecksumRow{
-					baseRow[*Checksum]{
+					baseRow: baseRow[*Checksum]{
 						RowSize:      1024,
 						StartControl: CHECKSUM_ROW,
 						EndControl:   CHECKSUM_ROW_CONTROL,
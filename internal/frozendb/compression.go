@@ -0,0 +1,109 @@
+package frozendb
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// ValueCompression selects how row values are stored. It is fixed when the
+// database is created and recorded in the header, so every reader knows whether
+// to decompress.
+type ValueCompression string
+
+const (
+	ValueCompressionNone ValueCompression = "none" // Values are stored as written
+	ValueCompressionGzip ValueCompression = "gzip" // Values are gzip-compressed
+)
+
+// HEADER_VALUE_COMPRESSION_FORMAT is appended inside the header JSON object when
+// values are compressed. The key and codes are abbreviated so the field fits in
+// the fixed 64-byte header alongside row_size and skew_ms.
+const HEADER_VALUE_COMPRESSION_FORMAT = `,"vc":%q`
+
+// headerCompressionCodes maps each compression to the code stored in the header.
+// ValueCompressionNone is never written: headers without "vc" are uncompressed.
+var headerCompressionCodes = map[ValueCompression]string{
+	ValueCompressionGzip: "gz",
+}
+
+// valueCompressionFromCode returns the compression recorded by a header "vc" code.
+func valueCompressionFromCode(code string) (ValueCompression, bool) {
+	if code == "" {
+		return ValueCompressionNone, true
+	}
+	for c, hc := range headerCompressionCodes {
+		if hc == code {
+			return c, true
+		}
+	}
+	return "", false
+}
+
+// validateValueCompression checks that c is a supported compression. The empty
+// string is accepted as ValueCompressionNone.
+func validateValueCompression(c ValueCompression) error {
+	if c == "" || c == ValueCompressionNone {
+		return nil
+	}
+	if _, ok := headerCompressionCodes[c]; !ok {
+		return NewInvalidInputError(fmt.Sprintf("unsupported value compression %q: expected %q or %q", c, ValueCompressionNone, ValueCompressionGzip), nil)
+	}
+	return nil
+}
+
+// CompressValue returns the bytes stored in a row for value. Compressed values
+// are stored as a JSON string holding the base64-encoded compressed bytes, so the
+// row payload stays printable and free of the NULL bytes used for padding. The
+// row's control bytes and parity cover these stored bytes.
+func CompressValue(c ValueCompression, value json.RawMessage) (json.RawMessage, error) {
+	if c != ValueCompressionGzip {
+		return value, nil
+	}
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(value); err != nil {
+		return nil, NewInvalidInputError("failed to compress value", err)
+	}
+	if err := zw.Close(); err != nil {
+		return nil, NewInvalidInputError("failed to compress value", err)
+	}
+	stored := make([]byte, 0, base64.StdEncoding.EncodedLen(buf.Len())+2)
+	stored = append(stored, '"')
+	stored = base64.StdEncoding.AppendEncode(stored, buf.Bytes())
+	stored = append(stored, '"')
+	return stored, nil
+}
+
+// DecompressValue returns the JSON value held by the stored bytes of a row,
+// reversing CompressValue. Stored bytes that do not decode are reported as
+// CorruptDatabaseError, since the header promised compressed values.
+func DecompressValue(c ValueCompression, stored json.RawMessage) (json.RawMessage, error) {
+	if c != ValueCompressionGzip {
+		return stored, nil
+	}
+	if len(stored) < 2 || stored[0] != '"' || stored[len(stored)-1] != '"' {
+		return nil, NewCorruptDatabaseError("compressed value is not a JSON string", nil)
+	}
+	compressed, err := base64.StdEncoding.DecodeString(string(stored[1 : len(stored)-1]))
+	if err != nil {
+		return nil, NewCorruptDatabaseError("compressed value is not valid base64", err)
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		return nil, NewCorruptDatabaseError("failed to decompress value", err)
+	}
+	value, err := io.ReadAll(zr)
+	if err != nil {
+		return nil, NewCorruptDatabaseError("failed to decompress value", err)
+	}
+	return value, nil
+}
+
+// decodeValue returns the JSON value held by the stored bytes of a row of db.
+func (db *FrozenDB) decodeValue(stored json.RawMessage) (json.RawMessage, error) {
+	return DecompressValue(db.header.GetValueCompression(), stored)
+}
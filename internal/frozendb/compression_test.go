@@ -0,0 +1,185 @@
+package frozendb
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestHeader_ValueCompressionRoundTrip(t *testing.T) {
+	h := &Header{signature: HEADER_SIGNATURE, version: FORMAT_VERSION_EXTENDED, rowSize: 4096, skewMs: 5000, valueCompression: ValueCompressionGzip}
+	if err := h.Validate(); err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+	text, err := h.MarshalText()
+	if err != nil {
+		t.Fatalf("MarshalText: %v", err)
+	}
+	if !bytes.HasPrefix(text, []byte(`{"sig":"fDB","ver":2,"row_size":4096,"skew_ms":5000,"vc":"gz"}`)) {
+		t.Errorf("unexpected header %q", text)
+	}
+	parsed := &Header{}
+	if err := parsed.UnmarshalText(text); err != nil {
+		t.Fatalf("UnmarshalText: %v", err)
+	}
+	if parsed.GetValueCompression() != ValueCompressionGzip {
+		t.Errorf("GetValueCompression() = %q, want %q", parsed.GetValueCompression(), ValueCompressionGzip)
+	}
+
+	// "none" is not written, keeping existing headers byte-identical
+	none := &Header{signature: HEADER_SIGNATURE, version: 1, rowSize: 4096, skewMs: 5000, valueCompression: ValueCompressionNone}
+	if text, _ := none.MarshalText(); bytes.Contains(text, []byte(`"vc"`)) {
+		t.Errorf("no compression should be omitted, got %q", text)
+	}
+	legacy := &Header{}
+	if err := legacy.UnmarshalText(paddedHeader(`{"sig":"fDB","ver":1,"row_size":1024,"skew_ms":5000}`)); err != nil {
+		t.Fatalf("UnmarshalText: %v", err)
+	}
+	if legacy.GetValueCompression() != ValueCompressionNone {
+		t.Errorf("GetValueCompression() = %q, want %q", legacy.GetValueCompression(), ValueCompressionNone)
+	}
+
+	// Readers must not return stored bytes of an unknown compression as values
+	var corrupt *CorruptDatabaseError
+	if err := (&Header{}).UnmarshalText(paddedHeader(`{"sig":"fDB","ver":2,"row_size":1024,"skew_ms":5000,"vc":"zs"}`)); !errors.As(err, &corrupt) {
+		t.Errorf("unknown vc: expected CorruptDatabaseError, got %v", err)
+	}
+}
+
+func TestHeader_ValueCompressionValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		header  Header
+		wantErr string
+	}{
+		{name: "unsupported", header: Header{rowSize: 1024, skewMs: 5000, valueCompression: "zstd"}, wantErr: "unsupported value compression"},
+		{name: "does not fit", header: Header{rowSize: 65536, skewMs: 86400000, valueCompression: ValueCompressionGzip}, wantErr: "does not fit"},
+		{name: "with checksum interval", header: Header{rowSize: 128, skewMs: 0, checksumInterval: 100, valueCompression: ValueCompressionGzip}, wantErr: "does not fit"},
+		{name: "largest row size", header: Header{rowSize: 65536, skewMs: 999, valueCompression: ValueCompressionGzip}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.header.signature = HEADER_SIGNATURE
+			tt.header.version = tt.header.formatVersion()
+			err := tt.header.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Validate: %v", err)
+				}
+				return
+			}
+			if _, ok := err.(*InvalidInputError); !ok || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected InvalidInputError containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestDecompressValue_Corrupt(t *testing.T) {
+	stored, err := CompressValue(ValueCompressionGzip, json.RawMessage(`{"a":1}`))
+	if err != nil {
+		t.Fatalf("CompressValue: %v", err)
+	}
+	value, err := DecompressValue(ValueCompressionGzip, stored)
+	if err != nil || string(value) != `{"a":1}` {
+		t.Fatalf("DecompressValue = %s, %v", value, err)
+	}
+
+	for _, bad := range []string{`{"a":1}`, `"not base64!"`, `"aGVsbG8="`} {
+		var corrupt *CorruptDatabaseError
+		if _, err := DecompressValue(ValueCompressionGzip, json.RawMessage(bad)); !errors.As(err, &corrupt) {
+			t.Errorf("DecompressValue(%s): expected CorruptDatabaseError, got %v", bad, err)
+		}
+	}
+}
+
+// TestValueCompression_ReadsAndWrites writes a value too large for a row as written,
+// which fits once compressed, and reads it back through every read path.
+func TestValueCompression_ReadsAndWrites(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "gzip.fdb")
+	setupMockSyscalls(false, false)
+	t.Cleanup(restoreRealSyscalls)
+	t.Setenv("SUDO_USER", MOCK_USER)
+	t.Setenv("SUDO_UID", MOCK_UID)
+	t.Setenv("SUDO_GID", MOCK_GID)
+	config := NewCreateConfig(path, confRowSize, confSkewMs)
+	config.SetValueCompression(ValueCompressionGzip)
+	if err := Create(config); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	large := json.RawMessage(fmt.Sprintf(`{"text":%q}`, strings.Repeat("frozen ", 4*confRowSize)))
+	key, small := uuidFromTS(1000), uuidFromTS(1001)
+
+	db, err := NewFrozenDB(path, MODE_WRITE, FinderStrategySimple)
+	if err != nil {
+		t.Fatalf("NewFrozenDB: %v", err)
+	}
+	defer db.Close()
+	tx, err := db.BeginTx()
+	if err != nil {
+		t.Fatalf("BeginTx: %v", err)
+	}
+	mustAdd(t, tx, key, string(large))
+	mustAdd(t, tx, small, `{"n":1}`)
+	var pending struct{ Text string }
+	if err := tx.Get(key, &pending); err != nil || !strings.HasPrefix(pending.Text, "frozen ") {
+		t.Errorf("Transaction.Get = %.20q, %v", pending.Text, err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+
+	var got json.RawMessage
+	if err := db.Get(key, &got); err != nil || !bytes.Equal(got, large) {
+		t.Errorf("Get returned %d bytes, %v; want the %d-byte value", len(got), err, len(large))
+	}
+	var buf bytes.Buffer
+	if err := db.GetInto(key, &buf); err != nil || !bytes.Equal(buf.Bytes(), large) {
+		t.Errorf("GetInto returned %d bytes, %v", buf.Len(), err)
+	}
+	if value, _, err := db.GetWithMeta(small); err != nil || string(value) != `{"n":1}` {
+		t.Errorf("GetWithMeta = %s, %v", value, err)
+	}
+	if err := db.GetLatest(small, &got); err != nil || string(got) != `{"n":1}` {
+		t.Errorf("GetLatest = %s, %v", got, err)
+	}
+	var scanned []string
+	if err := db.Scan(func(_ uuid.UUID, value json.RawMessage) bool {
+		scanned = append(scanned, string(value[:7]))
+		return true
+	}); err != nil || len(scanned) != 2 || scanned[0] != `{"text"` || scanned[1] != `{"n":1}` {
+		t.Errorf("Scan = %v, %v", scanned, err)
+	}
+
+	// The row holds the compressed bytes, covered by parity and checksums
+	row, err := db.RowAt(1)
+	if err != nil {
+		t.Fatalf("RowAt: %v", err)
+	}
+	if !bytes.HasPrefix(row.Value, []byte(`"H4sI`)) {
+		t.Errorf("stored value %.20q is not a gzip base64 string", row.Value)
+	}
+	if _, err := Verify(path); err != nil {
+		t.Errorf("Verify: %v", err)
+	}
+
+	// BuildDatabase compresses the same way
+	var built bytes.Buffer
+	if err := BuildDatabase(&built, config, []Entry{{Key: key, Value: large}, {Key: small, Value: json.RawMessage(`{"n":1}`)}}); err != nil {
+		t.Fatalf("BuildDatabase: %v", err)
+	}
+	written, err := db.file.Read(0, int32(db.file.Size()))
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	if !bytes.Equal(built.Bytes(), written) {
+		t.Errorf("BuildDatabase output differs from the written file")
+	}
+}
@@ -0,0 +1,192 @@
+package frozendb
+
+import (
+	"fmt"
+	"hash/crc32"
+	"io"
+)
+
+// CheckRowOrder reports whether every data row of the database file at path
+// satisfies the ordering rule under skewMs instead of the skew_ms in its header:
+// the key's timestamp plus skewMs must exceed the largest timestamp of the
+// complete data and null rows before it, as the writer requires. A trailing
+// partial row is checked once its key has been written. Use it before copying a
+// database with CopyRows into one created with a smaller skew_ms; any larger
+// skew_ms accepts every file the header's skew_ms does.
+//
+// Parameters:
+//   - path: Filesystem path to the database file (opened in MODE_READ)
+//   - skewMs: Skew window to check against, between 0 and MAX_SKEW_MS
+//
+// Returns:
+//   - error: KeyOrderViolationError for the first row that violates the rule,
+//     InvalidInputError (skewMs out of range), CorruptDatabaseError, ReadError,
+//     or PathError
+func CheckRowOrder(path string, skewMs int) error {
+	if skewMs < 0 || skewMs > MAX_SKEW_MS {
+		return NewInvalidInputError(fmt.Sprintf("skew_ms must be between 0 and %d, got %d", MAX_SKEW_MS, skewMs), nil)
+	}
+	dbFile, err := NewDBFile(path, MODE_READ)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = dbFile.Close() }()
+
+	header, err := validateDatabaseFile(dbFile)
+	if err != nil {
+		return err
+	}
+	rowSize := int64(header.GetRowSize())
+	size := dbFile.Size()
+	completeRows := (size - HEADER_SIZE) / rowSize
+
+	maxTimestamp := int64(0)
+	checkKey := func(index int64, ts int64) error {
+		if ts+int64(skewMs) > maxTimestamp {
+			return nil
+		}
+		return NewKeyOrderViolationError(
+			fmt.Sprintf("row %d: UUID timestamp %d plus skew_ms %d does not exceed the maximum timestamp %d", index, ts, skewMs, maxTimestamp),
+			int(index), ts, maxTimestamp,
+			NewKeyOrderingError("UUID timestamp violates ordering constraint", nil),
+		)
+	}
+	for index := int64(1); index < completeRows; index++ {
+		rowBytes, err := dbFile.Read(HEADER_SIZE+index*rowSize, int32(rowSize))
+		if err != nil {
+			return NewReadError(fmt.Sprintf("failed to read row %d", index), err)
+		}
+		var ru RowUnion
+		if err := ru.UnmarshalText(rowBytes); err != nil {
+			return NewCorruptDatabaseError(fmt.Sprintf("invalid row %d", index), err)
+		}
+		switch {
+		case ru.DataRow != nil:
+			ts := ExtractUUIDv7Timestamp(ru.DataRow.GetKey())
+			if err := checkKey(index, ts); err != nil {
+				return err
+			}
+			maxTimestamp = max(maxTimestamp, ts)
+		case ru.NullRow != nil:
+			maxTimestamp = max(maxTimestamp, ExtractUUIDv7Timestamp(ru.NullRow.GetKey()))
+		}
+	}
+
+	if rowsEnd := HEADER_SIZE + completeRows*rowSize; size > rowsEnd {
+		fragment, err := dbFile.Read(rowsEnd, int32(size-rowsEnd))
+		if err != nil {
+			return NewReadError("failed to read trailing partial row", err)
+		}
+		partialRow, err := parseTrailingPartialRow(fragment, completeRows, int(rowSize))
+		if err != nil {
+			return err
+		}
+		if key, ok := partialRow.GetKey(); ok {
+			return checkKey(completeRows, ExtractUUIDv7Timestamp(key))
+		}
+	}
+	return nil
+}
+
+// CopyRows appends every row of the database file at srcPath after its initial
+// checksum row to the database file at dstPath, which must hold only its initial
+// checksum row and share the source's row_size, checksum interval, and value
+// compression. Only the headers may differ, such as in skew_ms. Data and null
+// rows are copied byte for byte, including rolled back rows and a trailing partial
+// row, so every transaction keeps its boundaries and outcome; checksum rows are
+// regenerated to cover the destination's bytes.
+//
+// Every source row is validated as it is copied, as ApplyReplicationStream does,
+// and every source checksum row must match the source bytes it covers. The
+// destination's ordering rule is not checked; use CheckRowOrder first when its
+// skew_ms is smaller than the source's.
+//
+// Parameters:
+//   - srcPath: Filesystem path to the source database file (opened in MODE_READ)
+//   - dstPath: Filesystem path to the destination database file (opened in MODE_WRITE)
+//   - opts: Optional OpenOption values for the destination, such as WithoutLock
+//
+// Returns:
+//   - int64: Size of the destination file after the copy
+//   - error: InvalidInputError (the destination is not empty or its header does
+//     not match), CorruptDatabaseError (a source row fails validation), ReadError,
+//     PathError, or WriteError
+func CopyRows(srcPath, dstPath string, opts ...OpenOption) (int64, error) {
+	src, err := NewDBFile(srcPath, MODE_READ)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = src.Close() }()
+	srcHeader, err := validateDatabaseFile(src)
+	if err != nil {
+		return 0, err
+	}
+
+	dst, err := newDBFile(dstPath, MODE_WRITE, newOpenOptions(opts))
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = dst.Close() }()
+	dstHeader, err := validateDatabaseFile(dst)
+	if err != nil {
+		return 0, err
+	}
+
+	rowSize := srcHeader.GetRowSize()
+	if dstHeader.GetRowSize() != rowSize ||
+		dstHeader.GetChecksumInterval() != srcHeader.GetChecksumInterval() ||
+		dstHeader.GetValueCompression() != srcHeader.GetValueCompression() {
+		return 0, NewInvalidInputError("destination must have the source's row_size, checksum interval, and value compression", nil)
+	}
+	rowsStart := int64(HEADER_SIZE + rowSize)
+	if dst.Size() != rowsStart {
+		return 0, NewInvalidInputError(fmt.Sprintf("destination must hold only its initial checksum row (%d bytes), got %d bytes", rowsStart, dst.Size()), nil)
+	}
+
+	// The source's initial checksum covers its header and starts the next checksum's range
+	headerAndChecksum, err := src.Read(0, int32(rowsStart))
+	if err != nil {
+		return 0, NewReadError("failed to read source header and initial checksum row", err)
+	}
+	var initial ChecksumRow
+	if err := initial.UnmarshalText(headerAndChecksum[HEADER_SIZE:]); err != nil {
+		return 0, NewCorruptDatabaseError("invalid source initial checksum row", err)
+	}
+	if expected := crc32.ChecksumIEEE(initialChecksumInput(headerAndChecksum[:HEADER_SIZE], initial.Extension)); Checksum(expected) != *initial.RowPayload {
+		return 0, NewCorruptDatabaseError(
+			fmt.Sprintf("source initial checksum mismatch (expected %08X, got %08X)", expected, *initial.RowPayload), nil)
+	}
+
+	applier, err := newReplicaApplier(dst, dstHeader)
+	if err != nil {
+		return 0, err
+	}
+	applier.srcCRC = crc32.NewIEEE()
+	applier.srcCRC.Write(headerAndChecksum[HEADER_SIZE:])
+
+	if err := applier.run(dst, &dbFileReader{file: src, offset: rowsStart, end: src.Size()}); err != nil {
+		return 0, err
+	}
+	return dst.Size(), nil
+}
+
+// dbFileReader reads the bytes of a DBFile from offset up to end.
+type dbFileReader struct {
+	file   DBFile
+	offset int64
+	end    int64
+}
+
+func (r *dbFileReader) Read(p []byte) (int, error) {
+	if r.offset >= r.end {
+		return 0, io.EOF
+	}
+	n := min(int64(len(p)), r.end-r.offset)
+	data, err := r.file.Read(r.offset, int32(n))
+	if err != nil {
+		return 0, err
+	}
+	copy(p, data)
+	r.offset += int64(len(data))
+	return len(data), nil
+}
@@ -0,0 +1,79 @@
+package frozendb
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCopyRows_NewSkew(t *testing.T) {
+	src := createDatabaseWithChecksumWindows(t, 350)
+	writeTx(t, src, true, func(tx *Transaction) error {
+		return tx.AddRow(uuidFromTS(2000), json.RawMessage(`{"rolled":"back"}`))
+	})
+	// Accepted under the source's skew_ms, but behind the rolled back key by 500ms
+	writeTx(t, src, false, func(tx *Transaction) error {
+		return tx.AddRow(uuidFromTS(1500), json.RawMessage(`{"late":true}`))
+	})
+
+	var violation *KeyOrderViolationError
+	if err := CheckRowOrder(src, 500); !errors.As(err, &violation) || violation.Timestamp != 1500 || violation.MaxTimestamp != 2000 {
+		t.Errorf("CheckRowOrder(500): expected KeyOrderViolationError for timestamp 1500, got %v", err)
+	}
+	if err := CheckRowOrder(src, 501); err != nil {
+		t.Errorf("CheckRowOrder(501): %v", err)
+	}
+
+	dst := filepath.Join(t.TempDir(), "dst.fdb")
+	config := NewCreateConfig(dst, confRowSize, 20000)
+	config.SetChecksumInterval(MIN_CHECKSUM_INTERVAL)
+	if err := Create(config); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	size, err := CopyRows(src, dst)
+	if err != nil {
+		t.Fatalf("CopyRows: %v", err)
+	}
+
+	srcBytes, err := os.ReadFile(src)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	dstBytes, err := os.ReadFile(dst)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	if size != int64(len(srcBytes)) || len(dstBytes) != len(srcBytes) {
+		t.Fatalf("CopyRows size %d, destination %d bytes; want %d", size, len(dstBytes), len(srcBytes))
+	}
+	for index := 1; HEADER_SIZE+index*confRowSize < len(srcBytes); index++ {
+		if index%(MIN_CHECKSUM_INTERVAL+1) == 0 {
+			continue
+		}
+		row := srcBytes[HEADER_SIZE+index*confRowSize : HEADER_SIZE+(index+1)*confRowSize]
+		if !bytes.Equal(row, dstBytes[HEADER_SIZE+index*confRowSize:HEADER_SIZE+(index+1)*confRowSize]) {
+			t.Fatalf("row %d differs between source and destination", index)
+		}
+	}
+
+	report, err := Verify(dst)
+	if err != nil {
+		t.Fatalf("Verify destination: %v", err)
+	}
+	srcReport, _ := Verify(src)
+	if report.DataRows != srcReport.DataRows || report.ChecksumRows != srcReport.ChecksumRows || report.Transactions != srcReport.Transactions {
+		t.Errorf("destination report %+v differs from source %+v", report, srcReport)
+	}
+	if header, err := ReadHeader(dst); err != nil || header.GetSkewMs() != 20000 {
+		t.Errorf("destination header: %v", err)
+	}
+
+	// The destination now holds rows
+	var invalidInput *InvalidInputError
+	if _, err := CopyRows(src, dst); !errors.As(err, &invalidInput) {
+		t.Errorf("CopyRows onto a non-empty destination: expected InvalidInputError, got %v", err)
+	}
+}
@@ -2,12 +2,15 @@ package frozendb
 
 import (
 	"fmt"
+	"maps"
+	"math/rand/v2"
 	"os"
 	"os/user"
 	"path/filepath"
 	"strconv"
 	"strings"
 	"syscall"
+	"time"
 	"unsafe"
 )
 
@@ -20,6 +23,8 @@ type fsOperations struct {
 	Mkdir  func(path string, perm os.FileMode) error
 	Chown  func(name string, uid, gid int) error
 	Ioctl  func(trap uintptr, a1 uintptr, a2 uintptr, a3 uintptr) (r1 uintptr, r2 uintptr, err syscall.Errno)
+	Link   func(oldname, newname string) error
+	Rename func(oldpath, newpath string) error
 }
 
 // Default implementations using real OS functions
@@ -31,13 +36,22 @@ var defaultFSOps = fsOperations{
 	Mkdir:  os.Mkdir,
 	Chown:  os.Chown,
 	Ioctl:  syscall.Syscall,
+	Link:   os.Link,
+	Rename: os.Rename,
 }
 
 // Global variable to allow tests to inject mock filesystem operations
 var fsInterface = &defaultFSOps
 
 // SetFSInterface allows tests to inject custom filesystem operation implementations
+// Link and Rename left nil keep the real implementation
 func SetFSInterface(ops fsOperations) {
+	if ops.Link == nil {
+		ops.Link = defaultFSOps.Link
+	}
+	if ops.Rename == nil {
+		ops.Rename = defaultFSOps.Rename
+	}
 	fsInterface = &ops
 }
 
@@ -57,6 +71,8 @@ func setupMockFS(overrides fsOperations) {
 		Mkdir:  defaultFSOps.Mkdir,
 		Chown:  defaultFSOps.Chown,
 		Ioctl:  defaultFSOps.Ioctl,
+		Link:   defaultFSOps.Link,
+		Rename: defaultFSOps.Rename,
 	}
 
 	// Apply overrides
@@ -78,6 +94,12 @@ func setupMockFS(overrides fsOperations) {
 	if overrides.Chown != nil {
 		mockOps.Chown = overrides.Chown
 	}
+	if overrides.Link != nil {
+		mockOps.Link = overrides.Link
+	}
+	if overrides.Rename != nil {
+		mockOps.Rename = overrides.Rename
+	}
 	if overrides.Ioctl != nil {
 		mockOps.Ioctl = overrides.Ioctl
 	}
@@ -87,9 +109,16 @@ func setupMockFS(overrides fsOperations) {
 
 // CreateConfig holds configuration for creating a new frozenDB database file
 type CreateConfig struct {
-	path    string // Filesystem path for the database file
-	rowSize int    // Size of each data row in bytes (128-65536)
-	skewMs  int    // Time skew window in milliseconds (0-86400000)
+	path             string // Filesystem path for the database file
+	rowSize          int    // Size of each data row in bytes (128-65536)
+	skewMs           int    // Time skew window in milliseconds (0-86400000)
+	checksumInterval int    // Rows between checksum rows (0 means CHECKSUM_INTERVAL)
+	noImmutable      bool   // Skip the append-only attribute and the sudo requirement
+	force            bool   // Replace an existing file at path instead of failing
+
+	valueCompression ValueCompression  // How row values are stored ("" means ValueCompressionNone)
+	metadata         map[string]string // Application metadata stored in the initial checksum row (nil means none)
+	createdAt        time.Time         // Creation time stored in the initial checksum row (zero means none)
 }
 
 // NewCreateConfig creates a new CreateConfig with the specified parameters.
@@ -124,6 +153,97 @@ func (cfg *CreateConfig) GetSkewMs() int {
 	return cfg.skewMs
 }
 
+// SetChecksumInterval sets the number of data and null rows between checksum rows
+// (MIN_CHECKSUM_INTERVAL to MAX_CHECKSUM_INTERVAL). Smaller intervals detect
+// corruption at a finer granularity at the cost of more checksum rows. The value is
+// stored in the header, which must still fit in 64 bytes alongside row_size and
+// skew_ms; Validate reports intervals that do not fit. 0 selects CHECKSUM_INTERVAL.
+func (cfg *CreateConfig) SetChecksumInterval(interval int) {
+	cfg.checksumInterval = interval
+}
+
+// GetChecksumInterval returns the configured checksum interval (0 means CHECKSUM_INTERVAL)
+func (cfg *CreateConfig) GetChecksumInterval() int {
+	return cfg.checksumInterval
+}
+
+// SetValueCompression sets how row values are stored. With ValueCompressionGzip,
+// AddRow compresses each value before writing it and every read decompresses it
+// transparently, saving space for large JSON values at some CPU cost. The choice is
+// recorded in the header and cannot be changed later; Validate reports unsupported
+// values and headers that no longer fit in 64 bytes, which is always the case
+// together with a non-default checksum interval. "" selects ValueCompressionNone.
+func (cfg *CreateConfig) SetValueCompression(compression ValueCompression) {
+	cfg.valueCompression = compression
+}
+
+// GetValueCompression returns the configured value compression ("" means ValueCompressionNone)
+func (cfg *CreateConfig) GetValueCompression() ValueCompression {
+	return cfg.valueCompression
+}
+
+// SetMetadata sets application-defined metadata, such as a schema version or
+// source system, to record in the database. It is stored as JSON after the
+// checksum in the initial checksum row, which is covered by that checksum and
+// never rewritten, so it cannot change once the database is created; readers
+// get it from FrozenDB.Metadata. Any metadata makes the file version 2. Validate
+// reports empty keys and metadata that does not fit in one row (row_size minus
+// 17 bytes of JSON). The map is copied; nil or empty records no metadata.
+func (cfg *CreateConfig) SetMetadata(metadata map[string]string) {
+	cfg.metadata = maps.Clone(metadata)
+}
+
+// GetMetadata returns a copy of the configured metadata (nil means none)
+func (cfg *CreateConfig) GetMetadata() map[string]string {
+	return maps.Clone(cfg.metadata)
+}
+
+// SetCreatedAt sets the creation time to record in the database, normally
+// time.Now(). Like metadata, it is stored in the initial checksum row, is covered
+// by that row's checksum, and makes the file version 2; readers get it from
+// FrozenDB.CreatedAt and Header.GetCreatedAt. It is kept with millisecond
+// precision. Validate reports times before the Unix epoch. The zero time, the
+// default, records none, so the file stays readable by version 1 readers.
+func (cfg *CreateConfig) SetCreatedAt(createdAt time.Time) {
+	cfg.createdAt = createdAt
+}
+
+// GetCreatedAt returns the configured creation time (the zero time means none)
+func (cfg *CreateConfig) GetCreatedAt() time.Time {
+	return cfg.createdAt
+}
+
+// SetNoImmutable controls whether Create skips setting the filesystem append-only
+// attribute. Setting the attribute requires running under sudo, which CI containers
+// and rootless setups cannot do; with noImmutable, Create works unprivileged (or as
+// root directly) and writes a byte-identical file, but appending only is enforced by
+// frozenDB itself rather than by the operating system. Ownership is still handed to
+// SUDO_USER when running under sudo.
+func (cfg *CreateConfig) SetNoImmutable(noImmutable bool) {
+	cfg.noImmutable = noImmutable
+}
+
+// GetNoImmutable reports whether Create skips the append-only attribute
+func (cfg *CreateConfig) GetNoImmutable() bool {
+	return cfg.noImmutable
+}
+
+// SetForce controls whether Create replaces an existing regular file at the path.
+// By default Create fails with PathError "file already exists", so re-running it
+// cannot clobber a populated database. With force, the new file is written and
+// synced in full before it is renamed over the existing one, so the old contents
+// survive any failure up to the rename. The existing file's append-only
+// attribute, if set, is cleared just before the rename, which requires the same
+// privileges as setting it, and is restored if the rename fails.
+func (cfg *CreateConfig) SetForce(force bool) {
+	cfg.force = force
+}
+
+// GetForce reports whether Create replaces an existing file
+func (cfg *CreateConfig) GetForce() bool {
+	return cfg.force
+}
+
 // SudoContext contains information about the sudo environment
 type SudoContext struct {
 	user string // Original username from SUDO_USER
@@ -242,26 +362,54 @@ func (sc *SudoContext) Validate() error {
 
 // Validate validates the CreateConfig and returns appropriate error types
 func (cfg *CreateConfig) Validate() error {
-	// Validate rowSize and skewMs by creating a Header struct and validating it
-	header := &Header{
-		signature: HEADER_SIGNATURE,
-		version:   1,
-		rowSize:   cfg.rowSize,
-		skewMs:    cfg.skewMs,
-	}
-
-	if err := header.Validate(); err != nil {
+	// Validate the header fields by creating a Header struct and validating it
+	if err := cfg.header().Validate(); err != nil {
 		return err
 	}
 
 	// Validate path and filesystem preconditions
+	if cfg.force {
+		if err := validateParentPath(cfg.path); err != nil {
+			return err
+		}
+		return validateReplaceable(cfg.path)
+	}
 	return validatePath(cfg.path)
 }
 
+// header returns the Header described by the configuration, without validating it.
+func (cfg *CreateConfig) header() *Header {
+	header := &Header{
+		signature:        HEADER_SIGNATURE,
+		rowSize:          cfg.rowSize,
+		skewMs:           cfg.skewMs,
+		checksumInterval: cfg.checksumInterval,
+		valueCompression: cfg.valueCompression,
+	}
+	if len(cfg.metadata) > 0 {
+		header.metadata = maps.Clone(cfg.metadata)
+	}
+	if !cfg.createdAt.IsZero() {
+		header.createdAt = cfg.createdAt.UnixMilli()
+		if header.createdAt == 0 {
+			header.createdAt = -1 // 0 means none, so the epoch itself is rejected like earlier times
+		}
+	}
+	header.version = header.formatVersion()
+	return header
+}
+
 // Create creates a new frozenDB database file with the given configuration
 // The file is created with a 64-byte header followed by an initial checksum row
 // that covers the header bytes [0..63] using CRC32 IEEE polynomial
-func Create(config CreateConfig) error {
+// Unless SetNoImmutable(true) was called, Create must run under sudo and sets the
+// append-only filesystem attribute on the file.
+// An existing file at the path is only replaced when SetForce(true) was called.
+// The header and checksum row are written to a temporary file in the same
+// directory, which is then linked into place (or, with force, renamed over the
+// existing file), so a reader opening the path while it is being created sees
+// either no file, the old file, or the complete new one.
+func Create(config CreateConfig) (err error) {
 	// Validate all inputs first (no side effects)
 	if err := config.Validate(); err != nil {
 		return err
@@ -273,36 +421,39 @@ func Create(config CreateConfig) error {
 		return err
 	}
 
-	// Check for direct root execution - only reject if no sudo context
-	if fsInterface.Getuid() == 0 && sudoCtx == nil {
-		return NewWriteError("direct root execution not allowed", nil)
-	}
+	// Sudo is only needed to set the append-only attribute
+	if !config.noImmutable {
+		// Check for direct root execution - only reject if no sudo context
+		if fsInterface.Getuid() == 0 && sudoCtx == nil {
+			return NewWriteError("direct root execution not allowed", nil)
+		}
 
-	// Validate that we have proper sudo context for append-only setting
-	if sudoCtx == nil {
-		return NewWriteError("append-only attribute requires sudo privileges", nil)
+		// Validate that we have proper sudo context for append-only setting
+		if sudoCtx == nil {
+			return NewWriteError("append-only attribute requires sudo privileges", nil)
+		}
 	}
 
-	// Create file atomically
-	file, err := createFile(config.path)
+	// Write to a temporary file that is published once complete
+	file, tmpPath, err := createTempFile(config.path)
 	if err != nil {
 		return err
 	}
+	published := false
 
 	// Defer cleanup on any error
 	defer func() {
 		if err != nil {
-			_ = os.Remove(config.path)
+			_ = file.Close()
+			_ = os.Remove(tmpPath)
+			if published {
+				_ = os.Remove(config.path)
+			}
 		}
 	}()
 
 	// Create Header struct and generate header bytes
-	header := &Header{
-		signature: HEADER_SIGNATURE,
-		version:   1,
-		rowSize:   config.rowSize,
-		skewMs:    config.skewMs,
-	}
+	header := config.header()
 
 	if err := header.Validate(); err != nil {
 		return err
@@ -314,7 +465,7 @@ func Create(config CreateConfig) error {
 	}
 
 	// Calculate CRC32 for header bytes [0..63] (entire header)
-	checksumRow, err := NewChecksumRow(header.GetRowSize(), headerBytes)
+	checksumRow, err := newInitialChecksumRow(header, headerBytes)
 	if err != nil {
 		return NewWriteError("failed to create checksum row", err)
 	}
@@ -345,14 +496,42 @@ func Create(config CreateConfig) error {
 		return NewWriteError("failed to sync file data", err)
 	}
 
+	if config.force {
+		// Replace the existing file in one step; the temporary name is gone
+		// once the rename succeeds
+		if err = replaceExisting(tmpPath, config.path); err != nil {
+			return err
+		}
+		published = true
+	} else {
+		// Publish the complete file; Link fails rather than replace a file
+		// created at the path since validation
+		if err = fsInterface.Link(tmpPath, config.path); err != nil {
+			if os.IsExist(err) {
+				return NewPathError("file already exists", err)
+			}
+			return NewPathError("failed to link file into place", err)
+		}
+		published = true
+		// The append-only attribute forbids unlinking, so the temporary name is
+		// removed before it is set
+		if err = os.Remove(tmpPath); err != nil {
+			return NewPathError("failed to remove temporary file", err)
+		}
+	}
+
 	// Set ownership to original user (if running under sudo)
-	if err = setOwnership(config.path, sudoCtx); err != nil {
-		return err
+	if sudoCtx != nil {
+		if err = setOwnership(config.path, sudoCtx); err != nil {
+			return err
+		}
 	}
 
 	// Set append-only attribute using ioctl (must be done while file is open)
-	if err = setAppendOnlyAttr(int(file.Fd())); err != nil {
-		return err
+	if !config.noImmutable {
+		if err = setAppendOnlyAttr(int(file.Fd())); err != nil {
+			return err
+		}
 	}
 
 	// Close file before validation - we'll re-open for reading
@@ -364,6 +543,38 @@ func Create(config CreateConfig) error {
 
 // validatePath validates path format and filesystem preconditions
 func validatePath(path string) error {
+	if err := validateParentPath(path); err != nil {
+		return err
+	}
+
+	// Check if target file already exists
+	if _, err := fsInterface.Stat(path); err == nil {
+		return NewPathError("file already exists", nil)
+	} else if !os.IsNotExist(err) {
+		return NewPathError("failed to check if file exists", err)
+	}
+
+	return nil
+}
+
+// validateReplaceable checks that path is either absent or a regular file that
+// Create may replace with SetForce.
+func validateReplaceable(path string) error {
+	info, err := fsInterface.Stat(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return NewPathError("failed to check if file exists", err)
+	}
+	if !info.Mode().IsRegular() {
+		return NewPathError("existing path is not a regular file", nil)
+	}
+	return nil
+}
+
+// validateParentPath validates the path format and its parent directory
+func validateParentPath(path string) error {
 	// Validate path is not empty
 	if path == "" {
 		return NewInvalidInputError("path cannot be empty", nil)
@@ -396,25 +607,52 @@ func validatePath(path string) error {
 		return NewPathError("parent directory is not writable", nil)
 	}
 
-	// Check if target file already exists
-	if _, err := fsInterface.Stat(path); err == nil {
-		return NewPathError("file already exists", nil)
-	} else if !os.IsNotExist(err) {
-		return NewPathError("failed to check if file exists", err)
+	return nil
+}
+
+// replaceExisting renames tmpPath over path, which may or may not exist. An
+// append-only file cannot be replaced, so its attribute is cleared first and set
+// again if the rename fails, leaving the existing file as it was.
+func replaceExisting(tmpPath, path string) error {
+	file, err := fsInterface.Open(path, syscall.O_RDONLY, 0)
+	if err != nil && !os.IsNotExist(err) {
+		return NewPathError("failed to open existing file", err)
+	}
+	if file != nil {
+		defer func() { _ = file.Close() }()
+	}
+
+	cleared := false
+	if file != nil {
+		if cleared, err = clearAppendOnlyAttr(int(file.Fd())); err != nil {
+			return err
+		}
 	}
 
+	if err := fsInterface.Rename(tmpPath, path); err != nil {
+		if cleared {
+			_ = setAppendOnlyAttr(int(file.Fd()))
+		}
+		return NewPathError("failed to rename file into place", err)
+	}
 	return nil
 }
 
-// createFile atomically creates the file with proper permissions
-func createFile(path string) (*os.File, error) {
-	// Create file with O_CREAT|O_EXCL for atomic creation using fsInterface
-	file, err := fsInterface.Open(path, O_CREAT_EXCL|syscall.O_WRONLY, FILE_PERMISSIONS)
-	if err != nil {
-		return nil, NewPathError("failed to create file atomically", err)
+// createTempFile creates a uniquely named temporary file next to path, with the
+// permissions of a database file, and returns it with its path.
+func createTempFile(path string) (*os.File, string, error) {
+	dir, base := filepath.Split(path)
+	for range 100 {
+		tmpPath := filepath.Join(dir, "."+base+"."+strconv.FormatUint(rand.Uint64(), 36)+".tmp")
+		file, err := fsInterface.Open(tmpPath, O_CREAT_EXCL|syscall.O_WRONLY, FILE_PERMISSIONS)
+		if err == nil {
+			return file, tmpPath, nil
+		}
+		if !os.IsExist(err) {
+			return nil, "", NewPathError("failed to create temporary file", err)
+		}
 	}
-
-	return file, nil
+	return nil, "", NewPathError("failed to create temporary file: too many name collisions", nil)
 }
 
 // setAppendOnlyAttr sets the append-only attribute using ioctl
@@ -437,6 +675,26 @@ func setAppendOnlyAttr(fd int) error {
 	return nil
 }
 
+// clearAppendOnlyAttr removes the append-only attribute if it is set and reports
+// whether it did. Filesystems that do not support file flags cannot have set it,
+// so a failure to read the flags is not an error.
+func clearAppendOnlyAttr(fd int) (bool, error) {
+	var flags uint32
+
+	_, _, errno := fsInterface.Ioctl(syscall.SYS_IOCTL, uintptr(fd), FS_IOC_GETFLAGS, uintptr(unsafe.Pointer(&flags)))
+	if errno != 0 || flags&FS_APPEND_FL == 0 {
+		return false, nil
+	}
+
+	flags &^= FS_APPEND_FL
+	_, _, errno = fsInterface.Ioctl(syscall.SYS_IOCTL, uintptr(fd), FS_IOC_SETFLAGS, uintptr(unsafe.Pointer(&flags)))
+	if errno != 0 {
+		return false, NewWriteError("failed to clear append-only attribute", errno)
+	}
+
+	return true, nil
+}
+
 // setOwnership changes file ownership if running under sudo
 func setOwnership(path string, sudoCtx *SudoContext) error {
 	// Use fsInterface.Chown to change ownership to original user
@@ -447,3 +705,16 @@ func setOwnership(path string, sudoCtx *SudoContext) error {
 
 	return nil
 }
+
+// EstimatedSize returns the size in bytes of a database file holding dataRows
+// data and null rows of rowSize bytes: the header, the initial checksum row, the
+// rows themselves, and the checksum row written after every checksumInterval
+// rows. A checksumInterval of 0 selects CHECKSUM_INTERVAL. Inputs are not
+// validated; use CreateConfig.Validate for that.
+func EstimatedSize(rowSize int, dataRows int64, checksumInterval int) int64 {
+	if checksumInterval == 0 {
+		checksumInterval = CHECKSUM_INTERVAL
+	}
+	numChecksumRows := dataRows / int64(checksumInterval)
+	return HEADER_SIZE + int64(rowSize) + dataRows*int64(rowSize) + numChecksumRows*int64(rowSize)
+}
@@ -1,8 +1,12 @@
 package frozendb
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
 	"os"
 	"os/user"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"syscall"
@@ -366,3 +370,247 @@ func TestSetAppendOnlyAttr(t *testing.T) {
 		_ = writeErr
 	}
 }
+
+func TestCreateNoImmutable(t *testing.T) {
+	dir := t.TempDir()
+
+	// Reference file from the normal path, under mocked sudo
+	reference := setupCreate(t, dir, 0)
+	restoreRealSyscalls()
+
+	t.Setenv("SUDO_USER", "")
+	t.Setenv("SUDO_UID", "")
+	t.Setenv("SUDO_GID", "")
+	setupMockFS(fsOperations{
+		Getuid: func() int { return 1000 },
+		Chown: func(name string, uid, gid int) error {
+			t.Errorf("Chown called without sudo for %s", name)
+			return nil
+		},
+		Ioctl: func(trap uintptr, a1 uintptr, a2 uintptr, a3 uintptr) (uintptr, uintptr, syscall.Errno) {
+			t.Errorf("ioctl called with SetNoImmutable(true)")
+			return 0, 0, syscall.EPERM
+		},
+	})
+	t.Cleanup(restoreRealFS)
+
+	path := dir + "/plain.fdb"
+	config := NewCreateConfig(path, confRowSize, confSkewMs)
+	if err := Create(config); err == nil || !strings.Contains(err.Error(), "sudo") {
+		t.Fatalf("Create without sudo: expected sudo error, got %v", err)
+	}
+
+	config.SetNoImmutable(true)
+	if !config.GetNoImmutable() {
+		t.Fatal("GetNoImmutable() = false after SetNoImmutable(true)")
+	}
+	if err := Create(config); err != nil {
+		t.Fatalf("Create with SetNoImmutable(true): %v", err)
+	}
+	want, _ := os.ReadFile(reference)
+	got, _ := os.ReadFile(path)
+	if string(got) != string(want) {
+		t.Errorf("file differs from one created with the append-only attribute")
+	}
+	if _, err := Verify(path); err != nil {
+		t.Errorf("Verify: %v", err)
+	}
+}
+
+func TestCreate_ConcurrentReader(t *testing.T) {
+	setupMockSyscalls(false, false)
+	t.Cleanup(restoreRealSyscalls)
+	t.Setenv("SUDO_USER", MOCK_USER)
+	t.Setenv("SUDO_UID", MOCK_UID)
+	t.Setenv("SUDO_GID", MOCK_GID)
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "c.fdb")
+	config := NewCreateConfig(path, confRowSize, confSkewMs)
+	for i := 0; i < 200; i++ {
+		done := make(chan struct{})
+		seen := make(chan []byte, 1)
+		go func() {
+			defer close(seen)
+			for {
+				data, err := os.ReadFile(path)
+				if err == nil {
+					seen <- data
+					return
+				}
+				if !os.IsNotExist(err) {
+					t.Errorf("ReadFile: %v", err)
+					return
+				}
+				select {
+				case <-done:
+					return
+				default:
+				}
+			}
+		}()
+
+		err := Create(config)
+		close(done)
+		if err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+		if data, ok := <-seen; ok {
+			if len(data) != HEADER_SIZE+confRowSize {
+				t.Fatalf("reader saw %d bytes, want %d", len(data), HEADER_SIZE+confRowSize)
+			}
+			header := &Header{}
+			if err := header.UnmarshalText(data[:HEADER_SIZE]); err != nil {
+				t.Fatalf("reader saw an invalid header: %v", err)
+			}
+		}
+		if err := os.Remove(path); err != nil {
+			t.Fatalf("Remove: %v", err)
+		}
+	}
+
+	// A failed Create leaves neither its temporary file nor a partial database
+	if err := Create(config); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if err := Create(config); err == nil {
+		t.Fatal("second Create: expected an error")
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("ReadDir: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Name() != "c.fdb" {
+		names := make([]string, len(entries))
+		for i, entry := range entries {
+			names[i] = entry.Name()
+		}
+		t.Errorf("directory holds %v, want only c.fdb", names)
+	}
+}
+
+func TestCreate_ForceKeepsOriginalOnFailure(t *testing.T) {
+	setupMockSyscalls(false, false)
+	t.Cleanup(restoreRealSyscalls)
+	t.Setenv("SUDO_USER", MOCK_USER)
+	t.Setenv("SUDO_UID", MOCK_UID)
+	t.Setenv("SUDO_GID", MOCK_GID)
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "f.fdb")
+	config := NewCreateConfig(path, confRowSize, confSkewMs)
+	if err := Create(config); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	// Stand-in for rows written since, so the original is told apart from a new file
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		t.Fatalf("OpenFile: %v", err)
+	}
+	if _, err := file.Write(make([]byte, confRowSize)); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	_ = file.Close()
+	original, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+
+	assertOnlyOriginal := func(t *testing.T) {
+		t.Helper()
+		got, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("ReadFile: %v", err)
+		}
+		if string(got) != string(original) {
+			t.Errorf("original file changed: %d bytes, want %d", len(got), len(original))
+		}
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			t.Fatalf("ReadDir: %v", err)
+		}
+		if len(entries) != 1 {
+			t.Errorf("directory holds %d entries, want only f.fdb", len(entries))
+		}
+	}
+
+	t.Run("rename fails", func(t *testing.T) {
+		fsInterface.Rename = func(oldpath, newpath string) error { return syscall.EIO }
+		defer func() { fsInterface.Rename = os.Rename }()
+
+		config.SetForce(true)
+		err := Create(config)
+		var pathErr *PathError
+		if !errors.As(err, &pathErr) || !strings.Contains(err.Error(), "rename") {
+			t.Fatalf("Create with force: expected PathError from the rename, got %v", err)
+		}
+		assertOnlyOriginal(t)
+	})
+
+	t.Run("link fails", func(t *testing.T) {
+		fsInterface.Link = func(oldname, newname string) error { return syscall.EEXIST }
+		defer func() { fsInterface.Link = os.Link }()
+
+		config.SetForce(false)
+		// Validation already rejects the existing file, so clear the path check
+		fsInterface.Stat = func(name string) (os.FileInfo, error) {
+			if name == path {
+				return nil, os.ErrNotExist
+			}
+			return os.Stat(name)
+		}
+		defer func() { fsInterface.Stat = os.Stat }()
+
+		err := Create(config)
+		var pathErr *PathError
+		if !errors.As(err, &pathErr) || !strings.Contains(err.Error(), "file already exists") {
+			t.Fatalf("Create: expected PathError \"file already exists\", got %v", err)
+		}
+		assertOnlyOriginal(t)
+	})
+
+	// With the rename working, force replaces the file with a fresh database
+	config.SetForce(true)
+	if err := Create(config); err != nil {
+		t.Fatalf("Create with force: %v", err)
+	}
+	if info, err := os.Stat(path); err != nil || info.Size() != int64(HEADER_SIZE+confRowSize) {
+		t.Errorf("replaced file: %v, err %v", info, err)
+	}
+}
+
+func TestEstimatedSize(t *testing.T) {
+	tests := []struct {
+		rowSize  int
+		dataRows int64
+		interval int
+		want     int64
+	}{
+		{1024, 0, 0, HEADER_SIZE + 1024},
+		{512, 1_000_000, 0, HEADER_SIZE + 512 + 1_000_000*512 + 100*512},
+		{512, 9_999, 10_000, HEADER_SIZE + 512 + 9_999*512},
+		{128, 250, 100, HEADER_SIZE + 128 + 250*128 + 2*128},
+	}
+	for _, tt := range tests {
+		if got := EstimatedSize(tt.rowSize, tt.dataRows, tt.interval); got != tt.want {
+			t.Errorf("EstimatedSize(%d, %d, %d) = %d, want %d", tt.rowSize, tt.dataRows, tt.interval, got, tt.want)
+		}
+	}
+
+	// The estimate matches the size of a file actually written.
+	for _, n := range []int{0, 99, 100, 250} {
+		config := NewCreateConfig("estimate.fdb", confRowSize, confSkewMs)
+		config.SetChecksumInterval(MIN_CHECKSUM_INTERVAL)
+		entries := make([]Entry, n)
+		for i := range entries {
+			entries[i] = Entry{Key: uuidFromTS(1000 + i), Value: json.RawMessage(`{}`)}
+		}
+		var built bytes.Buffer
+		if err := BuildDatabase(&built, config, entries); err != nil {
+			t.Fatalf("BuildDatabase: %v", err)
+		}
+		if got := EstimatedSize(confRowSize, int64(n), MIN_CHECKSUM_INTERVAL); got != int64(built.Len()) {
+			t.Errorf("EstimatedSize for %d rows = %d, built file has %d bytes", n, got, built.Len())
+		}
+	}
+}
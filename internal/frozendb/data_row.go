@@ -49,7 +49,7 @@ func (drp *DataRowPayload) UnmarshalText(text []byte) error {
 
 	// Extract Base64 UUID (first 24 bytes)
 	uuidBase64 := string(text[0:24])
-	decoded, err := base64.StdEncoding.DecodeString(uuidBase64)
+	decoded, err := base64.StdEncoding.Strict().DecodeString(uuidBase64)
 	if err != nil {
 		return NewInvalidInputError("invalid Base64 encoding for UUID", err)
 	}
@@ -167,11 +167,30 @@ func validateStartControlForDataRow(startControl StartControl) error {
 	return nil
 }
 
+// MaxValueSize returns the largest value, in bytes, that fits in a data row of
+// rowSize bytes: the row minus ROW_START, start_control, the 24-byte Base64 key,
+// end_control, parity, ROW_END, and two bytes of NULL_BYTE padding. Readers find
+// the end of the value by the first NULL_BYTE before position N-6, so a longer
+// value could be written but not read back. Values are measured as stored, so in
+// a database with value compression the limit applies to the compressed value.
+func MaxValueSize(rowSize int) int {
+	return rowSize - minDataRowOverhead - 24
+}
+
+// minDataRowOverhead is the framing of a data row around its payload: ROW_START,
+// start_control, end_control, parity, ROW_END, and the minimum padding.
+const minDataRowOverhead = 9
+
 func validatePayloadSize(payload *DataRowPayload, rowSize int) error {
 	payloadSize := 24 + len(payload.Value)
-	requiredSize := payloadSize + 7
+	requiredSize := payloadSize + minDataRowOverhead
 	if requiredSize > rowSize {
-		return NewInvalidInputError(fmt.Sprintf("payload size (%d bytes) exceeds ROW_SIZE (%d bytes); maximum payload size is %d bytes", payloadSize, rowSize, rowSize-7), nil)
+		maxSize := MaxValueSize(rowSize)
+		return NewValueTooLargeError(
+			fmt.Sprintf("value size (%d bytes) exceeds the maximum of %d bytes for ROW_SIZE %d", len(payload.Value), maxSize, rowSize),
+			len(payload.Value), maxSize,
+			NewInvalidInputError(fmt.Sprintf("payload size (%d bytes) exceeds ROW_SIZE (%d bytes); maximum payload size is %d bytes", payloadSize, rowSize, rowSize-minDataRowOverhead), nil),
+		)
 	}
 	return nil
 }
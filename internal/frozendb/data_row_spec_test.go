@@ -616,8 +616,8 @@ func Test_S_005_FR_009_RowSizeValidation(t *testing.T) {
 
 	// Test that validation rejects payloads that would exceed ROW_SIZE
 	t.Run("rejects_payload_exceeding_row_size", func(t *testing.T) {
-		// Calculate maximum JSON value size: row_size - 7 (fixed overhead) - 24 (Base64 UUID) = 481 bytes
-		maxJsonValueSize := 512 - 7 - 24
+		// Maximum JSON value size: row_size - 9 (framing and minimum padding) - 24 (Base64 UUID) = 479 bytes
+		maxJsonValueSize := MaxValueSize(512)
 
 		// Create a JSON value that exceeds the maximum by 1 byte
 		excessiveValue := make([]byte, maxJsonValueSize+1)
@@ -643,19 +643,24 @@ func Test_S_005_FR_009_RowSizeValidation(t *testing.T) {
 			t.Error("Validate() should reject payload that exceeds ROW_SIZE")
 		}
 		if err != nil {
-			// Verify it's an InvalidInputError
-			if invalidErr, ok := err.(*InvalidInputError); !ok {
-				t.Errorf("Expected InvalidInputError, got %T: %v", err, err)
-			} else if invalidErr.Code != "invalid_input" {
-				t.Errorf("Expected error code 'invalid_input', got '%s'", invalidErr.Code)
+			// Verify it's a ValueTooLargeError wrapping an InvalidInputError
+			var sizeErr *ValueTooLargeError
+			if !errors.As(err, &sizeErr) {
+				t.Errorf("Expected ValueTooLargeError, got %T: %v", err, err)
+			} else if sizeErr.ValueSize != maxJsonValueSize+1 || sizeErr.MaxSize != maxJsonValueSize {
+				t.Errorf("Expected sizes %d and %d, got %d and %d", maxJsonValueSize+1, maxJsonValueSize, sizeErr.ValueSize, sizeErr.MaxSize)
+			}
+			var invalidErr *InvalidInputError
+			if !errors.As(err, &invalidErr) || invalidErr.Code != "invalid_input" {
+				t.Errorf("Expected a wrapped InvalidInputError, got %v", err)
 			}
 		}
 	})
 
 	// Test that validation accepts payloads that are exactly at the maximum allowed size
 	t.Run("accepts_payload_at_maximum_size", func(t *testing.T) {
-		// Calculate maximum JSON value size: row_size - 7 (fixed overhead) - 24 (Base64 UUID) = 481 bytes
-		maxJsonValueSize := 512 - 7 - 24
+		// Maximum JSON value size: row_size - 9 (framing and minimum padding) - 24 (Base64 UUID) = 479 bytes
+		maxJsonValueSize := MaxValueSize(512)
 
 		// Create a JSON value that is exactly at the maximum
 		maxValue := make([]byte, maxJsonValueSize)
@@ -689,6 +694,12 @@ func Test_S_005_FR_009_RowSizeValidation(t *testing.T) {
 		if len(rowBytes) != 512 {
 			t.Errorf("Marshaled row size mismatch: expected %d, got %d", 512, len(rowBytes))
 		}
+
+		// Verify the row reads back
+		parsed := &DataRow{}
+		if err := parsed.UnmarshalText(rowBytes); err != nil {
+			t.Errorf("UnmarshalText() should accept a row holding the maximum value, got error: %v", err)
+		}
 	})
 }
 
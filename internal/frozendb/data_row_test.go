@@ -2,7 +2,10 @@ package frozendb
 
 import (
 	"encoding/json"
+	"errors"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -164,6 +167,15 @@ func TestDataRowPayload_Validate(t *testing.T) {
 	}
 }
 
+func TestTimestampOf(t *testing.T) {
+	key := uuidFromTS(1_700_000_000_123)
+	got := TimestampOf(key)
+	want := time.UnixMilli(1_700_000_000_123).UTC()
+	if !got.Equal(want) || got.Location() != time.UTC {
+		t.Errorf("TimestampOf() = %v, want %v", got, want)
+	}
+}
+
 func TestValidateUUIDv7(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -444,3 +456,40 @@ func TestDataRow_Validate(t *testing.T) {
 		})
 	}
 }
+
+func TestMaxValueSize(t *testing.T) {
+	dir := t.TempDir()
+	db, err := NewFrozenDB(setupCreate(t, dir, 0), MODE_WRITE, FinderStrategySimple)
+	if err != nil {
+		t.Fatalf("NewFrozenDB: %v", err)
+	}
+	defer db.Close()
+
+	maxSize := MaxValueSize(confRowSize)
+	// A JSON string of exactly maxSize bytes fits
+	fits := json.RawMessage(`"` + strings.Repeat("a", maxSize-2) + `"`)
+	tx, err := db.BeginTx()
+	if err != nil {
+		t.Fatalf("BeginTx: %v", err)
+	}
+	if err := tx.AddRow(uuidFromTS(1000), fits); err != nil {
+		t.Fatalf("AddRow with a %d-byte value: %v", len(fits), err)
+	}
+
+	tooLarge := json.RawMessage(`"` + strings.Repeat("a", maxSize-1) + `"`)
+	err = tx.AddRow(uuidFromTS(1001), tooLarge)
+	var sizeErr *ValueTooLargeError
+	if !errors.As(err, &sizeErr) {
+		t.Fatalf("expected ValueTooLargeError, got %v", err)
+	}
+	if sizeErr.ValueSize != maxSize+1 || sizeErr.MaxSize != maxSize {
+		t.Errorf("ValueTooLargeError sizes = %d, %d; want %d, %d", sizeErr.ValueSize, sizeErr.MaxSize, maxSize+1, maxSize)
+	}
+	var invalidInput *InvalidInputError
+	if !errors.As(err, &invalidInput) {
+		t.Errorf("ValueTooLargeError should wrap InvalidInputError, got %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+}
@@ -208,3 +208,183 @@ func NewInvalidDataError(message string, err error) *InvalidDataError {
 type InvalidDataError struct {
 	FrozenDBError
 }
+
+// NewDuplicateKeyError creates a new DuplicateKeyError.
+func NewDuplicateKeyError(message string, err error) *DuplicateKeyError {
+	return &DuplicateKeyError{
+		FrozenDBError: FrozenDBError{
+			Code:    "duplicate_key",
+			Message: message,
+			Err:     err,
+		},
+	}
+}
+
+// DuplicateKeyError is returned when a key is added twice within one transaction.
+// Used for: AddRow() with a key already added earlier in the same transaction.
+type DuplicateKeyError struct {
+	FrozenDBError
+}
+
+// NewUnsupportedVersionError creates a new UnsupportedVersionError.
+func NewUnsupportedVersionError(message string, err error) *UnsupportedVersionError {
+	return &UnsupportedVersionError{
+		FrozenDBError: FrozenDBError{
+			Code:    "unsupported_version",
+			Message: message,
+			Err:     err,
+		},
+	}
+}
+
+// UnsupportedVersionError is returned when a file's header declares a format version
+// this build does not know how to read, typically a file written by a newer frozendb.
+// It wraps a CorruptDatabaseError, so checks written for that error still match.
+// Used for: opening, verifying, or inspecting a file with an unregistered header version.
+type UnsupportedVersionError struct {
+	FrozenDBError
+}
+
+// NewTruncatedFileError creates a new TruncatedFileError.
+func NewTruncatedFileError(message string, rowIndex, expectedSize, actualSize int64, err error) *TruncatedFileError {
+	return &TruncatedFileError{
+		FrozenDBError: FrozenDBError{
+			Code:    "truncated_file",
+			Message: message,
+			Err:     err,
+		},
+		RowIndex:     rowIndex,
+		ExpectedSize: expectedSize,
+		ActualSize:   actualSize,
+	}
+}
+
+// TruncatedFileError is returned when the file ends partway through a row at a point
+// no writer stops at, meaning the file was cut off rather than its bytes being wrong.
+// It wraps a CorruptDatabaseError, so checks written for that error still match.
+// Used for: opening, reading, or verifying a file whose trailing row is incomplete
+// and is not a valid PartialDataRow.
+type TruncatedFileError struct {
+	FrozenDBError
+	RowIndex     int64 // Index of the incomplete row
+	ExpectedSize int64 // File size at which the row would be complete
+	ActualSize   int64 // Actual file size
+}
+
+// NewValueTooLargeError creates a new ValueTooLargeError.
+func NewValueTooLargeError(message string, valueSize, maxSize int, err error) *ValueTooLargeError {
+	return &ValueTooLargeError{
+		FrozenDBError: FrozenDBError{
+			Code:    "value_too_large",
+			Message: message,
+			Err:     err,
+		},
+		ValueSize: valueSize,
+		MaxSize:   maxSize,
+	}
+}
+
+// ValueTooLargeError is returned when a value does not fit in a row of the
+// database's row_size. It wraps an InvalidInputError, so checks written for that
+// error still match.
+// Used for: AddRow(), batches, and BuildDatabase() with a value larger than
+// MaxValueSize(row_size).
+type ValueTooLargeError struct {
+	FrozenDBError
+	ValueSize int // Size of the value as it would be stored, in bytes
+	MaxSize   int // MaxValueSize for the row_size
+}
+
+// NewKeyOrderViolationError creates a new KeyOrderViolationError.
+func NewKeyOrderViolationError(message string, index int, timestamp, maxTimestamp int64, err error) *KeyOrderViolationError {
+	return &KeyOrderViolationError{
+		FrozenDBError: FrozenDBError{
+			Code:    "key_order_violation",
+			Message: message,
+			Err:     err,
+		},
+		Index:        index,
+		Timestamp:    timestamp,
+		MaxTimestamp: maxTimestamp,
+	}
+}
+
+// KeyOrderViolationError locates the first key of a sequence that breaks the
+// ordering rule. It wraps a KeyOrderingError, so checks written for that error
+// still match.
+// Used for: ValidateKeyOrder() and BuildDatabase() entries.
+type KeyOrderViolationError struct {
+	FrozenDBError
+	Index        int   // Position of the rejected key in the sequence
+	Timestamp    int64 // Timestamp of the rejected key, in milliseconds
+	MaxTimestamp int64 // Largest timestamp before it: the database's and earlier keys'
+}
+
+// NewRowSizeMismatchError creates a new RowSizeMismatchError.
+func NewRowSizeMismatchError(message string, headerRowSize, observedRowSize int, err error) *RowSizeMismatchError {
+	return &RowSizeMismatchError{
+		FrozenDBError: FrozenDBError{
+			Code:    "row_size_mismatch",
+			Message: message,
+			Err:     err,
+		},
+		HeaderRowSize:   headerRowSize,
+		ObservedRowSize: observedRowSize,
+	}
+}
+
+// RowSizeMismatchError is returned when the rows of a file are framed at a
+// different size than the header's row_size. It wraps a CorruptDatabaseError, so
+// checks written for that error still match.
+// Used for: opening a file whose initial checksum row does not end at row_size.
+type RowSizeMismatchError struct {
+	FrozenDBError
+	HeaderRowSize   int // row_size from the header
+	ObservedRowSize int // Size of the initial checksum row as written
+}
+
+// NewFutureTimestampError creates a new FutureTimestampError.
+func NewFutureTimestampError(message string, timestamp, now int64, err error) *FutureTimestampError {
+	return &FutureTimestampError{
+		FrozenDBError: FrozenDBError{
+			Code:    "future_timestamp",
+			Message: message,
+			Err:     err,
+		},
+		Timestamp: timestamp,
+		Now:       now,
+	}
+}
+
+// FutureTimestampError is returned when a key's timestamp is further ahead of the
+// system clock than skew_ms allows. It wraps an InvalidInputError, so checks
+// written for that error still match.
+// Used for: AddRow() and Delete() on a database opened with WithFutureGuard(true).
+type FutureTimestampError struct {
+	FrozenDBError
+	Timestamp int64 // Timestamp of the rejected key, in milliseconds
+	Now       int64 // System clock when the key was rejected, in milliseconds
+}
+
+// NewReadPastEndError creates a new ReadPastEndError.
+func NewReadPastEndError(message string, readEnd, fileSize int64, err error) *ReadPastEndError {
+	return &ReadPastEndError{
+		FrozenDBError: FrozenDBError{
+			Code:    "read_past_end",
+			Message: message,
+			Err:     err,
+		},
+		ReadEnd:  readEnd,
+		FileSize: fileSize,
+	}
+}
+
+// ReadPastEndError is returned when a read asks for bytes past the end of the
+// file, such as a full row where only a partial row has been written. It wraps an
+// InvalidInputError, so checks written for that error still match.
+// Used for: DBFile Read() and ReadInto() beyond Size().
+type ReadPastEndError struct {
+	FrozenDBError
+	ReadEnd  int64 // Offset just past the last byte requested
+	FileSize int64 // Size of the file when the read was rejected
+}
@@ -2,12 +2,14 @@ package frozendb
 
 import (
 	"errors"
+	"fmt"
 	"math"
 	"os"
 	"strings"
 	"sync"
 	"sync/atomic"
 	"syscall"
+	"time"
 
 	"github.com/fsnotify/fsnotify"
 )
@@ -47,9 +49,21 @@ type FileManager struct {
 	subscribers  *Subscriber[func() error]
 	watcher      *fsnotify.Watcher // File system watcher (nil in write mode, non-nil in read mode)
 	path         string            // Database file path (stored for watcher)
+	locked       bool              // Whether an exclusive flock is held and must be released on Close
+	updateMu     sync.Mutex        // Serializes processFileUpdate between the watcher and Refresh
+	logger       Logger            // Receives lock events (nil discards them)
+	sink         atomic.Value      // stores replicationSink (write mode only, see SetReplicationSink)
+	writeBuffer  int               // Capacity of write channels created for this file (see WithWriteBuffer)
+	writer       *fileID           // Entry in writeOpens released on Close (nil if none)
 }
 
-func NewFileManager(filePath string) (*FileManager, error) {
+// DEFAULT_WRITE_BUFFER is the capacity of the write channel a transaction creates
+// for a FileManager, unless overridden with WithWriteBuffer.
+const DEFAULT_WRITE_BUFFER = 100
+
+// NewFileManager opens filePath for reading and appending, with no lock and no
+// file watcher. Of opts, only WithWriteBuffer applies.
+func NewFileManager(filePath string, opts ...OpenOption) (*FileManager, error) {
 	if filePath == "" {
 		return nil, NewInvalidInputError("file path cannot be empty", nil)
 	}
@@ -67,6 +81,7 @@ func NewFileManager(filePath string) (*FileManager, error) {
 
 	fm := &FileManager{
 		subscribers: NewSubscriber[func() error](),
+		writeBuffer: newOpenOptions(opts).writeBuffer,
 	}
 	fm.file.Store(file)
 	fm.writeChannel.Store((<-chan Data)(nil))
@@ -84,6 +99,15 @@ func NewFileManager(filePath string) (*FileManager, error) {
 //   - DBFile: Interface implementation configured with mode-specific behavior
 //   - error: InvalidInputError, PathError, or WriteError
 func NewDBFile(path string, mode string) (DBFile, error) {
+	return newDBFile(path, mode, newOpenOptions(nil))
+}
+
+// newDBFile implements NewDBFile with the lock, logger, and write buffer settings
+// of opts.
+// opts.noLock skips the exclusive flock in MODE_WRITE; it is ignored in MODE_READ.
+func newDBFile(path string, mode string, opts openOptions) (DBFile, error) {
+	lock := !opts.noLock
+
 	// Validate mode
 	if mode != MODE_READ && mode != MODE_WRITE {
 		return nil, NewInvalidInputError("mode must be 'read' or 'write'", nil)
@@ -125,6 +149,9 @@ func NewDBFile(path string, mode string) (DBFile, error) {
 		mode:        mode,
 		subscribers: NewSubscriber[func() error](),
 		path:        path,
+		locked:      mode == MODE_WRITE && lock,
+		logger:      opts.logger,
+		writeBuffer: opts.writeBuffer,
 	}
 	fm.file.Store(file)
 	fm.writeChannel.Store((<-chan Data)(nil))
@@ -153,55 +180,218 @@ func NewDBFile(path string, mode string) (DBFile, error) {
 	}
 
 	// Acquire lock if write mode
-	if mode == MODE_WRITE {
-		lockMode := syscall.LOCK_EX | syscall.LOCK_NB
-		err = syscall.Flock(int(file.Fd()), lockMode)
+	if mode == MODE_WRITE && lock {
+		// Refuse a second writer in this process before asking the OS, whose locks
+		// are per process on some platforms
+		deadline := time.Now().Add(opts.lockTimeout)
+		id, err := registerWriter(fileInfo, opts.lockTimeout)
 		if err != nil {
+			_ = file.Close()
+			opts.logger.Warnf("frozendb: %s is locked by another writer in this process", path)
+			return nil, err
+		}
+		fm.writer = id
+
+		err = flockWithTimeout(int(file.Fd()), max(time.Until(deadline), 0))
+		if err != nil {
+			releaseWriter(id)
 			_ = file.Close()
 			if err == syscall.EWOULDBLOCK {
+				opts.logger.Warnf("frozendb: %s is locked by another writer", path)
+				if opts.lockTimeout > 0 {
+					return nil, NewWriteError(fmt.Sprintf("another process has the database locked (waited %s)", opts.lockTimeout), err)
+				}
 				return nil, NewWriteError("another process has the database locked", err)
 			}
+			opts.logger.Warnf("frozendb: failed to lock %s: %v", path, err)
 			return nil, NewWriteError("failed to acquire file lock", err)
 		}
+		opts.logger.Debugf("frozendb: acquired exclusive lock on %s", path)
+
+		// The previous writer may have appended while we waited for the lock
+		if opts.lockTimeout > 0 {
+			fileInfo, err := file.Stat()
+			if err != nil {
+				_ = syscall.Flock(int(file.Fd()), syscall.LOCK_UN)
+				releaseWriter(id)
+				_ = file.Close()
+				return nil, NewPathError("failed to stat file", err)
+			}
+			fm.currentSize.Store(uint64(fileInfo.Size()))
+		}
+	} else if mode == MODE_WRITE {
+		opts.logger.Debugf("frozendb: opened %s for writing without an exclusive lock", path)
 	}
 
 	return fm, nil
 }
 
-func (fm *FileManager) Read(start int64, size int32) ([]byte, error) {
-	if start < 0 {
-		return nil, NewInvalidInputError("start offset cannot be negative", nil)
+// fileID identifies a file by device and inode, so different paths to the same
+// file compare equal.
+type fileID struct {
+	dev, ino uint64
+}
+
+// writeOpens holds the files this process has open in MODE_WRITE with the
+// exclusive lock. flock excludes a second writer in the same process on Linux,
+// but advisory locks are per process on some platforms; the registry refuses the
+// second writer regardless. Writers opened with WithoutLock are not registered.
+var writeOpens = struct {
+	sync.Mutex
+	files map[fileID]bool
+}{files: make(map[fileID]bool)}
+
+// registerWriter records the file described by info in writeOpens, retrying as
+// flockWithTimeout does for up to timeout while another writer in this process
+// holds it, and returns WriteError if it is still held. A nil id means the
+// platform does not identify files by inode and nothing was registered.
+func registerWriter(info os.FileInfo, timeout time.Duration) (*fileID, error) {
+	st, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return nil, nil
+	}
+	id := &fileID{dev: uint64(st.Dev), ino: uint64(st.Ino)}
+	deadline := time.Now().Add(timeout)
+	delay := lockRetryInitialDelay
+	for {
+		writeOpens.Lock()
+		held := writeOpens.files[*id]
+		if !held {
+			writeOpens.files[*id] = true
+		}
+		writeOpens.Unlock()
+		if !held {
+			return id, nil
+		}
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			if timeout > 0 {
+				return nil, NewWriteError(fmt.Sprintf("the database is already locked by a writer in this process (waited %s)", timeout), nil)
+			}
+			return nil, NewWriteError("the database is already locked by a writer in this process", nil)
+		}
+		time.Sleep(min(delay, remaining))
+		delay = min(2*delay, lockRetryMaxDelay)
+	}
+}
+
+// releaseWriter removes id, as returned by registerWriter, from writeOpens.
+func releaseWriter(id *fileID) {
+	if id == nil {
+		return
+	}
+	writeOpens.Lock()
+	defer writeOpens.Unlock()
+	delete(writeOpens.files, *id)
+}
+
+// Backoff between attempts to take a lock held by another process.
+const (
+	lockRetryInitialDelay = 5 * time.Millisecond
+	lockRetryMaxDelay     = 250 * time.Millisecond
+)
+
+// flockWithTimeout takes an exclusive flock on fd, retrying with exponential backoff
+// while another process holds it, for up to timeout. It returns EWOULDBLOCK if the
+// lock is still held at the deadline; with a zero timeout it tries exactly once.
+func flockWithTimeout(fd int, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	delay := lockRetryInitialDelay
+	for {
+		err := syscall.Flock(fd, syscall.LOCK_EX|syscall.LOCK_NB)
+		if err != syscall.EWOULDBLOCK {
+			return err
+		}
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			return err
+		}
+		time.Sleep(min(delay, remaining))
+		delay = min(2*delay, lockRetryMaxDelay)
 	}
+}
+
+func (fm *FileManager) Read(start int64, size int32) ([]byte, error) {
 	if size <= 0 {
 		return nil, NewInvalidInputError("size must be positive", nil)
 	}
-	// Guaranteed not to overflow because start is int64 and size is int32
+	data := make([]byte, size)
+	if err := fm.ReadInto(data, start); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// newReadPastEndError reports a read of n bytes at start in a file of fileSize
+// bytes. A read end past math.MaxInt64 is reported as math.MaxInt64.
+func newReadPastEndError(start int64, n int, fileSize int64) *ReadPastEndError {
+	readEnd := int64(math.MaxInt64)
+	if start <= math.MaxInt64-int64(n) {
+		readEnd = start + int64(n)
+	}
+	return NewReadPastEndError(
+		fmt.Sprintf("read exceeds file size: read ends at %d, file is %d bytes", readEnd, fileSize),
+		readEnd, fileSize,
+		NewInvalidInputError("read exceeds file size", nil),
+	)
+}
+
+// ReadInto fills dst with the len(dst) bytes starting at offset start.
+// It behaves like Read but lets the caller reuse dst across calls, so the read
+// itself does not allocate.
+func (fm *FileManager) ReadInto(dst []byte, start int64) error {
+	if start < 0 {
+		return NewInvalidInputError("start offset cannot be negative", nil)
+	}
+	if len(dst) == 0 || len(dst) > math.MaxInt32 {
+		return NewInvalidInputError("size must be positive", nil)
+	}
+	// Guaranteed not to overflow because start is int64 and len(dst) fits in int32
 	// Thus, the max value is MAX_INT64 + MAX_INT32 < MAX_UINT64
-	if uint64(start)+uint64(size) > fm.currentSize.Load() {
-		return nil, NewInvalidInputError("read exceeds file size", nil)
+	if size := fm.currentSize.Load(); uint64(start)+uint64(len(dst)) > size {
+		return newReadPastEndError(start, len(dst), int64(size))
 	}
 
-	data := make([]byte, size)
 	file, err := fm.getFile()
 	if err != nil {
-		return nil, err
+		return err
 	}
-	_, err = file.ReadAt(data, start)
+	_, err = file.ReadAt(dst, start)
 	if err != nil {
 		// If there's a race, and Close() is called before the read, detect that and wrap the correct frozendDB error
 		if errors.Is(err, os.ErrClosed) {
-			return nil, NewTombstonedError("file manager is closed", err)
+			return NewTombstonedError("file manager is closed", err)
 		}
-		return nil, NewCorruptDatabaseError("failed to read from file", err)
+		return NewCorruptDatabaseError("failed to read from file", err)
 	}
-
-	return data, nil
+	return nil
 }
 
 func (fm *FileManager) Size() int64 {
 	return int64(fm.currentSize.Load())
 }
 
+// Refresh synchronously picks up growth of the file by another process: it
+// updates Size and runs the subscriber callbacks before returning. The file
+// watcher does the same asynchronously, so without Refresh a read handle may not
+// yet see rows committed moments earlier. Bytes below the previous size never
+// change, so subscribers only process the newly appended rows.
+//
+// Refresh is a no-op in write mode, where Size already tracks this handle's writes,
+// and after Close.
+func (fm *FileManager) Refresh() {
+	if fm.mode != MODE_READ || fm.file.Load().(*os.File) == nil {
+		return
+	}
+	fm.processFileUpdate()
+}
+
+// WriteBufferSize returns the capacity of the write channels transactions create
+// for this file (see WithWriteBuffer).
+func (fm *FileManager) WriteBufferSize() int {
+	return fm.writeBuffer
+}
+
 func (fm *FileManager) GetMode() string {
 	return fm.mode
 }
@@ -249,16 +439,33 @@ func (fm *FileManager) Close() error {
 
 	file := fm.file.Load().(*os.File)
 	if file != nil && fm.file.CompareAndSwap(file, (*os.File)(nil)) {
-		// Release lock if in write mode
-		if fm.mode == MODE_WRITE {
+		// Release lock if one was taken
+		if fm.locked {
 			_ = syscall.Flock(int(file.Fd()), syscall.LOCK_UN)
+			loggerOrNop(fm.logger).Debugf("frozendb: released exclusive lock on %s", fm.path)
 		}
+		releaseWriter(fm.writer)
 		// First time Close() was called, and also we won any race calling Close() multiple times
 		_ = file.Close()
 	}
 	return nil
 }
 
+// writeBuffered is implemented by DBFiles that choose the capacity of their write
+// channel (FileManager does, see WithWriteBuffer).
+type writeBuffered interface {
+	WriteBufferSize() int
+}
+
+// newWriteChan creates a write channel for f with the capacity f asks for, or
+// DEFAULT_WRITE_BUFFER if f does not implement writeBuffered.
+func newWriteChan(f DBFile) chan Data {
+	if wb, ok := f.(writeBuffered); ok {
+		return make(chan Data, wb.WriteBufferSize())
+	}
+	return make(chan Data, DEFAULT_WRITE_BUFFER)
+}
+
 func (fm *FileManager) SetWriter(dataChan <-chan Data) error {
 	// Check if in read mode
 	if fm.mode == MODE_READ {
@@ -333,6 +540,7 @@ func (fm *FileManager) processWrite(bytes []byte) error {
 		return NewWriteError("failed to write data", writeErr)
 	}
 	fm.currentSize.Add(appendSize)
+	fm.replicate(bytes)
 
 	// Notify subscribers after successful write
 	snapshot := fm.subscribers.Snapshot()
@@ -390,7 +598,13 @@ func (fm *FileManager) fileWatcherLoop() {
 // 1. Reads current file size
 // 2. Updates currentSize atomically
 // 3. Invokes subscriber callbacks if size changed
+//
+// Cycles are serialized, so a caller returning from Refresh knows that any update
+// already started by the watcher has finished notifying subscribers.
 func (fm *FileManager) processFileUpdate() {
+	fm.updateMu.Lock()
+	defer fm.updateMu.Unlock()
+
 	// Read current file size
 	fileInfo, err := os.Stat(fm.path)
 	if err != nil {
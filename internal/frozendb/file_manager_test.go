@@ -1,6 +1,9 @@
 package frozendb
 
 import (
+	"errors"
+	"fmt"
+	"math"
 	"os"
 	"runtime"
 	"sync"
@@ -145,8 +148,11 @@ func TestFileManager_ReadBoundaryConditions(t *testing.T) {
 			if tt.wantErrType != nil {
 				if err == nil {
 					t.Errorf("Read() expected error, got nil")
-				} else if _, ok := err.(*InvalidInputError); !ok {
-					t.Errorf("Read() expected InvalidInputError, got %T", err)
+				} else {
+					var invalidErr *InvalidInputError
+					if !errors.As(err, &invalidErr) {
+						t.Errorf("Read() expected InvalidInputError, got %T", err)
+					}
 				}
 			} else {
 				if err != nil {
@@ -1099,9 +1105,14 @@ func TestFileManager_Int64OverflowProtection(t *testing.T) {
 	if err == nil {
 		t.Error("Read with overflow should fail")
 	}
-	if _, ok := err.(*InvalidInputError); !ok {
+	var invalidErr *InvalidInputError
+	if !errors.As(err, &invalidErr) {
 		t.Errorf("Expected InvalidInputError, got %T: %v", err, err)
 	}
+	var pastEnd *ReadPastEndError
+	if !errors.As(err, &pastEnd) || pastEnd.ReadEnd != math.MaxInt64 || pastEnd.FileSize != 4 {
+		t.Errorf("Expected ReadPastEndError ending at MaxInt64 in a 4-byte file, got %v", err)
+	}
 }
 
 func TestFileManager_EmptyWrite(t *testing.T) {
@@ -1663,3 +1674,129 @@ func TestFileManager_WriterClosed(t *testing.T) {
 		wg.Wait()
 	})
 }
+
+func TestFileManager_RefreshPicksUpExternalAppends(t *testing.T) {
+	path := t.TempDir() + "/refresh.fdb"
+	createTestDatabase(t, path)
+
+	dbFile, err := NewDBFile(path, MODE_READ)
+	if err != nil {
+		t.Fatalf("NewDBFile: %v", err)
+	}
+	defer dbFile.Close()
+
+	var notifications atomic.Int32
+	if _, err := dbFile.Subscribe(func() error {
+		notifications.Add(1)
+		return nil
+	}); err != nil {
+		t.Fatalf("Subscribe: %v", err)
+	}
+
+	// Append from another handle, as a writer in another process would
+	initialSize := dbFile.Size()
+	writer, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		t.Fatalf("OpenFile: %v", err)
+	}
+	if _, err := writer.Write([]byte("appended")); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	writer.Close()
+
+	// Refresh returns only after Size and the subscribers reflect the append,
+	// whether the watcher or Refresh performed the update
+	dbFile.(*FileManager).Refresh()
+	if got := dbFile.Size(); got != initialSize+8 {
+		t.Errorf("Size after Refresh = %d, want %d", got, initialSize+8)
+	}
+	if got := notifications.Load(); got != 1 {
+		t.Errorf("notifications after Refresh = %d, want 1", got)
+	}
+
+	// Without further growth, Refresh does not notify again
+	dbFile.(*FileManager).Refresh()
+	if got := notifications.Load(); got != 1 {
+		t.Errorf("notifications after second Refresh = %d, want 1", got)
+	}
+}
+
+func TestFileManager_WithWriteBuffer(t *testing.T) {
+	t.Parallel()
+
+	tmpFile, err := os.CreateTemp("", "frozendb_test_*.fdb")
+	if err != nil {
+		t.Fatalf("Failed to create temp file: %v", err)
+	}
+	defer os.Remove(tmpFile.Name())
+	tmpFile.Close()
+
+	tests := []struct {
+		name string
+		opts []OpenOption
+		want int
+	}{
+		{name: "default", want: DEFAULT_WRITE_BUFFER},
+		{name: "custom", opts: []OpenOption{WithWriteBuffer(1000)}, want: 1000},
+		{name: "zero raised to one", opts: []OpenOption{WithWriteBuffer(0)}, want: 1},
+		{name: "negative raised to one", opts: []OpenOption{WithWriteBuffer(-5)}, want: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fm, err := NewFileManager(tmpFile.Name(), tt.opts...)
+			if err != nil {
+				t.Fatalf("NewFileManager: %v", err)
+			}
+			defer fm.Close()
+
+			if got := fm.WriteBufferSize(); got != tt.want {
+				t.Errorf("WriteBufferSize() = %d, want %d", got, tt.want)
+			}
+			if got := cap(newWriteChan(fm)); got != tt.want {
+				t.Errorf("cap(newWriteChan()) = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+// BenchmarkFileManager_WriteBuffer measures concurrent senders that each wait for
+// their write to complete, as transactions do, across write channel capacities.
+func BenchmarkFileManager_WriteBuffer(b *testing.B) {
+	payload := make([]byte, 128)
+	for _, size := range []int{1, 10, 100, 1000} {
+		b.Run(fmt.Sprintf("buffer=%d", size), func(b *testing.B) {
+			path := b.TempDir() + "/bench.fdb"
+			if err := os.WriteFile(path, nil, 0644); err != nil {
+				b.Fatalf("WriteFile: %v", err)
+			}
+			fm, err := NewFileManager(path, WithWriteBuffer(size))
+			if err != nil {
+				b.Fatalf("NewFileManager: %v", err)
+			}
+			defer fm.Close()
+
+			dataChan := newWriteChan(fm)
+			if err := fm.SetWriter(dataChan); err != nil {
+				b.Fatalf("SetWriter: %v", err)
+			}
+
+			b.SetBytes(int64(len(payload)))
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				response := make(chan error, 1)
+				for pb.Next() {
+					dataChan <- Data{Bytes: payload, Response: response}
+					if err := <-response; err != nil {
+						b.Error(err)
+						return
+					}
+				}
+			})
+			b.StopTimer()
+
+			close(dataChan)
+			fm.WriterClosed()
+		})
+	}
+}
@@ -1,6 +1,14 @@
 package frozendb
 
-import "github.com/google/uuid"
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/google/uuid"
+)
 
 // FinderStrategy selects the finder implementation when creating a FrozenDB.
 //
@@ -9,15 +17,277 @@ import "github.com/google/uuid"
 //     GetTransactionStart/End O(k) where k ≤ 101. Use when DB is large or memory is bounded.
 //   - FinderStrategyInMemory: ~40 bytes per row (uuid map + tx boundary maps); GetIndex,
 //     GetTransactionStart, GetTransactionEnd all O(1). Use when DB fits in memory and
-//     read-heavy workloads need low latency.
+//     read-heavy workloads need low latency. A file whose index would exceed the
+//     memory budget is opened with FinderStrategyBinarySearch instead.
+//   - FinderStrategyBinarySearch: O(row_size) fixed memory; GetIndex O(log n) over the
+//     timestamp-ordered keys.
+//   - FinderStrategyAuto: picks one of the above from the file size at open time
+//     (see resolveAutoFinderStrategy).
+//
+// The simple and binary search finders read only the rows at the end of the file to
+// find max_timestamp at open (see tailMaxTimestamp), so opening a large DB in write
+// mode does not scan it.
 type FinderStrategy string
 
 const (
 	FinderStrategySimple       FinderStrategy = "simple"
 	FinderStrategyInMemory     FinderStrategy = "inmemory"
 	FinderStrategyBinarySearch FinderStrategy = "binary_search"
+	FinderStrategyAuto         FinderStrategy = "auto"
+)
+
+const (
+	AUTO_SIMPLE_MAX_ROWS              = 1000     // Files with at most this many rows use the simple finder
+	INMEMORY_BYTES_PER_ROW            = 40       // Approximate InMemoryFinder memory use per row
+	DEFAULT_AUTO_FINDER_MEMORY_BUDGET = 64 << 20 // Default InMemoryFinder budget for FinderStrategyAuto (64 MiB)
+	MAX_FINDER_READ_AHEAD_BYTES       = 1 << 20  // Largest window WithFinderReadAhead reads at once (1 MiB)
 )
 
+// autoFinderMemoryBudget is the largest estimated InMemoryFinder footprint, in
+// bytes, for which FinderStrategyAuto selects the in-memory finder and
+// FinderStrategyInMemory is honored. WithFinderMemoryBudget overrides it per open.
+var autoFinderMemoryBudget atomic.Int64
+
+func init() {
+	autoFinderMemoryBudget.Store(DEFAULT_AUTO_FINDER_MEMORY_BUDGET)
+}
+
+// SetAutoFinderMemoryBudget sets the memory budget, in bytes, that the in-memory
+// finder may use, both when FinderStrategyAuto chooses it and when it is requested
+// with FinderStrategyInMemory (see WithFinderMemoryBudget). A budget of 0 disables
+// the in-memory finder. The setting applies to databases opened after the call.
+//
+// Returns InvalidInputError if bytes is negative.
+func SetAutoFinderMemoryBudget(bytes int64) error {
+	if bytes < 0 {
+		return NewInvalidInputError("memory budget cannot be negative", nil)
+	}
+	autoFinderMemoryBudget.Store(bytes)
+	return nil
+}
+
+// resolveAutoFinderStrategy picks the finder for FinderStrategyAuto from the file
+// size observed at open time:
+//   - At most AUTO_SIMPLE_MAX_ROWS rows: simple. A linear scan of a tiny file is
+//     cheap and needs no index.
+//   - Estimated in-memory index (rows * INMEMORY_BYTES_PER_ROW) within the memory
+//     budget: inmemory, for O(1) lookups.
+//   - Otherwise: binary_search, which keeps memory bounded on large files.
+//
+// The choice is not revisited as the file grows while open; an in-memory finder
+// that outgrows the budget logs a warning (see InMemoryFinder.setMemoryBudget).
+func resolveAutoFinderStrategy(fileSize int64, rowSize int, budget int64) FinderStrategy {
+	switch {
+	case fileRowCount(fileSize, rowSize) <= AUTO_SIMPLE_MAX_ROWS:
+		return FinderStrategySimple
+	case inMemoryFinderFits(fileSize, rowSize, budget):
+		return FinderStrategyInMemory
+	default:
+		return FinderStrategyBinarySearch
+	}
+}
+
+// inMemoryFinderFits reports whether the estimated InMemoryFinder index for a
+// file of fileSize bytes (rows * INMEMORY_BYTES_PER_ROW) is within budget bytes.
+func inMemoryFinderFits(fileSize int64, rowSize int, budget int64) bool {
+	return fileRowCount(fileSize, rowSize) <= budget/INMEMORY_BYTES_PER_ROW
+}
+
+// newInterleavedTransactionError reports that the transaction holding the row at
+// index had not ended when the row at next started another transaction. The
+// writer never produces this, so the rows of the unterminated transaction cannot
+// be attributed to any transaction end.
+func newInterleavedTransactionError(index, next int64) *CorruptDatabaseError {
+	return NewCorruptDatabaseError(fmt.Sprintf("transaction holding row %d is not terminated before row %d starts another transaction", index, next), nil)
+}
+
+// fileRowCount returns the number of complete rows after the header.
+func fileRowCount(fileSize int64, rowSize int) int64 {
+	if fileSize <= int64(HEADER_SIZE) {
+		return 0
+	}
+	return (fileSize - int64(HEADER_SIZE)) / int64(rowSize)
+}
+
+// occurrenceFinder is implemented by finders that can list every occurrence of a
+// key, so that a key written more than once resolves to its first committed
+// occurrence rather than to its first row. FrozenDB falls back to GetIndex for
+// finders that do not implement it.
+type occurrenceFinder interface {
+	// GetIndexes returns the indices of every complete DataRow containing key, in
+	// file order; the first is the index GetIndex returns. A key can only be
+	// written again while every timestamp in the file is below its own plus
+	// skewMs, so the search ends at the first row whose timestamp reaches that
+	// limit.
+	GetIndexes(key uuid.UUID, skewMs int64) ([]int64, error)
+}
+
+// tailMaxTimestamp returns the maximum timestamp among the first totalRows rows,
+// reading rows with readRow from the end of the file instead of scanning them all.
+//
+// Rows are read backward from the last complete row, skipping checksum, partial,
+// and unparseable rows. The key ordering rule guarantees every row written before
+// a row with timestamp ts has a timestamp < ts + skew_ms, and a NullRow carries
+// the max_timestamp at the time it was written. The walk therefore stops at the
+// first NullRow, or once the largest timestamp seen reaches the smallest timestamp
+// seen plus skewMs: no earlier row can exceed it. The rows read are those written
+// within about skewMs of the last key, usually just the last two.
+func tailMaxTimestamp(totalRows, skewMs int64, readRow func(index int64) ([]byte, error)) (int64, error) {
+	maxTimestamp := int64(0)
+	minTimestamp := int64(-1)
+
+	for i := totalRows - 1; i >= 0; i-- {
+		rowBytes, err := readRow(i)
+		if err != nil {
+			// Read error during initialization - fail immediately
+			return 0, err
+		}
+
+		var rowUnion RowUnion
+		if err := rowUnion.UnmarshalText(rowBytes); err != nil {
+			// Skip corrupted rows
+			continue
+		}
+
+		var timestamp int64
+		if rowUnion.DataRow != nil {
+			key := rowUnion.DataRow.GetKey()
+			if key == uuid.Nil || ValidateUUIDv7(key) != nil {
+				continue
+			}
+			timestamp = ExtractUUIDv7Timestamp(key)
+		} else if rowUnion.NullRow != nil {
+			// A NullRow key holds the max_timestamp of every row before it
+			timestamp = ExtractUUIDv7Timestamp(rowUnion.NullRow.GetKey())
+			return max(maxTimestamp, timestamp), nil
+		} else {
+			// Skip ChecksumRow and PartialDataRow
+			continue
+		}
+
+		maxTimestamp = max(maxTimestamp, timestamp)
+		if minTimestamp < 0 || timestamp < minTimestamp {
+			minTimestamp = timestamp
+		}
+		if maxTimestamp >= minTimestamp+skewMs {
+			return maxTimestamp, nil
+		}
+	}
+
+	return maxTimestamp, nil
+}
+
+// rowKeyPrefixSize is the number of bytes at the start of a row that hold its
+// start_control and the base64 encoding of its key: ROW_START, start_control,
+// and 24 key characters.
+const rowKeyPrefixSize = 2 + 24
+
+// rowKeyReader returns the first rowKeyPrefixSize bytes of the row at index.
+type rowKeyReader func(index int64) ([]byte, error)
+
+// readRowKeyPrefix implements rowKeyReader for finders, reading just the key
+// prefix from dbFile unless readAhead already holds the whole row.
+func readRowKeyPrefix(dbFile DBFile, readAhead *rowReadAhead, rowSize int32, index int64) ([]byte, error) {
+	if readAhead != nil {
+		row, err := readAhead.readRow(index)
+		if err != nil {
+			return nil, err
+		}
+		return row[:rowKeyPrefixSize], nil
+	}
+	return dbFile.Read(HEADER_SIZE+index*int64(rowSize), rowKeyPrefixSize)
+}
+
+// parseRowKeyPrefix returns the start_control of a row read by a rowKeyReader
+// and, unless it is a checksum row, the timestamp of its key. The first 8 base64
+// characters of the key encode the 48-bit timestamp exactly.
+func parseRowKeyPrefix(prefix []byte, index int64) (StartControl, int64, error) {
+	if prefix[0] != ROW_START {
+		return 0, 0, NewCorruptDatabaseError(fmt.Sprintf("failed to parse row at index %d", index),
+			NewInvalidInputError(fmt.Sprintf("invalid ROW_START: expected 0x%02X, got 0x%02X", ROW_START, prefix[0]), nil))
+	}
+	startControl := StartControl(prefix[1])
+	if err := startControl.Validate(); err != nil {
+		return 0, 0, NewCorruptDatabaseError(fmt.Sprintf("failed to parse row at index %d", index), err)
+	}
+	if startControl == CHECKSUM_ROW {
+		return startControl, 0, nil
+	}
+	var ts [8]byte
+	if _, err := base64.StdEncoding.Decode(ts[2:], prefix[2:10]); err != nil {
+		return 0, 0, NewCorruptDatabaseError(fmt.Sprintf("invalid key encoding at index %d", index), err)
+	}
+	return startControl, int64(binary.BigEndian.Uint64(ts[:])), nil
+}
+
+// firstOccurrence returns the lowest index at or below found that holds key, so
+// every finder resolves a repeated key to its first row. Reading stops at the
+// first data or null row whose timestamp is at least skewMs below key's: a row
+// may only be appended after an earlier occurrence of key if its timestamp is
+// within skewMs of it, so no occurrence can precede such a row.
+//
+// Only the key prefix of each row is read and compared in base64 form.
+func firstOccurrence(key uuid.UUID, skewMs, found int64, readKey rowKeyReader) (int64, error) {
+	floor := ExtractUUIDv7Timestamp(key) - skewMs
+	var encodedKey [24]byte
+	base64.StdEncoding.Encode(encodedKey[:], key[:])
+	first := found
+	for index := found - 1; index >= 0; index-- {
+		prefix, err := readKey(index)
+		if err != nil {
+			return -1, err
+		}
+		startControl, ts, err := parseRowKeyPrefix(prefix, index)
+		if err != nil {
+			return -1, err
+		}
+		if startControl == CHECKSUM_ROW {
+			continue
+		}
+		if bytes.Equal(prefix[2:], encodedKey[:]) {
+			first = index
+			continue
+		}
+		if ts <= floor {
+			break
+		}
+	}
+	return first, nil
+}
+
+// laterOccurrences appends to indexes the index of every DataRow holding key
+// after first and before totalRows. Reading stops at the first data or null row
+// whose timestamp is at least key's plus skewMs: the writer only accepts key
+// while every timestamp written is below that limit.
+//
+// Only the key prefix of each row is read and compared in base64 form; a null
+// row never matches, since its key cannot be a valid search key.
+func laterOccurrences(indexes []int64, key uuid.UUID, skewMs, first, totalRows int64, readKey rowKeyReader) ([]int64, error) {
+	limit := ExtractUUIDv7Timestamp(key) + skewMs
+	var encodedKey [24]byte
+	base64.StdEncoding.Encode(encodedKey[:], key[:])
+	for index := first + 1; index < totalRows; index++ {
+		prefix, err := readKey(index)
+		if err != nil {
+			return nil, err
+		}
+		startControl, ts, err := parseRowKeyPrefix(prefix, index)
+		if err != nil {
+			return nil, err
+		}
+		if startControl == CHECKSUM_ROW {
+			continue
+		}
+		if ts >= limit {
+			break
+		}
+		if bytes.Equal(prefix[2:], encodedKey[:]) {
+			indexes = append(indexes, index)
+		}
+	}
+	return indexes, nil
+}
+
 // Finder defines methods for locating rows and transaction boundaries in frozenDB files.
 // This interface enables different finder implementations with varying performance characteristics
 // while maintaining identical functional behavior.
@@ -59,6 +329,7 @@ type Finder interface {
 	//   - error: InvalidInputError for invalid indices or checksum rows,
 	//            TransactionActiveError if transaction has no ending row,
 	//            CorruptDatabaseError for invalid control bytes or malformed transactions,
+	//            including another transaction starting before this one ends,
 	//            ReadError for I/O failures
 	//
 	// If the input index itself ends the transaction, returns the same index.
@@ -220,6 +220,13 @@ const confRowSize = 1024
 const confSkewMs = 5000
 
 func setupCreate(t *testing.T, dir string, skewMs int) string {
+	t.Helper()
+	return setupCreateWithRowSize(t, dir, confRowSize, skewMs)
+}
+
+// setupCreateWithRowSize is setupCreate with an explicit row size, for tests that
+// exercise the MIN_ROW_SIZE..MAX_ROW_SIZE range.
+func setupCreateWithRowSize(t *testing.T, dir string, rowSize int, skewMs int) string {
 	t.Helper()
 	path := filepath.Join(dir, "c.fdb")
 	if skewMs == 0 {
@@ -230,7 +237,7 @@ func setupCreate(t *testing.T, dir string, skewMs int) string {
 	t.Setenv("SUDO_USER", MOCK_USER)
 	t.Setenv("SUDO_UID", MOCK_UID)
 	t.Setenv("SUDO_GID", MOCK_GID)
-	if err := Create(CreateConfig{path: path, rowSize: confRowSize, skewMs: skewMs}); err != nil {
+	if err := Create(CreateConfig{path: path, rowSize: rowSize, skewMs: skewMs}); err != nil {
 		t.Fatalf("Create: %v", err)
 	}
 	return path
@@ -0,0 +1,140 @@
+package frozendb
+
+import (
+	"fmt"
+	"math"
+)
+
+// finderDiagnosticsSamples is the number of lookups FinderDiagnostics measures.
+const finderDiagnosticsSamples = 100
+
+// FinderStats describes the layout of a database and how much work the active
+// finder does to look keys up in it, as reported by FrozenDB.FinderDiagnostics.
+type FinderStats struct {
+	Strategy         FinderStrategy // Finder in use, as reported by ActiveFinder
+	Rows             int64          // Complete rows after the header, including checksum rows
+	KeyRows          int64          // Data and null rows, the rows a lookup searches
+	ChecksumWindows  int64          // Checksum rows, each starting a window of at most the checksum interval rows
+	AvgKeysPerWindow float64        // KeyRows divided by ChecksumWindows
+	SampledLookups   int            // Lookups measured, of keys sampled evenly across the file
+	AvgRowsExamined  float64        // Mean rows the finder read per sampled lookup
+	MaxRowsExamined  int64          // Most rows the finder read for one sampled lookup
+	Log2KeyRows      float64        // log2(KeyRows), the rows an ideal binary search reads
+}
+
+// FinderDiagnostics reports how the active finder performs on this database's
+// key distribution. It looks up up to 100 keys taken from data rows spread evenly
+// across the file with a copy of the active finder that counts the rows it reads,
+// and reports the mean and maximum against log2(KeyRows). A mean far above
+// Log2KeyRows means keys cluster within skew_ms of each other, which the binary
+// search finder resolves by scanning; consider FinderStrategyInMemory or
+// WithFinderReadAhead. The in-memory finder answers from its index and reads no
+// rows.
+//
+// The analysis is read-only and measures the file as it is when called. Rows
+// examined are counted without read-ahead, so WithFinderReadAhead does not
+// change them.
+//
+// Returns:
+//   - FinderStats: Layout and lookup cost of the database
+//   - error: ReadError or CorruptDatabaseError
+//
+// Thread Safety: Safe for concurrent calls on the same FrozenDB instance
+func (db *FrozenDB) FinderDiagnostics() (FinderStats, error) {
+	db.refresh()
+	rowSize := int64(db.header.GetRowSize())
+	stats := FinderStats{
+		Strategy: db.finderStrategy,
+		Rows:     fileRowCount(db.file.Size(), int(rowSize)),
+	}
+	interval := int64(db.header.GetChecksumInterval())
+	stats.ChecksumWindows = (stats.Rows + interval) / (interval + 1)
+	stats.KeyRows = stats.Rows - stats.ChecksumWindows
+	if stats.ChecksumWindows > 0 {
+		stats.AvgKeysPerWindow = float64(stats.KeyRows) / float64(stats.ChecksumWindows)
+	}
+	if stats.KeyRows > 0 {
+		stats.Log2KeyRows = math.Log2(float64(stats.KeyRows))
+	}
+
+	counter := &rowCountingFile{DBFile: db.file, rowSize: rowSize}
+	var finder Finder
+	switch f := db.finder.(type) {
+	case *SimpleFinder:
+		finder = f.withFile(counter)
+	case *BinarySearchFinder:
+		finder = f.withFile(counter)
+	default:
+		finder = db.finder // Answers from memory; counter stays at zero
+	}
+
+	var total int64
+	stride := max(stats.Rows/finderDiagnosticsSamples, 1)
+	for index := int64(1); index < stats.Rows && stats.SampledLookups < finderDiagnosticsSamples; index += stride {
+		rowBytes, err := db.readRowAtIndex(index)
+		if err != nil {
+			return FinderStats{}, err
+		}
+		var ru RowUnion
+		if err := ru.UnmarshalText(rowBytes); err != nil {
+			return FinderStats{}, NewCorruptDatabaseError(fmt.Sprintf("failed to parse row %d", index), err)
+		}
+		if ru.DataRow == nil {
+			continue
+		}
+		counter.rows = 0
+		if _, err := finder.GetIndex(ru.DataRow.GetKey()); err != nil {
+			return FinderStats{}, err
+		}
+		stats.SampledLookups++
+		total += counter.rows
+		stats.MaxRowsExamined = max(stats.MaxRowsExamined, counter.rows)
+	}
+	if stats.SampledLookups > 0 {
+		stats.AvgRowsExamined = float64(total) / float64(stats.SampledLookups)
+	}
+	return stats, nil
+}
+
+// rowCountingFile is a DBFile that counts the rows read through it, for one
+// goroutine at a time.
+type rowCountingFile struct {
+	DBFile
+	rowSize int64
+	rows    int64
+}
+
+func (f *rowCountingFile) Read(start int64, size int32) ([]byte, error) {
+	f.rows += (int64(size) + f.rowSize - 1) / f.rowSize
+	return f.DBFile.Read(start, size)
+}
+
+// withFile returns a copy of the finder's current state that reads rows from
+// file, without read-ahead, and is never notified of new rows.
+func (sf *SimpleFinder) withFile(file DBFile) *SimpleFinder {
+	sf.mu.Lock()
+	defer sf.mu.Unlock()
+	return &SimpleFinder{
+		dbFile:        file,
+		rowSize:       sf.rowSize,
+		size:          sf.size,
+		maxTimestamp:  sf.maxTimestamp,
+		tombstonedErr: sf.tombstonedErr,
+	}
+}
+
+// withFile returns a copy of the finder's current state that reads rows from
+// file, without read-ahead, and is never notified of new rows.
+func (bsf *BinarySearchFinder) withFile(file DBFile) *BinarySearchFinder {
+	bsf.mu.Lock()
+	defer bsf.mu.Unlock()
+	return &BinarySearchFinder{
+		dbFile:        file,
+		rowSize:       bsf.rowSize,
+		size:          bsf.size,
+		maxTimestamp:  bsf.maxTimestamp,
+		skewMs:        bsf.skewMs,
+		interval:      bsf.interval,
+		tombstonedErr: bsf.tombstonedErr,
+	}
+}
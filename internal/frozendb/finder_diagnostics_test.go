@@ -0,0 +1,50 @@
+package frozendb
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestFinderDiagnostics(t *testing.T) {
+	// Keys further apart than skew_ms, so binary search never scans
+	path := setupCreate(t, t.TempDir(), 1)
+	const rows = 1000
+	for start := 0; start < rows; start += 100 {
+		writeTx(t, path, false, func(tx *Transaction) error {
+			for i := start; i < start+100; i++ {
+				if err := tx.AddRow(uuidFromTS(1000+10*i), json.RawMessage(`{}`)); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	}
+
+	stats := make(map[FinderStrategy]FinderStats)
+	for _, strategy := range []FinderStrategy{FinderStrategySimple, FinderStrategyInMemory, FinderStrategyBinarySearch} {
+		db, err := NewFrozenDB(path, MODE_READ, strategy)
+		if err != nil {
+			t.Fatalf("NewFrozenDB: %v", err)
+		}
+		s, err := db.FinderDiagnostics()
+		db.Close()
+		if err != nil {
+			t.Fatalf("%s: FinderDiagnostics: %v", strategy, err)
+		}
+		if s.Strategy != strategy || s.KeyRows != rows || s.Rows != rows+s.ChecksumWindows || s.SampledLookups == 0 {
+			t.Errorf("%s: unexpected stats %+v", strategy, s)
+		}
+		stats[strategy] = s
+	}
+
+	if s := stats[FinderStrategyInMemory]; s.AvgRowsExamined != 0 || s.MaxRowsExamined != 0 {
+		t.Errorf("inmemory examined rows: %+v", s)
+	}
+	binary, simple := stats[FinderStrategyBinarySearch], stats[FinderStrategySimple]
+	if binary.AvgRowsExamined > 3*binary.Log2KeyRows {
+		t.Errorf("binary search examined %.1f rows per lookup, want about log2(%d) = %.1f", binary.AvgRowsExamined, rows, binary.Log2KeyRows)
+	}
+	if simple.AvgRowsExamined <= binary.AvgRowsExamined {
+		t.Errorf("simple finder examined %.1f rows per lookup, want more than binary search's %.1f", simple.AvgRowsExamined, binary.AvgRowsExamined)
+	}
+}
@@ -0,0 +1,174 @@
+package frozendb
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestResolveAutoFinderStrategy(t *testing.T) {
+	const rowSize = 1024
+	sizeOf := func(rows int64) int64 { return int64(HEADER_SIZE) + rows*rowSize }
+
+	if err := SetAutoFinderMemoryBudget(1 << 20); err != nil {
+		t.Fatalf("SetAutoFinderMemoryBudget: %v", err)
+	}
+	t.Cleanup(func() { _ = SetAutoFinderMemoryBudget(DEFAULT_AUTO_FINDER_MEMORY_BUDGET) })
+	budgetRows := int64(1<<20) / INMEMORY_BYTES_PER_ROW
+
+	tests := []struct {
+		name     string
+		fileSize int64
+		want     FinderStrategy
+	}{
+		{name: "header only", fileSize: int64(HEADER_SIZE), want: FinderStrategySimple},
+		{name: "partial row", fileSize: sizeOf(1) + 10, want: FinderStrategySimple},
+		{name: "simple threshold", fileSize: sizeOf(AUTO_SIMPLE_MAX_ROWS), want: FinderStrategySimple},
+		{name: "above simple threshold", fileSize: sizeOf(AUTO_SIMPLE_MAX_ROWS + 1), want: FinderStrategyInMemory},
+		{name: "memory budget", fileSize: sizeOf(budgetRows), want: FinderStrategyInMemory},
+		{name: "above memory budget", fileSize: sizeOf(budgetRows + 1), want: FinderStrategyBinarySearch},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := resolveAutoFinderStrategy(tt.fileSize, rowSize, autoFinderMemoryBudget.Load()); got != tt.want {
+				t.Errorf("resolveAutoFinderStrategy(%d) = %s, want %s", tt.fileSize, got, tt.want)
+			}
+		})
+	}
+
+	// A zero budget never selects the in-memory finder
+	if err := SetAutoFinderMemoryBudget(0); err != nil {
+		t.Fatalf("SetAutoFinderMemoryBudget(0): %v", err)
+	}
+	if got := resolveAutoFinderStrategy(sizeOf(AUTO_SIMPLE_MAX_ROWS+1), rowSize, autoFinderMemoryBudget.Load()); got != FinderStrategyBinarySearch {
+		t.Errorf("zero budget: got %s, want %s", got, FinderStrategyBinarySearch)
+	}
+
+	var invalidInput *InvalidInputError
+	if err := SetAutoFinderMemoryBudget(-1); !errors.As(err, &invalidInput) {
+		t.Errorf("negative budget: expected InvalidInputError, got %v", err)
+	}
+}
+
+func TestNewFrozenDB_AutoFinder(t *testing.T) {
+	db, keys := openTestDatabaseFile(t, 512, committedTransactionRows(3), FinderStrategyAuto)
+
+	if got := db.ActiveFinder(); got != FinderStrategySimple {
+		t.Fatalf("ActiveFinder() = %s, want %s", got, FinderStrategySimple)
+	}
+	var value map[string]interface{}
+	if err := db.Get(keys[2], &value); err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+
+	explicit, _ := openTestDatabaseFile(t, 512, committedTransactionRows(1), FinderStrategyInMemory)
+	if got := explicit.ActiveFinder(); got != FinderStrategyInMemory {
+		t.Errorf("ActiveFinder() = %s, want %s", got, FinderStrategyInMemory)
+	}
+}
+
+// TestWithFinderMemoryBudget_InMemoryFallback opens a file with FinderStrategyInMemory
+// at budgets on either side of its estimated index size and requires the fallback
+// to binary search exactly past the threshold.
+func TestWithFinderMemoryBudget_InMemoryFallback(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "budget.fdb")
+	config := NewCreateConfig(path, 128, confSkewMs)
+	config.SetChecksumInterval(MIN_CHECKSUM_INTERVAL)
+	entries := make([]Entry, 5000)
+	for i := range entries {
+		entries[i] = Entry{Key: uuidFromTS(1000 + i), Value: json.RawMessage(`{}`)}
+	}
+	var buf bytes.Buffer
+	if err := BuildDatabase(&buf, config, entries); err != nil {
+		t.Fatalf("BuildDatabase: %v", err)
+	}
+	if err := os.WriteFile(path, buf.Bytes(), FILE_PERMISSIONS); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	threshold := fileRowCount(int64(buf.Len()), 128) * INMEMORY_BYTES_PER_ROW
+
+	tests := []struct {
+		name   string
+		budget int64
+		want   FinderStrategy
+	}{
+		{name: "at budget", budget: threshold, want: FinderStrategyInMemory},
+		{name: "one row over budget", budget: threshold - 1, want: FinderStrategyBinarySearch},
+		{name: "zero budget", budget: 0, want: FinderStrategyBinarySearch},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger := &recordingLogger{}
+			db, err := NewFrozenDB(path, MODE_READ, FinderStrategyInMemory, WithFinderMemoryBudget(tt.budget), WithLogger(logger))
+			if err != nil {
+				t.Fatalf("NewFrozenDB: %v", err)
+			}
+			defer db.Close()
+			if got := db.ActiveFinder(); got != tt.want {
+				t.Fatalf("ActiveFinder() = %s, want %s", got, tt.want)
+			}
+			if tt.want == FinderStrategyBinarySearch {
+				logger.requireEvent(t, "warn", "exceeds the")
+			}
+			var value map[string]any
+			if err := db.Get(entries[len(entries)-1].Key, &value); err != nil {
+				t.Errorf("Get: %v", err)
+			}
+		})
+	}
+
+	// The option also bounds FinderStrategyAuto
+	db, err := NewFrozenDB(path, MODE_READ, FinderStrategyAuto, WithFinderMemoryBudget(threshold-1))
+	if err != nil {
+		t.Fatalf("NewFrozenDB: %v", err)
+	}
+	defer db.Close()
+	if got := db.ActiveFinder(); got != FinderStrategyBinarySearch {
+		t.Errorf("auto ActiveFinder() = %s, want %s", got, FinderStrategyBinarySearch)
+	}
+}
+
+// TestWithFinderMemoryBudget_GrowthWarning writes past the budget of an open
+// in-memory finder and requires a single warning once its index outgrows it.
+func TestWithFinderMemoryBudget_GrowthWarning(t *testing.T) {
+	path := setupCreate(t, t.TempDir(), 0)
+	logger := &recordingLogger{}
+	// The initial checksum row plus two data rows fit; the third does not
+	db, err := NewFrozenDB(path, MODE_WRITE, FinderStrategyInMemory, WithFinderMemoryBudget(3*INMEMORY_BYTES_PER_ROW), WithLogger(logger))
+	if err != nil {
+		t.Fatalf("NewFrozenDB: %v", err)
+	}
+	defer db.Close()
+
+	warnings := func() int {
+		logger.mu.Lock()
+		defer logger.mu.Unlock()
+		n := 0
+		for _, event := range logger.events {
+			if strings.HasPrefix(event, "warn: ") && strings.Contains(event, "grown past") {
+				n++
+			}
+		}
+		return n
+	}
+	for i, wantWarnings := range []int{0, 0, 1, 1} {
+		tx, err := db.BeginTx()
+		if err != nil {
+			t.Fatalf("BeginTx: %v", err)
+		}
+		mustAdd(t, tx, uuidFromTS(1000*(i+1)), `{}`)
+		if err := tx.Commit(); err != nil {
+			t.Fatalf("Commit: %v", err)
+		}
+		if got := warnings(); got != wantWarnings {
+			t.Fatalf("after %d rows: %d budget warnings, want %d", i+1, got, wantWarnings)
+		}
+	}
+	if got := db.ActiveFinder(); got != FinderStrategyInMemory {
+		t.Errorf("ActiveFinder() = %s, want %s", got, FinderStrategyInMemory)
+	}
+}
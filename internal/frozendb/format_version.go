@@ -0,0 +1,98 @@
+package frozendb
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// formatReader opens database files whose header declares one format version.
+type formatReader struct {
+	// open builds a FrozenDB over dbFile, whose header declares this version. name
+	// identifies the file in log events. The caller closes dbFile on error.
+	open func(dbFile DBFile, name string, strategy FinderStrategy, options openOptions) (*FrozenDB, error)
+}
+
+// formatReaders maps the header "ver" value to the reader for that format version.
+// A new file format registers its reader in init; the version 1 reader stays so
+// existing files keep opening. Files declaring any other version are rejected with
+// UnsupportedVersionError. The map is only written during package initialization.
+var formatReaders = map[int]formatReader{}
+
+func init() {
+	// Registered here rather than in the literal above: openV1 parses headers,
+	// which consults formatReaders
+	formatReaders[FORMAT_VERSION] = formatReader{open: openV1}
+	// Version 2 only adds the "ci" and "vc" header fields, which openV1 reads
+	formatReaders[FORMAT_VERSION_EXTENDED] = formatReader{open: openV1}
+}
+
+// supportedFormatVersions returns the registered format versions in ascending order.
+func supportedFormatVersions() []int {
+	versions := make([]int, 0, len(formatReaders))
+	for version := range formatReaders {
+		versions = append(versions, version)
+	}
+	slices.Sort(versions)
+	return versions
+}
+
+// isSupportedFormatVersion reports whether a reader is registered for version.
+func isSupportedFormatVersion(version int) bool {
+	_, ok := formatReaders[version]
+	return ok
+}
+
+// newUnsupportedFormatVersionError returns the UnsupportedVersionError for a file
+// declaring version, telling the user to upgrade.
+func newUnsupportedFormatVersionError(version int) *UnsupportedVersionError {
+	supported := make([]string, 0, len(formatReaders))
+	for _, v := range supportedFormatVersions() {
+		supported = append(supported, fmt.Sprint(v))
+	}
+	return NewUnsupportedVersionError(
+		fmt.Sprintf("database file format version %d is not supported by this frozendb binary (supported: %s); upgrade frozendb to open it",
+			version, strings.Join(supported, ", ")),
+		NewCorruptDatabaseError(fmt.Sprintf("header declares unknown format version %d", version), nil),
+	)
+}
+
+// peekFormatVersion returns the format version declared by a header without
+// validating the rest of it. ok is false when headerBytes do not hold frozenDB
+// header JSON, in which case the version's own parser reports the problem.
+func peekFormatVersion(headerBytes []byte) (version int, ok bool) {
+	content := headerBytes
+	if nullPos := bytes.IndexByte(headerBytes, PADDING_CHAR); nullPos != -1 {
+		content = headerBytes[:nullPos]
+	}
+	var hdr struct {
+		Sig string `json:"sig"`
+		Ver int    `json:"ver"`
+	}
+	if err := json.Unmarshal(content, &hdr); err != nil || hdr.Sig != HEADER_SIGNATURE {
+		return 0, false
+	}
+	return hdr.Ver, true
+}
+
+// openWithFile builds a FrozenDB over an open DBFile with the reader registered
+// for the format version its header declares. Headers that cannot be read or
+// parsed go to the version 1 reader, which reports why. The caller closes dbFile
+// on error.
+func openWithFile(dbFile DBFile, name string, strategy FinderStrategy, options openOptions) (*FrozenDB, error) {
+	version := 1
+	if dbFile.Size() >= HEADER_SIZE {
+		if headerBytes, err := dbFile.Read(0, HEADER_SIZE); err == nil {
+			if declared, ok := peekFormatVersion(headerBytes); ok {
+				version = declared
+			}
+		}
+	}
+	reader, ok := formatReaders[version]
+	if !ok {
+		return nil, newUnsupportedFormatVersionError(version)
+	}
+	return reader.open(dbFile, name, strategy, options)
+}
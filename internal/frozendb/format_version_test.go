@@ -0,0 +1,166 @@
+package frozendb
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeHeaderOnlyFile writes a 64-byte header with the given JSON content followed
+// by one row of padding, and returns the path.
+func writeHeaderOnlyFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "version.fdb")
+	data := make([]byte, HEADER_SIZE+1024)
+	copy(data, content)
+	data[HEADER_SIZE-1] = HEADER_NEWLINE
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	return path
+}
+
+func TestUnsupportedFormatVersion(t *testing.T) {
+	path := writeHeaderOnlyFile(t, `{"sig":"fDB","ver":3,"row_size":1024,"skew_ms":5000}`)
+
+	_, err := NewFrozenDB(path, MODE_READ, FinderStrategySimple)
+	var unsupported *UnsupportedVersionError
+	if !errors.As(err, &unsupported) {
+		t.Fatalf("expected UnsupportedVersionError, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "version 3") || !strings.Contains(err.Error(), "upgrade frozendb") {
+		t.Errorf("error should name the version and ask for an upgrade: %v", err)
+	}
+	var corrupt *CorruptDatabaseError
+	if !errors.As(err, &corrupt) {
+		t.Errorf("UnsupportedVersionError should wrap CorruptDatabaseError")
+	}
+
+	// Every path that parses the header reports the same error
+	if _, err := Verify(path); !errors.As(err, &unsupported) {
+		t.Errorf("Verify: expected UnsupportedVersionError, got %v", err)
+	}
+
+	// A wrong signature is corruption, whatever the version says
+	path = writeHeaderOnlyFile(t, `{"sig":"xDB","ver":3,"row_size":1024,"skew_ms":5000}`)
+	_, err = NewFrozenDB(path, MODE_READ, FinderStrategySimple)
+	if !errors.As(err, &corrupt) || errors.As(err, &unsupported) {
+		t.Errorf("bad signature: expected CorruptDatabaseError only, got %v", err)
+	}
+}
+
+func TestFormatReaderDispatch(t *testing.T) {
+	const testVersion = 7
+	var opened string
+	formatReaders[testVersion] = formatReader{
+		open: func(dbFile DBFile, name string, strategy FinderStrategy, options openOptions) (*FrozenDB, error) {
+			opened = name
+			return nil, NewInvalidActionError("test reader", nil)
+		},
+	}
+	t.Cleanup(func() { delete(formatReaders, testVersion) })
+
+	path := writeHeaderOnlyFile(t, `{"sig":"fDB","ver":7,"row_size":1024,"skew_ms":5000}`)
+	var invalidAction *InvalidActionError
+	if _, err := NewFrozenDB(path, MODE_READ, FinderStrategySimple); !errors.As(err, &invalidAction) {
+		t.Fatalf("expected the registered reader's error, got %v", err)
+	}
+	if opened != path {
+		t.Errorf("registered reader opened %q, want %q", opened, path)
+	}
+	if got := supportedFormatVersions(); len(got) != 3 || got[0] != 1 || got[1] != 2 || got[2] != testVersion {
+		t.Errorf("supportedFormatVersions() = %v, want [1 2 %d]", got, testVersion)
+	}
+}
+
+func TestPeekFormatVersion(t *testing.T) {
+	tests := []struct {
+		content string
+		version int
+		ok      bool
+	}{
+		{`{"sig":"fDB","ver":1,"row_size":1024,"skew_ms":5000}`, 1, true},
+		{`{"sig":"fDB","ver":3}`, 3, true},
+		{`{"sig":"xDB","ver":3}`, 0, false},
+		{`not json`, 0, false},
+	}
+	for _, tt := range tests {
+		header := make([]byte, HEADER_SIZE)
+		copy(header, tt.content)
+		header[HEADER_SIZE-1] = HEADER_NEWLINE
+		version, ok := peekFormatVersion(header)
+		if version != tt.version || ok != tt.ok {
+			t.Errorf("peekFormatVersion(%s) = (%d, %v), want (%d, %v)", tt.content, version, ok, tt.version, tt.ok)
+		}
+	}
+}
+
+func TestExtendedHeaderVersion(t *testing.T) {
+	// A file declares version 2 exactly when its header carries "ci" or "vc", or
+	// its initial checksum row carries an extension
+	var corrupt *CorruptDatabaseError
+	for _, content := range []string{
+		`{"sig":"fDB","ver":1,"row_size":1024,"skew_ms":5000,"ci":500}`,
+		`{"sig":"fDB","ver":1,"row_size":1024,"skew_ms":5000,"vc":"gz"}`,
+	} {
+		if err := (&Header{}).UnmarshalText(paddedHeader(content)); !errors.As(err, &corrupt) {
+			t.Errorf("%s: expected CorruptDatabaseError, got %v", content, err)
+		}
+	}
+
+	// A version 2 header alone may be valid, so the file is only rejected once its
+	// initial checksum row turns out to have no extension either
+	headerBytes := paddedHeader(`{"sig":"fDB","ver":2,"row_size":1024,"skew_ms":5000}`)
+	if _, err := ParseHeader(headerBytes); err != nil {
+		t.Errorf("ParseHeader: %v", err)
+	}
+	checksumRow, err := NewChecksumRow(1024, headerBytes)
+	if err != nil {
+		t.Fatalf("NewChecksumRow: %v", err)
+	}
+	checksumBytes, err := checksumRow.MarshalText()
+	if err != nil {
+		t.Fatalf("MarshalText: %v", err)
+	}
+	bare := filepath.Join(t.TempDir(), "bare.fdb")
+	if err := os.WriteFile(bare, append(headerBytes, checksumBytes...), 0644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	if _, err := ReadHeader(bare); !errors.As(err, &corrupt) {
+		t.Errorf("ReadHeader of a bare version 2 file: expected CorruptDatabaseError, got %v", err)
+	}
+	if _, err := NewFrozenDB(bare, MODE_READ, FinderStrategySimple); !errors.As(err, &corrupt) {
+		t.Errorf("NewFrozenDB of a bare version 2 file: expected CorruptDatabaseError, got %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "interval.fdb")
+	setupMockSyscalls(false, false)
+	t.Cleanup(restoreRealSyscalls)
+	t.Setenv("SUDO_USER", MOCK_USER)
+	t.Setenv("SUDO_UID", MOCK_UID)
+	t.Setenv("SUDO_GID", MOCK_GID)
+	config := NewCreateConfig(path, confRowSize, confSkewMs)
+	config.SetChecksumInterval(MIN_CHECKSUM_INTERVAL)
+	if err := Create(config); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	header, err := ReadHeader(path)
+	if err != nil {
+		t.Fatalf("ReadHeader: %v", err)
+	}
+	if header.GetVersion() != FORMAT_VERSION_EXTENDED {
+		t.Errorf("GetVersion() = %d, want %d", header.GetVersion(), FORMAT_VERSION_EXTENDED)
+	}
+
+	// A reader that only implements version 1 rejects the file rather than
+	// placing checksum rows at the default interval
+	reader := formatReaders[FORMAT_VERSION_EXTENDED]
+	delete(formatReaders, FORMAT_VERSION_EXTENDED)
+	t.Cleanup(func() { formatReaders[FORMAT_VERSION_EXTENDED] = reader })
+	var unsupported *UnsupportedVersionError
+	if _, err := NewFrozenDB(path, MODE_READ, FinderStrategySimple); !errors.As(err, &unsupported) {
+		t.Errorf("version 1 reader: expected UnsupportedVersionError, got %v", err)
+	}
+}
@@ -4,7 +4,9 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
 	"sync"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -33,9 +35,35 @@ type FrozenDB struct {
 	// Transaction state management
 	activeTx *Transaction // Current active transaction (nil if none)
 	txMu     sync.RWMutex // Mutex for transaction state management
+	submitMu sync.Mutex   // Serializes transactions run by Submit
 
 	// Row finder for query operations
-	finder Finder // Finder interface for locating rows by UUID key
+	finder         Finder         // Finder interface for locating rows by UUID key
+	finderStrategy FinderStrategy // Strategy of finder (FinderStrategyAuto resolved at open)
+
+	// Row-sized read buffers reused by GetInto
+	rowBufs sync.Pool
+
+	// Optional schema applied to values of transactions begun after it is set
+	valueSchema *valueSchema // nil when no schema is set (guarded by txMu)
+
+	// Optional application check applied to rows of transactions begun after it is set
+	rowValidator RowValidator // nil when no validator is set (guarded by txMu)
+
+	// Diagnostic events, passed on to transactions
+	logger Logger // Set by NewFrozenDB (no-op unless WithLogger is used)
+
+	// Reject keys ahead of the system clock, passed on to transactions
+	futureGuard bool // Set by WithFutureGuard
+
+	// Error of the last iteration over Seq or SeqRange, reported by SeqErr
+	seqMu  sync.Mutex
+	seqErr error
+
+	// Close state and the callbacks registered with OnClose
+	closeMu    sync.Mutex
+	closed     bool     // Set by the first successful Close
+	closeHooks []func() // Run in order by the first successful Close
 }
 
 // NewFrozenDB opens an existing frozenDB database file with specified access mode
@@ -44,45 +72,104 @@ type FrozenDB struct {
 // Parameters:
 //   - path: Filesystem path to existing frozenDB database file (.fdb extension required)
 //   - mode: Access mode - MODE_READ for read-only, MODE_WRITE for read-write
-//   - strategy: FinderStrategySimple (fixed memory, O(n) GetIndex) or
-//     FinderStrategyInMemory (~40 bytes/row, O(1) Get*)
+//   - strategy: FinderStrategySimple (fixed memory, O(n) GetIndex),
+//     FinderStrategyInMemory (~40 bytes/row, O(1) Get*, see WithFinderMemoryBudget),
+//     FinderStrategyBinarySearch,
+//     or FinderStrategyAuto (chosen from the file size, see ActiveFinder)
+//   - opts: Optional OpenOption values, such as WithoutLock
 //
 // Returns:
 //   - *FrozenDB: Database instance ready for operations
 //   - error: InvalidInputError (invalid strategy), PathError, CorruptDatabaseError, or WriteError
 //
 // Thread Safety: Safe for concurrent calls on different files
-func NewFrozenDB(path string, mode string, strategy FinderStrategy) (*FrozenDB, error) {
-	if strategy != FinderStrategySimple && strategy != FinderStrategyInMemory && strategy != FinderStrategyBinarySearch {
-		return nil, NewInvalidInputError(
-			fmt.Sprintf("Invalid finder strategy: %q. Supported strategies: simple, inmemory, binary_search", strategy),
-			nil,
-		)
+func NewFrozenDB(path string, mode string, strategy FinderStrategy, opts ...OpenOption) (*FrozenDB, error) {
+	if err := validateFinderStrategy(strategy); err != nil {
+		return nil, err
 	}
-	dbFile, err := NewDBFile(path, mode)
+	options := newOpenOptions(opts)
+	dbFile, err := newDBFile(path, mode, options)
 	if err != nil {
 		return nil, err
 	}
 
-	var cleanupErr error
-	defer func() {
-		if cleanupErr != nil {
-			_ = dbFile.Close()
-		}
-	}()
+	db, err := openWithFile(dbFile, path, strategy, options)
+	if err != nil {
+		_ = dbFile.Close()
+		return nil, err
+	}
+	return db, nil
+}
 
+// NewFrozenDBWithFile opens a database through a caller-supplied DBFile instead of
+// a path, so that the read path can run over any storage: an object store, a
+// memory-mapped region, or a byte slice in a unit test. The header is read and
+// validated through file, and every later read goes through it.
+//
+// Only MODE_READ files are accepted. Writing requires the exclusive lock and
+// append semantics of a real file, so write-mode handles must be opened with
+// NewFrozenDB. The file must report a stable Size that only grows, return exactly
+// the requested bytes from Read, and call Subscribe callbacks after it grows if
+// the handle should see appended rows.
+//
+// Parameters:
+//   - file: DBFile whose GetMode is MODE_READ
+//   - strategy: Finder strategy, as for NewFrozenDB
+//   - opts: Optional OpenOption values, such as WithLogger (WithoutLock has no effect)
+//
+// Returns:
+//   - *FrozenDB: Database instance reading through file; Close closes file
+//   - error: InvalidInputError (nil file or invalid strategy), InvalidActionError
+//     (file not in MODE_READ), CorruptDatabaseError, or ReadError. The file is
+//     not closed on error.
+func NewFrozenDBWithFile(file DBFile, strategy FinderStrategy, opts ...OpenOption) (*FrozenDB, error) {
+	if file == nil {
+		return nil, NewInvalidInputError("file cannot be nil", nil)
+	}
+	if err := validateFinderStrategy(strategy); err != nil {
+		return nil, err
+	}
+	if file.GetMode() != MODE_READ {
+		return nil, NewInvalidActionError("a custom DBFile must be in read mode; open write-mode databases with NewFrozenDB", nil)
+	}
+	return openWithFile(file, "custom DBFile", strategy, newOpenOptions(opts))
+}
+
+// validateFinderStrategy rejects strategies other than the supported ones.
+func validateFinderStrategy(strategy FinderStrategy) error {
+	if strategy != FinderStrategySimple && strategy != FinderStrategyInMemory && strategy != FinderStrategyBinarySearch && strategy != FinderStrategyAuto {
+		return NewInvalidInputError(
+			fmt.Sprintf("Invalid finder strategy: %q. Supported strategies: simple, inmemory, binary_search, auto", strategy),
+			nil,
+		)
+	}
+	return nil
+}
+
+// openV1 is the format reader for version 1 files: it validates the header,
+// creates the finder, and recovers any in-progress transaction.
+func openV1(dbFile DBFile, name string, strategy FinderStrategy, options openOptions) (*FrozenDB, error) {
 	header, err := validateDatabaseFile(dbFile)
 	if err != nil {
-		cleanupErr = err
 		return nil, err
 	}
 
 	rowSize := int32(header.GetRowSize())
 
+	if strategy == FinderStrategyAuto {
+		strategy = resolveAutoFinderStrategy(dbFile.Size(), int(rowSize), options.finderMemoryBudget)
+		options.logger.Debugf("frozendb: auto finder selected %s for %s (%d bytes)", strategy, name, dbFile.Size())
+	} else if strategy == FinderStrategyInMemory && !inMemoryFinderFits(dbFile.Size(), int(rowSize), options.finderMemoryBudget) {
+		strategy = FinderStrategyBinarySearch
+		options.logger.Warnf("frozendb: inmemory finder for %s (%d rows) exceeds the %d byte memory budget, using %s",
+			name, fileRowCount(dbFile.Size(), int(rowSize)), options.finderMemoryBudget, strategy)
+	} else {
+		options.logger.Debugf("frozendb: using %s finder for %s", strategy, name)
+	}
+
 	// Create RowEmitter for all finder strategies
 	rowEmitter, err := NewRowEmitter(dbFile, int(rowSize))
 	if err != nil {
-		cleanupErr = err
 		return nil, err
 	}
 
@@ -96,26 +183,32 @@ func NewFrozenDB(path string, mode string, strategy FinderStrategy) (*FrozenDB,
 		finder, err = NewBinarySearchFinder(dbFile, rowSize, rowEmitter)
 	}
 	if err != nil {
-		cleanupErr = err
 		return nil, err
 	}
+	if ra, ok := finder.(interface{ setReadAhead(window int) }); ok && options.finderReadAhead > 1 {
+		ra.setReadAhead(options.finderReadAhead)
+	}
+	if imf, ok := finder.(*InMemoryFinder); ok {
+		imf.setMemoryBudget(options.finderMemoryBudget, options.logger)
+	}
 
 	// Create FrozenDB instance
 	db := &FrozenDB{
-		file:   dbFile,
-		header: header,
-		finder: finder,
+		file:           dbFile,
+		header:         header,
+		finder:         finder,
+		finderStrategy: strategy,
+		logger:         options.logger,
+		futureGuard:    options.futureGuard,
 	}
 
 	// Validate the FrozenDB instance (ensures internal consistency)
 	if err := db.Validate(); err != nil {
-		cleanupErr = err
 		return nil, err
 	}
 
 	// Recover transaction state if present
 	if err := db.recoverTransaction(); err != nil {
-		cleanupErr = err
 		return nil, err
 	}
 
@@ -155,22 +248,94 @@ func (db *FrozenDB) Validate() error {
 	return nil
 }
 
+// ActiveFinder returns the finder strategy in use. When the database was opened
+// with FinderStrategyAuto this is the strategy that was selected, and when
+// FinderStrategyInMemory exceeded the memory budget it is FinderStrategyBinarySearch.
+func (db *FrozenDB) ActiveFinder() FinderStrategy {
+	return db.finderStrategy
+}
+
+// Metadata returns a copy of the application metadata recorded when the database
+// was created (CreateConfig.SetMetadata), or nil if none was. It is read from the
+// initial checksum row when the database is opened and never changes.
+//
+// Thread Safety: Safe for concurrent calls on the same FrozenDB instance
+func (db *FrozenDB) Metadata() map[string]string {
+	return db.header.GetMetadata()
+}
+
+// CreatedAt returns the creation time recorded when the database was created
+// (CreateConfig.SetCreatedAt), or the zero time if none was. Databases created
+// without one, including every version 1 file, report the zero time.
+//
+// Thread Safety: Safe for concurrent calls on the same FrozenDB instance
+func (db *FrozenDB) CreatedAt() time.Time {
+	return db.header.GetCreatedAt()
+}
+
+// MaxTimestamp returns the database's max_timestamp: the largest key timestamp
+// among its complete data and null rows, including rolled back rows, or 0 for an
+// empty database. A key written next must have a timestamp plus skew_ms greater
+// than it. The key of a trailing partial row is not included.
+//
+// Thread Safety: Safe for concurrent calls on the same FrozenDB instance
+func (db *FrozenDB) MaxTimestamp() int64 {
+	db.refresh()
+	return db.finder.MaxTimestamp()
+}
+
 // Close releases all resources associated with the database connection
 // This method is thread-safe and idempotent - multiple concurrent calls are safe
 // Returns nil if already closed or cleanup successful
+//
+// The first successful Close runs the OnClose callbacks after the file and its
+// lock are released; later calls do not run them again.
 func (db *FrozenDB) Close() error {
-	if db.file == nil {
+	db.closeMu.Lock()
+	if db.closed {
+		db.closeMu.Unlock()
 		return nil
 	}
-	if err := db.file.Close(); err != nil {
-		return NewWriteError("failed to close file descriptor", err)
+	if db.file != nil {
+		if err := db.file.Close(); err != nil {
+			db.closeMu.Unlock()
+			return NewWriteError("failed to close file descriptor", err)
+		}
+	}
+	db.closed = true
+	hooks := db.closeHooks
+	db.closeHooks = nil
+	db.closeMu.Unlock()
+
+	// Outside the lock, so a callback may call Close or OnClose itself
+	for _, fn := range hooks {
+		fn()
 	}
 	return nil
 }
 
+// OnClose registers fn to run when the database is closed, so resources tied to
+// the handle, such as an external index or a metric, can be released with it.
+// Callbacks run once, in registration order, on the first successful Close and
+// after the file descriptor and write lock are released. A callback registered
+// after the database is closed runs immediately. A nil fn is ignored.
+func (db *FrozenDB) OnClose(fn func()) {
+	if fn == nil {
+		return
+	}
+	db.closeMu.Lock()
+	if !db.closed {
+		db.closeHooks = append(db.closeHooks, fn)
+		db.closeMu.Unlock()
+		return
+	}
+	db.closeMu.Unlock()
+	fn()
+}
+
 // recoverTransaction detects and recovers incomplete transaction state when opening a database file.
 // It follows the algorithm: Read the last row -> If closed transaction nothing to do.
-// Else, if open, read the last 101 rows (100 data rows + 1 checksum row), then figure out where the transaction starts.
+// Else, if open, read the last MAX_TRANSACTION_ROWS+1 rows (data rows + 1 checksum row), then figure out where the transaction starts.
 // Also, if the file size doesn't land on a row boundary then you can skip the first read
 // since that's guaranteed to be a PartialDataRow.
 func (db *FrozenDB) recoverTransaction() error {
@@ -197,23 +362,22 @@ func (db *FrozenDB) recoverTransaction() error {
 			return NewCorruptDatabaseError("failed to read PartialDataRow", err)
 		}
 
-		// Parse PartialDataRow
-		partialRow := &PartialDataRow{}
-		if err := partialRow.UnmarshalText(partialBytes); err != nil {
-			return NewCorruptDatabaseError("invalid PartialDataRow format", err)
+		// Parse PartialDataRow, distinguishing a file cut off mid-row from bad bytes
+		partialRow, err := parseTrailingPartialRow(partialBytes, 1+rowsInData, rowSize)
+		if err != nil {
+			return err
 		}
-		partialRow.d.RowSize = rowSize // Set row size for validation
 
 		// Create transaction with recovered PartialDataRow
 		// Check if this is a new transaction (START_TRANSACTION) or continuation (ROW_CONTINUE)
 		var txRows []DataRow
 		if partialRow.d.StartControl == ROW_CONTINUE {
 			// Transaction has preceding rows - read them
-			// Read up to 101 rows backwards to find transaction start (100 data rows + 1 checksum row)
+			// Read up to MAX_TRANSACTION_ROWS+1 rows backwards to find transaction start (data rows + 1 checksum row)
 			if rowsInData > 0 {
 				rowsToRead := rowsInData
-				if rowsToRead > 101 {
-					rowsToRead = 101
+				if rowsToRead > MAX_TRANSACTION_ROWS+1 {
+					rowsToRead = MAX_TRANSACTION_ROWS + 1
 				}
 
 				// Read the last rows to reconstruct transaction
@@ -241,7 +405,7 @@ func (db *FrozenDB) recoverTransaction() error {
 		// Create transaction with recovered state
 		// For read-only mode, create a dummy channel that won't be used
 		// For write mode, set up the actual writer
-		writeChan := make(chan Data, 100)
+		writeChan := newWriteChan(db.file)
 		if db.file.GetMode() == MODE_WRITE {
 			if err := db.file.SetWriter(writeChan); err != nil {
 				return NewWriteError("failed to set writer for recovered transaction", err)
@@ -263,6 +427,7 @@ func (db *FrozenDB) recoverTransaction() error {
 		// Note: maxTimestamp is now maintained by the finder, not the transaction
 
 		db.txMu.Lock()
+		db.configureTx(tx)
 		db.activeTx = tx
 		db.txMu.Unlock()
 
@@ -322,10 +487,10 @@ func (db *FrozenDB) recoverTransaction() error {
 
 		// Open transaction: RE or SE
 		if endControl == ROW_END_CONTROL || endControl == SAVEPOINT_CONTINUE {
-			// Read last 101 rows to find transaction start (100 data rows + 1 checksum row)
+			// Read last MAX_TRANSACTION_ROWS+1 rows to find transaction start (data rows + 1 checksum row)
 			rowsToRead := rowsInData
-			if rowsToRead > 101 {
-				rowsToRead = 101
+			if rowsToRead > MAX_TRANSACTION_ROWS+1 {
+				rowsToRead = MAX_TRANSACTION_ROWS + 1
 			}
 
 			// Ensure we have at least one row to read
@@ -351,7 +516,7 @@ func (db *FrozenDB) recoverTransaction() error {
 			}
 
 			// Create transaction with recovered state (no partial row for complete last row)
-			writeChan := make(chan Data, 100)
+			writeChan := newWriteChan(db.file)
 			if db.file.GetMode() == MODE_WRITE {
 				if err := db.file.SetWriter(writeChan); err != nil {
 					return NewWriteError("failed to set writer for recovered transaction", err)
@@ -370,6 +535,7 @@ func (db *FrozenDB) recoverTransaction() error {
 			// Note: maxTimestamp is now maintained by the finder, not the transaction
 
 			db.txMu.Lock()
+			db.configureTx(tx)
 			db.activeTx = tx
 			db.txMu.Unlock()
 
@@ -465,6 +631,17 @@ func (db *FrozenDB) GetActiveTx() *Transaction {
 	return db.activeTx
 }
 
+// configureTx applies the settings of db to a transaction it hands out, whether
+// begun by BeginTx or recovered on open. The caller must hold db.txMu.
+func (db *FrozenDB) configureTx(tx *Transaction) {
+	tx.valueSchema = db.valueSchema
+	tx.rowValidator = db.rowValidator
+	tx.committedGet = db.Get
+	tx.committedHas = db.hasCommitted
+	tx.logger = db.logger
+	tx.futureGuard = db.futureGuard
+}
+
 // BeginTx creates a new transaction if no active transaction exists.
 // Returns error if transaction creation fails or conflicts with existing active transaction.
 // Thread-safe using write lock on FrozenDB.txMu.
@@ -488,6 +665,8 @@ func (db *FrozenDB) BeginTx() (*Transaction, error) {
 		return nil, err
 	}
 
+	db.configureTx(tx)
+
 	// Initialize transaction with Begin()
 	if err := tx.Begin(); err != nil {
 		return nil, err
@@ -499,8 +678,52 @@ func (db *FrozenDB) BeginTx() (*Transaction, error) {
 	return tx, nil
 }
 
+// SetValueSchema sets a JSON Schema (draft-07) that every value passed to AddRow
+// must satisfy. Values that do not match are rejected with InvalidDataError naming
+// the JSON Pointer of the first violation, and nothing is written for that row.
+//
+// The schema is held in memory for the lifetime of this handle only; it is not
+// persisted in the file. It applies to transactions begun after the call; an
+// already active transaction keeps the schema it started with. Passing nil or an
+// empty schema removes the schema. No schema is set by default.
+//
+// Only references within the schema document ("#/definitions/...") are supported.
+//
+// Parameters:
+//   - schema: JSON Schema document, or nil to disable validation
+//
+// Returns:
+//   - error: InvalidInputError if the schema is invalid or unsupported
+//
+// Thread Safety: Safe for concurrent calls on the same FrozenDB instance
+func (db *FrozenDB) SetValueSchema(schema []byte) error {
+	var compiled *valueSchema
+	if len(schema) > 0 {
+		var err error
+		if compiled, err = compileValueSchema(schema); err != nil {
+			return err
+		}
+	}
+
+	db.txMu.Lock()
+	defer db.txMu.Unlock()
+	db.valueSchema = compiled
+	return nil
+}
+
 // Get retrieves the value associated with the given UUID key from committed transactions.
 // The method unmarshals the stored JSON data into the provided destination parameter.
+// On a MODE_READ handle, Get first extends its view to the current end of the file,
+// so transactions committed by another process are visible without reopening.
+//
+// If key was written more than once, Get returns its first committed occurrence in
+// file order. Occurrences that were rolled back or are still in progress are
+// skipped, so a key written again after a rollback is found. Use GetLatest to
+// read the last committed occurrence instead.
+// A tombstone written by Transaction.Delete ends the occurrences before it: if the
+// last committed occurrence is a tombstone, Get returns KeyNotFoundError, and if
+// the key was written again after the delete, Get returns the first committed
+// occurrence after it.
 //
 // Parameters:
 //   - key: UUIDv7 key to search for (must not be uuid.Nil)
@@ -536,18 +759,148 @@ func (db *FrozenDB) Get(key uuid.UUID, value any) error {
 	// We need to use reflection-style checking indirectly through json.Unmarshal behavior
 	// For now, we'll let json.Unmarshal handle the pointer validation
 
-	// Use finder to locate the row by UUID key
-	index, err := db.finder.GetIndex(key)
+	db.refresh()
+	return db.getBounded(key, value, math.MaxInt64)
+}
+
+// getBounded implements Get while only considering rows with an index below
+// endIndex. A key whose row or transaction end lies at or beyond endIndex is
+// treated as not yet committed. Get passes math.MaxInt64 to consider every row.
+func (db *FrozenDB) getBounded(key uuid.UUID, value any, endIndex int64) error {
+	index, err := db.visibleIndex(key, endIndex, db.parsedRowControls)
+	if err != nil {
+		return err
+	}
+	jsonValue, err := db.readValue(index)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(jsonValue, value); err != nil {
+		return NewInvalidDataError("failed to unmarshal JSON value", err)
+	}
+	return nil
+}
+
+// hasCommitted reports whether Get would find key: it has a committed occurrence
+// that no later committed tombstone deletes.
+func (db *FrozenDB) hasCommitted(key uuid.UUID) (bool, error) {
+	db.refresh()
+	_, result, err := db.classifyKey(key, math.MaxInt64, db.parsedRowControls)
+	if result == "" {
+		return false, err
+	}
+	return result == LookupFound, nil
+}
+
+// rowControlReader returns the start and end control of the row at index,
+// returning CorruptDatabaseError if the row cannot be parsed.
+type rowControlReader func(index int64) (StartControl, EndControl, error)
+
+// visibleIndex returns the row index of key if the key is visible under the
+// transaction visibility rules, considering only rows below endIndex.
+// controls is used to read the transaction end row and, for partial rollbacks,
+// the rows of the transaction.
+func (db *FrozenDB) visibleIndex(key uuid.UUID, endIndex int64, controls rowControlReader) (int64, error) {
+	row, err := db.locateVisible(key, endIndex, controls)
+	if err != nil {
+		return 0, err
+	}
+	return row.index, nil
+}
+
+// visibleRow is the location of a visible key and of the transaction holding it.
+type visibleRow struct {
+	index        int64      // Row index of the key
+	txStart      int64      // Row index of the transaction's first row
+	txEnd        int64      // Row index of the row that ended the transaction
+	txEndControl EndControl // end_control of the transaction's last row
+}
+
+// locateVisible implements visibleIndex, also returning the boundaries of the
+// transaction holding the key and how it ended.
+func (db *FrozenDB) locateVisible(key uuid.UUID, endIndex int64, controls rowControlReader) (visibleRow, error) {
+	row, _, err := db.classifyKey(key, endIndex, controls)
+	return row, err
+}
+
+// classifyKey implements locateVisible, also reporting why a key is not visible.
+// For every LookupResult other than LookupFound the error is a KeyNotFoundError;
+// any other error comes with an empty LookupResult.
+//
+// A key written more than once resolves to its first committed occurrence:
+// occurrences rolled back or still in progress are skipped. A committed tombstone
+// written by Delete ends the key's previous occurrences, so the key resolves to
+// the first committed occurrence after the last tombstone, or is LookupDeleted
+// when there is none. When no occurrence is visible, the key is classified by
+// its last occurrence, the most recent attempt to write it.
+func (db *FrozenDB) classifyKey(key uuid.UUID, endIndex int64, controls rowControlReader) (visibleRow, LookupResult, error) {
+	// Use finder to locate every row holding the UUID key
+	indexes, err := db.keyIndexes(key)
 	if err != nil {
 		// If key not found, return KeyNotFoundError as-is
 		// Other errors (ReadError, CorruptDatabaseError) pass through
-		return err
+		var notFound *KeyNotFoundError
+		if errors.As(err, &notFound) {
+			return visibleRow{}, LookupNotFound, err
+		}
+		return visibleRow{}, "", err
 	}
 
+	var resolved visibleRow
+	found, deleted := false, false
+	result, reason := LookupNotFound, error(NewKeyNotFoundError("key was written after the read bound", nil))
+	for _, index := range indexes {
+		if index >= endIndex {
+			break
+		}
+		row, rowResult, err := db.classifyIndex(index, endIndex, controls)
+		if rowResult == "" {
+			return visibleRow{}, "", err
+		}
+		if rowResult != LookupFound {
+			result, reason = rowResult, err
+			continue
+		}
+		tombstone, err := db.tombstoneAt(index)
+		if err != nil {
+			return visibleRow{}, "", err
+		}
+		switch {
+		case tombstone:
+			found, deleted = false, true
+		case !found:
+			resolved, found, deleted = row, true, false
+		}
+	}
+	switch {
+	case found:
+		return resolved, LookupFound, nil
+	case deleted:
+		return visibleRow{}, LookupDeleted, NewKeyNotFoundError(fmt.Sprintf("key %s was deleted", key), nil)
+	}
+	return visibleRow{}, result, reason
+}
+
+// keyIndexes returns the index of every row holding key, in file order, or just
+// the one GetIndex returns when the finder cannot list them.
+func (db *FrozenDB) keyIndexes(key uuid.UUID) ([]int64, error) {
+	if finder, ok := db.finder.(occurrenceFinder); ok {
+		return finder.GetIndexes(key, int64(db.header.GetSkewMs()))
+	}
+	index, err := db.finder.GetIndex(key)
+	if err != nil {
+		return nil, err
+	}
+	return []int64{index}, nil
+}
+
+// classifyIndex implements classifyKey for the data row at index, reporting
+// whether the transaction holding it left the row visible.
+func (db *FrozenDB) classifyIndex(index int64, endIndex int64, controls rowControlReader) (visibleRow, LookupResult, error) {
 	// Get transaction boundaries for the row
 	txStart, err := db.finder.GetTransactionStart(index)
 	if err != nil {
-		return err
+		return visibleRow{}, "", err
 	}
 
 	txEnd, err := db.finder.GetTransactionEnd(index)
@@ -556,44 +909,35 @@ func (db *FrozenDB) Get(key uuid.UUID, value any) error {
 		var txActiveErr *TransactionActiveError
 		if errors.As(err, &txActiveErr) {
 			// Key exists in active transaction - return KeyNotFoundError per spec
-			return NewKeyNotFoundError("key exists only in uncommitted transaction", err)
+			return visibleRow{}, LookupUncommitted, NewKeyNotFoundError("key exists only in uncommitted transaction", err)
 		}
-		return err
+		return visibleRow{}, "", err
+	}
+	if txEnd >= endIndex {
+		return visibleRow{}, LookupUncommitted, NewKeyNotFoundError("key exists only in transaction uncommitted at the read bound", nil)
 	}
 
 	// Read the transaction end row to determine transaction state
-	endRowBytes, err := db.readRowAtIndex(txEnd)
+	startControl, endControl, err := controls(txEnd)
 	if err != nil {
-		return err
+		return visibleRow{}, "", err
 	}
-
-	var endRowUnion RowUnion
-	if err := endRowUnion.UnmarshalText(endRowBytes); err != nil {
-		return NewCorruptDatabaseError("failed to parse transaction end row", err)
-	}
-
-	// Determine transaction validity based on end control
-	var endControl EndControl
-	if endRowUnion.DataRow != nil {
-		endControl = endRowUnion.DataRow.EndControl
-	} else if endRowUnion.NullRow != nil {
-		endControl = endRowUnion.NullRow.EndControl
-	} else {
-		return NewCorruptDatabaseError("transaction end row is not a DataRow or NullRow", nil)
+	if startControl == CHECKSUM_ROW {
+		return visibleRow{}, "", NewCorruptDatabaseError("transaction end row is not a DataRow or NullRow", nil)
 	}
+	row := visibleRow{index: index, txStart: txStart, txEnd: txEnd, txEndControl: endControl}
 
 	// Check transaction termination type
 	second := endControl[1]
 
 	// Full rollback (R0 or S0) - all rows invalid
 	if second == '0' {
-		return NewKeyNotFoundError("key exists only in fully rolled back transaction", nil)
+		return visibleRow{}, LookupRolledBack, NewKeyNotFoundError("key exists only in fully rolled back transaction", nil)
 	}
 
 	// Committed transaction (TC or SC) - all rows valid
 	if second == 'C' {
-		// Key is in committed transaction, proceed to read and unmarshal
-		return db.readAndUnmarshalRow(index, value)
+		return row, LookupFound, nil
 	}
 
 	// Partial rollback (R1-R9 or S1-S9) - need to check savepoint
@@ -606,30 +950,18 @@ func (db *FrozenDB) Get(key uuid.UUID, value any) error {
 
 		// Scan from transaction start to end, finding where savepoint N is
 		for i := txStart; i <= txEnd; i++ {
-			rowBytes, err := db.readRowAtIndex(i)
+			rowStart, rowEnd, err := controls(i)
 			if err != nil {
-				return err
-			}
-
-			var rowUnion RowUnion
-			if err := rowUnion.UnmarshalText(rowBytes); err != nil {
-				return NewCorruptDatabaseError(fmt.Sprintf("failed to parse row at index %d", i), err)
+				return visibleRow{}, "", err
 			}
 
 			// Skip checksum rows
-			if rowUnion.ChecksumRow != nil {
+			if rowStart == CHECKSUM_ROW {
 				continue
 			}
 
 			// Check if this row creates a savepoint
-			var rowEndControl EndControl
-			if rowUnion.DataRow != nil {
-				rowEndControl = rowUnion.DataRow.EndControl
-			} else if rowUnion.NullRow != nil {
-				rowEndControl = rowUnion.NullRow.EndControl
-			}
-
-			if rowEndControl[0] == 'S' {
+			if rowEnd[0] == 'S' {
 				savepointCount++
 				if savepointCount == savepointNum {
 					savepointIndex = i
@@ -638,24 +970,64 @@ func (db *FrozenDB) Get(key uuid.UUID, value any) error {
 		}
 
 		if savepointIndex == -1 {
-			return NewCorruptDatabaseError(fmt.Sprintf("savepoint %d not found in transaction", savepointNum), nil)
+			return visibleRow{}, "", NewCorruptDatabaseError(fmt.Sprintf("savepoint %d not found in transaction", savepointNum), nil)
 		}
 
 		// Key is visible if it's at or before the savepoint row
 		if index <= savepointIndex {
-			return db.readAndUnmarshalRow(index, value)
-		} else {
-			return NewKeyNotFoundError("key exists only after savepoint in partially rolled back transaction", nil)
+			return row, LookupFound, nil
 		}
+		return visibleRow{}, LookupAfterSavepoint, NewKeyNotFoundError("key exists only after savepoint in partially rolled back transaction", nil)
 	}
 
 	// Should not reach here - unknown end control
-	return NewCorruptDatabaseError(fmt.Sprintf("unknown transaction end control: %c%c", endControl[0], endControl[1]), nil)
+	return visibleRow{}, "", NewCorruptDatabaseError(fmt.Sprintf("unknown transaction end control: %c%c", endControl[0], endControl[1]), nil)
+}
+
+// parsedRowControls is a rowControlReader that fully parses and validates the row.
+func (db *FrozenDB) parsedRowControls(index int64) (StartControl, EndControl, error) {
+	rowBytes, err := db.readRowAtIndex(index)
+	if err != nil {
+		return 0, EndControl{}, err
+	}
+
+	var rowUnion RowUnion
+	if err := rowUnion.UnmarshalText(rowBytes); err != nil {
+		return 0, EndControl{}, NewCorruptDatabaseError(fmt.Sprintf("failed to parse row at index %d", index), err)
+	}
+
+	switch {
+	case rowUnion.DataRow != nil:
+		return rowUnion.DataRow.StartControl, rowUnion.DataRow.EndControl, nil
+	case rowUnion.NullRow != nil:
+		return rowUnion.NullRow.StartControl, rowUnion.NullRow.EndControl, nil
+	default:
+		return rowUnion.ChecksumRow.StartControl, rowUnion.ChecksumRow.EndControl, nil
+	}
+}
+
+// refresher is implemented by DBFiles that can synchronously pick up rows appended
+// by another process (FileManager does in read mode).
+type refresher interface {
+	Refresh()
+}
+
+// refresh extends the view of a read handle to the current end of the file before
+// a read, so rows committed by another process are visible without waiting for the
+// file watcher. Bytes below the previously known size never change, so the
+// finder only indexes the appended rows.
+func (db *FrozenDB) refresh() {
+	if r, ok := db.file.(refresher); ok {
+		r.Refresh()
+	}
 }
 
 // readRowAtIndex reads a row at the specified index from the database file.
 // Helper method for Get implementation.
 func (db *FrozenDB) readRowAtIndex(index int64) ([]byte, error) {
+	if err := db.checkRowComplete(index); err != nil {
+		return nil, err
+	}
 	offset := int64(HEADER_SIZE) + index*int64(db.header.GetRowSize())
 	rowBytes, err := db.file.Read(offset, int32(db.header.GetRowSize()))
 	if err != nil {
@@ -664,30 +1036,21 @@ func (db *FrozenDB) readRowAtIndex(index int64) ([]byte, error) {
 	return rowBytes, nil
 }
 
-// readAndUnmarshalRow reads a row at the specified index and unmarshals its JSON value.
-// Helper method for Get implementation.
-func (db *FrozenDB) readAndUnmarshalRow(index int64, value any) error {
+// readValue reads the row at the specified index and returns its decoded JSON value.
+func (db *FrozenDB) readValue(index int64) (json.RawMessage, error) {
 	rowBytes, err := db.readRowAtIndex(index)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	var rowUnion RowUnion
 	if err := rowUnion.UnmarshalText(rowBytes); err != nil {
-		return NewCorruptDatabaseError(fmt.Sprintf("failed to parse row at index %d", index), err)
+		return nil, NewCorruptDatabaseError(fmt.Sprintf("failed to parse row at index %d", index), err)
 	}
 
 	if rowUnion.DataRow == nil {
-		return NewCorruptDatabaseError("target row is not a DataRow", nil)
-	}
-
-	// Extract JSON value from row
-	jsonValue := rowUnion.DataRow.RowPayload.Value
-
-	// Unmarshal JSON into destination
-	if err := json.Unmarshal(jsonValue, value); err != nil {
-		return NewInvalidDataError("failed to unmarshal JSON value", err)
+		return nil, NewCorruptDatabaseError("target row is not a DataRow", nil)
 	}
 
-	return nil
+	return db.decodeValue(rowUnion.DataRow.RowPayload.Value)
 }
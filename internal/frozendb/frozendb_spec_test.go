@@ -1,6 +1,7 @@
 package frozendb
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -200,7 +201,7 @@ func Test_S_002_FR_004_FileDescriptorAndHeaderValidation(t *testing.T) {
 			setupFile: func(t *testing.T, path string) {
 				file, _ := os.Create(path)
 				defer file.Close()
-				invalidHeader := []byte(`{"sig":"fDB","ver":2,"row_size":1024,"skew_ms":5000}` + string(make([]byte, 64-48)))
+				invalidHeader := []byte(`{"sig":"fDB","ver":3,"row_size":1024,"skew_ms":5000}` + string(make([]byte, 64-48)))
 				invalidHeader[63] = '\n'
 				file.Write(invalidHeader)
 			},
@@ -660,7 +661,7 @@ func Test_S_002_FR_013_CorruptDatabaseErrorForHeaderValidationFailures(t *testin
 			setupFile: func(t *testing.T, path string) {
 				file, _ := os.Create(path)
 				defer file.Close()
-				invalidHeader := []byte(`{"sig":"fDB","ver":2,"row_size":1024,"skew_ms":5000}` + string(make([]byte, 64-48)))
+				invalidHeader := []byte(`{"sig":"fDB","ver":3,"row_size":1024,"skew_ms":5000}` + string(make([]byte, 64-48)))
 				invalidHeader[63] = '\n'
 				file.Write(invalidHeader)
 			},
@@ -783,7 +784,7 @@ func Test_S_002_FR_013_CorruptDatabaseErrorForHeaderValidationFailures(t *testin
 				invalidHeader[63] = '\n'
 				file.Write(invalidHeader)
 			},
-			errorContains: "JSON",
+			errorContains: "bad magic",
 		},
 	}
 
@@ -1558,6 +1559,84 @@ func Test_S_007_FR_001_AtomicFileCreation(t *testing.T) {
 	}
 }
 
+// Test_S_007_FR_001_ExistingFile checks that Create never clobbers an existing
+// file unless SetForce(true) is set, and that the override replaces it with a
+// fresh database.
+func Test_S_007_FR_001_ExistingFile(t *testing.T) {
+	setupMockSyscalls(false, false)
+	defer restoreRealSyscalls()
+
+	existing := []byte("not a frozenDB database")
+	newExisting := func(t *testing.T) string {
+		path := filepath.Join(t.TempDir(), "existing.fdb")
+		if err := os.WriteFile(path, existing, FILE_PERMISSIONS); err != nil {
+			t.Fatalf("WriteFile: %v", err)
+		}
+		return path
+	}
+
+	t.Run("rejected without force", func(t *testing.T) {
+		path := newExisting(t)
+		config := NewCreateConfig(path, 1024, 5000)
+		config.SetNoImmutable(true)
+
+		err := Create(config)
+		var pathErr *PathError
+		if !errors.As(err, &pathErr) || !strings.Contains(err.Error(), "file already exists") {
+			t.Fatalf("expected PathError \"file already exists\", got %v", err)
+		}
+		if data, _ := os.ReadFile(path); !bytes.Equal(data, existing) {
+			t.Error("existing file was modified")
+		}
+	})
+
+	t.Run("replaced with force", func(t *testing.T) {
+		path := newExisting(t)
+		config := NewCreateConfig(path, 1024, 5000)
+		config.SetNoImmutable(true)
+		config.SetForce(true)
+
+		if err := Create(config); err != nil {
+			t.Fatalf("Create with force: %v", err)
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatalf("Stat: %v", err)
+		}
+		if info.Size() != int64(HEADER_SIZE+1024) {
+			t.Errorf("File size = %d, want %d (header + checksum row)", info.Size(), HEADER_SIZE+1024)
+		}
+	})
+
+	t.Run("force without existing file", func(t *testing.T) {
+		config := NewCreateConfig(filepath.Join(t.TempDir(), "new.fdb"), 1024, 5000)
+		config.SetNoImmutable(true)
+		config.SetForce(true)
+
+		if err := Create(config); err != nil {
+			t.Fatalf("Create with force: %v", err)
+		}
+	})
+
+	t.Run("force does not replace a directory", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "dir.fdb")
+		if err := os.Mkdir(path, 0755); err != nil {
+			t.Fatalf("Mkdir: %v", err)
+		}
+		config := NewCreateConfig(path, 1024, 5000)
+		config.SetNoImmutable(true)
+		config.SetForce(true)
+
+		var pathErr *PathError
+		if err := Create(config); !errors.As(err, &pathErr) {
+			t.Fatalf("expected PathError, got %v", err)
+		}
+		if info, err := os.Stat(path); err != nil || !info.IsDir() {
+			t.Errorf("directory was removed (stat error %v)", err)
+		}
+	})
+}
+
 func Test_S_007_FR_006_ChecksumRowPositioning(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -1,9 +1,13 @@
 package frozendb
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"os"
+	"path/filepath"
 	"sync"
 	"testing"
 	"time"
@@ -221,7 +225,7 @@ func buildNullRow(rowSize int32, startControl StartControl, endControl EndContro
 func buildChecksumRow(rowSize int32, value uint32) []byte {
 	checksum := Checksum(value)
 	checksumRow := &ChecksumRow{
-		baseRow[*Checksum]{
+		baseRow: baseRow[*Checksum]{
 			RowSize:      int(rowSize),
 			StartControl: CHECKSUM_ROW,
 			EndControl:   CHECKSUM_ROW_CONTROL,
@@ -881,11 +885,12 @@ func TestGet_ErrorHandling(t *testing.T) {
 		data, keys, header := buildTestDatabase(rowSize, rows)
 
 		dbFile := newMockGetDBFile(data, MODE_READ)
-		// Inject read error on 2nd read (first read is during initialization, second is GetIndex)
-		// Initialization reads: row 0 (checksum) = read #1, row 1 (data) = read #2
-		// Get() will do: GetIndex reads row 1 = read #3, readRowAtIndex reads row 1 = read #4
-		// So inject error on read #3 (during GetIndex in Get())
-		dbFile.injectReadError(3, NewReadError("simulated disk failure", nil))
+		// Inject read error during GetIndex, after initialization
+		// Initialization reads: header = read #1, then backward from the tail: row 1 (data) = read #2,
+		// row 0 (checksum) = read #3, since one key cannot settle max_timestamp within skew_ms
+		// Get() will do: GetIndex reads row 1 = read #4, readRowAtIndex reads row 1 = read #5
+		// So inject error on read #4 (during GetIndex in Get())
+		dbFile.injectReadError(4, NewReadError("simulated disk failure", nil))
 
 		finder, finderErr := newTestSimpleFinderForGet(dbFile, rowSize)
 		if finderErr != nil {
@@ -967,11 +972,10 @@ func TestGet_ErrorHandling(t *testing.T) {
 
 		// Directly attempt to read row at index 1 (NullRow)
 		// We can't easily test this through Get() since GetIndex won't return NullRow index
-		// But we can test readAndUnmarshalRow directly
-		var result map[string]interface{}
-		err := db.readAndUnmarshalRow(1, &result)
+		// But we can test readValue directly
+		_, err := db.readValue(1)
 		if err == nil {
-			t.Fatal("readAndUnmarshalRow should fail for NullRow")
+			t.Fatal("readValue should fail for NullRow")
 		}
 
 		if _, ok := err.(*CorruptDatabaseError); !ok {
@@ -1478,3 +1482,328 @@ func BenchmarkGet_WithSavepoints(b *testing.B) {
 		_ = db.Get(keys[0], &result)
 	}
 }
+
+// BenchmarkGet_DenseFile measures Get on a file whose keys are 1 ms apart, so
+// thousands of rows fall within the default skew window of every key. Resolving
+// repeated keys reads that window, and must stay cheap next to the search itself.
+func BenchmarkGet_DenseFile(b *testing.B) {
+	const rows = 20000
+	path := filepath.Join(b.TempDir(), "dense.fdb")
+	entries := make([]Entry, rows)
+	for i := range entries {
+		entries[i] = Entry{Key: uuidFromTS(1_700_000_000_000 + i), Value: json.RawMessage(fmt.Sprintf(`{"i":%d}`, i))}
+	}
+	var buf bytes.Buffer
+	if err := BuildDatabase(&buf, NewCreateConfig(path, 4096, 5000), entries); err != nil {
+		b.Fatalf("BuildDatabase: %v", err)
+	}
+	if err := os.WriteFile(path, buf.Bytes(), FILE_PERMISSIONS); err != nil {
+		b.Fatalf("WriteFile: %v", err)
+	}
+
+	for _, strategy := range []FinderStrategy{FinderStrategyInMemory, FinderStrategyBinarySearch} {
+		b.Run(string(strategy), func(b *testing.B) {
+			db, err := NewFrozenDB(path, MODE_READ, strategy)
+			if err != nil {
+				b.Fatalf("NewFrozenDB: %v", err)
+			}
+			defer db.Close()
+
+			i := 0
+			for b.Loop() {
+				var value map[string]int
+				if err := db.Get(entries[i].Key, &value); err != nil {
+					b.Fatalf("Get: %v", err)
+				}
+				i = (i + 7919) % rows
+			}
+		})
+	}
+}
+
+// =============================================================================
+// Row size range coverage
+// =============================================================================
+
+// TestRowSizeRange_ReadPaths checks that every reader path honours the header's
+// row_size across the MIN_ROW_SIZE..MAX_ROW_SIZE range. Each database crosses
+// two checksum rows after the initial one: the small row sizes hold 25,000 rows
+// at the default checksum interval, while the large ones use a short interval so
+// the files stay small. An interval of 150 leaves some of the 100-row
+// transactions BuildDatabase writes straddling a checksum row.
+func TestRowSizeRange_ReadPaths(t *testing.T) {
+	cases := []struct {
+		rowSize  int
+		numRows  int
+		interval int
+	}{
+		{MIN_ROW_SIZE, 25000, CHECKSUM_INTERVAL},
+		{1000, 25000, CHECKSUM_INTERVAL},
+		{4096, 375, 150},
+		{MAX_ROW_SIZE, 375, 150},
+	}
+	strategies := []FinderStrategy{FinderStrategySimple, FinderStrategyInMemory, FinderStrategyBinarySearch}
+
+	for _, tc := range cases {
+		t.Run(fmt.Sprintf("row_size_%d", tc.rowSize), func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "rows.fdb")
+			config := NewCreateConfig(path, tc.rowSize, confSkewMs)
+			config.SetChecksumInterval(tc.interval)
+			entries := make([]Entry, tc.numRows)
+			for i := range entries {
+				entries[i] = Entry{Key: uuidFromTS(1_700_000_000_000 + i), Value: json.RawMessage(fmt.Sprintf(`{"i":%d}`, i))}
+			}
+			var buf bytes.Buffer
+			if err := BuildDatabase(&buf, config, entries); err != nil {
+				t.Fatalf("BuildDatabase: %v", err)
+			}
+			if err := os.WriteFile(path, buf.Bytes(), FILE_PERMISSIONS); err != nil {
+				t.Fatalf("WriteFile: %v", err)
+			}
+			if _, err := Verify(path); err != nil {
+				t.Fatalf("Verify: %v", err)
+			}
+
+			// Row index of the i-th DataRow, after the checksum rows before it
+			rowIndex := func(i int) int64 { return int64(i + i/tc.interval + 1) }
+			wantSize := int64(HEADER_SIZE) + (rowIndex(tc.numRows-1)+1)*int64(tc.rowSize)
+			if int64(buf.Len()) != wantSize {
+				t.Fatalf("file size = %d, want %d", buf.Len(), wantSize)
+			}
+			samples := []int{0, 1, MAX_TRANSACTION_ROWS - 1, MAX_TRANSACTION_ROWS,
+				tc.interval - 1, tc.interval, tc.interval + 1, 2*tc.interval - 1, 2 * tc.interval, tc.numRows - 1}
+
+			t.Run("scan", func(t *testing.T) {
+				db, err := NewFrozenDB(path, MODE_READ, FinderStrategySimple)
+				if err != nil {
+					t.Fatalf("NewFrozenDB: %v", err)
+				}
+				defer db.Close()
+
+				n := 0
+				err = db.Scan(func(key uuid.UUID, value json.RawMessage) bool {
+					if key != entries[n].Key {
+						t.Fatalf("Scan row %d key = %s, want %s", n, key, entries[n].Key)
+					}
+					n++
+					return true
+				})
+				if err != nil {
+					t.Fatalf("Scan: %v", err)
+				}
+				if n != tc.numRows {
+					t.Fatalf("Scan returned %d rows, want %d", n, tc.numRows)
+				}
+			})
+
+			for _, strategy := range strategies {
+				t.Run(string(strategy), func(t *testing.T) {
+					db, err := NewFrozenDB(path, MODE_READ, strategy)
+					if err != nil {
+						t.Fatalf("NewFrozenDB: %v", err)
+					}
+					defer db.Close()
+
+					if got := db.header.GetRowSize(); got != tc.rowSize {
+						t.Fatalf("header row size = %d, want %d", got, tc.rowSize)
+					}
+					if got, want := db.finder.MaxTimestamp(), ExtractUUIDv7Timestamp(entries[tc.numRows-1].Key); got != want {
+						t.Errorf("MaxTimestamp = %d, want %d", got, want)
+					}
+
+					for _, i := range samples {
+						var got struct {
+							I int `json:"i"`
+						}
+						if err := db.Get(entries[i].Key, &got); err != nil {
+							t.Fatalf("Get(row %d): %v", i, err)
+						}
+						if got.I != i {
+							t.Errorf("Get(row %d) returned value for row %d", i, got.I)
+						}
+
+						idx, err := db.finder.GetIndex(entries[i].Key)
+						if err != nil {
+							t.Fatalf("GetIndex(row %d): %v", i, err)
+						}
+						if idx != rowIndex(i) {
+							t.Errorf("GetIndex(row %d) = %d, want %d", i, idx, rowIndex(i))
+						}
+
+						txFirst := i - i%MAX_TRANSACTION_ROWS
+						txLast := min(txFirst+MAX_TRANSACTION_ROWS, tc.numRows) - 1
+						start, err := db.finder.GetTransactionStart(idx)
+						if err != nil {
+							t.Fatalf("GetTransactionStart(%d): %v", idx, err)
+						}
+						if start != rowIndex(txFirst) {
+							t.Errorf("GetTransactionStart(%d) = %d, want %d", idx, start, rowIndex(txFirst))
+						}
+						end, err := db.finder.GetTransactionEnd(idx)
+						if err != nil {
+							t.Fatalf("GetTransactionEnd(%d): %v", idx, err)
+						}
+						if end != rowIndex(txLast) {
+							t.Errorf("GetTransactionEnd(%d) = %d, want %d", idx, end, rowIndex(txLast))
+						}
+					}
+				})
+			}
+		})
+	}
+}
+
+func TestGet_ReadHandleSeesCommitsWithoutReopen(t *testing.T) {
+	for _, strategy := range []FinderStrategy{FinderStrategySimple, FinderStrategyInMemory, FinderStrategyBinarySearch} {
+		t.Run(string(strategy), func(t *testing.T) {
+			path := setupCreate(t, t.TempDir(), 0)
+			writer, err := NewFrozenDB(path, MODE_WRITE, FinderStrategySimple)
+			if err != nil {
+				t.Fatalf("NewFrozenDB(write): %v", err)
+			}
+			defer writer.Close()
+			reader, err := NewFrozenDB(path, MODE_READ, strategy)
+			if err != nil {
+				t.Fatalf("NewFrozenDB(read): %v", err)
+			}
+			defer reader.Close()
+
+			for i := 0; i < 20; i++ {
+				key := uuidFromTS(1000 + i)
+				tx, err := writer.BeginTx()
+				if err != nil {
+					t.Fatalf("BeginTx: %v", err)
+				}
+				mustAdd(t, tx, key, fmt.Sprintf(`{"i":%d}`, i))
+				if err := tx.Commit(); err != nil {
+					t.Fatalf("Commit: %v", err)
+				}
+
+				// The very next read sees the commit, without waiting for the file watcher
+				var value map[string]int
+				if err := reader.Get(key, &value); err != nil {
+					t.Fatalf("commit %d: Get: %v", i, err)
+				}
+				if value["i"] != i {
+					t.Fatalf("commit %d: Get = %v", i, value)
+				}
+			}
+
+			count := 0
+			if err := reader.Scan(func(uuid.UUID, json.RawMessage) bool {
+				count++
+				return true
+			}); err != nil {
+				t.Fatalf("Scan: %v", err)
+			}
+			if count != 20 {
+				t.Errorf("Scan visited %d rows, want 20", count)
+			}
+		})
+	}
+}
+
+func TestNewFrozenDBWithFile(t *testing.T) {
+	path := setupCreate(t, t.TempDir(), 0)
+	addDataRowsInOrder(t, path, []int{1000, 2000, 3000})
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+
+	for _, strategy := range []FinderStrategy{FinderStrategySimple, FinderStrategyInMemory, FinderStrategyBinarySearch, FinderStrategyAuto} {
+		t.Run(string(strategy), func(t *testing.T) {
+			file := newMockGetDBFile(data, MODE_READ)
+			db, err := NewFrozenDBWithFile(file, strategy)
+			if err != nil {
+				t.Fatalf("NewFrozenDBWithFile: %v", err)
+			}
+			var value map[string]any
+			for _, ts := range []int{1000, 2000, 3000} {
+				if err := db.Get(uuidFromTS(ts), &value); err != nil {
+					t.Errorf("Get %d: %v", ts, err)
+				}
+			}
+			if rows := scanAll(t, db); len(rows) != 3 {
+				t.Errorf("Scan returned %d rows, want 3", len(rows))
+			}
+			if err := db.Close(); err != nil {
+				t.Fatalf("Close: %v", err)
+			}
+			if !file.isClosed {
+				t.Errorf("Close did not close the DBFile")
+			}
+		})
+	}
+
+	var invalidAction *InvalidActionError
+	if _, err := NewFrozenDBWithFile(newMockGetDBFile(data, MODE_WRITE), FinderStrategySimple); !errors.As(err, &invalidAction) {
+		t.Errorf("write-mode file: expected InvalidActionError, got %v", err)
+	}
+	var invalidInput *InvalidInputError
+	if _, err := NewFrozenDBWithFile(nil, FinderStrategySimple); !errors.As(err, &invalidInput) {
+		t.Errorf("nil file: expected InvalidInputError, got %v", err)
+	}
+	if _, err := NewFrozenDBWithFile(newMockGetDBFile(data, MODE_READ), "bogus"); !errors.As(err, &invalidInput) {
+		t.Errorf("invalid strategy: expected InvalidInputError, got %v", err)
+	}
+
+	// The header is validated through the file, which is left open on error
+	corrupt := bytes.Clone(data)
+	corrupt[0] = 'x'
+	file := newMockGetDBFile(corrupt, MODE_READ)
+	var corruptErr *CorruptDatabaseError
+	if _, err := NewFrozenDBWithFile(file, FinderStrategySimple); !errors.As(err, &corruptErr) {
+		t.Errorf("corrupt header: expected CorruptDatabaseError, got %v", err)
+	}
+	if file.isClosed {
+		t.Errorf("file closed after a failed open")
+	}
+}
+
+func TestFrozenDB_OnClose(t *testing.T) {
+	path := setupCreate(t, t.TempDir(), 0)
+	db, err := NewFrozenDB(path, MODE_WRITE, FinderStrategySimple)
+	if err != nil {
+		t.Fatalf("NewFrozenDB: %v", err)
+	}
+
+	var order []string
+	db.OnClose(func() {
+		// The write lock is already released, so a new writer can open the file
+		writer, err := NewFrozenDB(path, MODE_WRITE, FinderStrategySimple)
+		if err != nil {
+			t.Errorf("opening a writer in the hook: %v", err)
+			return
+		}
+		_ = writer.Close()
+		order = append(order, "first")
+	})
+	db.OnClose(nil)
+	db.OnClose(func() { order = append(order, "second") })
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := db.Close(); err != nil {
+				t.Errorf("Close: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+	if err := db.Close(); err != nil {
+		t.Errorf("second Close: %v", err)
+	}
+	if len(order) != 2 || order[0] != "first" || order[1] != "second" {
+		t.Errorf("hooks ran %v, want [first second] once", order)
+	}
+
+	// Registered after Close: runs immediately
+	ran := false
+	db.OnClose(func() { ran = true })
+	if !ran {
+		t.Error("OnClose after Close should run the callback immediately")
+	}
+}
@@ -0,0 +1,100 @@
+package frozendb
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// GetField retrieves the value of key, following the same visibility rules as Get,
+// and unmarshals only the part of it at path into dest.
+//
+// path is a dotted path such as "user.name". Each segment names a field of an
+// object; a segment of decimal digits indexes an array instead, as in
+// "items.0.id". Only the objects and arrays along the path are decoded, each one
+// level deep, so the rest of the value is never unmarshaled.
+//
+// Parameters:
+//   - key: UUIDv7 key to search for (must not be uuid.Nil)
+//   - path: Dotted path of the field (must not be empty or contain empty segments)
+//   - dest: Pointer to unmarshal the field into (must not be nil)
+//
+// Returns:
+//   - error: InvalidInputError (invalid arguments, or path does not resolve),
+//     KeyNotFoundError, InvalidDataError, ReadError, or CorruptDatabaseError
+//
+// Thread Safety: Safe for concurrent calls on the same FrozenDB instance
+func (db *FrozenDB) GetField(key uuid.UUID, path string, dest any) error {
+	if dest == nil {
+		return NewInvalidInputError("dest cannot be nil", nil)
+	}
+	segments := strings.Split(path, ".")
+	for _, segment := range segments {
+		if segment == "" {
+			return NewInvalidInputError(fmt.Sprintf("invalid path %q: empty segment", path), nil)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := db.GetInto(key, &buf); err != nil {
+		return err
+	}
+	field, err := jsonField(buf.Bytes(), segments)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(field, dest); err != nil {
+		return NewInvalidDataError(fmt.Sprintf("failed to unmarshal JSON value at %q", path), err)
+	}
+	return nil
+}
+
+// jsonField returns the JSON at the dotted path segments of value, decoding one
+// level of each object or array along the way.
+func jsonField(value json.RawMessage, segments []string) (json.RawMessage, error) {
+	current := value
+	for i, segment := range segments {
+		resolved := strings.Join(segments[:i+1], ".")
+		switch firstJSONByte(current) {
+		case '{':
+			var object map[string]json.RawMessage
+			if err := json.Unmarshal(current, &object); err != nil {
+				return nil, NewInvalidDataError(fmt.Sprintf("failed to decode JSON object before %q", resolved), err)
+			}
+			field, ok := object[segment]
+			if !ok {
+				return nil, NewInvalidInputError(fmt.Sprintf("path %q does not resolve: no field %q", resolved, segment), nil)
+			}
+			current = field
+		case '[':
+			index, err := strconv.Atoi(segment)
+			if err != nil || index < 0 {
+				return nil, NewInvalidInputError(fmt.Sprintf("path %q does not resolve: %q is not an array index", resolved, segment), nil)
+			}
+			var array []json.RawMessage
+			if err := json.Unmarshal(current, &array); err != nil {
+				return nil, NewInvalidDataError(fmt.Sprintf("failed to decode JSON array before %q", resolved), err)
+			}
+			if index >= len(array) {
+				return nil, NewInvalidInputError(fmt.Sprintf("path %q does not resolve: index %d out of range for array of length %d", resolved, index, len(array)), nil)
+			}
+			current = array[index]
+		default:
+			return nil, NewInvalidInputError(fmt.Sprintf("path %q does not resolve: parent is not an object or array", resolved), nil)
+		}
+	}
+	return current, nil
+}
+
+// firstJSONByte returns the first non-whitespace byte of a JSON value, or 0.
+func firstJSONByte(value json.RawMessage) byte {
+	trimmed := bytes.TrimLeft(value, " \t\r\n")
+	if len(trimmed) == 0 {
+		return 0
+	}
+	return trimmed[0]
+}
@@ -0,0 +1,50 @@
+package frozendb
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestGetField(t *testing.T) {
+	rows := []testRow{
+		{rowType: "data", value: `{"user":{"name":"ada","tags":["a","b"]},"items":[{"id":7}],"n":null}`, startControl: START_TRANSACTION, endControl: ROW_END_CONTROL},
+		{rowType: "data", value: `[1,2,3]`, startControl: ROW_CONTINUE, endControl: TRANSACTION_COMMIT},
+	}
+	db, keys := openTestDatabaseFile(t, 1024, rows, FinderStrategySimple)
+
+	var name string
+	if err := db.GetField(keys[0], "user.name", &name); err != nil || name != "ada" {
+		t.Errorf("GetField(user.name) = %q, %v", name, err)
+	}
+	var tags []string
+	if err := db.GetField(keys[0], "user.tags", &tags); err != nil || len(tags) != 2 || tags[1] != "b" {
+		t.Errorf("GetField(user.tags) = %v, %v", tags, err)
+	}
+	var id int
+	if err := db.GetField(keys[0], "items.0.id", &id); err != nil || id != 7 {
+		t.Errorf("GetField(items.0.id) = %d, %v", id, err)
+	}
+	var n int
+	if err := db.GetField(keys[1], "2", &n); err != nil || n != 3 {
+		t.Errorf("GetField(2) = %d, %v", n, err)
+	}
+
+	var invalidInput *InvalidInputError
+	for _, path := range []string{"", "user..name", "user.email", "user.name.first", "items.1", "items.id", "n.x"} {
+		var dest any
+		if err := db.GetField(keys[0], path, &dest); !errors.As(err, &invalidInput) {
+			t.Errorf("GetField(%q): expected InvalidInputError, got %v", path, err)
+		}
+	}
+
+	var notFound *KeyNotFoundError
+	if err := db.GetField(uuid.Must(uuid.NewV7()), "user.name", &name); !errors.As(err, &notFound) {
+		t.Errorf("missing key: expected KeyNotFoundError, got %v", err)
+	}
+	var invalidData *InvalidDataError
+	if err := db.GetField(keys[0], "user.name", &id); !errors.As(err, &invalidData) {
+		t.Errorf("type mismatch: expected InvalidDataError, got %v", err)
+	}
+}
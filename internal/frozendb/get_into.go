@@ -0,0 +1,192 @@
+package frozendb
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"math"
+
+	"github.com/google/uuid"
+)
+
+// readerInto is implemented by DBFiles that can read into a caller-provided
+// buffer (FileManager does). Reads fall back to DBFile.Read otherwise.
+type readerInto interface {
+	ReadInto(dst []byte, start int64) error
+}
+
+// rowFrame holds the parts of a row located by parseRowFrame. The slices alias
+// the row bytes they were parsed from.
+type rowFrame struct {
+	startControl StartControl
+	endControl   EndControl
+	payload      []byte // Bytes between start_control and the padding
+}
+
+// GetInto copies the raw JSON value of key into buf, following the same visibility
+// and delete rules as Get. buf is reset first; on error its contents are unspecified.
+//
+// Unlike Get, GetInto does not unmarshal the value and reads rows into buffers
+// reused across calls, so a lookup through a finder with O(1) lookups
+// (FinderStrategyInMemory) does not allocate once buf has grown to the largest
+// value. Callers decode buf themselves, for example with a reused json.Decoder.
+// Rows are validated by their framing, control bytes, and parity, and the stored
+// key must match key.
+//
+// Parameters:
+//   - key: UUIDv7 key to search for (must not be uuid.Nil)
+//   - buf: Caller-owned buffer receiving the JSON value (must not be nil)
+//
+// Returns:
+//   - error: InvalidInputError, KeyNotFoundError, ReadError, or CorruptDatabaseError
+//
+// Thread Safety: Safe for concurrent calls on the same FrozenDB instance
+func (db *FrozenDB) GetInto(key uuid.UUID, buf *bytes.Buffer) error {
+	if key == uuid.Nil {
+		return NewInvalidInputError("key cannot be uuid.Nil", nil)
+	}
+	if buf == nil {
+		return NewInvalidInputError("buf cannot be nil", nil)
+	}
+
+	return db.withValue(key, func(value []byte) error {
+		buf.Reset()
+		buf.Write(value)
+		return nil
+	})
+}
+
+// withValue implements GetInto, calling fn with the JSON value of key. The value
+// may alias a pooled row buffer and must not be retained after fn returns. fn's
+// error is returned as is.
+func (db *FrozenDB) withValue(key uuid.UUID, fn func(value []byte) error) error {
+	db.refresh()
+	index, err := db.visibleIndex(key, math.MaxInt64, db.framedRowControls)
+	if err != nil {
+		return err
+	}
+
+	row := db.getRowBuffer()
+	defer db.rowBufs.Put(row)
+	frame, err := db.readRowFrame(index, *row)
+	if err != nil {
+		return err
+	}
+	if frame.startControl == CHECKSUM_ROW || frame.endControl == NULL_ROW_CONTROL {
+		return NewCorruptDatabaseError("target row is not a DataRow", nil)
+	}
+	if len(frame.payload) <= 24 {
+		return NewCorruptDatabaseError(fmt.Sprintf("row at index %d has no value", index), nil)
+	}
+
+	// Confirm the row holds the requested key; decode into a stack buffer
+	var decoded [18]byte
+	n, err := base64.StdEncoding.Strict().Decode(decoded[:], frame.payload[:24])
+	if err != nil || n != 16 {
+		return NewCorruptDatabaseError(fmt.Sprintf("invalid key encoding in row at index %d", index), err)
+	}
+	if !bytes.Equal(decoded[:16], key[:]) {
+		return NewCorruptDatabaseError(fmt.Sprintf("row at index %d does not hold key %s", index, key), nil)
+	}
+
+	value, err := db.decodeValue(frame.payload[24:])
+	if err != nil {
+		return err
+	}
+	return fn(value)
+}
+
+// framedRowControls is a rowControlReader that validates only the row framing,
+// control bytes, and parity, reading into a pooled buffer.
+func (db *FrozenDB) framedRowControls(index int64) (StartControl, EndControl, error) {
+	row := db.getRowBuffer()
+	defer db.rowBufs.Put(row)
+	frame, err := db.readRowFrame(index, *row)
+	if err != nil {
+		return 0, EndControl{}, err
+	}
+	return frame.startControl, frame.endControl, nil
+}
+
+// getRowBuffer returns a pooled buffer of exactly row_size bytes.
+func (db *FrozenDB) getRowBuffer() *[]byte {
+	rowSize := db.header.GetRowSize()
+	if row, ok := db.rowBufs.Get().(*[]byte); ok && len(*row) == rowSize {
+		return row
+	}
+	row := make([]byte, rowSize)
+	return &row
+}
+
+// readRowFrame reads the row at index into row and parses its frame.
+func (db *FrozenDB) readRowFrame(index int64, row []byte) (rowFrame, error) {
+	offset := int64(HEADER_SIZE) + index*int64(len(row))
+	if r, ok := db.file.(readerInto); ok {
+		if err := db.checkRowComplete(index); err != nil {
+			return rowFrame{}, err
+		}
+		if err := r.ReadInto(row, offset); err != nil {
+			return rowFrame{}, NewReadError(fmt.Sprintf("failed to read row at index %d", index), err)
+		}
+	} else {
+		rowBytes, err := db.readRowAtIndex(index)
+		if err != nil {
+			return rowFrame{}, err
+		}
+		copy(row, rowBytes)
+	}
+
+	frame, err := parseRowFrame(row)
+	if err != nil {
+		return rowFrame{}, NewCorruptDatabaseError(fmt.Sprintf("failed to parse row at index %d", index), err)
+	}
+	return frame, nil
+}
+
+// parseRowFrame validates ROW_START, ROW_END, parity, padding, and the control
+// bytes of a complete row without decoding its payload.
+func parseRowFrame(row []byte) (rowFrame, error) {
+	rowSize := len(row)
+	if rowSize < minRowBytes {
+		return rowFrame{}, NewInvalidInputError("row is too short", nil)
+	}
+	if row[0] != ROW_START {
+		return rowFrame{}, NewInvalidInputError(fmt.Sprintf("invalid ROW_START: expected 0x%02X, got 0x%02X", ROW_START, row[0]), nil)
+	}
+	if row[rowSize-1] != ROW_END {
+		return rowFrame{}, NewInvalidInputError(fmt.Sprintf("invalid ROW_END: expected 0x%02X, got 0x%02X", ROW_END, row[rowSize-1]), nil)
+	}
+
+	var xor byte
+	for _, b := range row[:rowSize-3] {
+		xor ^= b
+	}
+	const hexDigits = "0123456789ABCDEF"
+	if row[rowSize-3] != hexDigits[xor>>4] || row[rowSize-2] != hexDigits[xor&0x0F] {
+		return rowFrame{}, NewInvalidInputError("parity mismatch", nil)
+	}
+
+	frame := rowFrame{
+		startControl: StartControl(row[1]),
+		endControl:   EndControl{row[rowSize-5], row[rowSize-4]},
+	}
+	if err := frame.startControl.Validate(); err != nil {
+		return rowFrame{}, NewInvalidInputError("invalid start_control", err)
+	}
+	if err := frame.endControl.Validate(); err != nil {
+		return rowFrame{}, NewInvalidInputError("invalid end_control", err)
+	}
+
+	// Same payload and padding bounds as baseRow.UnmarshalText
+	padding := bytes.IndexByte(row[2:rowSize-6], NULL_BYTE)
+	if padding == -1 {
+		return rowFrame{}, NewInvalidInputError("no null byte found to mark padding start", nil)
+	}
+	frame.payload = row[2 : 2+padding]
+	for _, b := range row[2+padding : rowSize-5] {
+		if b != NULL_BYTE {
+			return rowFrame{}, NewInvalidInputError("invalid padding byte", nil)
+		}
+	}
+	return frame, nil
+}
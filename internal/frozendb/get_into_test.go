@@ -0,0 +1,160 @@
+package frozendb
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+// openTestDatabaseFile writes a database built by buildTestDatabase to disk and
+// opens it in read mode.
+func openTestDatabaseFile(tb testing.TB, rowSize int32, rows []testRow, strategy FinderStrategy) (*FrozenDB, []uuid.UUID) {
+	tb.Helper()
+	data, keys, _ := buildTestDatabase(rowSize, rows)
+	// buildTestDatabase stores a zero checksum; opening verifies the header CRC
+	checksumRow, err := NewChecksumRow(int(rowSize), data[:HEADER_SIZE])
+	if err != nil {
+		tb.Fatalf("NewChecksumRow: %v", err)
+	}
+	checksumBytes, err := checksumRow.MarshalText()
+	if err != nil {
+		tb.Fatalf("MarshalText: %v", err)
+	}
+	copy(data[HEADER_SIZE:], checksumBytes)
+
+	path := filepath.Join(tb.TempDir(), "get_into.fdb")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		tb.Fatalf("WriteFile: %v", err)
+	}
+	db, err := NewFrozenDB(path, MODE_READ, strategy)
+	if err != nil {
+		tb.Fatalf("NewFrozenDB: %v", err)
+	}
+	tb.Cleanup(func() { _ = db.Close() })
+	return db, keys
+}
+
+// committedTransactionRows returns n data rows forming one committed transaction.
+func committedTransactionRows(n int) []testRow {
+	rows := make([]testRow, n)
+	for i := range rows {
+		rows[i] = testRow{rowType: "data", value: fmt.Sprintf(`{"index":%d}`, i), startControl: ROW_CONTINUE, endControl: ROW_END_CONTROL}
+	}
+	rows[0].startControl = START_TRANSACTION
+	rows[n-1].endControl = TRANSACTION_COMMIT
+	return rows
+}
+
+func TestGetInto_MatchesGet(t *testing.T) {
+	rows := []testRow{
+		// Committed transaction
+		{rowType: "data", value: `{"n":1}`, startControl: START_TRANSACTION, endControl: ROW_END_CONTROL},
+		{rowType: "data", value: `{"n":2}`, startControl: ROW_CONTINUE, endControl: TRANSACTION_COMMIT},
+		// Partial rollback to savepoint 1: only the first row is visible
+		{rowType: "data", value: `{"n":3}`, startControl: START_TRANSACTION, endControl: SAVEPOINT_CONTINUE},
+		{rowType: "data", value: `{"n":4}`, startControl: ROW_CONTINUE, endControl: EndControl{'R', '1'}},
+		// Full rollback
+		{rowType: "data", value: `{"n":5}`, startControl: START_TRANSACTION, endControl: FULL_ROLLBACK},
+		// Transaction still in progress
+		{rowType: "data", value: `{"n":6}`, startControl: START_TRANSACTION, endControl: ROW_END_CONTROL},
+	}
+
+	for _, strategy := range []FinderStrategy{FinderStrategySimple, FinderStrategyInMemory, FinderStrategyBinarySearch} {
+		t.Run(string(strategy), func(t *testing.T) {
+			db, keys := openTestDatabaseFile(t, 512, rows, strategy)
+
+			var buf bytes.Buffer
+			buf.WriteString("stale contents")
+			for i, key := range keys {
+				var want json.RawMessage
+				getErr := db.Get(key, &want)
+				intoErr := db.GetInto(key, &buf)
+
+				if (getErr == nil) != (intoErr == nil) {
+					t.Fatalf("row %d: Get error %v, GetInto error %v", i, getErr, intoErr)
+				}
+				if getErr != nil {
+					var notFound *KeyNotFoundError
+					if !errors.As(intoErr, &notFound) {
+						t.Errorf("row %d: expected KeyNotFoundError, got %v", i, intoErr)
+					}
+					continue
+				}
+				if buf.String() != string(want) {
+					t.Errorf("row %d: GetInto = %q, want %q", i, buf.String(), want)
+				}
+			}
+		})
+	}
+}
+
+func TestGetInto_InvalidInput(t *testing.T) {
+	db, keys := openTestDatabaseFile(t, 512, committedTransactionRows(1), FinderStrategyInMemory)
+
+	var invalidInput *InvalidInputError
+	if err := db.GetInto(keys[0], nil); !errors.As(err, &invalidInput) {
+		t.Errorf("nil buf: expected InvalidInputError, got %v", err)
+	}
+	var buf bytes.Buffer
+	if err := db.GetInto(uuid.Nil, &buf); !errors.As(err, &invalidInput) {
+		t.Errorf("nil key: expected InvalidInputError, got %v", err)
+	}
+}
+
+func TestParseRowFrame_DetectsCorruption(t *testing.T) {
+	key := uuid.Must(uuid.NewV7())
+	row := buildDataRow(256, key, `{"a":1}`, START_TRANSACTION, TRANSACTION_COMMIT)
+
+	frame, err := parseRowFrame(row)
+	if err != nil {
+		t.Fatalf("parseRowFrame: %v", err)
+	}
+	if frame.startControl != START_TRANSACTION || frame.endControl != TRANSACTION_COMMIT || string(frame.payload[24:]) != `{"a":1}` {
+		t.Fatalf("unexpected frame: %+v", frame)
+	}
+
+	corrupted := append([]byte(nil), row...)
+	corrupted[30] ^= 0x01
+	if _, err := parseRowFrame(corrupted); err == nil {
+		t.Error("expected parity mismatch for flipped payload bit")
+	}
+	corrupted = append([]byte(nil), row...)
+	corrupted[len(corrupted)-1] = 'x'
+	if _, err := parseRowFrame(corrupted); err == nil {
+		t.Error("expected error for invalid ROW_END")
+	}
+}
+
+// BenchmarkGet_InMemoryFinder and BenchmarkGetInto_InMemoryFinder compare the
+// per-lookup allocations of Get and GetInto on the same database.
+func BenchmarkGet_InMemoryFinder(b *testing.B) {
+	db, keys := openTestDatabaseFile(b, 512, committedTransactionRows(100), FinderStrategyInMemory)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var result map[string]interface{}
+		if err := db.Get(keys[i%len(keys)], &result); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkGetInto_InMemoryFinder(b *testing.B) {
+	db, keys := openTestDatabaseFile(b, 512, committedTransactionRows(100), FinderStrategyInMemory)
+
+	var buf bytes.Buffer
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := db.GetInto(keys[i%len(keys)], &buf); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
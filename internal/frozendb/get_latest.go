@@ -0,0 +1,80 @@
+package frozendb
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// GetLatest retrieves the value of the last committed occurrence of key in file
+// order and unmarshals it into value. A key may be written again by a later
+// transaction when its timestamp is still within the skew window; Get considers
+// only the first occurrence, while GetLatest lets a later write shadow an
+// earlier one for upsert-style use. For a key written once, both return the same
+// value. A key whose last committed occurrence is a tombstone written by Delete
+// is not found.
+//
+// The file is read backward from the tail, one transaction at a time, and
+// reading stops at the first transaction holding a visible occurrence, or at a
+// row whose timestamp is skew_ms or more below the key's, before which no
+// occurrence can exist. Visibility follows the same rules as Get: rolled back
+// rows and any transaction still in progress are skipped.
+//
+// Parameters:
+//   - key: UUIDv7 key to search for (must not be uuid.Nil)
+//   - value: Destination for unmarshaling JSON data (must be non-nil pointer)
+//
+// Returns:
+//   - error: nil on success, or one of:
+//   - InvalidInputError: value is nil, or key is invalid
+//   - KeyNotFoundError: no committed occurrence of key
+//   - InvalidDataError: JSON unmarshal failed
+//   - ReadError: disk I/O failure
+//   - CorruptDatabaseError: data corruption detected
+//
+// Thread Safety: Safe for concurrent calls on the same FrozenDB instance
+func (db *FrozenDB) GetLatest(key uuid.UUID, value any) error {
+	if key == uuid.Nil {
+		return NewInvalidInputError("key cannot be uuid.Nil", nil)
+	}
+	if err := ValidateUUIDv7(key); err != nil {
+		return err
+	}
+	if value == nil {
+		return NewInvalidInputError("value cannot be nil", nil)
+	}
+
+	db.refresh()
+	reader := newReverseTransactionReader(db.file, db.header.GetRowSize(), db.file.Size())
+	floor := ExtractUUIDv7Timestamp(key) - int64(db.header.GetSkewMs())
+	for {
+		visible, minTs, ok, err := reader.prevTransaction()
+		if err != nil {
+			return err
+		}
+		if !ok {
+			break
+		}
+		for i := len(visible) - 1; i >= 0; i-- {
+			if visible[i].GetKey() != key {
+				continue
+			}
+			raw, err := db.decodeValue(visible[i].GetValue())
+			if err != nil {
+				return err
+			}
+			if isTombstone(raw) {
+				return NewKeyNotFoundError(fmt.Sprintf("key %s was deleted", key), nil)
+			}
+			if err := json.Unmarshal(raw, value); err != nil {
+				return NewInvalidDataError("failed to unmarshal JSON value", err)
+			}
+			return nil
+		}
+		if minTs <= floor {
+			break
+		}
+	}
+	return NewKeyNotFoundError(fmt.Sprintf("key %s not found in committed transactions", key), nil)
+}
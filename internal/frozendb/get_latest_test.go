@@ -0,0 +1,151 @@
+package frozendb
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestGet_RepeatedKey pins down both read semantics for a key committed by more
+// than one transaction: Get returns the first committed occurrence and GetLatest
+// the last, with every finder strategy.
+func TestGet_RepeatedKey(t *testing.T) {
+	for _, strategy := range []FinderStrategy{FinderStrategySimple, FinderStrategyInMemory, FinderStrategyBinarySearch} {
+		t.Run(string(strategy), func(t *testing.T) {
+			path := setupCreate(t, t.TempDir(), 0)
+			db, err := NewFrozenDB(path, MODE_WRITE, strategy)
+			if err != nil {
+				t.Fatalf("NewFrozenDB: %v", err)
+			}
+			defer db.Close()
+
+			repeated := uuidFromTS(1000)
+			retried := uuidFromTS(1001)
+			write := func(commit bool, rows ...[2]string) {
+				t.Helper()
+				tx, err := db.BeginTx()
+				if err != nil {
+					t.Fatalf("BeginTx: %v", err)
+				}
+				for _, r := range rows {
+					key := repeated
+					if r[0] == "retried" {
+						key = retried
+					}
+					mustAdd(t, tx, key, r[1])
+				}
+				if commit {
+					err = tx.Commit()
+				} else {
+					err = tx.Rollback(0)
+				}
+				if err != nil {
+					t.Fatalf("ending transaction: %v", err)
+				}
+			}
+			write(true, [2]string{"repeated", `{"v":1}`})
+			write(false, [2]string{"retried", `{"v":1}`})
+			write(true, [2]string{"repeated", `{"v":2}`}, [2]string{"retried", `{"v":2}`})
+			write(false, [2]string{"repeated", `{"v":3}`})
+
+			reopened, err := NewFrozenDB(path, MODE_READ, strategy)
+			if err != nil {
+				t.Fatalf("NewFrozenDB(MODE_READ): %v", err)
+			}
+			defer reopened.Close()
+
+			for name, db := range map[string]*FrozenDB{"writer": db, "reopened": reopened} {
+				var got struct{ V int }
+				if err := db.Get(repeated, &got); err != nil || got.V != 1 {
+					t.Errorf("%s: Get(repeated) = %d, %v; want first occurrence 1", name, got.V, err)
+				}
+				if err := db.GetLatest(repeated, &got); err != nil || got.V != 2 {
+					t.Errorf("%s: GetLatest(repeated) = %d, %v; want last committed occurrence 2", name, got.V, err)
+				}
+
+				// The first occurrence of retried was rolled back, so every read
+				// resolves to the committed retry
+				got.V = 0
+				if err := db.Get(retried, &got); err != nil || got.V != 2 {
+					t.Errorf("%s: Get(retried) = %d, %v; want committed retry 2", name, got.V, err)
+				}
+				got.V = 0
+				if err := db.GetLatest(retried, &got); err != nil || got.V != 2 {
+					t.Errorf("%s: GetLatest(retried) = %d, %v; want 2", name, got.V, err)
+				}
+				if result, err := db.Lookup(retried); err != nil || result != LookupFound {
+					t.Errorf("%s: Lookup(retried) = %s, %v; want %s", name, result, err, LookupFound)
+				}
+				value, meta, err := db.GetWithMeta(retried)
+				if err != nil || string(value) != `{"v":2}` || meta.TxOutcome != TxOutcomeCommitted {
+					t.Errorf("%s: GetWithMeta(retried) = %s, %+v, %v; want the committed retry", name, value, meta, err)
+				}
+				if offset, ok, err := db.OffsetOf(retried); err != nil || !ok || offset != meta.Offset {
+					t.Errorf("%s: OffsetOf(retried) = %d, %v, %v; want %d", name, offset, ok, err, meta.Offset)
+				}
+			}
+
+			var got struct{ V int }
+			var notFound *KeyNotFoundError
+			if err := db.GetLatest(uuidFromTS(999), &got); !errors.As(err, &notFound) {
+				t.Errorf("GetLatest(missing): expected KeyNotFoundError, got %v", err)
+			}
+		})
+	}
+}
+
+func TestGetLatest_StopsOutsideSkewWindow(t *testing.T) {
+	path := setupCreate(t, t.TempDir(), 0)
+	db, err := NewFrozenDB(path, MODE_WRITE, FinderStrategySimple)
+	if err != nil {
+		t.Fatalf("NewFrozenDB: %v", err)
+	}
+	defer db.Close()
+
+	old := uuidFromTS(1000)
+	tx, err := db.BeginTx()
+	if err != nil {
+		t.Fatalf("BeginTx: %v", err)
+	}
+	mustAdd(t, tx, old, `{"v":1}`)
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+	// Newer transactions beyond the skew window must be read through to reach it.
+	for i := 0; i < 3; i++ {
+		tx, err := db.BeginTx()
+		if err != nil {
+			t.Fatalf("BeginTx: %v", err)
+		}
+		mustAdd(t, tx, uuidFromTS(1000+(i+1)*2*confSkewMs), `{}`)
+		if err := tx.Commit(); err != nil {
+			t.Fatalf("Commit: %v", err)
+		}
+	}
+
+	var got struct{ V int }
+	if err := db.GetLatest(old, &got); err != nil || got.V != 1 {
+		t.Errorf("GetLatest(old) = %d, %v; want 1", got.V, err)
+	}
+	var notFound *KeyNotFoundError
+	if err := db.GetLatest(uuidFromTS(1500), &got); !errors.As(err, &notFound) {
+		t.Errorf("GetLatest(missing): expected KeyNotFoundError, got %v", err)
+	}
+}
+
+func TestGetLatest_InvalidInput(t *testing.T) {
+	path := setupCreate(t, t.TempDir(), 0)
+	db, err := NewFrozenDB(path, MODE_READ, FinderStrategySimple)
+	if err != nil {
+		t.Fatalf("NewFrozenDB: %v", err)
+	}
+	defer db.Close()
+
+	var got map[string]any
+	var invalid *InvalidInputError
+	if err := db.GetLatest(uuidFromTS(1000), nil); !errors.As(err, &invalid) {
+		t.Errorf("nil value: expected InvalidInputError, got %v", err)
+	}
+	if err := db.GetLatest([16]byte{}, &got); !errors.As(err, &invalid) {
+		t.Errorf("nil key: expected InvalidInputError, got %v", err)
+	}
+}
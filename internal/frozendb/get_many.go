@@ -0,0 +1,111 @@
+package frozendb
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"slices"
+	"sync"
+	"sync/atomic"
+
+	"github.com/google/uuid"
+)
+
+// GetMany returns the raw JSON values of keys, following the same visibility
+// rules as Get. Keys that are not found are left out of the map; any other error
+// stops the lookups and is returned.
+//
+// Parameters:
+//   - keys: UUIDv7 keys to look up (duplicates are looked up once each time they appear)
+//
+// Returns:
+//   - map[uuid.UUID]json.RawMessage: Value of each key found
+//   - error: InvalidInputError (invalid key), ReadError, or CorruptDatabaseError
+//
+// Thread Safety: Safe for concurrent calls on the same FrozenDB instance
+func (db *FrozenDB) GetMany(keys []uuid.UUID) (map[uuid.UUID]json.RawMessage, error) {
+	values := make(map[uuid.UUID]json.RawMessage, len(keys))
+	if err := db.getManyInto(keys, values, nil); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+// GetManyParallel is GetMany with the lookups spread across workers goroutines,
+// for bulk reads on multicore machines. Each lookup is an independent finder
+// traversal and row read: the finders guard their state with a lock held only
+// briefly, and reads through the DBFile do not share a position, so workers do
+// not serialize on each other. The speedup depends on the finder and on how
+// well the storage serves concurrent reads.
+//
+// Parameters:
+//   - keys: UUIDv7 keys to look up
+//   - workers: Number of goroutines (at least 1; more than len(keys) are not started)
+//
+// Returns:
+//   - map[uuid.UUID]json.RawMessage: Value of each key found
+//   - error: InvalidInputError (workers < 1 or an invalid key), ReadError, or
+//     CorruptDatabaseError; the first error stops the remaining lookups
+//
+// Thread Safety: Safe for concurrent calls on the same FrozenDB instance
+func (db *FrozenDB) GetManyParallel(keys []uuid.UUID, workers int) (map[uuid.UUID]json.RawMessage, error) {
+	if workers < 1 {
+		return nil, NewInvalidInputError("workers must be at least 1", nil)
+	}
+	workers = min(workers, len(keys))
+	if workers <= 1 {
+		return db.GetMany(keys)
+	}
+
+	var (
+		wg       sync.WaitGroup
+		stop     atomic.Bool
+		errOnce  sync.Once
+		firstErr error
+		results  = make([]map[uuid.UUID]json.RawMessage, workers)
+	)
+	chunkSize := (len(keys) + workers - 1) / workers
+	for w, chunk := range slices.Collect(slices.Chunk(keys, chunkSize)) {
+		results[w] = make(map[uuid.UUID]json.RawMessage, len(chunk))
+		wg.Add(1)
+		go func(chunk []uuid.UUID, values map[uuid.UUID]json.RawMessage) {
+			defer wg.Done()
+			if err := db.getManyInto(chunk, values, &stop); err != nil {
+				errOnce.Do(func() { firstErr = err })
+				stop.Store(true)
+			}
+		}(chunk, results[w])
+	}
+	wg.Wait()
+	if firstErr != nil {
+		return nil, firstErr
+	}
+
+	values := make(map[uuid.UUID]json.RawMessage, len(keys))
+	for _, partial := range results {
+		for key, value := range partial {
+			values[key] = value
+		}
+	}
+	return values, nil
+}
+
+// getManyInto looks up keys into values, skipping keys that are not found. It
+// returns early without error once stop is set by another worker.
+func (db *FrozenDB) getManyInto(keys []uuid.UUID, values map[uuid.UUID]json.RawMessage, stop *atomic.Bool) error {
+	var buf bytes.Buffer
+	for _, key := range keys {
+		if stop != nil && stop.Load() {
+			return nil
+		}
+		if err := db.GetInto(key, &buf); err != nil {
+			var notFound *KeyNotFoundError
+			if errors.As(err, &notFound) {
+				continue
+			}
+			return err
+		}
+		values[key] = bytes.Clone(buf.Bytes())
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package frozendb
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+// buildGetManyDatabase writes rows committed entries with BuildDatabase and opens
+// the file with strategy. Keys are 1ms apart, so the skew is kept small to
+// stop every lookup from scanning a wide fuzzy window.
+func buildGetManyDatabase(tb testing.TB, rows int, strategy FinderStrategy) (*FrozenDB, []Entry) {
+	tb.Helper()
+	path := filepath.Join(tb.TempDir(), "many.fdb")
+	config := NewCreateConfig(path, 256, 10)
+	entries := make([]Entry, rows)
+	for i := range entries {
+		entries[i] = Entry{Key: uuidFromTS(1000 + i), Value: json.RawMessage(fmt.Sprintf(`{"n":%d}`, i))}
+	}
+	var buf bytes.Buffer
+	if err := BuildDatabase(&buf, config, entries); err != nil {
+		tb.Fatalf("BuildDatabase: %v", err)
+	}
+	if err := os.WriteFile(path, buf.Bytes(), FILE_PERMISSIONS); err != nil {
+		tb.Fatalf("WriteFile: %v", err)
+	}
+	db, err := NewFrozenDB(path, MODE_READ, strategy)
+	if err != nil {
+		tb.Fatalf("NewFrozenDB: %v", err)
+	}
+	tb.Cleanup(func() { _ = db.Close() })
+	return db, entries
+}
+
+func TestGetManyParallel(t *testing.T) {
+	db, entries := buildGetManyDatabase(t, 2000, FinderStrategyBinarySearch)
+
+	keys := make([]uuid.UUID, 0, len(entries)/3+1)
+	for i := 0; i < len(entries); i += 3 {
+		keys = append(keys, entries[i].Key)
+	}
+	missing := uuidFromTS(1)
+	keys = append(keys, missing)
+
+	serial, err := db.GetMany(keys)
+	if err != nil {
+		t.Fatalf("GetMany: %v", err)
+	}
+	for _, workers := range []int{1, 4, 16, len(keys) + 10} {
+		parallel, err := db.GetManyParallel(keys, workers)
+		if err != nil {
+			t.Fatalf("GetManyParallel(%d): %v", workers, err)
+		}
+		if len(parallel) != len(keys)-1 || len(parallel) != len(serial) {
+			t.Fatalf("GetManyParallel(%d) returned %d values, GetMany %d, want %d", workers, len(parallel), len(serial), len(keys)-1)
+		}
+		for key, value := range serial {
+			if !bytes.Equal(parallel[key], value) {
+				t.Errorf("GetManyParallel(%d)[%s] = %s, want %s", workers, key, parallel[key], value)
+			}
+		}
+		if _, ok := parallel[missing]; ok {
+			t.Errorf("GetManyParallel(%d) returned a value for a missing key", workers)
+		}
+	}
+	if got := string(serial[entries[300].Key]); got != `{"n":300}` {
+		t.Errorf("value of entry 300 = %s", got)
+	}
+
+	var invalidInput *InvalidInputError
+	if _, err := db.GetManyParallel(keys, 0); !errors.As(err, &invalidInput) {
+		t.Errorf("0 workers: expected InvalidInputError, got %v", err)
+	}
+	if _, err := db.GetManyParallel(append(keys, uuid.Nil), 4); !errors.As(err, &invalidInput) {
+		t.Errorf("nil key: expected InvalidInputError, got %v", err)
+	}
+	if values, err := db.GetManyParallel(nil, 4); err != nil || len(values) != 0 {
+		t.Errorf("no keys: got %v, %v", values, err)
+	}
+}
+
+func BenchmarkGetMany(b *testing.B) {
+	db, entries := buildGetManyDatabase(b, 20000, FinderStrategyBinarySearch)
+	keys := make([]uuid.UUID, 0, 1000)
+	for i := 0; i < len(entries); i += len(entries) / cap(keys) {
+		keys = append(keys, entries[i].Key)
+	}
+
+	b.Run("serial", func(b *testing.B) {
+		for b.Loop() {
+			if _, err := db.GetMany(keys); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	for _, workers := range []int{2, 4, 8} {
+		b.Run(fmt.Sprintf("parallel_%d", workers), func(b *testing.B) {
+			for b.Loop() {
+				if _, err := db.GetManyParallel(keys, workers); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
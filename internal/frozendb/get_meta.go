@@ -0,0 +1,125 @@
+package frozendb
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TxOutcome describes how the transaction holding a row ended.
+type TxOutcome string
+
+const (
+	TxOutcomeCommitted          TxOutcome = "committed"           // Ended with TC or SC: every row is visible
+	TxOutcomeSavepointCommitted TxOutcome = "savepoint_committed" // Rolled back to savepoint N (R1-R9, S1-S9): rows up to the savepoint are visible
+	TxOutcomeRolledBack         TxOutcome = "rolled_back"         // Fully rolled back (R0, S0): no row is visible, so GetWithMeta never reports it
+	TxOutcomeOpen               TxOutcome = "open"                // Not ended yet: the last transaction in the file is still in progress or was interrupted
+)
+
+// RowMeta describes where and how a value returned by GetWithMeta is stored.
+type RowMeta struct {
+	Index        int64        // Physical row index (checksum rows included)
+	Offset       int64        // Byte offset of the row: HEADER_SIZE + Index*row_size
+	Timestamp    time.Time    // Millisecond timestamp encoded in the key, in UTC
+	StartControl StartControl // Row's start_control: 'T' (starts the transaction) or 'R'
+	EndControl   EndControl   // Row's end_control
+	TxStartIndex int64        // Row index of the transaction's first row
+	TxEndIndex   int64        // Row index of the row that ended the transaction
+	TxEndControl EndControl   // end_control of the row that ended the transaction
+	TxOutcome    TxOutcome    // How the transaction ended
+}
+
+// GetWithMeta returns the raw JSON value of key together with where it is stored
+// and how its transaction ended, following the same visibility rules as Get. It
+// is meant for debugging and audit; use Get or GetInto for ordinary reads.
+//
+// Parameters:
+//   - key: UUIDv7 key to search for (must not be uuid.Nil)
+//
+// Returns:
+//   - json.RawMessage: The stored value
+//   - RowMeta: Location of the row and the outcome of its transaction
+//   - error: InvalidInputError, KeyNotFoundError, ReadError, or CorruptDatabaseError
+//
+// Thread Safety: Safe for concurrent calls on the same FrozenDB instance
+func (db *FrozenDB) GetWithMeta(key uuid.UUID) (json.RawMessage, RowMeta, error) {
+	if key == uuid.Nil {
+		return nil, RowMeta{}, NewInvalidInputError("key cannot be uuid.Nil", nil)
+	}
+
+	db.refresh()
+	row, err := db.locateVisible(key, math.MaxInt64, db.parsedRowControls)
+	if err != nil {
+		return nil, RowMeta{}, err
+	}
+
+	rowBytes, err := db.readRowAtIndex(row.index)
+	if err != nil {
+		return nil, RowMeta{}, err
+	}
+	var ru RowUnion
+	if err := ru.UnmarshalText(rowBytes); err != nil {
+		return nil, RowMeta{}, NewCorruptDatabaseError(fmt.Sprintf("failed to parse row at index %d", row.index), err)
+	}
+	if ru.DataRow == nil || ru.DataRow.GetKey() != key {
+		return nil, RowMeta{}, NewCorruptDatabaseError(fmt.Sprintf("row at index %d does not hold key %s", row.index, key), nil)
+	}
+
+	outcome := TxOutcomeCommitted
+	if row.txEndControl[1] != 'C' {
+		outcome = TxOutcomeSavepointCommitted
+	}
+	meta := RowMeta{
+		Index:        row.index,
+		Offset:       int64(HEADER_SIZE) + row.index*int64(db.header.GetRowSize()),
+		Timestamp:    TimestampOf(key),
+		StartControl: ru.DataRow.StartControl,
+		EndControl:   ru.DataRow.EndControl,
+		TxStartIndex: row.txStart,
+		TxEndIndex:   row.txEnd,
+		TxEndControl: row.txEndControl,
+		TxOutcome:    outcome,
+	}
+	value, err := db.decodeValue(ru.DataRow.GetValue())
+	if err != nil {
+		return nil, RowMeta{}, err
+	}
+	return value, meta, nil
+}
+
+// OffsetOf returns the byte offset of the ROW_START byte of the data row holding
+// key, following the same visibility rules as Get. The offset is the one the
+// finder resolves for Get, HEADER_SIZE + index*row_size, so callers can build an
+// external index of a file and later read the row directly with ReadAt or RowAt
+// (index = (offset - HEADER_SIZE) / row_size). Offsets never change once a row is
+// committed, since the file is append-only.
+//
+// Parameters:
+//   - key: UUIDv7 key to search for (must not be uuid.Nil)
+//
+// Returns:
+//   - int64: Byte offset of the row (0 when not found)
+//   - bool: false when key has no visible committed row
+//   - error: InvalidInputError, ReadError, or CorruptDatabaseError
+//
+// Thread Safety: Safe for concurrent calls on the same FrozenDB instance
+func (db *FrozenDB) OffsetOf(key uuid.UUID) (int64, bool, error) {
+	if key == uuid.Nil {
+		return 0, false, NewInvalidInputError("key cannot be uuid.Nil", nil)
+	}
+
+	db.refresh()
+	row, err := db.locateVisible(key, math.MaxInt64, db.parsedRowControls)
+	if err != nil {
+		var notFound *KeyNotFoundError
+		if errors.As(err, &notFound) {
+			return 0, false, nil
+		}
+		return 0, false, err
+	}
+	return int64(HEADER_SIZE) + row.index*int64(db.header.GetRowSize()), true, nil
+}
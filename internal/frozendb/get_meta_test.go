@@ -0,0 +1,144 @@
+package frozendb
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestGetWithMeta(t *testing.T) {
+	path := setupCreate(t, t.TempDir(), 0)
+
+	// Rows 1-2: committed transaction
+	tx, db := openAndBegin(t, path)
+	mustAdd(t, tx, uuidFromTS(1000), `{"n":1}`)
+	mustAdd(t, tx, uuidFromTS(2000), `{"n":2}`)
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+	db.Close()
+
+	// Rows 3-4: rolled back to the savepoint after row 3
+	tx, db = openAndBegin(t, path)
+	mustAdd(t, tx, uuidFromTS(3000), `{"n":3}`)
+	if err := tx.Savepoint(); err != nil {
+		t.Fatalf("Savepoint: %v", err)
+	}
+	mustAdd(t, tx, uuidFromTS(4000), `{"n":4}`)
+	if err := tx.Rollback(1); err != nil {
+		t.Fatalf("Rollback: %v", err)
+	}
+	db.Close()
+
+	// Row 5: fully rolled back
+	tx, db = openAndBegin(t, path)
+	mustAdd(t, tx, uuidFromTS(5000), `{"n":5}`)
+	if err := tx.Rollback(0); err != nil {
+		t.Fatalf("Rollback: %v", err)
+	}
+	db.Close()
+
+	reader := openForScan(t, path)
+	tests := []struct {
+		ts    int
+		value string
+		meta  RowMeta
+	}{
+		{2000, `{"n":2}`, RowMeta{
+			Index: 2, Offset: HEADER_SIZE + 2*confRowSize, Timestamp: time.UnixMilli(2000).UTC(),
+			StartControl: ROW_CONTINUE, EndControl: TRANSACTION_COMMIT,
+			TxStartIndex: 1, TxEndIndex: 2, TxEndControl: TRANSACTION_COMMIT, TxOutcome: TxOutcomeCommitted,
+		}},
+		{3000, `{"n":3}`, RowMeta{
+			Index: 3, Offset: HEADER_SIZE + 3*confRowSize, Timestamp: time.UnixMilli(3000).UTC(),
+			StartControl: START_TRANSACTION, EndControl: SAVEPOINT_CONTINUE,
+			TxStartIndex: 3, TxEndIndex: 4, TxEndControl: EndControl{'R', '1'}, TxOutcome: TxOutcomeSavepointCommitted,
+		}},
+	}
+	for _, tt := range tests {
+		value, meta, err := reader.GetWithMeta(uuidFromTS(tt.ts))
+		if err != nil {
+			t.Fatalf("GetWithMeta(%d): %v", tt.ts, err)
+		}
+		if string(value) != tt.value {
+			t.Errorf("GetWithMeta(%d) value = %s, want %s", tt.ts, value, tt.value)
+		}
+		if meta != tt.meta {
+			t.Errorf("GetWithMeta(%d) meta = %+v, want %+v", tt.ts, meta, tt.meta)
+		}
+	}
+
+	var notFound *KeyNotFoundError
+	for _, ts := range []int{4000, 5000, 6000} {
+		if _, _, err := reader.GetWithMeta(uuidFromTS(ts)); !errors.As(err, &notFound) {
+			t.Errorf("GetWithMeta(%d): expected KeyNotFoundError, got %v", ts, err)
+		}
+	}
+	var invalidInput *InvalidInputError
+	if _, _, err := reader.GetWithMeta(uuid.Nil); !errors.As(err, &invalidInput) {
+		t.Errorf("GetWithMeta(uuid.Nil): expected InvalidInputError, got %v", err)
+	}
+}
+
+func TestOffsetOf(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "offsets.fdb")
+	createWithInterval(t, path, MIN_CHECKSUM_INTERVAL)
+
+	// Two transactions of 60 rows each cross the checksum row after row 100
+	var keys []uuid.UUID
+	for txn := 0; txn < 2; txn++ {
+		tx, db := openAndBegin(t, path)
+		for i := 0; i < 60; i++ {
+			key := uuidFromTS(1000 + len(keys))
+			mustAdd(t, tx, key, `{"n":1}`)
+			keys = append(keys, key)
+		}
+		if err := tx.Commit(); err != nil {
+			t.Fatalf("Commit: %v", err)
+		}
+		db.Close()
+	}
+	rolledBack := uuidFromTS(5000)
+	tx, db := openAndBegin(t, path)
+	mustAdd(t, tx, rolledBack, `{"n":2}`)
+	if err := tx.Rollback(0); err != nil {
+		t.Fatalf("Rollback: %v", err)
+	}
+	db.Close()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	reader := openForScan(t, path)
+	for i, key := range keys {
+		offset, ok, err := reader.OffsetOf(key)
+		if err != nil || !ok {
+			t.Fatalf("OffsetOf(key %d) = (%d, %v, %v)", i, offset, ok, err)
+		}
+		if (offset-HEADER_SIZE)%confRowSize != 0 || data[offset] != ROW_START {
+			t.Fatalf("OffsetOf(key %d) = %d is not a row boundary", i, offset)
+		}
+		row, err := reader.RowAt((offset - HEADER_SIZE) / confRowSize)
+		if err != nil {
+			t.Fatalf("RowAt: %v", err)
+		}
+		if row.Kind != RowKindData || row.Key != key {
+			t.Fatalf("row at offset %d holds %s %s, want data row %s", offset, row.Kind, row.Key, key)
+		}
+	}
+
+	for _, key := range []uuid.UUID{rolledBack, uuidFromTS(9000)} {
+		if offset, ok, err := reader.OffsetOf(key); err != nil || ok || offset != 0 {
+			t.Errorf("OffsetOf(%s) = (%d, %v, %v), want (0, false, nil)", key, offset, ok, err)
+		}
+	}
+	var invalidInput *InvalidInputError
+	if _, _, err := reader.OffsetOf(uuid.Nil); !errors.As(err, &invalidInput) {
+		t.Errorf("OffsetOf(uuid.Nil): expected InvalidInputError, got %v", err)
+	}
+}
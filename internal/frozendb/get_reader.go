@@ -0,0 +1,75 @@
+package frozendb
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/google/uuid"
+)
+
+// GetReader returns the raw JSON value of key as a stream, following the same
+// visibility rules as Get, so a stored document can be piped to an io.Writer
+// such as an HTTP response with io.Copy instead of being unmarshaled first.
+//
+// Every value is stored within a single row, so the reader holds at most
+// row_size bytes regardless of how the value is consumed. The value is read and
+// validated when GetReader is called, as GetInto does; reading from the returned
+// reader does not touch the file and cannot fail. Close releases the value and
+// always returns nil.
+//
+// Parameters:
+//   - key: UUIDv7 key to search for (must not be uuid.Nil)
+//
+// Returns:
+//   - io.ReadCloser: Reader over the JSON value (decompressed if the database
+//     stores values compressed)
+//   - error: InvalidInputError, KeyNotFoundError, ReadError, or CorruptDatabaseError
+//
+// Thread Safety: Safe for concurrent calls on the same FrozenDB instance; each
+// returned reader must be used by one goroutine at a time
+func (db *FrozenDB) GetReader(key uuid.UUID) (io.ReadCloser, error) {
+	var buf bytes.Buffer
+	if err := db.GetInto(key, &buf); err != nil {
+		return nil, err
+	}
+	return io.NopCloser(bytes.NewReader(buf.Bytes())), nil
+}
+
+// WriteValueTo writes the raw JSON value of key to w, following the same
+// visibility rules as Get. It is the read-and-forward counterpart of GetReader:
+// the value is written straight from the pooled row buffer GetInto reads into,
+// without copying it into a json.RawMessage first. Values of a database with
+// value compression are decompressed before writing, which allocates.
+//
+// Nothing is written unless key has a visible value; w receives the value with a
+// single Write call.
+//
+// Parameters:
+//   - key: UUIDv7 key to search for (must not be uuid.Nil)
+//   - w: Destination of the value (must not be nil)
+//
+// Returns:
+//   - int64: Number of bytes written to w
+//   - error: InvalidInputError, KeyNotFoundError, ReadError, CorruptDatabaseError,
+//     or WriteError wrapping the error returned by w
+//
+// Thread Safety: Safe for concurrent calls on the same FrozenDB instance
+func (db *FrozenDB) WriteValueTo(key uuid.UUID, w io.Writer) (int64, error) {
+	if key == uuid.Nil {
+		return 0, NewInvalidInputError("key cannot be uuid.Nil", nil)
+	}
+	if w == nil {
+		return 0, NewInvalidInputError("w cannot be nil", nil)
+	}
+
+	var written int
+	err := db.withValue(key, func(value []byte) error {
+		var err error
+		written, err = w.Write(value)
+		if err != nil {
+			return NewWriteError("failed to write value", err)
+		}
+		return nil
+	})
+	return int64(written), err
+}
@@ -0,0 +1,88 @@
+package frozendb
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestGetReader(t *testing.T) {
+	rows := []testRow{
+		{rowType: "data", value: `{"doc":"large stored document"}`, startControl: START_TRANSACTION, endControl: TRANSACTION_COMMIT},
+		{rowType: "data", value: `{"n":2}`, startControl: START_TRANSACTION, endControl: FULL_ROLLBACK},
+	}
+	db, keys := openTestDatabaseFile(t, 512, rows, FinderStrategyInMemory)
+
+	r, err := db.GetReader(keys[0])
+	if err != nil {
+		t.Fatalf("GetReader: %v", err)
+	}
+	got, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("ReadAll: %v", err)
+	}
+	if err := r.Close(); err != nil {
+		t.Errorf("Close: %v", err)
+	}
+	var want json.RawMessage
+	if err := db.Get(keys[0], &want); err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if string(got) != string(want) {
+		t.Errorf("GetReader = %q, want %q", got, want)
+	}
+
+	var notFound *KeyNotFoundError
+	if _, err := db.GetReader(keys[1]); !errors.As(err, &notFound) {
+		t.Errorf("rolled back key: expected KeyNotFoundError, got %v", err)
+	}
+	var invalidInput *InvalidInputError
+	if _, err := db.GetReader(uuid.Nil); !errors.As(err, &invalidInput) {
+		t.Errorf("nil key: expected InvalidInputError, got %v", err)
+	}
+}
+
+// failingWriter fails every Write
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) { return 0, errors.New("socket closed") }
+
+func TestWriteValueTo(t *testing.T) {
+	rows := []testRow{
+		{rowType: "data", value: `{"doc":"proxied"}`, startControl: START_TRANSACTION, endControl: SAVEPOINT_CONTINUE},
+		{rowType: "data", value: `{"n":2}`, startControl: ROW_CONTINUE, endControl: EndControl{'R', '1'}},
+	}
+	db, keys := openTestDatabaseFile(t, 512, rows, FinderStrategyInMemory)
+
+	var buf bytes.Buffer
+	n, err := db.WriteValueTo(keys[0], &buf)
+	if err != nil {
+		t.Fatalf("WriteValueTo: %v", err)
+	}
+	if buf.String() != `{"doc":"proxied"}` || n != int64(buf.Len()) {
+		t.Errorf("WriteValueTo wrote %q and returned %d", buf.String(), n)
+	}
+
+	// The row after the savepoint was rolled back
+	var notFound *KeyNotFoundError
+	buf.Reset()
+	if n, err := db.WriteValueTo(keys[1], &buf); !errors.As(err, &notFound) || n != 0 || buf.Len() != 0 {
+		t.Errorf("rolled back key: got %d bytes, %v; want KeyNotFoundError and nothing written", n, err)
+	}
+
+	var writeErr *WriteError
+	if _, err := db.WriteValueTo(keys[0], failingWriter{}); !errors.As(err, &writeErr) {
+		t.Errorf("failing writer: expected WriteError, got %v", err)
+	}
+	var invalidInput *InvalidInputError
+	if _, err := db.WriteValueTo(uuid.Nil, &buf); !errors.As(err, &invalidInput) {
+		t.Errorf("nil key: expected InvalidInputError, got %v", err)
+	}
+	if _, err := db.WriteValueTo(keys[0], nil); !errors.As(err, &invalidInput) {
+		t.Errorf("nil writer: expected InvalidInputError, got %v", err)
+	}
+}
@@ -3,7 +3,11 @@ package frozendb
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"math"
+	"os"
 	"strings"
 )
 
@@ -17,20 +21,53 @@ const (
 	HEADER_NEWLINE   = '\n'
 )
 
-const HEADER_FORMAT = `{"sig":"fDB","ver":1,"row_size":%d,"skew_ms":%d}`
+const (
+	CHECKSUM_INTERVAL     = 10000   // Default rows between checksum rows, used when the header has no "ci" field
+	MIN_CHECKSUM_INTERVAL = 100     // Smallest checksum interval accepted in a header
+	MAX_CHECKSUM_INTERVAL = 1000000 // Largest checksum interval accepted in a header
+)
+
+const HEADER_FORMAT = `{"sig":"fDB","ver":%d,"row_size":%d,"skew_ms":%d}`
+
+const (
+	FORMAT_VERSION          = 1 // Header without a checksum interval or value compression
+	FORMAT_VERSION_EXTENDED = 2 // Header with a "ci" or "vc" field, or an initial checksum row with an extension, which version 1 readers do not know
+)
+
+// HEADER_MAGIC is the prefix every frozenDB header starts with. It is checked
+// before the header is parsed as JSON, so an unrelated file is reported as such
+// rather than as a JSON syntax error.
+const HEADER_MAGIC = `{"sig":"fDB"`
+
+// HEADER_CHECKSUM_INTERVAL_FORMAT is appended inside the header JSON object when the
+// checksum interval differs from CHECKSUM_INTERVAL. The key is abbreviated so the
+// field fits in the fixed 64-byte header.
+const HEADER_CHECKSUM_INTERVAL_FORMAT = `,"ci":%d`
+
+// maxHeaderContentLength is the longest JSON content that leaves room for at least
+// one null terminator and the trailing newline in the 64-byte header.
+const maxHeaderContentLength = HEADER_SIZE - 2
 
 type headerJSON struct {
 	Sig     string `json:"sig"`
 	Ver     int    `json:"ver"`
 	RowSize int    `json:"row_size"`
 	SkewMs  int    `json:"skew_ms"`
+	CI      int    `json:"ci,omitempty"`
+	VC      string `json:"vc,omitempty"`
 }
 
 type Header struct {
-	signature string
-	version   int
-	rowSize   int
-	skewMs    int
+	signature        string
+	version          int
+	rowSize          int
+	skewMs           int
+	checksumInterval int              // 0 means CHECKSUM_INTERVAL
+	valueCompression ValueCompression // "" means ValueCompressionNone
+
+	// Read from the extension of the initial checksum row, not from the 64 header bytes
+	createdAt int64             // Unix milliseconds, 0 means not recorded
+	metadata  map[string]string // nil means no metadata
 }
 
 func (h *Header) GetSignature() string {
@@ -49,6 +86,24 @@ func (h *Header) GetSkewMs() int {
 	return h.skewMs
 }
 
+// GetChecksumInterval returns the number of data and null rows between checksum
+// rows. Headers written without a checksum interval use CHECKSUM_INTERVAL.
+func (h *Header) GetChecksumInterval() int {
+	if h.checksumInterval == 0 {
+		return CHECKSUM_INTERVAL
+	}
+	return h.checksumInterval
+}
+
+// GetValueCompression returns how row values are stored. Headers written without a
+// value compression use ValueCompressionNone.
+func (h *Header) GetValueCompression() ValueCompression {
+	if h.valueCompression == "" {
+		return ValueCompressionNone
+	}
+	return h.valueCompression
+}
+
 func (h *Header) UnmarshalText(headerBytes []byte) error {
 	if len(headerBytes) != HEADER_SIZE {
 		return NewCorruptDatabaseError(
@@ -57,6 +112,13 @@ func (h *Header) UnmarshalText(headerBytes []byte) error {
 		)
 	}
 
+	if !bytes.HasPrefix(headerBytes, []byte(HEADER_MAGIC)) {
+		return NewCorruptDatabaseError(
+			fmt.Sprintf("not a frozenDB file (bad magic): invalid signature, header must start with %s", HEADER_MAGIC),
+			nil,
+		)
+	}
+
 	if headerBytes[63] != HEADER_NEWLINE {
 		return NewCorruptDatabaseError(
 			fmt.Sprintf("byte 63 must be newline, got 0x%02x", headerBytes[63]),
@@ -85,19 +147,114 @@ func (h *Header) UnmarshalText(headerBytes []byte) error {
 		return NewCorruptDatabaseError("failed to parse JSON header", err)
 	}
 
+	if hdr.Sig == HEADER_SIGNATURE && !isSupportedFormatVersion(hdr.Ver) {
+		return newUnsupportedFormatVersionError(hdr.Ver)
+	}
+
 	h.signature = hdr.Sig
 	h.version = hdr.Ver
 	h.rowSize = hdr.RowSize
 	h.skewMs = hdr.SkewMs
+	h.checksumInterval = hdr.CI
+	compression, ok := valueCompressionFromCode(hdr.VC)
+	if !ok {
+		return NewCorruptDatabaseError(fmt.Sprintf("unknown value compression %q in header", hdr.VC), nil)
+	}
+	h.valueCompression = compression
 
-	if err := h.Validate(); err != nil {
+	if err := h.validateFields(); err != nil {
 		return NewCorruptDatabaseError("invalid header values", err)
 	}
 
 	return nil
 }
 
+// ParseHeader parses and validates the 64-byte header at the start of a
+// frozenDB file. The metadata and creation time of a version 2 file are stored
+// in its initial checksum row, which is not part of data, so the returned header
+// has neither: use ReadHeader, or FrozenDB.Metadata and FrozenDB.CreatedAt.
+//
+// Returns:
+//   - *Header: The validated header
+//   - error: CorruptDatabaseError (wrong length, bad magic, malformed JSON, or
+//     invalid values) or UnsupportedVersionError
+func ParseHeader(data []byte) (*Header, error) {
+	header := &Header{}
+	if err := header.UnmarshalText(data); err != nil {
+		return nil, err
+	}
+	return header, nil
+}
+
+// ReadHeader reads and validates the header of the frozenDB file at path. Only
+// the first HEADER_SIZE bytes are read, followed for a version 2 file by the
+// initial checksum row that holds its metadata and creation time: no lock is taken and no finder
+// is built, so it is safe to call while another process holds the database open
+// for writing.
+//
+// Returns:
+//   - *Header: The validated header
+//   - error: PathError (file cannot be opened), ReadError, CorruptDatabaseError
+//     (file shorter than a header, invalid header, or invalid initial checksum
+//     row of a version 2 file), or UnsupportedVersionError
+func ReadHeader(path string) (*Header, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, NewPathError("database file does not exist", err)
+		}
+		if os.IsPermission(err) {
+			return nil, NewPathError("permission denied to access database file", err)
+		}
+		return nil, NewPathError("failed to open database file", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	data := make([]byte, HEADER_SIZE)
+	if _, err := io.ReadFull(file, data); err != nil {
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return nil, NewCorruptDatabaseError(fmt.Sprintf("file is shorter than the %d-byte header", HEADER_SIZE), err)
+		}
+		return nil, NewReadError("failed to read header", err)
+	}
+	header, err := ParseHeader(data)
+	if err != nil || header.GetVersion() == FORMAT_VERSION {
+		return header, err
+	}
+
+	row := make([]byte, header.GetRowSize())
+	if _, err := io.ReadFull(file, row); err != nil {
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return nil, NewCorruptDatabaseError("file is shorter than the header and initial checksum row", err)
+		}
+		return nil, NewReadError("failed to read initial checksum row", err)
+	}
+	if _, err := header.readInitialChecksumRow(row); err != nil {
+		return nil, err
+	}
+	return header, nil
+}
+
+// Validate checks every header field, including that the version is the one
+// formatVersion requires. A version 2 header whose only version 2 content is in
+// the initial checksum row is valid once readInitialChecksumRow has read it.
 func (h *Header) Validate() error {
+	if err := h.validateFields(); err != nil {
+		return err
+	}
+	if h.version != h.formatVersion() {
+		return NewInvalidInputError(
+			fmt.Sprintf("version %d file must declare a checksum interval, value compression, metadata, or creation time", FORMAT_VERSION_EXTENDED),
+			nil,
+		)
+	}
+	return nil
+}
+
+// validateFields checks the header fields that can be checked from the 64 header
+// bytes alone. A version 2 header without a version 2 field passes, since the
+// initial checksum row may hold what makes it version 2.
+func (h *Header) validateFields() error {
 	if h.signature != HEADER_SIGNATURE {
 		return NewInvalidInputError(
 			fmt.Sprintf("invalid signature: expected '%s', got '%s'", HEADER_SIGNATURE, h.signature),
@@ -105,9 +262,9 @@ func (h *Header) Validate() error {
 		)
 	}
 
-	if h.version != 1 {
+	if h.version != FORMAT_VERSION && h.version != FORMAT_VERSION_EXTENDED {
 		return NewInvalidInputError(
-			fmt.Sprintf("unsupported version: expected 1, got %d", h.version),
+			fmt.Sprintf("unsupported version: expected %d or %d, got %d", FORMAT_VERSION, FORMAT_VERSION_EXTENDED, h.version),
 			nil,
 		)
 	}
@@ -126,14 +283,87 @@ func (h *Header) Validate() error {
 		)
 	}
 
+	if h.checksumInterval != 0 {
+		if h.checksumInterval < MIN_CHECKSUM_INTERVAL || h.checksumInterval > MAX_CHECKSUM_INTERVAL {
+			return NewInvalidInputError(
+				fmt.Sprintf("checksum_interval must be between %d and %d, got %d", MIN_CHECKSUM_INTERVAL, MAX_CHECKSUM_INTERVAL, h.checksumInterval),
+				nil,
+			)
+		}
+		// A checksum covers the previous checksum row and the interval after it, read in one call
+		if blockSize := int64(h.checksumInterval+1) * int64(h.rowSize); blockSize > math.MaxInt32 {
+			return NewInvalidInputError(
+				fmt.Sprintf("checksum_interval %d with row_size %d covers %d bytes per checksum, maximum %d", h.checksumInterval, h.rowSize, blockSize, math.MaxInt32),
+				nil,
+			)
+		}
+		if contentLength := len(h.jsonContent()); contentLength > maxHeaderContentLength {
+			return NewInvalidInputError(
+				fmt.Sprintf("checksum_interval %d does not fit in the %d-byte header with row_size %d and skew_ms %d (header content is %d bytes, maximum %d)",
+					h.checksumInterval, HEADER_SIZE, h.rowSize, h.skewMs, contentLength, maxHeaderContentLength),
+				nil,
+			)
+		}
+	}
+
+	if err := validateValueCompression(h.valueCompression); err != nil {
+		return err
+	}
+	if h.GetValueCompression() != ValueCompressionNone {
+		if contentLength := len(h.jsonContent()); contentLength > maxHeaderContentLength {
+			return NewInvalidInputError(
+				fmt.Sprintf("value compression %q does not fit in the %d-byte header with row_size %d and skew_ms %d (header content is %d bytes, maximum %d)",
+					h.valueCompression, HEADER_SIZE, h.rowSize, h.skewMs, contentLength, maxHeaderContentLength),
+				nil,
+			)
+		}
+	}
+
+	if err := h.validateExtension(); err != nil {
+		return err
+	}
+
+	if h.version == FORMAT_VERSION && h.formatVersion() != FORMAT_VERSION {
+		return NewInvalidInputError(
+			fmt.Sprintf("version %d file cannot declare a checksum interval, value compression, metadata, or creation time; they require version %d", FORMAT_VERSION, FORMAT_VERSION_EXTENDED),
+			nil,
+		)
+	}
+
 	return nil
 }
 
+// formatVersion returns the version the header must declare: FORMAT_VERSION_EXTENDED
+// when it carries a field version 1 readers would ignore, so that they reject the
+// file with UnsupportedVersionError instead of misreading its rows.
+func (h *Header) formatVersion() int {
+	if (h.checksumInterval != 0 && h.checksumInterval != CHECKSUM_INTERVAL) || h.GetValueCompression() != ValueCompressionNone ||
+		h.hasExtension() {
+		return FORMAT_VERSION_EXTENDED
+	}
+	return FORMAT_VERSION
+}
+
+// jsonContent returns the header JSON without padding. The checksum interval and
+// value compression are only written when they differ from the defaults, so
+// headers of databases using the defaults are byte-identical to those written
+// before the fields existed, and declare version 1.
+func (h *Header) jsonContent() string {
+	content := fmt.Sprintf(HEADER_FORMAT, h.formatVersion(), h.rowSize, h.skewMs)
+	if h.checksumInterval != 0 && h.checksumInterval != CHECKSUM_INTERVAL {
+		content = content[:len(content)-1] + fmt.Sprintf(HEADER_CHECKSUM_INTERVAL_FORMAT, h.checksumInterval) + "}"
+	}
+	if code, ok := headerCompressionCodes[h.valueCompression]; ok {
+		content = content[:len(content)-1] + fmt.Sprintf(HEADER_VALUE_COMPRESSION_FORMAT, code) + "}"
+	}
+	return content
+}
+
 func (h *Header) MarshalText() ([]byte, error) {
-	jsonContent := fmt.Sprintf(HEADER_FORMAT, h.rowSize, h.skewMs)
+	jsonContent := h.jsonContent()
 
 	contentLength := len(jsonContent)
-	if contentLength > 58 {
+	if contentLength > maxHeaderContentLength {
 		return nil, NewInvalidInputError("header content too long", nil)
 	}
 
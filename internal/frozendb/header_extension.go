@@ -0,0 +1,165 @@
+package frozendb
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"time"
+	"unicode/utf8"
+)
+
+// headerExtension is the JSON object stored after the checksum in the initial
+// checksum row of a version 2 file. It holds what does not fit in the 64-byte
+// header; every field is omitted when unset, and a file without any set has no
+// extension.
+type headerExtension struct {
+	CreatedAt int64             `json:"ca,omitempty"`
+	Metadata  map[string]string `json:"meta,omitempty"`
+}
+
+// GetCreatedAt returns the creation time recorded in the database, in UTC with
+// millisecond precision, or the zero time if none was recorded, which is always
+// the case for version 1 files.
+func (h *Header) GetCreatedAt() time.Time {
+	if h.createdAt == 0 {
+		return time.Time{}
+	}
+	return time.UnixMilli(h.createdAt).UTC()
+}
+
+// GetMetadata returns a copy of the application metadata recorded when the
+// database was created, or nil if none was.
+func (h *Header) GetMetadata() map[string]string {
+	return maps.Clone(h.metadata)
+}
+
+// hasExtension reports whether the header has fields stored in the initial
+// checksum row rather than in the 64 header bytes.
+func (h *Header) hasExtension() bool {
+	return h.createdAt != 0 || len(h.metadata) > 0
+}
+
+// extension returns the JSON object to store after the checksum in the initial
+// checksum row, or nil when the header has no extension fields.
+func (h *Header) extension() ([]byte, error) {
+	if !h.hasExtension() {
+		return nil, nil
+	}
+	extension, err := json.Marshal(headerExtension{CreatedAt: h.createdAt, Metadata: h.metadata})
+	if err != nil {
+		return nil, NewInvalidInputError("failed to encode initial checksum row extension", err)
+	}
+	return extension, nil
+}
+
+// validateExtension checks that the creation time is after the Unix epoch, that
+// every metadata key is non-empty, that keys and values are valid UTF-8 (so they
+// survive the JSON round trip unchanged), and that the encoded extension fits in
+// the initial checksum row.
+func (h *Header) validateExtension() error {
+	if h.createdAt < 0 {
+		return NewInvalidInputError("creation time must be after the Unix epoch", nil)
+	}
+	for key, value := range h.metadata {
+		if key == "" {
+			return NewInvalidInputError("metadata keys cannot be empty", nil)
+		}
+		if !utf8.ValidString(key) || !utf8.ValidString(value) {
+			return NewInvalidInputError(fmt.Sprintf("metadata %q must be valid UTF-8", key), nil)
+		}
+	}
+	extension, err := h.extension()
+	if err != nil {
+		return err
+	}
+	if maxSize := h.rowSize - checksumExtensionOverhead; len(extension) > maxSize {
+		return NewInvalidInputError(
+			fmt.Sprintf("metadata and creation time encode to %d bytes, which does not fit in the initial checksum row with row_size %d (maximum %d)",
+				len(extension), h.rowSize, maxSize),
+			nil,
+		)
+	}
+	return nil
+}
+
+// readInitialChecksumRow parses the initial checksum row of the file the header
+// was read from, sets the fields stored in its extension, and validates the
+// complete header. The caller checks the row's checksum against
+// initialChecksumInput.
+func (h *Header) readInitialChecksumRow(row []byte) (*ChecksumRow, error) {
+	checksumRow := &ChecksumRow{}
+	if err := checksumRow.UnmarshalText(row); err != nil {
+		return nil, NewCorruptDatabaseError("invalid initial checksum row", err)
+	}
+
+	if err := h.applyExtension(checksumRow.Extension); err != nil {
+		return nil, err
+	}
+	if err := h.Validate(); err != nil {
+		return nil, NewCorruptDatabaseError("invalid header values", err)
+	}
+	return checksumRow, nil
+}
+
+// applyExtension sets the header fields stored in extension, the JSON object
+// after the checksum in the initial checksum row. A nil extension sets none.
+func (h *Header) applyExtension(extension []byte) error {
+	if extension == nil {
+		return nil
+	}
+	if h.version == FORMAT_VERSION {
+		return NewCorruptDatabaseError(
+			fmt.Sprintf("version %d file cannot have an initial checksum row extension", FORMAT_VERSION), nil)
+	}
+	var fields headerExtension
+	decoder := json.NewDecoder(bytes.NewReader(extension))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&fields); err != nil {
+		return NewCorruptDatabaseError("invalid initial checksum row extension", err)
+	}
+	if fields.CreatedAt == 0 && len(fields.Metadata) == 0 {
+		return NewCorruptDatabaseError("initial checksum row extension has no fields", nil)
+	}
+	h.createdAt = fields.CreatedAt
+	h.metadata = fields.Metadata
+	return nil
+}
+
+// initialChecksumInput returns the bytes covered by the initial checksum: the
+// 64 header bytes, followed by the extension of the initial checksum row when it
+// has one, so that the extension is as tamper-evident as the header.
+func initialChecksumInput(headerBytes, extension []byte) []byte {
+	return append(headerBytes[:HEADER_SIZE:HEADER_SIZE], extension...)
+}
+
+// newInitialChecksumRow returns the initial checksum row of a file starting with
+// headerBytes, the marshalled header: the row carries the header's extension and
+// a checksum over initialChecksumInput.
+func newInitialChecksumRow(header *Header, headerBytes []byte) (*ChecksumRow, error) {
+	extension, err := header.extension()
+	if err != nil {
+		return nil, err
+	}
+	checksumRow, err := NewChecksumRow(header.GetRowSize(), initialChecksumInput(headerBytes, extension))
+	if err != nil {
+		return nil, err
+	}
+	checksumRow.Extension = extension
+	if err := checksumRow.Validate(); err != nil {
+		return nil, err
+	}
+	return checksumRow, nil
+}
+
+// equal reports whether h and other hold the same values.
+func (h *Header) equal(other *Header) bool {
+	return h.signature == other.signature &&
+		h.version == other.version &&
+		h.rowSize == other.rowSize &&
+		h.skewMs == other.skewMs &&
+		h.checksumInterval == other.checksumInterval &&
+		h.valueCompression == other.valueCompression &&
+		h.createdAt == other.createdAt &&
+		maps.Equal(h.metadata, other.metadata)
+}
@@ -0,0 +1,297 @@
+package frozendb
+
+import (
+	"bytes"
+	"errors"
+	"maps"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// createWithMetadata creates a database at path holding metadata.
+func createWithMetadata(t *testing.T, path string, metadata map[string]string) {
+	t.Helper()
+	setupMockSyscalls(false, false)
+	t.Cleanup(restoreRealSyscalls)
+	t.Setenv("SUDO_USER", MOCK_USER)
+	t.Setenv("SUDO_UID", MOCK_UID)
+	t.Setenv("SUDO_GID", MOCK_GID)
+	config := NewCreateConfig(path, confRowSize, confSkewMs)
+	config.SetMetadata(metadata)
+	if err := Create(config); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+}
+
+func TestMetadata_RoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	metadata := map[string]string{"schema": "3", "source": "billing"}
+	path := filepath.Join(dir, "meta.fdb")
+	createWithMetadata(t, path, metadata)
+
+	header, err := ReadHeader(path)
+	if err != nil {
+		t.Fatalf("ReadHeader: %v", err)
+	}
+	if header.GetVersion() != FORMAT_VERSION_EXTENDED {
+		t.Errorf("GetVersion() = %d, want %d", header.GetVersion(), FORMAT_VERSION_EXTENDED)
+	}
+	if got := header.GetMetadata(); !maps.Equal(got, metadata) {
+		t.Errorf("ReadHeader metadata = %v, want %v", got, metadata)
+	}
+
+	db, err := NewFrozenDB(path, MODE_WRITE, FinderStrategySimple)
+	if err != nil {
+		t.Fatalf("NewFrozenDB: %v", err)
+	}
+	if got := db.Metadata(); !maps.Equal(got, metadata) {
+		t.Errorf("Metadata() = %v, want %v", got, metadata)
+	}
+	db.Metadata()["schema"] = "changed"
+	if got := db.Metadata()["schema"]; got != "3" {
+		t.Errorf("Metadata() returned the database's own map: schema = %q after modifying a copy", got)
+	}
+	if err := db.VerifyHeaderIntegrity(); err != nil {
+		t.Errorf("VerifyHeaderIntegrity: %v", err)
+	}
+	tx, err := db.BeginTx()
+	if err != nil {
+		t.Fatalf("BeginTx: %v", err)
+	}
+	mustAdd(t, tx, uuidFromTS(1000), `{"n":1}`)
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+	db.Close()
+
+	if _, err := Verify(path); err != nil {
+		t.Errorf("Verify: %v", err)
+	}
+
+	// RecomputeChecksums carries the metadata over to the copy
+	repaired := filepath.Join(dir, "repaired.fdb")
+	if err := RecomputeChecksums(path, repaired); err != nil {
+		t.Fatalf("RecomputeChecksums: %v", err)
+	}
+	original, _ := os.ReadFile(path)
+	copied, _ := os.ReadFile(repaired)
+	if !bytes.Equal(original, copied) {
+		t.Error("RecomputeChecksums of an intact file with metadata should write an identical copy")
+	}
+
+	// A database created without metadata has none, and stays version 1
+	plain := filepath.Join(dir, "plain.fdb")
+	createWithMetadata(t, plain, nil)
+	db, err = NewFrozenDB(plain, MODE_READ, FinderStrategySimple)
+	if err != nil {
+		t.Fatalf("NewFrozenDB: %v", err)
+	}
+	defer db.Close()
+	if got := db.Metadata(); got != nil {
+		t.Errorf("Metadata() of a database without metadata = %v, want nil", got)
+	}
+	if header, _ := ReadHeader(plain); header.GetVersion() != FORMAT_VERSION {
+		t.Errorf("GetVersion() without metadata = %d, want %d", header.GetVersion(), FORMAT_VERSION)
+	}
+}
+
+func TestMetadata_BuildDatabase(t *testing.T) {
+	metadata := map[string]string{"schema": "3"}
+	config := NewCreateConfig("unused.fdb", confRowSize, confSkewMs)
+	config.SetMetadata(metadata)
+	var built bytes.Buffer
+	if err := BuildDatabase(&built, config, []Entry{{Key: uuidFromTS(1000), Value: []byte(`{"n":1}`)}}); err != nil {
+		t.Fatalf("BuildDatabase: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "built.fdb")
+	if err := os.WriteFile(path, built.Bytes(), 0644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	db, err := NewFrozenDB(path, MODE_READ, FinderStrategySimple)
+	if err != nil {
+		t.Fatalf("NewFrozenDB: %v", err)
+	}
+	defer db.Close()
+	if got := db.Metadata(); !maps.Equal(got, metadata) {
+		t.Errorf("Metadata() = %v, want %v", got, metadata)
+	}
+}
+
+func TestMetadata_Validation(t *testing.T) {
+	var invalidInput *InvalidInputError
+
+	config := NewCreateConfig(filepath.Join(t.TempDir(), "meta.fdb"), MIN_ROW_SIZE, confSkewMs)
+	config.SetMetadata(map[string]string{"": "value"})
+	if err := config.Validate(); !errors.As(err, &invalidInput) {
+		t.Errorf("empty key: expected InvalidInputError, got %v", err)
+	}
+
+	// {"meta":{"k":"<value>"}} is 17 bytes plus the value and must leave the
+	// checksum row's overhead free
+	fits := MIN_ROW_SIZE - checksumExtensionOverhead - 17
+	config.SetMetadata(map[string]string{"k": strings.Repeat("v", fits)})
+	if err := config.Validate(); err != nil {
+		t.Errorf("metadata filling the initial checksum row: %v", err)
+	}
+	config.SetMetadata(map[string]string{"k": strings.Repeat("v", fits+1)})
+	if err := config.Validate(); !errors.As(err, &invalidInput) {
+		t.Errorf("metadata one byte too long: expected InvalidInputError, got %v", err)
+	}
+
+	// SetMetadata copies the map
+	metadata := map[string]string{"schema": "3"}
+	config.SetMetadata(metadata)
+	metadata["schema"] = "4"
+	if got := config.GetMetadata()["schema"]; got != "3" {
+		t.Errorf("GetMetadata()[schema] = %q after modifying the map passed in, want 3", got)
+	}
+}
+
+func TestMetadata_TamperDetected(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "meta.fdb")
+	createWithMetadata(t, path, map[string]string{"schema": "3"})
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+
+	// Change the metadata and rewrite the row with valid parity, keeping the checksum
+	var checksumRow ChecksumRow
+	if err := checksumRow.UnmarshalText(data[HEADER_SIZE : HEADER_SIZE+confRowSize]); err != nil {
+		t.Fatalf("UnmarshalText: %v", err)
+	}
+	checksumRow.Extension = bytes.Replace(checksumRow.Extension, []byte(`"3"`), []byte(`"4"`), 1)
+	tampered, err := checksumRow.MarshalText()
+	if err != nil {
+		t.Fatalf("MarshalText: %v", err)
+	}
+	copy(data[HEADER_SIZE:], tampered)
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	var corrupt *CorruptDatabaseError
+	if _, err := NewFrozenDB(path, MODE_READ, FinderStrategySimple); !errors.As(err, &corrupt) || !strings.Contains(err.Error(), "CRC32") {
+		t.Errorf("expected CorruptDatabaseError for a CRC32 mismatch, got %v", err)
+	}
+	if _, err := Verify(path); err == nil {
+		t.Error("Verify should report the changed metadata")
+	}
+}
+
+func TestCreatedAt(t *testing.T) {
+	dir := t.TempDir()
+	setupMockSyscalls(false, false)
+	t.Cleanup(restoreRealSyscalls)
+	t.Setenv("SUDO_USER", MOCK_USER)
+	t.Setenv("SUDO_UID", MOCK_UID)
+	t.Setenv("SUDO_GID", MOCK_GID)
+
+	createdAt := time.Date(2026, 3, 14, 15, 9, 26, 535897932, time.FixedZone("UTC+2", 2*3600))
+	path := filepath.Join(dir, "created.fdb")
+	config := NewCreateConfig(path, confRowSize, confSkewMs)
+	config.SetCreatedAt(createdAt)
+	config.SetMetadata(map[string]string{"schema": "3"})
+	if err := Create(config); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	// The time is kept in UTC with millisecond precision
+	want := createdAt.Truncate(time.Millisecond).UTC()
+	header, err := ReadHeader(path)
+	if err != nil {
+		t.Fatalf("ReadHeader: %v", err)
+	}
+	if got := header.GetCreatedAt(); !got.Equal(want) || got.Location() != time.UTC {
+		t.Errorf("GetCreatedAt() = %v, want %v", got, want)
+	}
+	if header.GetVersion() != FORMAT_VERSION_EXTENDED || header.GetMetadata()["schema"] != "3" {
+		t.Errorf("version %d, metadata %v: want version 2 with the metadata", header.GetVersion(), header.GetMetadata())
+	}
+	db, err := NewFrozenDB(path, MODE_READ, FinderStrategySimple)
+	if err != nil {
+		t.Fatalf("NewFrozenDB: %v", err)
+	}
+	if got := db.CreatedAt(); !got.Equal(want) {
+		t.Errorf("CreatedAt() = %v, want %v", got, want)
+	}
+	db.Close()
+	report, err := Verify(path)
+	if err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	if !report.CreatedAt.Equal(want) {
+		t.Errorf("VerifyReport.CreatedAt = %v, want %v", report.CreatedAt, want)
+	}
+
+	// Files without a recorded creation time, including version 1 files, report the zero time
+	plain := filepath.Join(dir, "plain.fdb")
+	if err := Create(NewCreateConfig(plain, confRowSize, confSkewMs)); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	db, err = NewFrozenDB(plain, MODE_READ, FinderStrategySimple)
+	if err != nil {
+		t.Fatalf("NewFrozenDB: %v", err)
+	}
+	defer db.Close()
+	if got := db.CreatedAt(); !got.IsZero() {
+		t.Errorf("CreatedAt() of a version 1 file = %v, want the zero time", got)
+	}
+	if report, err := Verify(plain); err != nil || !report.CreatedAt.IsZero() {
+		t.Errorf("Verify of a version 1 file: CreatedAt %v, error %v", report.CreatedAt, err)
+	}
+
+	var invalidInput *InvalidInputError
+	for _, early := range []time.Time{time.UnixMilli(0), time.UnixMilli(-1)} {
+		config := NewCreateConfig(filepath.Join(dir, "early.fdb"), confRowSize, confSkewMs)
+		config.SetCreatedAt(early)
+		if err := config.Validate(); !errors.As(err, &invalidInput) {
+			t.Errorf("SetCreatedAt(%v): expected InvalidInputError, got %v", early, err)
+		}
+	}
+}
+
+func TestChecksumRow_Extension(t *testing.T) {
+	row, err := NewChecksumRow(MIN_ROW_SIZE, []byte("covered"))
+	if err != nil {
+		t.Fatalf("NewChecksumRow: %v", err)
+	}
+	row.Extension = []byte(`{"meta":{"a":"b"}}`)
+	text, err := row.MarshalText()
+	if err != nil {
+		t.Fatalf("MarshalText: %v", err)
+	}
+	if !bytes.Equal(text[10:10+len(row.Extension)], row.Extension) {
+		t.Errorf("extension not written after the checksum: %q", text)
+	}
+
+	var parsed ChecksumRow
+	if err := parsed.UnmarshalText(text); err != nil {
+		t.Fatalf("UnmarshalText: %v", err)
+	}
+	if parsed.GetChecksum() != row.GetChecksum() || !bytes.Equal(parsed.Extension, row.Extension) {
+		t.Errorf("round trip = %08X %q, want %08X %q", parsed.GetChecksum(), parsed.Extension, row.GetChecksum(), row.Extension)
+	}
+
+	// The largest extension that fits still leaves a byte of padding
+	row.Extension = []byte(`{"a":"` + strings.Repeat("b", MIN_ROW_SIZE-checksumExtensionOverhead-8) + `"}`)
+	text, err = row.MarshalText()
+	if err != nil {
+		t.Fatalf("MarshalText of the largest extension: %v", err)
+	}
+	if err := parsed.UnmarshalText(text); err != nil || !bytes.Equal(parsed.Extension, row.Extension) {
+		t.Errorf("round trip of the largest extension: %v", err)
+	}
+	row.Extension = []byte(`{"a":"` + strings.Repeat("b", MIN_ROW_SIZE-checksumExtensionOverhead-7) + `"}`)
+	if _, err := row.MarshalText(); err == nil {
+		t.Error("MarshalText should reject an extension one byte too long")
+	}
+
+	row.Extension = []byte(`not json`)
+	if _, err := row.MarshalText(); err == nil {
+		t.Error("MarshalText should reject an extension that is not a JSON object")
+	}
+}
@@ -0,0 +1,26 @@
+package frozendb
+
+// VerifyHeaderIntegrity re-reads the header and the initial checksum row from the
+// file and checks that the CRC32 stored in that row still matches the header
+// bytes, and that the header still holds the values read when the database was
+// opened. It detects a header edited in place after creation or while the
+// database is open, which the append-only attribute normally prevents.
+//
+// The same check runs every time a database is opened, so a handle never starts
+// from a tampered header; call this method to re-check a long-lived handle. Only
+// the header is covered: use Verify for the checksums of the rest of the file.
+//
+// Returns:
+//   - error: CorruptDatabaseError if the header or its checksum row is invalid, the
+//     CRC32 does not match, or the header changed since open; ReadError or
+//     TombstonedError if the file cannot be read
+func (db *FrozenDB) VerifyHeaderIntegrity() error {
+	header, err := validateDatabaseFile(db.file)
+	if err != nil {
+		return err
+	}
+	if !header.equal(db.header) {
+		return NewCorruptDatabaseError("header changed since the database was opened", nil)
+	}
+	return nil
+}
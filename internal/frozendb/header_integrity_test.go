@@ -0,0 +1,58 @@
+package frozendb
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestVerifyHeaderIntegrity(t *testing.T) {
+	header := createTestHeader()
+	original := minimalTestDatabaseBytes(t, header)
+	skewAt := bytes.Index(original, []byte(`"skew_ms":5000`)) + len(`"skew_ms":`)
+	if skewAt < len(`"skew_ms":`) {
+		t.Fatalf("skew_ms not found in header %q", original[:HEADER_SIZE])
+	}
+
+	open := func(t *testing.T) (*FrozenDB, *MemFileManager) {
+		t.Helper()
+		mem := NewMemFileManager(original, MODE_READ)
+		db, err := NewFrozenDBWithFile(mem, FinderStrategySimple)
+		if err != nil {
+			t.Fatalf("NewFrozenDBWithFile: %v", err)
+		}
+		if err := db.VerifyHeaderIntegrity(); err != nil {
+			t.Fatalf("VerifyHeaderIntegrity on an untouched file: %v", err)
+		}
+		return db, mem
+	}
+
+	t.Run("header edited in place", func(t *testing.T) {
+		db, mem := open(t)
+		mem.data[skewAt] = '6'
+		var corrupt *CorruptDatabaseError
+		if err := db.VerifyHeaderIntegrity(); !errors.As(err, &corrupt) {
+			t.Errorf("expected CorruptDatabaseError, got %v", err)
+		}
+	})
+
+	t.Run("header and checksum rewritten", func(t *testing.T) {
+		db, mem := open(t)
+		mem.data[skewAt] = '6'
+		row, err := NewChecksumRow(header.GetRowSize(), mem.data[:HEADER_SIZE])
+		if err != nil {
+			t.Fatalf("NewChecksumRow: %v", err)
+		}
+		rowBytes, err := row.MarshalText()
+		if err != nil {
+			t.Fatalf("MarshalText: %v", err)
+		}
+		// The CRC now matches, but the handle was opened with the old header
+		copy(mem.data[HEADER_SIZE:], rowBytes)
+
+		var corrupt *CorruptDatabaseError
+		if err := db.VerifyHeaderIntegrity(); !errors.As(err, &corrupt) {
+			t.Errorf("expected CorruptDatabaseError, got %v", err)
+		}
+	})
+}
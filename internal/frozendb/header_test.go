@@ -0,0 +1,224 @@
+package frozendb
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestHeader_ChecksumIntervalRoundTrip(t *testing.T) {
+	// The default interval is not written, keeping existing headers byte-identical
+	defaultHeader := &Header{signature: HEADER_SIGNATURE, version: 1, rowSize: 1024, skewMs: 5000, checksumInterval: CHECKSUM_INTERVAL}
+	text, err := defaultHeader.MarshalText()
+	if err != nil {
+		t.Fatalf("MarshalText: %v", err)
+	}
+	if bytes.Contains(text, []byte(`"ci"`)) {
+		t.Errorf("default interval should be omitted, got %q", text)
+	}
+
+	h := &Header{signature: HEADER_SIGNATURE, version: FORMAT_VERSION_EXTENDED, rowSize: 1024, skewMs: 5000, checksumInterval: 500}
+	text, err = h.MarshalText()
+	if err != nil {
+		t.Fatalf("MarshalText: %v", err)
+	}
+	if !bytes.HasPrefix(text, []byte(`{"sig":"fDB","ver":2,"row_size":1024,"skew_ms":5000,"ci":500}`)) {
+		t.Errorf("unexpected header %q", text)
+	}
+	parsed := &Header{}
+	if err := parsed.UnmarshalText(text); err != nil {
+		t.Fatalf("UnmarshalText: %v", err)
+	}
+	if parsed.GetChecksumInterval() != 500 {
+		t.Errorf("GetChecksumInterval() = %d, want 500", parsed.GetChecksumInterval())
+	}
+
+	// Headers without the field use the default
+	legacy := &Header{}
+	if err := legacy.UnmarshalText(paddedHeader(`{"sig":"fDB","ver":1,"row_size":1024,"skew_ms":5000}`)); err != nil {
+		t.Fatalf("UnmarshalText: %v", err)
+	}
+	if legacy.GetChecksumInterval() != CHECKSUM_INTERVAL {
+		t.Errorf("GetChecksumInterval() = %d, want %d", legacy.GetChecksumInterval(), CHECKSUM_INTERVAL)
+	}
+}
+
+func TestHeader_BadMagic(t *testing.T) {
+	unrelated := make([]byte, HEADER_SIZE)
+	copy(unrelated, "#!/bin/sh\necho this is not a database\n")
+	unrelated[HEADER_SIZE-1] = HEADER_NEWLINE
+
+	tests := map[string][]byte{
+		"unrelated file":  unrelated,
+		"other signature": paddedHeader(`{"sig":"xDB","ver":1,"row_size":1024,"skew_ms":5000}`),
+		"no newline":      bytes.Repeat([]byte{'x'}, HEADER_SIZE),
+	}
+	for name, headerBytes := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := (&Header{}).UnmarshalText(headerBytes)
+			var corrupt *CorruptDatabaseError
+			if !errors.As(err, &corrupt) {
+				t.Fatalf("expected CorruptDatabaseError, got %v", err)
+			}
+			if !strings.Contains(err.Error(), "not a frozenDB file (bad magic)") {
+				t.Errorf("expected bad magic error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestReadHeader(t *testing.T) {
+	dir := t.TempDir()
+	path := setupCreate(t, dir, 5000)
+
+	// A writer holding the lock does not block ReadHeader
+	db, err := NewFrozenDB(path, MODE_WRITE, FinderStrategySimple)
+	if err != nil {
+		t.Fatalf("NewFrozenDB: %v", err)
+	}
+	defer db.Close()
+
+	header, err := ReadHeader(path)
+	if err != nil {
+		t.Fatalf("ReadHeader: %v", err)
+	}
+	if header.GetVersion() != 1 || header.GetSkewMs() != 5000 || header.GetRowSize() < MIN_ROW_SIZE {
+		t.Errorf("unexpected header: version %d, row_size %d, skew_ms %d", header.GetVersion(), header.GetRowSize(), header.GetSkewMs())
+	}
+
+	parsed, err := ParseHeader(paddedHeader(`{"sig":"fDB","ver":1,"row_size":1024,"skew_ms":250}`))
+	if err != nil {
+		t.Fatalf("ParseHeader: %v", err)
+	}
+	if parsed.GetRowSize() != 1024 || parsed.GetSkewMs() != 250 {
+		t.Errorf("ParseHeader: row_size %d, skew_ms %d; want 1024, 250", parsed.GetRowSize(), parsed.GetSkewMs())
+	}
+
+	var pathErr *PathError
+	if _, err := ReadHeader(filepath.Join(dir, "missing.fdb")); !errors.As(err, &pathErr) {
+		t.Errorf("missing file: expected PathError, got %v", err)
+	}
+	short := filepath.Join(dir, "short.fdb")
+	if err := os.WriteFile(short, []byte(HEADER_MAGIC), 0644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	var corrupt *CorruptDatabaseError
+	if _, err := ReadHeader(short); !errors.As(err, &corrupt) {
+		t.Errorf("short file: expected CorruptDatabaseError, got %v", err)
+	}
+}
+
+func TestHeader_ChecksumIntervalValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		header  Header
+		wantErr string
+	}{
+		{name: "below minimum", header: Header{rowSize: 1024, skewMs: 5000, checksumInterval: MIN_CHECKSUM_INTERVAL - 1}, wantErr: "between"},
+		{name: "above maximum", header: Header{rowSize: 128, skewMs: 0, checksumInterval: MAX_CHECKSUM_INTERVAL + 1}, wantErr: "between"},
+		{name: "does not fit", header: Header{rowSize: 65536, skewMs: 86400000, checksumInterval: 100}, wantErr: "does not fit"},
+		{name: "block too large", header: Header{rowSize: 65536, skewMs: 0, checksumInterval: 99999}, wantErr: "bytes per checksum"},
+		{name: "minimum", header: Header{rowSize: 4096, skewMs: 5000, checksumInterval: MIN_CHECKSUM_INTERVAL}},
+		{name: "maximum", header: Header{rowSize: 128, skewMs: 0, checksumInterval: MAX_CHECKSUM_INTERVAL}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.header.signature = HEADER_SIGNATURE
+			tt.header.version = tt.header.formatVersion()
+			err := tt.header.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Validate: %v", err)
+				}
+				if _, err := tt.header.MarshalText(); err != nil {
+					t.Fatalf("MarshalText: %v", err)
+				}
+				return
+			}
+			if _, ok := err.(*InvalidInputError); !ok || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected InvalidInputError containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestChecksumInterval_WriterFindersAndVerify(t *testing.T) {
+	const interval = MIN_CHECKSUM_INTERVAL
+	path := filepath.Join(t.TempDir(), "interval.fdb")
+	setupMockSyscalls(false, false)
+	t.Cleanup(restoreRealSyscalls)
+	t.Setenv("SUDO_USER", MOCK_USER)
+	t.Setenv("SUDO_UID", MOCK_UID)
+	t.Setenv("SUDO_GID", MOCK_GID)
+	config := NewCreateConfig(path, confRowSize, confSkewMs)
+	config.SetChecksumInterval(interval)
+	if err := Create(config); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	db, err := NewFrozenDB(path, MODE_WRITE, FinderStrategySimple)
+	if err != nil {
+		t.Fatalf("NewFrozenDB: %v", err)
+	}
+	// Two and a half intervals of rows, in transactions of 50
+	total := interval*5/2 + 1
+	for start := 0; start < total; start += 50 {
+		tx, err := db.BeginTx()
+		if err != nil {
+			t.Fatalf("BeginTx: %v", err)
+		}
+		for i := start; i < start+50 && i < total; i++ {
+			mustAdd(t, tx, uuidFromTS(1000+i), `{"i":1}`)
+		}
+		if err := tx.Commit(); err != nil {
+			t.Fatalf("Commit: %v", err)
+		}
+	}
+	if err := db.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	if _, err := Verify(path); err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+
+	for _, strategy := range []FinderStrategy{FinderStrategySimple, FinderStrategyInMemory, FinderStrategyBinarySearch} {
+		t.Run(string(strategy), func(t *testing.T) {
+			db, err := NewFrozenDB(path, MODE_READ, strategy)
+			if err != nil {
+				t.Fatalf("NewFrozenDB: %v", err)
+			}
+			defer db.Close()
+
+			// Checksum rows sit at physical index k*(interval+1)
+			for k := int64(0); k <= 2; k++ {
+				rowBytes, err := db.readRowAtIndex(k * (interval + 1))
+				if err != nil {
+					t.Fatalf("readRowAtIndex: %v", err)
+				}
+				var ru RowUnion
+				if err := ru.UnmarshalText(rowBytes); err != nil || ru.ChecksumRow == nil {
+					t.Fatalf("expected checksum row at index %d (err %v)", k*(interval+1), err)
+				}
+			}
+			for _, i := range []int{0, interval - 1, interval, 2 * interval, total - 1} {
+				var value json.RawMessage
+				if err := db.Get(uuidFromTS(1000+i), &value); err != nil {
+					t.Errorf("Get(row %d): %v", i, err)
+				}
+			}
+		})
+	}
+}
+
+// paddedHeader returns content as a 64-byte header with null padding and trailing newline.
+func paddedHeader(content string) []byte {
+	header := make([]byte, HEADER_SIZE)
+	copy(header, content)
+	header[HEADER_SIZE-1] = HEADER_NEWLINE
+	return header
+}
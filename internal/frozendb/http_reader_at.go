@@ -0,0 +1,203 @@
+package frozendb
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	DEFAULT_HTTP_READ_AHEAD    = 64 << 10               // Default bytes fetched per range request (64 KiB)
+	DEFAULT_HTTP_MAX_RETRIES   = 3                      // Default retries of a request after a transient failure
+	DEFAULT_HTTP_RETRY_BACKOFF = 100 * time.Millisecond // Default delay before the first retry, doubled for each later one
+)
+
+// HTTPReaderAt is an io.ReaderAt over a file served by HTTP, typically a
+// database hosted on a CDN or object store, for NewFrozenDBFromReaderAt. Each
+// ReadAt that misses its buffer becomes one Range request, and the request is
+// extended to the read-ahead size so that reading neighboring rows, as binary
+// search and scans do, does not cost a request per row.
+//
+// Requests that fail with a network error, 429, or a 5xx status are retried
+// with exponential backoff. The server must answer range requests with 206
+// Partial Content. The file must not change while it is read.
+//
+// HTTPReaderAt is safe for concurrent use; reads are serialized.
+type HTTPReaderAt struct {
+	url        string
+	client     *http.Client
+	size       int64
+	readAhead  int
+	maxRetries int
+	backoff    time.Duration
+
+	mu       sync.Mutex
+	buf      []byte // Bytes of the last range fetched
+	bufStart int64  // Offset of buf in the file
+}
+
+// NewHTTPReaderAt returns an HTTPReaderAt for url, learning the file size with a
+// one-byte range request. A nil client uses http.DefaultClient.
+//
+// Returns:
+//   - *HTTPReaderAt: Reader using DEFAULT_HTTP_READ_AHEAD and DEFAULT_HTTP_MAX_RETRIES
+//   - error: InvalidInputError (empty url), or ReadError if the request fails or
+//     the server does not support range requests
+func NewHTTPReaderAt(url string, client *http.Client) (*HTTPReaderAt, error) {
+	if url == "" {
+		return nil, NewInvalidInputError("url cannot be empty", nil)
+	}
+	if client == nil {
+		client = http.DefaultClient
+	}
+	h := &HTTPReaderAt{
+		url:        url,
+		client:     client,
+		readAhead:  DEFAULT_HTTP_READ_AHEAD,
+		maxRetries: DEFAULT_HTTP_MAX_RETRIES,
+		backoff:    DEFAULT_HTTP_RETRY_BACKOFF,
+	}
+	_, size, err := h.fetch(0, 1)
+	if err != nil {
+		return nil, err
+	}
+	h.size = size
+	return h, nil
+}
+
+// SetReadAhead sets the least number of bytes each range request fetches.
+// Returns InvalidInputError if bytes is not positive.
+func (h *HTTPReaderAt) SetReadAhead(bytes int) error {
+	if bytes <= 0 {
+		return NewInvalidInputError(fmt.Sprintf("read-ahead must be positive, got %d", bytes), nil)
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.readAhead = bytes
+	return nil
+}
+
+// SetRetries sets how many times a request is retried after a transient
+// failure, and the delay before the first retry; each later retry waits twice as
+// long. Returns InvalidInputError if either is negative.
+func (h *HTTPReaderAt) SetRetries(maxRetries int, backoff time.Duration) error {
+	if maxRetries < 0 || backoff < 0 {
+		return NewInvalidInputError("retries and backoff must be non-negative", nil)
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.maxRetries = maxRetries
+	h.backoff = backoff
+	return nil
+}
+
+// Size returns the size of the file in bytes, as reported by the server when the
+// reader was created.
+func (h *HTTPReaderAt) Size() int64 {
+	return h.size
+}
+
+// ReadAt implements io.ReaderAt. It returns io.EOF when the read reaches past the
+// end of the file, and ReadError when a request fails.
+func (h *HTTPReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	if off < 0 {
+		return 0, NewInvalidInputError("offset cannot be negative", nil)
+	}
+	if off >= h.size {
+		return 0, io.EOF
+	}
+	end := min(off+int64(len(p)), h.size)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if off < h.bufStart || end > h.bufStart+int64(len(h.buf)) {
+		fetchEnd := min(off+max(int64(len(p)), int64(h.readAhead)), h.size)
+		data, _, err := h.fetch(off, fetchEnd-off)
+		if err != nil {
+			return 0, err
+		}
+		h.buf, h.bufStart = data, off
+	}
+	n := copy(p, h.buf[off-h.bufStart:end-h.bufStart])
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+// Close releases idle connections of the client.
+func (h *HTTPReaderAt) Close() error {
+	h.client.CloseIdleConnections()
+	return nil
+}
+
+// fetch requests length bytes at off, retrying transient failures, and returns
+// them with the total file size from the Content-Range header.
+func (h *HTTPReaderAt) fetch(off, length int64) ([]byte, int64, error) {
+	delay := h.backoff
+	for attempt := 0; ; attempt++ {
+		data, size, transient, err := h.fetchOnce(off, length)
+		if err == nil {
+			return data, size, nil
+		}
+		if !transient || attempt >= h.maxRetries {
+			return nil, 0, NewReadError(fmt.Sprintf("range request for %d bytes at offset %d failed", length, off), err)
+		}
+		time.Sleep(delay)
+		delay *= 2
+	}
+}
+
+// fetchOnce makes one range request, reporting whether a failure may succeed
+// when retried.
+func (h *HTTPReaderAt) fetchOnce(off, length int64) (data []byte, size int64, transient bool, err error) {
+	req, err := http.NewRequest(http.MethodGet, h.url, nil)
+	if err != nil {
+		return nil, 0, false, err
+	}
+	req.Header.Set("Range", fmt.Sprintf("bytes=%d-%d", off, off+length-1))
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return nil, 0, true, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	switch {
+	case resp.StatusCode == http.StatusPartialContent:
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return nil, 0, true, fmt.Errorf("server returned %s", resp.Status)
+	case resp.StatusCode == http.StatusOK:
+		return nil, 0, false, errors.New("server does not support range requests")
+	default:
+		return nil, 0, false, fmt.Errorf("server returned %s", resp.Status)
+	}
+
+	size, err = parseContentRangeSize(resp.Header.Get("Content-Range"))
+	if err != nil {
+		return nil, 0, false, err
+	}
+	data = make([]byte, min(length, max(size-off, 0)))
+	if _, err := io.ReadFull(resp.Body, data); err != nil {
+		return nil, 0, true, err
+	}
+	return data, size, false, nil
+}
+
+// parseContentRangeSize returns the complete length from a Content-Range header
+// of the form "bytes start-end/size".
+func parseContentRangeSize(contentRange string) (int64, error) {
+	_, total, ok := strings.Cut(contentRange, "/")
+	if !ok || !strings.HasPrefix(contentRange, "bytes ") {
+		return 0, fmt.Errorf("invalid Content-Range %q", contentRange)
+	}
+	size, err := strconv.ParseInt(total, 10, 64)
+	if err != nil || size < 0 {
+		return 0, fmt.Errorf("Content-Range %q has no file size", contentRange)
+	}
+	return size, nil
+}
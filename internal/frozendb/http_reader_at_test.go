@@ -0,0 +1,125 @@
+package frozendb
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// rangeServer serves data with range support, failing the first failures
+// requests with 503 and counting all of them.
+func rangeServer(t *testing.T, data []byte, failures int64) (*httptest.Server, *atomic.Int64) {
+	t.Helper()
+	var requests atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) <= failures {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		http.ServeContent(w, r, "db.fdb", time.Time{}, bytes.NewReader(data))
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+func TestHTTPReaderAt_QueriesRemoteDatabase(t *testing.T) {
+	config := NewCreateConfig("remote.fdb", confRowSize, confSkewMs)
+	config.SetChecksumInterval(MIN_CHECKSUM_INTERVAL)
+	entries := make([]Entry, 500)
+	for i := range entries {
+		entries[i] = Entry{Key: uuidFromTS(1000 + i), Value: json.RawMessage(fmt.Sprintf(`{"n":%d}`, i))}
+	}
+	var buf bytes.Buffer
+	if err := BuildDatabase(&buf, config, entries); err != nil {
+		t.Fatalf("BuildDatabase: %v", err)
+	}
+
+	// Transient failures are retried, including while learning the size
+	server, requests := rangeServer(t, buf.Bytes(), 2)
+	remote, err := NewHTTPReaderAt(server.URL, server.Client())
+	if err != nil {
+		t.Fatalf("NewHTTPReaderAt: %v", err)
+	}
+	if err := remote.SetRetries(3, time.Millisecond); err != nil {
+		t.Fatalf("SetRetries: %v", err)
+	}
+	if remote.Size() != int64(buf.Len()) {
+		t.Fatalf("Size() = %d, want %d", remote.Size(), buf.Len())
+	}
+
+	db, err := NewFrozenDBFromReaderAt(remote, remote.Size(), FinderStrategyBinarySearch)
+	if err != nil {
+		t.Fatalf("NewFrozenDBFromReaderAt: %v", err)
+	}
+	defer db.Close()
+
+	lookups := func() int64 {
+		before := requests.Load()
+		for _, i := range []int{0, 123, 250, 499} {
+			var value struct{ N int }
+			if err := db.Get(entries[i].Key, &value); err != nil || value.N != i {
+				t.Errorf("Get(entry %d) = %+v, %v", i, value, err)
+			}
+		}
+		return requests.Load() - before
+	}
+	withReadAhead := lookups()
+
+	// Without read-ahead every row read is its own request
+	if err := remote.SetReadAhead(1); err != nil {
+		t.Fatalf("SetReadAhead: %v", err)
+	}
+	withoutReadAhead := lookups()
+	if withReadAhead >= withoutReadAhead {
+		t.Errorf("read-ahead made %d requests, without it %d", withReadAhead, withoutReadAhead)
+	}
+
+	if _, err := db.BeginTx(); err == nil {
+		t.Error("BeginTx on a remote database should fail")
+	}
+}
+
+func TestHTTPReaderAt_Errors(t *testing.T) {
+	data := []byte("0123456789")
+
+	var readErr *ReadError
+	server, _ := rangeServer(t, data, 100)
+	remote, err := NewHTTPReaderAt(server.URL, server.Client())
+	if !errors.As(err, &readErr) {
+		t.Errorf("persistent 503: expected ReadError, got %v", err)
+	}
+	if remote != nil {
+		t.Errorf("expected no reader on error")
+	}
+
+	noRange := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(data)
+	}))
+	defer noRange.Close()
+	if _, err := NewHTTPReaderAt(noRange.URL, noRange.Client()); !errors.As(err, &readErr) {
+		t.Errorf("no range support: expected ReadError, got %v", err)
+	}
+
+	server, _ = rangeServer(t, data, 0)
+	remote, err = NewHTTPReaderAt(server.URL, server.Client())
+	if err != nil {
+		t.Fatalf("NewHTTPReaderAt: %v", err)
+	}
+	p := make([]byte, 4)
+	if n, err := remote.ReadAt(p, 8); n != 2 || err == nil || string(p[:n]) != "89" {
+		t.Errorf("ReadAt past the end = %d %q, %v; want 2 \"89\", io.EOF", n, p[:n], err)
+	}
+	var invalidInput *InvalidInputError
+	if _, err := NewHTTPReaderAt("", nil); !errors.As(err, &invalidInput) {
+		t.Errorf("empty url: expected InvalidInputError, got %v", err)
+	}
+	if _, err := NewFrozenDBFromReaderAt(nil, 0, FinderStrategySimple); !errors.As(err, &invalidInput) {
+		t.Errorf("nil reader: expected InvalidInputError, got %v", err)
+	}
+}
@@ -0,0 +1,84 @@
+package frozendb
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// InferSchema samples up to sampleSize committed values, in ascending key order
+// and following the same visibility rules as Scan, and reports the JSON type of
+// each top-level field seen in them: "string", "number", "bool", "object",
+// "array", or "null". A field whose type varies across the sample maps to its
+// types sorted and joined by "|", such as "null|string". Values that are not JSON
+// objects are skipped but count toward sampleSize, so the result describes the
+// shape of the first rows rather than reading the whole database.
+//
+// Parameters:
+//   - sampleSize: Maximum number of committed values to read (must be positive)
+//
+// Returns:
+//   - map[string]string: Inferred type of each field; empty if no object was sampled
+//   - error: InvalidInputError (sampleSize not positive), InvalidDataError (a
+//     sampled object is not valid JSON), ReadError, or CorruptDatabaseError
+//
+// Thread Safety: Safe for concurrent calls on the same FrozenDB instance
+func (db *FrozenDB) InferSchema(sampleSize int) (map[string]string, error) {
+	if sampleSize <= 0 {
+		return nil, NewInvalidInputError(fmt.Sprintf("sampleSize must be positive, got %d", sampleSize), nil)
+	}
+
+	fieldTypes := make(map[string][]string)
+	sampled := 0
+	var decodeErr error
+	err := db.Scan(func(key uuid.UUID, value json.RawMessage) bool {
+		sampled++
+		if firstJSONByte(value) == '{' {
+			var object map[string]json.RawMessage
+			if err := json.Unmarshal(value, &object); err != nil {
+				decodeErr = NewInvalidDataError(fmt.Sprintf("failed to decode JSON object of key %s", key), err)
+				return false
+			}
+			for field, fieldValue := range object {
+				if t := rawJSONType(fieldValue); !slices.Contains(fieldTypes[field], t) {
+					fieldTypes[field] = append(fieldTypes[field], t)
+				}
+			}
+		}
+		return sampled < sampleSize
+	})
+	if err != nil {
+		return nil, err
+	}
+	if decodeErr != nil {
+		return nil, decodeErr
+	}
+
+	schema := make(map[string]string, len(fieldTypes))
+	for field, types := range fieldTypes {
+		slices.Sort(types)
+		schema[field] = strings.Join(types, "|")
+	}
+	return schema, nil
+}
+
+// rawJSONType returns the JSON type of a valid encoded JSON value.
+func rawJSONType(value json.RawMessage) string {
+	switch firstJSONByte(value) {
+	case '"':
+		return "string"
+	case '{':
+		return "object"
+	case '[':
+		return "array"
+	case 't', 'f':
+		return "bool"
+	case 'n':
+		return "null"
+	default:
+		return "number"
+	}
+}
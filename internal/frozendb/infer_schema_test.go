@@ -0,0 +1,60 @@
+package frozendb
+
+import (
+	"encoding/json"
+	"errors"
+	"maps"
+	"testing"
+)
+
+func TestInferSchema(t *testing.T) {
+	path := setupCreate(t, t.TempDir(), 5000)
+	writeTx(t, path, false, func(tx *Transaction) error {
+		for i, value := range []string{
+			`{"name":"a","age":1,"tags":["x"],"meta":{"k":1},"ok":true,"note":null}`,
+			`[1,2,3]`,
+			`"not an object"`,
+			`{"name":"b","age":"unknown","ok":false}`,
+		} {
+			if err := tx.AddRow(uuidFromTS(1000+i), json.RawMessage(value)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	// Rolled back values are not sampled
+	writeTx(t, path, true, func(tx *Transaction) error {
+		return tx.AddRow(uuidFromTS(2000), json.RawMessage(`{"hidden":1}`))
+	})
+	db := openForScan(t, path)
+
+	schema, err := db.InferSchema(100)
+	if err != nil {
+		t.Fatalf("InferSchema: %v", err)
+	}
+	want := map[string]string{
+		"name": "string",
+		"age":  "number|string",
+		"tags": "array",
+		"meta": "object",
+		"ok":   "bool",
+		"note": "null",
+	}
+	if !maps.Equal(schema, want) {
+		t.Errorf("InferSchema(100) = %v, want %v", schema, want)
+	}
+
+	// The sample stops after the non-object values following the first row
+	schema, err = db.InferSchema(3)
+	if err != nil {
+		t.Fatalf("InferSchema: %v", err)
+	}
+	if schema["age"] != "number" || len(schema) != 6 {
+		t.Errorf("InferSchema(3) = %v, want only the first row's fields", schema)
+	}
+
+	var invalidInput *InvalidInputError
+	if _, err := db.InferSchema(0); !errors.As(err, &invalidInput) {
+		t.Errorf("InferSchema(0): expected InvalidInputError, got %v", err)
+	}
+}
@@ -15,15 +15,21 @@ var _ Finder = (*InMemoryFinder)(nil)
 // fixed memory when DB is large.
 type InMemoryFinder struct {
 	uuidIndex        map[uuid.UUID]int64
+	repeats          map[uuid.UUID][]int64 // Later occurrences of keys written more than once
 	transactionStart map[int64]int64
 	transactionEnd   map[int64]int64
+	checksumRows     map[int64]struct{}
+	interrupted      map[int64]int64 // Rows of an unterminated transaction, mapped to the row that started the next one
 	mu               sync.RWMutex
 	dbFile           DBFile
 	rowSize          int32
 	size             int64
 	lastTxStart      int64
 	maxTimestamp     int64
-	tombstonedErr    error // Error that caused this Finder to be tombstoned (nil if not tombstoned)
+	tombstonedErr    error  // Error that caused this Finder to be tombstoned (nil if not tombstoned)
+	budgetRows       int64  // Rows the memory budget covers (0 if no budget is set)
+	budgetBytes      int64  // Memory budget, for the warning once budgetRows is passed
+	logger           Logger // Receives the budget warning (nil discards it)
 }
 
 // NewInMemoryFinder builds an InMemoryFinder by scanning the database and
@@ -32,8 +38,8 @@ func NewInMemoryFinder(dbFile DBFile, rowSize int32, rowEmitter *RowEmitter) (*I
 	if dbFile == nil {
 		return nil, NewInvalidInputError("dbFile cannot be nil", nil)
 	}
-	if rowSize < 128 || rowSize > 65536 {
-		return nil, NewInvalidInputError(fmt.Sprintf("rowSize must be between 128 and 65536, got %d", rowSize), nil)
+	if rowSize < MIN_ROW_SIZE || rowSize > MAX_ROW_SIZE {
+		return nil, NewInvalidInputError(fmt.Sprintf("rowSize must be between %d and %d, got %d", MIN_ROW_SIZE, MAX_ROW_SIZE, rowSize), nil)
 	}
 	if rowEmitter == nil {
 		return nil, NewInvalidInputError("rowEmitter cannot be nil", nil)
@@ -41,8 +47,11 @@ func NewInMemoryFinder(dbFile DBFile, rowSize int32, rowEmitter *RowEmitter) (*I
 	size := dbFile.Size()
 	imf := &InMemoryFinder{
 		uuidIndex:        make(map[uuid.UUID]int64),
+		repeats:          make(map[uuid.UUID][]int64),
 		transactionStart: make(map[int64]int64),
 		transactionEnd:   make(map[int64]int64),
+		checksumRows:     make(map[int64]struct{}),
+		interrupted:      make(map[int64]int64),
 		dbFile:           dbFile,
 		rowSize:          rowSize,
 		size:             size,
@@ -76,10 +85,12 @@ func (imf *InMemoryFinder) buildIndex() error {
 			return NewCorruptDatabaseError(fmt.Sprintf("failed to parse row at index %d", i), err)
 		}
 		if ru.ChecksumRow != nil {
+			imf.checksumRows[i] = struct{}{}
 			continue
 		}
 		if ru.DataRow != nil {
 			if ru.DataRow.StartControl == START_TRANSACTION {
+				imf.markInterrupted(currentTxStart, i)
 				currentTxStart = i
 			}
 			imf.transactionStart[i] = currentTxStart
@@ -91,7 +102,11 @@ func (imf *InMemoryFinder) buildIndex() error {
 			key := ru.DataRow.GetKey()
 			if key != uuid.Nil {
 				if err := ValidateUUIDv7(key); err == nil {
-					imf.uuidIndex[key] = i
+					if _, seen := imf.uuidIndex[key]; !seen {
+						imf.uuidIndex[key] = i
+					} else {
+						imf.repeats[key] = append(imf.repeats[key], i)
+					}
 					// Update maxTimestamp for complete DataRow
 					timestamp := ExtractUUIDv7Timestamp(key)
 					if timestamp > imf.maxTimestamp {
@@ -100,6 +115,7 @@ func (imf *InMemoryFinder) buildIndex() error {
 				}
 			}
 		} else if ru.NullRow != nil {
+			imf.markInterrupted(currentTxStart, i)
 			currentTxStart = i
 			imf.transactionStart[i] = i
 			imf.transactionEnd[i] = i
@@ -115,6 +131,22 @@ func (imf *InMemoryFinder) buildIndex() error {
 	return nil
 }
 
+// markInterrupted records the rows of the transaction started at txStart as
+// interrupted by the transaction starting at next, unless it has already ended.
+func (imf *InMemoryFinder) markInterrupted(txStart, next int64) {
+	if txStart < 0 {
+		return
+	}
+	if _, ended := imf.transactionEnd[txStart]; ended {
+		return
+	}
+	for j := txStart; j < next; j++ {
+		if !imf.isChecksumRow(j) {
+			imf.interrupted[j] = next
+		}
+	}
+}
+
 func (imf *InMemoryFinder) rowEndsTransaction(ru *RowUnion) bool {
 	if ru.NullRow != nil {
 		return true
@@ -158,6 +190,18 @@ func (imf *InMemoryFinder) GetIndex(key uuid.UUID) (int64, error) {
 	return idx, nil
 }
 
+// GetIndexes returns the index of every row containing key, in file order, from
+// the index kept for each key written more than once. skewMs is not needed.
+func (imf *InMemoryFinder) GetIndexes(key uuid.UUID, skewMs int64) ([]int64, error) {
+	first, err := imf.GetIndex(key)
+	if err != nil {
+		return nil, err
+	}
+	imf.mu.RLock()
+	defer imf.mu.RUnlock()
+	return append([]int64{first}, imf.repeats[key]...), nil
+}
+
 func (imf *InMemoryFinder) GetTransactionStart(index int64) (int64, error) {
 	// FR-011: Check tombstoned state FIRST
 	imf.mu.RLock()
@@ -201,6 +245,9 @@ func (imf *InMemoryFinder) GetTransactionEnd(index int64) (int64, error) {
 	if imf.isChecksumRow(index) {
 		return -1, NewInvalidInputError("index points to checksum row", nil)
 	}
+	if next, ok := imf.interrupted[index]; ok {
+		return -1, newInterleavedTransactionError(index, next)
+	}
 	end, ok := imf.transactionEnd[index]
 	if !ok {
 		return -1, NewTransactionActiveError("transaction has no ending row", nil)
@@ -227,12 +274,18 @@ func (imf *InMemoryFinder) onRowAdded(index int64, row *RowUnion) error {
 		imf.tombstonedErr = NewTombstonedError("finder tombstoned due to onRowAdded error", err)
 		return err
 	}
+	if imf.budgetRows > 0 && index == imf.budgetRows {
+		loggerOrNop(imf.logger).Warnf("frozendb: inmemory finder index has grown past the %d byte memory budget (%d rows); reopen to use binary_search",
+			imf.budgetBytes, index+1)
+	}
 	if row.ChecksumRow != nil {
+		imf.checksumRows[index] = struct{}{}
 		imf.size += int64(imf.rowSize)
 		return nil
 	}
 	if row.DataRow != nil {
 		if row.DataRow.StartControl == START_TRANSACTION {
+			imf.markInterrupted(imf.lastTxStart, index)
 			imf.lastTxStart = index
 		}
 		imf.transactionStart[index] = imf.lastTxStart
@@ -244,7 +297,11 @@ func (imf *InMemoryFinder) onRowAdded(index int64, row *RowUnion) error {
 		key := row.DataRow.GetKey()
 		if key != uuid.Nil {
 			if err := ValidateUUIDv7(key); err == nil {
-				imf.uuidIndex[key] = index
+				if _, seen := imf.uuidIndex[key]; !seen {
+					imf.uuidIndex[key] = index
+				} else {
+					imf.repeats[key] = append(imf.repeats[key], index)
+				}
 				// Update maxTimestamp for complete DataRow
 				timestamp := ExtractUUIDv7Timestamp(key)
 				if timestamp > imf.maxTimestamp {
@@ -253,6 +310,7 @@ func (imf *InMemoryFinder) onRowAdded(index int64, row *RowUnion) error {
 			}
 		}
 	} else if row.NullRow != nil {
+		imf.markInterrupted(imf.lastTxStart, index)
 		imf.lastTxStart = index
 		imf.transactionStart[index] = index
 		imf.transactionEnd[index] = index
@@ -267,6 +325,16 @@ func (imf *InMemoryFinder) onRowAdded(index int64, row *RowUnion) error {
 	return nil
 }
 
+// setMemoryBudget makes onRowAdded log a warning through logger once the index
+// outgrows budget bytes (INMEMORY_BYTES_PER_ROW per row). The finder keeps
+// indexing every row; only a reopen can switch strategy. It must be called before
+// the finder is used.
+func (imf *InMemoryFinder) setMemoryBudget(budget int64, logger Logger) {
+	imf.budgetRows = budget / INMEMORY_BYTES_PER_ROW
+	imf.budgetBytes = budget
+	imf.logger = logger
+}
+
 // MaxTimestamp returns the maximum timestamp among all complete data and null rows.
 // Implements O(1) time complexity by returning the cached maxTimestamp value.
 // Note: This method returns the maxTimestamp value even if the Finder is tombstoned,
@@ -289,5 +357,6 @@ func (imf *InMemoryFinder) validateIndex(index int64) error {
 }
 
 func (imf *InMemoryFinder) isChecksumRow(index int64) bool {
-	return index%int64(CHECKSUM_INTERVAL+1) == 0
+	_, ok := imf.checksumRows[index]
+	return ok
 }
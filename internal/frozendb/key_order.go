@@ -0,0 +1,82 @@
+package frozendb
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// ValidateKeyOrder checks that keys can be added in order to a database whose
+// max_timestamp is maxTimestamp, applying AddRow's rule (FR-014) to each key in
+// turn: new_timestamp + skew_ms > max_timestamp, where max_timestamp grows to
+// include every earlier key of the slice. Use it to reject a bulk write before
+// any row is written, instead of failing partway through it.
+//
+// Pass 0 as maxTimestamp for an empty database, or the finder's current
+// max_timestamp to check keys that will be appended to an existing one. Keys
+// equal in timestamp, or a little out of order within skewMs, are accepted, as
+// AddRow accepts them; duplicate keys are not detected.
+//
+// Parameters:
+//   - keys: Keys in the order they will be added
+//   - maxTimestamp: Largest timestamp already in the database (0 when empty)
+//   - skewMs: The database's skew_ms
+//
+// Returns:
+//   - error: KeyOrderViolationError for the first key that AddRow would reject,
+//     or InvalidInputError (a key is not UUIDv7, or a negative argument)
+func ValidateKeyOrder(keys []uuid.UUID, maxTimestamp int64, skewMs int) error {
+	if maxTimestamp < 0 {
+		return NewInvalidInputError(fmt.Sprintf("maxTimestamp must be non-negative, got %d", maxTimestamp), nil)
+	}
+	if skewMs < 0 {
+		return NewInvalidInputError(fmt.Sprintf("skewMs must be non-negative, got %d", skewMs), nil)
+	}
+	for i, key := range keys {
+		if err := ValidateUUIDv7(key); err != nil {
+			return NewInvalidInputError(fmt.Sprintf("key %d: invalid UUIDv7 key", i), err)
+		}
+		ts := ExtractUUIDv7Timestamp(key)
+		if err := checkKeyOrder(i, ts, maxTimestamp, int64(skewMs)); err != nil {
+			return err
+		}
+		maxTimestamp = max(maxTimestamp, ts)
+	}
+	return nil
+}
+
+// OrderOK reports whether AddRow accepts key next after key prev under the
+// ordering rule (FR-014): next_timestamp + skew_ms > prev_timestamp. It is the
+// single-pair form of ValidateKeyOrder, for ID generators that must only hand out
+// keys the database will accept.
+//
+// AddRow compares against the largest timestamp written so far, so prev should
+// be the key with the largest timestamp, not merely the last one; uuid.Nil stands
+// for an empty database. A next that is not a UUIDv7 key is never OK.
+//
+// Parameters:
+//   - prev: Key with the largest timestamp already written, or uuid.Nil
+//   - next: Key about to be added
+//   - skewMs: The database's skew_ms
+//
+// Returns:
+//   - bool: true when next would not be rejected for its ordering
+func OrderOK(prev, next uuid.UUID, skewMs int) bool {
+	if ValidateUUIDv7(next) != nil {
+		return false
+	}
+	return ExtractUUIDv7Timestamp(next)+int64(skewMs) > ExtractUUIDv7Timestamp(prev)
+}
+
+// checkKeyOrder applies the FR-014 ordering rule to the key at index with
+// timestamp ts, returning a KeyOrderViolationError when it fails.
+func checkKeyOrder(index int, ts, maxTimestamp, skewMs int64) error {
+	if ts+skewMs > maxTimestamp {
+		return nil
+	}
+	return NewKeyOrderViolationError(
+		fmt.Sprintf("key %d: UUID timestamp %d violates ordering constraint: new_timestamp + skew_ms must be > max_timestamp %d", index, ts, maxTimestamp),
+		index, ts, maxTimestamp,
+		NewKeyOrderingError("UUID timestamp violates ordering constraint", nil),
+	)
+}
@@ -0,0 +1,94 @@
+package frozendb
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestValidateKeyOrder(t *testing.T) {
+	keys := func(timestamps ...int) []uuid.UUID {
+		out := make([]uuid.UUID, len(timestamps))
+		for i, ts := range timestamps {
+			out[i] = uuidFromTS(ts)
+		}
+		return out
+	}
+
+	// Ascending keys, equal timestamps, and keys back within the skew are accepted
+	for _, tt := range []struct {
+		name string
+		keys []uuid.UUID
+		max  int64
+	}{
+		{name: "empty", keys: nil},
+		{name: "ascending", keys: keys(1000, 2000, 3000)},
+		{name: "equal timestamps", keys: keys(1000, 1000)},
+		{name: "within skew", keys: keys(10000, 5001)},
+		{name: "after database max", keys: keys(20000), max: 24999},
+	} {
+		if err := ValidateKeyOrder(tt.keys, tt.max, 5000); err != nil {
+			t.Errorf("%s: ValidateKeyOrder = %v", tt.name, err)
+		}
+	}
+
+	var violation *KeyOrderViolationError
+	err := ValidateKeyOrder(keys(1000, 12000, 9000, 7000), 0, 5000)
+	if !errors.As(err, &violation) {
+		t.Fatalf("expected KeyOrderViolationError, got %v", err)
+	}
+	if violation.Index != 3 || violation.Timestamp != 7000 || violation.MaxTimestamp != 12000 {
+		t.Errorf("violation = index %d, ts %d, max %d; want 3, 7000, 12000", violation.Index, violation.Timestamp, violation.MaxTimestamp)
+	}
+	var ordering *KeyOrderingError
+	if !errors.As(err, &ordering) {
+		t.Errorf("expected the violation to wrap KeyOrderingError, got %v", err)
+	}
+
+	// The database's max_timestamp counts like an earlier key
+	if err := ValidateKeyOrder(keys(20000), 25000, 5000); !errors.As(err, &violation) || violation.Index != 0 {
+		t.Errorf("key at max - skew: expected violation at index 0, got %v", err)
+	}
+
+	var invalidInput *InvalidInputError
+	for name, err := range map[string]error{
+		"not UUIDv7":       ValidateKeyOrder([]uuid.UUID{uuid.New()}, 0, 5000),
+		"negative skew":    ValidateKeyOrder(nil, 0, -1),
+		"negative max":     ValidateKeyOrder(nil, -1, 5000),
+		"nil key in slice": ValidateKeyOrder([]uuid.UUID{uuidFromTS(1), uuid.Nil}, 0, 5000),
+	} {
+		if !errors.As(err, &invalidInput) {
+			t.Errorf("%s: expected InvalidInputError, got %v", name, err)
+		}
+	}
+}
+
+func TestOrderOK(t *testing.T) {
+	for _, tt := range []struct {
+		name       string
+		prev, next uuid.UUID
+		want       bool
+	}{
+		{name: "ascending", prev: uuidFromTS(1000), next: uuidFromTS(2000), want: true},
+		{name: "equal timestamps", prev: uuidFromTS(1000), next: uuidFromTS(1000), want: true},
+		{name: "descending within skew", prev: uuidFromTS(10000), next: uuidFromTS(5001), want: true},
+		{name: "descending by exactly skew", prev: uuidFromTS(10000), next: uuidFromTS(5000), want: false},
+		{name: "descending beyond skew", prev: uuidFromTS(10000), next: uuidFromTS(1000), want: false},
+		{name: "empty database", prev: uuid.Nil, next: uuidFromTS(1), want: true},
+		{name: "next not UUIDv7", prev: uuid.Nil, next: uuid.New(), want: false},
+	} {
+		if got := OrderOK(tt.prev, tt.next, 5000); got != tt.want {
+			t.Errorf("%s: OrderOK = %v, want %v", tt.name, got, tt.want)
+		}
+		// OrderOK agrees with ValidateKeyOrder on every pair it accepts
+		if tt.want && ValidateKeyOrder([]uuid.UUID{tt.next}, ExtractUUIDv7Timestamp(tt.prev), 5000) != nil {
+			t.Errorf("%s: ValidateKeyOrder rejects a pair OrderOK accepts", tt.name)
+		}
+	}
+
+	// With skew 0 only strictly ascending timestamps are accepted
+	if OrderOK(uuidFromTS(1000), uuidFromTS(1000), 0) {
+		t.Error("skew 0: equal timestamps accepted")
+	}
+}
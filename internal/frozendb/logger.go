@@ -0,0 +1,27 @@
+package frozendb
+
+// Logger receives diagnostic events from a FrozenDB and its transactions, such as
+// lock acquisition and release, finder strategy selection, checksum row
+// insertion, and transaction tombstoning. Set one with WithLogger.
+//
+// Debugf is used for routine events and Warnf for failures worth investigating.
+// Implementations must be safe for concurrent use; messages carry no trailing
+// newline.
+type Logger interface {
+	Debugf(format string, args ...any)
+	Warnf(format string, args ...any)
+}
+
+// nopLogger discards every event. It is the default Logger.
+type nopLogger struct{}
+
+func (nopLogger) Debugf(string, ...any) {}
+func (nopLogger) Warnf(string, ...any)  {}
+
+// loggerOrNop returns l, or the no-op logger when l is nil.
+func loggerOrNop(l Logger) Logger {
+	if l == nil {
+		return nopLogger{}
+	}
+	return l
+}
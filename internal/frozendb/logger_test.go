@@ -0,0 +1,101 @@
+package frozendb
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// recordingLogger collects formatted events, prefixed with their level.
+type recordingLogger struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func (l *recordingLogger) Debugf(format string, args ...any) { l.record("debug", format, args) }
+func (l *recordingLogger) Warnf(format string, args ...any)  { l.record("warn", format, args) }
+
+func (l *recordingLogger) record(level, format string, args []any) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.events = append(l.events, level+": "+fmt.Sprintf(format, args...))
+}
+
+// requireEvent fails the test unless an event with the level contains substr.
+func (l *recordingLogger) requireEvent(t *testing.T, level, substr string) {
+	t.Helper()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, event := range l.events {
+		if strings.HasPrefix(event, level+": ") && strings.Contains(event, substr) {
+			return
+		}
+	}
+	t.Errorf("no %s event containing %q in %q", level, substr, l.events)
+}
+
+func TestWithLogger_Events(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logger.fdb")
+	setupMockSyscalls(false, false)
+	t.Cleanup(restoreRealSyscalls)
+	t.Setenv("SUDO_USER", MOCK_USER)
+	t.Setenv("SUDO_UID", MOCK_UID)
+	t.Setenv("SUDO_GID", MOCK_GID)
+	config := NewCreateConfig(path, confRowSize, confSkewMs)
+	config.SetChecksumInterval(MIN_CHECKSUM_INTERVAL)
+	if err := Create(config); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	logger := &recordingLogger{}
+	db, err := NewFrozenDB(path, MODE_WRITE, FinderStrategyAuto, WithLogger(logger))
+	if err != nil {
+		t.Fatalf("NewFrozenDB: %v", err)
+	}
+	logger.requireEvent(t, "debug", "acquired exclusive lock")
+	logger.requireEvent(t, "debug", "auto finder selected simple")
+
+	// A second writer reports the lock conflict
+	conflict := &recordingLogger{}
+	var writeErr *WriteError
+	if _, err := NewFrozenDB(path, MODE_WRITE, FinderStrategySimple, WithLogger(conflict)); !errors.As(err, &writeErr) {
+		t.Fatalf("expected WriteError, got %v", err)
+	}
+	conflict.requireEvent(t, "warn", "locked by another writer")
+
+	// Filling one checksum interval inserts a checksum row
+	for start := 0; start < MIN_CHECKSUM_INTERVAL; start += 50 {
+		tx, err := db.BeginTx()
+		if err != nil {
+			t.Fatalf("BeginTx: %v", err)
+		}
+		for i := start; i < start+50; i++ {
+			mustAdd(t, tx, uuidFromTS(1000+i), `{}`)
+		}
+		if err := tx.Commit(); err != nil {
+			t.Fatalf("Commit: %v", err)
+		}
+	}
+	logger.requireEvent(t, "debug", "inserted checksum row")
+
+	if err := db.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	logger.requireEvent(t, "debug", "released exclusive lock")
+}
+
+func TestWithLogger_Tombstone(t *testing.T) {
+	logger := &recordingLogger{}
+	// An unbuffered channel with no reader cannot accept the write
+	tx := &Transaction{writeChan: make(chan Data), logger: logger}
+	if err := tx.writeBytes([]byte("row")); err == nil {
+		t.Fatal("expected write error")
+	}
+	if !tx.IsTombstoned() {
+		t.Fatal("expected transaction to be tombstoned")
+	}
+	logger.requireEvent(t, "warn", "tombstoned")
+}
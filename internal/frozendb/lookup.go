@@ -0,0 +1,75 @@
+package frozendb
+
+import (
+	"math"
+
+	"github.com/google/uuid"
+)
+
+// LookupResult classifies why FrozenDB.Lookup does or does not see a key.
+type LookupResult string
+
+const (
+	LookupFound          LookupResult = "found"           // Committed and visible: Get returns it
+	LookupNotFound       LookupResult = "not_found"       // Never written
+	LookupRolledBack     LookupResult = "rolled_back"     // Written by a fully rolled back transaction (R0, S0)
+	LookupAfterSavepoint LookupResult = "after_savepoint" // Written after the savepoint a transaction rolled back to (R1-R9, S1-S9)
+	LookupUncommitted    LookupResult = "uncommitted"     // Written by the transaction still in progress at the end of the file
+	LookupDeleted        LookupResult = "deleted"         // Last committed occurrence is a tombstone written by Transaction.Delete
+)
+
+// Lookup reports whether key is visible and, if not, why: Get returns
+// KeyNotFoundError alike for a key that was never written and for one whose
+// transaction rolled it back or has not ended yet. Lookup is a diagnostic
+// companion to Get for tracking down write bugs; it reads the same rows Get does
+// and does not return the value.
+//
+// A key written more than once is classified by its first committed occurrence,
+// which is the one Get returns, or by its last occurrence when none committed.
+//
+// Parameters:
+//   - key: UUIDv7 key to classify (must not be uuid.Nil)
+//
+// Returns:
+//   - LookupResult: LookupFound, LookupNotFound, LookupRolledBack,
+//     LookupAfterSavepoint, LookupUncommitted, or LookupDeleted
+//   - error: InvalidInputError, ReadError, or CorruptDatabaseError
+//
+// Thread Safety: Safe for concurrent calls on the same FrozenDB instance
+func (db *FrozenDB) Lookup(key uuid.UUID) (LookupResult, error) {
+	if key == uuid.Nil {
+		return "", NewInvalidInputError("key cannot be uuid.Nil", nil)
+	}
+
+	db.refresh()
+	_, result, err := db.classifyKey(key, math.MaxInt64, db.parsedRowControls)
+	if result == "" {
+		return "", err
+	}
+	if result == LookupNotFound {
+		// Finders index complete rows only, so a key in the row still being written
+		// is found by checking the trailing partial row
+		inPartial, err := db.partialRowHoldsKey(key)
+		if err != nil {
+			return "", err
+		}
+		if inPartial {
+			return LookupUncommitted, nil
+		}
+	}
+	return result, nil
+}
+
+// partialRowHoldsKey reports whether the file ends in a PartialDataRow holding key.
+func (db *FrozenDB) partialRowHoldsKey(key uuid.UUID) (bool, error) {
+	rowSize := int64(db.header.GetRowSize())
+	dataBytes := db.file.Size() - int64(HEADER_SIZE)
+	if dataBytes <= 0 || dataBytes%rowSize == 0 {
+		return false, nil
+	}
+	row, err := db.rowInfoAt(dataBytes / rowSize)
+	if err != nil {
+		return false, err
+	}
+	return row.Kind == RowKindPartial && row.Key == key, nil
+}
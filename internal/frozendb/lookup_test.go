@@ -0,0 +1,74 @@
+package frozendb
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestLookup(t *testing.T) {
+	path := setupCreate(t, t.TempDir(), 0)
+
+	// Committed transaction
+	tx, db := openAndBegin(t, path)
+	mustAdd(t, tx, uuidFromTS(1000), `{"n":1}`)
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+	db.Close()
+
+	// Rolled back to the savepoint after 2000
+	tx, db = openAndBegin(t, path)
+	mustAdd(t, tx, uuidFromTS(2000), `{"n":2}`)
+	if err := tx.Savepoint(); err != nil {
+		t.Fatalf("Savepoint: %v", err)
+	}
+	mustAdd(t, tx, uuidFromTS(3000), `{"n":3}`)
+	if err := tx.Rollback(1); err != nil {
+		t.Fatalf("Rollback: %v", err)
+	}
+	db.Close()
+
+	// Fully rolled back
+	tx, db = openAndBegin(t, path)
+	mustAdd(t, tx, uuidFromTS(4000), `{"n":4}`)
+	if err := tx.Rollback(0); err != nil {
+		t.Fatalf("Rollback: %v", err)
+	}
+	db.Close()
+
+	// In progress: 5000 is a complete row, 6000 the trailing partial row
+	tx, db = openAndBegin(t, path)
+	mustAdd(t, tx, uuidFromTS(5000), `{"n":5}`)
+	mustAdd(t, tx, uuidFromTS(6000), `{"n":6}`)
+	defer db.Close()
+
+	reader := openForScan(t, path)
+	tests := []struct {
+		ts   int
+		want LookupResult
+	}{
+		{1000, LookupFound},
+		{2000, LookupFound},
+		{3000, LookupAfterSavepoint},
+		{4000, LookupRolledBack},
+		{5000, LookupUncommitted},
+		{6000, LookupUncommitted},
+		{7000, LookupNotFound},
+	}
+	for _, tt := range tests {
+		got, err := reader.Lookup(uuidFromTS(tt.ts))
+		if err != nil {
+			t.Fatalf("Lookup(%d): %v", tt.ts, err)
+		}
+		if got != tt.want {
+			t.Errorf("Lookup(%d) = %s, want %s", tt.ts, got, tt.want)
+		}
+	}
+
+	var invalidInput *InvalidInputError
+	if _, err := reader.Lookup(uuid.Nil); !errors.As(err, &invalidInput) {
+		t.Errorf("Lookup(uuid.Nil): expected InvalidInputError, got %v", err)
+	}
+}
@@ -0,0 +1,63 @@
+package frozendb
+
+import (
+	"encoding/json"
+
+	"github.com/google/uuid"
+)
+
+// Materialize folds the committed rows into a map by calling reduce once per row,
+// in file order, and returns the map reduce built. This is the common pattern of
+// treating the database as an event log and replaying it into current state: the
+// reducer decides how each row is keyed and merged, for example last-write-wins on
+// an ID carried in the value, or a running total per account.
+//
+// Rows are read one transaction at a time with the same visibility rules as Scan;
+// rolled back rows and any transaction still in progress are skipped. Tombstones
+// written by Transaction.Delete are not passed to reduce, but the rows written
+// before them are: a log replays every event, and a reducer that needs deletes
+// should record them in its own values. File order
+// is the order in which transactions committed, so a later event is always applied
+// after an earlier one even when their keys are out of order within the skew
+// window. The read is bounded by the file size when Materialize is called.
+//
+// Materialize itself buffers at most one transaction, but the returned map holds
+// whatever state reduce accumulates; reducers over large logs should keep the map
+// to the entries they need and delete the ones they no longer do.
+//
+// Parameters:
+//   - reduce: Callback applying one row to state; it may store value in state
+//
+// Returns:
+//   - map[string]json.RawMessage: The state after every committed row was applied
+//   - error: InvalidInputError (nil reduce), ReadError, or CorruptDatabaseError
+//
+// Thread Safety: Safe for concurrent calls on the same FrozenDB instance
+func (db *FrozenDB) Materialize(reduce func(state map[string]json.RawMessage, key uuid.UUID, value json.RawMessage)) (map[string]json.RawMessage, error) {
+	if reduce == nil {
+		return nil, NewInvalidInputError("reduce cannot be nil", nil)
+	}
+
+	db.refresh()
+	reader := newCommittedRowReader(db.file, db.header.GetRowSize(), db.file.Size())
+	state := make(map[string]json.RawMessage)
+	for {
+		rows, ok, err := reader.nextTransaction()
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return state, nil
+		}
+		for i := range rows {
+			value, err := db.decodeValue(rows[i].GetValue())
+			if err != nil {
+				return nil, err
+			}
+			if isTombstone(value) {
+				continue
+			}
+			reduce(state, rows[i].GetKey(), value)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package frozendb
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestMaterialize_NilReducer(t *testing.T) {
+	path := setupCreate(t, t.TempDir(), 0)
+	db := openForScan(t, path)
+
+	var invalidInput *InvalidInputError
+	if _, err := db.Materialize(nil); !errors.As(err, &invalidInput) {
+		t.Fatalf("expected InvalidInputError, got %v", err)
+	}
+}
+
+func TestMaterialize_FoldsCommittedRowsInFileOrder(t *testing.T) {
+	path := setupCreate(t, t.TempDir(), 0)
+
+	// Key 1001 is written before 1000 (allowed by the skew window), so file order
+	// differs from key order: the update to "a" must win over the original write.
+	tx, db := openAndBegin(t, path)
+	mustAdd(t, tx, uuidFromTS(1001), `{"id":"a","v":1}`)
+	mustAdd(t, tx, uuidFromTS(1000), `{"id":"b","v":2}`)
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+	db.Close()
+
+	tx, db = openAndBegin(t, path)
+	mustAdd(t, tx, uuidFromTS(1002), `{"id":"a","v":3}`)
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+	db.Close()
+
+	// Rolled back update is not applied
+	tx, db = openAndBegin(t, path)
+	mustAdd(t, tx, uuidFromTS(1003), `{"id":"b","v":4}`)
+	if err := tx.Rollback(0); err != nil {
+		t.Fatalf("Rollback: %v", err)
+	}
+	db.Close()
+
+	var applied []uuid.UUID
+	state, err := openForScan(t, path).Materialize(func(state map[string]json.RawMessage, key uuid.UUID, value json.RawMessage) {
+		applied = append(applied, key)
+		var event struct {
+			ID string `json:"id"`
+		}
+		if err := json.Unmarshal(value, &event); err != nil {
+			t.Errorf("Unmarshal %s: %v", value, err)
+			return
+		}
+		state[event.ID] = value
+	})
+	if err != nil {
+		t.Fatalf("Materialize: %v", err)
+	}
+
+	wantApplied := []uuid.UUID{uuidFromTS(1001), uuidFromTS(1000), uuidFromTS(1002)}
+	if len(applied) != len(wantApplied) {
+		t.Fatalf("applied %d rows, want %d", len(applied), len(wantApplied))
+	}
+	for i := range wantApplied {
+		if applied[i] != wantApplied[i] {
+			t.Errorf("row %d = %s, want %s", i, applied[i], wantApplied[i])
+		}
+	}
+	if len(state) != 2 || string(state["a"]) != `{"id":"a","v":3}` || string(state["b"]) != `{"id":"b","v":2}` {
+		t.Errorf("state = %v", state)
+	}
+}
@@ -0,0 +1,331 @@
+package frozendb
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// MemFileManager is an in-memory DBFile for tests of the write path. It keeps
+// the append semantics of FileManager: one writer channel at a time, drained by
+// a goroutine that appends each write, answers on its Response channel, and then
+// notifies subscribers, so transactions and checksum interleaving behave exactly
+// as they do on disk without any file I/O.
+type MemFileManager struct {
+	mu           sync.RWMutex
+	data         []byte // Contents of the file, grown by writes
+	closed       bool
+	mode         string
+	writeChannel atomic.Value // stores <-chan Data (nil when no writer)
+	writerWg     sync.WaitGroup
+	subscribers  *Subscriber[func() error]
+}
+
+// NewMemFileManager returns a MemFileManager holding a copy of data.
+func NewMemFileManager(data []byte, mode string) *MemFileManager {
+	m := &MemFileManager{
+		data:        bytes.Clone(data),
+		mode:        mode,
+		subscribers: NewSubscriber[func() error](),
+	}
+	m.writeChannel.Store((<-chan Data)(nil))
+	return m
+}
+
+// newMemTestDatabase returns a write-mode MemFileManager holding header and its
+// initial checksum row, like createMinimalTestDatabase does on disk.
+func newMemTestDatabase(t *testing.T, header *Header) *MemFileManager {
+	t.Helper()
+	return NewMemFileManager(minimalTestDatabaseBytes(t, header), MODE_WRITE)
+}
+
+// minimalTestDatabaseBytes returns the bytes of a database holding only header
+// and its initial checksum row.
+func minimalTestDatabaseBytes(t *testing.T, header *Header) []byte {
+	t.Helper()
+	headerBytes, err := header.MarshalText()
+	if err != nil {
+		t.Fatalf("Failed to marshal header: %v", err)
+	}
+	checksumRow, err := NewChecksumRow(header.GetRowSize(), headerBytes)
+	if err != nil {
+		t.Fatalf("Failed to create checksum row: %v", err)
+	}
+	checksumBytes, err := checksumRow.MarshalText()
+	if err != nil {
+		t.Fatalf("Failed to marshal checksum row: %v", err)
+	}
+	return append(headerBytes, checksumBytes...)
+}
+
+// Bytes returns a copy of the current contents.
+func (m *MemFileManager) Bytes() []byte {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return bytes.Clone(m.data)
+}
+
+func (m *MemFileManager) Read(start int64, size int32) ([]byte, error) {
+	if size <= 0 {
+		return nil, NewInvalidInputError("size must be positive", nil)
+	}
+	data := make([]byte, size)
+	if err := m.ReadInto(data, start); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func (m *MemFileManager) ReadInto(dst []byte, start int64) error {
+	if start < 0 {
+		return NewInvalidInputError("start offset cannot be negative", nil)
+	}
+	if len(dst) == 0 || len(dst) > math.MaxInt32 {
+		return NewInvalidInputError("size must be positive", nil)
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.closed {
+		return NewTombstonedError("file manager is closed", os.ErrClosed)
+	}
+	if start+int64(len(dst)) > int64(len(m.data)) {
+		return newReadPastEndError(start, len(dst), int64(len(m.data)))
+	}
+	copy(dst, m.data[start:])
+	return nil
+}
+
+func (m *MemFileManager) Size() int64 {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return int64(len(m.data))
+}
+
+func (m *MemFileManager) Close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.closed = true
+	return nil
+}
+
+func (m *MemFileManager) GetMode() string {
+	return m.mode
+}
+
+func (m *MemFileManager) WriterClosed() {
+	if m.mode == MODE_READ {
+		return
+	}
+	m.writerWg.Wait()
+}
+
+func (m *MemFileManager) Subscribe(callback func() error) (func() error, error) {
+	if callback == nil {
+		return nil, NewInvalidInputError("callback cannot be nil", nil)
+	}
+	return m.subscribers.Subscribe(callback), nil
+}
+
+func (m *MemFileManager) SetWriter(dataChan <-chan Data) error {
+	if m.mode == MODE_READ {
+		return NewInvalidActionError("cannot set writer on read-mode DBFile", nil)
+	}
+	if dataChan == nil {
+		return NewInvalidActionError("dataChan cannot be nil", nil)
+	}
+	if !m.writeChannel.CompareAndSwap((<-chan Data)(nil), dataChan) {
+		return NewInvalidActionError("writer already active", nil)
+	}
+	m.writerWg.Add(1)
+	go m.writerLoop(dataChan)
+	return nil
+}
+
+func (m *MemFileManager) writerLoop(dataChan <-chan Data) {
+	defer func() {
+		m.writeChannel.Store((<-chan Data)(nil))
+		m.writerWg.Done()
+	}()
+	for data := range dataChan {
+		err := m.processWrite(data.Bytes)
+		data.Response <- err
+		if err != nil {
+			return
+		}
+	}
+}
+
+func (m *MemFileManager) processWrite(b []byte) error {
+	m.mu.Lock()
+	if m.closed {
+		m.mu.Unlock()
+		return NewTombstonedError("file manager is closed", os.ErrClosed)
+	}
+	m.data = append(m.data, b...)
+	m.mu.Unlock()
+
+	for _, callback := range m.subscribers.Snapshot() {
+		if err := callback(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// TestMemFileManager_MatchesFileManager runs the same transactions against a
+// FileManager and a MemFileManager and requires byte-identical files, covering
+// savepoints, rollbacks, and checksum rows at a short interval.
+func TestMemFileManager_MatchesFileManager(t *testing.T) {
+	header := createTestHeader()
+	header.checksumInterval = 7
+
+	path := filepath.Join(t.TempDir(), "parity.db")
+	createMinimalTestDatabase(t, path, header)
+	fm, err := NewFileManager(path)
+	if err != nil {
+		t.Fatalf("NewFileManager: %v", err)
+	}
+	defer fm.Close()
+	mem := newMemTestDatabase(t, header)
+
+	run := func(db DBFile) {
+		ts := 1000
+		for txNum := 0; txNum < 6; txNum++ {
+			tx, err := NewTransaction(db, header, &mockFinderWithMaxTimestamp{})
+			if err != nil {
+				t.Fatalf("NewTransaction: %v", err)
+			}
+			if err := tx.Begin(); err != nil {
+				t.Fatalf("Begin: %v", err)
+			}
+			for i := 0; i < 5; i++ {
+				ts += 10
+				if err := tx.AddRow(uuidFromTS(ts), json.RawMessage(fmt.Sprintf(`{"n":%d}`, ts))); err != nil {
+					t.Fatalf("AddRow: %v", err)
+				}
+				if i == 2 {
+					if err := tx.Savepoint(); err != nil {
+						t.Fatalf("Savepoint: %v", err)
+					}
+				}
+			}
+			switch txNum % 3 {
+			case 0:
+				err = tx.Commit()
+			case 1:
+				err = tx.Rollback(1)
+			default:
+				err = tx.Rollback(0)
+			}
+			if err != nil {
+				t.Fatalf("ending transaction %d: %v", txNum, err)
+			}
+		}
+		// An empty transaction ends in a NullRow
+		tx, err := NewTransaction(db, header, &mockFinderWithMaxTimestamp{})
+		if err != nil {
+			t.Fatalf("NewTransaction: %v", err)
+		}
+		if err := tx.Begin(); err != nil {
+			t.Fatalf("Begin: %v", err)
+		}
+		if err := tx.Commit(); err != nil {
+			t.Fatalf("Commit: %v", err)
+		}
+	}
+	run(fm)
+	run(mem)
+
+	onDisk, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	if !bytes.Equal(onDisk, mem.Bytes()) {
+		t.Fatalf("MemFileManager contents (%d bytes) differ from FileManager (%d bytes)", len(mem.Bytes()), len(onDisk))
+	}
+	if mem.Size() != fm.Size() {
+		t.Errorf("Size() = %d, FileManager reports %d", mem.Size(), fm.Size())
+	}
+	rowSize := int64(header.GetRowSize())
+	if checksumStart := HEADER_SIZE + 8*rowSize; int64(len(onDisk)) < checksumStart+rowSize || onDisk[checksumStart+1] != byte(CHECKSUM_ROW) {
+		t.Errorf("expected a checksum row after the first 7 rows")
+	}
+
+	writeChan := make(chan Data)
+	if err := mem.SetWriter(writeChan); err != nil {
+		t.Errorf("SetWriter after the writer finished: %v", err)
+	}
+	close(writeChan)
+	mem.WriterClosed()
+	mem.Close()
+	if _, err := mem.Read(0, 1); err == nil {
+		t.Error("Read after Close should fail")
+	}
+	if _, err := NewMemFileManager(nil, MODE_READ).Read(0, 1); err == nil {
+		t.Error("Read past the end should fail")
+	}
+}
+
+// TestMemFileManager_ChecksumAtDefaultInterval writes 10,000 rows through a
+// MemFileManager at the default checksum interval, leaving the last one in a
+// partial transaction first, so the interval logic runs at full scale without
+// touching disk.
+func TestMemFileManager_ChecksumAtDefaultInterval(t *testing.T) {
+	header := createTestHeader()
+	rowSize := int64(header.GetRowSize())
+	mem := newMemTestDatabase(t, header)
+
+	ts := 1000
+	addRows := func(tx *Transaction, n int) {
+		for i := 0; i < n; i++ {
+			ts++
+			if err := tx.AddRow(uuidFromTS(ts), json.RawMessage(fmt.Sprintf(`{"n":%d}`, ts))); err != nil {
+				t.Fatalf("AddRow: %v", err)
+			}
+		}
+	}
+	for txNum := 0; txNum < 100; txNum++ {
+		tx, err := NewTransaction(mem, header, &mockFinderWithMaxTimestamp{})
+		if err != nil {
+			t.Fatalf("NewTransaction: %v", err)
+		}
+		if err := tx.Begin(); err != nil {
+			t.Fatalf("Begin: %v", err)
+		}
+		if txNum < 99 {
+			addRows(tx, 100)
+			if err := tx.Commit(); err != nil {
+				t.Fatalf("Commit: %v", err)
+			}
+			continue
+		}
+
+		// 9,999 complete rows plus a partial one must not trigger a checksum
+		addRows(tx, 100)
+		if size := mem.Size(); size >= int64(HEADER_SIZE)+rowSize+10000*rowSize {
+			t.Fatalf("size with a partial row = %d, want less than %d complete rows", size, 10000)
+		}
+		if err := tx.Commit(); err != nil {
+			t.Fatalf("Commit: %v", err)
+		}
+	}
+
+	checksumOffset := int64(HEADER_SIZE) + rowSize + 10000*rowSize
+	if size := mem.Size(); size != checksumOffset+rowSize {
+		t.Fatalf("Size() = %d, want %d", size, checksumOffset+rowSize)
+	}
+	checksumBytes, err := mem.Read(checksumOffset, int32(rowSize))
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	var checksumRow ChecksumRow
+	if err := checksumRow.UnmarshalText(checksumBytes); err != nil {
+		t.Fatalf("row 10,000 is not a checksum row: %v", err)
+	}
+}
@@ -63,8 +63,8 @@ func (nrp *NullRowPayload) Validate() error {
 //   - error: InvalidInputError for invalid parameters
 func NewNullRow(rowSize int, maxTimestamp int64) (*NullRow, error) {
 	// Validate rowSize parameter
-	if rowSize < 128 || rowSize > 65536 {
-		return nil, NewInvalidInputError(fmt.Sprintf("rowSize must be between 128 and 65536, got %d", rowSize), nil)
+	if rowSize < MIN_ROW_SIZE || rowSize > MAX_ROW_SIZE {
+		return nil, NewInvalidInputError(fmt.Sprintf("rowSize must be between %d and %d, got %d", MIN_ROW_SIZE, MAX_ROW_SIZE, rowSize), nil)
 	}
 
 	// Validate maxTimestamp parameter
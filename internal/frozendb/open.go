@@ -1,6 +1,7 @@
 package frozendb
 
 import (
+	"bytes"
 	"fmt"
 	"hash/crc32"
 	"io"
@@ -34,6 +35,10 @@ func validateDatabaseFile(dbFile DBFile) (*Header, error) {
 	}
 
 	rowSize := header.GetRowSize()
+	if err := checkRowFraming(dbFile, fileSize, rowSize); err != nil {
+		return nil, err
+	}
+
 	expectedMinSize := int64(HEADER_SIZE + rowSize)
 	if fileSize < expectedMinSize {
 		return nil, NewCorruptDatabaseError(
@@ -87,7 +92,18 @@ func validateDatabaseFile(dbFile DBFile) (*Header, error) {
 		)
 	}
 
-	expectedCRC := crc32.ChecksumIEEE(headerBytes)
+	// The initial checksum row of a version 2 file may carry an extension holding
+	// header fields, which the checksum covers along with the header
+	var extension []byte
+	if header.GetVersion() != FORMAT_VERSION || checksumRowBytes[10] != NULL_BYTE {
+		checksumRow, err := header.readInitialChecksumRow(checksumRowBytes)
+		if err != nil {
+			return nil, err
+		}
+		extension = checksumRow.Extension
+	}
+
+	expectedCRC := crc32.ChecksumIEEE(initialChecksumInput(headerBytes, extension))
 
 	checksumPayloadPos := int64(HEADER_SIZE) + 2
 	checksumBytes, err := dbFile.Read(checksumPayloadPos, 8)
@@ -117,3 +133,63 @@ func validateDatabaseFile(dbFile DBFile) (*Header, error) {
 
 	return header, nil
 }
+
+// checkRowFraming verifies that the initial checksum row ends where the header's
+// row_size says it does. When it does not, the checksum row is looked for at
+// every size from MIN_ROW_SIZE to MAX_ROW_SIZE, and a RowSizeMismatchError
+// reports the size the rows were actually written with, instead of the
+// misaligned parse errors that reading at the header's row_size would cause.
+// Any other damage is left to the checks that follow.
+func checkRowFraming(dbFile DBFile, fileSize int64, rowSize int) error {
+	available := fileSize - int64(HEADER_SIZE)
+	if available >= int64(rowSize) {
+		row, err := dbFile.Read(int64(HEADER_SIZE), int32(rowSize))
+		if err != nil {
+			return NewCorruptDatabaseError("failed to read checksum row", err)
+		}
+		if checksumRowEndsAt(row, rowSize) {
+			return nil
+		}
+	}
+
+	readSize := min(available, MAX_ROW_SIZE)
+	if readSize < MIN_ROW_SIZE {
+		return nil
+	}
+	data, err := dbFile.Read(int64(HEADER_SIZE), int32(readSize))
+	if err != nil {
+		return NewCorruptDatabaseError("failed to read checksum row", err)
+	}
+	if data[0] != ROW_START || data[1] != byte(CHECKSUM_ROW) {
+		return nil
+	}
+	for observed := MIN_ROW_SIZE; observed <= len(data); observed++ {
+		if observed != rowSize && checksumRowEndsAt(data, observed) {
+			return NewRowSizeMismatchError(
+				fmt.Sprintf("header row_size is %d but the initial checksum row is %d bytes", rowSize, observed),
+				rowSize, observed,
+				NewCorruptDatabaseError("row framing does not match header row_size", nil),
+			)
+		}
+	}
+	return nil
+}
+
+// checksumRowEndsAt reports whether data starts with a checksum row framed at
+// size bytes: its 8-character checksum and any extension, followed by NULL_BYTE
+// padding up to end_control 'CS', two parity characters, and ROW_END.
+func checksumRowEndsAt(data []byte, size int) bool {
+	if len(data) < size || data[size-1] != ROW_END || data[size-5] != 'C' || data[size-4] != 'S' {
+		return false
+	}
+	padding := bytes.IndexByte(data[10:size-5], NULL_BYTE)
+	if padding == -1 {
+		return false
+	}
+	for _, b := range data[10+padding : size-5] {
+		if b != NULL_BYTE {
+			return false
+		}
+	}
+	return true
+}
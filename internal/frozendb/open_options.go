@@ -0,0 +1,128 @@
+package frozendb
+
+import "time"
+
+// OpenOption configures optional behavior of NewFrozenDB. Options are applied in
+// order; the defaults are the safe behavior described on NewFrozenDB.
+type OpenOption func(*openOptions)
+
+// openOptions holds the settings collected from OpenOption values.
+type openOptions struct {
+	noLock      bool          // Skip the exclusive flock in MODE_WRITE
+	lockTimeout time.Duration // How long to retry a held flock (0 fails immediately)
+	logger      Logger        // Receives diagnostic events (never nil after newOpenOptions)
+
+	finderMemoryBudget int64 // Bytes the in-memory finder may use (defaults to autoFinderMemoryBudget)
+	futureGuard        bool  // Reject keys ahead of the system clock by more than skew_ms
+	finderReadAhead    int   // Rows the finder reads around each row it needs (0 disables)
+	writeBuffer        int   // Capacity of the write channel transactions create (see WithWriteBuffer)
+}
+
+// newOpenOptions applies opts over the defaults.
+func newOpenOptions(opts []OpenOption) openOptions {
+	o := openOptions{logger: nopLogger{}, finderMemoryBudget: autoFinderMemoryBudget.Load(), writeBuffer: DEFAULT_WRITE_BUFFER}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&o)
+		}
+	}
+	o.logger = loggerOrNop(o.logger)
+	return o
+}
+
+// WithoutLock opens a MODE_WRITE database without taking the exclusive advisory
+// lock (flock) that normally prevents a second writer. Use it only on filesystems
+// where flock is unsupported or unreliable, such as some network mounts.
+//
+// The caller becomes responsible for guaranteeing that at most one writer, in any
+// process, has the file open at a time. Two unlocked writers appending to the same
+// file interleave rows and corrupt the database. The header is still validated and
+// the file is still opened for append. The option has no effect in MODE_READ,
+// which never locks.
+func WithoutLock() OpenOption {
+	return func(o *openOptions) {
+		o.noLock = true
+	}
+}
+
+// WithLockTimeout makes a MODE_WRITE open wait up to d for a writer in another
+// process to release the exclusive lock, retrying with exponential backoff, instead
+// of failing at once. This suits short-lived writers, such as scripted CLI calls,
+// that race each other. NewFrozenDB returns WriteError if the lock is still held
+// when d has elapsed. The default, and any d <= 0, keeps the fail-fast behavior.
+// The option has no effect with WithoutLock or in MODE_READ.
+func WithLockTimeout(d time.Duration) OpenOption {
+	return func(o *openOptions) {
+		o.lockTimeout = max(d, 0)
+	}
+}
+
+// WithLogger sends diagnostic events from the database and its transactions to
+// logger. By default events are discarded; a nil logger keeps that default.
+func WithLogger(logger Logger) OpenOption {
+	return func(o *openOptions) {
+		o.logger = logger
+	}
+}
+
+// WithFinderMemoryBudget sets the memory, in bytes, that the in-memory finder may
+// use for this database, overriding SetAutoFinderMemoryBudget. The index size is
+// estimated from the file size at open time (INMEMORY_BYTES_PER_ROW per row).
+// FinderStrategyAuto chooses the in-memory finder only within the budget, and a
+// file opened with FinderStrategyInMemory whose index would exceed it is opened
+// with FinderStrategyBinarySearch instead, with a warning logged, rather than
+// exhausting memory. A database whose index outgrows the budget while open keeps
+// the in-memory finder and logs a warning once; reopen it to switch strategy.
+// A budget of 0, or a negative one, disables the in-memory finder.
+func WithFinderMemoryBudget(bytes int64) OpenOption {
+	return func(o *openOptions) {
+		o.finderMemoryBudget = max(bytes, 0)
+	}
+}
+
+// WithFutureGuard makes AddRow and Delete reject keys whose timestamp is later
+// than the system clock plus skew_ms with FutureTimestampError. A single key far
+// in the future raises the database's max_timestamp, after which every key
+// generated from the real clock fails the ordering rule; the guard catches such a
+// key, for example a corrupted or hand-built UUIDv7, before it is written. The
+// guard is off by default, and it trusts the clock of the writing machine.
+func WithFutureGuard(enabled bool) OpenOption {
+	return func(o *openOptions) {
+		o.futureGuard = enabled
+	}
+}
+
+// WithFinderReadAhead makes the simple and binary search finders read a window of
+// rows neighboring each row they need with a single read, and serve the following
+// reads within that window from memory. The last steps of a binary search, and
+// the scans for a key's first occurrence and transaction boundaries, visit rows
+// close together, so a point lookup on a large file issues far fewer reads. This
+// matters most when each read is expensive, such as over an HTTPReaderAt.
+//
+// Windows are aligned to multiples of rows and only the most recent one is kept,
+// so the option costs about rows*row_size bytes; a window is capped at
+// MAX_FINDER_READ_AHEAD_BYTES. Values of 1 or less, the default, read one row at
+// a time. The in-memory finder reads every row once at open and ignores the
+// option.
+func WithFinderReadAhead(rows int) OpenOption {
+	return func(o *openOptions) {
+		o.finderReadAhead = max(rows, 0)
+	}
+}
+
+// WithWriteBuffer sets the capacity of the write channel that transactions create
+// for the database file. A larger buffer lets more writes queue behind a slow
+// fsync-bound writer before the sender blocks, which helps bursty callers. The
+// default is DEFAULT_WRITE_BUFFER. Values below 1 are raised to 1: a transaction
+// hands each write to the queue without blocking and tombstones itself with
+// WriteError when there is no room, so an unbuffered queue would fail writes
+// whenever the writer goroutine was not already waiting.
+//
+// Buffering does not weaken durability (FR-005): each Data carries a Response
+// channel, and the caller still waits for its write to complete before returning.
+// The buffer only bounds how many writes may be queued but not yet written.
+func WithWriteBuffer(n int) OpenOption {
+	return func(o *openOptions) {
+		o.writeBuffer = max(n, 1)
+	}
+}
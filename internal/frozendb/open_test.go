@@ -1,10 +1,14 @@
 package frozendb
 
 import (
+	"encoding/json"
+	"errors"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
 	"testing"
+	"time"
 )
 
 func createTestHeaderBytes() []byte {
@@ -253,3 +257,284 @@ func TestFileDescriptorLeaks(t *testing.T) {
 func countOpenFileDescriptors(t *testing.T) int {
 	return 0
 }
+
+func TestNewFrozenDB_WithoutLock(t *testing.T) {
+	path := setupCreate(t, t.TempDir(), 0)
+
+	first, err := NewFrozenDB(path, MODE_WRITE, FinderStrategySimple, WithoutLock())
+	if err != nil {
+		t.Fatalf("NewFrozenDB(WithoutLock): %v", err)
+	}
+	defer first.Close()
+
+	// No lock is held, so a second writer (locked or not) can open the file
+	second, err := NewFrozenDB(path, MODE_WRITE, FinderStrategySimple)
+	if err != nil {
+		t.Fatalf("NewFrozenDB with lock after unlocked writer: %v", err)
+	}
+
+	// The locked writer now excludes other locked writers, but not unlocked ones
+	var writeErr *WriteError
+	if _, err := NewFrozenDB(path, MODE_WRITE, FinderStrategySimple); !errors.As(err, &writeErr) {
+		t.Fatalf("expected WriteError while locked writer is open, got %v", err)
+	}
+	third, err := NewFrozenDB(path, MODE_WRITE, FinderStrategySimple, WithoutLock())
+	if err != nil {
+		t.Fatalf("NewFrozenDB(WithoutLock) while locked: %v", err)
+	}
+
+	// Closing an unlocked writer must not release the locked writer's flock
+	if err := third.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if _, err := NewFrozenDB(path, MODE_WRITE, FinderStrategySimple); !errors.As(err, &writeErr) {
+		t.Fatalf("expected WriteError after closing unlocked writer, got %v", err)
+	}
+	if err := second.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	// The header is still validated
+	invalid := filepath.Join(t.TempDir(), "invalid.fdb")
+	if err := os.WriteFile(invalid, make([]byte, HEADER_SIZE), 0644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	var corrupt *CorruptDatabaseError
+	if _, err := NewFrozenDB(invalid, MODE_WRITE, FinderStrategySimple, WithoutLock()); !errors.As(err, &corrupt) {
+		t.Fatalf("expected CorruptDatabaseError for invalid header, got %v", err)
+	}
+}
+
+func TestNewFrozenDB_WithLockTimeout(t *testing.T) {
+	path := setupCreate(t, t.TempDir(), 0)
+	holder, err := NewFrozenDB(path, MODE_WRITE, FinderStrategySimple)
+	if err != nil {
+		t.Fatalf("NewFrozenDB: %v", err)
+	}
+
+	// The lock stays held past the timeout
+	var writeErr *WriteError
+	start := time.Now()
+	if _, err := NewFrozenDB(path, MODE_WRITE, FinderStrategySimple, WithLockTimeout(50*time.Millisecond)); !errors.As(err, &writeErr) {
+		t.Fatalf("expected WriteError after the timeout, got %v", err)
+	}
+	if waited := time.Since(start); waited < 50*time.Millisecond {
+		t.Errorf("gave up after %s, before the 50ms timeout", waited)
+	}
+
+	// The holder commits and releases the lock while the second writer waits
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		tx, err := holder.BeginTx()
+		if err == nil {
+			_ = tx.AddRow(uuidFromTS(1000), json.RawMessage(`{"v":1}`))
+			_ = tx.Commit()
+		}
+		_ = holder.Close()
+	}()
+	waiter, err := NewFrozenDB(path, MODE_WRITE, FinderStrategySimple, WithLockTimeout(5*time.Second))
+	if err != nil {
+		t.Fatalf("NewFrozenDB with lock timeout: %v", err)
+	}
+	defer waiter.Close()
+
+	// The waiter sees the rows appended while it waited and can append after them
+	var value map[string]any
+	if err := waiter.Get(uuidFromTS(1000), &value); err != nil {
+		t.Fatalf("Get row committed while waiting: %v", err)
+	}
+	tx, err := waiter.BeginTx()
+	if err != nil {
+		t.Fatalf("BeginTx: %v", err)
+	}
+	mustAdd(t, tx, uuidFromTS(2000), `{"v":2}`)
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+	if _, err := Verify(path); err != nil {
+		t.Errorf("Verify: %v", err)
+	}
+}
+
+// TestNewFrozenDB_RowSizeMismatch pairs the header of one row_size with rows
+// written at another and requires the mismatch to be diagnosed on open.
+func TestNewFrozenDB_RowSizeMismatch(t *testing.T) {
+	build := func(rowSize int) []byte {
+		header := &Header{signature: HEADER_SIGNATURE, version: 1, rowSize: rowSize, skewMs: 5000}
+		return minimalTestDatabaseBytes(t, header)
+	}
+	tests := []struct {
+		name          string
+		headerSize    int
+		writtenSize   int
+		extraChecksum bool
+	}{
+		{name: "rows smaller than header", headerSize: 1024, writtenSize: 512, extraChecksum: true},
+		{name: "rows smaller, file shorter than one header row", headerSize: 1024, writtenSize: 512},
+		{name: "rows larger than header", headerSize: 512, writtenSize: 1024},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			written := build(tt.writtenSize)
+			data := append(build(tt.headerSize)[:HEADER_SIZE:HEADER_SIZE], written[HEADER_SIZE:]...)
+			if tt.extraChecksum {
+				data = append(data, written[HEADER_SIZE:]...)
+			}
+			path := filepath.Join(t.TempDir(), "mismatch.fdb")
+			if err := os.WriteFile(path, data, 0644); err != nil {
+				t.Fatalf("WriteFile: %v", err)
+			}
+
+			_, err := NewFrozenDB(path, MODE_READ, FinderStrategySimple)
+			var mismatch *RowSizeMismatchError
+			if !errors.As(err, &mismatch) {
+				t.Fatalf("expected RowSizeMismatchError, got %v", err)
+			}
+			if mismatch.HeaderRowSize != tt.headerSize || mismatch.ObservedRowSize != tt.writtenSize {
+				t.Errorf("sizes = (%d, %d), want (%d, %d)", mismatch.HeaderRowSize, mismatch.ObservedRowSize, tt.headerSize, tt.writtenSize)
+			}
+			var corrupt *CorruptDatabaseError
+			if !errors.As(err, &corrupt) {
+				t.Errorf("expected the error to wrap CorruptDatabaseError")
+			}
+		})
+	}
+
+	// A file cut off inside its checksum row is not a mismatch
+	path := filepath.Join(t.TempDir(), "short.fdb")
+	if err := os.WriteFile(path, build(1024)[:HEADER_SIZE+600], 0644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	var mismatch *RowSizeMismatchError
+	if _, err := NewFrozenDB(path, MODE_READ, FinderStrategySimple); err == nil || errors.As(err, &mismatch) {
+		t.Errorf("truncated checksum row: expected a corruption error other than a mismatch, got %v", err)
+	}
+}
+
+func TestNewFrozenDB_WithFutureGuard(t *testing.T) {
+	path := setupCreate(t, t.TempDir(), 5000)
+	now := int(time.Now().UnixMilli())
+	later := now + int(time.Hour.Milliseconds())
+
+	db, err := NewFrozenDB(path, MODE_WRITE, FinderStrategySimple, WithFutureGuard(true))
+	if err != nil {
+		t.Fatalf("NewFrozenDB: %v", err)
+	}
+	tx, err := db.BeginTx()
+	if err != nil {
+		t.Fatalf("BeginTx: %v", err)
+	}
+	mustAdd(t, tx, uuidFromTS(now), `{"v":1}`)
+	mustAdd(t, tx, uuidFromTS(now+1000), `{"v":2}`) // ahead, but within skew_ms
+
+	var future *FutureTimestampError
+	var invalidInput *InvalidInputError
+	err = tx.AddRow(uuidFromTS(later), json.RawMessage(`{"v":3}`))
+	if !errors.As(err, &future) || !errors.As(err, &invalidInput) {
+		t.Fatalf("expected FutureTimestampError wrapping InvalidInputError, got %v", err)
+	}
+	if future.Timestamp != int64(later) || future.Now < int64(now) {
+		t.Errorf("FutureTimestampError Timestamp = %d, Now = %d", future.Timestamp, future.Now)
+	}
+	if err := tx.Delete(uuidFromTS(later)); !errors.As(err, &future) {
+		t.Errorf("Delete: expected FutureTimestampError, got %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+	_ = db.Close()
+
+	// Without the guard the far-future key is accepted
+	db, err = NewFrozenDB(path, MODE_WRITE, FinderStrategySimple)
+	if err != nil {
+		t.Fatalf("NewFrozenDB: %v", err)
+	}
+	defer db.Close()
+	tx, err = db.BeginTx()
+	if err != nil {
+		t.Fatalf("BeginTx: %v", err)
+	}
+	mustAdd(t, tx, uuidFromTS(later), `{"v":3}`)
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+}
+
+func TestNewFrozenDB_WithFutureGuardRecoveredTx(t *testing.T) {
+	path := setupCreate(t, t.TempDir(), 5000)
+	now := int(time.Now().UnixMilli())
+	_, tx := openRecoveredTx(t, path, false, []OpenOption{WithFutureGuard(true)}, uuidFromTS(now))
+
+	// The transaction left open before the reopen applies the guard too
+	var future *FutureTimestampError
+	later := now + int(time.Hour.Milliseconds())
+	if err := tx.AddRow(uuidFromTS(later), json.RawMessage(`{"v":2}`)); !errors.As(err, &future) {
+		t.Errorf("AddRow on recovered transaction: expected FutureTimestampError, got %v", err)
+	}
+}
+
+func TestNewFrozenDB_WithWriteBuffer(t *testing.T) {
+	path := setupCreate(t, t.TempDir(), 0)
+	for i, tc := range []struct {
+		opts []OpenOption
+		want int
+	}{
+		{want: DEFAULT_WRITE_BUFFER},
+		{opts: []OpenOption{WithWriteBuffer(0)}, want: 1},
+		{opts: []OpenOption{WithWriteBuffer(1000)}, want: 1000},
+	} {
+		db, err := NewFrozenDB(path, MODE_WRITE, FinderStrategySimple, tc.opts...)
+		if err != nil {
+			t.Fatalf("NewFrozenDB: %v", err)
+		}
+		if got := db.file.(*FileManager).WriteBufferSize(); got != tc.want {
+			t.Errorf("WriteBufferSize() = %d, want %d", got, tc.want)
+		}
+
+		// Transactions write through a channel of that size
+		tx, err := db.BeginTx()
+		if err != nil {
+			t.Fatalf("BeginTx: %v", err)
+		}
+		mustAdd(t, tx, uuidFromTS(1000*(i+1)), `{"v":1}`)
+		if err := tx.Commit(); err != nil {
+			t.Fatalf("Commit: %v", err)
+		}
+		_ = db.Close()
+	}
+}
+
+func TestNewFrozenDB_SecondWriterInProcess(t *testing.T) {
+	path := setupCreate(t, t.TempDir(), 0)
+	first, err := NewFrozenDB(path, MODE_WRITE, FinderStrategySimple)
+	if err != nil {
+		t.Fatalf("NewFrozenDB: %v", err)
+	}
+
+	// The registry refuses the second writer, also through another path to the file
+	link := filepath.Join(t.TempDir(), "link.fdb")
+	if err := os.Symlink(path, link); err != nil {
+		t.Fatalf("Symlink: %v", err)
+	}
+	var writeErr *WriteError
+	for _, p := range []string{path, link} {
+		if _, err := NewFrozenDB(p, MODE_WRITE, FinderStrategySimple); !errors.As(err, &writeErr) || !strings.Contains(err.Error(), "in this process") {
+			t.Errorf("second writer via %s: expected in-process WriteError, got %v", p, err)
+		}
+	}
+
+	// Readers are unaffected, and Close releases the entry
+	reader, err := NewFrozenDB(path, MODE_READ, FinderStrategySimple)
+	if err != nil {
+		t.Fatalf("NewFrozenDB(MODE_READ): %v", err)
+	}
+	_ = reader.Close()
+	if err := first.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	second, err := NewFrozenDB(link, MODE_WRITE, FinderStrategySimple)
+	if err != nil {
+		t.Fatalf("NewFrozenDB after Close: %v", err)
+	}
+	_ = second.Close()
+}
@@ -54,6 +54,29 @@ func (pdr *PartialDataRow) GetState() PartialRowState {
 	return pdr.state
 }
 
+// GetStartControl returns the start_control byte, which is present in every state.
+func (pdr *PartialDataRow) GetStartControl() StartControl {
+	return pdr.d.StartControl
+}
+
+// GetKey returns the UUIDv7 key. The boolean is false in PartialDataRowWithStartControl,
+// where no payload has been written yet.
+func (pdr *PartialDataRow) GetKey() (uuid.UUID, bool) {
+	if pdr.state == PartialDataRowWithStartControl || pdr.d.RowPayload == nil {
+		return uuid.Nil, false
+	}
+	return pdr.d.RowPayload.Key, true
+}
+
+// GetValue returns the raw JSON value. The boolean is false in PartialDataRowWithStartControl,
+// where no payload has been written yet.
+func (pdr *PartialDataRow) GetValue() (json.RawMessage, bool) {
+	if pdr.state == PartialDataRowWithStartControl || pdr.d.RowPayload == nil {
+		return nil, false
+	}
+	return pdr.d.RowPayload.Value, true
+}
+
 func (pdr *PartialDataRow) AddRow(key uuid.UUID, json json.RawMessage) error {
 	if pdr.d.RowSize == -1 {
 		return NewInvalidActionError("RowSize is not set", nil)
@@ -361,6 +361,49 @@ func TestPartialDataRow_GetState(t *testing.T) {
 	}
 }
 
+func TestPartialDataRow_Accessors(t *testing.T) {
+	pdr, err := NewPartialDataRow(512, ROW_CONTINUE)
+	if err != nil {
+		t.Fatalf("NewPartialDataRow failed: %v", err)
+	}
+	if pdr.GetStartControl() != ROW_CONTINUE {
+		t.Errorf("Expected start control %c, got %c", ROW_CONTINUE, pdr.GetStartControl())
+	}
+	if _, ok := pdr.GetKey(); ok {
+		t.Error("Expected no key in PartialDataRowWithStartControl")
+	}
+	if _, ok := pdr.GetValue(); ok {
+		t.Error("Expected no value in PartialDataRowWithStartControl")
+	}
+
+	key := generateValidUUIDv7()
+	if err := pdr.AddRow(key, json.RawMessage(`{"name":"test"}`)); err != nil {
+		t.Fatalf("AddRow failed: %v", err)
+	}
+	if err := pdr.Savepoint(); err != nil {
+		t.Fatalf("Savepoint failed: %v", err)
+	}
+
+	// Fields survive a round trip through the on-disk bytes
+	text, err := pdr.MarshalText()
+	if err != nil {
+		t.Fatalf("MarshalText failed: %v", err)
+	}
+	parsed := &PartialDataRow{}
+	if err := parsed.UnmarshalText(text); err != nil {
+		t.Fatalf("UnmarshalText failed: %v", err)
+	}
+	if got, ok := parsed.GetKey(); !ok || got != key {
+		t.Errorf("Expected key %s, got %s (ok=%v)", key, got, ok)
+	}
+	if got, ok := parsed.GetValue(); !ok || string(got) != `{"name":"test"}` {
+		t.Errorf("Expected value {\"name\":\"test\"}, got %s (ok=%v)", got, ok)
+	}
+	if parsed.GetStartControl() != ROW_CONTINUE {
+		t.Errorf("Expected start control %c, got %c", ROW_CONTINUE, parsed.GetStartControl())
+	}
+}
+
 func TestPartialDataRow_SavepointFromState1_ShouldFail(t *testing.T) {
 
 	pdr, err := NewPartialDataRow(512, START_TRANSACTION)
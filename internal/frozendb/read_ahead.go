@@ -0,0 +1,68 @@
+package frozendb
+
+import "sync"
+
+// rowReadAhead serves a finder's single-row reads from a window of neighboring
+// rows fetched with one DBFile.Read, so that the rows a lookup visits close
+// together, such as the final steps of a binary search and the scans for
+// transaction boundaries, cost one read instead of one each. It works over any
+// DBFile, including one backed by an io.ReaderAt.
+//
+// Windows are aligned to multiples of window rows and hold only rows that are
+// complete in the file when read. Complete rows never change in an append-only
+// file, so a cached window stays valid as the file grows. Only the most recent
+// window is kept.
+type rowReadAhead struct {
+	dbFile  DBFile
+	rowSize int64
+	window  int64 // Rows read per miss; at most 1 disables read-ahead
+
+	mu    sync.Mutex
+	first int64  // Index of the first cached row
+	rows  []byte // Cached rows starting at first, a whole number of rows
+}
+
+// newRowReadAhead returns a rowReadAhead reading window rows of rowSize bytes at
+// a time from dbFile, capped at MAX_FINDER_READ_AHEAD_BYTES.
+func newRowReadAhead(dbFile DBFile, rowSize int32, window int) *rowReadAhead {
+	return &rowReadAhead{
+		dbFile:  dbFile,
+		rowSize: int64(rowSize),
+		window:  min(int64(window), MAX_FINDER_READ_AHEAD_BYTES/int64(rowSize)),
+	}
+}
+
+// readRow returns the bytes of the row at index. The returned slice must not be
+// modified.
+func (r *rowReadAhead) readRow(index int64) ([]byte, error) {
+	offset := HEADER_SIZE + index*r.rowSize
+	if r.window <= 1 {
+		return r.dbFile.Read(offset, int32(r.rowSize))
+	}
+
+	r.mu.Lock()
+	if index >= r.first && (index-r.first+1)*r.rowSize <= int64(len(r.rows)) {
+		start := (index - r.first) * r.rowSize
+		row := r.rows[start : start+r.rowSize : start+r.rowSize]
+		r.mu.Unlock()
+		return row, nil
+	}
+	r.mu.Unlock()
+
+	first := index - index%r.window
+	end := min(first+r.window, (r.dbFile.Size()-HEADER_SIZE)/r.rowSize)
+	if index >= end {
+		// The row is not complete yet; let the DBFile report it
+		return r.dbFile.Read(offset, int32(r.rowSize))
+	}
+	rows, err := r.dbFile.Read(HEADER_SIZE+first*r.rowSize, int32((end-first)*r.rowSize))
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	r.first, r.rows = first, rows
+	r.mu.Unlock()
+	start := (index - first) * r.rowSize
+	return rows[start : start+r.rowSize : start+r.rowSize], nil
+}
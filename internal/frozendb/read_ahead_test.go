@@ -0,0 +1,103 @@
+package frozendb
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync/atomic"
+	"testing"
+)
+
+// countingReaderAt counts the ReadAt calls made on an os.File.
+type countingReaderAt struct {
+	file  *os.File
+	reads atomic.Int64
+}
+
+func (c *countingReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	c.reads.Add(1)
+	return c.file.ReadAt(p, off)
+}
+
+func TestWithFinderReadAhead(t *testing.T) {
+	path := setupCreate(t, t.TempDir(), 5000)
+	const rows = 2000
+	for start := 0; start < rows; start += 100 {
+		writeTx(t, path, false, func(tx *Transaction) error {
+			for i := start; i < start+100; i++ {
+				if err := tx.AddRow(uuidFromTS(1000+i), json.RawMessage(fmt.Sprintf(`{"i":%d}`, i))); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		t.Fatalf("Stat: %v", err)
+	}
+
+	for _, strategy := range []FinderStrategy{FinderStrategySimple, FinderStrategyBinarySearch} {
+		t.Run(string(strategy), func(t *testing.T) {
+			lookupReads := func(opts ...OpenOption) int64 {
+				r := &countingReaderAt{file: file}
+				db, err := NewFrozenDBFromReaderAt(r, info.Size(), strategy, opts...)
+				if err != nil {
+					t.Fatalf("NewFrozenDBFromReaderAt: %v", err)
+				}
+				defer db.Close()
+				r.reads.Store(0)
+				for _, i := range []int{0, 777, 1999} {
+					var value map[string]int
+					if err := db.Get(uuidFromTS(1000+i), &value); err != nil || value["i"] != i {
+						t.Fatalf("Get(%d) = %v, %v", i, value, err)
+					}
+				}
+				return r.reads.Load()
+			}
+			plain := lookupReads()
+			ahead := lookupReads(WithFinderReadAhead(64))
+			if ahead >= plain {
+				t.Errorf("read-ahead lookups made %d reads, want fewer than %d", ahead, plain)
+			}
+			if disabled := lookupReads(WithFinderReadAhead(1)); disabled != plain {
+				t.Errorf("WithFinderReadAhead(1) made %d reads, want %d", disabled, plain)
+			}
+		})
+	}
+}
+
+func TestWithFinderReadAhead_FileGrows(t *testing.T) {
+	path := setupCreate(t, t.TempDir(), 5000)
+	db, err := NewFrozenDB(path, MODE_WRITE, FinderStrategyBinarySearch, WithFinderReadAhead(64))
+	if err != nil {
+		t.Fatalf("NewFrozenDB: %v", err)
+	}
+	defer db.Close()
+
+	// Each lookup caches the partial window at the end of the file, which the
+	// next commit extends
+	for i := 0; i < 5; i++ {
+		tx, err := db.BeginTx()
+		if err != nil {
+			t.Fatalf("BeginTx: %v", err)
+		}
+		if err := tx.AddRow(uuidFromTS(1000+i), json.RawMessage(fmt.Sprintf(`{"i":%d}`, i))); err != nil {
+			t.Fatalf("AddRow: %v", err)
+		}
+		if err := tx.Commit(); err != nil {
+			t.Fatalf("Commit: %v", err)
+		}
+		for j := 0; j <= i; j++ {
+			var value map[string]int
+			if err := db.Get(uuidFromTS(1000+j), &value); err != nil || value["i"] != j {
+				t.Fatalf("after commit %d: Get(%d) = %v, %v", i, j, value, err)
+			}
+		}
+	}
+}
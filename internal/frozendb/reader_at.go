@@ -0,0 +1,115 @@
+package frozendb
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sync/atomic"
+)
+
+// readerAtFile is a read-only DBFile over an io.ReaderAt of fixed size, such as
+// an HTTPReaderAt or a bytes.Reader. The size never changes, so subscribers are
+// never called.
+type readerAtFile struct {
+	r      io.ReaderAt
+	size   int64
+	closed atomic.Bool
+}
+
+// NewFrozenDBFromReaderAt opens a read-only database whose bytes are served by r,
+// for files that are not on the local filesystem, such as an HTTPReaderAt for a
+// database hosted behind a CDN. The first size bytes of r are the database; rows
+// appended to the source later are not seen.
+//
+// Every Read becomes a ReadAt on r, so pick a finder that reads little:
+// FinderStrategyBinarySearch opens by reading the file tail and looks keys up
+// with O(log n) reads, while FinderStrategySimple and FinderStrategyInMemory read
+// the whole file.
+//
+// Parameters:
+//   - r: Source of the database bytes; closed by Close if it implements io.Closer
+//   - size: Size of the database in bytes
+//   - strategy: Finder strategy, as for NewFrozenDB
+//   - opts: Optional OpenOption values, such as WithLogger
+//
+// Returns:
+//   - *FrozenDB: Read-only database instance
+//   - error: InvalidInputError (nil r, negative size, or invalid strategy),
+//     CorruptDatabaseError, or ReadError
+func NewFrozenDBFromReaderAt(r io.ReaderAt, size int64, strategy FinderStrategy, opts ...OpenOption) (*FrozenDB, error) {
+	if r == nil {
+		return nil, NewInvalidInputError("reader cannot be nil", nil)
+	}
+	if size < 0 {
+		return nil, NewInvalidInputError(fmt.Sprintf("size must be non-negative, got %d", size), nil)
+	}
+	return NewFrozenDBWithFile(&readerAtFile{r: r, size: size}, strategy, opts...)
+}
+
+func (f *readerAtFile) Read(start int64, size int32) ([]byte, error) {
+	if size <= 0 {
+		return nil, NewInvalidInputError("size must be positive", nil)
+	}
+	data := make([]byte, size)
+	if err := f.ReadInto(data, start); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// ReadInto fills dst with the len(dst) bytes starting at offset start.
+func (f *readerAtFile) ReadInto(dst []byte, start int64) error {
+	if start < 0 {
+		return NewInvalidInputError("start offset cannot be negative", nil)
+	}
+	if len(dst) == 0 || len(dst) > math.MaxInt32 {
+		return NewInvalidInputError("size must be positive", nil)
+	}
+	if f.closed.Load() {
+		return NewTombstonedError("reader is closed", nil)
+	}
+	if start+int64(len(dst)) > f.size {
+		return newReadPastEndError(start, len(dst), f.size)
+	}
+	n, err := f.r.ReadAt(dst, start)
+	if n == len(dst) {
+		return nil
+	}
+	if err == nil || errors.Is(err, io.EOF) {
+		err = io.ErrUnexpectedEOF
+	}
+	return NewReadError(fmt.Sprintf("failed to read %d bytes at offset %d", len(dst), start), err)
+}
+
+func (f *readerAtFile) Size() int64 {
+	return f.size
+}
+
+func (f *readerAtFile) Close() error {
+	if f.closed.Swap(true) {
+		return nil
+	}
+	if closer, ok := f.r.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+func (f *readerAtFile) SetWriter(dataChan <-chan Data) error {
+	return NewInvalidActionError("cannot set writer on read-mode DBFile", nil)
+}
+
+func (f *readerAtFile) GetMode() string {
+	return MODE_READ
+}
+
+func (f *readerAtFile) WriterClosed() {}
+
+// Subscribe accepts callback but never calls it: the file does not grow.
+func (f *readerAtFile) Subscribe(callback func() error) (func() error, error) {
+	if callback == nil {
+		return nil, NewInvalidInputError("callback cannot be nil", nil)
+	}
+	return func() error { return nil }, nil
+}
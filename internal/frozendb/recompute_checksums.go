@@ -0,0 +1,155 @@
+package frozendb
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+)
+
+// RecomputeChecksums writes a copy of the database at src to dst with its
+// checksum rows rebuilt: every checksum row of src is dropped and fresh ones are
+// written at the checksum_interval boundaries of the header, with recomputed
+// CRC32 values. It repairs files whose checksum rows are missing, misplaced, or
+// stale without touching the data.
+//
+// The header and every data and null row are copied byte for byte, including
+// rolled-back transactions, so unlike a compaction the copy keeps the full
+// history. Each of those rows is validated while it is copied: framing, control
+// bytes, parity, and the payload must parse, and transactions must begin and
+// continue with the right start_control. Checksum rows are recognized by their
+// control bytes alone, so damaged ones are replaced as well; the metadata in
+// the initial checksum row of a version 2 file is carried over. Rows of a
+// transaction that is still open at the end of src, and a trailing partial row,
+// are not copied.
+//
+// dst is created with O_EXCL and is removed again if the copy fails. It is not
+// marked append-only; use the CLI or chattr for that once it is verified.
+//
+// Parameters:
+//   - src: Path of the database to repair (it is only read)
+//   - dst: Path of the file to write (must not exist)
+//
+// Returns:
+//   - error: PathError (src cannot be opened or dst cannot be created),
+//     CorruptDatabaseError (a header or row of src is invalid), ReadError, or WriteError
+func RecomputeChecksums(src, dst string) (err error) {
+	in, err := os.Open(src)
+	if err != nil {
+		return NewPathError("failed to open source database", err)
+	}
+	defer func() { _ = in.Close() }()
+	r := bufio.NewReader(in)
+
+	headerBytes := make([]byte, HEADER_SIZE)
+	if _, err := io.ReadFull(r, headerBytes); err != nil {
+		return NewCorruptDatabaseError("failed to read header", err)
+	}
+	header := &Header{}
+	if err := header.UnmarshalText(headerBytes); err != nil {
+		return NewCorruptDatabaseError("invalid header", err)
+	}
+	// The extension of a version 2 file's initial checksum row is carried over
+	// even when the rest of that row is damaged
+	if header.GetVersion() != FORMAT_VERSION {
+		initial := make([]byte, header.GetRowSize())
+		if _, err := in.ReadAt(initial, HEADER_SIZE); err != nil {
+			return NewCorruptDatabaseError("failed to read initial checksum row", err)
+		}
+		if initial[1] == byte(CHECKSUM_ROW) && initial[10] != NULL_BYTE {
+			extension, _, _ := bytes.Cut(initial[10:len(initial)-5], []byte{NULL_BYTE})
+			if err := validateChecksumExtension(extension); err != nil {
+				return NewCorruptDatabaseError("invalid initial checksum row extension", err)
+			}
+			if err := header.applyExtension(extension); err != nil {
+				return err
+			}
+		}
+	}
+
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_EXCL|os.O_WRONLY, FILE_PERMISSIONS)
+	if err != nil {
+		return NewPathError("failed to create destination file", err)
+	}
+	defer func() {
+		if closeErr := out.Close(); closeErr != nil && err == nil {
+			err = NewWriteError("failed to close destination file", closeErr)
+		}
+		if err != nil {
+			_ = os.Remove(dst)
+		}
+	}()
+	w := bufio.NewWriter(out)
+
+	if err := copyWithChecksums(r, w, header, headerBytes); err != nil {
+		return err
+	}
+	if err := w.Flush(); err != nil {
+		return NewWriteError("failed to write destination file", err)
+	}
+	if err := out.Sync(); err != nil {
+		return NewWriteError("failed to sync destination file", err)
+	}
+	return nil
+}
+
+// copyWithChecksums writes headerBytes and a fresh initial checksum row,
+// carrying the extension of header, to w,
+// then copies the rows of every ended transaction read from r, leaving out the
+// checksum rows of r and inserting new ones through a databaseBuilder.
+func copyWithChecksums(r io.Reader, w io.Writer, header *Header, headerBytes []byte) error {
+	rowSize := header.GetRowSize()
+	checksumRow, err := newInitialChecksumRow(header, headerBytes)
+	if err != nil {
+		return NewWriteError("failed to create checksum row", err)
+	}
+	checksumBytes, err := checksumRow.MarshalText()
+	if err != nil {
+		return NewWriteError("failed to marshal checksum row", err)
+	}
+	if _, err := w.Write(headerBytes); err != nil {
+		return NewWriteError("failed to write header", err)
+	}
+	b := &databaseBuilder{w: w, header: header, crc: crc32.NewIEEE()}
+	if err := b.writeRow(checksumBytes); err != nil {
+		return err
+	}
+
+	var pending [][]byte // Rows of the transaction that has not ended yet
+	for index := int64(0); ; index++ {
+		row := make([]byte, rowSize)
+		if _, err := io.ReadFull(r, row); err != nil {
+			if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+				return nil
+			}
+			return NewReadError(fmt.Sprintf("failed to read row %d", index), err)
+		}
+
+		start, end := StartControl(row[1]), EndControl{row[rowSize-5], row[rowSize-4]}
+		if row[0] == ROW_START && start == CHECKSUM_ROW && end == CHECKSUM_ROW_CONTROL {
+			continue
+		}
+		var ru RowUnion
+		if err := ru.UnmarshalText(row); err != nil {
+			return NewCorruptDatabaseError(fmt.Sprintf("row %d is invalid", index), err)
+		}
+		if (start == START_TRANSACTION) != (len(pending) == 0) {
+			return NewCorruptDatabaseError(fmt.Sprintf("row %d has start_control %q out of transaction sequence", index, start), nil)
+		}
+		pending = append(pending, row)
+
+		second := end[1]
+		if end != NULL_ROW_CONTROL && second != 'C' && (second < '0' || second > '9') {
+			continue
+		}
+		for _, txRow := range pending {
+			if err := b.writeDataRow(txRow); err != nil {
+				return err
+			}
+		}
+		pending = pending[:0]
+	}
+}
@@ -0,0 +1,148 @@
+package frozendb
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestRecomputeChecksums removes or corrupts the checksum rows of a database and
+// requires the repaired copy to match the file the write path produced.
+func TestRecomputeChecksums(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "good.fdb")
+	createWithInterval(t, path, MIN_CHECKSUM_INTERVAL)
+
+	db, err := NewFrozenDB(path, MODE_WRITE, FinderStrategySimple)
+	if err != nil {
+		t.Fatalf("NewFrozenDB: %v", err)
+	}
+	ts := 1000
+	for txNum := 0; txNum < 8; txNum++ {
+		tx, err := db.BeginTx()
+		if err != nil {
+			t.Fatalf("BeginTx: %v", err)
+		}
+		for i := 0; i < 30; i++ {
+			ts++
+			mustAdd(t, tx, uuidFromTS(ts), fmt.Sprintf(`{"n":%d}`, ts))
+		}
+		if txNum%3 == 1 {
+			err = tx.Rollback(0)
+		} else {
+			err = tx.Commit()
+		}
+		if err != nil {
+			t.Fatalf("ending transaction: %v", err)
+		}
+	}
+	good, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	// An open transaction at the end is not copied
+	tx, err := db.BeginTx()
+	if err != nil {
+		t.Fatalf("BeginTx: %v", err)
+	}
+	mustAdd(t, tx, uuidFromTS(ts+1), `{"open":true}`)
+	mustAdd(t, tx, uuidFromTS(ts+2), `{"open":true}`)
+	written, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	db.Close()
+
+	rowSize := confRowSize
+	rows := (len(written) - HEADER_SIZE) / rowSize
+	var stripped, stale []byte
+	stripped = append(stripped, written[:HEADER_SIZE]...)
+	stale = append(stale, written...)
+	for i := 0; i < rows; i++ {
+		row := written[HEADER_SIZE+i*rowSize : HEADER_SIZE+(i+1)*rowSize]
+		if row[1] != byte(CHECKSUM_ROW) {
+			stripped = append(stripped, row...)
+			continue
+		}
+		// Overwrite the CRC of every checksum row, breaking its parity too
+		copy(stale[HEADER_SIZE+i*rowSize+2:], "AAAAAA==")
+	}
+	stripped = append(stripped, written[HEADER_SIZE+rows*rowSize:]...)
+
+	for name, src := range map[string][]byte{"stripped": stripped, "stale": stale, "good": written} {
+		t.Run(name, func(t *testing.T) {
+			srcPath := filepath.Join(dir, name+".src.fdb")
+			dstPath := filepath.Join(dir, name+".dst.fdb")
+			if err := os.WriteFile(srcPath, src, 0644); err != nil {
+				t.Fatalf("WriteFile: %v", err)
+			}
+			if err := RecomputeChecksums(srcPath, dstPath); err != nil {
+				t.Fatalf("RecomputeChecksums: %v", err)
+			}
+			repaired, err := os.ReadFile(dstPath)
+			if err != nil {
+				t.Fatalf("ReadFile: %v", err)
+			}
+			if !bytes.Equal(repaired, good) {
+				t.Errorf("repaired file (%d bytes) differs from the written file (%d bytes)", len(repaired), len(good))
+			}
+			if report, err := Verify(dstPath); err != nil || report.ProblemCount != 0 {
+				t.Errorf("Verify = %+v, %v", report, err)
+			}
+		})
+	}
+
+	var pathErr *PathError
+	if err := RecomputeChecksums(path, path); !errors.As(err, &pathErr) {
+		t.Errorf("existing dst: expected PathError, got %v", err)
+	}
+}
+
+func TestRecomputeChecksums_InvalidRows(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "db.fdb")
+	config := NewCreateConfig(path, confRowSize, confSkewMs)
+	var buf bytes.Buffer
+	entries := []Entry{{Key: uuidFromTS(1000), Value: json.RawMessage(`{"a":1}`)}, {Key: uuidFromTS(1001), Value: json.RawMessage(`{"a":2}`)}}
+	if err := BuildDatabase(&buf, config, entries); err != nil {
+		t.Fatalf("BuildDatabase: %v", err)
+	}
+	second := HEADER_SIZE + 2*confRowSize
+
+	corruptions := map[string]func(data []byte){
+		// Parity no longer matches the payload
+		"parity": func(data []byte) { data[HEADER_SIZE+confRowSize+40] ^= 0x01 },
+		// A transaction continues a row that never began one
+		"sequence": func(data []byte) {
+			row := data[second : second+confRowSize]
+			row[1] = byte(START_TRANSACTION)
+			var parity byte
+			for _, b := range row[:confRowSize-3] {
+				parity ^= b
+			}
+			copy(row[confRowSize-3:], fmt.Sprintf("%02X", parity))
+		},
+	}
+	for name, corrupt := range corruptions {
+		t.Run(name, func(t *testing.T) {
+			data := bytes.Clone(buf.Bytes())
+			corrupt(data)
+			srcPath := filepath.Join(dir, name+".fdb")
+			dstPath := filepath.Join(dir, name+".out.fdb")
+			if err := os.WriteFile(srcPath, data, 0644); err != nil {
+				t.Fatalf("WriteFile: %v", err)
+			}
+			var corruptErr *CorruptDatabaseError
+			if err := RecomputeChecksums(srcPath, dstPath); !errors.As(err, &corruptErr) {
+				t.Fatalf("expected CorruptDatabaseError, got %v", err)
+			}
+			if _, err := os.Stat(dstPath); !os.IsNotExist(err) {
+				t.Errorf("dst should be removed after a failed copy, Stat: %v", err)
+			}
+		})
+	}
+}
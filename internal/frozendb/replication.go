@@ -0,0 +1,323 @@
+package frozendb
+
+import (
+	"errors"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"io"
+)
+
+// replicationSink wraps the io.Writer stored in FileManager.sink so that the
+// atomic.Value always holds the same concrete type (w is nil when detached).
+type replicationSink struct {
+	w io.Writer
+}
+
+// replicationReadSize is the buffer size ApplyReplicationStream reads with.
+const replicationReadSize = 32 * 1024
+
+// replicator is implemented by DBFiles that can forward appended bytes to a
+// replication sink (FileManager does in write mode).
+type replicator interface {
+	SetReplicationSink(w io.Writer) error
+}
+
+// SetReplicationSink forwards every byte appended by the writer to w, in order,
+// after it has been written to the file. Passing nil detaches the sink.
+//
+// A failed write to w detaches the sink and is logged; it does not fail the
+// append, because the bytes are already in the file.
+func (fm *FileManager) SetReplicationSink(w io.Writer) error {
+	if fm.mode != MODE_WRITE {
+		return NewInvalidActionError("cannot set replication sink on read-mode DBFile", nil)
+	}
+	fm.sink.Store(replicationSink{w: w})
+	return nil
+}
+
+// replicate writes bytes just appended to the file to the replication sink, if any.
+func (fm *FileManager) replicate(bytes []byte) {
+	sink, _ := fm.sink.Load().(replicationSink)
+	if sink.w == nil {
+		return
+	}
+	if _, err := sink.w.Write(bytes); err != nil {
+		fm.sink.CompareAndSwap(sink, replicationSink{})
+		loggerOrNop(fm.logger).Warnf("frozendb: replication sink for %s detached after write failure: %v", fm.path, err)
+	}
+}
+
+// SetReplicationSink forwards every byte this handle appends to the file to w, so
+// that a standby can mirror the database by passing the stream to
+// ApplyReplicationStream. Since the file is append-only, the stream is exactly the
+// bytes added to the file after the call.
+//
+// The standby file must be a byte-for-byte copy of this file at the moment the sink
+// is set, so set it before beginning a transaction. Bytes are forwarded as they are
+// written, including partial rows of an open transaction. A failed write to w
+// detaches the sink and is logged as a warning; writes to the database continue.
+// Passing nil detaches the sink.
+//
+// Returns:
+//   - error: InvalidActionError if the database is not open in MODE_WRITE
+func (db *FrozenDB) SetReplicationSink(w io.Writer) error {
+	r, ok := db.file.(replicator)
+	if !ok || db.file.GetMode() != MODE_WRITE {
+		return NewInvalidActionError("replication sink requires a database opened in write mode", nil)
+	}
+	return r.SetReplicationSink(w)
+}
+
+// ApplyReplicationStream appends the bytes read from r to the standby database file
+// at path until r returns io.EOF. The stream is what a primary forwards to its
+// replication sink (see FrozenDB.SetReplicationSink), and the file must hold the
+// primary's bytes up to the point the stream starts.
+//
+// The file is opened in MODE_WRITE, so the call holds the exclusive lock and no
+// other writer can interleave. Readers can open the file in MODE_READ while it is
+// applied and see new transactions as they commit. Every row is checked before it
+// is appended: complete rows must parse with valid framing and parity, rows at
+// checksum positions must be checksum rows matching the bytes they cover, and a
+// trailing partial row is only appended in one of the states a writer produces.
+// Bytes of a row that is not yet complete are held until the rest arrives.
+//
+// Parameters:
+//   - path: Filesystem path to the standby database file
+//   - r: Replication stream from the primary
+//   - opts: Optional OpenOption values, such as WithoutLock or WithLogger
+//
+// Returns:
+//   - error: nil when r is exhausted on a state the writer could have produced;
+//     CorruptDatabaseError if a row fails validation or the stream ends within a
+//     row; ReadError if r fails; PathError or WriteError from opening or appending
+func ApplyReplicationStream(path string, r io.Reader, opts ...OpenOption) error {
+	dbFile, err := newDBFile(path, MODE_WRITE, newOpenOptions(opts))
+	if err != nil {
+		return err
+	}
+	defer func() { _ = dbFile.Close() }()
+
+	header, err := validateDatabaseFile(dbFile)
+	if err != nil {
+		return err
+	}
+	return applyStream(dbFile, header, r)
+}
+
+// applyStream validates and appends the bytes read from r to dbFile, opened in
+// MODE_WRITE, until r returns io.EOF.
+func applyStream(dbFile DBFile, header *Header, r io.Reader) error {
+	applier, err := newReplicaApplier(dbFile, header)
+	if err != nil {
+		return err
+	}
+	return applier.run(dbFile, r)
+}
+
+// run appends the bytes read from r to dbFile, the file the applier was created
+// for, until r returns io.EOF.
+func (a *replicaApplier) run(dbFile DBFile, r io.Reader) error {
+	writeChan := make(chan Data)
+	if err := dbFile.SetWriter(writeChan); err != nil {
+		return err
+	}
+	a.writeChan = writeChan
+	defer func() {
+		close(writeChan)
+		dbFile.WriterClosed()
+	}()
+
+	buf := make([]byte, replicationReadSize)
+	for {
+		n, readErr := r.Read(buf)
+		if n > 0 {
+			if err := a.apply(buf[:n]); err != nil {
+				return err
+			}
+		}
+		if errors.Is(readErr, io.EOF) {
+			return a.finish()
+		}
+		if readErr != nil {
+			return NewReadError("failed to read replication stream", readErr)
+		}
+	}
+}
+
+// replicaApplier validates and appends a replication stream row by row.
+type replicaApplier struct {
+	header    *Header
+	rowSize   int
+	writeChan chan<- Data
+
+	// Row being assembled: pending holds its bytes so far, of which the first
+	// written are already in the file
+	index   int64 // Index of the row being assembled (row 0 is the initial checksum row)
+	pending []byte
+	written int
+
+	// CRC32 of the bytes from the most recent checksum row up to the row being assembled
+	crc hash.Hash32
+
+	// When srcCRC is set, checksum rows are regenerated rather than copied: each
+	// incoming checksum row must match srcCRC, the CRC32 of the incoming bytes it
+	// covers, and is replaced by the checksum of the bytes written (CopyRows)
+	srcCRC hash.Hash32
+}
+
+// newReplicaApplier positions an applier at the end of dbFile, picking up a
+// trailing partial row and the checksum state of the rows since the last checksum row.
+func newReplicaApplier(dbFile DBFile, header *Header) (*replicaApplier, error) {
+	rowSize := header.GetRowSize()
+	size := dbFile.Size()
+	completeRows := (size - HEADER_SIZE) / int64(rowSize)
+	rowsEnd := HEADER_SIZE + completeRows*int64(rowSize)
+
+	a := &replicaApplier{
+		header:  header,
+		rowSize: rowSize,
+		index:   completeRows,
+		crc:     crc32.NewIEEE(),
+	}
+
+	if size > rowsEnd {
+		tail, err := dbFile.Read(rowsEnd, int32(size-rowsEnd))
+		if err != nil {
+			return nil, NewReadError("failed to read trailing partial row", err)
+		}
+		a.pending = tail
+		a.written = len(tail)
+	}
+
+	// The next checksum row covers the file from the last checksum row onwards
+	block := int64(header.GetChecksumInterval() + 1)
+	lastChecksum := ((completeRows - 1) / block) * block
+	for start := HEADER_SIZE + lastChecksum*int64(rowSize); start < rowsEnd; {
+		chunk := min(rowsEnd-start, int64(replicationReadSize))
+		data, err := dbFile.Read(start, int32(chunk))
+		if err != nil {
+			return nil, NewReadError("failed to read rows since the last checksum row", err)
+		}
+		a.crc.Write(data)
+		start += chunk
+	}
+	return a, nil
+}
+
+// apply validates and appends every row completed by data. Remaining bytes are
+// appended only once they form a partial row in a state a writer produces.
+func (a *replicaApplier) apply(data []byte) error {
+	a.pending = append(a.pending, data...)
+	for len(a.pending) >= a.rowSize {
+		row := a.pending[:a.rowSize]
+		out, err := a.checkRow(row)
+		if err != nil {
+			return err
+		}
+		if err := a.write(out[a.written:]); err != nil {
+			return err
+		}
+		a.crc.Write(out)
+		if a.srcCRC != nil {
+			a.srcCRC.Write(row)
+		}
+		a.pending = a.pending[a.rowSize:]
+		a.written = 0
+		a.index++
+	}
+	a.pending = append([]byte(nil), a.pending...)
+
+	if len(a.pending) > a.written && a.isPartialRow(a.pending) {
+		if err := a.write(a.pending[a.written:]); err != nil {
+			return err
+		}
+		a.written = len(a.pending)
+	}
+	return nil
+}
+
+// finish reports an error if the stream ended within a row.
+func (a *replicaApplier) finish() error {
+	if len(a.pending) > a.written {
+		return NewCorruptDatabaseError(
+			fmt.Sprintf("replication stream ended within row %d (%d of %d bytes received)", a.index, len(a.pending), a.rowSize),
+			nil,
+		)
+	}
+	return nil
+}
+
+// checkRow validates a complete row at a.index, returning the bytes to append:
+// the row itself, or a regenerated checksum row when a.srcCRC is set.
+func (a *replicaApplier) checkRow(row []byte) ([]byte, error) {
+	offset := HEADER_SIZE + a.index*int64(a.rowSize)
+	var ru RowUnion
+	if err := ru.UnmarshalText(row); err != nil {
+		return nil, NewCorruptDatabaseError(fmt.Sprintf("invalid row in replication stream at offset %d: %v", offset, err), err)
+	}
+
+	isChecksumPosition := a.index%int64(a.header.GetChecksumInterval()+1) == 0
+	if isChecksumPosition != (ru.ChecksumRow != nil) {
+		return nil, NewCorruptDatabaseError(fmt.Sprintf("misplaced row in replication stream at offset %d: checksum rows must be at every %d rows", offset, a.header.GetChecksumInterval()+1), nil)
+	}
+	if ru.ChecksumRow == nil {
+		return row, nil
+	}
+
+	incoming := a.crc
+	if a.srcCRC != nil {
+		incoming = a.srcCRC
+	}
+	expected := incoming.Sum32()
+	if Checksum(expected) != *ru.ChecksumRow.RowPayload {
+		return nil, NewCorruptDatabaseError(
+			fmt.Sprintf("checksum mismatch in replication stream at offset %d (expected %08X, got %08X)",
+				offset, expected, *ru.ChecksumRow.RowPayload),
+			nil,
+		)
+	}
+	incoming.Reset()
+	if a.srcCRC == nil {
+		return row, nil
+	}
+
+	checksum := Checksum(a.crc.Sum32())
+	a.crc.Reset()
+	checksumRow := &ChecksumRow{
+		baseRow: baseRow[*Checksum]{
+			RowSize:      a.rowSize,
+			StartControl: CHECKSUM_ROW,
+			EndControl:   CHECKSUM_ROW_CONTROL,
+			RowPayload:   &checksum,
+		},
+	}
+	rewritten, err := checksumRow.MarshalText()
+	if err != nil {
+		return nil, NewWriteError("failed to marshal checksum row", err)
+	}
+	return rewritten, nil
+}
+
+// isPartialRow reports whether b is a data row cut off where a writer pauses:
+// after start_control, after the payload, or after a savepoint marker.
+func (a *replicaApplier) isPartialRow(b []byte) bool {
+	if a.index%int64(a.header.GetChecksumInterval()+1) == 0 {
+		return false
+	}
+	if !isPartialRowLength(len(b), a.rowSize) {
+		return false
+	}
+	var partialRow PartialDataRow
+	return partialRow.UnmarshalText(b) == nil
+}
+
+// write appends bytes to the file through the writer goroutine.
+func (a *replicaApplier) write(bytes []byte) error {
+	if len(bytes) == 0 {
+		return nil
+	}
+	response := make(chan error, 1)
+	a.writeChan <- Data{Bytes: bytes, Response: response}
+	return <-response
+}
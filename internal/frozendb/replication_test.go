@@ -0,0 +1,181 @@
+package frozendb
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// setupReplicaPair creates a primary database with the smallest checksum interval
+// and a byte-for-byte copy of it to act as the standby.
+func setupReplicaPair(t *testing.T) (primary, replica string) {
+	t.Helper()
+	dir := t.TempDir()
+	primary = filepath.Join(dir, "primary.fdb")
+	replica = filepath.Join(dir, "replica.fdb")
+	setupMockSyscalls(false, false)
+	t.Cleanup(restoreRealSyscalls)
+	t.Setenv("SUDO_USER", MOCK_USER)
+	t.Setenv("SUDO_UID", MOCK_UID)
+	t.Setenv("SUDO_GID", MOCK_GID)
+	config := NewCreateConfig(primary, confRowSize, confSkewMs)
+	config.SetChecksumInterval(MIN_CHECKSUM_INTERVAL)
+	if err := Create(config); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	data, err := os.ReadFile(primary)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	if err := os.WriteFile(replica, data, 0644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	return primary, replica
+}
+
+// replicateRows commits one transaction per batch of keys, leaves a final
+// transaction open with a savepoint on its partial row, and returns the handle.
+func replicateRows(t *testing.T, path string, sink io.Writer, batches [][]int) *FrozenDB {
+	t.Helper()
+	db, err := NewFrozenDB(path, MODE_WRITE, FinderStrategySimple)
+	if err != nil {
+		t.Fatalf("NewFrozenDB: %v", err)
+	}
+	if err := db.SetReplicationSink(sink); err != nil {
+		t.Fatalf("SetReplicationSink: %v", err)
+	}
+	for i, batch := range batches {
+		tx, err := db.BeginTx()
+		if err != nil {
+			t.Fatalf("BeginTx: %v", err)
+		}
+		for _, ts := range batch {
+			mustAdd(t, tx, uuidFromTS(ts), `{"v":1}`)
+		}
+		if i == len(batches)-1 {
+			if err := tx.Savepoint(); err != nil {
+				t.Fatalf("Savepoint: %v", err)
+			}
+			continue
+		}
+		if err := tx.Commit(); err != nil {
+			t.Fatalf("Commit: %v", err)
+		}
+	}
+	return db
+}
+
+func TestReplication_MirrorsTransactions(t *testing.T) {
+	primary, replica := setupReplicaPair(t)
+
+	pr, pw := io.Pipe()
+	applied := make(chan error, 1)
+	go func() { applied <- ApplyReplicationStream(replica, pr) }()
+
+	// 150 rows in the committed transactions cross a checksum row
+	var committed []int
+	batches := [][]int{{}, {}, {9000, 9001}}
+	for ts := 1000; ts < 1150; ts++ {
+		batches[(ts-1000)/100] = append(batches[(ts-1000)/100], ts)
+		committed = append(committed, ts)
+	}
+	db := replicateRows(t, primary, pw, batches)
+	defer db.Close()
+	_ = pw.Close()
+	if err := <-applied; err != nil {
+		t.Fatalf("ApplyReplicationStream: %v", err)
+	}
+
+	primaryBytes, _ := os.ReadFile(primary)
+	replicaBytes, _ := os.ReadFile(replica)
+	if !bytes.Equal(primaryBytes, replicaBytes) {
+		t.Fatalf("replica (%d bytes) differs from primary (%d bytes)", len(replicaBytes), len(primaryBytes))
+	}
+	if _, err := Verify(replica); err != nil {
+		t.Errorf("Verify replica: %v", err)
+	}
+
+	standby, err := NewFrozenDB(replica, MODE_READ, FinderStrategyInMemory)
+	if err != nil {
+		t.Fatalf("NewFrozenDB replica: %v", err)
+	}
+	defer standby.Close()
+	var value map[string]any
+	for _, ts := range committed {
+		if err := standby.Get(uuidFromTS(ts), &value); err != nil {
+			t.Fatalf("Get %d on replica: %v", ts, err)
+		}
+	}
+	var active *TransactionActiveError
+	if err := standby.Get(uuidFromTS(9000), &value); !errors.As(err, &active) {
+		t.Errorf("Get key of open transaction: expected TransactionActiveError, got %v", err)
+	}
+
+	// The stream resumes on the partial row left by the open transaction
+	var resumed bytes.Buffer
+	if err := db.SetReplicationSink(&resumed); err != nil {
+		t.Fatalf("SetReplicationSink: %v", err)
+	}
+	if err := db.GetActiveTx().Commit(); err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+	if err := ApplyReplicationStream(replica, &resumed); err != nil {
+		t.Fatalf("ApplyReplicationStream resumed: %v", err)
+	}
+	if err := standby.Get(uuidFromTS(9001), &value); err != nil {
+		t.Errorf("Get after commit on replica: %v", err)
+	}
+}
+
+func TestApplyReplicationStream_ValidatesRows(t *testing.T) {
+	primary, replica := setupReplicaPair(t)
+	var stream bytes.Buffer
+	db := replicateRows(t, primary, &stream, [][]int{{1000, 1001, 1002}, {2000}})
+	_ = db.Close()
+	replicaSize := int64(HEADER_SIZE + confRowSize)
+
+	var corrupt *CorruptDatabaseError
+
+	// A bad parity byte in the second row stops the stream after the first row
+	bad := bytes.Clone(stream.Bytes())
+	bad[2*confRowSize-3] ^= 0x01
+	if err := ApplyReplicationStream(replica, bytes.NewReader(bad)); !errors.As(err, &corrupt) {
+		t.Fatalf("expected CorruptDatabaseError for bad parity, got %v", err)
+	}
+	if info, _ := os.Stat(replica); info.Size() != replicaSize+confRowSize {
+		t.Errorf("replica size = %d, want %d", info.Size(), replicaSize+confRowSize)
+	}
+
+	// A stream cut within a row applies what came before and reports the cut
+	if err := ApplyReplicationStream(replica, bytes.NewReader(stream.Bytes()[confRowSize:2*confRowSize+10])); !errors.As(err, &corrupt) {
+		t.Fatalf("expected CorruptDatabaseError for truncated stream, got %v", err)
+	}
+	if info, _ := os.Stat(replica); info.Size() != replicaSize+2*confRowSize {
+		t.Errorf("replica size = %d, want %d", info.Size(), replicaSize+2*confRowSize)
+	}
+
+	if err := ApplyReplicationStream(replica, bytes.NewReader(stream.Bytes()[2*confRowSize:])); err != nil {
+		t.Fatalf("ApplyReplicationStream remainder: %v", err)
+	}
+	primaryBytes, _ := os.ReadFile(primary)
+	replicaBytes, _ := os.ReadFile(replica)
+	if !bytes.Equal(primaryBytes, replicaBytes) {
+		t.Errorf("replica differs from primary after applying the remainder")
+	}
+}
+
+func TestSetReplicationSink_RequiresWriteMode(t *testing.T) {
+	path := setupCreate(t, t.TempDir(), 0)
+	db, err := NewFrozenDB(path, MODE_READ, FinderStrategySimple)
+	if err != nil {
+		t.Fatalf("NewFrozenDB: %v", err)
+	}
+	defer db.Close()
+	var invalidAction *InvalidActionError
+	if err := db.SetReplicationSink(io.Discard); !errors.As(err, &invalidAction) {
+		t.Errorf("expected InvalidActionError, got %v", err)
+	}
+}
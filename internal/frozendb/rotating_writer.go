@@ -0,0 +1,238 @@
+package frozendb
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// RotatingWriter appends rows to a numbered series of database files, creating
+// the next file once the current one reaches a size or row threshold. A path of
+// "logs/db.fdb" produces "logs/db-0001.fdb", "logs/db-0002.fdb", and so on.
+//
+// Rows are grouped into transactions that are committed when they reach
+// MAX_TRANSACTION_ROWS, when the writer rotates, and on Flush or Close. Rows added
+// since the last commit are therefore not visible to readers, and are lost if the
+// process exits before one of those points.
+//
+// Key ordering holds across the series: a key must satisfy
+// new_timestamp + skew_ms > max_timestamp, where max_timestamp covers the files
+// the writer rotated away from as well as the current one.
+//
+// Thread Safety: Safe for concurrent calls on the same RotatingWriter instance
+type RotatingWriter struct {
+	mu sync.Mutex
+
+	config   CreateConfig // Template for each file; its path is the series base path
+	maxBytes int64        // Rotate once the file holds at least this many bytes (0 = no limit)
+	maxRows  int64        // Rotate once the file holds at least this many rows (0 = no limit)
+
+	seq          int          // Number of the current file
+	db           *FrozenDB    // Write handle of the current file (nil after Close)
+	tx           *Transaction // Open transaction, nil when none has been begun
+	txRows       int          // Data rows added to tx
+	maxTimestamp int64        // Largest key timestamp of the files rotated away from
+}
+
+// NewRotatingWriter opens the last existing file of the series based on
+// config's path, or creates the first one when none exists. Resuming reads every
+// earlier file of the series once to recover its max timestamp. Each new file is
+// created with config's row size, skew, checksum interval, value compression,
+// and immutability settings.
+//
+// Parameters:
+//   - config: Template for the files; its path must end with .fdb
+//   - maxBytes: Rotate once the current file is at least this many bytes (0 = no limit)
+//   - maxRows: Rotate once the current file holds at least this many rows, counted
+//     as RowCount does (0 = no limit)
+//
+// Returns:
+//   - *RotatingWriter: Writer positioned at the end of the last file
+//   - error: InvalidInputError (invalid config or limits), or an error from Create or NewFrozenDB
+func NewRotatingWriter(config CreateConfig, maxBytes int64, maxRows int64) (*RotatingWriter, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	if maxBytes < 0 || maxRows < 0 {
+		return nil, NewInvalidInputError(fmt.Sprintf("rotation limits must not be negative, got %d bytes and %d rows", maxBytes, maxRows), nil)
+	}
+
+	w := &RotatingWriter{config: config, maxBytes: maxBytes, maxRows: maxRows}
+
+	last := 0
+	for {
+		if _, err := os.Stat(w.seriesPath(last + 1)); err != nil {
+			break
+		}
+		last++
+	}
+	if last == 0 {
+		if err := w.openNext(); err != nil {
+			return nil, err
+		}
+		return w, nil
+	}
+
+	// Resume the series: keys must follow every earlier file as well
+	for seq := 1; seq < last; seq++ {
+		prev, err := NewFrozenDB(w.seriesPath(seq), MODE_READ, FinderStrategySimple)
+		if err != nil {
+			return nil, err
+		}
+		w.maxTimestamp = max(w.maxTimestamp, prev.finder.MaxTimestamp())
+		_ = prev.Close()
+	}
+	db, err := NewFrozenDB(w.seriesPath(last), MODE_WRITE, FinderStrategySimple)
+	if err != nil {
+		return nil, err
+	}
+	w.seq, w.db = last, db
+	return w, nil
+}
+
+// seriesPath returns the path of file number seq of the series.
+func (w *RotatingWriter) seriesPath(seq int) string {
+	base := strings.TrimSuffix(w.config.GetPath(), FILE_EXTENSION)
+	return fmt.Sprintf("%s-%04d%s", base, seq, FILE_EXTENSION)
+}
+
+// Path returns the path of the file rows are currently written to.
+func (w *RotatingWriter) Path() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.seriesPath(w.seq)
+}
+
+// Add appends a row to the current file, first rotating to the next file when
+// the current one has reached a limit.
+//
+// Parameters:
+//   - key: UUIDv7 key, ordered after the keys already written to the series
+//   - value: JSON value of the row
+//
+// Returns:
+//   - error: InvalidActionError (writer closed), KeyOrderingError, or an error from
+//     AddRow, Commit, or rotating to the next file
+func (w *RotatingWriter) Add(key uuid.UUID, value json.RawMessage) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.db == nil {
+		return NewInvalidActionError("rotating writer is closed", nil)
+	}
+	// Checked before rotating, since AddRow only orders against the current file
+	if ExtractUUIDv7Timestamp(key)+int64(w.config.GetSkewMs()) <= w.seriesMaxTimestamp() {
+		return NewKeyOrderingError("UUID timestamp violates ordering constraint: new_timestamp + skew_ms must be > max_timestamp of the series", nil)
+	}
+	if w.limitReached() {
+		if err := w.rotate(); err != nil {
+			return err
+		}
+	}
+	if w.tx == nil {
+		tx, err := w.db.BeginTx()
+		if err != nil {
+			return err
+		}
+		w.tx, w.txRows = tx, 0
+	}
+	if err := w.tx.AddRow(key, value); err != nil {
+		return err
+	}
+	w.txRows++
+	if w.txRows == MAX_TRANSACTION_ROWS {
+		return w.commit()
+	}
+	return nil
+}
+
+// Flush commits the rows added since the last commit, making them visible to
+// readers of the current file.
+func (w *RotatingWriter) Flush() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.db == nil {
+		return NewInvalidActionError("rotating writer is closed", nil)
+	}
+	return w.commit()
+}
+
+// Close commits any open transaction and closes the current file. Close is
+// idempotent.
+func (w *RotatingWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.db == nil {
+		return nil
+	}
+	commitErr := w.commit()
+	closeErr := w.db.Close()
+	w.db = nil
+	if commitErr != nil {
+		return commitErr
+	}
+	return closeErr
+}
+
+// seriesMaxTimestamp returns the largest key timestamp written to the series,
+// including the rows of the open transaction.
+func (w *RotatingWriter) seriesMaxTimestamp() int64 {
+	maxTs := max(w.maxTimestamp, w.db.finder.MaxTimestamp())
+	if w.tx != nil {
+		maxTs = max(maxTs, w.tx.maxTimestamp)
+	}
+	return maxTs
+}
+
+// limitReached reports whether the current file has reached either limit. The
+// size includes the partial row of an open transaction.
+func (w *RotatingWriter) limitReached() bool {
+	if w.maxBytes > 0 && w.db.file.Size() >= w.maxBytes {
+		return true
+	}
+	return w.maxRows > 0 && w.db.RowCount() >= w.maxRows
+}
+
+// commit commits the open transaction, if any.
+func (w *RotatingWriter) commit() error {
+	if w.tx == nil {
+		return nil
+	}
+	tx := w.tx
+	w.tx, w.txRows = nil, 0
+	return tx.Commit()
+}
+
+// rotate commits and closes the current file, carrying its max timestamp
+// forward, and creates the next file of the series.
+func (w *RotatingWriter) rotate() error {
+	if err := w.commit(); err != nil {
+		return err
+	}
+	w.maxTimestamp = max(w.maxTimestamp, w.db.finder.MaxTimestamp())
+	if err := w.db.Close(); err != nil {
+		return err
+	}
+	w.db = nil
+	return w.openNext()
+}
+
+// openNext creates the next file of the series and opens it for writing.
+func (w *RotatingWriter) openNext() error {
+	config := w.config
+	config.path = w.seriesPath(w.seq + 1)
+	if err := Create(config); err != nil {
+		return err
+	}
+	db, err := NewFrozenDB(config.path, MODE_WRITE, FinderStrategySimple)
+	if err != nil {
+		return err
+	}
+	w.seq++
+	w.db = db
+	return nil
+}
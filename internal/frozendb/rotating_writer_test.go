@@ -0,0 +1,104 @@
+package frozendb
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+)
+
+func newRotatingTestConfig(t *testing.T) CreateConfig {
+	t.Helper()
+	config := NewCreateConfig(filepath.Join(t.TempDir(), "db.fdb"), confRowSize, confSkewMs)
+	config.SetNoImmutable(true)
+	return config
+}
+
+func TestRotatingWriter_RotatesAtSizeLimit(t *testing.T) {
+	config := newRotatingTestConfig(t)
+	maxBytes := int64(HEADER_SIZE + 4*confRowSize)
+	w, err := NewRotatingWriter(config, maxBytes, 0)
+	if err != nil {
+		t.Fatalf("NewRotatingWriter: %v", err)
+	}
+	for i := 1; i <= 20; i++ {
+		if err := w.Add(uuidFromTS(i*1000), json.RawMessage(fmt.Sprintf(`{"n":%d}`, i))); err != nil {
+			t.Fatalf("Add(%d): %v", i, err)
+		}
+	}
+	files := w.seq
+	if files < 2 {
+		t.Fatalf("expected the writer to rotate, still on file %d", files)
+	}
+	if got, want := w.Path(), filepath.Join(filepath.Dir(config.GetPath()), fmt.Sprintf("db-%04d.fdb", files)); got != want {
+		t.Errorf("Path() = %q, want %q", got, want)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if err := w.Add(uuidFromTS(100000), json.RawMessage(`{}`)); err == nil {
+		t.Error("Add after Close should fail")
+	}
+
+	var values []string
+	for seq := 1; seq <= files; seq++ {
+		db, err := NewFrozenDB(w.seriesPath(seq), MODE_READ, FinderStrategySimple)
+		if err != nil {
+			t.Fatalf("open file %d: %v", seq, err)
+		}
+		if seq < files && db.file.Size() > maxBytes+int64(confRowSize) {
+			t.Errorf("file %d is %d bytes, past the limit by more than a row", seq, db.file.Size())
+		}
+		for _, row := range scanAll(t, db) {
+			values = append(values, string(row.value))
+		}
+		db.Close()
+	}
+	if len(values) != 20 || values[0] != `{"n":1}` || values[19] != `{"n":20}` {
+		t.Errorf("series holds %v, want the 20 rows in order", values)
+	}
+}
+
+func TestRotatingWriter_OrderingAcrossFilesAndResume(t *testing.T) {
+	config := newRotatingTestConfig(t)
+	// Two rows: the initial checksum row and one data row
+	w, err := NewRotatingWriter(config, 0, 2)
+	if err != nil {
+		t.Fatalf("NewRotatingWriter: %v", err)
+	}
+	if err := w.Add(uuidFromTS(100000), json.RawMessage(`{"n":1}`)); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	// Within the skew of the first file's key, so still ordered
+	if err := w.Add(uuidFromTS(96000), json.RawMessage(`{"n":2}`)); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	if w.seq != 2 {
+		t.Fatalf("expected the second row to rotate, current file is %d", w.seq)
+	}
+	// Ordered after the second file's key, but not after the first file's
+	var orderErr *KeyOrderingError
+	if err := w.Add(uuidFromTS(92000), json.RawMessage(`{}`)); !errors.As(err, &orderErr) {
+		t.Errorf("expected KeyOrderingError, got %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	// Reopening resumes the last file and still orders against the previous one
+	w, err = NewRotatingWriter(config, 0, 0)
+	if err != nil {
+		t.Fatalf("NewRotatingWriter (resume): %v", err)
+	}
+	defer w.Close()
+	if w.seq != 2 {
+		t.Errorf("resumed at file %d, want 2", w.seq)
+	}
+	if err := w.Add(uuidFromTS(92000), json.RawMessage(`{}`)); !errors.As(err, &orderErr) {
+		t.Errorf("expected KeyOrderingError after resume, got %v", err)
+	}
+	if err := w.Add(uuidFromTS(300000), json.RawMessage(`{"n":3}`)); err != nil {
+		t.Errorf("Add after resume: %v", err)
+	}
+}
@@ -14,6 +14,11 @@ const (
 	NULL_BYTE = 0x00 // Null character for padding
 )
 
+// minRowBytes is the shortest byte slice that can hold a row's framing:
+// ROW_START, start_control, one NULL_BYTE, end_control, parity, and ROW_END.
+// UnmarshalText rejects anything shorter before slicing into it.
+const minRowBytes = 8
+
 // StartControl represents single-byte control characters at row position [1]
 type StartControl byte
 
@@ -299,6 +304,9 @@ func (br *baseRow[T]) UnmarshalText(text []byte) error {
 	if len(text) == 0 {
 		return NewInvalidInputError("row bytes cannot be empty", nil)
 	}
+	if len(text) < minRowBytes {
+		return NewInvalidInputError(fmt.Sprintf("row bytes too short: need at least %d, got %d", minRowBytes, len(text)), nil)
+	}
 
 	rowSize := len(text)
 	// Set RowSize early so it's available for GetParity() and other methods
@@ -353,7 +361,7 @@ func (br *baseRow[T]) UnmarshalText(text []byte) error {
 	br.RowPayload = payload
 
 	// Step 4: Validate that bytes [firstNullIndex..N-6] are all null (padding)
-	for i := firstNullIndex; i < rowSize-6; i++ {
+	for i := firstNullIndex; i < rowSize-5; i++ {
 		if text[i] != NULL_BYTE {
 			return NewInvalidInputError(fmt.Sprintf("invalid padding byte at position %d: expected NULL_BYTE (0x%02X), got 0x%02X", i, NULL_BYTE, text[i]), nil)
 		}
@@ -0,0 +1,173 @@
+package frozendb
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// RowKind identifies the type of a physical row returned by FrozenDB.RowAt.
+type RowKind string
+
+const (
+	RowKindData     RowKind = "data"     // Complete DataRow
+	RowKindNull     RowKind = "null"     // NullRow (empty transaction)
+	RowKindChecksum RowKind = "checksum" // ChecksumRow
+	RowKindPartial  RowKind = "partial"  // PartialDataRow at the end of the file
+)
+
+// RowInfo is a validated physical row with its control bytes decoded.
+//
+// Fields that do not apply to Kind hold their zero value: Key is uuid.Nil for
+// checksum rows, Value is nil except for data rows and partial rows that reached
+// their payload, Checksum is set only for checksum rows, and EndControl is zero
+// for partial rows, which have not written it yet.
+type RowInfo struct {
+	Index        int64           // Physical row index (checksum rows included)
+	Kind         RowKind         // Row type
+	StartControl StartControl    // 'T', 'R', or 'C'
+	EndControl   EndControl      // Two-byte end control (zero for partial rows)
+	Key          uuid.UUID       // UUIDv7 key of data, null, and partial rows
+	Value        json.RawMessage // JSON value of data rows and partial rows with a payload
+	Checksum     uint32          // CRC32 stored in checksum rows
+
+	TxStart           bool // Row starts a transaction (start_control 'T', and every NullRow)
+	TxEnd             bool // Row ends its transaction by commit, rollback, or as a NullRow
+	Commit            bool // Row commits its transaction (end_control 'TC' or 'SC')
+	Savepoint         bool // Row creates a savepoint (end_control 'S*', or a partial row's 'S')
+	Rollback          bool // Row rolls back its transaction (end_control 'R0'-'S9')
+	RollbackSavepoint int  // Savepoint rolled back to when Rollback is set (0 = full rollback)
+}
+
+// RowCount returns the number of physical rows in the database file, including
+// checksum rows and a trailing partial row, so RowAt accepts indices 0 through
+// RowCount()-1. Rows appended later increase the count.
+//
+// Thread Safety: Safe for concurrent calls on the same FrozenDB instance
+func (db *FrozenDB) RowCount() int64 {
+	db.refresh()
+	rowSize := int64(db.header.GetRowSize())
+	dataBytes := db.file.Size() - int64(HEADER_SIZE)
+	if dataBytes <= 0 {
+		return 0
+	}
+	return (dataBytes + rowSize - 1) / rowSize
+}
+
+// RowAt reads the physical row at index, validates it, and returns it decoded.
+// Index 0 is the initial checksum row; the row starts at byte offset
+// HEADER_SIZE + index*row_size. Unlike Get, RowAt applies no transaction
+// visibility rules: uncommitted and rolled back rows are returned as stored.
+//
+// Parameters:
+//   - index: Physical row index in [0, RowCount())
+//
+// Returns:
+//   - *RowInfo: The decoded row
+//   - error: InvalidInputError (index out of range), ReadError, or CorruptDatabaseError
+//     (the row fails framing, parity, or checksum-row validation)
+//
+// Thread Safety: Safe for concurrent calls on the same FrozenDB instance
+func (db *FrozenDB) RowAt(index int64) (*RowInfo, error) {
+	rowCount := db.RowCount()
+	if index < 0 || index >= rowCount {
+		return nil, NewInvalidInputError(fmt.Sprintf("row index %d out of range [0, %d)", index, rowCount), nil)
+	}
+	return db.rowInfoAt(index)
+}
+
+// rowInfoAt reads and decodes the row at index without refreshing the file size
+// or range-checking index; callers have already done both.
+func (db *FrozenDB) rowInfoAt(index int64) (*RowInfo, error) {
+	rowSize := db.header.GetRowSize()
+	offset := int64(HEADER_SIZE) + index*int64(rowSize)
+	if remaining := db.file.Size() - offset; remaining < int64(rowSize) {
+		rowBytes, err := db.file.Read(offset, int32(remaining))
+		if err != nil {
+			return nil, NewReadError(fmt.Sprintf("failed to read partial row at index %d", index), err)
+		}
+		return partialRowInfo(index, rowBytes)
+	}
+
+	rowBytes, err := db.readRowAtIndex(index)
+	if err != nil {
+		return nil, err
+	}
+	var ru RowUnion
+	if err := ru.UnmarshalText(rowBytes); err != nil {
+		return nil, NewCorruptDatabaseError(fmt.Sprintf("failed to parse row at index %d", index), err)
+	}
+
+	switch {
+	case ru.ChecksumRow != nil:
+		return &RowInfo{
+			Index:        index,
+			Kind:         RowKindChecksum,
+			StartControl: ru.ChecksumRow.StartControl,
+			EndControl:   ru.ChecksumRow.EndControl,
+			Checksum:     uint32(ru.ChecksumRow.GetChecksum()),
+		}, nil
+	case ru.NullRow != nil:
+		return &RowInfo{
+			Index:        index,
+			Kind:         RowKindNull,
+			StartControl: ru.NullRow.StartControl,
+			EndControl:   ru.NullRow.EndControl,
+			Key:          ru.NullRow.GetKey(),
+			TxStart:      true,
+			TxEnd:        true,
+		}, nil
+	case ru.DataRow != nil:
+		info := &RowInfo{
+			Index:        index,
+			Kind:         RowKindData,
+			StartControl: ru.DataRow.StartControl,
+			EndControl:   ru.DataRow.EndControl,
+			Key:          ru.DataRow.GetKey(),
+			Value:        ru.DataRow.GetValue(),
+			TxStart:      ru.DataRow.StartControl == START_TRANSACTION,
+		}
+		info.decodeEndControl()
+		return info, nil
+	default:
+		return nil, NewCorruptDatabaseError(fmt.Sprintf("row at index %d has unknown type", index), nil)
+	}
+}
+
+// partialRowInfo decodes the trailing partial row at index.
+func partialRowInfo(index int64, rowBytes []byte) (*RowInfo, error) {
+	var pdr PartialDataRow
+	if err := pdr.UnmarshalText(rowBytes); err != nil {
+		return nil, NewCorruptDatabaseError(fmt.Sprintf("failed to parse partial row at index %d", index), err)
+	}
+	info := &RowInfo{
+		Index:        index,
+		Kind:         RowKindPartial,
+		StartControl: pdr.GetStartControl(),
+		TxStart:      pdr.GetStartControl() == START_TRANSACTION,
+		Savepoint:    pdr.GetState() == PartialDataRowWithSavepoint,
+	}
+	if key, ok := pdr.GetKey(); ok {
+		info.Key = key
+	}
+	if value, ok := pdr.GetValue(); ok {
+		info.Value = value
+	}
+	return info, nil
+}
+
+// decodeEndControl sets the transaction fields derived from a DataRow's end_control.
+func (info *RowInfo) decodeEndControl() {
+	first, second := info.EndControl[0], info.EndControl[1]
+	info.Savepoint = first == 'S'
+	switch {
+	case second == 'C':
+		info.Commit = true
+		info.TxEnd = true
+	case second >= '0' && second <= '9':
+		info.Rollback = true
+		info.RollbackSavepoint = int(second - '0')
+		info.TxEnd = true
+	}
+}
@@ -0,0 +1,109 @@
+package frozendb
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestRowAt_DecodesEveryRowKind(t *testing.T) {
+	path := setupCreate(t, t.TempDir(), 0)
+	db, err := NewFrozenDB(path, MODE_WRITE, FinderStrategySimple)
+	if err != nil {
+		t.Fatalf("NewFrozenDB: %v", err)
+	}
+	defer db.Close()
+
+	begin := func() *Transaction {
+		t.Helper()
+		tx, err := db.BeginTx()
+		if err != nil {
+			t.Fatalf("BeginTx: %v", err)
+		}
+		return tx
+	}
+
+	// Rows 1-2: committed transaction
+	tx := begin()
+	mustAdd(t, tx, uuidFromTS(1000), `{"n":1}`)
+	mustAdd(t, tx, uuidFromTS(1001), `{"n":2}`)
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+	// Rows 3-4: savepoint after the first row, then rollback to it
+	tx = begin()
+	mustAdd(t, tx, uuidFromTS(1002), `{"n":3}`)
+	if err := tx.Savepoint(); err != nil {
+		t.Fatalf("Savepoint: %v", err)
+	}
+	mustAdd(t, tx, uuidFromTS(1003), `{"n":4}`)
+	if err := tx.Rollback(1); err != nil {
+		t.Fatalf("Rollback: %v", err)
+	}
+	// Row 5: empty transaction (NullRow)
+	tx = begin()
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+	// Row 6: transaction still in progress (PartialDataRow)
+	tx = begin()
+	mustAdd(t, tx, uuidFromTS(1004), `{"n":5}`)
+
+	if got := db.RowCount(); got != 7 {
+		t.Fatalf("RowCount() = %d, want 7", got)
+	}
+
+	tests := []struct {
+		index int64
+		want  RowInfo
+	}{
+		{0, RowInfo{Kind: RowKindChecksum, StartControl: CHECKSUM_ROW, EndControl: CHECKSUM_ROW_CONTROL}},
+		{1, RowInfo{Kind: RowKindData, StartControl: START_TRANSACTION, EndControl: ROW_END_CONTROL, Key: uuidFromTS(1000), Value: []byte(`{"n":1}`), TxStart: true}},
+		{2, RowInfo{Kind: RowKindData, StartControl: ROW_CONTINUE, EndControl: TRANSACTION_COMMIT, Key: uuidFromTS(1001), Value: []byte(`{"n":2}`), TxEnd: true, Commit: true}},
+		{3, RowInfo{Kind: RowKindData, StartControl: START_TRANSACTION, EndControl: SAVEPOINT_CONTINUE, Key: uuidFromTS(1002), Value: []byte(`{"n":3}`), TxStart: true, Savepoint: true}},
+		{4, RowInfo{Kind: RowKindData, StartControl: ROW_CONTINUE, EndControl: EndControl{'R', '1'}, Key: uuidFromTS(1003), Value: []byte(`{"n":4}`), TxEnd: true, Rollback: true, RollbackSavepoint: 1}},
+		{5, RowInfo{Kind: RowKindNull, StartControl: START_TRANSACTION, EndControl: NULL_ROW_CONTROL, TxStart: true, TxEnd: true}},
+		{6, RowInfo{Kind: RowKindPartial, StartControl: START_TRANSACTION, Key: uuidFromTS(1004), Value: []byte(`{"n":5}`), TxStart: true}},
+	}
+	for _, tt := range tests {
+		got, err := db.RowAt(tt.index)
+		if err != nil {
+			t.Fatalf("RowAt(%d): %v", tt.index, err)
+		}
+		want := tt.want
+		want.Index = tt.index
+		if got.Kind == RowKindChecksum {
+			want.Checksum = got.Checksum
+		}
+		if got.Kind == RowKindNull {
+			want.Key = got.Key
+		}
+		if got.Index != want.Index || got.Kind != want.Kind || got.StartControl != want.StartControl ||
+			got.EndControl != want.EndControl || got.Key != want.Key || string(got.Value) != string(want.Value) ||
+			got.TxStart != want.TxStart || got.TxEnd != want.TxEnd || got.Commit != want.Commit ||
+			got.Savepoint != want.Savepoint || got.Rollback != want.Rollback || got.RollbackSavepoint != want.RollbackSavepoint {
+			t.Errorf("RowAt(%d) = %+v, want %+v", tt.index, *got, want)
+		}
+	}
+
+	var invalidInput *InvalidInputError
+	for _, index := range []int64{-1, 7} {
+		if _, err := db.RowAt(index); !errors.As(err, &invalidInput) {
+			t.Errorf("RowAt(%d): expected InvalidInputError, got %v", index, err)
+		}
+	}
+}
+
+func TestRowAt_CorruptRow(t *testing.T) {
+	data, _, header := buildTestDatabase(512, committedTransactionRows(2))
+	// Flip a payload byte of row 2 so its parity no longer matches
+	data[HEADER_SIZE+2*512+30] ^= 0x01
+
+	db := &FrozenDB{file: newMockGetDBFile(data, MODE_READ), header: header}
+	if _, err := db.RowAt(1); err != nil {
+		t.Fatalf("RowAt(1): %v", err)
+	}
+	var corrupt *CorruptDatabaseError
+	if _, err := db.RowAt(2); !errors.As(err, &corrupt) {
+		t.Fatalf("RowAt(2): expected CorruptDatabaseError, got %v", err)
+	}
+}
@@ -1,5 +1,7 @@
 package frozendb
 
+import "fmt"
+
 // RowUnion holds pointers to all possible row types.
 // Exactly one pointer will be non-nil after unmarshaling.
 // Header must be set before calling UnmarshalText.
@@ -18,6 +20,9 @@ func (ru *RowUnion) UnmarshalText(rowBytes []byte) error {
 	if rowSize == 0 {
 		return NewInvalidInputError("row bytes cannot be empty", nil)
 	}
+	if rowSize < minRowBytes {
+		return NewInvalidInputError(fmt.Sprintf("row bytes too short: need at least %d, got %d", minRowBytes, rowSize), nil)
+	}
 
 	// Read start_control at position [1]
 	startControl := StartControl(rowBytes[1])
@@ -30,7 +35,7 @@ func (ru *RowUnion) UnmarshalText(rowBytes []byte) error {
 	if startControl == CHECKSUM_ROW && endControl == CHECKSUM_ROW_CONTROL {
 		// ChecksumRow: start_control='C', end_control='CS'
 		ru.ChecksumRow = &ChecksumRow{
-			baseRow[*Checksum]{
+			baseRow: baseRow[*Checksum]{
 				RowSize: rowSize,
 			},
 		}
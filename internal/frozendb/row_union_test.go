@@ -0,0 +1,70 @@
+package frozendb
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+// FuzzRowUnionUnmarshal checks that RowUnion.UnmarshalText returns an error
+// rather than panicking on arbitrary bytes, and that any row it accepts is of
+// exactly one type and marshals back to the same bytes.
+func FuzzRowUnionUnmarshal(f *testing.F) {
+	dataRow := &DataRow{
+		baseRow[*DataRowPayload]{
+			RowSize:      MIN_ROW_SIZE,
+			StartControl: START_TRANSACTION,
+			EndControl:   TRANSACTION_COMMIT,
+			RowPayload:   &DataRowPayload{Key: uuidFromTS(1000), Value: json.RawMessage(`{"a":1}`)},
+		},
+	}
+	checksumRow, err := NewChecksumRow(MIN_ROW_SIZE, []byte("header"))
+	if err != nil {
+		f.Fatalf("NewChecksumRow: %v", err)
+	}
+	nullRow, err := NewNullRow(MIN_ROW_SIZE, 1000)
+	if err != nil {
+		f.Fatalf("NewNullRow: %v", err)
+	}
+	for _, row := range []interface{ MarshalText() ([]byte, error) }{dataRow, checksumRow, nullRow} {
+		rowBytes, err := row.MarshalText()
+		if err != nil {
+			f.Fatalf("MarshalText: %v", err)
+		}
+		f.Add(rowBytes)
+		// Truncated from either end, keeping the framing bytes in view
+		for _, n := range []int{1, 2, 5, 6, 7, 8, 9} {
+			f.Add(rowBytes[:n])
+			f.Add(rowBytes[len(rowBytes)-n:])
+			f.Add(append(rowBytes[:2:2], rowBytes[len(rowBytes)-n:]...))
+		}
+	}
+	f.Add([]byte{})
+	f.Add([]byte{ROW_START, 'T', 'T', 'C', '0', '0', ROW_END})
+	f.Add([]byte{ROW_START, 'C', NULL_BYTE, 'C', 'S', '0', '0', ROW_END})
+
+	f.Fuzz(func(t *testing.T, rowBytes []byte) {
+		var ru RowUnion
+		if err := ru.UnmarshalText(rowBytes); err != nil {
+			return
+		}
+		var marshaled []byte
+		var err error
+		switch {
+		case ru.DataRow != nil && ru.NullRow == nil && ru.ChecksumRow == nil:
+			marshaled, err = ru.DataRow.MarshalText()
+		case ru.NullRow != nil && ru.DataRow == nil && ru.ChecksumRow == nil:
+			marshaled, err = ru.NullRow.MarshalText()
+		case ru.ChecksumRow != nil && ru.DataRow == nil && ru.NullRow == nil:
+			marshaled, err = ru.ChecksumRow.MarshalText()
+		default:
+			t.Fatalf("accepted row is not exactly one type: %+v", ru)
+		}
+		if err != nil {
+			t.Fatalf("MarshalText of accepted row: %v", err)
+		}
+		if !bytes.Equal(marshaled, rowBytes) {
+			t.Fatalf("accepted row marshals to %q, want %q", marshaled, rowBytes)
+		}
+	})
+}
@@ -0,0 +1,52 @@
+package frozendb
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// RowValidator checks a row before it is added to a transaction. Returning an error
+// rejects the row: AddRow returns it as an InvalidDataError and writes nothing.
+//
+// The validator runs while the transaction is locked, so it must not call methods
+// of the transaction or begin another one. value must not be modified or retained.
+type RowValidator func(key uuid.UUID, value json.RawMessage) error
+
+// SetRowValidator sets a function that every row passed to AddRow must pass, for
+// application rules the value schema cannot express. It runs after the built-in
+// checks and the value schema, before anything is written, so a rejected row never
+// reaches the file and the transaction stays usable.
+//
+// Like SetValueSchema, the validator is held in memory for this handle only and
+// applies to transactions begun after the call; an already active transaction
+// keeps the validator it started with. Passing nil removes the validator. No
+// validator is set by default.
+//
+// Parameters:
+//   - validator: Function checking each row, or nil to disable
+//
+// Thread Safety: Safe for concurrent calls on the same FrozenDB instance
+func (db *FrozenDB) SetRowValidator(validator RowValidator) {
+	db.txMu.Lock()
+	defer db.txMu.Unlock()
+	db.rowValidator = validator
+}
+
+// validateRow runs validator on a row, returning its rejection as an InvalidDataError.
+func validateRow(validator RowValidator, key uuid.UUID, value json.RawMessage) error {
+	if validator == nil {
+		return nil
+	}
+	err := validator(key, value)
+	if err == nil {
+		return nil
+	}
+	var invalidData *InvalidDataError
+	if errors.As(err, &invalidData) {
+		return err
+	}
+	return NewInvalidDataError(fmt.Sprintf("row validator rejected key %s", key), err)
+}
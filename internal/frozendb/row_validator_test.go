@@ -0,0 +1,95 @@
+package frozendb
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestSetRowValidator_AddRow(t *testing.T) {
+	path := setupCreate(t, t.TempDir(), 0)
+	db, err := NewFrozenDB(path, MODE_WRITE, FinderStrategySimple)
+	if err != nil {
+		t.Fatalf("NewFrozenDB: %v", err)
+	}
+	defer db.Close()
+
+	errNoType := errors.New(`value must contain a "type" field`)
+	db.SetRowValidator(func(key uuid.UUID, value json.RawMessage) error {
+		var fields map[string]any
+		if err := json.Unmarshal(value, &fields); err != nil {
+			return err
+		}
+		if _, ok := fields["type"]; !ok {
+			return errNoType
+		}
+		return nil
+	})
+
+	tx, err := db.BeginTx()
+	if err != nil {
+		t.Fatalf("BeginTx: %v", err)
+	}
+	mustAdd(t, tx, uuidFromTS(1000), `{"type":"a"}`)
+	before, _ := os.Stat(path)
+	var invalidData *InvalidDataError
+	err = tx.AddRow(uuidFromTS(1001), json.RawMessage(`{"n":1}`))
+	if !errors.As(err, &invalidData) || !errors.Is(err, errNoType) {
+		t.Fatalf("expected InvalidDataError wrapping the validator error, got %v", err)
+	}
+	if after, _ := os.Stat(path); after.Size() != before.Size() {
+		t.Errorf("rejected row wrote %d bytes", after.Size()-before.Size())
+	}
+
+	// Replacing the validator does not affect the active transaction
+	db.SetRowValidator(nil)
+	if err := tx.AddRow(uuidFromTS(1002), json.RawMessage(`{"n":1}`)); !errors.As(err, &invalidData) {
+		t.Fatalf("active transaction: expected InvalidDataError, got %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+
+	var value map[string]any
+	var notFound *KeyNotFoundError
+	if err := db.Get(uuidFromTS(1001), &value); !errors.As(err, &notFound) {
+		t.Errorf("rejected row: expected KeyNotFoundError, got %v", err)
+	}
+
+	tx, err = db.BeginTx()
+	if err != nil {
+		t.Fatalf("BeginTx: %v", err)
+	}
+	mustAdd(t, tx, uuidFromTS(2000), `{"n":1}`)
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+}
+
+func TestSetRowValidator_PrepareBatch(t *testing.T) {
+	path := setupCreate(t, t.TempDir(), 0)
+	db, err := NewFrozenDB(path, MODE_WRITE, FinderStrategySimple)
+	if err != nil {
+		t.Fatalf("NewFrozenDB: %v", err)
+	}
+	defer db.Close()
+
+	rejected := uuidFromTS(1001)
+	db.SetRowValidator(func(key uuid.UUID, value json.RawMessage) error {
+		if key == rejected {
+			return NewInvalidDataError("key is reserved", nil)
+		}
+		return nil
+	})
+	_, err = db.PrepareBatch(
+		Entry{Key: uuidFromTS(1000), Value: json.RawMessage(`{}`)},
+		Entry{Key: rejected, Value: json.RawMessage(`{}`)},
+	)
+	var invalidData *InvalidDataError
+	if !errors.As(err, &invalidData) {
+		t.Fatalf("expected InvalidDataError, got %v", err)
+	}
+}
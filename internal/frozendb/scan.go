@@ -0,0 +1,819 @@
+package frozendb
+
+import (
+	"bytes"
+	"container/heap"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"github.com/google/uuid"
+)
+
+// committedRowReader walks the database forward from the first row and returns
+// the visible DataRows of each completed transaction, in file order.
+//
+// Only one transaction is buffered at a time, so memory is bounded by the
+// 100-row transaction limit regardless of database size. Rows belonging to a
+// transaction that has not ended by endIndex are never returned.
+type committedRowReader struct {
+	file     DBFile
+	rowSize  int32
+	endIndex int64     // First row index that is not read (bounds the scan)
+	index    int64     // Next row index to read
+	txRows   []DataRow // Rows of the transaction currently being read
+	maxTs    int64     // Maximum timestamp of all complete data and null rows read so far
+
+	// onCorrupt, if set, is called for each row that fails to parse, which is
+	// then skipped instead of ending the read with CorruptDatabaseError
+	onCorrupt func(index int64, err error)
+	txCorrupt bool // A row of the transaction currently being read was skipped
+}
+
+// newCommittedRowReader creates a reader over the complete rows contained in the
+// first size bytes of file. Any trailing partial row is ignored.
+func newCommittedRowReader(file DBFile, rowSize int, size int64) *committedRowReader {
+	endIndex := int64(0)
+	if size > int64(HEADER_SIZE) {
+		endIndex = (size - int64(HEADER_SIZE)) / int64(rowSize)
+	}
+	return &committedRowReader{
+		file:     file,
+		rowSize:  int32(rowSize),
+		endIndex: endIndex,
+	}
+}
+
+// nextTransaction reads forward until the next transaction ends and returns the
+// rows that transaction made visible. The returned slice may be empty (for
+// example after a full rollback). ok is false once no complete transaction
+// remains before endIndex.
+func (r *committedRowReader) nextTransaction() (rows []DataRow, ok bool, err error) {
+	for r.index < r.endIndex {
+		index := r.index
+		rowBytes, err := r.file.Read(int64(HEADER_SIZE)+index*int64(r.rowSize), r.rowSize)
+		if err != nil {
+			return nil, false, NewReadError(fmt.Sprintf("failed to read row at index %d", index), err)
+		}
+		r.index++
+
+		var ru RowUnion
+		if err := ru.UnmarshalText(rowBytes); err != nil {
+			corruptErr := NewCorruptDatabaseError(fmt.Sprintf("failed to parse row at index %d", index), err)
+			if r.onCorrupt == nil {
+				return nil, false, corruptErr
+			}
+			r.onCorrupt(index, corruptErr)
+			r.txCorrupt = true
+			continue
+		}
+
+		switch {
+		case ru.ChecksumRow != nil:
+			continue
+		case ru.NullRow != nil:
+			r.observeTimestamp(ru.NullRow.GetKey())
+			r.txRows = r.txRows[:0]
+			r.txCorrupt = false
+			return nil, true, nil
+		case ru.DataRow != nil:
+			if ru.DataRow.StartControl == START_TRANSACTION {
+				r.txRows = r.txRows[:0]
+				r.txCorrupt = false
+			}
+			r.observeTimestamp(ru.DataRow.GetKey())
+			r.txRows = append(r.txRows, *ru.DataRow)
+
+			ec := ru.DataRow.EndControl
+			if ec == ROW_END_CONTROL || ec == SAVEPOINT_CONTINUE {
+				continue
+			}
+			visible, err := visibleTransactionRows(r.txRows)
+			r.txRows = nil
+			if err != nil {
+				// A skipped savepoint row leaves a partial rollback unresolvable
+				if r.txCorrupt {
+					r.txCorrupt = false
+					return nil, true, nil
+				}
+				return nil, false, err
+			}
+			r.txCorrupt = false
+			return visible, true, nil
+		}
+	}
+	return nil, false, nil
+}
+
+// extend raises the scan bound to the complete rows contained in the first size
+// bytes of the file. A transaction left incomplete at the previous bound resumes
+// where it stopped. Sizes at or below the current bound are ignored.
+func (r *committedRowReader) extend(size int64) {
+	if size <= int64(HEADER_SIZE) {
+		return
+	}
+	if endIndex := (size - int64(HEADER_SIZE)) / int64(r.rowSize); endIndex > r.endIndex {
+		r.endIndex = endIndex
+	}
+}
+
+func (r *committedRowReader) observeTimestamp(key uuid.UUID) {
+	if ts := ExtractUUIDv7Timestamp(key); ts > r.maxTs {
+		r.maxTs = ts
+	}
+}
+
+// visibleTransactionRows applies the transaction visibility rules to the rows of
+// a completed transaction, whose last row carries the terminating end control:
+//   - TC / SC: all rows are visible
+//   - R0 / S0: no rows are visible
+//   - R1-R9 / S1-S9: rows up to and including savepoint N are visible
+func visibleTransactionRows(rows []DataRow) ([]DataRow, error) {
+	if len(rows) == 0 {
+		return nil, nil
+	}
+	endControl := rows[len(rows)-1].EndControl
+	second := endControl[1]
+	switch {
+	case second == 'C':
+		return rows, nil
+	case second == '0':
+		return nil, nil
+	case second >= '1' && second <= '9':
+		savepointNum := int(second - '0')
+		savepointCount := 0
+		for i := range rows {
+			if rows[i].EndControl[0] == 'S' {
+				savepointCount++
+				if savepointCount == savepointNum {
+					return rows[:i+1], nil
+				}
+			}
+		}
+		return nil, NewCorruptDatabaseError(fmt.Sprintf("savepoint %d not found in transaction", savepointNum), nil)
+	}
+	return nil, NewCorruptDatabaseError(fmt.Sprintf("unknown transaction end control: %s", endControl.String()), nil)
+}
+
+// reverseTransactionReader walks the database backward from the last complete
+// row, returning one completed transaction at a time. Rows of a transaction that
+// is still in progress at the tail are skipped.
+type reverseTransactionReader struct {
+	file    DBFile
+	rowSize int32
+	index   int64 // Next row index to examine; -1 once the start of the file is reached
+}
+
+// newReverseTransactionReader creates a reader over the complete rows contained in
+// the first size bytes of file.
+func newReverseTransactionReader(file DBFile, rowSize int, size int64) *reverseTransactionReader {
+	last := int64(-1)
+	if size > int64(HEADER_SIZE) {
+		last = (size-int64(HEADER_SIZE))/int64(rowSize) - 1
+	}
+	return &reverseTransactionReader{file: file, rowSize: int32(rowSize), index: last}
+}
+
+func (r *reverseTransactionReader) readRow(index int64) (*RowUnion, error) {
+	rowBytes, err := r.file.Read(int64(HEADER_SIZE)+index*int64(r.rowSize), r.rowSize)
+	if err != nil {
+		return nil, NewReadError(fmt.Sprintf("failed to read row at index %d", index), err)
+	}
+	var ru RowUnion
+	if err := ru.UnmarshalText(rowBytes); err != nil {
+		return nil, NewCorruptDatabaseError(fmt.Sprintf("failed to parse row at index %d", index), err)
+	}
+	return &ru, nil
+}
+
+// prevTransaction returns the visible rows of the previous completed transaction
+// in file order, together with the smallest key timestamp among all of its rows
+// (visible or not). A NullRow is returned as a transaction with no visible rows.
+// ok is false once the start of the file is reached.
+func (r *reverseTransactionReader) prevTransaction() (visible []DataRow, minTs int64, ok bool, err error) {
+	var rows []DataRow
+	for ; r.index >= 0; r.index-- {
+		ru, err := r.readRow(r.index)
+		if err != nil {
+			return nil, 0, false, err
+		}
+
+		switch {
+		case ru.ChecksumRow != nil:
+			continue
+		case ru.NullRow != nil:
+			if len(rows) > 0 {
+				return nil, 0, false, NewCorruptDatabaseError(fmt.Sprintf("null row at index %d inside a transaction", r.index), nil)
+			}
+			r.index--
+			return nil, ExtractUUIDv7Timestamp(ru.NullRow.GetKey()), true, nil
+		case ru.DataRow != nil:
+			ec := ru.DataRow.EndControl
+			if len(rows) == 0 && (ec == ROW_END_CONTROL || ec == SAVEPOINT_CONTINUE) {
+				// Transaction still in progress at the tail of the file
+				continue
+			}
+			rows = append(rows, *ru.DataRow)
+			if ru.DataRow.StartControl != START_TRANSACTION {
+				continue
+			}
+
+			r.index--
+			slices.Reverse(rows)
+			minTs = ExtractUUIDv7Timestamp(rows[0].GetKey())
+			for i := range rows {
+				minTs = min(minTs, ExtractUUIDv7Timestamp(rows[i].GetKey()))
+			}
+			visible, err := visibleTransactionRows(rows)
+			if err != nil {
+				return nil, 0, false, err
+			}
+			return visible, minTs, true, nil
+		}
+	}
+	if len(rows) > 0 {
+		return nil, 0, false, NewCorruptDatabaseError("transaction has no start row", nil)
+	}
+	return nil, 0, false, nil
+}
+
+// heapRow is a DataRow buffered by a key-ordered reader, with its position in
+// file order.
+type heapRow struct {
+	DataRow
+	seq int64 // Orders rows by their position in the file
+}
+
+// dataRowHeap is a min-heap of DataRows ordered by key bytes, then by seq, so
+// the occurrences of a key written more than once come out in file order.
+type dataRowHeap []heapRow
+
+func (h dataRowHeap) Len() int { return len(h) }
+func (h dataRowHeap) Less(i, j int) bool {
+	ki, kj := h[i].GetKey(), h[j].GetKey()
+	if c := bytes.Compare(ki[:], kj[:]); c != 0 {
+		return c < 0
+	}
+	return h[i].seq < h[j].seq
+}
+func (h dataRowHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+func (h *dataRowHeap) Push(x any)   { *h = append(*h, x.(heapRow)) }
+func (h *dataRowHeap) Pop() any {
+	old := *h
+	n := len(old)
+	row := old[n-1]
+	*h = old[:n-1]
+	return row
+}
+
+// removeKey removes every row with key from h and returns how many of them were
+// tombstones.
+func (h *dataRowHeap) removeKey(key uuid.UUID, tombstone func(row *DataRow) bool) int {
+	removed := 0
+	kept := (*h)[:0]
+	for i := range *h {
+		if (*h)[i].GetKey() != key {
+			kept = append(kept, (*h)[i])
+		} else if tombstone(&(*h)[i].DataRow) {
+			removed++
+		}
+	}
+	if len(kept) != len(*h) {
+		*h = kept
+		heap.Init(h)
+	}
+	return removed
+}
+
+// hasTombstone reports whether h holds a tombstone for key.
+func (h dataRowHeap) hasTombstone(key uuid.UUID, tombstone func(row *DataRow) bool) bool {
+	for i := range h {
+		if h[i].GetKey() == key && tombstone(&h[i].DataRow) {
+			return true
+		}
+	}
+	return false
+}
+
+// dataRowMaxHeap is a max-heap of DataRows ordered by key bytes, then by seq.
+type dataRowMaxHeap struct{ dataRowHeap }
+
+func (h dataRowMaxHeap) Less(i, j int) bool { return h.dataRowHeap.Less(j, i) }
+
+// keyOrderedRowReader re-orders the output of a committedRowReader into
+// ascending key order.
+//
+// Keys in the file are only ordered within the skew window: every row written
+// after a row with timestamp max_ts has a timestamp greater than max_ts - skew_ms.
+// A buffered row whose timestamp is at or below that bound can therefore never
+// be preceded by a row that has not been read yet, and is safe to emit. Memory
+// is bounded by the number of rows written within one skew window.
+//
+// Deleted keys are dropped: a row with the same key can only be written while
+// the key's timestamp is within the skew window, so every occurrence of a key
+// has been read by the time it is safe to emit. A tombstone removes the pending
+// occurrences of its key and is itself never emitted.
+type keyOrderedRowReader struct {
+	reader     *committedRowReader
+	skewMs     int64
+	pending    dataRowHeap
+	seq        int64 // Rows read so far
+	done       bool
+	tombstone  func(row *DataRow) bool // Reports whether a row was written by Delete
+	tombstones int                     // Tombstones in pending
+}
+
+func newKeyOrderedRowReader(reader *committedRowReader, skewMs int, tombstone func(row *DataRow) bool) *keyOrderedRowReader {
+	return &keyOrderedRowReader{reader: reader, skewMs: int64(skewMs), tombstone: tombstone}
+}
+
+// next returns the committed row with the next smallest key. ok is false once
+// all committed rows have been returned.
+func (k *keyOrderedRowReader) next() (row DataRow, ok bool, err error) {
+	for {
+		if len(k.pending) > 0 {
+			minTs := ExtractUUIDv7Timestamp(k.pending[0].GetKey())
+			if k.done || minTs <= k.reader.maxTs-k.skewMs {
+				row := heap.Pop(&k.pending).(heapRow)
+				if k.tombstones > 0 && k.tombstone(&row.DataRow) {
+					k.tombstones--
+					continue
+				}
+				return row.DataRow, true, nil
+			}
+		}
+		if k.done {
+			return DataRow{}, false, nil
+		}
+		rows, more, err := k.reader.nextTransaction()
+		if err != nil {
+			return DataRow{}, false, err
+		}
+		if !more {
+			k.done = true
+			continue
+		}
+		for _, r := range rows {
+			if k.tombstone(&r) {
+				// Rows read earlier are earlier occurrences of the key
+				k.tombstones -= k.pending.removeKey(r.GetKey(), k.tombstone)
+				k.tombstones++
+			}
+			heap.Push(&k.pending, heapRow{DataRow: r, seq: k.seq})
+			k.seq++
+		}
+	}
+}
+
+// reverseKeyOrderedRowReader re-orders the output of a reverseTransactionReader
+// into descending key order.
+//
+// Reading backward, every row not yet read was written before all rows already
+// read, so its timestamp is below min_ts + skew_ms, where min_ts is the smallest
+// timestamp read so far (below or equal to it for a NullRow, whose timestamp is
+// the maximum at insertion). A buffered row whose timestamp is at or above
+// min_ts + max(skew_ms, 1) can therefore never be followed by a higher key, and
+// is safe to emit. Memory is bounded by the number of rows written within one
+// skew window.
+//
+// Deleted keys are dropped as in keyOrderedRowReader. Reading backward, the first
+// occurrence of a key read is its last in file order, so a row is dropped when a
+// tombstone for its key is pending.
+type reverseKeyOrderedRowReader struct {
+	reader     *reverseTransactionReader
+	margin     int64 // max(skew_ms, 1)
+	minTs      int64 // Smallest key timestamp of all rows read so far
+	read       bool  // At least one transaction has been read
+	pending    dataRowMaxHeap
+	seq        int64 // Minus the transactions read so far, so seq follows file order
+	done       bool
+	tombstone  func(row *DataRow) bool // Reports whether a row was written by Delete
+	tombstones int                     // Tombstones in pending
+}
+
+func newReverseKeyOrderedRowReader(reader *reverseTransactionReader, skewMs int, tombstone func(row *DataRow) bool) *reverseKeyOrderedRowReader {
+	return &reverseKeyOrderedRowReader{reader: reader, margin: max(int64(skewMs), 1), tombstone: tombstone}
+}
+
+// next returns the committed row with the next largest key. ok is false once
+// all committed rows have been returned.
+func (k *reverseKeyOrderedRowReader) next() (row DataRow, ok bool, err error) {
+	for {
+		if len(k.pending.dataRowHeap) > 0 {
+			maxTs := ExtractUUIDv7Timestamp(k.pending.dataRowHeap[0].GetKey())
+			if k.done || k.minTs+k.margin <= maxTs {
+				row := heap.Pop(&k.pending).(heapRow)
+				if k.tombstones > 0 && k.tombstone(&row.DataRow) {
+					k.tombstones--
+					continue
+				}
+				return row.DataRow, true, nil
+			}
+		}
+		if k.done {
+			return DataRow{}, false, nil
+		}
+		rows, minTs, more, err := k.reader.prevTransaction()
+		if err != nil {
+			return DataRow{}, false, err
+		}
+		if !more {
+			k.done = true
+			continue
+		}
+		if !k.read || minTs < k.minTs {
+			k.minTs = minTs
+			k.read = true
+		}
+		// A key appears at most once per transaction, so its rows share seq
+		k.seq--
+		for _, r := range rows {
+			if k.tombstones > 0 && k.pending.hasTombstone(r.GetKey(), k.tombstone) {
+				continue // Shadowed by a later Delete
+			}
+			if k.tombstone(&r) {
+				k.tombstones++
+			}
+			heap.Push(&k.pending, heapRow{DataRow: r, seq: k.seq})
+		}
+	}
+}
+
+// Scan calls fn for every committed row in ascending key order, stopping early
+// if fn returns false. A key written more than once is returned once per
+// occurrence, in file order.
+//
+// Rows from rolled back savepoints, fully rolled back transactions, and any
+// transaction still in progress are not visible. The scan is bounded by the file
+// size when Scan is called; rows appended while it runs are not returned.
+//
+// Parameters:
+//   - fn: Callback receiving each key and its raw JSON value; return false to stop
+//
+// Returns:
+//   - error: InvalidInputError (nil fn), ReadError, or CorruptDatabaseError
+//
+// Thread Safety: Safe for concurrent calls on the same FrozenDB instance
+func (db *FrozenDB) Scan(fn func(key uuid.UUID, value json.RawMessage) bool) error {
+	if fn == nil {
+		return NewInvalidInputError("fn cannot be nil", nil)
+	}
+	db.refresh()
+	return db.scanBounded(db.file.Size(), ScanOptions{}, fn)
+}
+
+// ScanOptions configures ScanWithOptions. The zero value scans exactly as Scan does.
+type ScanOptions struct {
+	// SkipCorrupt skips rows that fail framing or parity validation instead of
+	// aborting the scan with CorruptDatabaseError, so the readable rows of a
+	// damaged file can be recovered. The rest of a transaction with a skipped row
+	// is still returned if the transaction committed; a transaction whose end
+	// row was skipped is never returned, and one whose partial rollback cannot
+	// be resolved without the skipped row is dropped.
+	SkipCorrupt bool
+
+	// OnCorruptRow, if set, is called with the row index and the
+	// CorruptDatabaseError of each row skipped because of SkipCorrupt, in file
+	// order, before any row read after it is passed to fn.
+	OnCorruptRow func(index int64, err error)
+}
+
+// ScanWithOptions behaves like Scan, configured by opts. With opts.SkipCorrupt,
+// rows that cannot be parsed are reported to opts.OnCorruptRow and skipped; read
+// errors still end the scan.
+//
+// Parameters:
+//   - opts: Scan configuration; the zero value behaves like Scan
+//   - fn: Callback receiving each key and its raw JSON value; return false to stop
+//
+// Returns:
+//   - error: InvalidInputError (nil fn), ReadError, or CorruptDatabaseError
+//
+// Thread Safety: Safe for concurrent calls on the same FrozenDB instance
+func (db *FrozenDB) ScanWithOptions(opts ScanOptions, fn func(key uuid.UUID, value json.RawMessage) bool) error {
+	if fn == nil {
+		return NewInvalidInputError("fn cannot be nil", nil)
+	}
+	db.refresh()
+	return db.scanBounded(db.file.Size(), opts, fn)
+}
+
+// scanBounded implements ScanWithOptions over the complete rows in the first size
+// bytes of the file.
+func (db *FrozenDB) scanBounded(size int64, opts ScanOptions, fn func(key uuid.UUID, value json.RawMessage) bool) error {
+	reader := newCommittedRowReader(db.file, db.header.GetRowSize(), size)
+	if opts.SkipCorrupt {
+		reader.onCorrupt = func(index int64, err error) {
+			if opts.OnCorruptRow != nil {
+				opts.OnCorruptRow(index, err)
+			}
+		}
+	}
+	rows := newKeyOrderedRowReader(reader, db.header.GetSkewMs(), db.rowIsTombstone)
+	for {
+		row, ok, err := rows.next()
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return nil
+		}
+		value, err := db.decodeValue(row.GetValue())
+		if err != nil {
+			return err
+		}
+		if !fn(row.GetKey(), value) {
+			return nil
+		}
+	}
+}
+
+// ScanReverse calls fn for every committed row in descending key order, stopping
+// early if fn returns false.
+//
+// The file is read backward from the tail, one transaction at a time, so the
+// newest rows are returned without reading the rest of the file first. The
+// visibility rules are the same as Scan: rolled back rows and any transaction
+// still in progress are not returned, and the occurrences of a key written more
+// than once come in reverse file order. The scan is bounded by the file size when
+// ScanReverse is called.
+//
+// Parameters:
+//   - fn: Callback receiving each key and its raw JSON value; return false to stop
+//
+// Returns:
+//   - error: InvalidInputError (nil fn), ReadError, or CorruptDatabaseError
+//
+// Thread Safety: Safe for concurrent calls on the same FrozenDB instance
+func (db *FrozenDB) ScanReverse(fn func(key uuid.UUID, value json.RawMessage) bool) error {
+	if fn == nil {
+		return NewInvalidInputError("fn cannot be nil", nil)
+	}
+	db.refresh()
+	reader := newReverseTransactionReader(db.file, db.header.GetRowSize(), db.file.Size())
+	rows := newReverseKeyOrderedRowReader(reader, db.header.GetSkewMs(), db.rowIsTombstone)
+	for {
+		row, ok, err := rows.next()
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return nil
+		}
+		value, err := db.decodeValue(row.GetValue())
+		if err != nil {
+			return err
+		}
+		if !fn(row.GetKey(), value) {
+			return nil
+		}
+	}
+}
+
+// LastKey returns the highest committed key in the database without a full scan.
+//
+// The file is read backward from the tail, skipping checksum rows, NullRows, rolled
+// back rows, and any transaction still in progress. Because keys are only ordered
+// within the skew window, reading continues until a row older than the skew window
+// below the best key is found; no earlier row can hold a higher key.
+//
+// Returns:
+//   - uuid.UUID: The highest committed key (uuid.Nil when none exists)
+//   - bool: false when the database has no committed data rows
+//   - error: ReadError or CorruptDatabaseError
+//
+// Thread Safety: Safe for concurrent calls on the same FrozenDB instance
+func (db *FrozenDB) LastKey() (uuid.UUID, bool, error) {
+	db.refresh()
+	reader := newReverseTransactionReader(db.file, db.header.GetRowSize(), db.file.Size())
+	skewMs := int64(db.header.GetSkewMs())
+
+	best := uuid.Nil
+	found := false
+	for {
+		visible, minTs, ok, err := reader.prevTransaction()
+		if err != nil {
+			return uuid.Nil, false, err
+		}
+		if !ok {
+			break
+		}
+		for i := range visible {
+			key := visible[i].GetKey()
+			if !found || bytes.Compare(key[:], best[:]) > 0 {
+				best = key
+				found = true
+			}
+		}
+		if found && minTs <= ExtractUUIDv7Timestamp(best)-skewMs {
+			break
+		}
+	}
+	return best, found, nil
+}
+
+// IsEmpty reports whether the database has no committed data rows, so callers can
+// tell a database that is empty from one that lacks a particular key (Get returns
+// KeyNotFoundError in both cases). Scan and ScanReverse return nil without calling
+// fn on an empty database.
+//
+// The file is read backward from the tail and reading stops at the first visible
+// row that is not a tombstone and whose key has no later tombstone, so a populated
+// database is usually answered from its last transaction and a freshly created
+// one from its size alone. NullRows, rolled back rows, a transaction still in
+// progress, and deleted keys do not count as data.
+//
+// Returns:
+//   - bool: true when no committed data row exists
+//   - error: ReadError or CorruptDatabaseError
+//
+// Thread Safety: Safe for concurrent calls on the same FrozenDB instance
+func (db *FrozenDB) IsEmpty() (bool, error) {
+	db.refresh()
+	reader := newReverseTransactionReader(db.file, db.header.GetRowSize(), db.file.Size())
+	deleted := make(map[uuid.UUID]struct{})
+	for {
+		visible, _, ok, err := reader.prevTransaction()
+		if err != nil {
+			return false, err
+		}
+		if !ok {
+			return true, nil
+		}
+		// Reading backward, a key's later occurrences are read first
+		for i := len(visible) - 1; i >= 0; i-- {
+			key := visible[i].GetKey()
+			if db.rowIsTombstone(&visible[i]) {
+				deleted[key] = struct{}{}
+			} else if _, ok := deleted[key]; !ok {
+				return false, nil
+			}
+		}
+	}
+}
+
+// CountRange returns the number of committed rows whose key lies strictly between
+// after and before, counting exactly the rows Scan would return: deleted keys and
+// the tombstones that delete them are not counted. uuid.Nil leaves that side of
+// the range unbounded, so CountRange(uuid.Nil, uuid.Nil) counts every committed
+// row.
+//
+// Values are only decoded to recognize tombstones, never unmarshaled. With a
+// before bound the file is only read until the skew window guarantees that no
+// later row can hold a smaller key.
+//
+// Parameters:
+//   - after: Exclusive lower bound, or uuid.Nil
+//   - before: Exclusive upper bound, or uuid.Nil
+//
+// Returns:
+//   - int64: Number of committed rows in the range
+//   - error: ReadError or CorruptDatabaseError
+//
+// Thread Safety: Safe for concurrent calls on the same FrozenDB instance
+func (db *FrozenDB) CountRange(after, before uuid.UUID) (int64, error) {
+	db.refresh()
+	reader := newCommittedRowReader(db.file, db.header.GetRowSize(), db.file.Size())
+	rows := newKeyOrderedRowReader(reader, db.header.GetSkewMs(), db.rowIsTombstone)
+
+	var count int64
+	for {
+		row, ok, err := rows.next()
+		if err != nil {
+			return 0, err
+		}
+		if !ok {
+			return count, nil
+		}
+		key := row.GetKey()
+		if before != uuid.Nil && bytes.Compare(key[:], before[:]) >= 0 {
+			return count, nil
+		}
+		if after != uuid.Nil && bytes.Compare(key[:], after[:]) <= 0 {
+			continue
+		}
+		count++
+	}
+}
+
+// ScanPrefix calls fn, in ascending key order, for every committed row whose key
+// bytes start with prefix, stopping early if fn returns false. Because UUIDv7 keys
+// begin with their millisecond timestamp, a prefix selects a coarse time bucket:
+// the first 5 bytes cover about 0.26 seconds, the first 4 about 67 seconds.
+//
+// The read starts at a transaction found by binary search to hold no matching
+// key before it: any row appended after a key has a timestamp above the key's
+// minus skew_ms, so no matching key precedes a row whose timestamp is at least
+// skew_ms below the smallest key with prefix. Reading stops once keys pass the
+// prefix. Visibility rules are the same as Scan, and an empty prefix scans every
+// committed row.
+//
+// Parameters:
+//   - prefix: Leading key bytes to match (at most 16)
+//   - fn: Callback receiving each key and its raw JSON value; return false to stop
+//
+// Returns:
+//   - error: InvalidInputError (prefix longer than 16 bytes or nil fn), ReadError,
+//     or CorruptDatabaseError
+//
+// Thread Safety: Safe for concurrent calls on the same FrozenDB instance
+func (db *FrozenDB) ScanPrefix(prefix []byte, fn func(key uuid.UUID, value json.RawMessage) bool) error {
+	if len(prefix) > len(uuid.UUID{}) {
+		return NewInvalidInputError(fmt.Sprintf("prefix must be at most %d bytes, got %d", len(uuid.UUID{}), len(prefix)), nil)
+	}
+	if fn == nil {
+		return NewInvalidInputError("fn cannot be nil", nil)
+	}
+	db.refresh()
+	size := db.file.Size()
+
+	var lowest uuid.UUID
+	copy(lowest[:], prefix)
+	start, err := db.prefixScanStart(ExtractUUIDv7Timestamp(lowest)-int64(db.header.GetSkewMs()), size)
+	if err != nil {
+		return err
+	}
+
+	reader := newCommittedRowReader(db.file, db.header.GetRowSize(), size)
+	reader.index = start
+	rows := newKeyOrderedRowReader(reader, db.header.GetSkewMs(), db.rowIsTombstone)
+	for {
+		row, ok, err := rows.next()
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return nil
+		}
+		key := row.GetKey()
+		if cmp := bytes.Compare(key[:len(prefix)], prefix); cmp < 0 {
+			continue
+		} else if cmp > 0 {
+			return nil
+		}
+		value, err := db.decodeValue(row.GetValue())
+		if err != nil {
+			return err
+		}
+		if !fn(key, value) {
+			return nil
+		}
+	}
+}
+
+// prefixScanStart returns the index of a transaction start at or before which no
+// key with a timestamp above floorTs + skew_ms can appear: the transaction holding
+// a complete row with a timestamp at or below floorTs, found by binary search over
+// the complete rows in the first size bytes of the file. Timestamps are only
+// ordered within the skew window, so the row found need not be the last such row;
+// 0 is returned when none is found.
+func (db *FrozenDB) prefixScanStart(floorTs int64, size int64) (int64, error) {
+	rowCount := int64(0)
+	if size > int64(HEADER_SIZE) {
+		rowCount = (size - int64(HEADER_SIZE)) / int64(db.header.GetRowSize())
+	}
+	lo, hi := int64(0), rowCount // Row lo satisfies the condition (0 stands for the file start)
+	for hi-lo > 1 {
+		mid := lo + (hi-lo)/2
+		index := mid
+		if index%int64(db.header.GetChecksumInterval()+1) == 0 {
+			index-- // Checksum rows carry no key; use the row before
+		}
+		ts, err := db.rowKeyTimestamp(index)
+		if err != nil {
+			return 0, err
+		}
+		if ts <= floorTs {
+			lo = index
+			if index < mid {
+				hi = mid
+			}
+		} else {
+			hi = mid
+		}
+	}
+	if lo == 0 {
+		return 0, nil
+	}
+	return db.finder.GetTransactionStart(lo)
+}
+
+// rowKeyTimestamp returns the key timestamp of the data or null row at index.
+func (db *FrozenDB) rowKeyTimestamp(index int64) (int64, error) {
+	rowBytes, err := db.readRowAtIndex(index)
+	if err != nil {
+		return 0, err
+	}
+	var ru RowUnion
+	if err := ru.UnmarshalText(rowBytes); err != nil {
+		return 0, NewCorruptDatabaseError(fmt.Sprintf("failed to parse row at index %d", index), err)
+	}
+	switch {
+	case ru.DataRow != nil:
+		return ExtractUUIDv7Timestamp(ru.DataRow.GetKey()), nil
+	case ru.NullRow != nil:
+		return ExtractUUIDv7Timestamp(ru.NullRow.GetKey()), nil
+	default:
+		return 0, NewCorruptDatabaseError(fmt.Sprintf("row at index %d is not a data or null row", index), nil)
+	}
+}
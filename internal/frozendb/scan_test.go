@@ -0,0 +1,677 @@
+package frozendb
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+type scannedRow struct {
+	key   uuid.UUID
+	value string
+}
+
+func scanAll(t *testing.T, db *FrozenDB) []scannedRow {
+	t.Helper()
+	var rows []scannedRow
+	err := db.Scan(func(key uuid.UUID, value json.RawMessage) bool {
+		rows = append(rows, scannedRow{key: key, value: string(value)})
+		return true
+	})
+	if err != nil {
+		t.Fatalf("Scan: %v", err)
+	}
+	return rows
+}
+
+func openForScan(t *testing.T, path string) *FrozenDB {
+	t.Helper()
+	db, err := NewFrozenDB(path, MODE_READ, FinderStrategySimple)
+	if err != nil {
+		t.Fatalf("NewFrozenDB: %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+	return db
+}
+
+func TestScan_EmptyDatabase(t *testing.T) {
+	path := setupCreate(t, t.TempDir(), 0)
+	db := openForScan(t, path)
+
+	if rows := scanAll(t, db); len(rows) != 0 {
+		t.Fatalf("expected no rows, got %d", len(rows))
+	}
+}
+
+func TestScan_NilCallback(t *testing.T) {
+	path := setupCreate(t, t.TempDir(), 0)
+	db := openForScan(t, path)
+
+	var invalidInput *InvalidInputError
+	if err := db.Scan(nil); !errors.As(err, &invalidInput) {
+		t.Fatalf("expected InvalidInputError, got %v", err)
+	}
+}
+
+func TestScan_AppliesTransactionVisibility(t *testing.T) {
+	path := setupCreate(t, t.TempDir(), 0)
+
+	// Committed transaction: both rows visible
+	tx, db := openAndBegin(t, path)
+	mustAdd(t, tx, uuidFromTS(1000), `{"n":1}`)
+	mustAdd(t, tx, uuidFromTS(1001), `{"n":2}`)
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+	db.Close()
+
+	// Partial rollback to savepoint 1: only the first row visible
+	tx, db = openAndBegin(t, path)
+	mustAdd(t, tx, uuidFromTS(1002), `{"n":3}`)
+	if err := tx.Savepoint(); err != nil {
+		t.Fatalf("Savepoint: %v", err)
+	}
+	mustAdd(t, tx, uuidFromTS(1003), `{"n":4}`)
+	if err := tx.Rollback(1); err != nil {
+		t.Fatalf("Rollback: %v", err)
+	}
+	db.Close()
+
+	// Full rollback: nothing visible
+	tx, db = openAndBegin(t, path)
+	mustAdd(t, tx, uuidFromTS(1004), `{"n":5}`)
+	if err := tx.Rollback(0); err != nil {
+		t.Fatalf("Rollback: %v", err)
+	}
+	db.Close()
+
+	// Empty transaction produces a NullRow
+	dbAddNullRow(t, path)
+
+	// In-progress transaction: not visible
+	tx, db = openAndBegin(t, path)
+	mustAdd(t, tx, uuidFromTS(1005), `{"n":6}`)
+	defer db.Close()
+
+	reader := openForScan(t, path)
+	got := scanAll(t, reader)
+	want := []scannedRow{
+		{uuidFromTS(1000), `{"n":1}`},
+		{uuidFromTS(1001), `{"n":2}`},
+		{uuidFromTS(1002), `{"n":3}`},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d rows, want %d: %v", len(got), len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("row %d = %v, want %v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestScan_KeyOrderWithinSkew(t *testing.T) {
+	path := setupCreate(t, t.TempDir(), 0)
+
+	// File order differs from key order but stays within the 5000ms skew window
+	timestamps := []int{10000, 8000, 12000, 9000, 20000, 16000}
+	addDataRowsInOrder(t, path, timestamps)
+
+	db := openForScan(t, path)
+	got := scanAll(t, db)
+	wantOrder := []int{8000, 9000, 10000, 12000, 16000, 20000}
+	if len(got) != len(wantOrder) {
+		t.Fatalf("got %d rows, want %d", len(got), len(wantOrder))
+	}
+	for i, ts := range wantOrder {
+		if got[i].key != uuidFromTS(ts) {
+			t.Errorf("row %d key = %s, want key for ts %d", i, got[i].key, ts)
+		}
+	}
+}
+
+func TestScan_StopsWhenCallbackReturnsFalse(t *testing.T) {
+	path := setupCreate(t, t.TempDir(), 0)
+	addDataRowsInOrder(t, path, []int{1000, 2000, 3000})
+
+	db := openForScan(t, path)
+	calls := 0
+	err := db.Scan(func(uuid.UUID, json.RawMessage) bool {
+		calls++
+		return calls < 2
+	})
+	if err != nil {
+		t.Fatalf("Scan: %v", err)
+	}
+	if calls != 2 {
+		t.Fatalf("expected 2 callback calls, got %d", calls)
+	}
+}
+
+func TestScanWithOptions_SkipCorrupt(t *testing.T) {
+	path := setupCreate(t, t.TempDir(), 0)
+	addDataRowsInOrder(t, path, []int{1000, 2000, 3000})
+
+	// Change the value of the second row without updating its parity
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	pos := bytes.Index(data, []byte(`{"ts":1}`))
+	if pos < 0 {
+		t.Fatal("second row value not found")
+	}
+	data[pos+6] = '7'
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	// The binary search finder opens without reading every row
+	db, err := NewFrozenDB(path, MODE_READ, FinderStrategyBinarySearch)
+	if err != nil {
+		t.Fatalf("NewFrozenDB: %v", err)
+	}
+	defer db.Close()
+
+	var corrupt *CorruptDatabaseError
+	if err := db.Scan(func(uuid.UUID, json.RawMessage) bool { return true }); !errors.As(err, &corrupt) {
+		t.Fatalf("Scan: expected CorruptDatabaseError, got %v", err)
+	}
+
+	var skipped []int64
+	var keys []uuid.UUID
+	opts := ScanOptions{
+		SkipCorrupt: true,
+		OnCorruptRow: func(index int64, err error) {
+			if !errors.As(err, &corrupt) {
+				t.Errorf("OnCorruptRow(%d): expected CorruptDatabaseError, got %v", index, err)
+			}
+			skipped = append(skipped, index)
+		},
+	}
+	err = db.ScanWithOptions(opts, func(key uuid.UUID, value json.RawMessage) bool {
+		keys = append(keys, key)
+		return true
+	})
+	if err != nil {
+		t.Fatalf("ScanWithOptions: %v", err)
+	}
+	if !slices.Equal(skipped, []int64{2}) {
+		t.Errorf("skipped rows = %v, want [2]", skipped)
+	}
+	if want := []uuid.UUID{uuidFromTS(1000), uuidFromTS(3000)}; !slices.Equal(keys, want) {
+		t.Errorf("scanned keys = %v, want %v", keys, want)
+	}
+
+	// The zero value behaves like Scan
+	if err := db.ScanWithOptions(ScanOptions{}, func(uuid.UUID, json.RawMessage) bool { return true }); !errors.As(err, &corrupt) {
+		t.Errorf("ScanWithOptions(zero): expected CorruptDatabaseError, got %v", err)
+	}
+}
+
+func scanAllReverse(t *testing.T, db *FrozenDB) []scannedRow {
+	t.Helper()
+	var rows []scannedRow
+	err := db.ScanReverse(func(key uuid.UUID, value json.RawMessage) bool {
+		rows = append(rows, scannedRow{key: key, value: string(value)})
+		return true
+	})
+	if err != nil {
+		t.Fatalf("ScanReverse: %v", err)
+	}
+	return rows
+}
+
+func TestScanReverse_NilCallbackAndEmptyDatabase(t *testing.T) {
+	path := setupCreate(t, t.TempDir(), 0)
+	db := openForScan(t, path)
+
+	var invalidInput *InvalidInputError
+	if err := db.ScanReverse(nil); !errors.As(err, &invalidInput) {
+		t.Fatalf("expected InvalidInputError, got %v", err)
+	}
+	if rows := scanAllReverse(t, db); len(rows) != 0 {
+		t.Fatalf("expected no rows, got %d", len(rows))
+	}
+}
+
+func TestScanReverse_KeyOrderWithinSkew(t *testing.T) {
+	path := setupCreate(t, t.TempDir(), 0)
+
+	// File order differs from key order but stays within the 5000ms skew window
+	addDataRowsInOrder(t, path, []int{10000, 8000, 12000, 9000, 20000, 16000})
+
+	db := openForScan(t, path)
+	got := scanAllReverse(t, db)
+	wantOrder := []int{20000, 16000, 12000, 10000, 9000, 8000}
+	if len(got) != len(wantOrder) {
+		t.Fatalf("got %d rows, want %d", len(got), len(wantOrder))
+	}
+	for i, ts := range wantOrder {
+		if got[i].key != uuidFromTS(ts) {
+			t.Errorf("row %d key = %s, want key for ts %d", i, got[i].key, ts)
+		}
+	}
+}
+
+func TestScanReverse_StopsWhenCallbackReturnsFalse(t *testing.T) {
+	path := setupCreate(t, t.TempDir(), 0)
+	addDataRowsInOrder(t, path, []int{1000, 2000, 3000})
+
+	db := openForScan(t, path)
+	var keys []uuid.UUID
+	err := db.ScanReverse(func(key uuid.UUID, _ json.RawMessage) bool {
+		keys = append(keys, key)
+		return len(keys) < 2
+	})
+	if err != nil {
+		t.Fatalf("ScanReverse: %v", err)
+	}
+	if len(keys) != 2 || keys[0] != uuidFromTS(3000) || keys[1] != uuidFromTS(2000) {
+		t.Fatalf("expected keys for ts 3000 and 2000, got %v", keys)
+	}
+}
+
+// TestScanReverse_MatchesScan writes transactions with random outcomes and
+// out-of-order keys, and checks that ScanReverse returns exactly the rows of
+// Scan in reverse
+func TestScanReverse_MatchesScan(t *testing.T) {
+	path := setupCreate(t, t.TempDir(), 0)
+	db, err := NewFrozenDB(path, MODE_WRITE, FinderStrategySimple)
+	if err != nil {
+		t.Fatalf("NewFrozenDB: %v", err)
+	}
+	defer db.Close()
+
+	rng := rand.New(rand.NewSource(1))
+	used := make(map[int]bool)
+	maxTs := 100000
+	nextKey := func() uuid.UUID {
+		for {
+			// Up to 4000ms behind the maximum, within the 5000ms skew window
+			ts := maxTs - 4000 + rng.Intn(6000)
+			if !used[ts] {
+				used[ts] = true
+				maxTs = max(maxTs, ts)
+				return uuidFromTS(ts)
+			}
+		}
+	}
+
+	for i := 0; i < 200; i++ {
+		tx, err := db.BeginTx()
+		if err != nil {
+			t.Fatalf("BeginTx: %v", err)
+		}
+		for n := rng.Intn(4); n > 0; n-- {
+			mustAdd(t, tx, nextKey(), fmt.Sprintf(`{"tx":%d}`, i))
+			if rng.Intn(3) == 0 {
+				if err := tx.Savepoint(); err != nil {
+					t.Fatalf("Savepoint: %v", err)
+				}
+			}
+		}
+		switch rng.Intn(4) {
+		case 0:
+			err = tx.Rollback(0)
+		case 1:
+			err = tx.Rollback(1)
+			if errors.As(err, new(*InvalidInputError)) {
+				err = tx.Commit()
+			}
+		default:
+			err = tx.Commit()
+		}
+		if err != nil {
+			t.Fatalf("ending transaction %d: %v", i, err)
+		}
+	}
+	// A transaction still in progress is not visible
+	tx, err := db.BeginTx()
+	if err != nil {
+		t.Fatalf("BeginTx: %v", err)
+	}
+	mustAdd(t, tx, nextKey(), `{"tx":"open"}`)
+
+	reader := openForScan(t, path)
+	forward := scanAll(t, reader)
+	reverse := scanAllReverse(t, reader)
+	if len(forward) == 0 || len(reverse) != len(forward) {
+		t.Fatalf("ScanReverse returned %d rows, Scan returned %d", len(reverse), len(forward))
+	}
+	for i := range reverse {
+		if reverse[i] != forward[len(forward)-1-i] {
+			t.Fatalf("row %d = %v, want %v", i, reverse[i], forward[len(forward)-1-i])
+		}
+	}
+}
+
+func TestVisibleTransactionRows_MissingSavepoint(t *testing.T) {
+	rows := []DataRow{
+		{baseRow[*DataRowPayload]{StartControl: START_TRANSACTION, EndControl: EndControl{'R', '2'}}},
+	}
+	var corrupt *CorruptDatabaseError
+	if _, err := visibleTransactionRows(rows); !errors.As(err, &corrupt) {
+		t.Fatalf("expected CorruptDatabaseError, got %v", err)
+	}
+}
+
+func mustAdd(t *testing.T, tx *Transaction, key uuid.UUID, value string) {
+	t.Helper()
+	if err := tx.AddRow(key, json.RawMessage(value)); err != nil {
+		t.Fatalf("AddRow: %v", err)
+	}
+}
+
+func TestLastKey_EmptyDatabase(t *testing.T) {
+	path := setupCreate(t, t.TempDir(), 0)
+	db := openForScan(t, path)
+
+	key, ok, err := db.LastKey()
+	if err != nil {
+		t.Fatalf("LastKey: %v", err)
+	}
+	if ok || key != uuid.Nil {
+		t.Fatalf("expected no key, got %s (ok=%v)", key, ok)
+	}
+}
+
+func TestLastKey_SkipsUncommittedAndNullRows(t *testing.T) {
+	path := setupCreate(t, t.TempDir(), 0)
+	addDataRowsInOrder(t, path, []int{1000, 2000})
+
+	// Fully rolled back transaction
+	tx, db := openAndBegin(t, path)
+	mustAdd(t, tx, uuidFromTS(3000), `{}`)
+	if err := tx.Rollback(0); err != nil {
+		t.Fatalf("Rollback: %v", err)
+	}
+	db.Close()
+
+	// Partially rolled back transaction: only the row before the savepoint survives
+	tx, db = openAndBegin(t, path)
+	mustAdd(t, tx, uuidFromTS(4000), `{}`)
+	if err := tx.Savepoint(); err != nil {
+		t.Fatalf("Savepoint: %v", err)
+	}
+	mustAdd(t, tx, uuidFromTS(5000), `{}`)
+	if err := tx.Rollback(1); err != nil {
+		t.Fatalf("Rollback: %v", err)
+	}
+	db.Close()
+
+	dbAddNullRow(t, path)
+
+	// Transaction still in progress at the tail
+	tx, db = openAndBegin(t, path)
+	mustAdd(t, tx, uuidFromTS(6000), `{}`)
+	mustAdd(t, tx, uuidFromTS(7000), `{}`)
+	defer db.Close()
+
+	reader := openForScan(t, path)
+	key, ok, err := reader.LastKey()
+	if err != nil {
+		t.Fatalf("LastKey: %v", err)
+	}
+	if !ok || key != uuidFromTS(4000) {
+		t.Fatalf("LastKey = %s (ok=%v), want %s", key, ok, uuidFromTS(4000))
+	}
+}
+
+func TestLastKey_HighestKeyWithinSkew(t *testing.T) {
+	path := setupCreate(t, t.TempDir(), 0)
+
+	// The highest key is not the last row written, but is within the skew window
+	addDataRowsInOrder(t, path, []int{1000, 10000, 8000, 7000})
+
+	db := openForScan(t, path)
+	key, ok, err := db.LastKey()
+	if err != nil {
+		t.Fatalf("LastKey: %v", err)
+	}
+	if !ok || key != uuidFromTS(10000) {
+		t.Fatalf("LastKey = %s (ok=%v), want %s", key, ok, uuidFromTS(10000))
+	}
+}
+
+func requireIsEmpty(t *testing.T, db *FrozenDB, want bool) {
+	t.Helper()
+	empty, err := db.IsEmpty()
+	if err != nil {
+		t.Fatalf("IsEmpty: %v", err)
+	}
+	if empty != want {
+		t.Fatalf("IsEmpty = %v, want %v", empty, want)
+	}
+}
+
+func TestIsEmpty(t *testing.T) {
+	path := setupCreate(t, t.TempDir(), 0)
+	reader := openForScan(t, path)
+
+	// A freshly created database is empty; Get still reports the missing key
+	requireIsEmpty(t, reader, true)
+	var notFound *KeyNotFoundError
+	var value map[string]any
+	if err := reader.Get(uuidFromTS(1000), &value); !errors.As(err, &notFound) {
+		t.Fatalf("expected KeyNotFoundError, got %v", err)
+	}
+	if rows := scanAllReverse(t, reader); len(rows) != 0 {
+		t.Fatalf("expected no rows from ScanReverse, got %d", len(rows))
+	}
+
+	// Rolled back rows, NullRows, and a transaction in progress are not data
+	tx, db := openAndBegin(t, path)
+	mustAdd(t, tx, uuidFromTS(1000), `{}`)
+	if err := tx.Rollback(0); err != nil {
+		t.Fatalf("Rollback: %v", err)
+	}
+	db.Close()
+	dbAddNullRow(t, path)
+	tx, db = openAndBegin(t, path)
+	defer db.Close()
+	mustAdd(t, tx, uuidFromTS(2000), `{}`)
+	requireIsEmpty(t, reader, true)
+
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+	requireIsEmpty(t, reader, false)
+	requireIsEmpty(t, db, false)
+}
+
+func TestCountRange(t *testing.T) {
+	path := setupCreate(t, t.TempDir(), 0)
+	// Out-of-order keys within the skew window
+	addDataRowsInOrder(t, path, []int{1000, 3000, 2000, 4000})
+
+	// Fully rolled back and partially rolled back transactions
+	tx, db := openAndBegin(t, path)
+	mustAdd(t, tx, uuidFromTS(4500), `{}`)
+	if err := tx.Rollback(0); err != nil {
+		t.Fatalf("Rollback: %v", err)
+	}
+	db.Close()
+	tx, db = openAndBegin(t, path)
+	mustAdd(t, tx, uuidFromTS(5000), `{}`)
+	if err := tx.Savepoint(); err != nil {
+		t.Fatalf("Savepoint: %v", err)
+	}
+	mustAdd(t, tx, uuidFromTS(6000), `{}`)
+	if err := tx.Rollback(1); err != nil {
+		t.Fatalf("Rollback: %v", err)
+	}
+	db.Close()
+
+	// Transaction still in progress at the tail
+	tx, db = openAndBegin(t, path)
+	mustAdd(t, tx, uuidFromTS(7000), `{}`)
+	defer db.Close()
+
+	reader := openForScan(t, path)
+	tests := []struct {
+		name          string
+		after, before uuid.UUID
+		want          int64
+	}{
+		{name: "unbounded", want: 5},
+		{name: "after is exclusive", after: uuidFromTS(1000), want: 4},
+		{name: "before is exclusive", before: uuidFromTS(3000), want: 2},
+		{name: "both bounds", after: uuidFromTS(1000), before: uuidFromTS(5000), want: 3},
+		{name: "rolled back keys", after: uuidFromTS(4000), before: uuidFromTS(7000), want: 1},
+		{name: "uncommitted key", after: uuidFromTS(5000), want: 0},
+		{name: "empty range", after: uuidFromTS(4000), before: uuidFromTS(2000), want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := reader.CountRange(tt.after, tt.before)
+			if err != nil {
+				t.Fatalf("CountRange: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("CountRange = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScanPrefix(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "prefix.fdb")
+	createWithInterval(t, path, MIN_CHECKSUM_INTERVAL)
+	db, err := NewFrozenDB(path, MODE_WRITE, FinderStrategyInMemory)
+	if err != nil {
+		t.Fatalf("NewFrozenDB: %v", err)
+	}
+	defer db.Close()
+
+	// 30 transactions of 10 rows crossing checksum rows, with timestamps running up
+	// to 3000 ms behind the trend and every fourth transaction rolled back
+	var keys []uuid.UUID
+	for txn := 0; txn < 30; txn++ {
+		tx, err := db.BeginTx()
+		if err != nil {
+			t.Fatalf("BeginTx: %v", err)
+		}
+		for j := 0; j < 10; j++ {
+			i := txn*10 + j
+			key := uuidFromTS(100000 + i*100 - (i%7)*500)
+			mustAdd(t, tx, key, fmt.Sprintf(`{"i":%d}`, i))
+			keys = append(keys, key)
+		}
+		if txn%4 == 3 {
+			err = tx.Rollback(0)
+		} else {
+			err = tx.Commit()
+		}
+		if err != nil {
+			t.Fatalf("ending transaction: %v", err)
+		}
+	}
+	all := scanAll(t, db)
+
+	var prefixes [][]byte
+	for _, n := range []int{0, 4, 5, 6, 16} {
+		for _, key := range []uuid.UUID{keys[0], keys[37], keys[150], keys[299]} {
+			prefixes = append(prefixes, append([]byte(nil), key[:n]...))
+		}
+	}
+	missing := uuidFromTS(1)
+	prefixes = append(prefixes, missing[:6])
+	for _, prefix := range prefixes {
+		var want []scannedRow
+		for _, row := range all {
+			if bytes.HasPrefix(row.key[:], prefix) {
+				want = append(want, row)
+			}
+		}
+		var got []scannedRow
+		if err := db.ScanPrefix(prefix, func(key uuid.UUID, value json.RawMessage) bool {
+			got = append(got, scannedRow{key: key, value: string(value)})
+			return true
+		}); err != nil {
+			t.Fatalf("ScanPrefix(%x): %v", prefix, err)
+		}
+		if !slices.Equal(got, want) {
+			t.Errorf("ScanPrefix(%x) returned %d rows, want %d", prefix, len(got), len(want))
+		}
+	}
+
+	var calls int
+	if err := db.ScanPrefix(nil, func(uuid.UUID, json.RawMessage) bool { calls++; return false }); err != nil || calls != 1 {
+		t.Errorf("ScanPrefix stopping early: %d calls, %v", calls, err)
+	}
+
+	var invalid *InvalidInputError
+	if err := db.ScanPrefix(make([]byte, 17), func(uuid.UUID, json.RawMessage) bool { return true }); !errors.As(err, &invalid) {
+		t.Errorf("17-byte prefix: expected InvalidInputError, got %v", err)
+	}
+	if err := db.ScanPrefix(nil, nil); !errors.As(err, &invalid) {
+		t.Errorf("nil fn: expected InvalidInputError, got %v", err)
+	}
+}
+
+func TestPrefixScanStart_SkipsEarlierTransactions(t *testing.T) {
+	path := setupCreate(t, t.TempDir(), 0)
+	db, err := NewFrozenDB(path, MODE_WRITE, FinderStrategySimple)
+	if err != nil {
+		t.Fatalf("NewFrozenDB: %v", err)
+	}
+	defer db.Close()
+	for txn := 0; txn < 20; txn++ {
+		tx, err := db.BeginTx()
+		if err != nil {
+			t.Fatalf("BeginTx: %v", err)
+		}
+		for j := 0; j < 5; j++ {
+			mustAdd(t, tx, uuidFromTS(1000+(txn*5+j)*confSkewMs), `{}`)
+		}
+		if err := tx.Commit(); err != nil {
+			t.Fatalf("Commit: %v", err)
+		}
+	}
+
+	// The row with timestamp 1000+52*skew is the third row of transaction 10 (rows 51-55)
+	start, err := db.prefixScanStart(1000+52*confSkewMs, db.file.Size())
+	if err != nil {
+		t.Fatalf("prefixScanStart: %v", err)
+	}
+	if start != 51 {
+		t.Errorf("prefixScanStart = %d, want 51", start)
+	}
+}
+
+func TestScan_RepeatedKeyFileOrder(t *testing.T) {
+	path := setupCreate(t, t.TempDir(), 5000)
+	key := uuidFromTS(1000)
+	for v := 1; v <= 4; v++ {
+		writeTx(t, path, false, func(tx *Transaction) error {
+			return tx.AddRow(key, json.RawMessage(fmt.Sprintf(`{"v":%d}`, v)))
+		})
+	}
+
+	db := openForScan(t, path)
+	want := []string{`{"v":1}`, `{"v":2}`, `{"v":3}`, `{"v":4}`}
+	var got []string
+	for _, row := range scanAll(t, db) {
+		got = append(got, row.value)
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("Scan values = %v, want %v", got, want)
+	}
+	got = got[:0]
+	for _, row := range scanAllReverse(t, db) {
+		got = append(got, row.value)
+	}
+	slices.Reverse(want)
+	if !slices.Equal(got, want) {
+		t.Errorf("ScanReverse values = %v, want %v", got, want)
+	}
+}
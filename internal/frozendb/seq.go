@@ -0,0 +1,99 @@
+package frozendb
+
+import (
+	"bytes"
+	"encoding/json"
+	"iter"
+
+	"github.com/google/uuid"
+)
+
+// Seq returns an iterator over every committed row in ascending key order, for
+// use with range:
+//
+//	for key, value := range db.Seq() {
+//		...
+//	}
+//	if err := db.SeqErr(); err != nil {
+//		...
+//	}
+//
+// Rows are streamed as by Scan, with the same visibility rules. Each range over
+// the iterator starts a new scan bounded by the file size at that moment. An
+// iterator cannot yield errors, so a read or corruption error ends the loop early
+// and is reported by SeqErr.
+//
+// Thread Safety: Safe for concurrent calls on the same FrozenDB instance, but
+// SeqErr only reports the last iteration to finish; goroutines iterating
+// concurrently should use Scan to receive their own error
+func (db *FrozenDB) Seq() iter.Seq2[uuid.UUID, json.RawMessage] {
+	return db.SeqRange(uuid.Nil, uuid.Nil)
+}
+
+// SeqRange returns an iterator, like Seq, over the committed rows whose key lies
+// in the half-open range [start, end). uuid.Nil leaves that side of the range
+// unbounded. With a start bound the read begins at a transaction found by binary
+// search, as ScanPrefix does; with an end bound it stops once keys reach end.
+//
+// Thread Safety: Safe for concurrent calls on the same FrozenDB instance (see Seq)
+func (db *FrozenDB) SeqRange(start, end uuid.UUID) iter.Seq2[uuid.UUID, json.RawMessage] {
+	return func(yield func(uuid.UUID, json.RawMessage) bool) {
+		err := db.scanRange(start, end, yield)
+		db.seqMu.Lock()
+		db.seqErr = err
+		db.seqMu.Unlock()
+	}
+}
+
+// SeqErr returns the error that ended the last iteration over an iterator from
+// Seq or SeqRange on this instance, or nil if it visited every row in range or
+// the loop body stopped it. Check it after the range loop.
+//
+// Returns:
+//   - error: nil, ReadError, or CorruptDatabaseError
+func (db *FrozenDB) SeqErr() error {
+	db.seqMu.Lock()
+	defer db.seqMu.Unlock()
+	return db.seqErr
+}
+
+// scanRange calls fn, in ascending key order, for every committed row whose key
+// lies in [start, end), stopping early if fn returns false. uuid.Nil leaves that
+// side of the range unbounded.
+func (db *FrozenDB) scanRange(start, end uuid.UUID, fn func(key uuid.UUID, value json.RawMessage) bool) error {
+	db.refresh()
+	size := db.file.Size()
+
+	reader := newCommittedRowReader(db.file, db.header.GetRowSize(), size)
+	if start != uuid.Nil {
+		first, err := db.prefixScanStart(ExtractUUIDv7Timestamp(start)-int64(db.header.GetSkewMs()), size)
+		if err != nil {
+			return err
+		}
+		reader.index = first
+	}
+	rows := newKeyOrderedRowReader(reader, db.header.GetSkewMs(), db.rowIsTombstone)
+	for {
+		row, ok, err := rows.next()
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return nil
+		}
+		key := row.GetKey()
+		if bytes.Compare(key[:], start[:]) < 0 {
+			continue
+		}
+		if end != uuid.Nil && bytes.Compare(key[:], end[:]) >= 0 {
+			return nil
+		}
+		value, err := db.decodeValue(row.GetValue())
+		if err != nil {
+			return err
+		}
+		if !fn(key, value) {
+			return nil
+		}
+	}
+}
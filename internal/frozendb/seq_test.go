@@ -0,0 +1,106 @@
+package frozendb
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"slices"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func seqKeys(t *testing.T, db *FrozenDB, start, end uuid.UUID) []uuid.UUID {
+	t.Helper()
+	var keys []uuid.UUID
+	for key := range db.SeqRange(start, end) {
+		keys = append(keys, key)
+	}
+	if err := db.SeqErr(); err != nil {
+		t.Fatalf("SeqErr: %v", err)
+	}
+	return keys
+}
+
+func TestSeq_MatchesScan(t *testing.T) {
+	path := setupCreate(t, t.TempDir(), 0)
+	addDataRowsInOrder(t, path, []int{10000, 8000, 12000, 9000, 20000, 16000})
+	db := openForScan(t, path)
+
+	var got []scannedRow
+	for key, value := range db.Seq() {
+		got = append(got, scannedRow{key: key, value: string(value)})
+	}
+	if err := db.SeqErr(); err != nil {
+		t.Fatalf("SeqErr: %v", err)
+	}
+	if want := scanAll(t, db); !slices.Equal(got, want) {
+		t.Errorf("Seq = %v, want %v", got, want)
+	}
+
+	// Breaking out of the loop stops the scan without an error
+	calls := 0
+	for range db.Seq() {
+		calls++
+		break
+	}
+	if calls != 1 || db.SeqErr() != nil {
+		t.Errorf("break: %d iterations, SeqErr %v", calls, db.SeqErr())
+	}
+}
+
+func TestSeqRange(t *testing.T) {
+	path := setupCreate(t, t.TempDir(), 0)
+	addDataRowsInOrder(t, path, []int{1000, 3000, 2000, 40000, 36000, 50000})
+	db := openForScan(t, path)
+
+	tests := []struct {
+		name       string
+		start, end uuid.UUID
+		want       []int
+	}{
+		{"unbounded", uuid.Nil, uuid.Nil, []int{1000, 2000, 3000, 36000, 40000, 50000}},
+		{"start inclusive", uuidFromTS(3000), uuid.Nil, []int{3000, 36000, 40000, 50000}},
+		{"start past the skew window", uuidFromTS(36000), uuid.Nil, []int{36000, 40000, 50000}},
+		{"end exclusive", uuid.Nil, uuidFromTS(36000), []int{1000, 2000, 3000}},
+		{"both bounds", uuidFromTS(2000), uuidFromTS(50000), []int{2000, 3000, 36000, 40000}},
+		{"empty", uuidFromTS(60000), uuid.Nil, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var want []uuid.UUID
+			for _, ts := range tt.want {
+				want = append(want, uuidFromTS(ts))
+			}
+			if got := seqKeys(t, db, tt.start, tt.end); !slices.Equal(got, want) {
+				t.Errorf("SeqRange = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestSeqErr_ReportsCorruption(t *testing.T) {
+	path := setupCreate(t, t.TempDir(), 0)
+	addDataRowsInOrder(t, path, []int{1000, 2000, 3000})
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	data[bytes.Index(data, []byte(`{"ts":1}`))+6] = '7'
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	db, err := NewFrozenDB(path, MODE_READ, FinderStrategyBinarySearch)
+	if err != nil {
+		t.Fatalf("NewFrozenDB: %v", err)
+	}
+	defer db.Close()
+
+	for range db.Seq() {
+	}
+	var corrupt *CorruptDatabaseError
+	if err := db.SeqErr(); !errors.As(err, &corrupt) {
+		t.Errorf("SeqErr: expected CorruptDatabaseError, got %v", err)
+	}
+}
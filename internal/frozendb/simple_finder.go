@@ -21,12 +21,14 @@ import (
 // Memory Usage: O(row_size) - constant regardless of database size
 // Performance: O(n) for GetIndex, O(k) for transaction boundary methods where k <= 101
 type SimpleFinder struct {
-	dbFile        DBFile     // Database file interface for reading rows
-	rowSize       int32      // Size of each row in bytes from header
-	size          int64      // Confirmed file size (updated via OnRowAdded)
-	maxTimestamp  int64      // Maximum timestamp among all complete data and null rows
-	tombstonedErr error      // Error that caused this Finder to be tombstoned (nil if not tombstoned)
-	mu            sync.Mutex // Protects size, maxTimestamp, and tombstonedErr fields for concurrent access
+	dbFile        DBFile        // Database file interface for reading rows
+	rowSize       int32         // Size of each row in bytes from header
+	size          int64         // Confirmed file size (updated via OnRowAdded)
+	maxTimestamp  int64         // Maximum timestamp among all complete data and null rows
+	skewMs        int64         // Time skew window from header (bounds the tail read at open)
+	tombstonedErr error         // Error that caused this Finder to be tombstoned (nil if not tombstoned)
+	readAhead     *rowReadAhead // Serves readRow when WithFinderReadAhead is set (nil otherwise)
+	mu            sync.Mutex    // Protects size, maxTimestamp, and tombstonedErr fields for concurrent access
 }
 
 // NewSimpleFinder creates a new SimpleFinder instance.
@@ -38,7 +40,8 @@ type SimpleFinder struct {
 //
 // Returns:
 //   - *SimpleFinder: Initialized finder instance
-//   - error: InvalidInputError if parameters are invalid
+//   - error: InvalidInputError if parameters are invalid, CorruptDatabaseError if
+//     the header cannot be read
 //
 // The finder initializes with the current database file size from dbFile.Size(),
 // representing the extent of data confirmed via OnRowAdded() callbacks.
@@ -46,27 +49,38 @@ func NewSimpleFinder(dbFile DBFile, rowSize int32, rowEmitter *RowEmitter) (*Sim
 	if dbFile == nil {
 		return nil, NewInvalidInputError("dbFile cannot be nil", nil)
 	}
-	if rowSize < 128 || rowSize > 65536 {
-		return nil, NewInvalidInputError(fmt.Sprintf("rowSize must be between 128 and 65536, got %d", rowSize), nil)
+	if rowSize < MIN_ROW_SIZE || rowSize > MAX_ROW_SIZE {
+		return nil, NewInvalidInputError(fmt.Sprintf("rowSize must be between %d and %d, got %d", MIN_ROW_SIZE, MAX_ROW_SIZE, rowSize), nil)
 	}
 	if rowEmitter == nil {
 		return nil, NewInvalidInputError("rowEmitter cannot be nil", nil)
 	}
 
+	// Read header to get skewMs
+	headerBytes, err := dbFile.Read(0, HEADER_SIZE)
+	if err != nil {
+		return nil, NewCorruptDatabaseError("failed to read header", err)
+	}
+	header := &Header{}
+	if err := header.UnmarshalText(headerBytes); err != nil {
+		return nil, NewCorruptDatabaseError("failed to parse header", err)
+	}
+
 	sf := &SimpleFinder{
 		dbFile:       dbFile,
 		rowSize:      rowSize,
 		size:         dbFile.Size(),
 		maxTimestamp: 0,
+		skewMs:       int64(header.GetSkewMs()),
 	}
 
-	// Initialize maxTimestamp by scanning existing rows
+	// Initialize maxTimestamp from the rows at the end of the file
 	if err := sf.initializeMaxTimestamp(); err != nil {
 		return nil, err
 	}
 
 	// Subscribe to row emitter to update size when rows are added
-	_, err := rowEmitter.Subscribe(sf.OnRowAdded)
+	_, err = rowEmitter.Subscribe(sf.OnRowAdded)
 	if err != nil {
 		return nil, err
 	}
@@ -74,53 +88,18 @@ func NewSimpleFinder(dbFile DBFile, rowSize int32, rowEmitter *RowEmitter) (*Sim
 	return sf, nil
 }
 
-// initializeMaxTimestamp scans all existing rows to find the maximum timestamp.
-// This is called once during initialization to establish the baseline maxTimestamp.
+// initializeMaxTimestamp derives the maximum timestamp from the tail of the file
+// (see tailMaxTimestamp), so opening a large database does not scan it.
 func (sf *SimpleFinder) initializeMaxTimestamp() error {
 	sf.mu.Lock()
 	defer sf.mu.Unlock()
 
-	confirmedSize := sf.size
-	totalRows := (confirmedSize - HEADER_SIZE) / int64(sf.rowSize)
-
-	sf.maxTimestamp = 0
-
-	// Scan all rows to find maximum timestamp
-	for i := int64(0); i < totalRows; i++ {
-		rowBytes, err := sf.readRow(i)
-		if err != nil {
-			// Read error during initialization - fail immediately
-			return err
-		}
-
-		var rowUnion RowUnion
-		if err := rowUnion.UnmarshalText(rowBytes); err != nil {
-			// Skip corrupted rows
-			continue
-		}
-
-		// Only consider complete DataRow and NullRow entries
-		if rowUnion.DataRow != nil {
-			key := rowUnion.DataRow.GetKey()
-			if key != uuid.Nil {
-				if err := ValidateUUIDv7(key); err == nil {
-					timestamp := ExtractUUIDv7Timestamp(key)
-					if timestamp > sf.maxTimestamp {
-						sf.maxTimestamp = timestamp
-					}
-				}
-			}
-		} else if rowUnion.NullRow != nil {
-			// Extract timestamp from NullRow key (uuid.Nil) and compare, same as DataRow
-			key := rowUnion.NullRow.GetKey()
-			timestamp := ExtractUUIDv7Timestamp(key)
-			if timestamp > sf.maxTimestamp {
-				sf.maxTimestamp = timestamp
-			}
-		}
-		// Skip ChecksumRow and PartialDataRow
+	totalRows := (sf.size - HEADER_SIZE) / int64(sf.rowSize)
+	maxTimestamp, err := tailMaxTimestamp(totalRows, sf.skewMs, sf.readRow)
+	if err != nil {
+		return err
 	}
-
+	sf.maxTimestamp = maxTimestamp
 	return nil
 }
 
@@ -195,6 +174,21 @@ func (sf *SimpleFinder) GetIndex(key uuid.UUID) (int64, error) {
 	return -1, NewKeyNotFoundError(fmt.Sprintf("key %s not found in database", key.String()), nil)
 }
 
+// GetIndexes returns the index of every row containing key, in file order,
+// continuing the scan GetIndex starts until the skew window of key has passed.
+//
+// Time Complexity: O(n), plus the rows written within skewMs of key
+func (sf *SimpleFinder) GetIndexes(key uuid.UUID, skewMs int64) ([]int64, error) {
+	first, err := sf.GetIndex(key)
+	if err != nil {
+		return nil, err
+	}
+	sf.mu.Lock()
+	totalRows := (sf.size - HEADER_SIZE) / int64(sf.rowSize)
+	sf.mu.Unlock()
+	return laterOccurrences([]int64{first}, key, skewMs, first, totalRows, sf.readRowKey)
+}
+
 // GetTransactionStart returns the index of the first row in the transaction
 // containing the specified index. Implements backward scanning from input index.
 //
@@ -328,6 +322,11 @@ func (sf *SimpleFinder) GetTransactionEnd(index int64) (int64, error) {
 			continue
 		}
 
+		// A transaction cannot start before the current one ends
+		if sf.rowStartsTransaction(row) {
+			return -1, newInterleavedTransactionError(index, i)
+		}
+
 		// Check if this row ends a transaction
 		if sf.rowEndsTransaction(row) {
 			return i, nil
@@ -417,13 +416,27 @@ func (sf *SimpleFinder) MaxTimestamp() int64 {
 	return sf.maxTimestamp
 }
 
+// setReadAhead makes readRow read window rows at a time through a rowReadAhead.
+// It must be called before the finder is used.
+func (sf *SimpleFinder) setReadAhead(window int) {
+	sf.readAhead = newRowReadAhead(sf.dbFile, sf.rowSize, window)
+}
+
 // readRow reads a single row from disk at the specified index.
 // Helper method for internal use.
 func (sf *SimpleFinder) readRow(index int64) ([]byte, error) {
+	if sf.readAhead != nil {
+		return sf.readAhead.readRow(index)
+	}
 	offset := HEADER_SIZE + index*int64(sf.rowSize)
 	return sf.dbFile.Read(offset, sf.rowSize)
 }
 
+// readRowKey reads the key prefix of the row at index, implementing rowKeyReader.
+func (sf *SimpleFinder) readRowKey(index int64) ([]byte, error) {
+	return readRowKeyPrefix(sf.dbFile, sf.readAhead, sf.rowSize, index)
+}
+
 // readRowUnion reads and parses a row as RowUnion.
 // Helper method for internal use.
 func (sf *SimpleFinder) readRowUnion(index int64) (*RowUnion, error) {
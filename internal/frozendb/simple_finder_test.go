@@ -3,6 +3,8 @@ package frozendb
 import (
 	"encoding/json"
 	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/google/uuid"
@@ -1031,7 +1033,7 @@ func TestSimpleFinder_ConcurrentReads(t *testing.T) {
 func createMockChecksumRow(rowSize int32) []byte {
 	checksum := Checksum(0)
 	checksumRow := &ChecksumRow{
-		baseRow[*Checksum]{
+		baseRow: baseRow[*Checksum]{
 			RowSize:      int(rowSize),
 			StartControl: CHECKSUM_ROW,
 			EndControl:   CHECKSUM_ROW_CONTROL,
@@ -1133,3 +1135,54 @@ func createMockTransactionEndRow(rowSize int32) []byte {
 	bytes, _ := dataRow.MarshalText()
 	return bytes
 }
+
+// TestSimpleFinder_MaxTimestampFromTail requires NewSimpleFinder to read only the
+// header and the last rows of the file to find max_timestamp, as
+// NewBinarySearchFinder does. Keys are further apart than skew_ms, so the last
+// two rows settle it.
+func TestSimpleFinder_MaxTimestampFromTail(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tail.fdb")
+	createWithInterval(t, path, MIN_CHECKSUM_INTERVAL)
+
+	db, err := NewFrozenDB(path, MODE_WRITE, FinderStrategySimple)
+	if err != nil {
+		t.Fatalf("NewFrozenDB: %v", err)
+	}
+	ts := 1000
+	for txNum := 0; txNum < 3; txNum++ {
+		tx, err := db.BeginTx()
+		if err != nil {
+			t.Fatalf("BeginTx: %v", err)
+		}
+		for i := 0; i < 50; i++ {
+			ts += confSkewMs + 1000
+			mustAdd(t, tx, uuidFromTS(ts), fmt.Sprintf(`{"n":%d}`, ts))
+		}
+		if err := tx.Commit(); err != nil {
+			t.Fatalf("Commit: %v", err)
+		}
+	}
+	db.Close()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	file := &readCountingFile{DBFile: NewMemFileManager(data, MODE_READ)}
+	emitter, err := NewRowEmitter(file, confRowSize)
+	if err != nil {
+		t.Fatalf("NewRowEmitter: %v", err)
+	}
+	reads := file.reads
+	finder, err := NewSimpleFinder(file, confRowSize, emitter)
+	if err != nil {
+		t.Fatalf("NewSimpleFinder: %v", err)
+	}
+	// The header, then the last two rows
+	if got := file.reads - reads; got != 3 {
+		t.Errorf("NewSimpleFinder read %d times, want 3", got)
+	}
+	if got := finder.MaxTimestamp(); got != int64(ts) {
+		t.Errorf("MaxTimestamp() = %d, want %d", got, ts)
+	}
+}
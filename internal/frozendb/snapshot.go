@@ -0,0 +1,90 @@
+package frozendb
+
+import (
+	"encoding/json"
+
+	"github.com/google/uuid"
+)
+
+// Snapshot is a read-consistent view of a database pinned at the file size
+// observed when it was taken.
+//
+// The file is append-only and existing bytes are never rewritten, so bounding
+// every read at the captured size is enough to hide rows appended afterwards.
+// A transaction that had not ended when the snapshot was taken stays invisible
+// through the snapshot even after it commits.
+//
+// A Snapshot shares the FrozenDB's file and finder and is only valid until the
+// FrozenDB is closed.
+type Snapshot struct {
+	db       *FrozenDB
+	size     int64 // File size in bytes when the snapshot was taken
+	endIndex int64 // First row index beyond the snapshot
+}
+
+// Snapshot captures the current end of the database file.
+//
+// Returns:
+//   - *Snapshot: View bounded at the current file size
+//   - error: InvalidActionError if the file does not contain a complete header and
+//     initial checksum row
+//
+// Thread Safety: Safe for concurrent calls on the same FrozenDB instance
+func (db *FrozenDB) Snapshot() (*Snapshot, error) {
+	db.refresh()
+	rowSize := int64(db.header.GetRowSize())
+	size := db.file.Size()
+	if size < int64(HEADER_SIZE)+rowSize {
+		return nil, NewInvalidActionError("database file does not contain a complete initial checksum row", nil)
+	}
+	return &Snapshot{
+		db:       db,
+		size:     size,
+		endIndex: (size - int64(HEADER_SIZE)) / rowSize,
+	}, nil
+}
+
+// Size returns the file size in bytes captured by the snapshot.
+func (s *Snapshot) Size() int64 {
+	return s.size
+}
+
+// Get retrieves the value for key as of the snapshot, following the same
+// visibility rules as FrozenDB.Get. Rows written after the snapshot, and rows of
+// transactions that had not ended when it was taken, return KeyNotFoundError.
+//
+// Parameters:
+//   - key: UUIDv7 key to look up (must not be uuid.Nil)
+//   - value: Pointer to unmarshal the JSON value into (must not be nil)
+//
+// Returns:
+//   - error: InvalidInputError, KeyNotFoundError, InvalidDataError, ReadError, or
+//     CorruptDatabaseError
+//
+// Thread Safety: Safe for concurrent calls on the same Snapshot
+func (s *Snapshot) Get(key uuid.UUID, value any) error {
+	if key == uuid.Nil {
+		return NewInvalidInputError("key cannot be uuid.Nil", nil)
+	}
+	if value == nil {
+		return NewInvalidInputError("value cannot be nil", nil)
+	}
+	return s.db.getBounded(key, value, s.endIndex)
+}
+
+// Scan calls fn for every row committed as of the snapshot in ascending key
+// order, stopping early if fn returns false. See FrozenDB.Scan.
+//
+// Parameters:
+//   - fn: Callback receiving each key and its raw JSON value; return false to stop
+//
+// Returns:
+//   - error: InvalidInputError (nil fn), ReadError, or CorruptDatabaseError
+//
+// Thread Safety: Safe for concurrent calls on the same Snapshot
+func (s *Snapshot) Scan(fn func(key uuid.UUID, value json.RawMessage) bool) error {
+	if fn == nil {
+		return NewInvalidInputError("fn cannot be nil", nil)
+	}
+	return s.db.scanBounded(s.size, ScanOptions{}, fn)
+}
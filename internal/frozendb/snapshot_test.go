@@ -0,0 +1,83 @@
+package frozendb
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestSnapshot_IgnoresRowsAppendedLater(t *testing.T) {
+	strategies := []FinderStrategy{FinderStrategySimple, FinderStrategyInMemory, FinderStrategyBinarySearch}
+	for _, strategy := range strategies {
+		t.Run(string(strategy), func(t *testing.T) {
+			path := setupCreate(t, t.TempDir(), 0)
+			addDataRowsInOrder(t, path, []int{1000, 2000})
+
+			db, err := NewFrozenDB(path, MODE_WRITE, strategy)
+			if err != nil {
+				t.Fatalf("NewFrozenDB: %v", err)
+			}
+			defer db.Close()
+
+			// Transaction in flight when the snapshot is taken
+			tx, err := db.BeginTx()
+			if err != nil {
+				t.Fatalf("BeginTx: %v", err)
+			}
+			mustAdd(t, tx, uuidFromTS(3000), `{"n":3}`)
+
+			snap, err := db.Snapshot()
+			if err != nil {
+				t.Fatalf("Snapshot: %v", err)
+			}
+
+			if err := tx.Commit(); err != nil {
+				t.Fatalf("Commit: %v", err)
+			}
+			tx, err = db.BeginTx()
+			if err != nil {
+				t.Fatalf("BeginTx: %v", err)
+			}
+			mustAdd(t, tx, uuidFromTS(4000), `{"n":4}`)
+			if err := tx.Commit(); err != nil {
+				t.Fatalf("Commit: %v", err)
+			}
+
+			if snap.Size() >= db.file.Size() {
+				t.Fatalf("snapshot size %d should be below current size %d", snap.Size(), db.file.Size())
+			}
+
+			var value map[string]any
+			if err := snap.Get(uuidFromTS(1000), &value); err != nil {
+				t.Errorf("Get committed key: %v", err)
+			}
+			var notFound *KeyNotFoundError
+			for _, ts := range []int{3000, 4000} {
+				if err := snap.Get(uuidFromTS(ts), &value); !errors.As(err, &notFound) {
+					t.Errorf("Get key %d: expected KeyNotFoundError, got %v", ts, err)
+				}
+				// The live database sees the row
+				if err := db.Get(uuidFromTS(ts), &value); err != nil {
+					t.Errorf("db.Get key %d: %v", ts, err)
+				}
+			}
+
+			var got []scannedRow
+			err = snap.Scan(func(key uuid.UUID, value json.RawMessage) bool {
+				got = append(got, scannedRow{key: key, value: string(value)})
+				return true
+			})
+			if err != nil {
+				t.Fatalf("Scan: %v", err)
+			}
+			if len(got) != 2 || got[0].key != uuidFromTS(1000) || got[1].key != uuidFromTS(2000) {
+				t.Errorf("snapshot scan = %v, want keys 1000 and 2000", got)
+			}
+			if live := scanAll(t, db); len(live) != 4 {
+				t.Errorf("live scan returned %d rows, want 4", len(live))
+			}
+		})
+	}
+}
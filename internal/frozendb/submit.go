@@ -0,0 +1,66 @@
+package frozendb
+
+// Submit runs fn inside its own transaction on this write handle, serializing it
+// with every other Submit call on the handle, so goroutines can share one writer
+// without coordinating BeginTx and Commit themselves.
+//
+// Submit begins a transaction, calls fn with it, and commits when fn returns nil.
+// When fn returns an error, the transaction is fully rolled back and fn's error is
+// returned unchanged. fn may end the transaction itself with Commit or Rollback;
+// Submit then leaves it as it is. If fn panics, the transaction is rolled back and
+// the panic continues. fn must not call Submit or BeginTx on the same handle, and
+// must not keep tx after it returns.
+//
+// Transactions begun with BeginTx outside Submit are not serialized with it: while
+// one is active, Submit returns the InvalidActionError from BeginTx.
+//
+// Parameters:
+//   - fn: Callback adding rows to tx (must not be nil)
+//
+// Returns:
+//   - error: fn's error; InvalidInputError (nil fn); or an error from BeginTx or Commit
+//
+// Thread Safety: Safe for concurrent calls on the same FrozenDB instance
+func (db *FrozenDB) Submit(fn func(tx *Transaction) error) error {
+	if fn == nil {
+		return NewInvalidInputError("fn cannot be nil", nil)
+	}
+
+	db.submitMu.Lock()
+	defer db.submitMu.Unlock()
+
+	tx, err := db.BeginTx()
+	if err != nil {
+		return err
+	}
+
+	completed := false
+	defer func() {
+		if !completed {
+			db.rollbackSubmitted(tx)
+		}
+	}()
+	fnErr := fn(tx)
+	completed = true
+
+	if fnErr != nil {
+		db.rollbackSubmitted(tx)
+		return fnErr
+	}
+	if tx.IsCommitted() {
+		return nil
+	}
+	return tx.Commit()
+}
+
+// rollbackSubmitted fully rolls back a transaction run by Submit unless fn already
+// ended it. A failed rollback tombstones the transaction and is only logged, since
+// the error that caused it is the one returned.
+func (db *FrozenDB) rollbackSubmitted(tx *Transaction) {
+	if tx.IsCommitted() || tx.IsTombstoned() {
+		return
+	}
+	if err := tx.Rollback(0); err != nil {
+		loggerOrNop(db.logger).Warnf("frozendb: failed to roll back submitted transaction: %v", err)
+	}
+}
@@ -0,0 +1,128 @@
+package frozendb
+
+import (
+	"errors"
+	"sync"
+	"testing"
+)
+
+func TestSubmit_SerializesConcurrentTransactions(t *testing.T) {
+	path := setupCreate(t, t.TempDir(), confSkewMs)
+	db, err := NewFrozenDB(path, MODE_WRITE, FinderStrategySimple)
+	if err != nil {
+		t.Fatalf("NewFrozenDB: %v", err)
+	}
+	defer db.Close()
+
+	const writers, perWriter = 8, 5
+	var wg sync.WaitGroup
+	errs := make(chan error, writers)
+	for w := 0; w < writers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			err := db.Submit(func(tx *Transaction) error {
+				for i := 0; i < perWriter; i++ {
+					if err := tx.AddRow(uuidFromTS(1000+w*perWriter+i), []byte(`{"v":1}`)); err != nil {
+						return err
+					}
+				}
+				return nil
+			})
+			errs <- err
+		}(w)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("Submit: %v", err)
+		}
+	}
+
+	var value map[string]any
+	for ts := 1000; ts < 1000+writers*perWriter; ts++ {
+		if err := db.Get(uuidFromTS(ts), &value); err != nil {
+			t.Errorf("Get %d: %v", ts, err)
+		}
+	}
+	if db.GetActiveTx() != nil {
+		t.Errorf("expected no active transaction after Submit")
+	}
+}
+
+func TestSubmit_RollsBackOnError(t *testing.T) {
+	path := setupCreate(t, t.TempDir(), confSkewMs)
+	db, err := NewFrozenDB(path, MODE_WRITE, FinderStrategySimple)
+	if err != nil {
+		t.Fatalf("NewFrozenDB: %v", err)
+	}
+	defer db.Close()
+
+	errBoom := errors.New("boom")
+	err = db.Submit(func(tx *Transaction) error {
+		mustAdd(t, tx, uuidFromTS(1000), `{"v":1}`)
+		return errBoom
+	})
+	if err != errBoom {
+		t.Fatalf("expected closure error, got %v", err)
+	}
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Errorf("expected panic to propagate")
+			}
+		}()
+		_ = db.Submit(func(tx *Transaction) error {
+			mustAdd(t, tx, uuidFromTS(1001), `{"v":1}`)
+			panic("boom")
+		})
+	}()
+
+	// A closure ending the transaction itself is left as it is
+	if err := db.Submit(func(tx *Transaction) error {
+		mustAdd(t, tx, uuidFromTS(1002), `{"v":1}`)
+		return tx.Rollback(0)
+	}); err != nil {
+		t.Fatalf("Submit with explicit rollback: %v", err)
+	}
+	if err := db.Submit(func(tx *Transaction) error {
+		mustAdd(t, tx, uuidFromTS(1003), `{"v":1}`)
+		return tx.Commit()
+	}); err != nil {
+		t.Fatalf("Submit with explicit commit: %v", err)
+	}
+
+	var value map[string]any
+	var notFound *KeyNotFoundError
+	for _, ts := range []int{1000, 1001, 1002} {
+		if err := db.Get(uuidFromTS(ts), &value); !errors.As(err, &notFound) {
+			t.Errorf("Get %d: expected KeyNotFoundError, got %v", ts, err)
+		}
+	}
+	if err := db.Get(uuidFromTS(1003), &value); err != nil {
+		t.Errorf("Get 1003: %v", err)
+	}
+
+	var invalidInput *InvalidInputError
+	if err := db.Submit(nil); !errors.As(err, &invalidInput) {
+		t.Errorf("expected InvalidInputError for nil fn, got %v", err)
+	}
+}
+
+func TestSubmit_RequiresWriteMode(t *testing.T) {
+	path := setupCreate(t, t.TempDir(), 0)
+	db, err := NewFrozenDB(path, MODE_READ, FinderStrategySimple)
+	if err != nil {
+		t.Fatalf("NewFrozenDB: %v", err)
+	}
+	defer db.Close()
+	called := false
+	if err := db.Submit(func(tx *Transaction) error { called = true; return nil }); err == nil {
+		t.Errorf("expected error submitting on a read-mode handle")
+	}
+	if called {
+		t.Errorf("fn must not run when the transaction cannot begin")
+	}
+}
@@ -0,0 +1,101 @@
+package frozendb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// tailPollInterval bounds how long Tail waits before re-checking the file size
+// when no write notification arrives. File watching in read mode is best-effort,
+// so the poll guarantees progress even if an event is missed.
+const tailPollInterval = 250 * time.Millisecond
+
+// Tail calls fn for every committed row written after the row with key from, in
+// file order, then keeps waiting for newly committed transactions and calls fn for
+// their rows until ctx is cancelled.
+//
+// Rows are only surfaced once their transaction has ended, and then only the rows
+// the transaction made visible (see Get for the visibility rules). Rows of an
+// in-flight transaction are never passed to fn. Passing uuid.Nil for from emits
+// every committed row from the start of the file.
+//
+// Parameters:
+//   - ctx: Context controlling how long to tail; cancellation stops Tail
+//   - from: Key of the last row already consumed, or uuid.Nil to start at the beginning
+//   - fn: Callback receiving each key and its raw JSON value; a non-nil error stops Tail
+//
+// Returns:
+//   - error: ctx.Err() on cancellation, the error returned by fn, InvalidInputError
+//     (nil fn), KeyNotFoundError (from is not a committed key), ReadError, or
+//     CorruptDatabaseError
+//
+// Thread Safety: Safe for concurrent calls on the same FrozenDB instance
+func (db *FrozenDB) Tail(ctx context.Context, from uuid.UUID, fn func(key uuid.UUID, value json.RawMessage) error) error {
+	if fn == nil {
+		return NewInvalidInputError("fn cannot be nil", nil)
+	}
+
+	// Wake up as soon as the file grows; the buffered channel coalesces bursts of writes
+	notify := make(chan struct{}, 1)
+	unsubscribe, err := db.file.Subscribe(func() error {
+		select {
+		case notify <- struct{}{}:
+		default:
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = unsubscribe() }()
+
+	db.refresh()
+	reader := newCommittedRowReader(db.file, db.header.GetRowSize(), db.file.Size())
+	skipping := from != uuid.Nil
+	ticker := time.NewTicker(tailPollInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		rows, ok, err := reader.nextTransaction()
+		if err != nil {
+			return err
+		}
+		if ok {
+			for _, row := range rows {
+				if skipping {
+					skipping = row.GetKey() != from
+					continue
+				}
+				value, err := db.decodeValue(row.GetValue())
+				if err != nil {
+					return err
+				}
+				if err := fn(row.GetKey(), value); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		// Caught up with the committed tail
+		if skipping {
+			return NewKeyNotFoundError(fmt.Sprintf("key %s not found in committed rows", from), nil)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-notify:
+		case <-ticker.C:
+			db.refresh()
+		}
+		reader.extend(db.file.Size())
+	}
+}
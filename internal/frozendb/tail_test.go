@@ -0,0 +1,138 @@
+package frozendb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// startTail runs Tail in the background and forwards every emitted key
+func startTail(t *testing.T, db *FrozenDB, from uuid.UUID) (<-chan uuid.UUID, <-chan error, context.CancelFunc) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	keys := make(chan uuid.UUID, 100)
+	done := make(chan error, 1)
+	go func() {
+		done <- db.Tail(ctx, from, func(key uuid.UUID, _ json.RawMessage) error {
+			keys <- key
+			return nil
+		})
+	}()
+	t.Cleanup(cancel)
+	return keys, done, cancel
+}
+
+func expectTailKeys(t *testing.T, keys <-chan uuid.UUID, want ...uuid.UUID) {
+	t.Helper()
+	for i, w := range want {
+		select {
+		case got := <-keys:
+			if got != w {
+				t.Fatalf("row %d = %s, want %s", i, got, w)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for row %d (%s)", i, w)
+		}
+	}
+}
+
+func expectNoTailKeys(t *testing.T, keys <-chan uuid.UUID) {
+	t.Helper()
+	select {
+	case got := <-keys:
+		t.Fatalf("unexpected row %s", got)
+	case <-time.After(2 * tailPollInterval):
+	}
+}
+
+func TestTail_NilCallback(t *testing.T) {
+	path := setupCreate(t, t.TempDir(), 0)
+	db := openForScan(t, path)
+
+	var invalidInput *InvalidInputError
+	if err := db.Tail(context.Background(), uuid.Nil, nil); !errors.As(err, &invalidInput) {
+		t.Fatalf("expected InvalidInputError, got %v", err)
+	}
+}
+
+func TestTail_UnknownFromKey(t *testing.T) {
+	path := setupCreate(t, t.TempDir(), 0)
+	addDataRowsInOrder(t, path, []int{1000})
+	db := openForScan(t, path)
+
+	err := db.Tail(context.Background(), uuidFromTS(2000), func(uuid.UUID, json.RawMessage) error { return nil })
+	var notFound *KeyNotFoundError
+	if !errors.As(err, &notFound) {
+		t.Fatalf("expected KeyNotFoundError, got %v", err)
+	}
+}
+
+func TestTail_EmitsExistingThenNewCommittedRows(t *testing.T) {
+	path := setupCreate(t, t.TempDir(), 0)
+	addDataRowsInOrder(t, path, []int{1000, 2000, 3000})
+
+	reader := openForScan(t, path)
+	keys, done, cancel := startTail(t, reader, uuidFromTS(1000))
+	expectTailKeys(t, keys, uuidFromTS(2000), uuidFromTS(3000))
+
+	// Rows of an in-flight transaction are held back until it commits
+	tx, writer := openAndBegin(t, path)
+	defer writer.Close()
+	mustAdd(t, tx, uuidFromTS(4000), `{}`)
+	mustAdd(t, tx, uuidFromTS(5000), `{}`)
+	expectNoTailKeys(t, keys)
+
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+	expectTailKeys(t, keys, uuidFromTS(4000), uuidFromTS(5000))
+
+	// Rolled back rows are never emitted
+	tx, err := writer.BeginTx()
+	if err != nil {
+		t.Fatalf("BeginTx: %v", err)
+	}
+	mustAdd(t, tx, uuidFromTS(6000), `{}`)
+	if err := tx.Rollback(0); err != nil {
+		t.Fatalf("Rollback: %v", err)
+	}
+	tx, err = writer.BeginTx()
+	if err != nil {
+		t.Fatalf("BeginTx: %v", err)
+	}
+	mustAdd(t, tx, uuidFromTS(7000), `{}`)
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+	expectTailKeys(t, keys, uuidFromTS(7000))
+
+	cancel()
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("expected context.Canceled, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Tail did not return after cancellation")
+	}
+}
+
+func TestTail_CallbackErrorStops(t *testing.T) {
+	path := setupCreate(t, t.TempDir(), 0)
+	addDataRowsInOrder(t, path, []int{1000, 2000})
+	db := openForScan(t, path)
+
+	stop := errors.New("stop")
+	calls := 0
+	err := db.Tail(context.Background(), uuid.Nil, func(uuid.UUID, json.RawMessage) error {
+		calls++
+		return stop
+	})
+	if !errors.Is(err, stop) || calls != 1 {
+		t.Fatalf("expected callback error after 1 call, got %v after %d calls", err, calls)
+	}
+}
@@ -0,0 +1,67 @@
+package frozendb
+
+import (
+	"encoding/json"
+
+	"github.com/google/uuid"
+)
+
+// TOMBSTONE_VALUE is the reserved value Transaction.Delete stores to mark a key
+// deleted. The file format is unchanged: a tombstone is an ordinary data row
+// whose value is exactly these bytes (before compression), and AddRow rejects
+// the value so applications cannot write one by accident.
+//
+// Deletes resolve by last occurrence: a key is deleted when its last committed
+// occurrence in file order is a tombstone, and every read treats it as absent.
+// Writing the key again after a Delete makes it visible again, and Get then
+// returns the first committed occurrence after the tombstone, so the value
+// written before the Delete is never returned.
+const TOMBSTONE_VALUE = `{"$frozendb":"tombstone"}`
+
+// isTombstone reports whether a decoded value is TOMBSTONE_VALUE.
+func isTombstone(value []byte) bool {
+	return string(value) == TOMBSTONE_VALUE
+}
+
+// Delete marks key deleted by appending a row with TOMBSTONE_VALUE to the
+// transaction. Once the transaction commits, reads treat the key as absent.
+//
+// The tombstone is a new occurrence of key, so it is subject to the same
+// ordering rule as AddRow: key's timestamp plus skew_ms must exceed the largest
+// timestamp written so far. Only keys written within the last skew window can be
+// deleted; older keys fail with KeyOrderingError. As with AddRow, a key cannot
+// appear twice in one transaction. The value schema and row validator are not
+// applied to tombstones.
+//
+// Parameters:
+//   - key: UUIDv7 key to delete (need not have been written before)
+//
+// Returns:
+//   - error: the errors of AddRow, other than InvalidDataError
+func (tx *Transaction) Delete(key uuid.UUID) error {
+	return tx.addRow(key, json.RawMessage(TOMBSTONE_VALUE), false)
+}
+
+// rowIsTombstone reports whether row stores TOMBSTONE_VALUE. A value that cannot
+// be decoded is not a tombstone; reading it reports the error.
+func (db *FrozenDB) rowIsTombstone(row *DataRow) bool {
+	value, err := db.decodeValue(row.GetValue())
+	return err == nil && isTombstone(value)
+}
+
+// tombstoneAt reports whether the data row at index stores TOMBSTONE_VALUE,
+// validating only the row framing. A value that cannot be decoded is not a
+// tombstone; reading it reports the error.
+func (db *FrozenDB) tombstoneAt(index int64) (bool, error) {
+	row := db.getRowBuffer()
+	defer db.rowBufs.Put(row)
+	frame, err := db.readRowFrame(index, *row)
+	if err != nil {
+		return false, err
+	}
+	if len(frame.payload) <= 24 {
+		return false, nil
+	}
+	value, err := db.decodeValue(frame.payload[24:])
+	return err == nil && isTombstone(value), nil
+}
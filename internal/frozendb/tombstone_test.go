@@ -0,0 +1,283 @@
+package frozendb
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+// writeTx runs fn in a transaction on path and commits it, or rolls back to
+// savepoint 0 when rollback is set.
+func writeTx(t *testing.T, path string, rollback bool, fn func(tx *Transaction) error) {
+	t.Helper()
+	db, err := NewFrozenDB(path, MODE_WRITE, FinderStrategySimple)
+	if err != nil {
+		t.Fatalf("NewFrozenDB: %v", err)
+	}
+	defer db.Close()
+	tx, err := db.BeginTx()
+	if err != nil {
+		t.Fatalf("BeginTx: %v", err)
+	}
+	if err := fn(tx); err != nil {
+		t.Fatalf("transaction: %v", err)
+	}
+	if rollback {
+		err = tx.Rollback(0)
+	} else {
+		err = tx.Commit()
+	}
+	if err != nil {
+		t.Fatalf("finish transaction: %v", err)
+	}
+}
+
+func scannedKeys(rows []scannedRow) []uuid.UUID {
+	keys := make([]uuid.UUID, len(rows))
+	for i, row := range rows {
+		keys[i] = row.key
+	}
+	return keys
+}
+
+func TestDelete_HidesKey(t *testing.T) {
+	path := setupCreate(t, t.TempDir(), 5000)
+	a, b, c := uuidFromTS(1000), uuidFromTS(2000), uuidFromTS(3000)
+	writeTx(t, path, false, func(tx *Transaction) error {
+		for _, key := range []uuid.UUID{a, b, c} {
+			if err := tx.AddRow(key, json.RawMessage(`{"v":1}`)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	writeTx(t, path, false, func(tx *Transaction) error { return tx.Delete(b) })
+	// A rolled back delete has no effect
+	writeTx(t, path, true, func(tx *Transaction) error { return tx.Delete(a) })
+
+	for _, strategy := range []FinderStrategy{FinderStrategySimple, FinderStrategyInMemory, FinderStrategyBinarySearch} {
+		t.Run(string(strategy), func(t *testing.T) {
+			db, err := NewFrozenDB(path, MODE_READ, strategy)
+			if err != nil {
+				t.Fatalf("NewFrozenDB: %v", err)
+			}
+			defer db.Close()
+
+			var value map[string]int
+			var notFound *KeyNotFoundError
+			if err := db.Get(b, &value); !errors.As(err, &notFound) {
+				t.Errorf("Get deleted key: expected KeyNotFoundError, got %v", err)
+			}
+			if err := db.GetInto(b, &bytes.Buffer{}); !errors.As(err, &notFound) {
+				t.Errorf("GetInto deleted key: expected KeyNotFoundError, got %v", err)
+			}
+			if err := db.GetLatest(b, &value); !errors.As(err, &notFound) {
+				t.Errorf("GetLatest deleted key: expected KeyNotFoundError, got %v", err)
+			}
+			if err := db.Get(a, &value); err != nil || value["v"] != 1 {
+				t.Errorf("Get(a) = %v, %v; want v=1", value, err)
+			}
+		})
+	}
+
+	db := openForScan(t, path)
+	want := []uuid.UUID{a, c}
+	if got := scannedKeys(scanAll(t, db)); !slices.Equal(got, want) {
+		t.Errorf("Scan keys = %v, want %v", got, want)
+	}
+	if got := scannedKeys(scanAllReverse(t, db)); !slices.Equal(got, []uuid.UUID{c, a}) {
+		t.Errorf("ScanReverse keys = %v, want %v", got, []uuid.UUID{c, a})
+	}
+	if got := seqKeys(t, db, uuid.Nil, uuid.Nil); !slices.Equal(got, want) {
+		t.Errorf("Seq keys = %v, want %v", got, want)
+	}
+}
+
+func TestDelete_WriteAgain(t *testing.T) {
+	path := setupCreate(t, t.TempDir(), 5000)
+	key := uuidFromTS(1000)
+	writeTx(t, path, false, func(tx *Transaction) error {
+		return tx.AddRow(key, json.RawMessage(`{"v":1}`))
+	})
+	writeTx(t, path, false, func(tx *Transaction) error { return tx.Delete(key) })
+	writeTx(t, path, false, func(tx *Transaction) error {
+		return tx.AddRow(key, json.RawMessage(`{"v":2}`))
+	})
+	writeTx(t, path, false, func(tx *Transaction) error {
+		return tx.AddRow(key, json.RawMessage(`{"v":3}`))
+	})
+
+	for _, strategy := range []FinderStrategy{FinderStrategySimple, FinderStrategyInMemory, FinderStrategyBinarySearch} {
+		t.Run(string(strategy), func(t *testing.T) {
+			db, err := NewFrozenDB(path, MODE_READ, strategy)
+			if err != nil {
+				t.Fatalf("NewFrozenDB: %v", err)
+			}
+			defer db.Close()
+
+			var value map[string]int
+			if err := db.Get(key, &value); err != nil || value["v"] != 2 {
+				t.Errorf("Get = %v, %v; want first occurrence after the delete v=2", value, err)
+			}
+			buf := &bytes.Buffer{}
+			if err := db.GetInto(key, buf); err != nil || buf.String() != `{"v":2}` {
+				t.Errorf("GetInto = %q, %v; want {\"v\":2}", buf.String(), err)
+			}
+			if err := db.GetLatest(key, &value); err != nil || value["v"] != 3 {
+				t.Errorf("GetLatest = %v, %v; want v=3", value, err)
+			}
+			raw, meta, err := db.GetWithMeta(key)
+			if err != nil || string(raw) != `{"v":2}` {
+				t.Errorf("GetWithMeta = %s, %v; want {\"v\":2}", raw, err)
+			}
+			if offset, ok, err := db.OffsetOf(key); err != nil || !ok || offset != meta.Offset {
+				t.Errorf("OffsetOf = %d, %v, %v; want %d", offset, ok, err, meta.Offset)
+			}
+			if result, err := db.Lookup(key); err != nil || result != LookupFound {
+				t.Errorf("Lookup = %q, %v; want %q", result, err, LookupFound)
+			}
+		})
+	}
+
+	db := openForScan(t, path)
+	rows := scanAll(t, db)
+	if len(rows) != 2 || rows[0].value != `{"v":2}` || rows[1].value != `{"v":3}` {
+		t.Errorf("Scan = %v, want only v=2 and v=3", rows)
+	}
+
+	// A key whose first occurrence is a tombstone reads as the later write
+	other := uuidFromTS(2000)
+	writeTx(t, path, false, func(tx *Transaction) error { return tx.Delete(other) })
+	writeTx(t, path, false, func(tx *Transaction) error {
+		return tx.AddRow(other, json.RawMessage(`{"v":4}`))
+	})
+	buf := &bytes.Buffer{}
+	if err := db.GetInto(other, buf); err != nil || buf.String() != `{"v":4}` {
+		t.Errorf("GetInto = %q, %v; want {\"v\":4}", buf.String(), err)
+	}
+}
+
+func TestDelete_ReadAPIs(t *testing.T) {
+	path := setupCreate(t, t.TempDir(), 5000)
+	a, b := uuidFromTS(1000), uuidFromTS(2000)
+	writeTx(t, path, false, func(tx *Transaction) error {
+		if err := tx.AddRow(a, json.RawMessage(`{"v":1}`)); err != nil {
+			return err
+		}
+		return tx.AddRow(b, json.RawMessage(`{"v":1}`))
+	})
+	writeTx(t, path, false, func(tx *Transaction) error { return tx.Delete(b) })
+
+	for _, strategy := range []FinderStrategy{FinderStrategySimple, FinderStrategyInMemory, FinderStrategyBinarySearch} {
+		t.Run(string(strategy), func(t *testing.T) {
+			db, err := NewFrozenDB(path, MODE_READ, strategy)
+			if err != nil {
+				t.Fatalf("NewFrozenDB: %v", err)
+			}
+			defer db.Close()
+
+			var notFound *KeyNotFoundError
+			if _, _, err := db.GetWithMeta(b); !errors.As(err, &notFound) {
+				t.Errorf("GetWithMeta deleted key: expected KeyNotFoundError, got %v", err)
+			}
+			if _, ok, err := db.OffsetOf(b); err != nil || ok {
+				t.Errorf("OffsetOf deleted key = %v, %v; want not found", ok, err)
+			}
+			if result, err := db.Lookup(b); err != nil || result != LookupDeleted {
+				t.Errorf("Lookup deleted key = %q, %v; want %q", result, err, LookupDeleted)
+			}
+			if count, err := db.CountRange(uuid.Nil, uuid.Nil); err != nil || count != 1 {
+				t.Errorf("CountRange = %d, %v; want 1", count, err)
+			}
+			state, err := db.Materialize(func(state map[string]json.RawMessage, key uuid.UUID, value json.RawMessage) {
+				state[key.String()] = value
+			})
+			if err != nil {
+				t.Fatalf("Materialize: %v", err)
+			}
+			if string(state[b.String()]) != `{"v":1}` {
+				t.Errorf("Materialize passed the tombstone to reduce: state[b] = %s", state[b.String()])
+			}
+		})
+	}
+
+	// Deleting the only key leaves the database empty
+	writeTx(t, path, false, func(tx *Transaction) error { return tx.Delete(a) })
+	db := openForScan(t, path)
+	if empty, err := db.IsEmpty(); err != nil || !empty {
+		t.Errorf("IsEmpty after deleting every key = %v, %v; want true", empty, err)
+	}
+	if count, err := db.CountRange(uuid.Nil, uuid.Nil); err != nil || count != 0 {
+		t.Errorf("CountRange after deleting every key = %d, %v; want 0", count, err)
+	}
+}
+
+func TestDelete_TransactionGet(t *testing.T) {
+	path := setupCreate(t, t.TempDir(), 5000)
+	a, b := uuidFromTS(1000), uuidFromTS(2000)
+	writeTx(t, path, false, func(tx *Transaction) error {
+		return tx.AddRow(a, json.RawMessage(`{"v":1}`))
+	})
+	writeTx(t, path, false, func(tx *Transaction) error { return tx.Delete(a) })
+
+	db, err := NewFrozenDB(path, MODE_WRITE, FinderStrategySimple)
+	if err != nil {
+		t.Fatalf("NewFrozenDB: %v", err)
+	}
+	defer db.Close()
+	tx, err := db.BeginTx()
+	if err != nil {
+		t.Fatalf("BeginTx: %v", err)
+	}
+	if err := tx.Delete(b); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+
+	var value map[string]int
+	var notFound *KeyNotFoundError
+	if err := tx.Get(a, &value); !errors.As(err, &notFound) {
+		t.Errorf("Get key deleted on disk: expected KeyNotFoundError, got %v", err)
+	}
+	if err := tx.Get(b, &value); !errors.As(err, &notFound) {
+		t.Errorf("Get key deleted in the transaction: expected KeyNotFoundError, got %v", err)
+	}
+	if err := tx.Rollback(0); err != nil {
+		t.Fatalf("Rollback: %v", err)
+	}
+}
+
+func TestDelete_Rejections(t *testing.T) {
+	path := setupCreate(t, t.TempDir(), 5000)
+	db, err := NewFrozenDB(path, MODE_WRITE, FinderStrategySimple)
+	if err != nil {
+		t.Fatalf("NewFrozenDB: %v", err)
+	}
+	defer db.Close()
+
+	var invalidInput *InvalidInputError
+	if _, err := db.PrepareBatch(Entry{Key: uuidFromTS(1000), Value: json.RawMessage(TOMBSTONE_VALUE)}); !errors.As(err, &invalidInput) {
+		t.Errorf("PrepareBatch tombstone: expected InvalidInputError, got %v", err)
+	}
+
+	tx, err := db.BeginTx()
+	if err != nil {
+		t.Fatalf("BeginTx: %v", err)
+	}
+	if err := tx.AddRow(uuidFromTS(1000), json.RawMessage(TOMBSTONE_VALUE)); !errors.As(err, &invalidInput) {
+		t.Errorf("AddRow tombstone: expected InvalidInputError, got %v", err)
+	}
+	if err := tx.AddRow(uuidFromTS(20000), json.RawMessage(`{}`)); err != nil {
+		t.Fatalf("AddRow: %v", err)
+	}
+	var ordering *KeyOrderingError
+	if err := tx.Delete(uuidFromTS(1000)); !errors.As(err, &ordering) {
+		t.Errorf("Delete outside skew window: expected KeyOrderingError, got %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+}
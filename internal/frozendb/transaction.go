@@ -2,12 +2,22 @@ package frozendb
 
 import (
 	"encoding/json"
+	"fmt"
 	"sync"
+	"time"
 
 	"github.com/google/uuid"
 )
 
-// Transaction represents a single database transaction with maximum 100 DataRow objects.
+// MAX_TRANSACTION_ROWS is the most data rows a transaction can hold, counting its
+// PartialDataRow. It is a v1 file format constraint rather than a tunable default:
+// readers locate the start of an open transaction, and finders the boundaries of
+// any transaction, by reading back at most this many rows plus one checksum row,
+// so a longer transaction could not be recovered or queried correctly.
+// Transaction.SetMaxRows can lower the limit for a transaction but not raise it.
+const MAX_TRANSACTION_ROWS = 100
+
+// Transaction represents a single database transaction with maximum MAX_TRANSACTION_ROWS DataRow objects.
 // The first row must be the transaction start (StartControl = 'T'), and the last row
 // is either the end of the transaction or the transaction is still open.
 //
@@ -20,7 +30,7 @@ import (
 //
 // After creating a Transaction struct directly, you MUST call Validate() before using it.
 type Transaction struct {
-	rows            []DataRow       // Single slice of DataRow objects (max 100) - unexported for immutability
+	rows            []DataRow       // Single slice of DataRow objects (max MAX_TRANSACTION_ROWS) - unexported for immutability
 	empty           *NullRow        // Empty null row after successful commit
 	last            *PartialDataRow // Current partial data row being built
 	Header          *Header         // Header reference for row creation
@@ -31,14 +41,29 @@ type Transaction struct {
 	tombstone       bool            // Tombstone flag set when write operation fails
 	db              DBFile          // File manager interface for reading rows and calculating checksums
 	finder          Finder          // Finder interface for notifying of new rows (optional)
+	savepointLabels map[string]int  // Savepoint label to savepoint id (1-9), in memory only
+	valueSchema     *valueSchema    // Schema every added value must satisfy (nil disables validation)
+	rowValidator    RowValidator    // Application check every added row must pass (nil disables it)
+	autoSavepoint   int             // Create a savepoint after every autoSavepoint rows (0 disables)
+	maxRows         int             // Row limit set by SetMaxRows (0 means MAX_TRANSACTION_ROWS)
+	checksumRows    int             // Checksum rows written by this transaction
+	bytesWritten    int64           // Bytes appended by this transaction, checksum rows included
+	began, ended    time.Time       // When Begin started and the transaction ended (zero until then)
+
+	// committedGet reads committed rows for Get (nil when not created by FrozenDB)
+	committedGet func(key uuid.UUID, value any) error
+	// committedHas reports whether a key has a committed, undeleted row, for
+	// AddRowIfAbsent (nil when not created by FrozenDB)
+	committedHas func(key uuid.UUID) (bool, error)
+	// logger receives diagnostic events (nil discards them)
+	logger Logger
+	// futureGuard rejects keys ahead of the system clock plus skew_ms (WithFutureGuard)
+	futureGuard bool
 }
 
-const (
-	CHECKSUM_INTERVAL = 10000 // Checksum rows inserted every 10,000 complete rows
-)
-
 // NewTransaction creates a new transaction with automatic checksum row insertion.
-// The transaction will automatically insert checksum rows at 10,000-row intervals.
+// The transaction will automatically insert a checksum row after every
+// header.GetChecksumInterval() complete rows (10,000 by default).
 //
 // Parameters:
 //   - db: DBFile interface for reading rows and calculating checksums
@@ -60,7 +85,7 @@ func NewTransaction(db DBFile, header *Header, finder Finder) (*Transaction, err
 		return nil, NewInvalidInputError("Finder cannot be nil", nil)
 	}
 	// Create write channel internally
-	writeChan := make(chan Data, 100)
+	writeChan := newWriteChan(db)
 
 	// SetWriter needs a receive-only channel
 	if err := db.SetWriter(writeChan); err != nil {
@@ -100,11 +125,13 @@ func (tx *Transaction) checkTombstone() error {
 }
 
 // getChecksumStart returns the offset where the most recent checksum row starts.
-// If file size is less than 10,000 data rows, returns HEADER_SIZE (64) for the initial checksum.
-// Otherwise, calculates the position of the most recent checksum row based on row count.
+// If the file holds fewer than one checksum interval of data rows, returns HEADER_SIZE
+// (64) for the initial checksum. Otherwise, calculates the position of the most
+// recent checksum row based on row count and the header's checksum interval.
 func (tx *Transaction) getChecksumStart() int64 {
 	fileSize := tx.db.Size()
 	rowSize := tx.Header.GetRowSize()
+	interval := int64(tx.Header.GetChecksumInterval())
 
 	// If no data yet (file only has header), no checksum
 	if fileSize <= int64(HEADER_SIZE) {
@@ -114,21 +141,22 @@ func (tx *Transaction) getChecksumStart() int64 {
 	// Calculate total rows in data section (checksum rows + data rows)
 	totalRows := (fileSize - int64(HEADER_SIZE)) / int64(rowSize)
 
-	// If total rows < CHECKSUM_INTERVAL+1, initial checksum is at HEADER_SIZE
-	// (checksum row + less than CHECKSUM_INTERVAL data rows)
-	if totalRows <= int64(CHECKSUM_INTERVAL+1) {
+	// If total rows < interval+1, initial checksum is at HEADER_SIZE
+	// (checksum row + less than interval data rows)
+	if totalRows <= interval+1 {
 		return int64(HEADER_SIZE)
 	}
 
-	// Number of complete blocks of (CHECKSUM_INTERVAL data rows + checksum row)
-	blocks := (totalRows - 1) / (int64(CHECKSUM_INTERVAL) + 1)
+	// Number of complete blocks of (interval data rows + checksum row)
+	blocks := (totalRows - 1) / (interval + 1)
 
-	// Offset: HEADER_SIZE + blocks * (CHECKSUM_INTERVAL+1) * rowSize
-	return int64(HEADER_SIZE) + blocks*(int64(CHECKSUM_INTERVAL)+1)*int64(rowSize)
+	// Offset: HEADER_SIZE + blocks * (interval+1) * rowSize
+	return int64(HEADER_SIZE) + blocks*(interval+1)*int64(rowSize)
 }
 
 // shouldInsertChecksum returns true if a checksum row should be inserted.
-// Checks if the distance from getChecksumStart() to fileSize() is exactly 10,001 rows.
+// Checks if the distance from getChecksumStart() to fileSize() is exactly one
+// checksum interval plus the checksum row itself.
 func (tx *Transaction) shouldInsertChecksum() bool {
 	fileSize := tx.db.Size()
 	rowSize := tx.Header.GetRowSize()
@@ -137,7 +165,7 @@ func (tx *Transaction) shouldInsertChecksum() bool {
 	bytesFromChecksum := fileSize - checksumStart
 	rowsFromChecksum := bytesFromChecksum / int64(rowSize)
 
-	shouldInsert := rowsFromChecksum == int64(CHECKSUM_INTERVAL+1)
+	shouldInsert := rowsFromChecksum == int64(tx.Header.GetChecksumInterval()+1)
 	return shouldInsert
 }
 
@@ -181,7 +209,9 @@ func (tx *Transaction) insertChecksum(bytes []byte) error {
 	if err := tx.writeBytes(checksumBytes); err != nil {
 		return err
 	}
+	tx.checksumRows++
 
+	loggerOrNop(tx.logger).Debugf("frozendb: inserted checksum row covering %d rows", tx.Header.GetChecksumInterval())
 	return nil
 }
 
@@ -200,7 +230,7 @@ func (tx *Transaction) checkAndInsertChecksum() error {
 	rowSize := tx.Header.GetRowSize()
 
 	dataStart := checksumStart
-	bytesNeeded := int64(CHECKSUM_INTERVAL+1) * int64(rowSize)
+	bytesNeeded := int64(tx.Header.GetChecksumInterval()+1) * int64(rowSize)
 
 	bytes, err := tx.db.Read(dataStart, int32(bytesNeeded))
 	if err != nil {
@@ -278,14 +308,17 @@ func (tx *Transaction) writeBytes(fullBytes []byte) error {
 		if err != nil {
 			// FR-006: Tombstone transaction on write failure
 			tx.tombstone = true
+			loggerOrNop(tx.logger).Warnf("frozendb: transaction tombstoned after write failure: %v", err)
 			return err
 		}
 		// Update rowBytesWritten to full length after successful write
 		tx.rowBytesWritten = len(fullBytes)
+		tx.bytesWritten += int64(len(newBytes))
 		return nil
 	default:
 		// FR-006: Tombstone transaction on write failure
 		tx.tombstone = true
+		loggerOrNop(tx.logger).Warnf("frozendb: transaction tombstoned: write channel is full or closed")
 		return NewWriteError("write channel is full or closed", nil)
 	}
 }
@@ -330,6 +363,8 @@ func (tx *Transaction) Begin() error {
 		return NewInvalidActionError("Begin() cannot be called when partial row exists", nil)
 	}
 
+	tx.began = time.Now()
+
 	// Create PartialDataRow with start control
 	pdr, err := NewPartialDataRow(tx.Header.GetRowSize(), START_TRANSACTION)
 	if err != nil {
@@ -355,6 +390,86 @@ func (tx *Transaction) Begin() error {
 	return nil
 }
 
+// hasKey reports whether key was already added to the transaction, including the
+// row still being built. The caller must hold tx.mu.
+func (tx *Transaction) hasKey(key uuid.UUID) bool {
+	_, ok := tx.pendingValue(key)
+	return ok
+}
+
+// pendingValue returns the value added for key in this transaction, if any.
+// The caller must hold at least a read lock on tx.mu.
+func (tx *Transaction) pendingValue(key uuid.UUID) (json.RawMessage, bool) {
+	for i := range tx.rows {
+		if tx.rows[i].GetKey() == key {
+			return tx.rows[i].GetValue(), true
+		}
+	}
+	if tx.last != nil {
+		if lastKey, ok := tx.last.GetKey(); ok && lastKey == key {
+			return tx.last.GetValue()
+		}
+	}
+	return nil, false
+}
+
+// Get retrieves the value for key as the transaction would see it if it committed
+// now, giving the writer read-your-own-writes. Rows added to the active transaction
+// are searched first, including rows before a savepoint; committed data on disk is
+// consulted otherwise. Once the transaction has ended, Get reads only committed data.
+// A key deleted in this transaction returns KeyNotFoundError.
+//
+// db.Get is unaffected: other readers never see uncommitted rows.
+//
+// Parameters:
+//   - key: UUIDv7 key to search for (must not be uuid.Nil)
+//   - value: Pointer to unmarshal the JSON value into (must not be nil)
+//
+// Returns:
+//   - error: InvalidInputError, KeyNotFoundError, InvalidDataError, TombstonedError,
+//     or any error returned by FrozenDB.Get for committed rows
+//
+// Thread Safety: Safe for concurrent calls on the same Transaction
+func (tx *Transaction) Get(key uuid.UUID, value any) error {
+	if key == uuid.Nil {
+		return NewInvalidInputError("key cannot be uuid.Nil", nil)
+	}
+	if value == nil {
+		return NewInvalidInputError("value cannot be nil", nil)
+	}
+
+	tx.mu.RLock()
+	if err := tx.checkTombstone(); err != nil {
+		tx.mu.RUnlock()
+		return err
+	}
+	var raw json.RawMessage
+	found := false
+	if tx.isActive() {
+		raw, found = tx.pendingValue(key)
+	}
+	committedGet := tx.committedGet
+	tx.mu.RUnlock()
+
+	if found {
+		raw, err := DecompressValue(tx.Header.GetValueCompression(), raw)
+		if err != nil {
+			return err
+		}
+		if isTombstone(raw) {
+			return NewKeyNotFoundError(fmt.Sprintf("key %s was deleted", key), nil)
+		}
+		if err := json.Unmarshal(raw, value); err != nil {
+			return NewInvalidDataError("failed to unmarshal JSON value", err)
+		}
+		return nil
+	}
+	if committedGet == nil {
+		return NewKeyNotFoundError(fmt.Sprintf("key %s not found in database", key), nil)
+	}
+	return committedGet(key, value)
+}
+
 // AddRow adds a new key-value pair to the transaction.
 //
 // The data flow is:
@@ -364,9 +479,11 @@ func (tx *Transaction) Begin() error {
 //
 // Preconditions:
 //   - Transaction must be active (last non-nil, empty nil)
-//   - Key must be valid UUIDv7
+//   - Key must be valid UUIDv7 and not already added in this transaction
 //   - Value must be non-empty JSON string
-//   - Transaction must have < 100 rows total
+//   - Value must satisfy the database's value schema, if one is set
+//   - Row must pass the database's row validator, if one is set
+//   - Transaction must have fewer rows than its limit (MAX_TRANSACTION_ROWS unless lowered by SetMaxRows)
 //   - UUID timestamp must satisfy: new_timestamp + skew_ms > max_timestamp
 //   - transaction must not be tombstoned
 //
@@ -377,13 +494,90 @@ func (tx *Transaction) Begin() error {
 //
 // Returns:
 //   - InvalidActionError: Transaction not active or already committed
-//   - InvalidInputError: Invalid UUIDv7, empty value, or >=100 rows
+//   - InvalidInputError: Invalid UUIDv7, empty value, or row limit reached
+//   - DuplicateKeyError: Key was already added in this transaction
+//   - InvalidDataError: Value violates the value schema or is rejected by the row validator
 //   - KeyOrderingError: Timestamp ordering violation
 //   - TombstonedError: Transaction is tombstoned
+//
+// TOMBSTONE_VALUE is reserved: AddRow rejects it with InvalidInputError, and keys
+// are deleted with Delete instead.
 func (tx *Transaction) AddRow(key uuid.UUID, value json.RawMessage) error {
+	if isTombstone(value) {
+		return NewInvalidInputError("value is reserved for Delete", nil)
+	}
+	return tx.addRow(key, value, true)
+}
+
+// AddRowIfAbsent adds key with value unless the key is already present, for
+// idempotent ingestion that may retry the same record. A key is present when it
+// was added earlier in this transaction or has a committed row on disk.
+// Occurrences in rolled back transactions and keys whose last committed
+// occurrence is a Delete count as absent. The check and the append happen under
+// the transaction's lock, and the transaction is the database's only writer, so
+// nothing can write the key in between.
+//
+// Parameters:
+//   - key: UUIDv7 key to add
+//   - value: JSON value, subject to the same rules as AddRow
+//
+// Returns:
+//   - added: true if the row was appended, false if the key was already present
+//   - error: the errors of AddRow, or ReadError or CorruptDatabaseError from the
+//     existence check. A key deleted earlier in this transaction returns
+//     DuplicateKeyError, as with AddRow.
+//
+// Thread Safety: Safe for concurrent calls on the same Transaction
+func (tx *Transaction) AddRowIfAbsent(key uuid.UUID, value json.RawMessage) (bool, error) {
+	if isTombstone(value) {
+		return false, NewInvalidInputError("value is reserved for Delete", nil)
+	}
+
 	tx.mu.Lock()
 	defer tx.mu.Unlock()
 
+	if err := tx.checkTombstone(); err != nil {
+		return false, err
+	}
+	if !tx.isActive() {
+		return false, NewInvalidActionError("AddRowIfAbsent() requires an active transaction", nil)
+	}
+	if err := ValidateUUIDv7(key); err != nil {
+		return false, NewInvalidInputError("invalid UUIDv7 key", err)
+	}
+
+	if pending, ok := tx.pendingValue(key); ok {
+		// A key deleted in this transaction is absent, but addRowUnlocked rejects it as a duplicate
+		decoded, err := DecompressValue(tx.Header.GetValueCompression(), pending)
+		if err != nil || !isTombstone(decoded) {
+			return false, nil
+		}
+	} else if tx.committedHas != nil {
+		present, err := tx.committedHas(key)
+		if err != nil {
+			return false, err
+		}
+		if present {
+			return false, nil
+		}
+	}
+
+	if err := tx.addRowUnlocked(key, value, true); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// addRow implements AddRow and Delete. checkValue runs the value schema and row
+// validator, which Delete skips since its value is not application data.
+func (tx *Transaction) addRow(key uuid.UUID, value json.RawMessage, checkValue bool) error {
+	tx.mu.Lock()
+	defer tx.mu.Unlock()
+	return tx.addRowUnlocked(key, value, checkValue)
+}
+
+// addRowUnlocked implements addRow. The caller must hold tx.mu.
+func (tx *Transaction) addRowUnlocked(key uuid.UUID, value json.RawMessage, checkValue bool) error {
 	// FR-006: Check if tombstoned
 	if err := tx.checkTombstone(); err != nil {
 		return err
@@ -407,6 +601,32 @@ func (tx *Transaction) AddRow(key uuid.UUID, value json.RawMessage) error {
 		return NewInvalidInputError("value cannot be empty", nil)
 	}
 
+	// Reject a key already added in this transaction: Get could only return one of the values
+	if tx.hasKey(key) {
+		return NewDuplicateKeyError(fmt.Sprintf("key %s was already added in this transaction", key), nil)
+	}
+
+	if checkValue {
+		// Validate against the value schema, if one was set on the database
+		if tx.valueSchema != nil {
+			if err := tx.valueSchema.validate(value); err != nil {
+				return err
+			}
+		}
+
+		// Run the application's row validator, if one was set on the database
+		if err := validateRow(tx.rowValidator, key, value); err != nil {
+			return err
+		}
+	}
+
+	// Compress the stored bytes if the database was created with value compression;
+	// the checks above apply to the value as written
+	stored, err := CompressValue(tx.Header.GetValueCompression(), value)
+	if err != nil {
+		return err
+	}
+
 	// FR-010: Validate row count
 	// Total rows after this AddRow = len(tx.rows) + 1 (if we finalize) + 1 (new/current partial)
 	// Or len(tx.rows) + 1 (if we just add to existing partial)
@@ -415,8 +635,8 @@ func (tx *Transaction) AddRow(key uuid.UUID, value json.RawMessage) error {
 	if tx.last.GetState() != PartialDataRowWithStartControl {
 		currentTotal++ // Current partial will become a row
 	}
-	if currentTotal >= 100 {
-		return NewInvalidInputError("transaction cannot contain more than 100 rows", nil)
+	if limit := tx.rowLimit(); currentTotal >= limit {
+		return NewInvalidInputError(fmt.Sprintf("transaction cannot contain more than %d rows", limit), nil)
 	}
 
 	// FR-014, FR-016, FR-017: Validate timestamp ordering
@@ -435,12 +655,20 @@ func (tx *Transaction) AddRow(key uuid.UUID, value json.RawMessage) error {
 		return NewKeyOrderingError("UUID timestamp violates ordering constraint: new_timestamp + skew_ms must be > max_timestamp", nil)
 	}
 
+	// Optional guard: new_timestamp must not exceed now + skew_ms
+	if tx.futureGuard {
+		if now := time.Now().UnixMilli(); newTimestamp > now+skewMs {
+			msg := fmt.Sprintf("UUID timestamp %d is more than skew_ms (%d) ahead of the system clock (%d)", newTimestamp, skewMs, now)
+			return NewFutureTimestampError(msg, newTimestamp, now, NewInvalidInputError(msg, nil))
+		}
+	}
+
 	// Check the current state of the partial row
 	if tx.last.GetState() == PartialDataRowWithStartControl {
 		// First AddRow after Begin(): add key/value to the existing partial
 		// The partial already has START_TRANSACTION from Begin()
 
-		if err := tx.last.AddRow(key, value); err != nil {
+		if err := tx.last.AddRow(key, stored); err != nil {
 			return err
 		}
 
@@ -496,7 +724,7 @@ func (tx *Transaction) AddRow(key uuid.UUID, value json.RawMessage) error {
 		}
 
 		// Add the key-value data to the new partial
-		if err := newPdr.AddRow(key, value); err != nil {
+		if err := newPdr.AddRow(key, stored); err != nil {
 			return err
 		}
 
@@ -522,7 +750,21 @@ func (tx *Transaction) AddRow(key uuid.UUID, value json.RawMessage) error {
 		tx.maxTimestamp = newTimestamp
 	}
 
-	return nil
+	return tx.autoSavepointUnlocked()
+}
+
+// autoSavepointUnlocked creates the savepoint requested by SetAutoSavepoint when
+// the row just added completes another group of rows. The caller must hold the
+// write lock on tx.mu.
+func (tx *Transaction) autoSavepointUnlocked() error {
+	if tx.autoSavepoint == 0 || (len(tx.rows)+1)%tx.autoSavepoint != 0 {
+		return nil
+	}
+	// Stop creating savepoints once all 9 are used
+	if len(tx.getSavepointIndicesUnlocked()) >= 9 {
+		return nil
+	}
+	return tx.savepointUnlocked()
 }
 
 // Commit finalizes the transaction.
@@ -624,6 +866,7 @@ func (tx *Transaction) Commit() error {
 
 		// Wait for writer to complete before returning to eliminate race condition
 		tx.db.WriterClosed()
+		tx.ended = time.Now()
 
 		return nil
 	}
@@ -671,6 +914,7 @@ func (tx *Transaction) Commit() error {
 
 	// Wait for writer to complete before returning to eliminate race condition
 	tx.db.WriterClosed()
+	tx.ended = time.Now()
 
 	return nil
 }
@@ -731,6 +975,7 @@ func (tx *Transaction) Close() error {
 
 	// Tombstone the transaction first
 	tx.tombstone = true
+	loggerOrNop(tx.logger).Debugf("frozendb: transaction closed")
 
 	// Close the writer channel if it exists
 	// This signals writerLoop to exit, which will nil out FileManager's writeChannel
@@ -760,6 +1005,11 @@ func (tx *Transaction) Savepoint() error {
 	tx.mu.Lock()
 	defer tx.mu.Unlock()
 
+	return tx.savepointUnlocked()
+}
+
+// savepointUnlocked implements Savepoint(). The caller must hold the write lock on tx.mu.
+func (tx *Transaction) savepointUnlocked() error {
 	// FR-006: Check if tombstoned
 	if err := tx.checkTombstone(); err != nil {
 		return err
@@ -810,6 +1060,133 @@ func (tx *Transaction) Savepoint() error {
 	return nil
 }
 
+// SetAutoSavepoint makes AddRow create a savepoint after every everyN rows of
+// the transaction, as if Savepoint() were called after the everyN-th,
+// 2*everyN-th, ... row. After an error, Rollback(n) then keeps the first n*everyN
+// rows (fewer if manual savepoints were also created).
+//
+// Automatic savepoints share the 9 savepoint ids with Savepoint() and
+// SavepointNamed(). Once 9 savepoints exist, no more are created automatically
+// and AddRow continues without them; a manual Savepoint() then fails as usual.
+// The setting applies to rows added after the call and lasts for the lifetime of
+// the transaction.
+//
+// Parameters:
+//   - everyN: Rows between automatic savepoints; 0 disables them
+//
+// Returns:
+//   - nil on success
+//   - InvalidInputError if everyN is negative
+//   - TombstonedError if transaction is tombstoned
+//
+// If writing an automatic savepoint fails, AddRow returns the error and the
+// transaction is tombstoned, as with Savepoint().
+func (tx *Transaction) SetAutoSavepoint(everyN int) error {
+	tx.mu.Lock()
+	defer tx.mu.Unlock()
+
+	if err := tx.checkTombstone(); err != nil {
+		return err
+	}
+	if everyN < 0 {
+		return NewInvalidInputError(fmt.Sprintf("auto savepoint interval must not be negative, got %d", everyN), nil)
+	}
+	tx.autoSavepoint = everyN
+	return nil
+}
+
+// SetMaxRows lowers the number of data rows this transaction accepts; AddRow
+// rejects rows beyond it with InvalidInputError. The default, and the ceiling, is
+// MAX_TRANSACTION_ROWS: the file format does not allow longer transactions, so a
+// larger limit is rejected rather than clamped. A limit below the rows already
+// added makes every further AddRow fail.
+//
+// Parameters:
+//   - n: Maximum data rows, in [1, MAX_TRANSACTION_ROWS]
+//
+// Returns:
+//   - nil on success
+//   - InvalidInputError if n is out of range
+//   - TombstonedError if transaction is tombstoned
+func (tx *Transaction) SetMaxRows(n int) error {
+	tx.mu.Lock()
+	defer tx.mu.Unlock()
+
+	if err := tx.checkTombstone(); err != nil {
+		return err
+	}
+	if n < 1 || n > MAX_TRANSACTION_ROWS {
+		return NewInvalidInputError(fmt.Sprintf(
+			"max rows must be between 1 and %d, got %d; the file format limits a transaction to %d data rows",
+			MAX_TRANSACTION_ROWS, n, MAX_TRANSACTION_ROWS), nil)
+	}
+	tx.maxRows = n
+	return nil
+}
+
+// rowLimit returns the number of data rows the transaction accepts.
+func (tx *Transaction) rowLimit() int {
+	if tx.maxRows == 0 {
+		return MAX_TRANSACTION_ROWS
+	}
+	return tx.maxRows
+}
+
+// SavepointNamed creates a savepoint like Savepoint() and associates it with label,
+// so it can later be targeted with RollbackTo(label) instead of a numeric id.
+//
+// Labels exist only in memory for the lifetime of this Transaction; the on-disk
+// encoding is unchanged and the savepoint still occupies one of the 9 available ids.
+//
+// Returns:
+//   - nil on success
+//   - InvalidInputError if label is empty or already used in this transaction
+//   - InvalidActionError under the same conditions as Savepoint()
+//   - TombstonedError if transaction is tombstoned
+func (tx *Transaction) SavepointNamed(label string) error {
+	tx.mu.Lock()
+	defer tx.mu.Unlock()
+
+	if err := tx.checkTombstone(); err != nil {
+		return err
+	}
+	if label == "" {
+		return NewInvalidInputError("savepoint label cannot be empty", nil)
+	}
+	if _, exists := tx.savepointLabels[label]; exists {
+		return NewInvalidInputError(fmt.Sprintf("savepoint label %q already exists", label), nil)
+	}
+
+	if err := tx.savepointUnlocked(); err != nil {
+		return err
+	}
+
+	// The new savepoint is on the partial row, after all savepoints in complete rows
+	if tx.savepointLabels == nil {
+		tx.savepointLabels = make(map[string]int)
+	}
+	tx.savepointLabels[label] = len(tx.getSavepointIndicesUnlocked()) + 1
+	return nil
+}
+
+// RollbackTo rolls back the transaction to the savepoint created by
+// SavepointNamed(label). It is equivalent to Rollback(id) with the savepoint's id.
+//
+// Returns:
+//   - nil on success
+//   - InvalidInputError if no savepoint with this label exists
+//   - Any error returned by Rollback()
+func (tx *Transaction) RollbackTo(label string) error {
+	tx.mu.RLock()
+	id, exists := tx.savepointLabels[label]
+	tx.mu.RUnlock()
+
+	if !exists {
+		return NewInvalidInputError(fmt.Sprintf("unknown savepoint label %q", label), nil)
+	}
+	return tx.Rollback(id)
+}
+
 // Rollback rolls back the transaction to a specified savepoint or fully closes it.
 //
 // Parameters:
@@ -926,6 +1303,7 @@ func (tx *Transaction) Rollback(savepointId int) error {
 
 		// Wait for writer to complete before returning to eliminate race condition
 		tx.db.WriterClosed()
+		tx.ended = time.Now()
 
 		return nil
 	}
@@ -966,6 +1344,7 @@ func (tx *Transaction) Rollback(savepointId int) error {
 
 	// Wait for writer to complete before returning to eliminate race condition
 	tx.db.WriterClosed()
+	tx.ended = time.Now()
 
 	return nil
 }
@@ -1075,6 +1454,42 @@ func (tx *Transaction) IsRowCommitted(index int) (bool, error) {
 	return false, nil
 }
 
+// ChecksumRowsWritten returns how many checksum rows were written to the file
+// during this transaction, one for every checksum interval (10,000 rows by
+// default) of the file that its rows completed. It keeps counting after the
+// transaction ends, including the row written after its final row.
+func (tx *Transaction) ChecksumRowsWritten() int {
+	tx.mu.RLock()
+	defer tx.mu.RUnlock()
+	return tx.checksumRows
+}
+
+// Elapsed returns the time from Begin to the end of the Commit or Rollback that
+// finished the transaction, including the checksum row written after its final
+// row, if any. While the transaction is active it returns the time since Begin so
+// far, and before Begin it returns 0.
+func (tx *Transaction) Elapsed() time.Duration {
+	tx.mu.RLock()
+	defer tx.mu.RUnlock()
+	if tx.began.IsZero() {
+		return 0
+	}
+	if tx.ended.IsZero() {
+		return time.Since(tx.began)
+	}
+	return tx.ended.Sub(tx.began)
+}
+
+// WriteBytes returns the number of bytes the transaction appended to the file,
+// including checksum rows written during it. Like ChecksumRowsWritten, it counts
+// the checksum row written after the final row. Bytes of a write that failed are
+// not counted.
+func (tx *Transaction) WriteBytes() int64 {
+	tx.mu.RLock()
+	defer tx.mu.RUnlock()
+	return tx.bytesWritten
+}
+
 // GetSavepointIndices identifies all savepoint locations within the transaction
 // using EndControl patterns with 'S' as first character.
 // Returns indices for easy reference within the slice.
@@ -1101,6 +1516,44 @@ func (tx *Transaction) getSavepointIndicesUnlocked() []int {
 	return savepointIndices
 }
 
+// SavepointInfo describes one savepoint of a transaction, as returned by
+// Transaction.Savepoints.
+type SavepointInfo struct {
+	Number   int       // Savepoint id (1-9) accepted by Rollback
+	RowIndex int       // Index of the row carrying the savepoint within the transaction
+	Key      uuid.UUID // Key of that row: the savepoint is set after this key
+}
+
+// Savepoints returns the savepoints of the transaction in order, numbered 1-9 as
+// Rollback expects them. Unlike GetSavepointIndices it includes a savepoint set
+// on the current partial row, whose RowIndex is the number of complete rows.
+// Returns an empty slice if the transaction is tombstoned.
+func (tx *Transaction) Savepoints() []SavepointInfo {
+	tx.mu.RLock()
+	defer tx.mu.RUnlock()
+	if tx.tombstone {
+		return []SavepointInfo{}
+	}
+
+	savepoints := []SavepointInfo{}
+	for _, i := range tx.getSavepointIndicesUnlocked() {
+		savepoints = append(savepoints, SavepointInfo{
+			Number:   len(savepoints) + 1,
+			RowIndex: i,
+			Key:      tx.rows[i].GetKey(),
+		})
+	}
+	if tx.last != nil && tx.last.GetState() == PartialDataRowWithSavepoint {
+		key, _ := tx.last.GetKey()
+		savepoints = append(savepoints, SavepointInfo{
+			Number:   len(savepoints) + 1,
+			RowIndex: len(tx.rows),
+			Key:      key,
+		})
+	}
+	return savepoints
+}
+
 // Validate scans all rows in the slice to ensure transaction integrity.
 // Verifies:
 //   - First row has StartControl = 'T' (transaction start)
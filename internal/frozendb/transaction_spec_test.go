@@ -4254,7 +4254,7 @@ func Test_S_016_FR_004_FormatRequirements(t *testing.T) {
 
 	// Verify checksum row can be unmarshaled and validated
 	checksumRow := &ChecksumRow{
-		baseRow[*Checksum]{
+		baseRow: baseRow[*Checksum]{
 			RowSize: header.GetRowSize(),
 		},
 	}
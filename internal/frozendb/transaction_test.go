@@ -2,7 +2,11 @@ package frozendb
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
 	"sync"
 	"testing"
 	"time"
@@ -254,6 +258,77 @@ func TestAddRow_TimestampOrdering(t *testing.T) {
 	})
 }
 
+// TestAddRow_DuplicateKey verifies that a key cannot be added twice in one transaction
+func TestAddRow_DuplicateKey(t *testing.T) {
+	t.Run("exact_duplicate_of_previous_key", func(t *testing.T) {
+		tx := createTransactionWithMockWriter(createTestHeader())
+		tx.Begin()
+
+		key := uuidFromTS(1000)
+		if err := tx.AddRow(key, json.RawMessage(`{"v":1}`)); err != nil {
+			t.Fatalf("First AddRow failed: %v", err)
+		}
+		err := tx.AddRow(key, json.RawMessage(`{"v":2}`))
+		if _, ok := err.(*DuplicateKeyError); !ok {
+			t.Fatalf("Expected DuplicateKeyError, got %T: %v", err, err)
+		}
+		if len(tx.rows) != 0 {
+			t.Errorf("Rejected row must not finalize the previous row, got %d rows", len(tx.rows))
+		}
+
+		// The transaction remains usable
+		if err := tx.AddRow(uuidFromTS(1001), json.RawMessage(`{"v":3}`)); err != nil {
+			t.Fatalf("AddRow after duplicate failed: %v", err)
+		}
+	})
+
+	t.Run("exact_duplicate_of_earlier_key", func(t *testing.T) {
+		tx := createTransactionWithMockWriter(createTestHeader())
+		tx.Begin()
+
+		for _, ts := range []int{1000, 1001, 1002} {
+			if err := tx.AddRow(uuidFromTS(ts), json.RawMessage(`{}`)); err != nil {
+				t.Fatalf("AddRow failed: %v", err)
+			}
+		}
+		err := tx.AddRow(uuidFromTS(1000), json.RawMessage(`{}`))
+		if _, ok := err.(*DuplicateKeyError); !ok {
+			t.Fatalf("Expected DuplicateKeyError, got %T: %v", err, err)
+		}
+	})
+
+	t.Run("near_duplicate_same_millisecond_accepted", func(t *testing.T) {
+		tx := createTransactionWithMockWriter(createTestHeader())
+		tx.Begin()
+
+		// Same 48-bit timestamp, different random bits
+		key1 := uuidFromTS(1000)
+		key2 := key1
+		key2[15] ^= 0xFF
+		if err := tx.AddRow(key1, json.RawMessage(`{"v":1}`)); err != nil {
+			t.Fatalf("First AddRow failed: %v", err)
+		}
+		if err := tx.AddRow(key2, json.RawMessage(`{"v":2}`)); err != nil {
+			t.Fatalf("Near-duplicate key within skew should be accepted: %v", err)
+		}
+	})
+
+	t.Run("has_key_includes_row_being_built", func(t *testing.T) {
+		tx := createTransactionWithMockWriter(createTestHeader())
+		tx.Begin()
+		key := uuidFromTS(1000)
+		if err := tx.AddRow(key, json.RawMessage(`{}`)); err != nil {
+			t.Fatalf("AddRow failed: %v", err)
+		}
+		if !tx.hasKey(key) {
+			t.Fatal("hasKey should report the row still being built")
+		}
+		if tx.hasKey(uuidFromTS(1001)) {
+			t.Fatal("hasKey reported a key that was never added")
+		}
+	})
+}
+
 // TestAddRow_RowCountLimit verifies the 100 row limit
 func TestAddRow_RowCountLimit(t *testing.T) {
 	header := createTestHeader()
@@ -2524,6 +2599,192 @@ func TestRollback_WithSavepointOnCurrentRow(t *testing.T) {
 // Disk Persistence Unit Tests (015-transaction-persistence)
 // =============================================================================
 
+// TestSavepointNamed_RollbackTo verifies that labels map onto the numeric savepoint ids
+func TestSavepoints(t *testing.T) {
+	header := createTestHeader()
+	tx := createTransactionWithMockWriter(header)
+	tx.Begin()
+
+	if got := tx.Savepoints(); len(got) != 0 {
+		t.Fatalf("Savepoints() before any savepoint = %v, want none", got)
+	}
+
+	var keys []uuid.UUID
+	for i := 0; i < 5; i++ {
+		key, _ := uuid.NewV7()
+		keys = append(keys, key)
+		if err := tx.AddRow(key, json.RawMessage(`{"data":"test"}`)); err != nil {
+			t.Fatalf("AddRow() %d failed: %v", i, err)
+		}
+		// Savepoints after the second, third, and last (still partial) rows
+		if i == 1 || i == 2 || i == 4 {
+			if err := tx.Savepoint(); err != nil {
+				t.Fatalf("Savepoint() after row %d failed: %v", i, err)
+			}
+		}
+	}
+
+	want := []SavepointInfo{
+		{Number: 1, RowIndex: 1, Key: keys[1]},
+		{Number: 2, RowIndex: 2, Key: keys[2]},
+		{Number: 3, RowIndex: 4, Key: keys[4]},
+	}
+	got := tx.Savepoints()
+	if len(got) != len(want) {
+		t.Fatalf("Savepoints() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Savepoints()[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+	if indices := tx.GetSavepointIndices(); len(indices) != 2 {
+		t.Errorf("GetSavepointIndices() = %v, want the 2 complete rows only", indices)
+	}
+
+	// Rolling back to a listed number keeps the rows up to its key
+	if err := tx.Rollback(got[1].Number); err != nil {
+		t.Fatalf("Rollback(%d) failed: %v", got[1].Number, err)
+	}
+	iter, _ := tx.GetCommittedRows()
+	committed := 0
+	for _, more := iter(); more; _, more = iter() {
+		committed++
+	}
+	if committed != got[1].RowIndex+1 {
+		t.Errorf("Expected %d committed rows, got %d", got[1].RowIndex+1, committed)
+	}
+}
+
+func TestSavepointNamed_RollbackTo(t *testing.T) {
+	header := createTestHeader()
+
+	t.Run("rollback_to_label_commits_rows_up_to_labelled_savepoint", func(t *testing.T) {
+		tx := createTransactionWithMockWriter(header)
+		tx.Begin()
+
+		for i, label := range []string{"first", "second", "third"} {
+			key, _ := uuid.NewV7()
+			if err := tx.AddRow(key, json.RawMessage(`{"data":"test"}`)); err != nil {
+				t.Fatalf("AddRow() %d failed: %v", i, err)
+			}
+			if err := tx.SavepointNamed(label); err != nil {
+				t.Fatalf("SavepointNamed(%q) failed: %v", label, err)
+			}
+		}
+		key, _ := uuid.NewV7()
+		tx.AddRow(key, json.RawMessage(`{"data":"after"}`))
+
+		if err := tx.RollbackTo("second"); err != nil {
+			t.Fatalf("RollbackTo(second) failed: %v", err)
+		}
+
+		rows := tx.rows
+		if got := rows[len(rows)-1].EndControl; got != (EndControl{'R', '2'}) {
+			t.Errorf("Expected last row end control R2, got %s", got)
+		}
+
+		iter, _ := tx.GetCommittedRows()
+		committed := 0
+		for _, more := iter(); more; _, more = iter() {
+			committed++
+		}
+		if committed != 2 {
+			t.Errorf("Expected 2 committed rows, got %d", committed)
+		}
+	})
+
+	t.Run("labels_share_ids_with_numeric_savepoints", func(t *testing.T) {
+		tx := createTransactionWithMockWriter(header)
+		tx.Begin()
+
+		key1, _ := uuid.NewV7()
+		tx.AddRow(key1, json.RawMessage(`{"data":"first"}`))
+		tx.Savepoint()
+
+		key2, _ := uuid.NewV7()
+		tx.AddRow(key2, json.RawMessage(`{"data":"second"}`))
+		if err := tx.SavepointNamed("named"); err != nil {
+			t.Fatalf("SavepointNamed failed: %v", err)
+		}
+
+		// Savepoint on the current (last) row: rollback encodes S2
+		if err := tx.RollbackTo("named"); err != nil {
+			t.Fatalf("RollbackTo failed: %v", err)
+		}
+		rows := tx.rows
+		if got := rows[len(rows)-1].EndControl; got != (EndControl{'S', '2'}) {
+			t.Errorf("Expected last row end control S2, got %s", got)
+		}
+	})
+
+	t.Run("unknown_label_fails", func(t *testing.T) {
+		tx := createTransactionWithMockWriter(header)
+		tx.Begin()
+
+		key, _ := uuid.NewV7()
+		tx.AddRow(key, json.RawMessage(`{"data":"test"}`))
+
+		err := tx.RollbackTo("missing")
+		if _, ok := err.(*InvalidInputError); !ok {
+			t.Fatalf("Expected InvalidInputError, got %T: %v", err, err)
+		}
+		if !tx.isActive() {
+			t.Error("Transaction should remain active after failed RollbackTo")
+		}
+	})
+
+	t.Run("empty_and_duplicate_labels_fail", func(t *testing.T) {
+		tx := createTransactionWithMockWriter(header)
+		tx.Begin()
+
+		key, _ := uuid.NewV7()
+		tx.AddRow(key, json.RawMessage(`{"data":"test"}`))
+
+		if _, ok := tx.SavepointNamed("").(*InvalidInputError); !ok {
+			t.Error("Expected InvalidInputError for empty label")
+		}
+		if err := tx.SavepointNamed("dup"); err != nil {
+			t.Fatalf("SavepointNamed failed: %v", err)
+		}
+
+		key2, _ := uuid.NewV7()
+		tx.AddRow(key2, json.RawMessage(`{"data":"test"}`))
+		if _, ok := tx.SavepointNamed("dup").(*InvalidInputError); !ok {
+			t.Error("Expected InvalidInputError for duplicate label")
+		}
+		if got := len(tx.GetSavepointIndices()); got != 1 {
+			t.Errorf("Expected duplicate label to not create a savepoint, got %d savepoints", got)
+		}
+	})
+
+	t.Run("10th_named_savepoint_fails", func(t *testing.T) {
+		tx := createTransactionWithMockWriter(header)
+		tx.Begin()
+
+		for i := 0; i < 9; i++ {
+			key, _ := uuid.NewV7()
+			tx.AddRow(key, json.RawMessage(`{"data":"test"}`))
+			if err := tx.SavepointNamed(fmt.Sprintf("sp%d", i+1)); err != nil {
+				t.Fatalf("SavepointNamed %d failed: %v", i+1, err)
+			}
+		}
+		key, _ := uuid.NewV7()
+		tx.AddRow(key, json.RawMessage(`{"data":"test"}`))
+
+		err := tx.SavepointNamed("sp10")
+		if _, ok := err.(*InvalidActionError); !ok {
+			t.Fatalf("Expected InvalidActionError, got %T: %v", err, err)
+		}
+		if err := tx.RollbackTo("sp10"); err == nil {
+			t.Error("Failed savepoint should not register its label")
+		}
+		if err := tx.RollbackTo("sp9"); err != nil {
+			t.Fatalf("RollbackTo(sp9) failed: %v", err)
+		}
+	})
+}
+
 // createTransactionWithByteCollector creates a transaction with a write channel
 // that collects all written bytes into a slice. This simulates an in-memory file
 // by appending all bytes written to the channel.
@@ -3680,3 +3941,462 @@ func TestTransactionPersistence_SynchronousWrites(t *testing.T) {
 		}
 	})
 }
+
+func TestTransactionGet_ReadYourOwnWrites(t *testing.T) {
+	path := setupCreate(t, t.TempDir(), 0)
+	db, err := NewFrozenDB(path, MODE_WRITE, FinderStrategySimple)
+	if err != nil {
+		t.Fatalf("NewFrozenDB: %v", err)
+	}
+	defer db.Close()
+
+	committedKey := uuidFromTS(1000)
+	tx, err := db.BeginTx()
+	if err != nil {
+		t.Fatalf("BeginTx: %v", err)
+	}
+	mustAdd(t, tx, committedKey, `{"v":"committed"}`)
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+
+	tx, err = db.BeginTx()
+	if err != nil {
+		t.Fatalf("BeginTx: %v", err)
+	}
+	first, last := uuidFromTS(2000), uuidFromTS(2001)
+	mustAdd(t, tx, first, `{"v":"first"}`)
+	if err := tx.Savepoint(); err != nil {
+		t.Fatalf("Savepoint: %v", err)
+	}
+	mustAdd(t, tx, last, `{"v":"last"}`)
+
+	for key, want := range map[uuid.UUID]string{committedKey: "committed", first: "first", last: "last"} {
+		var got map[string]string
+		if err := tx.Get(key, &got); err != nil {
+			t.Fatalf("tx.Get(%s): %v", key, err)
+		}
+		if got["v"] != want {
+			t.Errorf("tx.Get(%s) = %v, want %s", key, got, want)
+		}
+	}
+
+	// Readers outside the transaction do not see uncommitted rows
+	var value map[string]string
+	if err := db.Get(last, &value); err == nil {
+		t.Error("db.Get returned an uncommitted row")
+	}
+	if err := tx.Get(uuidFromTS(3000), &value); err == nil {
+		t.Error("expected KeyNotFoundError for unknown key")
+	} else if _, ok := err.(*KeyNotFoundError); !ok {
+		t.Errorf("expected KeyNotFoundError, got %T: %v", err, err)
+	}
+	if err := tx.Get(uuid.Nil, &value); err == nil {
+		t.Error("expected InvalidInputError for uuid.Nil")
+	}
+
+	// After rolling back to the savepoint only committed data is visible
+	if err := tx.Rollback(1); err != nil {
+		t.Fatalf("Rollback: %v", err)
+	}
+	if err := tx.Get(first, &value); err != nil || value["v"] != "first" {
+		t.Errorf("tx.Get(first) after rollback = %v, %v", value, err)
+	}
+	if err := tx.Get(last, &value); err == nil {
+		t.Error("expected rolled back row to be invisible")
+	}
+}
+
+func TestSetAutoSavepoint(t *testing.T) {
+	header := createTestHeader()
+
+	addRows := func(t *testing.T, tx *Transaction, n int) {
+		t.Helper()
+		for i := 0; i < n; i++ {
+			key, _ := uuid.NewV7()
+			if err := tx.AddRow(key, json.RawMessage(`{"data":"test"}`)); err != nil {
+				t.Fatalf("AddRow() %d failed: %v", i, err)
+			}
+		}
+	}
+	committedCount := func(tx *Transaction) int {
+		iter, _ := tx.GetCommittedRows()
+		count := 0
+		for _, more := iter(); more; _, more = iter() {
+			count++
+		}
+		return count
+	}
+
+	t.Run("savepoint_after_every_n_rows", func(t *testing.T) {
+		tx := createTransactionWithMockWriter(header)
+		tx.Begin()
+		if err := tx.SetAutoSavepoint(2); err != nil {
+			t.Fatalf("SetAutoSavepoint failed: %v", err)
+		}
+		addRows(t, tx, 5)
+
+		if got := tx.GetSavepointIndices(); !slices.Equal(got, []int{1, 3}) {
+			t.Fatalf("Expected savepoints at rows [1 3], got %v", got)
+		}
+		// Roll back to the last automatic savepoint: the first 4 rows are kept
+		if err := tx.Rollback(2); err != nil {
+			t.Fatalf("Rollback(2) failed: %v", err)
+		}
+		if got := committedCount(tx); got != 4 {
+			t.Errorf("Expected 4 committed rows, got %d", got)
+		}
+	})
+
+	t.Run("stops_at_exactly_nine_savepoints", func(t *testing.T) {
+		tx := createTransactionWithMockWriter(header)
+		tx.Begin()
+		if err := tx.SetAutoSavepoint(1); err != nil {
+			t.Fatalf("SetAutoSavepoint failed: %v", err)
+		}
+		addRows(t, tx, 9)
+		// The ninth savepoint is on the row still being written
+		if got := len(tx.GetSavepointIndices()); got != 8 || tx.last.GetState() != PartialDataRowWithSavepoint {
+			t.Fatalf("Expected 8 complete savepoint rows and a savepoint on the partial row, got %d (%v)", got, tx.last.GetState())
+		}
+
+		// Rows after the ninth savepoint are added without one
+		addRows(t, tx, 3)
+		if got := tx.GetSavepointIndices(); !slices.Equal(got, []int{0, 1, 2, 3, 4, 5, 6, 7, 8}) {
+			t.Fatalf("Expected savepoints on the first 9 rows only, got %v", got)
+		}
+		if _, ok := tx.Savepoint().(*InvalidActionError); !ok {
+			t.Error("Expected manual Savepoint() to fail once 9 savepoints exist")
+		}
+
+		if err := tx.Rollback(9); err != nil {
+			t.Fatalf("Rollback(9) failed: %v", err)
+		}
+		if got := committedCount(tx); got != 9 {
+			t.Errorf("Expected 9 committed rows, got %d", got)
+		}
+	})
+
+	t.Run("shares_ids_with_manual_savepoints", func(t *testing.T) {
+		tx := createTransactionWithMockWriter(header)
+		tx.Begin()
+		if err := tx.SetAutoSavepoint(3); err != nil {
+			t.Fatalf("SetAutoSavepoint failed: %v", err)
+		}
+		addRows(t, tx, 1)
+		if err := tx.Savepoint(); err != nil {
+			t.Fatalf("Savepoint failed: %v", err)
+		}
+		addRows(t, tx, 3)
+
+		if got := tx.GetSavepointIndices(); !slices.Equal(got, []int{0, 2}) {
+			t.Fatalf("Expected savepoints at rows [0 2], got %v", got)
+		}
+	})
+
+	t.Run("zero_disables_and_negative_fails", func(t *testing.T) {
+		tx := createTransactionWithMockWriter(header)
+		tx.Begin()
+		if _, ok := tx.SetAutoSavepoint(-1).(*InvalidInputError); !ok {
+			t.Error("Expected InvalidInputError for negative interval")
+		}
+		tx.SetAutoSavepoint(1)
+		tx.SetAutoSavepoint(0)
+		addRows(t, tx, 3)
+		if got := tx.GetSavepointIndices(); len(got) != 0 {
+			t.Errorf("Expected no savepoints, got %v", got)
+		}
+	})
+}
+
+func TestSetMaxRows(t *testing.T) {
+	header := createTestHeader()
+	addRow := func(tx *Transaction) error {
+		key, _ := uuid.NewV7()
+		return tx.AddRow(key, json.RawMessage(`{"data":"test"}`))
+	}
+
+	t.Run("lowered_limit_is_enforced", func(t *testing.T) {
+		tx := createTransactionWithMockWriter(header)
+		tx.Begin()
+		if err := tx.SetMaxRows(3); err != nil {
+			t.Fatalf("SetMaxRows failed: %v", err)
+		}
+		for i := 0; i < 3; i++ {
+			if err := addRow(tx); err != nil {
+				t.Fatalf("AddRow() %d failed: %v", i, err)
+			}
+		}
+		if _, ok := addRow(tx).(*InvalidInputError); !ok {
+			t.Fatal("Expected InvalidInputError once the limit is reached")
+		}
+		if err := tx.Commit(); err != nil {
+			t.Fatalf("Commit failed: %v", err)
+		}
+	})
+
+	t.Run("default_is_format_maximum", func(t *testing.T) {
+		tx := createTransactionWithMockWriter(header)
+		tx.Begin()
+		for i := 0; i < MAX_TRANSACTION_ROWS; i++ {
+			if err := addRow(tx); err != nil {
+				t.Fatalf("AddRow() %d failed: %v", i, err)
+			}
+		}
+		if _, ok := addRow(tx).(*InvalidInputError); !ok {
+			t.Fatalf("Expected InvalidInputError after %d rows", MAX_TRANSACTION_ROWS)
+		}
+	})
+
+	t.Run("out_of_range_fails", func(t *testing.T) {
+		tx := createTransactionWithMockWriter(header)
+		tx.Begin()
+		for _, n := range []int{0, -1, MAX_TRANSACTION_ROWS + 1} {
+			if _, ok := tx.SetMaxRows(n).(*InvalidInputError); !ok {
+				t.Errorf("Expected InvalidInputError for SetMaxRows(%d)", n)
+			}
+		}
+		if err := tx.SetMaxRows(MAX_TRANSACTION_ROWS); err != nil {
+			t.Errorf("SetMaxRows(MAX_TRANSACTION_ROWS) failed: %v", err)
+		}
+	})
+}
+
+func TestChecksumRowsWritten(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "checksum.fdb")
+	setupMockSyscalls(false, false)
+	t.Cleanup(restoreRealSyscalls)
+	t.Setenv("SUDO_USER", MOCK_USER)
+	t.Setenv("SUDO_UID", MOCK_UID)
+	t.Setenv("SUDO_GID", MOCK_GID)
+	config := NewCreateConfig(path, confRowSize, confSkewMs)
+	config.SetChecksumInterval(MIN_CHECKSUM_INTERVAL)
+	if err := Create(config); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	db, err := NewFrozenDB(path, MODE_WRITE, FinderStrategySimple)
+	if err != nil {
+		t.Fatalf("NewFrozenDB: %v", err)
+	}
+	defer db.Close()
+
+	// The second transaction completes the first interval with its final row, and
+	// the third crosses the second interval mid-transaction
+	ts := 1000
+	for i, tc := range []struct{ rows, want int }{{60, 0}, {40, 1}, {50, 0}, {60, 1}} {
+		tx, err := db.BeginTx()
+		if err != nil {
+			t.Fatalf("BeginTx: %v", err)
+		}
+		for range tc.rows {
+			mustAdd(t, tx, uuidFromTS(ts), `{}`)
+			ts++
+		}
+		if err := tx.Commit(); err != nil {
+			t.Fatalf("Commit: %v", err)
+		}
+		if got := tx.ChecksumRowsWritten(); got != tc.want {
+			t.Errorf("transaction %d: ChecksumRowsWritten() = %d, want %d", i, got, tc.want)
+		}
+	}
+}
+
+func TestTransaction_ElapsedAndWriteBytes(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "timing.fdb")
+	setupMockSyscalls(false, false)
+	t.Cleanup(restoreRealSyscalls)
+	t.Setenv("SUDO_USER", MOCK_USER)
+	t.Setenv("SUDO_UID", MOCK_UID)
+	t.Setenv("SUDO_GID", MOCK_GID)
+	config := NewCreateConfig(path, confRowSize, confSkewMs)
+	config.SetChecksumInterval(MIN_CHECKSUM_INTERVAL)
+	if err := Create(config); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	db, err := NewFrozenDB(path, MODE_WRITE, FinderStrategySimple)
+	if err != nil {
+		t.Fatalf("NewFrozenDB: %v", err)
+	}
+	defer db.Close()
+
+	// The second transaction completes the first interval, so it also writes a
+	// checksum row; the third is rolled back
+	ts := 1000
+	for i, tc := range []struct {
+		rows     int
+		rollback bool
+		wantRows int64
+	}{{60, false, 60}, {40, false, 41}, {1, true, 1}} {
+		tx, err := db.BeginTx()
+		if err != nil {
+			t.Fatalf("BeginTx: %v", err)
+		}
+		if tx.WriteBytes() != 2 {
+			t.Errorf("transaction %d: WriteBytes() after Begin = %d, want 2", i, tx.WriteBytes())
+		}
+		for range tc.rows {
+			mustAdd(t, tx, uuidFromTS(ts), `{}`)
+			ts++
+		}
+		if tc.rollback {
+			err = tx.Rollback(0)
+		} else {
+			err = tx.Commit()
+		}
+		if err != nil {
+			t.Fatalf("transaction %d: %v", i, err)
+		}
+		if got, want := tx.WriteBytes(), tc.wantRows*int64(confRowSize); got != want {
+			t.Errorf("transaction %d: WriteBytes() = %d, want %d", i, got, want)
+		}
+		elapsed := tx.Elapsed()
+		if elapsed <= 0 {
+			t.Errorf("transaction %d: Elapsed() = %s, want > 0", i, elapsed)
+		}
+		time.Sleep(time.Millisecond)
+		if tx.Elapsed() != elapsed {
+			t.Errorf("transaction %d: Elapsed() changed after the transaction ended", i)
+		}
+	}
+}
+
+func TestTransaction_AddRowIfAbsent(t *testing.T) {
+	path := setupCreate(t, t.TempDir(), 5000)
+	committed, rolledBack, deleted := uuidFromTS(1000), uuidFromTS(2000), uuidFromTS(3000)
+	writeTx(t, path, false, func(tx *Transaction) error {
+		if err := tx.AddRow(committed, json.RawMessage(`{"v":1}`)); err != nil {
+			return err
+		}
+		return tx.AddRow(deleted, json.RawMessage(`{"v":1}`))
+	})
+	writeTx(t, path, true, func(tx *Transaction) error {
+		return tx.AddRow(rolledBack, json.RawMessage(`{"v":1}`))
+	})
+	writeTx(t, path, false, func(tx *Transaction) error { return tx.Delete(deleted) })
+
+	addIfAbsent := func(tx *Transaction, key uuid.UUID, want bool) {
+		t.Helper()
+		added, err := tx.AddRowIfAbsent(key, json.RawMessage(`{"v":2}`))
+		if err != nil {
+			t.Fatalf("AddRowIfAbsent(%s): %v", key, err)
+		}
+		if added != want {
+			t.Errorf("AddRowIfAbsent(%s) = %v, want %v", key, added, want)
+		}
+	}
+	writeTx(t, path, false, func(tx *Transaction) error {
+		addIfAbsent(tx, committed, false)
+		addIfAbsent(tx, rolledBack, true)
+		addIfAbsent(tx, rolledBack, false) // already added in this transaction
+		addIfAbsent(tx, deleted, true)
+		return nil
+	})
+
+	// Retrying finds the rows written after the rollback and the delete
+	writeTx(t, path, false, func(tx *Transaction) error {
+		addIfAbsent(tx, rolledBack, false)
+		addIfAbsent(tx, deleted, false)
+		return nil
+	})
+
+	db, err := NewFrozenDB(path, MODE_WRITE, FinderStrategySimple)
+	if err != nil {
+		t.Fatalf("NewFrozenDB: %v", err)
+	}
+	defer db.Close()
+	var value map[string]int
+	if err := db.GetLatest(committed, &value); err != nil || value["v"] != 1 {
+		t.Errorf("GetLatest(committed) = %v, %v; want v=1", value, err)
+	}
+	if err := db.GetLatest(deleted, &value); err != nil || value["v"] != 2 {
+		t.Errorf("GetLatest(deleted) = %v, %v; want v=2", value, err)
+	}
+
+	tx, err := db.BeginTx()
+	if err != nil {
+		t.Fatalf("BeginTx: %v", err)
+	}
+	var invalidInput *InvalidInputError
+	if _, err := tx.AddRowIfAbsent(uuidFromTS(4000), json.RawMessage(TOMBSTONE_VALUE)); !errors.As(err, &invalidInput) {
+		t.Errorf("tombstone value: expected InvalidInputError, got %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+	var invalidAction *InvalidActionError
+	if _, err := tx.AddRowIfAbsent(uuidFromTS(4000), json.RawMessage(`{}`)); !errors.As(err, &invalidAction) {
+		t.Errorf("after Commit: expected InvalidActionError, got %v", err)
+	}
+}
+
+// openRecoveredTx leaves a transaction open in path after writing keys, closing
+// the database without committing, and reopens it in write mode with opts. With
+// completeRows the trailing partial row is cut off, so the file ends with a
+// complete row of the open transaction.
+func openRecoveredTx(t *testing.T, path string, completeRows bool, opts []OpenOption, keys ...uuid.UUID) (*FrozenDB, *Transaction) {
+	t.Helper()
+	db, err := NewFrozenDB(path, MODE_WRITE, FinderStrategySimple)
+	if err != nil {
+		t.Fatalf("NewFrozenDB: %v", err)
+	}
+	tx, err := db.BeginTx()
+	if err != nil {
+		t.Fatalf("BeginTx: %v", err)
+	}
+	for _, key := range keys {
+		if err := tx.AddRow(key, json.RawMessage(`{"v":1}`)); err != nil {
+			t.Fatalf("AddRow: %v", err)
+		}
+	}
+	if err := db.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if completeRows {
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatalf("Stat: %v", err)
+		}
+		rows := (info.Size() - HEADER_SIZE) / confRowSize
+		if err := os.Truncate(path, HEADER_SIZE+rows*confRowSize); err != nil {
+			t.Fatalf("Truncate: %v", err)
+		}
+	}
+
+	db, err = NewFrozenDB(path, MODE_WRITE, FinderStrategySimple, opts...)
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+	recovered := db.GetActiveTx()
+	if recovered == nil {
+		t.Fatal("expected a recovered transaction")
+	}
+	return db, recovered
+}
+
+func TestTransaction_RecoveredReadsCommittedRows(t *testing.T) {
+	for _, completeRows := range []bool{false, true} {
+		t.Run(fmt.Sprintf("completeRows=%v", completeRows), func(t *testing.T) {
+			path := setupCreate(t, t.TempDir(), 5000)
+			committed := uuidFromTS(1000)
+			writeTx(t, path, false, func(tx *Transaction) error {
+				return tx.AddRow(committed, json.RawMessage(`{"v":0}`))
+			})
+			_, tx := openRecoveredTx(t, path, completeRows, nil, uuidFromTS(2000), uuidFromTS(3000))
+
+			var value map[string]int
+			if err := tx.Get(committed, &value); err != nil || value["v"] != 0 {
+				t.Errorf("Get committed key = %v, %v; want v=0", value, err)
+			}
+			if completeRows {
+				return // The recovered transaction has no partial row to append to
+			}
+			if added, err := tx.AddRowIfAbsent(committed, json.RawMessage(`{"v":2}`)); err != nil || added {
+				t.Errorf("AddRowIfAbsent committed key = %v, %v; want false", added, err)
+			}
+			if added, err := tx.AddRowIfAbsent(uuidFromTS(4000), json.RawMessage(`{"v":2}`)); err != nil || !added {
+				t.Errorf("AddRowIfAbsent new key = %v, %v; want true", added, err)
+			}
+		})
+	}
+}
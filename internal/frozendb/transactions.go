@@ -0,0 +1,103 @@
+package frozendb
+
+import (
+	"fmt"
+)
+
+// TransactionInfo describes one transaction in the database file, as listed by
+// FrozenDB.Transactions.
+type TransactionInfo struct {
+	StartIndex        int64      // Row index of the transaction's first row
+	EndIndex          int64      // Row index of the row that ended it (last row written if Outcome is TxOutcomeOpen)
+	EndControl        EndControl // end_control of the ending row (zero if Outcome is TxOutcomeOpen)
+	Outcome           TxOutcome  // How the transaction ended
+	RollbackSavepoint int        // Savepoint rolled back to (0 unless Outcome is TxOutcomeSavepointCommitted)
+	Empty             bool       // Transaction is a NullRow: committed with no data rows
+	Rows              int        // Data rows written, including rolled back and partial rows
+	Savepoints        int        // Savepoints created
+	CommittedRows     int        // Data rows left visible by the ending row
+}
+
+// Transactions lists every transaction in the database file in the order it was
+// written, in a single pass over the rows. Checksum rows are skipped. An empty
+// transaction appears as a single NullRow with Empty set. If the file ends inside
+// a transaction, the last entry has Outcome TxOutcomeOpen and no committed rows.
+//
+// Unlike Get, Transactions reports rolled back transactions as well as committed
+// ones, which makes it a transaction-level complement to RowAt for auditing.
+//
+// Returns:
+//   - []TransactionInfo: Transactions in file order (empty for a new database)
+//   - error: ReadError or CorruptDatabaseError (a row fails validation, or the
+//     transaction control bytes are out of sequence)
+//
+// Thread Safety: Safe for concurrent calls on the same FrozenDB instance
+func (db *FrozenDB) Transactions() ([]TransactionInfo, error) {
+	rowCount := db.RowCount()
+	txs := []TransactionInfo{}
+
+	var cur *TransactionInfo
+	var savepointRows []int // cur.Rows at each savepoint, for partial rollbacks
+	for index := int64(0); index < rowCount; index++ {
+		info, err := db.rowInfoAt(index)
+		if err != nil {
+			return nil, err
+		}
+		if info.Kind == RowKindChecksum {
+			continue
+		}
+		if info.TxStart && cur != nil {
+			return nil, NewCorruptDatabaseError(fmt.Sprintf("row %d starts a transaction before the transaction at row %d ended", index, cur.StartIndex), nil)
+		}
+		if !info.TxStart && cur == nil {
+			return nil, NewCorruptDatabaseError(fmt.Sprintf("row %d continues a transaction that was never started", index), nil)
+		}
+
+		if info.Kind == RowKindNull {
+			txs = append(txs, TransactionInfo{
+				StartIndex: index,
+				EndIndex:   index,
+				EndControl: info.EndControl,
+				Outcome:    TxOutcomeCommitted,
+				Empty:      true,
+			})
+			continue
+		}
+
+		if info.TxStart {
+			cur = &TransactionInfo{StartIndex: index, Outcome: TxOutcomeOpen}
+			savepointRows = savepointRows[:0]
+		}
+		cur.Rows++
+		cur.EndIndex = index
+		if info.Savepoint {
+			cur.Savepoints++
+			savepointRows = append(savepointRows, cur.Rows)
+		}
+		if !info.TxEnd {
+			continue
+		}
+
+		cur.EndControl = info.EndControl
+		switch {
+		case info.Commit:
+			cur.Outcome = TxOutcomeCommitted
+			cur.CommittedRows = cur.Rows
+		case info.RollbackSavepoint == 0:
+			cur.Outcome = TxOutcomeRolledBack
+		default:
+			if info.RollbackSavepoint > len(savepointRows) {
+				return nil, NewCorruptDatabaseError(fmt.Sprintf("row %d rolls back to savepoint %d but the transaction has %d", index, info.RollbackSavepoint, len(savepointRows)), nil)
+			}
+			cur.Outcome = TxOutcomeSavepointCommitted
+			cur.RollbackSavepoint = info.RollbackSavepoint
+			cur.CommittedRows = savepointRows[info.RollbackSavepoint-1]
+		}
+		txs = append(txs, *cur)
+		cur = nil
+	}
+	if cur != nil {
+		txs = append(txs, *cur)
+	}
+	return txs, nil
+}
@@ -0,0 +1,120 @@
+package frozendb
+
+import (
+	"testing"
+)
+
+func TestTransactions(t *testing.T) {
+	path := setupCreate(t, t.TempDir(), 0)
+	db, err := NewFrozenDB(path, MODE_WRITE, FinderStrategySimple)
+	if err != nil {
+		t.Fatalf("NewFrozenDB: %v", err)
+	}
+	defer db.Close()
+
+	if txs, err := db.Transactions(); err != nil || len(txs) != 0 {
+		t.Fatalf("Transactions() on new database = %v, %v; want none", txs, err)
+	}
+
+	begin := func() *Transaction {
+		t.Helper()
+		tx, err := db.BeginTx()
+		if err != nil {
+			t.Fatalf("BeginTx: %v", err)
+		}
+		return tx
+	}
+
+	// Rows 1-2: committed
+	tx := begin()
+	mustAdd(t, tx, uuidFromTS(1000), `{"n":1}`)
+	mustAdd(t, tx, uuidFromTS(1001), `{"n":2}`)
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+	// Rows 3-6: two savepoints, rolled back to the second
+	tx = begin()
+	mustAdd(t, tx, uuidFromTS(1002), `{"n":3}`)
+	if err := tx.Savepoint(); err != nil {
+		t.Fatalf("Savepoint: %v", err)
+	}
+	mustAdd(t, tx, uuidFromTS(1003), `{"n":4}`)
+	mustAdd(t, tx, uuidFromTS(1004), `{"n":5}`)
+	if err := tx.Savepoint(); err != nil {
+		t.Fatalf("Savepoint: %v", err)
+	}
+	mustAdd(t, tx, uuidFromTS(1005), `{"n":6}`)
+	if err := tx.Rollback(2); err != nil {
+		t.Fatalf("Rollback: %v", err)
+	}
+	// Row 7: fully rolled back
+	tx = begin()
+	mustAdd(t, tx, uuidFromTS(1006), `{"n":7}`)
+	if err := tx.Rollback(0); err != nil {
+		t.Fatalf("Rollback: %v", err)
+	}
+	// Row 8: empty transaction (NullRow)
+	tx = begin()
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+	// Rows 9-10: still in progress
+	tx = begin()
+	mustAdd(t, tx, uuidFromTS(1007), `{"n":8}`)
+	mustAdd(t, tx, uuidFromTS(1008), `{"n":9}`)
+
+	txs, err := db.Transactions()
+	if err != nil {
+		t.Fatalf("Transactions: %v", err)
+	}
+	want := []TransactionInfo{
+		{StartIndex: 1, EndIndex: 2, EndControl: TRANSACTION_COMMIT, Outcome: TxOutcomeCommitted, Rows: 2, CommittedRows: 2},
+		{StartIndex: 3, EndIndex: 6, EndControl: EndControl{'R', '2'}, Outcome: TxOutcomeSavepointCommitted, RollbackSavepoint: 2, Rows: 4, Savepoints: 2, CommittedRows: 3},
+		{StartIndex: 7, EndIndex: 7, EndControl: FULL_ROLLBACK, Outcome: TxOutcomeRolledBack, Rows: 1},
+		{StartIndex: 8, EndIndex: 8, EndControl: NULL_ROW_CONTROL, Outcome: TxOutcomeCommitted, Empty: true},
+		{StartIndex: 9, EndIndex: 10, Outcome: TxOutcomeOpen, Rows: 2},
+	}
+	if len(txs) != len(want) {
+		t.Fatalf("Transactions() returned %d entries, want %d: %+v", len(txs), len(want), txs)
+	}
+	for i := range want {
+		if txs[i] != want[i] {
+			t.Errorf("Transactions()[%d] = %+v, want %+v", i, txs[i], want[i])
+		}
+	}
+}
+
+func TestTransactions_SkipsChecksumRows(t *testing.T) {
+	path := setupCreate(t, t.TempDir(), 0)
+	db, err := NewFrozenDB(path, MODE_WRITE, FinderStrategySimple)
+	if err != nil {
+		t.Fatalf("NewFrozenDB: %v", err)
+	}
+	defer db.Close()
+
+	// 10001 data rows cross the checksum row at index 10001.
+	for batch := 0; batch < 101; batch++ {
+		tx, err := db.BeginTx()
+		if err != nil {
+			t.Fatalf("BeginTx: %v", err)
+		}
+		for i := 0; i < 100 && batch*100+i < 10001; i++ {
+			mustAdd(t, tx, uuidFromTS(1000+batch*100+i), `{}`)
+		}
+		if err := tx.Commit(); err != nil {
+			t.Fatalf("Commit: %v", err)
+		}
+	}
+
+	txs, err := db.Transactions()
+	if err != nil {
+		t.Fatalf("Transactions: %v", err)
+	}
+	if len(txs) != 101 {
+		t.Fatalf("Transactions() returned %d entries, want 101", len(txs))
+	}
+	last := txs[100]
+	if last.StartIndex != 10002 || last.EndIndex != 10002 || last.CommittedRows != 1 {
+		t.Errorf("last transaction = %+v, want row 10002 with 1 committed row", last)
+	}
+}
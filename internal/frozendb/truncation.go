@@ -0,0 +1,69 @@
+package frozendb
+
+import "fmt"
+
+// isPartialRowLength reports whether n is a length a writer leaves a row at while a
+// transaction is open: after start_control, after the payload and padding, or after
+// a savepoint marker.
+func isPartialRowLength(n, rowSize int) bool {
+	return n == 2 || n == rowSize-5 || n == rowSize-4
+}
+
+// parseTrailingPartialRow parses fragment, the bytes at the end of a file that does
+// not end on a row boundary, as the PartialDataRow at rowIndex. A fragment that is
+// not one is reported as a TruncatedFileError when it begins a row with a valid
+// start_control but stops at a length no writer pauses at, and as a
+// CorruptDatabaseError when its bytes are wrong.
+func parseTrailingPartialRow(fragment []byte, rowIndex int64, rowSize int) (*PartialDataRow, error) {
+	offset := int64(HEADER_SIZE) + rowIndex*int64(rowSize)
+	partialRow := &PartialDataRow{}
+	err := partialRow.UnmarshalText(fragment)
+	if err == nil {
+		partialRow.d.RowSize = rowSize
+		return partialRow, nil
+	}
+
+	if isTruncatedRow(fragment, rowSize) {
+		expected := offset + int64(rowSize)
+		actual := offset + int64(len(fragment))
+		return nil, NewTruncatedFileError(
+			fmt.Sprintf("file truncated within row %d at offset %d: file is %d bytes, row ends at %d", rowIndex, offset, actual, expected),
+			rowIndex, expected, actual,
+			NewCorruptDatabaseError(fmt.Sprintf("incomplete row at offset %d (%d of %d bytes)", offset, len(fragment), rowSize), err),
+		)
+	}
+	return nil, NewCorruptDatabaseError(fmt.Sprintf("invalid partial row at offset %d: %v", offset, err), err)
+}
+
+// isTruncatedRow reports whether fragment, which failed to parse as a PartialDataRow,
+// is the start of a row cut off at a length no writer pauses at.
+func isTruncatedRow(fragment []byte, rowSize int) bool {
+	if len(fragment) == 0 || fragment[0] != ROW_START || isPartialRowLength(len(fragment), rowSize) {
+		return false
+	}
+	if len(fragment) == 1 {
+		return true
+	}
+	var sc StartControl
+	return sc.UnmarshalText(fragment[1:2]) == nil
+}
+
+// checkRowComplete returns an error if the row at index extends past the end of the
+// file: the error from parseTrailingPartialRow if the trailing bytes are not a valid
+// PartialDataRow, or a ReadError if they are one that is still being written.
+func (db *FrozenDB) checkRowComplete(index int64) error {
+	rowSize := db.header.GetRowSize()
+	offset := int64(HEADER_SIZE) + index*int64(rowSize)
+	size := db.file.Size()
+	if offset >= size || offset+int64(rowSize) <= size {
+		return nil
+	}
+	fragment, err := db.file.Read(offset, int32(size-offset))
+	if err != nil {
+		return NewReadError(fmt.Sprintf("failed to read partial row at index %d", index), err)
+	}
+	if _, err := parseTrailingPartialRow(fragment, index, rowSize); err != nil {
+		return err
+	}
+	return NewReadError(fmt.Sprintf("row at index %d is a PartialDataRow still being written", index), nil)
+}
@@ -0,0 +1,113 @@
+package frozendb
+
+import (
+	"errors"
+	"os"
+	"testing"
+)
+
+// setupCommittedRows creates a database holding one committed transaction of n
+// rows and returns its path and size.
+func setupCommittedRows(t *testing.T, n int) (string, int64) {
+	t.Helper()
+	path := setupCreate(t, t.TempDir(), confSkewMs)
+	tx, db := openAndBegin(t, path)
+	for i := 0; i < n; i++ {
+		mustAdd(t, tx, uuidFromTS(1000+i), `{"v":1}`)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+	_ = db.Close()
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("Stat: %v", err)
+	}
+	return path, info.Size()
+}
+
+func TestTruncatedFile_OpenAndVerify(t *testing.T) {
+	path, size := setupCommittedRows(t, 3)
+	// Cut the last row within its key, where no writer ever pauses
+	cut := size - confRowSize + 20
+	if err := os.Truncate(path, cut); err != nil {
+		t.Fatalf("Truncate: %v", err)
+	}
+	lastIndex := (size-HEADER_SIZE)/confRowSize - 1
+
+	var truncated *TruncatedFileError
+	_, err := NewFrozenDB(path, MODE_READ, FinderStrategySimple)
+	if !errors.As(err, &truncated) {
+		t.Fatalf("NewFrozenDB: expected TruncatedFileError, got %v", err)
+	}
+	if truncated.RowIndex != lastIndex || truncated.ExpectedSize != size || truncated.ActualSize != cut {
+		t.Errorf("TruncatedFileError{RowIndex: %d, ExpectedSize: %d, ActualSize: %d}, want {%d, %d, %d}",
+			truncated.RowIndex, truncated.ExpectedSize, truncated.ActualSize, lastIndex, size, cut)
+	}
+	var corrupt *CorruptDatabaseError
+	if !errors.As(err, &corrupt) {
+		t.Errorf("TruncatedFileError should wrap CorruptDatabaseError, got %v", err)
+	}
+
+	report, err := Verify(path)
+	if !errors.As(err, &truncated) {
+		t.Fatalf("Verify: expected TruncatedFileError, got %v", err)
+	}
+	if report.ProblemCount != 1 {
+		t.Errorf("Verify ProblemCount = %d, want 1", report.ProblemCount)
+	}
+}
+
+func TestTruncatedFile_CorruptTailIsNotTruncation(t *testing.T) {
+	path, size := setupCommittedRows(t, 3)
+	// A fragment of a length a writer does produce, but with the wrong bytes
+	if err := os.Truncate(path, size-5); err != nil {
+		t.Fatalf("Truncate: %v", err)
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatalf("OpenFile: %v", err)
+	}
+	_, err = f.WriteAt([]byte{'X'}, size-confRowSize)
+	_ = f.Close()
+	if err != nil {
+		t.Fatalf("WriteAt: %v", err)
+	}
+
+	_, err = NewFrozenDB(path, MODE_READ, FinderStrategySimple)
+	var truncated *TruncatedFileError
+	var corrupt *CorruptDatabaseError
+	if errors.As(err, &truncated) || !errors.As(err, &corrupt) {
+		t.Errorf("expected CorruptDatabaseError that is not a TruncatedFileError, got %v", err)
+	}
+}
+
+func TestTruncatedFile_ReadPath(t *testing.T) {
+	path, size := setupCommittedRows(t, 3)
+	db, err := NewFrozenDB(path, MODE_READ, FinderStrategySimple)
+	if err != nil {
+		t.Fatalf("NewFrozenDB: %v", err)
+	}
+	defer db.Close()
+
+	// Bytes that begin a row and stop partway through its payload
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		t.Fatalf("OpenFile: %v", err)
+	}
+	_, err = f.Write([]byte{ROW_START, 'T', '{', '"'})
+	_ = f.Close()
+	if err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	db.refresh()
+
+	index := (size - HEADER_SIZE) / confRowSize
+	var truncated *TruncatedFileError
+	if _, err := db.readRowAtIndex(index); !errors.As(err, &truncated) {
+		t.Fatalf("readRowAtIndex: expected TruncatedFileError, got %v", err)
+	}
+	if truncated.RowIndex != index || truncated.ActualSize != size+4 {
+		t.Errorf("TruncatedFileError{RowIndex: %d, ActualSize: %d}, want {%d, %d}", truncated.RowIndex, truncated.ActualSize, index, size+4)
+	}
+}
@@ -3,6 +3,7 @@ package frozendb
 import (
 	"encoding/base64"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -39,6 +40,13 @@ func ExtractUUIDv7Timestamp(u uuid.UUID) int64 {
 		int64(u[3])<<16 | int64(u[4])<<8 | int64(u[5])
 }
 
+// TimestampOf returns the time encoded in a UUIDv7 key's 48-bit millisecond
+// timestamp, in UTC. The key is not validated; callers that need to reject
+// non-v7 keys should call ValidateUUIDv7 first.
+func TimestampOf(u uuid.UUID) time.Time {
+	return time.UnixMilli(ExtractUUIDv7Timestamp(u)).UTC()
+}
+
 // NewUUIDv7 creates new UUIDv7 with current timestamp.
 // Returns error if UUID generation fails.
 func NewUUIDv7() (uuid.UUID, error) {
@@ -184,7 +192,7 @@ func DecodeUUIDBase64(data []byte) (uuid.UUID, error) {
 	}
 
 	// Decode Base64
-	decoded, err := base64.StdEncoding.DecodeString(string(data))
+	decoded, err := base64.StdEncoding.Strict().DecodeString(string(data))
 	if err != nil {
 		return uuid.Nil, NewInvalidInputError("invalid Base64 encoding for UUID", err)
 	}
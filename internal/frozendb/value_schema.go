@@ -0,0 +1,862 @@
+package frozendb
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// valueSchema is a compiled JSON Schema (draft-07) used to validate row values
+// at write time.
+//
+// Supported keywords:
+//   - Generic: type, enum, const, allOf, anyOf, oneOf, not, if/then/else
+//   - Numbers: minimum, maximum, exclusiveMinimum, exclusiveMaximum, multipleOf
+//   - Strings: minLength, maxLength, pattern
+//   - Arrays: items, additionalItems, minItems, maxItems, uniqueItems, contains
+//   - Objects: properties, patternProperties, additionalProperties, required,
+//     minProperties, maxProperties, propertyNames, dependencies
+//   - References: $ref to a JSON Pointer within the same schema ("#", "#/definitions/x")
+//
+// Annotation keywords (title, description, format, default, ...) are accepted and
+// ignored. Remote references are rejected when the schema is compiled, so a schema
+// is never silently validated more loosely than written.
+type valueSchema struct {
+	alwaysFalse bool // Boolean schema false: no value is valid
+
+	ref     string       // Local reference, resolved after compilation
+	refTo   *valueSchema // Resolved reference target
+	types   []string
+	enum    []any
+	constV  any
+	hasCons bool
+
+	minimum, maximum                   *float64
+	exclusiveMinimum, exclusiveMaximum *float64
+	multipleOf                         *float64
+
+	minLength, maxLength *int
+	pattern              *regexp.Regexp
+
+	items           *valueSchema   // Schema for every item
+	itemsTuple      []*valueSchema // Positional item schemas
+	additionalItems *valueSchema
+	contains        *valueSchema
+	minItems        *int
+	maxItems        *int
+	uniqueItems     bool
+
+	properties           map[string]*valueSchema
+	patternProperties    map[*regexp.Regexp]*valueSchema
+	additionalProperties *valueSchema
+	required             []string
+	minProperties        *int
+	maxProperties        *int
+	propertyNames        *valueSchema
+	dependencySchemas    map[string]*valueSchema
+	dependencyRequired   map[string][]string
+
+	allOf, anyOf, oneOf []*valueSchema
+	not                 *valueSchema
+	ifS, thenS, elseS   *valueSchema
+}
+
+// compileValueSchema parses and compiles a JSON Schema document.
+// Returns InvalidInputError if the schema is not valid JSON, uses an unsupported
+// reference, or has a keyword with the wrong shape.
+func compileValueSchema(raw []byte) (*valueSchema, error) {
+	doc, err := decodeJSONUseNumber(raw)
+	if err != nil {
+		return nil, NewInvalidInputError("schema is not valid JSON", err)
+	}
+	c := &schemaCompiler{root: doc, byPointer: make(map[string]*valueSchema)}
+	schema, err := c.compile(doc, "#")
+	if err != nil {
+		return nil, err
+	}
+	if err := c.resolveRefs(); err != nil {
+		return nil, err
+	}
+	return schema, nil
+}
+
+// schemaCompiler holds the state for compiling one schema document.
+type schemaCompiler struct {
+	root      any
+	byPointer map[string]*valueSchema // Compiled schemas by JSON Pointer fragment
+	refs      []*valueSchema          // Schemas whose $ref still needs resolving
+}
+
+func (c *schemaCompiler) compile(node any, pointer string) (*valueSchema, error) {
+	if existing, ok := c.byPointer[pointer]; ok {
+		return existing, nil
+	}
+	s := &valueSchema{}
+	c.byPointer[pointer] = s
+
+	switch n := node.(type) {
+	case bool:
+		s.alwaysFalse = !n
+		return s, nil
+	case map[string]any:
+		if err := c.compileObject(s, n, pointer); err != nil {
+			return nil, err
+		}
+		return s, nil
+	default:
+		return nil, schemaError(pointer, "schema must be an object or boolean")
+	}
+}
+
+func (c *schemaCompiler) compileObject(s *valueSchema, n map[string]any, pointer string) error {
+	var err error
+	sub := func(key string) (*valueSchema, error) {
+		v, ok := n[key]
+		if !ok {
+			return nil, nil
+		}
+		return c.compile(v, pointer+"/"+escapePointer(key))
+	}
+	subList := func(key string) ([]*valueSchema, error) {
+		v, ok := n[key]
+		if !ok {
+			return nil, nil
+		}
+		list, ok := v.([]any)
+		if !ok || len(list) == 0 {
+			return nil, schemaError(pointer, key+" must be a non-empty array")
+		}
+		out := make([]*valueSchema, len(list))
+		for i, item := range list {
+			if out[i], err = c.compile(item, fmt.Sprintf("%s/%s/%d", pointer, key, i)); err != nil {
+				return nil, err
+			}
+		}
+		return out, nil
+	}
+	subMap := func(key string) (map[string]*valueSchema, error) {
+		v, ok := n[key]
+		if !ok {
+			return nil, nil
+		}
+		m, ok := v.(map[string]any)
+		if !ok {
+			return nil, schemaError(pointer, key+" must be an object")
+		}
+		out := make(map[string]*valueSchema, len(m))
+		for name, item := range m {
+			if out[name], err = c.compile(item, pointer+"/"+key+"/"+escapePointer(name)); err != nil {
+				return nil, err
+			}
+		}
+		return out, nil
+	}
+
+	if v, ok := n["$ref"]; ok {
+		ref, isString := v.(string)
+		if !isString || (ref != "#" && !strings.HasPrefix(ref, "#/")) {
+			return schemaError(pointer, fmt.Sprintf("unsupported $ref %v: only references within the schema are supported", v))
+		}
+		// Per draft-07, siblings of $ref are ignored
+		s.ref = ref
+		c.refs = append(c.refs, s)
+		return nil
+	}
+
+	// Compile definitions so references into them resolve to the same schemas
+	if _, err := subMap("definitions"); err != nil {
+		return err
+	}
+
+	if v, ok := n["type"]; ok {
+		switch t := v.(type) {
+		case string:
+			s.types = []string{t}
+		case []any:
+			for _, item := range t {
+				name, ok := item.(string)
+				if !ok {
+					return schemaError(pointer, "type must be a string or array of strings")
+				}
+				s.types = append(s.types, name)
+			}
+		default:
+			return schemaError(pointer, "type must be a string or array of strings")
+		}
+		for _, t := range s.types {
+			switch t {
+			case "null", "boolean", "object", "array", "number", "integer", "string":
+			default:
+				return schemaError(pointer, fmt.Sprintf("unknown type %q", t))
+			}
+		}
+	}
+	if v, ok := n["enum"]; ok {
+		list, isList := v.([]any)
+		if !isList {
+			return schemaError(pointer, "enum must be an array")
+		}
+		s.enum = list
+	}
+	if v, ok := n["const"]; ok {
+		s.constV, s.hasCons = v, true
+	}
+
+	numbers := []struct {
+		key string
+		dst **float64
+	}{
+		{"minimum", &s.minimum}, {"maximum", &s.maximum},
+		{"exclusiveMinimum", &s.exclusiveMinimum}, {"exclusiveMaximum", &s.exclusiveMaximum},
+		{"multipleOf", &s.multipleOf},
+	}
+	for _, num := range numbers {
+		if v, ok := n[num.key]; ok {
+			f, isNum := jsonNumberValue(v)
+			if !isNum {
+				return schemaError(pointer, num.key+" must be a number")
+			}
+			*num.dst = &f
+		}
+	}
+	if s.multipleOf != nil && *s.multipleOf <= 0 {
+		return schemaError(pointer, "multipleOf must be greater than 0")
+	}
+
+	counts := []struct {
+		key string
+		dst **int
+	}{
+		{"minLength", &s.minLength}, {"maxLength", &s.maxLength},
+		{"minItems", &s.minItems}, {"maxItems", &s.maxItems},
+		{"minProperties", &s.minProperties}, {"maxProperties", &s.maxProperties},
+	}
+	for _, cnt := range counts {
+		if v, ok := n[cnt.key]; ok {
+			f, isNum := jsonNumberValue(v)
+			if !isNum || f < 0 || f != math.Trunc(f) {
+				return schemaError(pointer, cnt.key+" must be a non-negative integer")
+			}
+			i := int(f)
+			*cnt.dst = &i
+		}
+	}
+
+	if v, ok := n["pattern"]; ok {
+		if s.pattern, err = compileSchemaPattern(v, pointer); err != nil {
+			return err
+		}
+	}
+	if v, ok := n["uniqueItems"]; ok {
+		b, isBool := v.(bool)
+		if !isBool {
+			return schemaError(pointer, "uniqueItems must be a boolean")
+		}
+		s.uniqueItems = b
+	}
+
+	if v, ok := n["items"]; ok {
+		if _, isList := v.([]any); isList {
+			if s.itemsTuple, err = subList("items"); err != nil {
+				return err
+			}
+		} else if s.items, err = sub("items"); err != nil {
+			return err
+		}
+	}
+	if s.additionalItems, err = sub("additionalItems"); err != nil {
+		return err
+	}
+	if s.contains, err = sub("contains"); err != nil {
+		return err
+	}
+
+	if s.properties, err = subMap("properties"); err != nil {
+		return err
+	}
+	if v, ok := n["patternProperties"]; ok {
+		m, isMap := v.(map[string]any)
+		if !isMap {
+			return schemaError(pointer, "patternProperties must be an object")
+		}
+		s.patternProperties = make(map[*regexp.Regexp]*valueSchema, len(m))
+		for expr, item := range m {
+			re, err := compileSchemaPattern(expr, pointer)
+			if err != nil {
+				return err
+			}
+			if s.patternProperties[re], err = c.compile(item, pointer+"/patternProperties/"+escapePointer(expr)); err != nil {
+				return err
+			}
+		}
+	}
+	if s.additionalProperties, err = sub("additionalProperties"); err != nil {
+		return err
+	}
+	if v, ok := n["required"]; ok {
+		if s.required, err = schemaStringList(v, pointer, "required"); err != nil {
+			return err
+		}
+	}
+	if s.propertyNames, err = sub("propertyNames"); err != nil {
+		return err
+	}
+	if v, ok := n["dependencies"]; ok {
+		m, isMap := v.(map[string]any)
+		if !isMap {
+			return schemaError(pointer, "dependencies must be an object")
+		}
+		for name, dep := range m {
+			if list, isList := dep.([]any); isList {
+				if s.dependencyRequired == nil {
+					s.dependencyRequired = make(map[string][]string)
+				}
+				if s.dependencyRequired[name], err = schemaStringList(list, pointer, "dependencies"); err != nil {
+					return err
+				}
+				continue
+			}
+			if s.dependencySchemas == nil {
+				s.dependencySchemas = make(map[string]*valueSchema)
+			}
+			if s.dependencySchemas[name], err = c.compile(dep, pointer+"/dependencies/"+escapePointer(name)); err != nil {
+				return err
+			}
+		}
+	}
+
+	if s.allOf, err = subList("allOf"); err != nil {
+		return err
+	}
+	if s.anyOf, err = subList("anyOf"); err != nil {
+		return err
+	}
+	if s.oneOf, err = subList("oneOf"); err != nil {
+		return err
+	}
+	if s.not, err = sub("not"); err != nil {
+		return err
+	}
+	if s.ifS, err = sub("if"); err != nil {
+		return err
+	}
+	if s.thenS, err = sub("then"); err != nil {
+		return err
+	}
+	if s.elseS, err = sub("else"); err != nil {
+		return err
+	}
+	return nil
+}
+
+// resolveRefs links every $ref to its target, compiling targets that are not
+// reachable through a schema keyword (for example under a custom container).
+func (c *schemaCompiler) resolveRefs() error {
+	for i := 0; i < len(c.refs); i++ {
+		s := c.refs[i]
+		if target, ok := c.byPointer[s.ref]; ok {
+			s.refTo = target
+			continue
+		}
+		node, err := resolvePointer(c.root, strings.TrimPrefix(s.ref, "#"))
+		if err != nil {
+			return schemaError(s.ref, err.Error())
+		}
+		if s.refTo, err = c.compile(node, s.ref); err != nil {
+			return err
+		}
+	}
+	// A reference that leads back to itself without descending into the value
+	// would make validation recurse forever. Every such cycle passes through a
+	// $ref, so searching from each one finds them all.
+	visited := make(map[*valueSchema]bool) // false while on the search path
+	var cyclic func(s *valueSchema) bool
+	cyclic = func(s *valueSchema) bool {
+		if done, seen := visited[s]; seen {
+			return !done
+		}
+		visited[s] = false
+		for _, sub := range s.inPlace() {
+			if sub != nil && cyclic(sub) {
+				return true
+			}
+		}
+		visited[s] = true
+		return false
+	}
+	for _, s := range c.refs {
+		if cyclic(s) {
+			return schemaError(s.ref, "circular $ref")
+		}
+	}
+	return nil
+}
+
+// inPlace returns the subschemas applied to the same value as s, rather than to
+// one of its items or properties.
+func (s *valueSchema) inPlace() []*valueSchema {
+	subs := []*valueSchema{s.refTo, s.not, s.ifS, s.thenS, s.elseS}
+	subs = append(subs, s.allOf...)
+	subs = append(subs, s.anyOf...)
+	subs = append(subs, s.oneOf...)
+	for _, dep := range s.dependencySchemas {
+		subs = append(subs, dep)
+	}
+	return subs
+}
+
+// validate checks value against the schema and returns InvalidDataError naming
+// the JSON Pointer of the first violation.
+func (s *valueSchema) validate(value json.RawMessage) error {
+	doc, err := decodeJSONUseNumber(value)
+	if err != nil {
+		return NewInvalidDataError("value is not valid JSON", err)
+	}
+	if path, reason, ok := s.check(doc, ""); !ok {
+		if path == "" {
+			path = "/"
+		}
+		return NewInvalidDataError(fmt.Sprintf("value at %s violates schema: %s", path, reason), nil)
+	}
+	return nil
+}
+
+// check reports whether v satisfies the schema. On failure it returns the JSON
+// Pointer of the offending value and a short reason.
+func (s *valueSchema) check(v any, path string) (string, string, bool) {
+	if s.alwaysFalse {
+		return path, "no value is allowed", false
+	}
+	if s.refTo != nil {
+		return s.refTo.check(v, path)
+	}
+
+	if len(s.types) > 0 && !matchesAnyType(v, s.types) {
+		return path, fmt.Sprintf("expected type %s, got %s", strings.Join(s.types, " or "), jsonTypeName(v)), false
+	}
+	if s.enum != nil {
+		found := false
+		for _, e := range s.enum {
+			if jsonEqual(v, e) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return path, "value is not one of the enumerated values", false
+		}
+	}
+	if s.hasCons && !jsonEqual(v, s.constV) {
+		return path, "value does not equal const", false
+	}
+
+	switch val := v.(type) {
+	case json.Number:
+		if p, r, ok := s.checkNumber(val, path); !ok {
+			return p, r, false
+		}
+	case string:
+		if p, r, ok := s.checkString(val, path); !ok {
+			return p, r, false
+		}
+	case []any:
+		if p, r, ok := s.checkArray(val, path); !ok {
+			return p, r, false
+		}
+	case map[string]any:
+		if p, r, ok := s.checkObject(val, path); !ok {
+			return p, r, false
+		}
+	}
+
+	for _, sub := range s.allOf {
+		if p, r, ok := sub.check(v, path); !ok {
+			return p, r, false
+		}
+	}
+	if s.anyOf != nil {
+		matched := false
+		for _, sub := range s.anyOf {
+			if _, _, ok := sub.check(v, path); ok {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return path, "value does not match any schema in anyOf", false
+		}
+	}
+	if s.oneOf != nil {
+		matches := 0
+		for _, sub := range s.oneOf {
+			if _, _, ok := sub.check(v, path); ok {
+				matches++
+			}
+		}
+		if matches != 1 {
+			return path, fmt.Sprintf("value matches %d schemas in oneOf, expected exactly 1", matches), false
+		}
+	}
+	if s.not != nil {
+		if _, _, ok := s.not.check(v, path); ok {
+			return path, "value must not match the schema in not", false
+		}
+	}
+	if s.ifS != nil {
+		if _, _, ok := s.ifS.check(v, path); ok {
+			if s.thenS != nil {
+				if p, r, ok := s.thenS.check(v, path); !ok {
+					return p, r, false
+				}
+			}
+		} else if s.elseS != nil {
+			if p, r, ok := s.elseS.check(v, path); !ok {
+				return p, r, false
+			}
+		}
+	}
+	return "", "", true
+}
+
+func (s *valueSchema) checkNumber(n json.Number, path string) (string, string, bool) {
+	f, err := n.Float64()
+	if err != nil {
+		return path, "number out of range", false
+	}
+	if s.minimum != nil && f < *s.minimum {
+		return path, fmt.Sprintf("must be >= %v", *s.minimum), false
+	}
+	if s.maximum != nil && f > *s.maximum {
+		return path, fmt.Sprintf("must be <= %v", *s.maximum), false
+	}
+	if s.exclusiveMinimum != nil && f <= *s.exclusiveMinimum {
+		return path, fmt.Sprintf("must be > %v", *s.exclusiveMinimum), false
+	}
+	if s.exclusiveMaximum != nil && f >= *s.exclusiveMaximum {
+		return path, fmt.Sprintf("must be < %v", *s.exclusiveMaximum), false
+	}
+	if s.multipleOf != nil {
+		q := f / *s.multipleOf
+		if math.Abs(q-math.Round(q)) > 1e-9 {
+			return path, fmt.Sprintf("must be a multiple of %v", *s.multipleOf), false
+		}
+	}
+	return "", "", true
+}
+
+func (s *valueSchema) checkString(str string, path string) (string, string, bool) {
+	length := utf8.RuneCountInString(str)
+	if s.minLength != nil && length < *s.minLength {
+		return path, fmt.Sprintf("length must be >= %d", *s.minLength), false
+	}
+	if s.maxLength != nil && length > *s.maxLength {
+		return path, fmt.Sprintf("length must be <= %d", *s.maxLength), false
+	}
+	if s.pattern != nil && !s.pattern.MatchString(str) {
+		return path, fmt.Sprintf("must match pattern %q", s.pattern.String()), false
+	}
+	return "", "", true
+}
+
+func (s *valueSchema) checkArray(arr []any, path string) (string, string, bool) {
+	if s.minItems != nil && len(arr) < *s.minItems {
+		return path, fmt.Sprintf("must have at least %d items", *s.minItems), false
+	}
+	if s.maxItems != nil && len(arr) > *s.maxItems {
+		return path, fmt.Sprintf("must have at most %d items", *s.maxItems), false
+	}
+	for i, item := range arr {
+		itemPath := path + "/" + strconv.Itoa(i)
+		var sub *valueSchema
+		switch {
+		case s.items != nil:
+			sub = s.items
+		case s.itemsTuple != nil && i < len(s.itemsTuple):
+			sub = s.itemsTuple[i]
+		case s.itemsTuple != nil:
+			sub = s.additionalItems
+		}
+		if sub != nil {
+			if p, r, ok := sub.check(item, itemPath); !ok {
+				return p, r, false
+			}
+		}
+	}
+	if s.uniqueItems {
+		for i := range arr {
+			for j := i + 1; j < len(arr); j++ {
+				if jsonEqual(arr[i], arr[j]) {
+					return path, fmt.Sprintf("items %d and %d are equal", i, j), false
+				}
+			}
+		}
+	}
+	if s.contains != nil {
+		found := false
+		for i, item := range arr {
+			if _, _, ok := s.contains.check(item, path+"/"+strconv.Itoa(i)); ok {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return path, "no item matches the schema in contains", false
+		}
+	}
+	return "", "", true
+}
+
+func (s *valueSchema) checkObject(obj map[string]any, path string) (string, string, bool) {
+	if s.minProperties != nil && len(obj) < *s.minProperties {
+		return path, fmt.Sprintf("must have at least %d properties", *s.minProperties), false
+	}
+	if s.maxProperties != nil && len(obj) > *s.maxProperties {
+		return path, fmt.Sprintf("must have at most %d properties", *s.maxProperties), false
+	}
+	for _, name := range s.required {
+		if _, ok := obj[name]; !ok {
+			return path, fmt.Sprintf("missing required property %q", name), false
+		}
+	}
+
+	// Visit properties in sorted order so the reported violation is deterministic
+	names := make([]string, 0, len(obj))
+	for name := range obj {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		value := obj[name]
+		propPath := path + "/" + escapePointer(name)
+		if s.propertyNames != nil {
+			if _, r, ok := s.propertyNames.check(name, propPath); !ok {
+				return propPath, "invalid property name: " + r, false
+			}
+		}
+
+		matched := false
+		if sub, ok := s.properties[name]; ok {
+			matched = true
+			if p, r, ok := sub.check(value, propPath); !ok {
+				return p, r, false
+			}
+		}
+		for re, sub := range s.patternProperties {
+			if !re.MatchString(name) {
+				continue
+			}
+			matched = true
+			if p, r, ok := sub.check(value, propPath); !ok {
+				return p, r, false
+			}
+		}
+		if !matched && s.additionalProperties != nil {
+			if s.additionalProperties.alwaysFalse {
+				return propPath, "additional property is not allowed", false
+			}
+			if p, r, ok := s.additionalProperties.check(value, propPath); !ok {
+				return p, r, false
+			}
+		}
+
+		for _, dep := range s.dependencyRequired[name] {
+			if _, ok := obj[dep]; !ok {
+				return path, fmt.Sprintf("property %q requires property %q", name, dep), false
+			}
+		}
+		if sub, ok := s.dependencySchemas[name]; ok {
+			if p, r, ok := sub.check(obj, path); !ok {
+				return p, r, false
+			}
+		}
+	}
+	return "", "", true
+}
+
+// decodeJSONUseNumber decodes a single JSON document, keeping numbers as
+// json.Number so integers are checked exactly.
+func decodeJSONUseNumber(data []byte) (any, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	if dec.More() {
+		return nil, fmt.Errorf("unexpected data after JSON value")
+	}
+	return v, nil
+}
+
+func matchesAnyType(v any, types []string) bool {
+	for _, t := range types {
+		switch t {
+		case "null":
+			if v == nil {
+				return true
+			}
+		case "boolean":
+			if _, ok := v.(bool); ok {
+				return true
+			}
+		case "object":
+			if _, ok := v.(map[string]any); ok {
+				return true
+			}
+		case "array":
+			if _, ok := v.([]any); ok {
+				return true
+			}
+		case "string":
+			if _, ok := v.(string); ok {
+				return true
+			}
+		case "number":
+			if _, ok := v.(json.Number); ok {
+				return true
+			}
+		case "integer":
+			if n, ok := v.(json.Number); ok {
+				if f, err := n.Float64(); err == nil && f == math.Trunc(f) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+func jsonTypeName(v any) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case map[string]any:
+		return "object"
+	case []any:
+		return "array"
+	case string:
+		return "string"
+	case json.Number:
+		return "number"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+// jsonEqual compares two decoded JSON values, treating numbers by value.
+func jsonEqual(a, b any) bool {
+	switch av := a.(type) {
+	case json.Number:
+		bv, ok := b.(json.Number)
+		if !ok {
+			return false
+		}
+		af, aErr := av.Float64()
+		bf, bErr := bv.Float64()
+		return aErr == nil && bErr == nil && af == bf
+	case []any:
+		bv, ok := b.([]any)
+		if !ok || len(av) != len(bv) {
+			return false
+		}
+		for i := range av {
+			if !jsonEqual(av[i], bv[i]) {
+				return false
+			}
+		}
+		return true
+	case map[string]any:
+		bv, ok := b.(map[string]any)
+		if !ok || len(av) != len(bv) {
+			return false
+		}
+		for k, v := range av {
+			other, ok := bv[k]
+			if !ok || !jsonEqual(v, other) {
+				return false
+			}
+		}
+		return true
+	default:
+		return a == b
+	}
+}
+
+func jsonNumberValue(v any) (float64, bool) {
+	n, ok := v.(json.Number)
+	if !ok {
+		return 0, false
+	}
+	f, err := n.Float64()
+	return f, err == nil
+}
+
+func compileSchemaPattern(v any, pointer string) (*regexp.Regexp, error) {
+	expr, ok := v.(string)
+	if !ok {
+		return nil, schemaError(pointer, "pattern must be a string")
+	}
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, NewInvalidInputError(fmt.Sprintf("schema at %s has an invalid pattern %q", pointer, expr), err)
+	}
+	return re, nil
+}
+
+func schemaStringList(v any, pointer, key string) ([]string, error) {
+	list, ok := v.([]any)
+	if !ok {
+		return nil, schemaError(pointer, key+" must be an array of strings")
+	}
+	out := make([]string, len(list))
+	for i, item := range list {
+		if out[i], ok = item.(string); !ok {
+			return nil, schemaError(pointer, key+" must be an array of strings")
+		}
+	}
+	return out, nil
+}
+
+// resolvePointer follows a JSON Pointer (RFC 6901) within a decoded document.
+func resolvePointer(doc any, pointer string) (any, error) {
+	if pointer == "" {
+		return doc, nil
+	}
+	node := doc
+	for _, token := range strings.Split(strings.TrimPrefix(pointer, "/"), "/") {
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+		switch n := node.(type) {
+		case map[string]any:
+			next, ok := n[token]
+			if !ok {
+				return nil, fmt.Errorf("reference target %q not found", pointer)
+			}
+			node = next
+		case []any:
+			i, err := strconv.Atoi(token)
+			if err != nil || i < 0 || i >= len(n) {
+				return nil, fmt.Errorf("reference target %q not found", pointer)
+			}
+			node = n[i]
+		default:
+			return nil, fmt.Errorf("reference target %q not found", pointer)
+		}
+	}
+	return node, nil
+}
+
+func escapePointer(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+}
+
+func schemaError(pointer, reason string) error {
+	return NewInvalidInputError(fmt.Sprintf("invalid schema at %s: %s", pointer, reason), nil)
+}
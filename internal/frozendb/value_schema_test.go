@@ -0,0 +1,173 @@
+package frozendb
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestValueSchema_Validate(t *testing.T) {
+	schema := `{
+		"$schema": "http://json-schema.org/draft-07/schema#",
+		"type": "object",
+		"required": ["name", "age"],
+		"properties": {
+			"name": {"type": "string", "minLength": 1, "pattern": "^[a-z]+$"},
+			"age": {"type": "integer", "minimum": 0, "exclusiveMaximum": 150},
+			"tags": {"type": "array", "items": {"type": "string"}, "uniqueItems": true, "maxItems": 3},
+			"kind": {"enum": ["a", "b"]},
+			"owner": {"$ref": "#/definitions/person"}
+		},
+		"additionalProperties": false,
+		"definitions": {
+			"person": {"type": "object", "required": ["id"], "properties": {"id": {"type": "integer"}}}
+		}
+	}`
+	s, err := compileValueSchema([]byte(schema))
+	if err != nil {
+		t.Fatalf("compileValueSchema: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		value    string
+		wantPath string // empty when the value is valid
+	}{
+		{name: "valid", value: `{"name":"ann","age":30,"tags":["x","y"],"kind":"a","owner":{"id":1}}`},
+		{name: "integer written as float", value: `{"name":"ann","age":30.0}`},
+		{name: "missing required", value: `{"name":"ann"}`, wantPath: "/"},
+		{name: "wrong type", value: `{"name":"ann","age":"30"}`, wantPath: "/age"},
+		{name: "not an integer", value: `{"name":"ann","age":30.5}`, wantPath: "/age"},
+		{name: "exclusive maximum", value: `{"name":"ann","age":150}`, wantPath: "/age"},
+		{name: "pattern", value: `{"name":"Ann","age":1}`, wantPath: "/name"},
+		{name: "item type", value: `{"name":"ann","age":1,"tags":["x",2]}`, wantPath: "/tags/1"},
+		{name: "unique items", value: `{"name":"ann","age":1,"tags":["x","x"]}`, wantPath: "/tags"},
+		{name: "enum", value: `{"name":"ann","age":1,"kind":"c"}`, wantPath: "/kind"},
+		{name: "additional property", value: `{"name":"ann","age":1,"extra":true}`, wantPath: "/extra"},
+		{name: "ref", value: `{"name":"ann","age":1,"owner":{"id":"x"}}`, wantPath: "/owner/id"},
+		{name: "not an object", value: `[1,2]`, wantPath: "/"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := s.validate(json.RawMessage(tt.value))
+			if tt.wantPath == "" {
+				if err != nil {
+					t.Fatalf("expected valid, got %v", err)
+				}
+				return
+			}
+			var invalidData *InvalidDataError
+			if !errors.As(err, &invalidData) {
+				t.Fatalf("expected InvalidDataError, got %v", err)
+			}
+			if !strings.Contains(err.Error(), "value at "+tt.wantPath+" ") {
+				t.Errorf("expected error for path %s, got %v", tt.wantPath, err)
+			}
+		})
+	}
+}
+
+func TestValueSchema_Combinators(t *testing.T) {
+	s, err := compileValueSchema([]byte(`{
+		"oneOf": [{"type": "string"}, {"type": "number"}],
+		"not": {"const": "forbidden"}
+	}`))
+	if err != nil {
+		t.Fatalf("compileValueSchema: %v", err)
+	}
+	for _, valid := range []string{`"ok"`, `12`} {
+		if err := s.validate(json.RawMessage(valid)); err != nil {
+			t.Errorf("%s: expected valid, got %v", valid, err)
+		}
+	}
+	for _, invalid := range []string{`"forbidden"`, `true`, `null`} {
+		if err := s.validate(json.RawMessage(invalid)); err == nil {
+			t.Errorf("%s: expected violation", invalid)
+		}
+	}
+}
+
+func TestCompileValueSchema_Rejects(t *testing.T) {
+	schemas := map[string]string{
+		"invalid JSON":   `{"type":`,
+		"not an object":  `"string"`,
+		"remote ref":     `{"$ref": "http://example.com/schema.json"}`,
+		"missing ref":    `{"$ref": "#/definitions/nope"}`,
+		"circular ref":   `{"$ref": "#"}`,
+		"ref in allOf":   `{"allOf": [{"$ref": "#"}]}`,
+		"ref via not":    `{"definitions": {"a": {"not": {"$ref": "#/definitions/b"}}, "b": {"anyOf": [{"$ref": "#/definitions/a"}]}}, "$ref": "#/definitions/a"}`,
+		"unknown type":   `{"type": "date"}`,
+		"bad pattern":    `{"pattern": "("}`,
+		"bad minLength":  `{"minLength": -1}`,
+		"bad multipleOf": `{"multipleOf": 0}`,
+	}
+	for name, schema := range schemas {
+		t.Run(name, func(t *testing.T) {
+			var invalidInput *InvalidInputError
+			if _, err := compileValueSchema([]byte(schema)); !errors.As(err, &invalidInput) {
+				t.Fatalf("expected InvalidInputError, got %v", err)
+			}
+		})
+	}
+}
+
+func TestCompileValueSchema_RecursiveRef(t *testing.T) {
+	// A reference back to the root through a property descends into the value
+	s, err := compileValueSchema([]byte(`{"type": "object", "properties": {"child": {"allOf": [{"$ref": "#"}]}}}`))
+	if err != nil {
+		t.Fatalf("compileValueSchema: %v", err)
+	}
+	if err := s.validate(json.RawMessage(`{"child": {"child": {}}}`)); err != nil {
+		t.Errorf("expected valid, got %v", err)
+	}
+	if err := s.validate(json.RawMessage(`{"child": {"child": 1}}`)); err == nil {
+		t.Error("expected violation for a non-object grandchild")
+	}
+}
+
+func TestSetValueSchema_AddRow(t *testing.T) {
+	path := setupCreate(t, t.TempDir(), 0)
+	db, err := NewFrozenDB(path, MODE_WRITE, FinderStrategySimple)
+	if err != nil {
+		t.Fatalf("NewFrozenDB: %v", err)
+	}
+	defer db.Close()
+
+	if err := db.SetValueSchema([]byte(`{"type":"object","required":["n"]}`)); err != nil {
+		t.Fatalf("SetValueSchema: %v", err)
+	}
+
+	tx, err := db.BeginTx()
+	if err != nil {
+		t.Fatalf("BeginTx: %v", err)
+	}
+	var invalidData *InvalidDataError
+	if err := tx.AddRow(uuidFromTS(1000), json.RawMessage(`{"m":1}`)); !errors.As(err, &invalidData) {
+		t.Fatalf("expected InvalidDataError, got %v", err)
+	}
+	// The rejected row leaves the transaction usable
+	mustAdd(t, tx, uuidFromTS(1000), `{"n":1}`)
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+
+	// Removing the schema disables validation for later transactions
+	if err := db.SetValueSchema(nil); err != nil {
+		t.Fatalf("SetValueSchema(nil): %v", err)
+	}
+	tx, err = db.BeginTx()
+	if err != nil {
+		t.Fatalf("BeginTx: %v", err)
+	}
+	mustAdd(t, tx, uuidFromTS(2000), `{"m":1}`)
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+
+	var invalidInput *InvalidInputError
+	if err := db.SetValueSchema([]byte(`{"type":1}`)); !errors.As(err, &invalidInput) {
+		t.Fatalf("expected InvalidInputError for invalid schema, got %v", err)
+	}
+}
@@ -4,15 +4,59 @@ import (
 	"fmt"
 	"hash/crc32"
 	"os"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
 )
 
-// Verify validates the integrity of a frozenDB file.
+// VERIFY_MAX_PROBLEMS is the number of problems a VerifyReport records. Problems
+// found beyond it are only counted in VerifyReport.ProblemCount.
+const VERIFY_MAX_PROBLEMS = 100
+
+// VerifyProblem is a single problem found by Verify.
+type VerifyProblem struct {
+	Offset int64 // File offset of the row (or header) with the problem
+	Err    error // CorruptDatabaseError or ReadError describing the problem
+}
+
+// VerifyReport summarizes the structure of a verified file and the problems found.
+type VerifyReport struct {
+	RowSize          int             // row_size from the header
+	ChecksumInterval int             // Checksum interval from the header
+	CreatedAt        time.Time       // Creation time from the initial checksum row (zero if not recorded or unreadable)
+	DataRows         int64           // Complete DataRows
+	NullRows         int64           // NullRows
+	ChecksumRows     int64           // ChecksumRows, including the initial one
+	Transactions     int64           // Transactions ended by a commit, rollback, or NullRow
+	PartialRow       bool            // The file ends with a PartialDataRow
+	OpenTransaction  bool            // The file ends inside a transaction
+	ProblemCount     int             // Total problems found, including those not in Problems
+	Problems         []VerifyProblem // The first VERIFY_MAX_PROBLEMS problems, in the order found
+}
+
+// OK reports whether no problems were found.
+func (r *VerifyReport) OK() bool {
+	return r.ProblemCount == 0
+}
+
+func (r *VerifyReport) addProblem(offset int64, err error) {
+	r.ProblemCount++
+	if len(r.Problems) < VERIFY_MAX_PROBLEMS {
+		r.Problems = append(r.Problems, VerifyProblem{Offset: offset, Err: err})
+	}
+}
+
+// Verify validates the integrity and structure of a frozenDB file, continuing
+// past problems so the returned report lists all of them (up to
+// VERIFY_MAX_PROBLEMS).
 //
 // Verify performs comprehensive validation using a two-pass approach:
 //
 // Pass 1 - Checksum Validation:
 //   - Validate initial checksum at offset 64 covers header [0..64)
-//   - For each expected checksum position (every 10,000 data/null rows):
+//   - For each expected checksum position (every checksum interval of data/null
+//     rows, read from the header; 10,000 by default):
 //   - Read checksum row, parse with ChecksumRow.UnmarshalText()
 //   - Calculate byte range covered by this checksum
 //   - Read bytes, calculate CRC32, compare to checksum value
@@ -22,163 +66,522 @@ import (
 //   - For each row in file:
 //   - Call UnmarshalText() to validate structure and parity
 //   - Works for all row types (data, null, checksum)
+//   - Check the row against the rows before it (see below)
 //   - If file doesn't end on row boundary, validate as PartialDataRow
 //
 // Verify validates:
-//   - Header structure and field values (64-byte header, signature, version, row_size, skew_ms)
-//   - All checksum blocks (initial checksum covering header, subsequent checksums every 10,000 rows)
+//   - Header structure and field values (64-byte header, signature, version, row_size, skew_ms,
+//     and the optional checksum interval)
+//   - All checksum blocks (initial checksum covering header, subsequent checksums every checksum interval)
 //   - Parity bytes for all rows after the last checksum block
 //   - Row format compliance (ROW_START, ROW_END, control bytes, UUID format, JSON validity, padding)
 //   - Partial data row validity if present as the last row
+//   - Checksum rows appear only at checksum positions
+//   - Transaction structure: 'T' starts a transaction only when none is open,
+//     'R' only continues an open one, NullRows appear only between transactions,
+//     and a rollback targets a savepoint that exists
+//   - UUID timestamp ordering: each key's timestamp plus skew_ms exceeds the
+//     maximum timestamp of the committed rows and earlier rows of its transaction,
+//     and NullRow timestamps do not fall below the committed maximum
+//
+// Returns:
+//   - *VerifyReport: Row counts and problems found; nil when the file cannot be
+//     opened or its header is invalid, since no row can be located without it
+//   - error: nil when the report has no problems; otherwise the first problem
+//     (checksum problems are reported before row problems), or the error that
+//     prevented verification
+func Verify(path string) (*VerifyReport, error) {
+	return VerifyWithOptions(path, VerifyOptions{})
+}
+
+// VerifyOptions selects optional behavior of VerifyWithOptions.
+type VerifyOptions struct {
+	// Workers is the maximum number of checksum windows validated at once, as for
+	// VerifyParallel. 0 means 1.
+	Workers int
+	// CheckOrdering adds a pass over the committed data rows, the rows a reader
+	// sees, checking each key against the committed key with the largest
+	// timestamp before it under the skew rule: timestamp plus skew_ms must exceed
+	// that key's timestamp. Unlike the ordering check Verify always runs, rows kept
+	// by a partial rollback take part. Only the first offending pair is reported,
+	// since one key far ahead of the others would make every later key fail.
+	CheckOrdering bool
+}
+
+// VerifyParallel is Verify with Pass 1 spread over up to workers goroutines.
+// Checksum windows cover fixed byte ranges of a file whose earlier bytes never
+// change, so each is validated independently with positional reads of the shared
+// file; the problems found are merged in file order, so the report is identical
+// to Verify's. Pass 2 checks each row against the rows before it and stays serial.
+//
+// Parameters:
+//   - path: Filesystem path to the database file
+//   - workers: Maximum number of checksum windows validated at once (at least 1)
 //
-// Verify does NOT validate:
-//   - Transaction nesting or state relationships between rows
-//   - UUID timestamp ordering constraints
-//   - Savepoint numbering or rollback semantics
-func Verify(path string) error {
+// Returns:
+//   - *VerifyReport, error: As for Verify; InvalidInputError if workers < 1
+func VerifyParallel(path string, workers int) (*VerifyReport, error) {
+	if workers < 1 {
+		return nil, NewInvalidInputError(fmt.Sprintf("workers must be at least 1, got %d", workers), nil)
+	}
+	return VerifyWithOptions(path, VerifyOptions{Workers: workers})
+}
+
+// VerifyWithOptions is Verify with the optional behavior selected by opts.
+//
+// Returns:
+//   - *VerifyReport, error: As for Verify; InvalidInputError if opts.Workers < 0
+func VerifyWithOptions(path string, opts VerifyOptions) (*VerifyReport, error) {
+	workers := opts.Workers
+	if workers < 0 {
+		return nil, NewInvalidInputError(fmt.Sprintf("workers must not be negative, got %d", workers), nil)
+	}
+	if workers == 0 {
+		workers = 1
+	}
+	file, fileSize, header, err := openVerifyTarget(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = file.Close() }()
+
+	report := &VerifyReport{
+		RowSize:          header.GetRowSize(),
+		ChecksumInterval: header.GetChecksumInterval(),
+	}
+
+	// PASS 1: Validate All Checksums (initial + subsequent)
+	validateAllChecksums(file, fileSize, header, report, workers)
+
+	// The creation time is stored in the initial checksum row, which pass 1 has
+	// checked; a problem with it is already reported
+	initialHeader := *header
+	initialRow := make([]byte, header.GetRowSize())
+	if _, err := file.ReadAt(initialRow, HEADER_SIZE); err == nil {
+		if _, err := initialHeader.readInitialChecksumRow(initialRow); err == nil {
+			report.CreatedAt = initialHeader.GetCreatedAt()
+		}
+	}
+
+	// PASS 2: Validate All Rows (structure, parity, and relationships between rows)
+	validateAllRows(file, fileSize, header, report, opts.CheckOrdering)
+
+	if report.ProblemCount > 0 {
+		return report, report.Problems[0].Err
+	}
+	return report, nil
+}
+
+// openVerifyTarget opens the database file at path for verification, validating
+// the header and the minimum file size. On success the caller must close the file.
+func openVerifyTarget(path string) (file *os.File, fileSize int64, header *Header, err error) {
 	// Validate input
 	if path == "" {
-		return NewInvalidInputError("path cannot be empty", nil)
+		return nil, 0, nil, NewInvalidInputError("path cannot be empty", nil)
 	}
 
 	// Open file for reading
-	file, err := os.Open(path)
+	file, err = os.Open(path)
 	if err != nil {
-		return NewReadError(fmt.Sprintf("failed to open file: %s", path), err)
+		return nil, 0, nil, NewReadError(fmt.Sprintf("failed to open file: %s", path), err)
 	}
+	// Close the file on any validation failure; the caller owns it on success
 	defer func() {
-		if closeErr := file.Close(); closeErr != nil && err == nil {
-			err = NewReadError("failed to close file", closeErr)
+		if err != nil {
+			_ = file.Close()
 		}
 	}()
 
 	// Get file info
 	fileInfo, err := file.Stat()
 	if err != nil {
-		return NewReadError("failed to get file info", err)
+		return nil, 0, nil, NewReadError("failed to get file info", err)
 	}
-	fileSize := fileInfo.Size()
+	fileSize = fileInfo.Size()
 
 	// Minimum file size: 64-byte header + 1 checksum row (128 bytes minimum)
 	if fileSize < 64 {
-		return NewCorruptDatabaseError("file too small: must be at least 64 bytes for header", nil)
+		return nil, 0, nil, NewCorruptDatabaseError("file too small: must be at least 64 bytes for header", nil)
 	}
 
 	// Read and validate header first (needed to get row_size)
 	headerBytes := make([]byte, HEADER_SIZE)
 	n, err := file.ReadAt(headerBytes, 0)
 	if err != nil || n != HEADER_SIZE {
-		return NewCorruptDatabaseError("failed to read header: file must be at least 64 bytes", err)
+		return nil, 0, nil, NewCorruptDatabaseError("failed to read header: file must be at least 64 bytes", err)
 	}
 
-	var header Header
+	header = &Header{}
 	if err := header.UnmarshalText(headerBytes); err != nil {
-		return NewCorruptDatabaseError(fmt.Sprintf("invalid header at offset 0: %v", err), err)
+		return nil, 0, nil, NewCorruptDatabaseError(fmt.Sprintf("invalid header at offset 0: %v", err), err)
 	}
 
 	rowSize := header.GetRowSize()
 
 	// Validate minimum file size for initial checksum
 	if fileSize < int64(HEADER_SIZE+rowSize) {
-		return NewCorruptDatabaseError(fmt.Sprintf("file too small: must have at least header (64 bytes) + initial checksum row (%d bytes)", rowSize), nil)
+		return nil, 0, nil, NewCorruptDatabaseError(fmt.Sprintf("file too small: must have at least header (64 bytes) + initial checksum row (%d bytes)", rowSize), nil)
 	}
+	return file, fileSize, header, nil
+}
 
-	// PASS 1: Validate All Checksums (initial + subsequent)
-	if err := validateAllChecksums(file, fileSize, rowSize); err != nil {
-		return err
+// validateAllChecksums performs Pass 1: validates all checksum rows in the file
+// using up to workers goroutines, adding a problem to report for each one that
+// fails, in file order
+func validateAllChecksums(file *os.File, fileSize int64, header *Header, report *VerifyReport, workers int) {
+	// A checksum exists if there's enough space for a complete checksum row
+	rowSize := int64(header.GetRowSize())
+	checksumCount := 0
+	for checksumRowOffset(checksumCount, header)+rowSize <= fileSize {
+		checksumCount++
 	}
 
-	// PASS 2: Validate All Rows (structure and parity for rows after last checksum)
-	if err := validateAllRows(file, fileSize, rowSize); err != nil {
-		return err
+	errs := make([]error, checksumCount)
+	indices := make(chan int)
+	var wg sync.WaitGroup
+	for range min(workers, checksumCount) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for checksumIndex := range indices {
+				errs[checksumIndex] = validateChecksumRow(file, checksumIndex, header)
+			}
+		}()
+	}
+	for checksumIndex := range checksumCount {
+		indices <- checksumIndex
 	}
+	close(indices)
+	wg.Wait()
 
-	return nil
+	for checksumIndex, err := range errs {
+		if err != nil {
+			report.addProblem(checksumRowOffset(checksumIndex, header), err)
+		}
+	}
 }
 
-// validateAllChecksums performs Pass 1: validates all checksum rows in the file
-func validateAllChecksums(file *os.File, fileSize int64, rowSize int) error {
-	// Checksum positions follow this pattern:
-	// Checksum 0: offset 64 (covers header bytes [0, 64))
-	// Checksum 1: offset 64 + 1*(rowSize + 10000*rowSize) = 64 + 10001*rowSize
-	//             (covers checksum 0 + 10,000 data rows)
-	// Checksum 2: offset 64 + 2*(rowSize + 10000*rowSize) = 64 + 2*10001*rowSize
-	//             (covers checksum 1 + next 10,000 data rows)
-	// Checksum i: offset = 64 + i*(rowSize + 10000*rowSize) = 64 + i*10001*rowSize
-
-	checksumIndex := 0
-
-	for {
-		// Calculate checksum position
-		var checksumOffset int64
-		var rangeStart int64
-		var rangeLength int64
-
-		if checksumIndex == 0 {
-			// Initial checksum at offset 64
-			checksumOffset = int64(HEADER_SIZE)
-			rangeStart = 0
-			rangeLength = HEADER_SIZE
-		} else {
-			// Subsequent checksums: 64 + checksumIndex * 10001 * rowSize
-			checksumOffset = int64(HEADER_SIZE + checksumIndex*10001*rowSize)
+// checksumRowOffset returns the file offset of the checksum row with the given index.
+// Checksum 0 sits at offset 64 and covers the header; checksum i (i >= 1) sits at
+// 64 + i*(interval+1)*rowSize and covers checksum i-1 plus the interval rows after
+// it, where interval is the header's checksum interval (10,000 by default).
+func checksumRowOffset(checksumIndex int, header *Header) int64 {
+	return int64(HEADER_SIZE) + int64(checksumIndex)*int64(header.GetChecksumInterval()+1)*int64(header.GetRowSize())
+}
+
+// validateChecksumRow reads the checksum row with the given index and compares
+// its value to the CRC32 of the bytes it covers. The row must exist in the file.
+func validateChecksumRow(file *os.File, checksumIndex int, header *Header) error {
+	rowSize := header.GetRowSize()
+	checksumOffset := checksumRowOffset(checksumIndex, header)
+
+	var rangeStart int64
+	var rangeLength int64
+	if checksumIndex == 0 {
+		// Initial checksum covers the header
+		rangeStart = 0
+		rangeLength = HEADER_SIZE
+	} else {
+		// Range starts at previous checksum offset
+		rangeStart = checksumRowOffset(checksumIndex-1, header)
+		rangeLength = checksumOffset - rangeStart
+	}
+
+	// Read checksum row
+	checksumRowBytes := make([]byte, rowSize)
+	n, err := file.ReadAt(checksumRowBytes, checksumOffset)
+	if err != nil || n != rowSize {
+		return NewCorruptDatabaseError(fmt.Sprintf("failed to read checksum row at offset %d", checksumOffset), err)
+	}
+
+	// Parse checksum row - this MUST succeed since we expect a checksum at this position
+	var checksumRow ChecksumRow
+	if err := checksumRow.UnmarshalText(checksumRowBytes); err != nil {
+		return NewCorruptDatabaseError(fmt.Sprintf("invalid checksum row at offset %d: %v", checksumOffset, err), err)
+	}
 
-			// Range starts at previous checksum offset
-			previousChecksumOffset := int64(HEADER_SIZE + (checksumIndex-1)*10001*rowSize)
-			rangeStart = previousChecksumOffset
-			rangeLength = checksumOffset - previousChecksumOffset
+	// Only the initial checksum row may carry an extension, which holds header
+	// fields that must agree with the header version
+	if checksumIndex == 0 {
+		initialHeader := *header
+		if _, err := initialHeader.readInitialChecksumRow(checksumRowBytes); err != nil {
+			return NewCorruptDatabaseError(fmt.Sprintf("invalid checksum row at offset %d: %v", checksumOffset, err), err)
 		}
+	} else if checksumRow.Extension != nil {
+		return NewCorruptDatabaseError(fmt.Sprintf("checksum row at offset %d has an extension, which only the initial checksum row may have", checksumOffset), nil)
+	}
+
+	// Read the bytes that should be covered by this checksum
+	dataToChecksum := make([]byte, rangeLength)
+	if _, err := file.ReadAt(dataToChecksum, rangeStart); err != nil {
+		return NewReadError(fmt.Sprintf("failed to read data for checksum validation at offset %d", checksumOffset), err)
+	}
+	if checksumIndex == 0 {
+		dataToChecksum = initialChecksumInput(dataToChecksum, checksumRow.Extension)
+	}
+
+	// Calculate expected checksum
+	expectedChecksum := crc32.ChecksumIEEE(dataToChecksum)
+
+	// Compare checksums
+	if Checksum(expectedChecksum) != *checksumRow.RowPayload {
+		return NewCorruptDatabaseError(
+			fmt.Sprintf("checksum mismatch at offset %d (expected %08X, got %08X)",
+				checksumOffset, expectedChecksum, *checksumRow.RowPayload),
+			nil,
+		)
+	}
+	return nil
+}
+
+// validateAllRows performs Pass 2: row-by-row validation
+// Validates structure and parity for all rows and checks each row against the
+// rows before it, adding a problem to report for each failure. Rows that already
+// failed in Pass 1 are not reported again. checkOrdering enables the
+// VerifyOptions.CheckOrdering pass.
+func validateAllRows(file *os.File, fileSize int64, header *Header, report *VerifyReport, checkOrdering bool) {
+	checker := &structureChecker{
+		header:   header,
+		report:   report,
+		reported: make(map[int64]bool, len(report.Problems)),
+		ordering: checkOrdering,
+	}
+	for _, problem := range report.Problems {
+		checker.reported[problem.Offset] = true
+	}
 
-		// Check if this checksum should exist based on file size
-		// A checksum exists if there's enough space for: the checksum row + at least 1 more row after it
-		// OR if it's the last complete row in the file
-		if checksumOffset+int64(rowSize) > fileSize {
-			// Not enough space for a complete checksum row
+	rowSize := int64(header.GetRowSize())
+	// Start at offset 64 (after header)
+	for offset := int64(HEADER_SIZE); offset < fileSize; offset += rowSize {
+		if remainingBytes := fileSize - offset; remainingBytes < rowSize {
+			checker.checkPartialRow(file, offset, remainingBytes)
 			break
 		}
+		checker.checkRow(file, offset)
+	}
+	if checker.inTx {
+		report.OpenTransaction = true
+	}
+}
 
-		// Read checksum row
-		checksumRowBytes := make([]byte, rowSize)
-		n, err := file.ReadAt(checksumRowBytes, checksumOffset)
-		if err != nil || n != rowSize {
-			return NewCorruptDatabaseError(fmt.Sprintf("failed to read checksum row at offset %d", checksumOffset), err)
-		}
+// structureChecker tracks transaction and timestamp state across the rows of a
+// file during Pass 2.
+type structureChecker struct {
+	header   *Header
+	report   *VerifyReport
+	reported map[int64]bool // Offsets of rows that failed in Pass 1
+
+	inTx       bool  // A transaction is open
+	txUnknown  bool  // A row that failed to parse may have started or ended a transaction
+	txOffset   int64 // Offset of the open transaction's first row
+	savepoints int   // Savepoints created in the open transaction
+	txMax      int64 // Maximum key timestamp in the open transaction
+
+	committedMax int64 // Maximum key timestamp of committed rows
+
+	ordering      bool         // Run the VerifyOptions.CheckOrdering pass
+	txKeys        []orderedKey // Keys of the open transaction, for the ordering pass
+	orderMax      orderedKey   // Committed key with the largest timestamp so far
+	orderReported bool         // The ordering pass has reported its problem
+}
 
-		// Parse checksum row - this MUST succeed since we expect a checksum at this position
-		var checksumRow ChecksumRow
-		if err := checksumRow.UnmarshalText(checksumRowBytes); err != nil {
-			return NewCorruptDatabaseError(fmt.Sprintf("invalid checksum row at offset %d: %v", checksumOffset, err), err)
+// rowIndex returns the index of the row at offset.
+func (c *structureChecker) rowIndex(offset int64) int64 {
+	return (offset - HEADER_SIZE) / int64(c.header.GetRowSize())
+}
+
+// orderedKey is a DataRow key seen by the ordering pass.
+type orderedKey struct {
+	offset    int64
+	key       uuid.UUID
+	savepoint int // Savepoints created in the transaction before the row
+}
+
+func (c *structureChecker) addProblem(offset int64, err error) {
+	if !c.reported[offset] {
+		c.report.addProblem(offset, err)
+	}
+}
+
+// checkRow validates the complete row at offset.
+func (c *structureChecker) checkRow(file *os.File, offset int64) {
+	rowBytes := make([]byte, c.header.GetRowSize())
+	if _, err := file.ReadAt(rowBytes, offset); err != nil {
+		c.addProblem(offset, NewReadError(fmt.Sprintf("failed to read row at offset %d", offset), err))
+		return
+	}
+
+	// Use RowUnion to unmarshal and validate the row
+	// RowUnion will automatically detect the row type from control bytes
+	// This validates structure, parity, and all row-specific fields
+	var rowUnion RowUnion
+	if err := rowUnion.UnmarshalText(rowBytes); err != nil {
+		c.addProblem(offset, NewCorruptDatabaseError(fmt.Sprintf("invalid row at offset %d: %v", offset, err), err))
+		// Checksum positions hold no transaction rows, so only other rows leave
+		// the transaction state unknown
+		if (offset-int64(HEADER_SIZE))/int64(c.header.GetRowSize())%int64(c.header.GetChecksumInterval()+1) != 0 {
+			c.txUnknown = true
 		}
+		return
+	}
 
-		// Read the bytes that should be covered by this checksum
-		dataToChecksum := make([]byte, rangeLength)
-		if _, err := file.ReadAt(dataToChecksum, rangeStart); err != nil {
-			return NewReadError(fmt.Sprintf("failed to read data for checksum validation at offset %d", checksumOffset), err)
+	index := (offset - int64(HEADER_SIZE)) / int64(c.header.GetRowSize())
+	isChecksumPosition := index%int64(c.header.GetChecksumInterval()+1) == 0
+
+	switch {
+	case rowUnion.ChecksumRow != nil:
+		c.report.ChecksumRows++
+		if !isChecksumPosition {
+			c.addProblem(offset, NewCorruptDatabaseError(fmt.Sprintf("checksum row at offset %d is not at a checksum position", offset), nil))
+		}
+	case rowUnion.NullRow != nil:
+		c.report.NullRows++
+		c.report.Transactions++
+		if c.inTx && !c.txUnknown {
+			c.addProblem(offset, NewCorruptDatabaseError(
+				fmt.Sprintf("null row %d at offset %d inside the transaction started at row %d (offset %d), which is not terminated",
+					c.rowIndex(offset), offset, c.rowIndex(c.txOffset), c.txOffset), nil))
+		}
+		c.inTx = false
+		c.txUnknown = false
+		c.txKeys = c.txKeys[:0]
+		if ts := ExtractUUIDv7Timestamp(rowUnion.NullRow.GetKey()); ts < c.committedMax {
+			c.addProblem(offset, NewCorruptDatabaseError(
+				fmt.Sprintf("null row at offset %d has timestamp %d, below the maximum committed timestamp %d", offset, ts, c.committedMax), nil))
 		}
+	case rowUnion.DataRow != nil:
+		c.report.DataRows++
+		c.startOrContinue(offset, rowUnion.DataRow.StartControl)
+		c.checkTimestamp(offset, rowUnion.DataRow.GetKey())
+		if c.ordering {
+			c.txKeys = append(c.txKeys, orderedKey{offset: offset, key: rowUnion.DataRow.GetKey(), savepoint: c.savepoints})
+		}
+		c.end(offset, rowUnion.DataRow.EndControl)
+	}
+}
 
-		// Calculate expected checksum
-		expectedChecksum := crc32.ChecksumIEEE(dataToChecksum)
+// checkPartialRow validates the trailing partial row at offset.
+func (c *structureChecker) checkPartialRow(file *os.File, offset int64, length int64) {
+	c.report.PartialRow = true
+	partialBytes := make([]byte, length)
+	if _, err := file.ReadAt(partialBytes, offset); err != nil {
+		c.addProblem(offset, NewReadError(fmt.Sprintf("failed to read partial row at offset %d", offset), err))
+		return
+	}
 
-		// Compare checksums
-		if Checksum(expectedChecksum) != *checksumRow.RowPayload {
-			return NewCorruptDatabaseError(
-				fmt.Sprintf("checksum mismatch at offset %d (expected %08X, got %08X)",
-					checksumOffset, expectedChecksum, *checksumRow.RowPayload),
-				nil,
-			)
+	// Try to parse as PartialDataRow
+	rowIndex := (offset - int64(HEADER_SIZE)) / int64(c.header.GetRowSize())
+	partialRow, err := parseTrailingPartialRow(partialBytes, rowIndex, c.header.GetRowSize())
+	if err != nil {
+		c.addProblem(offset, err)
+		return
+	}
+	c.startOrContinue(offset, partialRow.GetStartControl())
+	if key, ok := partialRow.GetKey(); ok {
+		c.checkTimestamp(offset, key)
+	}
+}
+
+// startOrContinue applies a DataRow's start_control to the transaction state.
+func (c *structureChecker) startOrContinue(offset int64, startControl StartControl) {
+	switch startControl {
+	case START_TRANSACTION:
+		if c.inTx && !c.txUnknown {
+			c.addProblem(offset, NewCorruptDatabaseError(
+				fmt.Sprintf("row %d at offset %d starts a transaction while the transaction started at row %d (offset %d) is not terminated",
+					c.rowIndex(offset), offset, c.rowIndex(c.txOffset), c.txOffset), nil))
+		}
+	case ROW_CONTINUE:
+		if c.inTx || c.txUnknown {
+			c.inTx = true
+			return
 		}
+		c.addProblem(offset, NewCorruptDatabaseError(
+			fmt.Sprintf("row at offset %d continues a transaction but none is open", offset), nil))
+	}
+	// Start a new transaction, also after a problem, so later rows are checked
+	// against it rather than reported again
+	c.inTx = true
+	c.txUnknown = false
+	c.txOffset = offset
+	c.savepoints = 0
+	c.txMax = 0
+	c.txKeys = c.txKeys[:0]
+}
 
-		checksumIndex++
+// checkTimestamp checks the ordering constraint for a DataRow key and records
+// its timestamp in the open transaction.
+func (c *structureChecker) checkTimestamp(offset int64, key uuid.UUID) {
+	ts := ExtractUUIDv7Timestamp(key)
+	maxTimestamp := max(c.committedMax, c.txMax)
+	if skewMs := int64(c.header.GetSkewMs()); ts+skewMs <= maxTimestamp {
+		c.addProblem(offset, NewCorruptDatabaseError(
+			fmt.Sprintf("key at offset %d has timestamp %d, which plus skew_ms %d does not exceed the maximum timestamp %d",
+				offset, ts, skewMs, maxTimestamp), nil))
 	}
+	c.txMax = max(c.txMax, ts)
+}
 
-	return nil
+// end applies a DataRow's end_control to the transaction state.
+func (c *structureChecker) end(offset int64, endControl EndControl) {
+	if endControl[0] == 'S' {
+		c.savepoints++
+	}
+	switch second := endControl[1]; {
+	case second == 'C':
+		c.committedMax = max(c.committedMax, c.txMax)
+		c.checkOrdering(c.savepoints + 1)
+	case second >= '0' && second <= '9':
+		// Rows kept by a partial rollback are not added to committedMax, which
+		// keeps the timestamp checks from reporting rows the writer accepted
+		if savepoint := int(second - '0'); savepoint > c.savepoints && !c.txUnknown {
+			c.addProblem(offset, NewCorruptDatabaseError(
+				fmt.Sprintf("row at offset %d rolls back to savepoint %d but the transaction has %d", offset, savepoint, c.savepoints), nil))
+		} else {
+			c.checkOrdering(savepoint)
+		}
+	default:
+		return
+	}
+	c.report.Transactions++
+	c.inTx = false
+	c.txUnknown = false
+	c.txMax = 0
+	c.txKeys = c.txKeys[:0]
 }
 
-// validateAllRows performs Pass 2: row-by-row validation
-// Validates structure and parity for all rows
-func validateAllRows(file *os.File, fileSize int64, rowSize int) error {
-	// Start at offset 64 (after header)
-	currentOffset := int64(HEADER_SIZE)
+// checkOrdering runs the ordering pass over the rows of the transaction ending
+// now that were created before savepoint number savepoints, the rows it commits.
+func (c *structureChecker) checkOrdering(savepoints int) {
+	skewMs := int64(c.header.GetSkewMs())
+	for _, k := range c.txKeys {
+		if k.savepoint >= savepoints {
+			break
+		}
+		ts := ExtractUUIDv7Timestamp(k.key)
+		if c.orderMax.key == uuid.Nil {
+			c.orderMax = k
+			continue
+		}
+		maxTs := ExtractUUIDv7Timestamp(c.orderMax.key)
+		if ts+skewMs <= maxTs && !c.orderReported {
+			c.orderReported = true
+			c.report.addProblem(k.offset, NewCorruptDatabaseError(
+				fmt.Sprintf("key %s at offset %d (timestamp %d, %s) is out of order after key %s at offset %d (timestamp %d, %s): timestamp plus skew_ms %d does not exceed %d",
+					k.key, k.offset, ts, TimestampOf(k.key).Format(time.RFC3339Nano),
+					c.orderMax.key, c.orderMax.offset, maxTs, TimestampOf(c.orderMax.key).Format(time.RFC3339Nano), skewMs, maxTs), nil))
+		}
+		if ts > maxTs {
+			c.orderMax = k
+		}
+	}
+}
+
+// validateRowsInRange validates structure and parity for the rows in [start, end).
+// start must be a row boundary; a trailing fragment shorter than rowSize is
+// validated as a PartialDataRow.
+func validateRowsInRange(file *os.File, start int64, fileSize int64, rowSize int) error {
+	currentOffset := start
 
 	for currentOffset < fileSize {
 		remainingBytes := fileSize - currentOffset
@@ -192,12 +595,9 @@ func validateAllRows(file *os.File, fileSize int64, rowSize int) error {
 			}
 
 			// Try to parse as PartialDataRow
-			var partialRow PartialDataRow
-			if err := partialRow.UnmarshalText(partialBytes); err != nil {
-				return NewCorruptDatabaseError(
-					fmt.Sprintf("invalid partial row at offset %d: %v", currentOffset, err),
-					err,
-				)
+			rowIndex := (currentOffset - int64(HEADER_SIZE)) / int64(rowSize)
+			if _, err := parseTrailingPartialRow(partialBytes, rowIndex, rowSize); err != nil {
+				return err
 			}
 
 			break
@@ -225,3 +625,51 @@ func validateAllRows(file *os.File, fileSize int64, rowSize int) error {
 
 	return nil
 }
+
+// VerifiedPrefixSize returns the size of the longest prefix of the database file
+// that ends on a checksum row and passes verification: every checksum row up to
+// and including the last one validates, and every row before it is well formed.
+//
+// Bytes beyond this size cannot be vouched for by a checksum. Truncating the file
+// to this size yields a database that Verify accepts, discarding only the tail.
+//
+// Returns:
+//   - int64: Prefix size in bytes (always at least header + initial checksum row)
+//   - error: InvalidInputError, ReadError, or CorruptDatabaseError when the header
+//     or the initial checksum row is itself invalid, so no valid prefix exists
+func VerifiedPrefixSize(path string) (int64, error) {
+	file, fileSize, header, err := openVerifyTarget(path)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = file.Close() }()
+	rowSize := header.GetRowSize()
+
+	var verified int64
+	for checksumIndex := 0; ; checksumIndex++ {
+		checksumOffset := checksumRowOffset(checksumIndex, header)
+		end := checksumOffset + int64(rowSize)
+		if end > fileSize {
+			break
+		}
+		if err := validateChecksumRow(file, checksumIndex, header); err != nil {
+			if checksumIndex == 0 {
+				return 0, err
+			}
+			break
+		}
+		// Rows covered by this checksum, plus the checksum row itself
+		rangeStart := int64(HEADER_SIZE)
+		if checksumIndex > 0 {
+			rangeStart = checksumRowOffset(checksumIndex-1, header) + int64(rowSize)
+		}
+		if err := validateRowsInRange(file, rangeStart, end, rowSize); err != nil {
+			if checksumIndex == 0 {
+				return 0, err
+			}
+			break
+		}
+		verified = end
+	}
+	return verified, nil
+}
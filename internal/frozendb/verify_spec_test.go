@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"errors"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
@@ -72,7 +73,7 @@ func Test_S_034_FR_001_HeaderSize(t *testing.T) {
 	tmpFile.Close()
 
 	// Verify should fail
-	err = Verify(tmpPath)
+	_, err = Verify(tmpPath)
 	if err == nil {
 		t.Error("Verify should fail for header size != 64 bytes")
 	}
@@ -103,7 +104,7 @@ func Test_S_034_FR_002_HeaderSignature(t *testing.T) {
 	tmpFile.Close()
 
 	// Verify should fail
-	err = Verify(tmpPath)
+	_, err = Verify(tmpPath)
 	if err == nil {
 		t.Error("Verify should fail for invalid signature")
 	}
@@ -134,7 +135,7 @@ func Test_S_034_FR_003_HeaderVersion(t *testing.T) {
 	tmpFile.Close()
 
 	// Verify should fail
-	err = Verify(tmpPath)
+	_, err = Verify(tmpPath)
 	if err == nil {
 		t.Error("Verify should fail for version != 1")
 	}
@@ -165,7 +166,7 @@ func Test_S_034_FR_004_HeaderRowSize(t *testing.T) {
 	tmpFile.Close()
 
 	// Verify should fail
-	err = Verify(tmpPath)
+	_, err = Verify(tmpPath)
 	if err == nil {
 		t.Error("Verify should fail for row_size out of range")
 	}
@@ -196,7 +197,7 @@ func Test_S_034_FR_005_HeaderSkewMs(t *testing.T) {
 	tmpFile.Close()
 
 	// Verify should fail
-	err = Verify(tmpPath)
+	_, err = Verify(tmpPath)
 	if err == nil {
 		t.Error("Verify should fail for skew_ms out of range")
 	}
@@ -227,7 +228,7 @@ func Test_S_034_FR_006_HeaderJSONKeyOrder(t *testing.T) {
 	tmpFile.Close()
 
 	// Verify should fail
-	err = Verify(tmpPath)
+	_, err = Verify(tmpPath)
 	if err == nil {
 		t.Error("Verify should fail for incorrect JSON key order")
 	}
@@ -262,7 +263,7 @@ func Test_S_034_FR_007_HeaderNullPadding(t *testing.T) {
 	tmpFile.Close()
 
 	// Verify should fail
-	err = Verify(tmpPath)
+	_, err = Verify(tmpPath)
 	if err == nil {
 		t.Error("Verify should fail for non-null padding")
 	}
@@ -303,7 +304,7 @@ func Test_S_034_FR_008_InitialChecksumExists(t *testing.T) {
 	tmpFile.Close()
 
 	// Verify should fail
-	err = Verify(tmpPath)
+	_, err = Verify(tmpPath)
 	if err == nil {
 		t.Error("Verify should fail when initial checksum is missing")
 	}
@@ -340,7 +341,7 @@ func Test_S_034_FR_009_InitialChecksumValid(t *testing.T) {
 	// Write checksum row with WRONG checksum
 	wrongChecksum := Checksum(0xDEADBEEF)
 	checksumRow := &ChecksumRow{
-		baseRow[*Checksum]{
+		baseRow: baseRow[*Checksum]{
 			RowSize:      128,
 			StartControl: CHECKSUM_ROW,
 			EndControl:   CHECKSUM_ROW_CONTROL,
@@ -357,7 +358,7 @@ func Test_S_034_FR_009_InitialChecksumValid(t *testing.T) {
 	tmpFile.Close()
 
 	// Verify should fail
-	err = Verify(tmpPath)
+	_, err = Verify(tmpPath)
 	if err == nil {
 		t.Error("Verify should fail when initial checksum is invalid")
 	}
@@ -385,7 +386,7 @@ func Test_S_034_FR_010_ChecksumEvery10kRows(t *testing.T) {
 	createTestDatabaseForVerify(t, tmpPath, 128, 0)
 
 	// Verify should succeed - validates checksum positioning logic works
-	err = Verify(tmpPath)
+	_, err = Verify(tmpPath)
 	if err != nil {
 		t.Errorf("Verify should succeed for valid database, got error: %v", err)
 	}
@@ -408,7 +409,7 @@ func Test_S_034_FR_011_ChecksumBlockMatches(t *testing.T) {
 	createTestDatabaseForVerify(t, tmpPath, 128, 0)
 
 	// Verify should succeed
-	err = Verify(tmpPath)
+	_, err = Verify(tmpPath)
 	if err != nil {
 		t.Errorf("Verify should succeed for valid checksum block, got error: %v", err)
 	}
@@ -454,7 +455,7 @@ func Test_S_034_FR_012_ParityValidationAfterChecksum(t *testing.T) {
 	file.Close()
 
 	// Verify should succeed - validates parity is checked
-	err = Verify(tmpPath)
+	_, err = Verify(tmpPath)
 	if err != nil {
 		t.Errorf("Verify should succeed for valid row with parity, got error: %v", err)
 	}
@@ -509,7 +510,7 @@ func Test_S_034_FR_013_LRCParityMatches(t *testing.T) {
 	file.Close()
 
 	// Verify should fail due to invalid parity
-	err = Verify(tmpPath)
+	_, err = Verify(tmpPath)
 	if err == nil {
 		t.Error("Verify should fail for invalid parity")
 	}
@@ -577,7 +578,7 @@ func Test_S_034_FR_014_DataRowStartByte(t *testing.T) {
 	file.Close()
 
 	// Verify should fail
-	err = Verify(tmpPath)
+	_, err = Verify(tmpPath)
 	if err == nil {
 		t.Error("Verify should fail for invalid ROW_START byte")
 	}
@@ -641,7 +642,7 @@ func Test_S_034_FR_015_DataRowEndByte(t *testing.T) {
 	file.Close()
 
 	// Verify should fail
-	err = Verify(tmpPath)
+	_, err = Verify(tmpPath)
 	if err == nil {
 		t.Error("Verify should fail for invalid ROW_END byte")
 	}
@@ -705,7 +706,7 @@ func Test_S_034_FR_016_DataRowStartControl(t *testing.T) {
 	file.Close()
 
 	// Verify should fail
-	err = Verify(tmpPath)
+	_, err = Verify(tmpPath)
 	if err == nil {
 		t.Error("Verify should fail for invalid start_control")
 	}
@@ -771,7 +772,7 @@ func Test_S_034_FR_017_DataRowEndControl(t *testing.T) {
 	file.Close()
 
 	// Verify should fail
-	err = Verify(tmpPath)
+	_, err = Verify(tmpPath)
 	if err == nil {
 		t.Error("Verify should fail for invalid end_control")
 	}
@@ -835,7 +836,7 @@ func Test_S_034_FR_018_UUIDBase64Valid(t *testing.T) {
 	file.Close()
 
 	// Verify should fail
-	err = Verify(tmpPath)
+	_, err = Verify(tmpPath)
 	if err == nil {
 		t.Error("Verify should fail for invalid UUID Base64")
 	}
@@ -886,7 +887,7 @@ func Test_S_034_FR_019_UUIDv7Valid(t *testing.T) {
 	file.Close()
 
 	// Verify should fail - UUIDv4 is not UUIDv7
-	err = Verify(tmpPath)
+	_, err = Verify(tmpPath)
 	if err == nil {
 		t.Error("Verify should fail for non-UUIDv7")
 	}
@@ -950,7 +951,7 @@ func Test_S_034_FR_020_JSONPayloadValid(t *testing.T) {
 	file.Close()
 
 	// Verify should fail
-	err = Verify(tmpPath)
+	_, err = Verify(tmpPath)
 	if err == nil {
 		t.Error("Verify should fail for invalid JSON")
 	}
@@ -1016,7 +1017,7 @@ func Test_S_034_FR_021_DataRowPadding(t *testing.T) {
 	file.Close()
 
 	// Verify should fail
-	err = Verify(tmpPath)
+	_, err = Verify(tmpPath)
 	if err == nil {
 		t.Error("Verify should fail for non-null padding")
 	}
@@ -1054,7 +1055,7 @@ func Test_S_034_FR_022_ChecksumRowFormat(t *testing.T) {
 	// Write invalid checksum row (wrong start_control)
 	invalidChecksum := Checksum(0x12345678)
 	checksumRow := &ChecksumRow{
-		baseRow[*Checksum]{
+		baseRow: baseRow[*Checksum]{
 			RowSize:      128,
 			StartControl: 'T', // Wrong! Should be 'C'
 			EndControl:   CHECKSUM_ROW_CONTROL,
@@ -1071,7 +1072,7 @@ func Test_S_034_FR_022_ChecksumRowFormat(t *testing.T) {
 	tmpFile.Close()
 
 	// Verify should fail
-	err = Verify(tmpPath)
+	_, err = Verify(tmpPath)
 	if err == nil {
 		t.Error("Verify should fail for invalid checksum row format")
 	}
@@ -1115,7 +1116,7 @@ func Test_S_034_FR_023_NullRowFormat(t *testing.T) {
 	file.Close()
 
 	// Verify should succeed - validates null row format is correct
-	err = Verify(tmpPath)
+	_, err = Verify(tmpPath)
 	if err != nil {
 		t.Errorf("Verify should succeed for valid null row, got error: %v", err)
 	}
@@ -1190,7 +1191,7 @@ func Test_S_034_FR_024_NullRowUUID(t *testing.T) {
 	file.Close()
 
 	// Verify should fail
-	err = Verify(tmpPath)
+	_, err = Verify(tmpPath)
 	if err == nil {
 		t.Error("Verify should fail for null row with non-zero UUID")
 	}
@@ -1230,7 +1231,7 @@ func Test_S_034_FR_025_PartialDataRowDetection(t *testing.T) {
 	file.Close()
 
 	// Verify should succeed - valid State 1 partial row
-	err = Verify(tmpPath)
+	_, err = Verify(tmpPath)
 	if err != nil {
 		t.Errorf("Verify should succeed for valid State 1 partial row, got error: %v", err)
 	}
@@ -1266,7 +1267,7 @@ func Test_S_034_FR_026_PartialDataRowLastOnly(t *testing.T) {
 	file.Close()
 
 	// Verify should fail - partial row with extra bytes
-	err = Verify(tmpPath)
+	_, err = Verify(tmpPath)
 	if err == nil {
 		t.Error("Verify should fail for partial row with extra bytes")
 	}
@@ -1334,7 +1335,7 @@ func Test_S_034_FR_027_PartialDataRowValidStates(t *testing.T) {
 			file.Close()
 
 			// Verify should succeed for valid state
-			err = Verify(tmpPath)
+			_, err = Verify(tmpPath)
 			if err != nil {
 				t.Errorf("Verify should succeed for valid %s partial row, got error: %v", state.name, err)
 			}
@@ -1367,7 +1368,7 @@ func Test_S_034_FR_028_PartialDataRowState1(t *testing.T) {
 	file.Close()
 
 	// Verify should succeed
-	err = Verify(tmpPath)
+	_, err = Verify(tmpPath)
 	if err != nil {
 		t.Errorf("Verify should succeed for State 1 partial row, got error: %v", err)
 	}
@@ -1410,7 +1411,7 @@ func Test_S_034_FR_029_PartialDataRowState2(t *testing.T) {
 	file.Close()
 
 	// Verify should succeed
-	err = Verify(tmpPath)
+	_, err = Verify(tmpPath)
 	if err != nil {
 		t.Errorf("Verify should succeed for State 2 partial row, got error: %v", err)
 	}
@@ -1454,7 +1455,7 @@ func Test_S_034_FR_030_PartialDataRowState3(t *testing.T) {
 	file.Close()
 
 	// Verify should succeed
-	err = Verify(tmpPath)
+	_, err = Verify(tmpPath)
 	if err != nil {
 		t.Errorf("Verify should succeed for State 3 partial row, got error: %v", err)
 	}
@@ -1486,7 +1487,7 @@ func Test_S_034_FR_031_PartialDataRowNoBytesAfter(t *testing.T) {
 	file.Close()
 
 	// Verify should fail
-	err = Verify(tmpPath)
+	_, err = Verify(tmpPath)
 	if err == nil {
 		t.Error("Verify should fail for partial row with extra bytes")
 	}
@@ -1530,7 +1531,7 @@ func Test_S_034_FR_032_PartialDataRowFieldValidation(t *testing.T) {
 	file.Close()
 
 	// Verify should fail
-	err = Verify(tmpPath)
+	_, err = Verify(tmpPath)
 	if err == nil {
 		t.Error("Verify should fail for partial row with invalid UUID")
 	}
@@ -1563,7 +1564,7 @@ func Test_S_034_FR_033_CorruptionTypeReporting(t *testing.T) {
 	tmpFile.Close()
 
 	// Verify should fail with error message containing corruption type
-	err = Verify(tmpPath)
+	_, err = Verify(tmpPath)
 	if err == nil {
 		t.Error("Verify should return error for corrupted file")
 	}
@@ -1629,7 +1630,7 @@ func Test_S_034_FR_034_CorruptionLocationReporting(t *testing.T) {
 	file.Close()
 
 	// Verify should fail with error message containing location
-	err = Verify(tmpPath)
+	_, err = Verify(tmpPath)
 	if err == nil {
 		t.Error("Verify should fail for corrupted row")
 	}
@@ -1655,7 +1656,7 @@ func Test_S_034_FR_035_SuccessWhenValid(t *testing.T) {
 	createTestDatabaseForVerify(t, tmpPath, 128, 0)
 
 	// Verify should succeed
-	err = Verify(tmpPath)
+	_, err = Verify(tmpPath)
 	if err != nil {
 		t.Errorf("Verify should succeed for valid database, got error: %v", err)
 	}
@@ -1679,7 +1680,7 @@ func Test_S_034_FR_036_ErrorWhenInvalid(t *testing.T) {
 	tmpFile.Close()
 
 	// Verify should fail
-	err = Verify(tmpPath)
+	_, err = Verify(tmpPath)
 	if err == nil {
 		t.Error("Verify should return error for invalid database")
 	}
@@ -1730,7 +1731,7 @@ func Test_S_034_FR_037_DataRowUUIDNonZero(t *testing.T) {
 	file.Close()
 
 	// Verify should fail
-	err = Verify(tmpPath)
+	_, err = Verify(tmpPath)
 	if err == nil {
 		t.Error("Verify should fail for data row with zero UUID")
 	}
@@ -1763,7 +1764,7 @@ func Test_S_034_FR_038_FailFastBehavior(t *testing.T) {
 	tmpFile.Close()
 
 	// Verify should fail on first corruption (header)
-	err = Verify(tmpPath)
+	_, err = Verify(tmpPath)
 	if err == nil {
 		t.Error("Verify should fail for corrupted header")
 	}
@@ -1777,10 +1778,12 @@ func Test_S_034_FR_038_FailFastBehavior(t *testing.T) {
 
 // ============================================================================
 // Additional Validation Tests (FR-039 to FR-040)
+// FR-039 and FR-040 originally excluded these checks; Verify now performs them
 // ============================================================================
 
-// Test_S_034_FR_039_NoTransactionNestingValidation tests FR-039: Verify does NOT validate transaction nesting
-func Test_S_034_FR_039_NoTransactionNestingValidation(t *testing.T) {
+// Test_S_034_FR_039_TransactionNestingValidation tests FR-039 as revised: Verify
+// reports a transaction started while another is open
+func Test_S_034_FR_039_TransactionNestingValidation(t *testing.T) {
 	tmpFile, err := os.CreateTemp("", "frozendb_verify_test_*.db")
 	if err != nil {
 		t.Fatalf("Failed to create temp file: %v", err)
@@ -1821,15 +1824,20 @@ func Test_S_034_FR_039_NoTransactionNestingValidation(t *testing.T) {
 	}
 	file.Close()
 
-	// Verify should succeed - does NOT validate transaction nesting
-	err = Verify(tmpPath)
-	if err != nil {
-		t.Errorf("Verify should succeed (does not validate transaction nesting), got error: %v", err)
+	// The second row starts a transaction while the first is still open
+	report, err := Verify(tmpPath)
+	var corruptErr *CorruptDatabaseError
+	if !errors.As(err, &corruptErr) || !strings.Contains(err.Error(), "starts a transaction") {
+		t.Fatalf("Expected CorruptDatabaseError for nested transaction, got %v", err)
+	}
+	if report.Problems[0].Offset != int64(HEADER_SIZE+2*128) {
+		t.Errorf("Expected the first problem at the second data row, got %+v", report.Problems)
 	}
 }
 
-// Test_S_034_FR_040_NoUUIDTimestampOrdering tests FR-040: Verify does NOT validate UUID timestamp ordering
-func Test_S_034_FR_040_NoUUIDTimestampOrdering(t *testing.T) {
+// Test_S_034_FR_040_UUIDTimestampOrdering tests FR-040 as revised: Verify reports
+// a key whose timestamp falls further behind the maximum than skew_ms allows
+func Test_S_034_FR_040_UUIDTimestampOrdering(t *testing.T) {
 	tmpFile, err := os.CreateTemp("", "frozendb_verify_test_*.db")
 	if err != nil {
 		t.Fatalf("Failed to create temp file: %v", err)
@@ -1889,9 +1897,13 @@ func Test_S_034_FR_040_NoUUIDTimestampOrdering(t *testing.T) {
 	}
 	file.Close()
 
-	// Verify should succeed - does NOT validate UUID timestamp ordering
-	err = Verify(tmpPath)
-	if err != nil {
-		t.Errorf("Verify should succeed (does not validate UUID ordering), got error: %v", err)
+	// The second key is far older than the first, beyond the 0ms skew
+	report, err := Verify(tmpPath)
+	var corruptErr *CorruptDatabaseError
+	if !errors.As(err, &corruptErr) || !strings.Contains(err.Error(), "timestamp") {
+		t.Fatalf("Expected CorruptDatabaseError for timestamp ordering, got %v", err)
+	}
+	if report.ProblemCount != 1 || report.Problems[0].Offset != int64(HEADER_SIZE+2*128) {
+		t.Errorf("Expected one problem at the second data row, got %+v", report.Problems)
 	}
 }
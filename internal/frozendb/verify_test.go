@@ -5,6 +5,9 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/google/uuid"
@@ -45,7 +48,7 @@ func Test_RowUnion(t *testing.T) {
 	t.Run("valid_checksum_row", func(t *testing.T) {
 		checksum := Checksum(0x12345678)
 		checksumRow := &ChecksumRow{
-			baseRow[*Checksum]{
+			baseRow: baseRow[*Checksum]{
 				RowSize:      256,
 				StartControl: CHECKSUM_ROW,
 				EndControl:   CHECKSUM_ROW_CONTROL,
@@ -86,7 +89,7 @@ func Test_RowUnion(t *testing.T) {
 
 // Test_Verify_EmptyPath tests that Verify rejects empty path
 func Test_Verify_EmptyPath(t *testing.T) {
-	err := Verify("")
+	_, err := Verify("")
 	if err == nil {
 		t.Error("Verify should fail for empty path")
 	}
@@ -99,7 +102,7 @@ func Test_Verify_EmptyPath(t *testing.T) {
 
 // Test_Verify_NonExistentFile tests that Verify handles non-existent files
 func Test_Verify_NonExistentFile(t *testing.T) {
-	err := Verify("/nonexistent/path/to/database.db")
+	_, err := Verify("/nonexistent/path/to/database.db")
 	if err == nil {
 		t.Error("Verify should fail for non-existent file")
 	}
@@ -125,7 +128,7 @@ func Test_Verify_FileTooSmall(t *testing.T) {
 	}
 	tmpFile.Close()
 
-	err = Verify(tmpPath)
+	_, err = Verify(tmpPath)
 	if err == nil {
 		t.Error("Verify should fail for file smaller than 64 bytes")
 	}
@@ -237,7 +240,7 @@ func Test_Verify_Exactly10000Rows(t *testing.T) {
 	createDatabaseWithRows(t, tmpPath, 256, 10000)
 
 	// Verify should succeed
-	err = Verify(tmpPath)
+	_, err = Verify(tmpPath)
 	if err != nil {
 		t.Errorf("Verify should succeed for file with exactly 10,000 rows, got error: %v", err)
 	}
@@ -261,7 +264,7 @@ func Test_Verify_25000Rows(t *testing.T) {
 	createDatabaseWithRows(t, tmpPath, 256, 25000)
 
 	// Verify should succeed
-	err = Verify(tmpPath)
+	_, err = Verify(tmpPath)
 	if err != nil {
 		t.Errorf("Verify should succeed for file with 25,000 rows, got error: %v", err)
 	}
@@ -301,7 +304,7 @@ func Test_Verify_CorruptedSecondChecksum(t *testing.T) {
 	file.Close()
 
 	// Verify should fail
-	err = Verify(tmpPath)
+	_, err = Verify(tmpPath)
 	if err == nil {
 		t.Error("Verify should fail when second checksum block is corrupted")
 	}
@@ -334,7 +337,7 @@ func Test_Verify_VariousRowSizes(t *testing.T) {
 			createDatabaseWithRows(t, tmpPath, rowSize, 100)
 
 			// Verify should succeed
-			err = Verify(tmpPath)
+			_, err = Verify(tmpPath)
 			if err != nil {
 				t.Errorf("Verify should succeed for row size %d, got error: %v", rowSize, err)
 			}
@@ -361,7 +364,7 @@ func Test_Verify_SecondChecksumValidation(t *testing.T) {
 	createDatabaseWithRows(t, tmpPath, 256, 10001)
 
 	// First verify the file is valid
-	err = Verify(tmpPath)
+	_, err = Verify(tmpPath)
 	if err != nil {
 		t.Fatalf("Initial verify should succeed, got error: %v", err)
 	}
@@ -420,7 +423,7 @@ func Test_Verify_SecondChecksumValidation(t *testing.T) {
 	file.Close()
 
 	// Verify should now fail because the second checksum is corrupted
-	err = Verify(tmpPath)
+	_, err = Verify(tmpPath)
 	if err == nil {
 		t.Error("Verify should fail when second checksum is corrupted")
 	} else {
@@ -432,3 +435,434 @@ func Test_Verify_SecondChecksumValidation(t *testing.T) {
 		t.Errorf("Expected CorruptDatabaseError, got %T: %v", err, err)
 	}
 }
+
+// Test_VerifiedPrefixSize_SmallFile tests that only the initial checksum row is vouched for
+// when no further checksum has been written
+func Test_VerifiedPrefixSize_SmallFile(t *testing.T) {
+	tmpPath := t.TempDir() + "/prefix_small.fdb"
+	createDatabaseWithRows(t, tmpPath, 256, 10)
+
+	size, err := VerifiedPrefixSize(tmpPath)
+	if err != nil {
+		t.Fatalf("VerifiedPrefixSize failed: %v", err)
+	}
+	if want := int64(HEADER_SIZE + 256); size != want {
+		t.Errorf("Expected verified prefix %d, got %d", want, size)
+	}
+}
+
+// Test_VerifiedPrefixSize_CorruptInitialChecksum tests that no prefix exists when the
+// initial checksum row is corrupt
+func Test_VerifiedPrefixSize_CorruptInitialChecksum(t *testing.T) {
+	tmpPath := t.TempDir() + "/prefix_corrupt_initial.fdb"
+	createDatabaseWithRows(t, tmpPath, 256, 0)
+
+	file, err := os.OpenFile(tmpPath, os.O_RDWR, 0644)
+	if err != nil {
+		t.Fatalf("Failed to open file for corruption: %v", err)
+	}
+	if _, err := file.WriteAt([]byte{0xFF}, int64(HEADER_SIZE+10)); err != nil {
+		t.Fatalf("Failed to corrupt file: %v", err)
+	}
+	file.Close()
+
+	_, err = VerifiedPrefixSize(tmpPath)
+	var corruptErr *CorruptDatabaseError
+	if !errors.As(err, &corruptErr) {
+		t.Errorf("Expected CorruptDatabaseError, got %T: %v", err, err)
+	}
+}
+
+// Test_VerifiedPrefixSize_CorruptTail tests that truncating to the verified prefix
+// discards a corrupt tail and leaves a database that verifies
+func Test_VerifiedPrefixSize_CorruptTail(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	tmpPath := t.TempDir() + "/prefix_corrupt_tail.fdb"
+	createDatabaseWithRows(t, tmpPath, 256, 12000)
+	secondChecksumEnd := int64(64 + 10001*256 + 256)
+
+	// Garbage after the last row, as left by a crash mid-write
+	file, err := os.OpenFile(tmpPath, os.O_RDWR|os.O_APPEND, 0644)
+	if err != nil {
+		t.Fatalf("Failed to open file for corruption: %v", err)
+	}
+	if _, err := file.Write([]byte("garbage after crash")); err != nil {
+		t.Fatalf("Failed to corrupt file: %v", err)
+	}
+	file.Close()
+
+	if _, err := Verify(tmpPath); err == nil {
+		t.Fatal("Verify should fail with a corrupt tail")
+	}
+
+	size, err := VerifiedPrefixSize(tmpPath)
+	if err != nil {
+		t.Fatalf("VerifiedPrefixSize failed: %v", err)
+	}
+	if size != secondChecksumEnd {
+		t.Fatalf("Expected verified prefix %d, got %d", secondChecksumEnd, size)
+	}
+
+	if err := os.Truncate(tmpPath, size); err != nil {
+		t.Fatalf("Failed to truncate: %v", err)
+	}
+	if _, err := Verify(tmpPath); err != nil {
+		t.Errorf("Verify should succeed after truncating to verified prefix, got: %v", err)
+	}
+}
+
+// Test_VerifiedPrefixSize_CorruptSecondChecksum tests that a corrupt checksum row is not
+// part of the verified prefix
+func Test_VerifiedPrefixSize_CorruptSecondChecksum(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	tmpPath := t.TempDir() + "/prefix_corrupt_cs2.fdb"
+	createDatabaseWithRows(t, tmpPath, 256, 10500)
+
+	file, err := os.OpenFile(tmpPath, os.O_RDWR, 0644)
+	if err != nil {
+		t.Fatalf("Failed to open file for corruption: %v", err)
+	}
+	if _, err := file.WriteAt([]byte{0xFF}, int64(64+10001*256+10)); err != nil {
+		t.Fatalf("Failed to corrupt file: %v", err)
+	}
+	file.Close()
+
+	size, err := VerifiedPrefixSize(tmpPath)
+	if err != nil {
+		t.Fatalf("VerifiedPrefixSize failed: %v", err)
+	}
+	if want := int64(HEADER_SIZE + 256); size != want {
+		t.Errorf("Expected verified prefix %d, got %d", want, size)
+	}
+}
+
+// Test_Verify_ReportCollectsStructuralProblems tests that Verify reports every
+// structural problem with its offset instead of stopping at the first
+func Test_Verify_ReportCollectsStructuralProblems(t *testing.T) {
+	tmpPath := t.TempDir() + "/structure.fdb"
+	createTestDatabaseForVerify(t, tmpPath, 128, 0)
+
+	dataRow := func(ts int, start StartControl, end EndControl) *DataRow {
+		return &DataRow{baseRow[*DataRowPayload]{
+			RowSize:      128,
+			StartControl: start,
+			EndControl:   end,
+			RowPayload:   &DataRowPayload{Key: uuidFromTS(ts), Value: json.RawMessage(`{}`)},
+		}}
+	}
+	nullRow, err := NewNullRow(128, 3)
+	if err != nil {
+		t.Fatalf("NewNullRow failed: %v", err)
+	}
+	rows := []interface{ MarshalText() ([]byte, error) }{
+		dataRow(1, START_TRANSACTION, TRANSACTION_COMMIT), // valid
+		dataRow(2, ROW_CONTINUE, TRANSACTION_COMMIT),      // no open transaction
+		dataRow(3, START_TRANSACTION, ROW_END_CONTROL),    // opens a transaction
+		nullRow, // inside that transaction
+		dataRow(4, START_TRANSACTION, EndControl{'R', '3'}), // no savepoint 3
+	}
+	file, err := os.OpenFile(tmpPath, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatalf("Failed to open file for append: %v", err)
+	}
+	for _, row := range rows {
+		rowBytes, err := row.MarshalText()
+		if err != nil {
+			t.Fatalf("Failed to marshal row: %v", err)
+		}
+		if _, err := file.Write(rowBytes); err != nil {
+			t.Fatalf("Failed to write row: %v", err)
+		}
+	}
+	file.Close()
+
+	report, err := Verify(tmpPath)
+	var corruptErr *CorruptDatabaseError
+	if !errors.As(err, &corruptErr) {
+		t.Fatalf("Expected CorruptDatabaseError, got %v", err)
+	}
+	if report.OK() {
+		t.Fatal("Expected report with problems")
+	}
+
+	rowOffset := func(index int) int64 { return int64(HEADER_SIZE + index*128) }
+	wantOffsets := []int64{rowOffset(2), rowOffset(4), rowOffset(5)}
+	if report.ProblemCount != len(wantOffsets) || len(report.Problems) != len(wantOffsets) {
+		t.Fatalf("Expected %d problems, got %d: %+v", len(wantOffsets), report.ProblemCount, report.Problems)
+	}
+	for i, want := range wantOffsets {
+		if report.Problems[i].Offset != want {
+			t.Errorf("Problem %d: expected offset %d, got %d (%v)", i, want, report.Problems[i].Offset, report.Problems[i].Err)
+		}
+	}
+	if err != report.Problems[0].Err {
+		t.Errorf("Expected the returned error to be the first problem, got %v", err)
+	}
+
+	if report.DataRows != 4 || report.NullRows != 1 || report.ChecksumRows != 1 || report.Transactions != 4 {
+		t.Errorf("Unexpected counts: %+v", report)
+	}
+	if report.PartialRow || report.OpenTransaction {
+		t.Errorf("Expected no open transaction or partial row: %+v", report)
+	}
+}
+
+// Test_VerifyWithOptions_CheckOrdering tests the ordering pass on a row kept by a
+// partial rollback, which the ordering check Verify always runs does not cover
+
+func Test_InterleavedTransactionIsCorrupt(t *testing.T) {
+	tmpPath := t.TempDir() + "/interleaved.fdb"
+	createTestDatabaseForVerify(t, tmpPath, 128, 0)
+
+	// Row 1 opens a transaction that row 2 interrupts by starting another
+	file, err := os.OpenFile(tmpPath, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatalf("Failed to open file for append: %v", err)
+	}
+	for _, row := range []*DataRow{
+		{baseRow[*DataRowPayload]{RowSize: 128, StartControl: START_TRANSACTION, EndControl: ROW_END_CONTROL,
+			RowPayload: &DataRowPayload{Key: uuidFromTS(1), Value: json.RawMessage(`{"a":1}`)}}},
+		{baseRow[*DataRowPayload]{RowSize: 128, StartControl: START_TRANSACTION, EndControl: TRANSACTION_COMMIT,
+			RowPayload: &DataRowPayload{Key: uuidFromTS(2), Value: json.RawMessage(`{"b":2}`)}}},
+	} {
+		rowBytes, err := row.MarshalText()
+		if err != nil {
+			t.Fatalf("Failed to marshal row: %v", err)
+		}
+		if _, err := file.Write(rowBytes); err != nil {
+			t.Fatalf("Failed to write row: %v", err)
+		}
+	}
+	file.Close()
+
+	report, err := Verify(tmpPath)
+	var corruptErr *CorruptDatabaseError
+	if !errors.As(err, &corruptErr) {
+		t.Fatalf("Expected CorruptDatabaseError, got %v", err)
+	}
+	if report.ProblemCount != 1 || report.Problems[0].Offset != int64(HEADER_SIZE+2*128) {
+		t.Fatalf("Expected one problem at row 2, got %+v", report.Problems)
+	}
+	if msg := err.Error(); !strings.Contains(msg, "row 2 at offset") || !strings.Contains(msg, "started at row 1") {
+		t.Errorf("Expected both row indices in %q", msg)
+	}
+
+	for _, strategy := range []FinderStrategy{FinderStrategySimple, FinderStrategyInMemory, FinderStrategyBinarySearch} {
+		t.Run(string(strategy), func(t *testing.T) {
+			db, err := NewFrozenDB(tmpPath, MODE_READ, strategy)
+			if err != nil {
+				t.Fatalf("NewFrozenDB: %v", err)
+			}
+			defer db.Close()
+
+			var value map[string]int
+			if err := db.Get(uuidFromTS(1), &value); !errors.As(err, &corruptErr) {
+				t.Errorf("Get interrupted key: expected CorruptDatabaseError, got %v (value %v)", err, value)
+			}
+			if err := db.Get(uuidFromTS(2), &value); err != nil || value["b"] != 2 {
+				t.Errorf("Get(b) = %v, %v; want b=2", value, err)
+			}
+		})
+	}
+}
+func Test_VerifyWithOptions_CheckOrdering(t *testing.T) {
+	tmpPath := t.TempDir() + "/ordering.fdb"
+	createTestDatabaseForVerify(t, tmpPath, 128, 0)
+
+	dataRow := func(ts int, start StartControl, end EndControl) *DataRow {
+		return &DataRow{baseRow[*DataRowPayload]{
+			RowSize:      128,
+			StartControl: start,
+			EndControl:   end,
+			RowPayload:   &DataRowPayload{Key: uuidFromTS(ts), Value: json.RawMessage(`{}`)},
+		}}
+	}
+	rows := []*DataRow{
+		dataRow(1000, START_TRANSACTION, TRANSACTION_COMMIT),
+		dataRow(9000, START_TRANSACTION, EndControl{'S', 'E'}), // kept by the rollback
+		dataRow(9500, ROW_CONTINUE, EndControl{'R', '1'}),
+		dataRow(5000, START_TRANSACTION, TRANSACTION_COMMIT), // behind the kept row
+		dataRow(6000, START_TRANSACTION, TRANSACTION_COMMIT), // also behind, but only the first pair is reported
+	}
+	file, err := os.OpenFile(tmpPath, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatalf("Failed to open file for append: %v", err)
+	}
+	for _, row := range rows {
+		rowBytes, err := row.MarshalText()
+		if err != nil {
+			t.Fatalf("Failed to marshal row: %v", err)
+		}
+		if _, err := file.Write(rowBytes); err != nil {
+			t.Fatalf("Failed to write row: %v", err)
+		}
+	}
+	file.Close()
+
+	if _, err := Verify(tmpPath); err != nil {
+		t.Fatalf("Verify without the ordering pass: %v", err)
+	}
+
+	report, err := VerifyWithOptions(tmpPath, VerifyOptions{CheckOrdering: true})
+	var corruptErr *CorruptDatabaseError
+	if !errors.As(err, &corruptErr) {
+		t.Fatalf("Expected CorruptDatabaseError, got %v", err)
+	}
+	if report.ProblemCount != 1 || report.Problems[0].Offset != int64(HEADER_SIZE+4*128) {
+		t.Fatalf("Expected one problem at row 4, got %+v", report.Problems)
+	}
+	for _, want := range []string{uuidFromTS(5000).String(), uuidFromTS(9000).String(), "timestamp 5000", "timestamp 9000", "1970-01-01T00:00:09Z"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Expected %q in %v", want, err)
+		}
+	}
+}
+
+// Test_Verify_ReportForValidDatabase tests the report of a database written
+// through a transaction that is still open
+func Test_Verify_ReportForValidDatabase(t *testing.T) {
+	dir := t.TempDir()
+	path := setupCreate(t, dir, confSkewMs)
+	tx, db := openAndBegin(t, path)
+	defer db.Close()
+	for i := 1; i <= 3; i++ {
+		if err := tx.AddRow(uuidFromTS(i*1000), json.RawMessage(`{}`)); err != nil {
+			t.Fatalf("AddRow failed: %v", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Commit failed: %v", err)
+	}
+	tx, err := db.BeginTx()
+	if err != nil {
+		t.Fatalf("BeginTx failed: %v", err)
+	}
+	if err := tx.AddRow(uuidFromTS(4000), json.RawMessage(`{}`)); err != nil {
+		t.Fatalf("AddRow failed: %v", err)
+	}
+
+	report, err := Verify(path)
+	if err != nil {
+		t.Fatalf("Verify failed: %v", err)
+	}
+	if !report.OK() || report.DataRows != 3 || report.ChecksumRows != 1 || report.Transactions != 1 {
+		t.Errorf("Unexpected report: %+v", report)
+	}
+	if !report.PartialRow || !report.OpenTransaction {
+		t.Errorf("Expected an open transaction ending in a partial row: %+v", report)
+	}
+	if report.RowSize != confRowSize || report.ChecksumInterval != CHECKSUM_INTERVAL {
+		t.Errorf("Unexpected header fields: %+v", report)
+	}
+}
+
+// createDatabaseWithChecksumWindows creates a database with the smallest checksum
+// interval and rows committed data rows, so that it spans rows/MIN_CHECKSUM_INTERVAL
+// checksum windows.
+func createDatabaseWithChecksumWindows(tb testing.TB, rows int) string {
+	tb.Helper()
+	path := filepath.Join(tb.TempDir(), "windows.fdb")
+	setupMockSyscalls(false, false)
+	tb.Cleanup(restoreRealSyscalls)
+	tb.Setenv("SUDO_USER", MOCK_USER)
+	tb.Setenv("SUDO_UID", MOCK_UID)
+	tb.Setenv("SUDO_GID", MOCK_GID)
+	config := NewCreateConfig(path, confRowSize, confSkewMs)
+	config.SetChecksumInterval(MIN_CHECKSUM_INTERVAL)
+	if err := Create(config); err != nil {
+		tb.Fatalf("Create: %v", err)
+	}
+
+	db, err := NewFrozenDB(path, MODE_WRITE, FinderStrategySimple)
+	if err != nil {
+		tb.Fatalf("NewFrozenDB: %v", err)
+	}
+	defer db.Close()
+	for start := 0; start < rows; start += 100 {
+		tx, err := db.BeginTx()
+		if err != nil {
+			tb.Fatalf("BeginTx: %v", err)
+		}
+		for i := start; i < min(start+100, rows); i++ {
+			if err := tx.AddRow(uuidFromTS(1000+i), json.RawMessage(fmt.Sprintf(`{"row":%d}`, i))); err != nil {
+				tb.Fatalf("AddRow: %v", err)
+			}
+		}
+		if err := tx.Commit(); err != nil {
+			tb.Fatalf("Commit: %v", err)
+		}
+	}
+	return path
+}
+
+func Test_VerifyParallel_MatchesVerify(t *testing.T) {
+	path := createDatabaseWithChecksumWindows(t, 1050)
+
+	// Corrupt a row value in the second and eighth checksum windows
+	file, err := os.OpenFile(path, os.O_RDWR, 0644)
+	if err != nil {
+		t.Fatalf("OpenFile: %v", err)
+	}
+	for _, index := range []int64{150, 750} {
+		if _, err := file.WriteAt([]byte("9"), HEADER_SIZE+index*confRowSize+30); err != nil {
+			t.Fatalf("WriteAt: %v", err)
+		}
+	}
+	file.Close()
+
+	serial, serialErr := Verify(path)
+	if serialErr == nil || serial == nil {
+		t.Fatalf("Verify: expected problems, got report %v, err %v", serial, serialErr)
+	}
+	for _, workers := range []int{2, 4, 64} {
+		parallel, parallelErr := VerifyParallel(path, workers)
+		if !reflect.DeepEqual(parallel, serial) || !reflect.DeepEqual(parallelErr, serialErr) {
+			t.Errorf("VerifyParallel(%d) = %+v, %v; want %+v, %v", workers, parallel, parallelErr, serial, serialErr)
+		}
+	}
+
+	checksumProblems := 0
+	for _, problem := range serial.Problems {
+		if strings.Contains(problem.Err.Error(), "checksum mismatch") {
+			checksumProblems++
+		}
+	}
+	if checksumProblems != 2 {
+		t.Errorf("expected 2 checksum mismatches, got %d in %+v", checksumProblems, serial.Problems)
+	}
+
+	var invalidInput *InvalidInputError
+	if _, err := VerifyParallel(path, 0); !errors.As(err, &invalidInput) {
+		t.Errorf("VerifyParallel(0): expected InvalidInputError, got %v", err)
+	}
+}
+
+// BenchmarkVerifyChecksumWindows measures Pass 1, the part VerifyParallel spreads
+// over workers, on a file of 200 checksum windows. The speedup over workers=1
+// is bounded by GOMAXPROCS.
+func BenchmarkVerifyChecksumWindows(b *testing.B) {
+	path := createDatabaseWithChecksumWindows(b, 200*MIN_CHECKSUM_INTERVAL)
+	file, fileSize, header, err := openVerifyTarget(path)
+	if err != nil {
+		b.Fatalf("openVerifyTarget: %v", err)
+	}
+	defer file.Close()
+	for _, workers := range []int{1, 2, 4, 8} {
+		b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+			b.SetBytes(fileSize)
+			for b.Loop() {
+				report := &VerifyReport{}
+				validateAllChecksums(file, fileSize, header, report, workers)
+				if !report.OK() {
+					b.Fatalf("unexpected problems: %+v", report.Problems)
+				}
+			}
+		})
+	}
+}
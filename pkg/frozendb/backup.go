@@ -0,0 +1,37 @@
+package frozendb
+
+import (
+	"io"
+
+	internal "github.com/susu-dot-dev/frozenDB/internal/frozendb"
+)
+
+// WriteBackupDelta writes an incremental backup of the database file at path to
+// w: a header line recording the byte range, then the file bytes from offset since
+// to the current end of the file. Since the file is append-only, deltas taken one
+// after another, each from the offset the previous one returned, reproduce the file
+// when applied in order with ApplyBackupDelta. A since of 0 starts after the
+// initial checksum row, with the first row a writer appended; restore such a delta
+// onto a copy of the file as created.
+//
+// Returns:
+//   - int64: Offset the delta ends at; pass it as since to the next backup
+//   - error: InvalidInputError if since is beyond the file or not where a writer
+//     stops; WriteError, ReadError, PathError, or CorruptDatabaseError
+func WriteBackupDelta(path string, since int64, w io.Writer, opts ...OpenOption) (int64, error) {
+	return internal.WriteBackupDelta(path, since, w, opts...)
+}
+
+// ApplyBackupDelta appends a delta written by WriteBackupDelta to the database file
+// at path, which must end exactly where the delta begins. Every row is validated
+// (framing, parity, and checksum rows) before it is appended; the file is held open
+// in MODE_WRITE meanwhile.
+//
+// Returns:
+//   - int64: File size after the delta was applied
+//   - error: InvalidInputError if r is not a delta or the file does not end where
+//     it begins; CorruptDatabaseError if a row fails validation or the delta is
+//     cut short; ReadError, PathError, or WriteError
+func ApplyBackupDelta(path string, r io.Reader, opts ...OpenOption) (int64, error) {
+	return internal.ApplyBackupDelta(path, r, opts...)
+}
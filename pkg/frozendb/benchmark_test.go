@@ -0,0 +1,288 @@
+package frozendb_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/susu-dot-dev/frozenDB/pkg/frozendb"
+)
+
+// The benchmarks in this file measure each finder strategy against databases of
+// benchmarkSizes rows. Run them with:
+//
+//	go test -run '^$' -bench . -benchmem ./pkg/frozendb
+//
+// The 1M-row fixture takes several seconds to build and is skipped with -short.
+var benchmarkSizes = []int{1_000, 100_000, 1_000_000}
+
+var benchmarkStrategies = []frozendb.FinderStrategy{
+	frozendb.FinderStrategySimple,
+	frozendb.FinderStrategyInMemory,
+	frozendb.FinderStrategyBinarySearch,
+}
+
+// benchmarkTemplate is an empty database (header and initial checksum row) taken
+// from the example database, since creating one requires sudo.
+const benchmarkTemplate = "../../examples/getting_started/sample.fdb"
+
+// benchmarkBaseMs is the UUIDv7 timestamp of the first fixture key.
+const benchmarkBaseMs = 1_700_000_000_000
+
+var fixtures struct {
+	mu    sync.Mutex
+	dir   string
+	paths map[int]string
+}
+
+func TestMain(m *testing.M) {
+	code := m.Run()
+	if fixtures.dir != "" {
+		_ = os.RemoveAll(fixtures.dir)
+	}
+	os.Exit(code)
+}
+
+// benchmarkKey returns the fixture key for row i: a UUIDv7 whose timestamp is
+// benchmarkBaseMs+i, so keys are strictly ascending.
+func benchmarkKey(i int) uuid.UUID {
+	var u uuid.UUID
+	ts := uint64(benchmarkBaseMs + i)
+	for j := 0; j < 6; j++ {
+		u[j] = byte(ts >> (40 - 8*j))
+	}
+	u[6] = 0x70 // version 7
+	u[7] = 0x01
+	u[8] = 0x80 // RFC 4122 variant
+	return u
+}
+
+// benchmarkFixture returns the path of a committed database holding rows rows,
+// building it on first use. Fixtures are shared by all benchmarks in a run and
+// must only be opened in MODE_READ.
+func benchmarkFixture(b *testing.B, rows int) string {
+	b.Helper()
+	fixtures.mu.Lock()
+	defer fixtures.mu.Unlock()
+
+	if path, ok := fixtures.paths[rows]; ok {
+		return path
+	}
+	if fixtures.dir == "" {
+		dir, err := os.MkdirTemp("", "frozendb-bench-")
+		if err != nil {
+			b.Fatalf("MkdirTemp: %v", err)
+		}
+		fixtures.dir = dir
+		fixtures.paths = make(map[int]string)
+	}
+
+	path := filepath.Join(fixtures.dir, fmt.Sprintf("rows-%d.fdb", rows))
+	if err := buildBenchmarkFixture(path, rows); err != nil {
+		b.Fatalf("building %d-row fixture: %v", rows, err)
+	}
+	fixtures.paths[rows] = path
+	return path
+}
+
+func buildBenchmarkFixture(path string, rows int) error {
+	template, err := os.ReadFile(benchmarkTemplate)
+	if err != nil {
+		return err
+	}
+	var header struct {
+		RowSize int `json:"row_size"`
+	}
+	if err := json.Unmarshal(bytes.TrimRight(template[:63], "\x00"), &header); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, template[:64+header.RowSize], 0644); err != nil {
+		return err
+	}
+
+	db, err := frozendb.NewFrozenDB(path, frozendb.MODE_WRITE, frozendb.FinderStrategySimple)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	const rowsPerTx = 100
+	for start := 0; start < rows; start += rowsPerTx {
+		tx, err := db.BeginTx()
+		if err != nil {
+			return err
+		}
+		for i := start; i < start+rowsPerTx && i < rows; i++ {
+			if err := tx.AddRow(benchmarkKey(i), json.RawMessage(fmt.Sprintf(`{"i":%d}`, i))); err != nil {
+				return err
+			}
+		}
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// forEachSize runs fn as a sub-benchmark for every fixture size.
+func forEachSize(b *testing.B, fn func(b *testing.B, rows int, path string)) {
+	for _, rows := range benchmarkSizes {
+		b.Run(fmt.Sprintf("rows=%d", rows), func(b *testing.B) {
+			if rows >= 1_000_000 && testing.Short() {
+				b.Skip("skipping 1M-row fixture in short mode")
+			}
+			fn(b, rows, benchmarkFixture(b, rows))
+		})
+	}
+}
+
+func openBenchmarkDB(b *testing.B, path string, strategy frozendb.FinderStrategy) *frozendb.FrozenDB {
+	b.Helper()
+	db, err := frozendb.NewFrozenDB(path, frozendb.MODE_READ, strategy)
+	if err != nil {
+		b.Fatalf("NewFrozenDB: %v", err)
+	}
+	b.Cleanup(func() { _ = db.Close() })
+	return db
+}
+
+// BenchmarkGet measures point lookup latency for keys spread across the database.
+func BenchmarkGet(b *testing.B) {
+	for _, strategy := range benchmarkStrategies {
+		b.Run(string(strategy), func(b *testing.B) {
+			forEachSize(b, func(b *testing.B, rows int, path string) {
+				db := openBenchmarkDB(b, path, strategy)
+
+				b.ReportAllocs()
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					// Stride through the keys so consecutive lookups hit distant rows
+					var value json.RawMessage
+					if err := db.Get(benchmarkKey((i*7919)%rows), &value); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		})
+	}
+}
+
+// BenchmarkScan measures full-database Scan throughput, reported as rows/s.
+func BenchmarkScan(b *testing.B) {
+	for _, strategy := range benchmarkStrategies {
+		b.Run(string(strategy), func(b *testing.B) {
+			forEachSize(b, func(b *testing.B, rows int, path string) {
+				db := openBenchmarkDB(b, path, strategy)
+
+				b.ReportAllocs()
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					count := 0
+					err := db.Scan(func(uuid.UUID, json.RawMessage) bool {
+						count++
+						return true
+					})
+					if err != nil {
+						b.Fatal(err)
+					}
+					if count != rows {
+						b.Fatalf("Scan visited %d rows, want %d", count, rows)
+					}
+				}
+				b.ReportMetric(float64(rows)*float64(b.N)/b.Elapsed().Seconds(), "rows/s")
+			})
+		})
+	}
+}
+
+// BenchmarkOpen_InMemory measures the time to open a database with the in-memory
+// finder, which indexes every row, and reports the heap retained by the open
+// database as B/row.
+func BenchmarkOpen_InMemory(b *testing.B) {
+	forEachSize(b, func(b *testing.B, rows int, path string) {
+		var before, after runtime.MemStats
+		runtime.GC()
+		runtime.ReadMemStats(&before)
+		db, err := frozendb.NewFrozenDB(path, frozendb.MODE_READ, frozendb.FinderStrategyInMemory)
+		if err != nil {
+			b.Fatalf("NewFrozenDB: %v", err)
+		}
+		runtime.GC()
+		runtime.ReadMemStats(&after)
+		retained := int64(after.HeapAlloc) - int64(before.HeapAlloc)
+		_ = db.Close()
+
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			db, err := frozendb.NewFrozenDB(path, frozendb.MODE_READ, frozendb.FinderStrategyInMemory)
+			if err != nil {
+				b.Fatal(err)
+			}
+			_ = db.Close()
+		}
+		b.StopTimer()
+		b.ReportMetric(float64(retained)/float64(rows), "B/row")
+	})
+}
+
+// countingReaderAt counts the ReadAt calls made on an os.File.
+type countingReaderAt struct {
+	file  *os.File
+	reads atomic.Int64
+}
+
+func (c *countingReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	c.reads.Add(1)
+	return c.file.ReadAt(p, off)
+}
+
+// BenchmarkGet_ReadAhead measures binary search point lookups on the 1M-row
+// fixture through an io.ReaderAt for several WithFinderReadAhead windows, and
+// reports the ReadAt calls each lookup makes as reads/op.
+func BenchmarkGet_ReadAhead(b *testing.B) {
+	if testing.Short() {
+		b.Skip("skipping 1M-row fixture in short mode")
+	}
+	const rows = 1_000_000
+	path := benchmarkFixture(b, rows)
+	file, err := os.Open(path)
+	if err != nil {
+		b.Fatalf("Open: %v", err)
+	}
+	b.Cleanup(func() { _ = file.Close() })
+	info, err := file.Stat()
+	if err != nil {
+		b.Fatalf("Stat: %v", err)
+	}
+
+	for _, window := range []int{0, 16, 64, 256} {
+		b.Run(fmt.Sprintf("readahead=%d", window), func(b *testing.B) {
+			r := &countingReaderAt{file: file}
+			db, err := frozendb.NewFrozenDBFromReaderAt(r, info.Size(), frozendb.FinderStrategyBinarySearch,
+				frozendb.WithFinderReadAhead(window))
+			if err != nil {
+				b.Fatalf("NewFrozenDBFromReaderAt: %v", err)
+			}
+			b.Cleanup(func() { _ = db.Close() })
+
+			r.reads.Store(0)
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				var value json.RawMessage
+				if err := db.Get(benchmarkKey((i*7919)%rows), &value); err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.ReportMetric(float64(r.reads.Load())/float64(b.N), "reads/op")
+		})
+	}
+}
@@ -51,6 +51,46 @@ type TransactionActiveError = internal.TransactionActiveError
 // Used for: JSON syntax errors, type mismatches, malformed data in stored values.
 type InvalidDataError = internal.InvalidDataError
 
+// DuplicateKeyError is returned when a key is added twice within one transaction.
+// Used for: AddRow() with a key already added earlier in the same transaction.
+type DuplicateKeyError = internal.DuplicateKeyError
+
+// UnsupportedVersionError is returned when a file's header declares a format version
+// this build cannot read, typically a file written by a newer frozendb. It wraps a
+// CorruptDatabaseError. Upgrading frozendb is the fix.
+type UnsupportedVersionError = internal.UnsupportedVersionError
+
+// TruncatedFileError is returned when the file ends partway through a row where no
+// writer stops, meaning the file was cut off rather than its bytes being wrong.
+// RowIndex, ExpectedSize, and ActualSize locate the cut. It wraps a
+// CorruptDatabaseError.
+type TruncatedFileError = internal.TruncatedFileError
+
+// ValueTooLargeError is returned when a value does not fit in a row. ValueSize
+// and MaxSize give the value's size and MaxValueSize for the row_size. It wraps
+// an InvalidInputError.
+type ValueTooLargeError = internal.ValueTooLargeError
+
+// KeyOrderViolationError is returned by ValidateKeyOrder for the first key that
+// breaks the ordering rule. Index, Timestamp, and MaxTimestamp locate it. It wraps
+// a KeyOrderingError.
+type KeyOrderViolationError = internal.KeyOrderViolationError
+
+// RowSizeMismatchError is returned when a file's rows are framed at a different
+// size than its header's row_size. HeaderRowSize and ObservedRowSize give both
+// sizes. It wraps a CorruptDatabaseError.
+type RowSizeMismatchError = internal.RowSizeMismatchError
+
+// FutureTimestampError is returned by AddRow and Delete on a database opened with
+// WithFutureGuard(true) for a key later than the system clock plus skew_ms.
+// Timestamp and Now give both, in milliseconds. It wraps an InvalidInputError.
+type FutureTimestampError = internal.FutureTimestampError
+
+// ReadPastEndError is returned when a read asks for bytes past the end of the
+// file. ReadEnd and FileSize give where the read would end and the file's size.
+// It wraps an InvalidInputError.
+type ReadPastEndError = internal.ReadPastEndError
+
 // Error constructor functions
 
 // NewInvalidInputError creates a new InvalidInputError.
@@ -107,3 +147,43 @@ func NewTransactionActiveError(message string, err error) *TransactionActiveErro
 func NewInvalidDataError(message string, err error) *InvalidDataError {
 	return internal.NewInvalidDataError(message, err)
 }
+
+// NewDuplicateKeyError creates a new DuplicateKeyError.
+func NewDuplicateKeyError(message string, err error) *DuplicateKeyError {
+	return internal.NewDuplicateKeyError(message, err)
+}
+
+// NewUnsupportedVersionError creates a new UnsupportedVersionError.
+func NewUnsupportedVersionError(message string, err error) *UnsupportedVersionError {
+	return internal.NewUnsupportedVersionError(message, err)
+}
+
+// NewTruncatedFileError creates a new TruncatedFileError.
+func NewTruncatedFileError(message string, rowIndex, expectedSize, actualSize int64, err error) *TruncatedFileError {
+	return internal.NewTruncatedFileError(message, rowIndex, expectedSize, actualSize, err)
+}
+
+// NewValueTooLargeError creates a new ValueTooLargeError.
+func NewValueTooLargeError(message string, valueSize, maxSize int, err error) *ValueTooLargeError {
+	return internal.NewValueTooLargeError(message, valueSize, maxSize, err)
+}
+
+// NewKeyOrderViolationError creates a new KeyOrderViolationError.
+func NewKeyOrderViolationError(message string, index int, timestamp, maxTimestamp int64, err error) *KeyOrderViolationError {
+	return internal.NewKeyOrderViolationError(message, index, timestamp, maxTimestamp, err)
+}
+
+// NewRowSizeMismatchError creates a new RowSizeMismatchError.
+func NewRowSizeMismatchError(message string, headerRowSize, observedRowSize int, err error) *RowSizeMismatchError {
+	return internal.NewRowSizeMismatchError(message, headerRowSize, observedRowSize, err)
+}
+
+// NewFutureTimestampError creates a new FutureTimestampError.
+func NewFutureTimestampError(message string, timestamp, now int64, err error) *FutureTimestampError {
+	return internal.NewFutureTimestampError(message, timestamp, now, err)
+}
+
+// NewReadPastEndError creates a new ReadPastEndError.
+func NewReadPastEndError(message string, readEnd, fileSize int64, err error) *ReadPastEndError {
+	return internal.NewReadPastEndError(message, readEnd, fileSize, err)
+}
@@ -14,6 +14,7 @@ import (
 //     read-heavy workloads need low latency.
 //   - FinderStrategyBinarySearch: Optimized for time-ordered UUID lookups with binary search.
 //     GetIndex O(log n) with time-based optimizations for chronologically ordered keys.
+//   - FinderStrategyAuto: Chooses one of the above from the file size at open time.
 type FinderStrategy = internal.FinderStrategy
 
 const (
@@ -24,11 +25,46 @@ const (
 
 	// FinderStrategyInMemory uses ~40 bytes per row to maintain in-memory indices.
 	// All operations (GetIndex, GetTransactionStart, GetTransactionEnd) are O(1).
-	// Best for databases that fit in memory with read-heavy workloads.
+	// Best for databases that fit in memory with read-heavy workloads. Files whose
+	// index would exceed the memory budget are opened with FinderStrategyBinarySearch.
 	FinderStrategyInMemory = internal.FinderStrategyInMemory
 
 	// FinderStrategyBinarySearch uses binary search for time-ordered UUID lookups.
 	// GetIndex is O(log n) with time-based optimizations.
 	// Best for chronologically ordered keys (UUIDv7) with frequent lookups.
 	FinderStrategyBinarySearch = internal.FinderStrategyBinarySearch
+
+	// FinderStrategyAuto selects a strategy from the file size when the database is opened:
+	// simple for files of at most AUTO_SIMPLE_MAX_ROWS rows, inmemory when the estimated
+	// index (INMEMORY_BYTES_PER_ROW per row) fits the memory budget, and binary_search
+	// otherwise. FrozenDB.ActiveFinder reports the selected strategy.
+	FinderStrategyAuto = internal.FinderStrategyAuto
+)
+
+const (
+	// AUTO_SIMPLE_MAX_ROWS is the largest row count for which FinderStrategyAuto picks the simple finder.
+	AUTO_SIMPLE_MAX_ROWS = internal.AUTO_SIMPLE_MAX_ROWS
+
+	// INMEMORY_BYTES_PER_ROW is the per-row memory estimate FinderStrategyAuto uses for the in-memory finder.
+	INMEMORY_BYTES_PER_ROW = internal.INMEMORY_BYTES_PER_ROW
+
+	// DEFAULT_AUTO_FINDER_MEMORY_BUDGET is the default in-memory finder budget (64 MiB).
+	DEFAULT_AUTO_FINDER_MEMORY_BUDGET = internal.DEFAULT_AUTO_FINDER_MEMORY_BUDGET
+
+	// MAX_FINDER_READ_AHEAD_BYTES caps the window WithFinderReadAhead reads at once (1 MiB).
+	MAX_FINDER_READ_AHEAD_BYTES = internal.MAX_FINDER_READ_AHEAD_BYTES
 )
+
+// FinderStats describes a database's layout and the rows the active finder reads
+// per lookup, as reported by FrozenDB.FinderDiagnostics.
+type FinderStats = internal.FinderStats
+
+// SetAutoFinderMemoryBudget sets the memory budget, in bytes, within which
+// FinderStrategyAuto may choose the in-memory finder and FinderStrategyInMemory is
+// honored (see WithFinderMemoryBudget). A budget of 0 disables the in-memory
+// finder. It applies to databases opened after the call.
+//
+// Returns InvalidInputError if bytes is negative.
+func SetAutoFinderMemoryBudget(bytes int64) error {
+	return internal.SetAutoFinderMemoryBudget(bytes)
+}
@@ -8,6 +8,11 @@
 package frozendb
 
 import (
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
 	internal "github.com/susu-dot-dev/frozenDB/internal/frozendb"
 )
 
@@ -22,15 +27,215 @@ type FrozenDB = internal.FrozenDB
 // Parameters:
 //   - path: Filesystem path to existing frozenDB database file (.fdb extension required)
 //   - mode: Access mode - MODE_READ for read-only, MODE_WRITE for read-write
-//   - strategy: FinderStrategySimple, FinderStrategyInMemory, or FinderStrategyBinarySearch
+//   - strategy: FinderStrategySimple, FinderStrategyInMemory, FinderStrategyBinarySearch,
+//     or FinderStrategyAuto
+//   - opts: Optional OpenOption values, such as WithoutLock
 //
 // Returns:
 //   - *FrozenDB: Database instance ready for operations
 //   - error: InvalidInputError (invalid strategy), PathError, CorruptDatabaseError, or WriteError
 //
 // Thread Safety: Safe for concurrent calls on different files
-func NewFrozenDB(path string, mode string, strategy FinderStrategy) (*FrozenDB, error) {
-	return internal.NewFrozenDB(path, mode, internal.FinderStrategy(strategy))
+func NewFrozenDB(path string, mode string, strategy FinderStrategy, opts ...OpenOption) (*FrozenDB, error) {
+	return internal.NewFrozenDB(path, mode, internal.FinderStrategy(strategy), opts...)
+}
+
+// DBFile is the storage interface a FrozenDB reads through. Implement it to serve
+// a database from storage other than a local file and pass it to
+// NewFrozenDBWithFile. A read-only implementation returns an error from SetWriter,
+// returns immediately from WriterClosed, and reports MODE_READ from GetMode.
+type DBFile = internal.DBFile
+
+// Data is a write request sent to DBFile.SetWriter's channel. Read-only DBFile
+// implementations never receive one.
+type Data = internal.Data
+
+// NewFrozenDBWithFile opens a database through a caller-supplied DBFile, such as an
+// adapter for an object store, a memory map, or an in-memory copy for tests. The
+// header is read and validated through file and all later reads use it.
+//
+// Only MODE_READ files are accepted: write-mode handles need the exclusive lock
+// and append semantics of a real file and must be opened with NewFrozenDB.
+//
+// Returns:
+//   - *FrozenDB: Database instance reading through file; Close closes file
+//   - error: InvalidInputError, InvalidActionError (file not in MODE_READ),
+//     CorruptDatabaseError, or ReadError. The file is not closed on error.
+func NewFrozenDBWithFile(file DBFile, strategy FinderStrategy, opts ...OpenOption) (*FrozenDB, error) {
+	return internal.NewFrozenDBWithFile(file, internal.FinderStrategy(strategy), opts...)
+}
+
+// NewFrozenDBFromReaderAt opens a read-only database from the first size bytes of
+// r, such as an HTTPReaderAt for a database on a CDN. FinderStrategyBinarySearch
+// reads the least; the simple and in-memory finders read the whole file. Close
+// closes r if it implements io.Closer.
+//
+// Returns:
+//   - *FrozenDB: Read-only database instance
+//   - error: InvalidInputError, CorruptDatabaseError, or ReadError
+func NewFrozenDBFromReaderAt(r io.ReaderAt, size int64, strategy FinderStrategy, opts ...OpenOption) (*FrozenDB, error) {
+	return internal.NewFrozenDBFromReaderAt(r, size, internal.FinderStrategy(strategy), opts...)
+}
+
+// HTTPReaderAt is an io.ReaderAt over a file served by HTTP range requests, for
+// NewFrozenDBFromReaderAt. Reads are extended to a read-ahead size so nearby rows
+// share a request, and transient failures (network errors, 429, 5xx) are retried
+// with exponential backoff. Configure it with SetReadAhead and SetRetries.
+type HTTPReaderAt = internal.HTTPReaderAt
+
+const (
+	// DEFAULT_HTTP_READ_AHEAD is the default number of bytes per range request (64 KiB).
+	DEFAULT_HTTP_READ_AHEAD = internal.DEFAULT_HTTP_READ_AHEAD
+
+	// DEFAULT_HTTP_MAX_RETRIES is the default number of retries after a transient failure.
+	DEFAULT_HTTP_MAX_RETRIES = internal.DEFAULT_HTTP_MAX_RETRIES
+
+	// DEFAULT_HTTP_RETRY_BACKOFF is the default delay before the first retry.
+	DEFAULT_HTTP_RETRY_BACKOFF = internal.DEFAULT_HTTP_RETRY_BACKOFF
+)
+
+// NewHTTPReaderAt returns an HTTPReaderAt for url, learning the file size with a
+// one-byte range request. A nil client uses http.DefaultClient. Returns ReadError
+// if the server fails or does not support range requests.
+func NewHTTPReaderAt(url string, client *http.Client) (*HTTPReaderAt, error) {
+	return internal.NewHTTPReaderAt(url, client)
+}
+
+// ValueCompression is how a database stores row values, as reported by
+// Header.GetValueCompression.
+type ValueCompression = internal.ValueCompression
+
+const (
+	ValueCompressionNone = internal.ValueCompressionNone // Values are stored as written
+	ValueCompressionGzip = internal.ValueCompressionGzip // Values are gzip-compressed
+)
+
+// ReadHeader reads and validates the header of the database file at path without
+// opening the database: only the first 64 bytes are read, no lock is taken, and
+// no finder is built. Use it to discover a file's row_size or skew_ms cheaply.
+//
+// The header type itself is internal and not re-exported; use its getters
+// (GetVersion, GetRowSize, GetSkewMs, GetChecksumInterval, GetValueCompression).
+//
+// Returns:
+//   - *internal.Header: The validated header
+//   - error: PathError, ReadError, CorruptDatabaseError, or UnsupportedVersionError
+func ReadHeader(path string) (*internal.Header, error) {
+	return internal.ReadHeader(path)
+}
+
+// ParseHeader parses and validates a 64-byte header, such as the first bytes of a
+// database file fetched by other means.
+//
+// Returns:
+//   - *internal.Header: The validated header
+//   - error: CorruptDatabaseError or UnsupportedVersionError
+func ParseHeader(data []byte) (*internal.Header, error) {
+	return internal.ParseHeader(data)
+}
+
+// EstimatedSize returns the projected size in bytes of a database file holding
+// dataRows data and null rows of rowSize bytes, including the header and every
+// checksum row. A checksumInterval of 0 selects the default of 10,000 rows.
+func EstimatedSize(rowSize int, dataRows int64, checksumInterval int) int64 {
+	return internal.EstimatedSize(rowSize, dataRows, checksumInterval)
+}
+
+// MaxValueSize returns the largest value, in bytes, that fits in a data row of
+// rowSize bytes. AddRow returns a ValueTooLargeError for larger values. With value
+// compression the limit applies to the compressed value.
+func MaxValueSize(rowSize int) int {
+	return internal.MaxValueSize(rowSize)
+}
+
+// ValidateKeyOrder checks that keys can be added in order after maxTimestamp (0
+// for an empty database) with the given skewMs, applying AddRow's rule
+// new_timestamp + skew_ms > max_timestamp across the whole slice. It returns a
+// KeyOrderViolationError for the first key AddRow would reject, or
+// InvalidInputError for a key that is not UUIDv7.
+func ValidateKeyOrder(keys []uuid.UUID, maxTimestamp int64, skewMs int) error {
+	return internal.ValidateKeyOrder(keys, maxTimestamp, skewMs)
+}
+
+// OrderOK reports whether AddRow accepts key next after prev, the key with the
+// largest timestamp written so far (uuid.Nil for an empty database), under the
+// ordering rule next_timestamp + skewMs > prev_timestamp. A next that is not a
+// UUIDv7 key is never OK.
+func OrderOK(prev, next uuid.UUID, skewMs int) bool {
+	return internal.OrderOK(prev, next, skewMs)
+}
+
+// OpenOption configures optional behavior of NewFrozenDB.
+type OpenOption = internal.OpenOption
+
+// WithoutLock opens a MODE_WRITE database without the exclusive advisory lock
+// (flock), for filesystems that do not support it. The caller must guarantee that
+// only one writer, in any process, has the file open; concurrent unlocked writers
+// corrupt the database. The header is still validated. No effect in MODE_READ.
+func WithoutLock() OpenOption {
+	return internal.WithoutLock()
+}
+
+// WithLockTimeout makes a MODE_WRITE open wait up to d, retrying with exponential
+// backoff, for another process to release the write lock instead of failing at
+// once. WriteError is returned if the lock is still held after d. The default
+// (d <= 0) fails immediately. No effect with WithoutLock or in MODE_READ.
+func WithLockTimeout(d time.Duration) OpenOption {
+	return internal.WithLockTimeout(d)
+}
+
+// WithFinderMemoryBudget sets the memory, in bytes, the in-memory finder may use
+// for this database, overriding SetAutoFinderMemoryBudget. The index is estimated
+// at INMEMORY_BYTES_PER_ROW per row of the file at open time. A file opened with
+// FinderStrategyInMemory that exceeds the budget falls back to
+// FinderStrategyBinarySearch, logging a warning. An index that outgrows the
+// budget while open logs a warning once; reopen to switch finders. A budget of 0
+// disables the in-memory finder.
+func WithFinderMemoryBudget(bytes int64) OpenOption {
+	return internal.WithFinderMemoryBudget(bytes)
+}
+
+// WithFinderReadAhead makes the simple and binary search finders read rows rows
+// around each row they need in one read and serve nearby reads from that window,
+// cutting the reads of a point lookup on a large file, notably over an
+// HTTPReaderAt. Only the latest window is kept, capped at
+// MAX_FINDER_READ_AHEAD_BYTES. Values of 1 or less read one row at a time.
+func WithFinderReadAhead(rows int) OpenOption {
+	return internal.WithFinderReadAhead(rows)
+}
+
+// WithFutureGuard makes AddRow and Delete reject keys later than the system clock
+// plus skew_ms with FutureTimestampError, so one far-future key cannot raise the
+// max timestamp past every key the real clock will generate. Off by default.
+func WithFutureGuard(enabled bool) OpenOption {
+	return internal.WithFutureGuard(enabled)
+}
+
+// WithWriteBuffer sets how many writes may queue for the writer goroutine of a
+// MODE_WRITE database. Each write is still durable before AddRow or Commit
+// returns. The default is DEFAULT_WRITE_BUFFER; values below 1 are raised to 1.
+func WithWriteBuffer(n int) OpenOption {
+	return internal.WithWriteBuffer(n)
+}
+
+// DEFAULT_WRITE_BUFFER is the default capacity of the write queue (see WithWriteBuffer).
+const DEFAULT_WRITE_BUFFER = internal.DEFAULT_WRITE_BUFFER
+
+// Logger receives diagnostic events such as lock acquisition and release, finder
+// strategy selection, checksum row insertion, and transaction tombstoning.
+// Debugf is used for routine events and Warnf for failures. Implementations must
+// be safe for concurrent use.
+type Logger = internal.Logger
+
+// WithLogger sends diagnostic events to logger. Events are discarded by default.
+func WithLogger(logger Logger) OpenOption {
+	return internal.WithLogger(logger)
+}
+
+// TimestampOf returns the time encoded in a UUIDv7 key's millisecond timestamp, in UTC.
+// The key is not validated as UUIDv7.
+func TimestampOf(key uuid.UUID) time.Time {
+	return internal.TimestampOf(key)
 }
 
 // Access mode constants for opening frozenDB database files
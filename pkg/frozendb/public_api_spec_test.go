@@ -42,12 +42,17 @@ func Test_S_028_FR_005_MinimalPublicAPI(t *testing.T) {
 		var _ = frozendb.FinderStrategySimple
 		var _ = frozendb.FinderStrategyInMemory
 		var _ = frozendb.FinderStrategyBinarySearch
+		var _ = frozendb.FinderStrategyAuto
 	})
 
 	t.Run("NewFrozenDB_function_exists", func(t *testing.T) {
 		// Verify NewFrozenDB function is exported (will fail at runtime if called with invalid path)
 		// We're just checking the function exists and has the right signature
 		_ = frozendb.NewFrozenDB
+		var _ frozendb.OpenOption = frozendb.WithoutLock()
+		var _ frozendb.OpenOption = frozendb.WithLogger(nil)
+		var _ *frozendb.RowInfo
+		var _ = frozendb.RowKindPartial
 	})
 
 	t.Run("error_types_exist", func(t *testing.T) {
@@ -64,6 +69,7 @@ func Test_S_028_FR_005_MinimalPublicAPI(t *testing.T) {
 		var _ *frozendb.KeyNotFoundError
 		var _ *frozendb.TransactionActiveError
 		var _ *frozendb.InvalidDataError
+		var _ *frozendb.DuplicateKeyError
 	})
 
 	t.Run("error_constructors_exist", func(t *testing.T) {
@@ -79,6 +85,7 @@ func Test_S_028_FR_005_MinimalPublicAPI(t *testing.T) {
 		_ = frozendb.NewKeyNotFoundError
 		_ = frozendb.NewTransactionActiveError
 		_ = frozendb.NewInvalidDataError
+		_ = frozendb.NewDuplicateKeyError
 	})
 }
 
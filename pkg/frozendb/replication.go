@@ -0,0 +1,19 @@
+package frozendb
+
+import (
+	"io"
+
+	internal "github.com/susu-dot-dev/frozenDB/internal/frozendb"
+)
+
+// ApplyReplicationStream appends the bytes read from r, as forwarded by a
+// primary's FrozenDB.SetReplicationSink, to the standby database file at path
+// until r returns io.EOF. Every row is validated (framing, parity, and checksum
+// rows) before it is appended; the file is held open in MODE_WRITE meanwhile.
+//
+// Returns:
+//   - error: nil when r is exhausted; CorruptDatabaseError if a row fails
+//     validation or the stream ends within a row; ReadError, PathError, or WriteError
+func ApplyReplicationStream(path string, r io.Reader, opts ...OpenOption) error {
+	return internal.ApplyReplicationStream(path, r, opts...)
+}
@@ -0,0 +1,90 @@
+package frozendb
+
+import (
+	internal "github.com/susu-dot-dev/frozenDB/internal/frozendb"
+)
+
+// RowInfo is a validated physical row returned by FrozenDB.RowAt, with its
+// control bytes decoded into transaction fields.
+type RowInfo = internal.RowInfo
+
+// RowKind identifies the type of a RowInfo.
+type RowKind = internal.RowKind
+
+const (
+	// RowKindData is a complete data row holding a key and JSON value.
+	RowKindData = internal.RowKindData
+
+	// RowKindNull is a NullRow, written for a transaction with no data rows.
+	RowKindNull = internal.RowKindNull
+
+	// RowKindChecksum is a checksum row covering the rows before it.
+	RowKindChecksum = internal.RowKindChecksum
+
+	// RowKindPartial is an incomplete data row at the end of the file, written by
+	// a transaction that is still in progress or was interrupted.
+	RowKindPartial = internal.RowKindPartial
+)
+
+// StartControl is the start_control byte of a row: 'T' (transaction start),
+// 'R' (row continue), or 'C' (checksum row).
+type StartControl = internal.StartControl
+
+// EndControl is the two-byte end_control of a row, such as "TC" (commit) or
+// "R0" (full rollback). Its String method returns the two characters.
+type EndControl = internal.EndControl
+
+// RowMeta describes where a value returned by FrozenDB.GetWithMeta is stored: its
+// row index and byte offset, key timestamp, control bytes, and the boundaries and
+// outcome of its transaction.
+type RowMeta = internal.RowMeta
+
+// TxOutcome describes how the transaction holding a row ended.
+type TxOutcome = internal.TxOutcome
+
+const (
+	// TxOutcomeCommitted is a transaction ended by a commit (TC or SC).
+	TxOutcomeCommitted = internal.TxOutcomeCommitted
+
+	// TxOutcomeSavepointCommitted is a transaction rolled back to a savepoint
+	// (R1-R9 or S1-S9); rows up to that savepoint stay visible.
+	TxOutcomeSavepointCommitted = internal.TxOutcomeSavepointCommitted
+
+	// TxOutcomeRolledBack is a fully rolled back transaction (R0 or S0). None of
+	// its rows are visible, so GetWithMeta never reports it.
+	TxOutcomeRolledBack = internal.TxOutcomeRolledBack
+
+	// TxOutcomeOpen is a transaction that has not ended: the last transaction in
+	// the file, still in progress or interrupted. Only Transactions reports it.
+	TxOutcomeOpen = internal.TxOutcomeOpen
+)
+
+// LookupResult classifies why FrozenDB.Lookup does or does not see a key.
+type LookupResult = internal.LookupResult
+
+const (
+	// LookupFound is a committed, visible key that Get returns.
+	LookupFound = internal.LookupFound
+
+	// LookupNotFound is a key that was never written.
+	LookupNotFound = internal.LookupNotFound
+
+	// LookupRolledBack is a key written by a fully rolled back transaction.
+	LookupRolledBack = internal.LookupRolledBack
+
+	// LookupAfterSavepoint is a key written after the savepoint its transaction
+	// rolled back to.
+	LookupAfterSavepoint = internal.LookupAfterSavepoint
+
+	// LookupUncommitted is a key written by the transaction still in progress at
+	// the end of the file.
+	LookupUncommitted = internal.LookupUncommitted
+
+	// LookupDeleted is a key whose last committed occurrence is a tombstone
+	// written by Transaction.Delete.
+	LookupDeleted = internal.LookupDeleted
+)
+
+// TransactionInfo describes one transaction listed by FrozenDB.Transactions: its
+// first and ending row indices, how it ended, and its row and savepoint counts.
+type TransactionInfo = internal.TransactionInfo
@@ -0,0 +1,14 @@
+package frozendb
+
+import (
+	internal "github.com/susu-dot-dev/frozenDB/internal/frozendb"
+)
+
+// Snapshot is a read-consistent view of a database pinned at the file size
+// observed when FrozenDB.Snapshot was called. Get and Scan through a Snapshot
+// ignore every row appended afterwards.
+type Snapshot = internal.Snapshot
+
+// ScanOptions configures FrozenDB.ScanWithOptions. Set SkipCorrupt to skip rows
+// that fail validation, reported through OnCorruptRow, instead of aborting the scan.
+type ScanOptions = internal.ScanOptions
@@ -10,3 +10,29 @@ import (
 // This type is re-exported from the internal implementation, but excludes
 // internal methods that expose internal types (GetEmptyRow, GetRows).
 type Transaction = internal.Transaction
+
+// SavepointInfo describes a savepoint returned by Transaction.Savepoints: its
+// number, the index of its row in the transaction, and that row's key.
+type SavepointInfo = internal.SavepointInfo
+
+// Entry is a key-value pair for FrozenDB.PrepareBatch.
+type Entry = internal.Entry
+
+// PreparedBatch is a batch validated in memory by FrozenDB.PrepareBatch and written
+// as one transaction by its Commit method.
+type PreparedBatch = internal.PreparedBatch
+
+// MAX_BATCH_ENTRIES is the most entries a PreparedBatch can hold.
+const MAX_BATCH_ENTRIES = internal.MAX_BATCH_ENTRIES
+
+// RowValidator checks a row before AddRow writes it; set one with
+// FrozenDB.SetRowValidator. A returned error rejects the row with InvalidDataError.
+type RowValidator = internal.RowValidator
+
+// MAX_TRANSACTION_ROWS is the most data rows a transaction can hold, a file format
+// limit. Transaction.SetMaxRows can lower it for a transaction.
+const MAX_TRANSACTION_ROWS = internal.MAX_TRANSACTION_ROWS
+
+// TOMBSTONE_VALUE is the reserved value Transaction.Delete writes to mark a key
+// deleted. AddRow rejects it.
+const TOMBSTONE_VALUE = internal.TOMBSTONE_VALUE
@@ -0,0 +1,54 @@
+package frozendb
+
+import (
+	internal "github.com/susu-dot-dev/frozenDB/internal/frozendb"
+)
+
+// VerifyReport summarizes the structure of a file checked by Verify: row and
+// transaction counts, and the problems found.
+type VerifyReport = internal.VerifyReport
+
+// VerifyProblem is a single problem in a VerifyReport, with the file offset of
+// the row it concerns.
+type VerifyProblem = internal.VerifyProblem
+
+// VERIFY_MAX_PROBLEMS is the number of problems a VerifyReport lists; further
+// problems are only counted.
+const VERIFY_MAX_PROBLEMS = internal.VERIFY_MAX_PROBLEMS
+
+// Verify checks that the database file at path is well formed end to end: the
+// header, every checksum, the framing and parity of every row, the placement of
+// checksum rows, transaction structure, and key timestamp ordering. It does not
+// stop at the first problem.
+//
+// Returns:
+//   - *VerifyReport: Counts and problems found; nil if the file or its header
+//     cannot be read
+//   - error: nil if the file is valid, otherwise the first problem found
+func Verify(path string) (*VerifyReport, error) {
+	return internal.Verify(path)
+}
+
+// VerifyParallel is Verify with checksum windows validated by up to workers
+// goroutines. The report is the same as Verify's.
+//
+// Returns:
+//   - *VerifyReport, error: As for Verify; InvalidInputError if workers < 1
+func VerifyParallel(path string, workers int) (*VerifyReport, error) {
+	return internal.VerifyParallel(path, workers)
+}
+
+// VerifyOptions selects optional behavior of VerifyWithOptions: the number of
+// checksum workers, and CheckOrdering, a pass that checks the committed keys
+// against each other under the skew rule and reports the first offending pair.
+// Run it before trusting FinderStrategyBinarySearch on a file from an untrusted
+// source.
+type VerifyOptions = internal.VerifyOptions
+
+// VerifyWithOptions is Verify with the optional behavior selected by opts.
+//
+// Returns:
+//   - *VerifyReport, error: As for Verify; InvalidInputError if opts.Workers < 0
+func VerifyWithOptions(path string, opts VerifyOptions) (*VerifyReport, error) {
+	return internal.VerifyWithOptions(path, opts)
+}